@@ -9,7 +9,14 @@ Default mode produces three JSON dataset files:
 Additional modes:
   --xml                Generate XML equivalents (wide.xml, deep.xml, mixed.xml)
   --validation-targets Generate targeted validation datasets (val_regex, val_cardinality, val_referential)
-  --aasx               Generate AASX packages (aasx_small.aasx, aasx_medium.aasx)
+  --aasx               Generate AASX packages (aasx_small.aasx, aasx_medium.aasx) with a
+                       thumbnail, PDFs and binary attachments (--aasx-*-kb set their sizes)
+  --mini               Generate miniature wide/deep/mixed JSON test fixtures
+  --scaling            Generate a size ladder (scale_wide_x1, scale_deep_x1, ... x16)
+  --i18n               Generate i18n.json, MultiLanguageProperties in many scripts (~10 MB)
+  --blob-heavy         Generate blob_heavy.json, Blobs of 1-50 MB base64 each (~91 MB)
+  --fuzz-corpus        Generate fuzz/, mutants of mixed and deep (renamed fields, flipped
+                       types, nesting thousands deep) for aasbench run --fuzz-corpus
 
 Usage:
     python3 datasets/generate.py --output-dir <dir>
@@ -17,14 +24,22 @@ Usage:
     python3 datasets/generate.py --output-dir <dir> --validation-targets
     python3 datasets/generate.py --output-dir <dir> --aasx
     python3 datasets/generate.py --output-dir <dir> --only mixed
+    python3 datasets/generate.py --output-dir <dir> --scaling --ladder 1,2,4,8
+    python3 datasets/generate.py --output-dir <dir> --i18n
+    python3 datasets/generate.py --output-dir <dir> --blob-heavy --blob-sizes 1,5
+    python3 datasets/generate.py --output-dir <dir> --fuzz-corpus --fuzz-mutants 20
+    python3 datasets/generate.py --output-dir sdks/aas-core3-golang/testdata/mini --mini
 """
 
 import argparse
 import base64
 import json
 import os
+import random
+import struct
 import xml.etree.ElementTree as ET
 import zipfile
+import zlib
 
 AAS_NS = "https://admin-shell.io/aas/3/0"
 
@@ -137,7 +152,7 @@ def make_environment(shells, submodels):
 # ---------------------------------------------------------------------------
 
 
-def build_wide():
+def build_wide(num_props=100_000):
     """1 AAS -> 1 Submodel -> 100,000 Property elements."""
     sm_id = "urn:benchmark:submodel:wide:0"
     # Pad values to ~200 extra chars so total file reaches ~30 MB.
@@ -147,7 +162,7 @@ def build_wide():
             f"Prop{i:06d}",
             f"val-{i}-" + f"benchmark-payload-{i:06d}-".ljust(200, "x"),
         )
-        for i in range(100_000)
+        for i in range(num_props)
     ]
     submodel = make_submodel(sm_id, "WideSubmodel", elements)
     aas = make_aas(
@@ -178,14 +193,14 @@ def _build_nested_collection(depth, max_depth, prefix):
     return make_collection(f"{prefix}_Col", props + [child])
 
 
-def build_deep():
+def build_deep(num_submodels=5, max_depth=15):
     """1 AAS -> 5 Submodels -> nested collections 15 levels deep, 5 props/level."""
     submodels = []
     sm_ids = []
-    for s in range(5):
+    for s in range(num_submodels):
         sm_id = f"urn:benchmark:submodel:deep:{s}"
         sm_ids.append(sm_id)
-        root = _build_nested_collection(1, 15, f"SM{s}_L1")
+        root = _build_nested_collection(1, max_depth, f"SM{s}_L1")
         submodels.append(make_submodel(sm_id, f"DeepSubmodel{s}", [root]))
     aas = make_aas(
         "urn:benchmark:aas:deep:0",
@@ -227,13 +242,13 @@ def _build_mixed_collection(depth, max_depth, prefix):
     return make_collection(f"{prefix}_Col", children)
 
 
-def build_mixed():
+def build_mixed(num_shells=5, submodels_per_shell=4, max_depth=4):
     """5 AAS -> 20 Submodels -> varied elements with 4-level nesting."""
     all_shells = []
     all_submodels = []
-    for a in range(5):
+    for a in range(num_shells):
         sm_ids = []
-        for s in range(4):
+        for s in range(submodels_per_shell):
             sm_id = f"urn:benchmark:submodel:mixed:{a}:{s}"
             sm_ids.append(sm_id)
             # Build a mix of top-level elements and nested collection trees
@@ -264,7 +279,7 @@ def build_mixed():
             # 2 nested collection trees for more volume
             for c in range(2):
                 elements.append(
-                    _build_mixed_collection(1, 4, f"A{a}S{s}_C{c}_L1")
+                    _build_mixed_collection(1, max_depth, f"A{a}S{s}_C{c}_L1")
                 )
             all_submodels.append(
                 make_submodel(sm_id, f"MixedSubmodel_A{a}_S{s}", elements)
@@ -285,6 +300,191 @@ DATASETS = {
     "mixed": build_mixed,
 }
 
+# Miniature datasets with the same shapes, a few KB each. The Go harness
+# embeds them as test fixtures (sdks/aas-core3-golang/testdata/mini).
+MINI_DATASETS = {
+    "wide": lambda: build_wide(num_props=20),
+    "deep": lambda: build_deep(num_submodels=2, max_depth=3),
+    "mixed": lambda: build_mixed(num_shells=1, submodels_per_shell=2, max_depth=2),
+}
+
+# Size ladder datasets, named scale_<shape>_x<factor>. Each rung multiplies
+# the element count of the x1 base by its factor: wide adds Properties side
+# by side, deep adds nesting levels, so an operation's time across the rungs
+# shows how it scales with breadth and with depth.
+DEFAULT_LADDER = [1, 2, 4, 8, 16]
+
+
+def build_deep_ladder(levels):
+    """1 AAS -> 1 Submodel -> a collection chain `levels` deep, 5 props/level.
+
+    Unlike build_deep, idShorts do not accumulate the path, so every level
+    adds the same number of bytes and only the nesting grows.
+    """
+    sm_id = "urn:benchmark:submodel:scale-deep:0"
+    node = None
+    for depth in range(levels, 0, -1):
+        children = [
+            make_property(f"Prop{i}", f"depth{depth:04d}-val{i}-" + ("d" * 80))
+            for i in range(5)
+        ]
+        if node is not None:
+            children.append(node)
+        node = make_collection(f"Level{depth:04d}", children)
+    submodel = make_submodel(sm_id, "ScaleDeepSubmodel", [node])
+    aas = make_aas(
+        "urn:benchmark:aas:scale-deep:0",
+        "ScaleDeepAAS",
+        "urn:benchmark:asset:scale-deep:0",
+        [sm_id],
+    )
+    return make_environment([aas], [submodel])
+
+
+SCALING_SHAPES = {
+    "wide": lambda factor: build_wide(num_props=1000 * factor),
+    "deep": lambda factor: build_deep_ladder(levels=8 * factor),
+}
+
+
+def parse_ladder(value):
+    """Parse a comma-separated ladder of positive factors, e.g. "1,2,4"."""
+    try:
+        factors = sorted({int(f) for f in value.split(",") if f.strip()})
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"invalid ladder {value!r}")
+    if not factors or factors[0] < 1:
+        raise argparse.ArgumentTypeError(f"ladder factors must be positive: {value!r}")
+    return factors
+
+
+def generate_scaling_datasets(output_dir, ladder):
+    """Generate every shape of the size ladder at every factor."""
+    for shape, builder in SCALING_SHAPES.items():
+        for factor in ladder:
+            name = f"scale_{shape}_x{factor}"
+            path = os.path.join(output_dir, f"{name}.json")
+            print(f"Generating {name}.json ...", end=" ", flush=True)
+            with open(path, "w") as f:
+                json.dump(builder(factor), f)
+            size_mb = os.path.getsize(path) / (1024 * 1024)
+            print(f"done ({size_mb:.1f} MB)")
+
+
+# Unicode stress dataset. Every MultiLanguageProperty carries one text per
+# entry below: CJK, right-to-left scripts, emoji (including ZWJ sequences,
+# skin tones and flags, which are surrogate pairs in UTF-16), and Latin text
+# in decomposed form with combining marks, so string decoding, validation
+# and encoding take their slow paths.
+I18N_TEXTS = [
+    ("zh-Hans", "最大转速与额定功率的测量值"),
+    ("ja", "最大回転速度と定格出力の測定値"),
+    ("ko", "최대 회전 속도와 정격 출력의 측정값"),
+    ("ar", "القيمة المقاسة لأقصى سرعة دوران والقدرة المقدرة"),
+    ("he", "הערך הנמדד של מהירות הסיבוב המרבית וההספק הנקוב"),
+    ("hi", "अधिकतम घूर्णन गति और रेटेड शक्ति का मापा गया मान"),
+    ("th", "ค่าที่วัดได้ของความเร็วรอบสูงสุดและกำลังพิกัด"),
+    ("en", "Motor 🚀 status ✅ team 👩\u200d👩\u200d👧\u200d👦 ok 👍🏽 site 🇩🇪🇯🇵"),
+    ("vi", "Gia\u0301 tri\u0323 đo đươ\u0323c cu\u0309a tô\u0301c đô\u0323 quay tô\u0301i đa"),
+    ("de", "Gemessener Wert der maximalen Drehzahl und Nennleistung – Größe ±5 %"),
+]
+
+
+def build_i18n(num_submodels=10, mlps_per_submodel=500, repeat=2):
+    """1 AAS -> 10 Submodels -> 500 MultiLanguageProperties in 10 languages each.
+
+    Each text is repeated `repeat` times, numbered so that no two elements
+    share a value.
+    """
+    all_submodels = []
+    sm_ids = []
+    for s in range(num_submodels):
+        sm_id = f"urn:benchmark:submodel:i18n:{s}"
+        sm_ids.append(sm_id)
+        elements = []
+        for m in range(mlps_per_submodel):
+            elements.append({
+                "modelType": "MultiLanguageProperty",
+                "idShort": f"Text{s}_{m}",
+                "value": [
+                    {"language": lang, "text": f"{s}.{m} " + " · ".join([text] * repeat)}
+                    for lang, text in I18N_TEXTS
+                ],
+            })
+        all_submodels.append(make_submodel(sm_id, f"I18nSubmodel{s}", elements))
+    aas = make_aas(
+        "urn:benchmark:aas:i18n:0",
+        "I18nAAS",
+        "urn:benchmark:asset:i18n:0",
+        sm_ids,
+    )
+    return make_environment([aas], all_submodels)
+
+
+def generate_i18n_dataset(output_dir):
+    """Generate i18n.json, written as raw UTF-8 rather than \\u escapes so
+    that readers decode multi-byte sequences as they would in the field."""
+    path = os.path.join(output_dir, "i18n.json")
+    print("Generating i18n.json ...", end=" ", flush=True)
+    with open(path, "w", encoding="utf-8") as f:
+        json.dump(build_i18n(), f, ensure_ascii=False)
+    size_mb = os.path.getsize(path) / (1024 * 1024)
+    print(f"done ({size_mb:.1f} MB)")
+
+
+# Blob-heavy dataset: one submodel per size, each carrying a Blob whose
+# base64 value is that many MB, as AASX payloads embedded in an environment
+# are. Only deserialize and serialize are measured on it.
+DEFAULT_BLOB_SIZES_MB = [1, 5, 10, 25, 50]
+
+
+def parse_blob_sizes(value):
+    """Parse comma-separated positive Blob sizes in MB, e.g. "1,5,50"."""
+    try:
+        sizes = [int(s) for s in value.split(",") if s.strip()]
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"invalid blob sizes {value!r}")
+    if not sizes or min(sizes) < 1:
+        raise argparse.ArgumentTypeError(f"blob sizes must be positive: {value!r}")
+    return sizes
+
+
+def build_blob_heavy(sizes_mb=DEFAULT_BLOB_SIZES_MB):
+    """1 AAS -> one Submodel per size -> a Blob of that many MB base64 + 3 props."""
+    all_submodels = []
+    sm_ids = []
+    for i, size_mb in enumerate(sizes_mb):
+        sm_id = f"urn:benchmark:submodel:blob-heavy:{i}"
+        sm_ids.append(sm_id)
+        # base64 encodes 3 bytes as 4 characters.
+        raw_size = size_mb * 1024 * 1024 * 3 // 4
+        chunk = f"blob-heavy-{i:02d}-".encode("ascii") + bytes(range(256))
+        payload = (chunk * (raw_size // len(chunk) + 1))[:raw_size]
+        elements = [
+            make_property(f"Name{i}", f"payload-{size_mb}mb.bin"),
+            make_property(f"SizeMB{i}", str(size_mb)),
+            make_property(f"Checksum{i}", f"{sum(payload) & 0xFFFFFFFF:08x}"),
+            make_blob(f"Payload{i}", payload),
+        ]
+        all_submodels.append(make_submodel(sm_id, f"BlobSubmodel{i}", elements))
+    aas = make_aas(
+        "urn:benchmark:aas:blob-heavy:0",
+        "BlobHeavyAAS",
+        "urn:benchmark:asset:blob-heavy:0",
+        sm_ids,
+    )
+    return make_environment([aas], all_submodels)
+
+
+def generate_blob_heavy_dataset(output_dir, sizes_mb):
+    """Generate blob_heavy.json."""
+    path = os.path.join(output_dir, "blob_heavy.json")
+    print("Generating blob_heavy.json ...", end=" ", flush=True)
+    with open(path, "w") as f:
+        json.dump(build_blob_heavy(sizes_mb), f)
+    size_mb = os.path.getsize(path) / (1024 * 1024)
+    print(f"done ({size_mb:.1f} MB)")
+
 
 # ---------------------------------------------------------------------------
 # XML generation (SRQ-1)
@@ -575,64 +775,317 @@ def generate_validation_datasets(output_dir):
 # AASX generation (SRQ-4)
 # ---------------------------------------------------------------------------
 
-CONTENT_TYPES_XML = """\
-<?xml version="1.0" encoding="UTF-8"?>
-<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">
-  <Default Extension="json" ContentType="application/json"/>
-  <Default Extension="bin" ContentType="application/octet-stream"/>
-  <Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>
-</Types>"""
-
-RELS_XML = """\
-<?xml version="1.0" encoding="UTF-8"?>
-<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
-  <Relationship Id="rId1" Type="http://www.admin-shell.io/aasx/relationships/aas-spec"
-                Target="/aasx/environment.json"/>
-</Relationships>"""
-
-
-def generate_aasx_datasets(output_dir):
-    """Generate AASX packages with embedded environment JSON and supplementary files."""
-    # aasx_small: mixed.json + 5 synthetic 1KB binary files
-    _generate_aasx(
-        output_dir,
-        "aasx_small",
-        build_mixed,
-        num_binaries=5,
-        binary_size=1024,
-    )
-    # aasx_medium: wide.json + 20 synthetic 100KB binary files
-    _generate_aasx(
-        output_dir,
-        "aasx_medium",
-        build_wide,
-        num_binaries=20,
-        binary_size=100 * 1024,
+# An AASX file is an OPC package: [Content_Types].xml maps extensions to
+# media types, the package relationships point to the aasx-origin part and
+# the thumbnail, the origin's relationships to the environment, and the
+# environment's relationships to each supplementary file.
+REL_AASX_ORIGIN = "http://admin-shell.io/aasx/relationships/aasx-origin"
+REL_AAS_SPEC = "http://admin-shell.io/aasx/relationships/aas-spec"
+REL_AAS_SUPPL = "http://admin-shell.io/aasx/relationships/aas-suppl"
+REL_THUMBNAIL = "http://schemas.openxmlformats.org/package/2006/relationships/metadata/thumbnail"
+
+CONTENT_TYPES = {
+    "json": "application/json",
+    "png": "image/png",
+    "pdf": "application/pdf",
+    "bin": "application/octet-stream",
+    "rels": "application/vnd.openxmlformats-package.relationships+xml",
+}
+
+# Fixed so that regenerated packages are byte-identical.
+ZIP_DATE_TIME = (1980, 1, 1, 0, 0, 0)
+
+
+class AasxProfile:
+    """The environment and supplementary files of one AASX package. Sizes
+    are in KB; each entry of pdf_kb and attachment_kb is one file."""
+
+    def __init__(self, env_builder, thumbnail_kb, pdf_kb, attachment_kb):
+        self.env_builder = env_builder
+        self.thumbnail_kb = thumbnail_kb
+        self.pdf_kb = pdf_kb
+        self.attachment_kb = attachment_kb
+
+
+AASX_PROFILES = {
+    # mixed + a small thumbnail, one datasheet and 5 x 1 KB attachments
+    "aasx_small": AasxProfile(build_mixed, thumbnail_kb=16, pdf_kb=[64], attachment_kb=[1] * 5),
+    # wide + a large thumbnail, three manuals and 20 x 100 KB attachments
+    "aasx_medium": AasxProfile(
+        build_wide, thumbnail_kb=256, pdf_kb=[512, 1024, 2048], attachment_kb=[100] * 20
+    ),
+}
+
+
+def parse_kb_sizes(value):
+    """Parse comma-separated non-negative sizes in KB, e.g. "1,100,1024"."""
+    try:
+        sizes = [int(s) for s in value.split(",") if s.strip()]
+    except ValueError:
+        raise argparse.ArgumentTypeError(f"invalid sizes {value!r}")
+    if min(sizes, default=0) < 0:
+        raise argparse.ArgumentTypeError(f"sizes must not be negative: {value!r}")
+    return sizes
+
+
+def _noise(seed, size):
+    """Deterministic incompressible bytes, like real images and binaries."""
+    out = bytearray(size)
+    state = (seed * 2654435761 + 1) & 0xFFFFFFFF
+    for i in range(size):
+        state = (state * 1103515245 + 12345) & 0xFFFFFFFF
+        out[i] = state >> 24
+    return bytes(out)
+
+
+def make_png(size_bytes, seed=0):
+    """A valid RGB PNG of noise, about size_bytes large."""
+    side = max(1, int((max(size_bytes, 1) / 3) ** 0.5))
+    row = side * 3
+    pixels = _noise(seed, row * side)
+    raw = b"".join(b"\x00" + pixels[y * row:(y + 1) * row] for y in range(side))
+
+    def chunk(kind, data):
+        body = kind + data
+        return struct.pack(">I", len(data)) + body + struct.pack(">I", zlib.crc32(body))
+
+    return (
+        b"\x89PNG\r\n\x1a\n"
+        + chunk(b"IHDR", struct.pack(">IIBBBBB", side, side, 8, 2, 0, 0, 0))
+        + chunk(b"IDAT", zlib.compress(raw, 9))
+        + chunk(b"IEND", b"")
     )
 
 
-def _generate_aasx(output_dir, name, env_builder, num_binaries, binary_size):
+def make_pdf(size_bytes, title, seed=0):
+    """A valid one-page PDF naming title, padded to about size_bytes with an
+    embedded binary stream, as scanned manuals carry images."""
+    text = f"BT /F1 18 Tf 72 720 Td ({title}) Tj ET".encode("ascii")
+    objects = [
+        b"<< /Type /Catalog /Pages 2 0 R >>",
+        b"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+        b"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] "
+        b"/Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+        b"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>",
+        b"<< /Length %d >>\nstream\n%s\nendstream" % (len(text), text),
+    ]
+    padding = _noise(seed, max(0, size_bytes - 1024))
+    objects.append(b"<< /Length %d >>\nstream\n%s\nendstream" % (len(padding), padding))
+
+    out = bytearray(b"%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+    offsets = []
+    for i, obj in enumerate(objects, start=1):
+        offsets.append(len(out))
+        out += b"%d 0 obj\n%s\nendobj\n" % (i, obj)
+    xref = len(out)
+    out += b"xref\n0 %d\n0000000000 65535 f \n" % (len(objects) + 1)
+    for offset in offsets:
+        out += b"%010d 00000 n \n" % offset
+    out += b"trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n" % (len(objects) + 1, xref)
+    return bytes(out)
+
+
+def make_file(id_short, path, content_type):
+    """Create an AAS v3.0 File element pointing into the package."""
+    return {
+        "modelType": "File",
+        "idShort": id_short,
+        "contentType": content_type,
+        "value": path,
+    }
+
+
+def _relationships(rels):
+    """An OPC relationships part for (id, type, target) triples."""
+    lines = ['<?xml version="1.0" encoding="UTF-8"?>',
+             '<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">']
+    for rel_id, rel_type, target in rels:
+        lines.append(f'  <Relationship Id="{rel_id}" Type="{rel_type}" Target="{target}"/>')
+    lines.append("</Relationships>")
+    return "\n".join(lines)
+
+
+def _content_types():
+    lines = ['<?xml version="1.0" encoding="UTF-8"?>',
+             '<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">']
+    for ext, media_type in CONTENT_TYPES.items():
+        lines.append(f'  <Default Extension="{ext}" ContentType="{media_type}"/>')
+    # The origin part has no extension.
+    lines.append('  <Override PartName="/aasx/aasx-origin" ContentType="text/plain"/>')
+    lines.append("</Types>")
+    return "\n".join(lines)
+
+
+def generate_aasx_datasets(output_dir, thumbnail_kb=None, pdf_kb=None, attachment_kb=None):
+    """Generate AASX packages with an embedded environment JSON and
+    supplementary files. The size arguments replace every profile's own."""
+    for name, profile in AASX_PROFILES.items():
+        _generate_aasx(
+            output_dir,
+            name,
+            profile.env_builder,
+            profile.thumbnail_kb if thumbnail_kb is None else thumbnail_kb,
+            profile.pdf_kb if pdf_kb is None else pdf_kb,
+            profile.attachment_kb if attachment_kb is None else attachment_kb,
+        )
+
+
+def _generate_aasx(output_dir, name, env_builder, thumbnail_kb, pdf_kb, attachment_kb):
     """Create a single AASX package."""
     path = os.path.join(output_dir, f"{name}.aasx")
     print(f"Generating {name}.aasx ...", end=" ", flush=True)
 
+    # Supplementary files as (part name, content type, bytes).
+    supplementary = []
+    for i, kb in enumerate(pdf_kb):
+        supplementary.append((
+            f"/aasx/supplementary/manual_{i:02d}.pdf",
+            CONTENT_TYPES["pdf"],
+            make_pdf(kb * 1024, f"{name} manual {i}", seed=100 + i),
+        ))
+    for i, kb in enumerate(attachment_kb):
+        supplementary.append((
+            f"/aasx/supplementary/binary_{i:04d}.bin",
+            CONTENT_TYPES["bin"],
+            _noise(1000 + i, kb * 1024),
+        ))
+
     env = env_builder()
-    env_json = json.dumps(env)
+    thumbnail = "/aasx/thumbnail.png"
+    env["assetAdministrationShells"][0]["assetInformation"]["defaultThumbnail"] = {
+        "path": thumbnail,
+        "contentType": CONTENT_TYPES["png"],
+    }
+    env["submodels"].append(make_submodel(
+        f"urn:benchmark:submodel:{name}:documentation",
+        "Documentation",
+        [make_file(f"File{i:04d}", part, ctype) for i, (part, ctype, _) in enumerate(supplementary)],
+    ))
+    env["assetAdministrationShells"][0]["submodels"].append({
+        "type": "ModelReference",
+        "keys": [{"type": "Submodel", "value": f"urn:benchmark:submodel:{name}:documentation"}],
+    })
+
+    parts = [
+        ("[Content_Types].xml", _content_types().encode()),
+        ("_rels/.rels", _relationships([
+            ("rId1", REL_AASX_ORIGIN, "/aasx/aasx-origin"),
+            ("rId2", REL_THUMBNAIL, thumbnail),
+        ]).encode()),
+        ("aasx/aasx-origin", b"Intentionally empty."),
+        ("aasx/_rels/aasx-origin.rels", _relationships([
+            ("rId1", REL_AAS_SPEC, "/aasx/environment.json"),
+        ]).encode()),
+        ("aasx/environment.json", json.dumps(env).encode()),
+        ("aasx/_rels/environment.json.rels", _relationships([
+            (f"rId{i + 1}", REL_AAS_SUPPL, part) for i, (part, _, _) in enumerate(supplementary)
+        ]).encode()),
+        ("aasx/thumbnail.png", make_png(thumbnail_kb * 1024, seed=1)),
+    ]
+    parts += [(part.lstrip("/"), data) for part, _, data in supplementary]
 
     with zipfile.ZipFile(path, "w", zipfile.ZIP_DEFLATED) as zf:
-        zf.writestr("[Content_Types].xml", CONTENT_TYPES_XML)
-        zf.writestr("_rels/.rels", RELS_XML)
-        zf.writestr("aasx/environment.json", env_json)
-
-        # Deterministic binary supplementary files
-        for i in range(num_binaries):
-            # Deterministic content: repeating pattern based on index
-            chunk = f"binary-payload-{i:04d}-".encode("ascii")
-            data = (chunk * ((binary_size // len(chunk)) + 1))[:binary_size]
-            zf.writestr(f"aasx/supplementary/binary_{i:04d}.bin", data)
+        for part, data in parts:
+            info = zipfile.ZipInfo(part, date_time=ZIP_DATE_TIME)
+            info.compress_type = zipfile.ZIP_DEFLATED
+            zf.writestr(info, data)
 
     size_mb = os.path.getsize(path) / (1024 * 1024)
-    print(f"done ({size_mb:.1f} MB)")
+    print(f"done ({size_mb:.1f} MB, {len(supplementary) + 1} supplementary files)")
+
+
+# ---------------------------------------------------------------------------
+# Fuzz corpus
+# ---------------------------------------------------------------------------
+
+# Mutations of the fuzz corpus. aasbench run --fuzz-corpus feeds every
+# mutant to the deserializer; a rejection or an acceptance is fine, a
+# panic, a hang or runaway memory is not.
+FUZZ_MUTATIONS = ("field_rename", "type_flip", "depth_explosion")
+FUZZ_SOURCES = {"mixed": build_mixed, "deep": build_deep}
+DEFAULT_FUZZ_MUTANTS = 10
+DEFAULT_FUZZ_MAX_DEPTH = 5000
+
+
+def _json_objects(node, out):
+    """Collect every object of a JSON document, depth first."""
+    if isinstance(node, dict):
+        out.append(node)
+        for value in node.values():
+            _json_objects(value, out)
+    elif isinstance(node, list):
+        for value in node:
+            _json_objects(value, out)
+    return out
+
+
+def _mutate_field_rename(env, rng):
+    """Rename one key of a random object: a typo, a case change or a
+    suffix."""
+    obj = rng.choice([o for o in _json_objects(env, []) if o])
+    key = rng.choice(sorted(obj))
+    renamed = rng.choice([key[:-1], key.upper(), key + "_", key[::-1]]) or "_"
+    obj[renamed] = obj.pop(key)
+
+
+def _mutate_type_flip(env, rng):
+    """Replace one value of a random object with one of another JSON type."""
+    obj = rng.choice([o for o in _json_objects(env, []) if o])
+    key = rng.choice(sorted(obj))
+    value = obj[key]
+    flips = [42, -1.5, True, None, "flipped", [], {}]
+    obj[key] = rng.choice([f for f in flips if type(f) is not type(value)])
+
+
+def _mutate_depth_explosion(env, rng, max_depth):
+    """Mark a random submodel's first element for a chain of nested
+    collections between max_depth / 10 and max_depth deep. Returns the
+    depth; _render_fuzz_mutant expands the marker, since the json module
+    cannot encode documents that deep."""
+    submodels = [sm for sm in env["submodels"] if sm.get("submodelElements")]
+    sm = rng.choice(submodels)
+    sm["submodelElements"][0] = "__DEPTH_EXPLOSION__"
+    return rng.randint(max(1, max_depth // 10), max_depth)
+
+
+def _render_fuzz_mutant(env, depth):
+    """Encode a mutant, expanding the depth explosion marker."""
+    raw = json.dumps(env)
+    if not depth:
+        return raw
+    head = '{"modelType":"SubmodelElementCollection","idShort":"Nested","value":['
+    leaf = json.dumps(make_property("Leaf"))
+    return raw.replace('"__DEPTH_EXPLOSION__"', head * depth + leaf + "]}" * depth, 1)
+
+
+def generate_fuzz_corpus(output_dir, mutants, max_depth, seed):
+    """Write fuzz/<dataset>_<mutation>_<n>.json for every source dataset
+    and mutation, and fuzz/corpus.json indexing them."""
+    corpus_dir = os.path.join(output_dir, "fuzz")
+    os.makedirs(corpus_dir, exist_ok=True)
+    inputs = []
+    for dataset, builder in FUZZ_SOURCES.items():
+        print(f"Generating fuzz corpus of {dataset} ...", end=" ", flush=True)
+        source = json.dumps(builder())
+        for mutation in FUZZ_MUTATIONS:
+            for n in range(mutants):
+                mutant_seed = seed + len(inputs)
+                rng = random.Random(mutant_seed)
+                env = json.loads(source)
+                depth = 0
+                if mutation == "field_rename":
+                    _mutate_field_rename(env, rng)
+                elif mutation == "type_flip":
+                    _mutate_type_flip(env, rng)
+                else:
+                    depth = _mutate_depth_explosion(env, rng, max_depth)
+                name = f"{dataset}_{mutation}_{n:03d}.json"
+                with open(os.path.join(corpus_dir, name), "w") as f:
+                    f.write(_render_fuzz_mutant(env, depth))
+                inputs.append({"file": name, "dataset": dataset, "mutation": mutation, "seed": mutant_seed})
+        print(f"done ({mutants * len(FUZZ_MUTATIONS)} mutants)")
+    with open(os.path.join(corpus_dir, "corpus.json"), "w") as f:
+        json.dump({"inputs": inputs}, f, indent=2)
 
 
 # ---------------------------------------------------------------------------
@@ -670,13 +1123,90 @@ def main():
         action="store_true",
         help="Generate AASX package datasets.",
     )
+    parser.add_argument(
+        "--aasx-thumbnail-kb",
+        type=int,
+        default=None,
+        help="Thumbnail PNG size in KB for --aasx (default: per package, 16 and 256).",
+    )
+    parser.add_argument(
+        "--aasx-pdf-kb",
+        type=parse_kb_sizes,
+        default=None,
+        help="Comma-separated PDF sizes in KB for --aasx, one PDF each (default: per package).",
+    )
+    parser.add_argument(
+        "--aasx-attachment-kb",
+        type=parse_kb_sizes,
+        default=None,
+        help="Comma-separated binary attachment sizes in KB for --aasx, one file each (default: per package).",
+    )
+    parser.add_argument(
+        "--mini",
+        action="store_true",
+        help="Generate miniature JSON datasets for harness unit tests.",
+    )
+    parser.add_argument(
+        "--scaling",
+        action="store_true",
+        help="Generate the size ladder datasets for scaling curves.",
+    )
+    parser.add_argument(
+        "--i18n",
+        action="store_true",
+        help="Generate the Unicode stress dataset i18n.json.",
+    )
+    parser.add_argument(
+        "--blob-heavy",
+        action="store_true",
+        help="Generate the base64 Blob dataset blob_heavy.json.",
+    )
+    parser.add_argument(
+        "--blob-sizes",
+        type=parse_blob_sizes,
+        default=DEFAULT_BLOB_SIZES_MB,
+        help="Comma-separated Blob sizes in MB of base64 for --blob-heavy (default: 1,5,10,25,50).",
+    )
+    parser.add_argument(
+        "--fuzz-corpus",
+        action="store_true",
+        help="Generate the deserializer fuzz corpus under fuzz/.",
+    )
+    parser.add_argument(
+        "--fuzz-mutants",
+        type=int,
+        default=DEFAULT_FUZZ_MUTANTS,
+        help=f"Mutants per dataset and mutation for --fuzz-corpus (default: {DEFAULT_FUZZ_MUTANTS}).",
+    )
+    parser.add_argument(
+        "--fuzz-max-depth",
+        type=int,
+        default=DEFAULT_FUZZ_MAX_DEPTH,
+        help=f"Deepest collection nesting of depth_explosion mutants (default: {DEFAULT_FUZZ_MAX_DEPTH}).",
+    )
+    parser.add_argument(
+        "--fuzz-seed",
+        type=int,
+        default=0,
+        help="Seed of the first fuzz mutant; the corpus is deterministic per seed (default: 0).",
+    )
+    parser.add_argument(
+        "--ladder",
+        type=parse_ladder,
+        default=DEFAULT_LADDER,
+        help="Comma-separated size factors for --scaling (default: 1,2,4,8,16).",
+    )
     args = parser.parse_args()
 
     os.makedirs(args.output_dir, exist_ok=True)
 
     # If no special flag is set, generate standard JSON datasets
-    if not args.xml and not args.validation_targets and not args.aasx:
-        targets = {args.only: DATASETS[args.only]} if args.only else DATASETS
+    special = (
+        args.xml, args.validation_targets, args.aasx, args.scaling, args.i18n, args.blob_heavy, args.fuzz_corpus
+    )
+    if not any(special):
+        builders = MINI_DATASETS if args.mini else DATASETS
+        targets = {args.only: builders[args.only]} if args.only else builders
 
         for name, builder in targets.items():
             path = os.path.join(args.output_dir, f"{name}.json")
@@ -699,9 +1229,27 @@ def main():
         print("Validation datasets written to", args.output_dir)
 
     if args.aasx:
-        generate_aasx_datasets(args.output_dir)
+        generate_aasx_datasets(
+            args.output_dir, args.aasx_thumbnail_kb, args.aasx_pdf_kb, args.aasx_attachment_kb
+        )
         print("AASX datasets written to", args.output_dir)
 
+    if args.scaling:
+        generate_scaling_datasets(args.output_dir, args.ladder)
+        print("Scaling datasets written to", args.output_dir)
+
+    if args.i18n:
+        generate_i18n_dataset(args.output_dir)
+        print("i18n dataset written to", args.output_dir)
+
+    if args.blob_heavy:
+        generate_blob_heavy_dataset(args.output_dir, args.blob_sizes)
+        print("Blob-heavy dataset written to", args.output_dir)
+
+    if args.fuzz_corpus:
+        generate_fuzz_corpus(args.output_dir, args.fuzz_mutants, args.fuzz_max_depth, args.fuzz_seed)
+        print("Fuzz corpus written to", os.path.join(args.output_dir, "fuzz"))
+
 
 if __name__ == "__main__":
     main()
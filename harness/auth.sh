@@ -0,0 +1,25 @@
+#!/usr/bin/env bash
+# Sourced by the harness's curl-based scripts: sets AUTH_ARGS to the curl
+# arguments that authenticate a request, for the AUTH_MODE and AUTH_*
+# variables harness/k6/lib/http.js documents.
+
+AUTH_ARGS=()
+case "${AUTH_MODE:-none}" in
+  none) ;;
+  api_key)
+    AUTH_ARGS=(-H "${AUTH_HEADER:-X-API-Key}: ${AUTH_API_KEY:?AUTH_API_KEY is required for api_key auth}")
+    ;;
+  oauth2)
+    TOKEN_ARGS=(-d grant_type=client_credentials -d "client_id=$AUTH_CLIENT_ID" -d "client_secret=$AUTH_CLIENT_SECRET")
+    [ -n "${AUTH_SCOPE:-}" ] && TOKEN_ARGS+=(-d "scope=$AUTH_SCOPE")
+    AUTH_TOKEN="$(curl -sf "${TOKEN_ARGS[@]}" "$AUTH_TOKEN_URL" | jq -r '.access_token')"
+    AUTH_ARGS=(-H "Authorization: Bearer $AUTH_TOKEN")
+    ;;
+  mtls)
+    AUTH_ARGS=(--cert "$AUTH_CERT" --key "$AUTH_KEY")
+    ;;
+  *)
+    echo "Error: unknown AUTH_MODE '$AUTH_MODE' (none, api_key, oauth2 or mtls)" >&2
+    exit 1
+    ;;
+esac
@@ -0,0 +1,157 @@
+#!/usr/bin/env python3
+"""Measure the end-to-end latency and loss of a server's MQTT update events.
+
+Subscribes to the server's broker, sets the value of one Property N times
+through PATCH .../$value and matches every event to its update by the
+unique value it carries. Latency runs from sending the PATCH to receiving
+its event, so it includes the HTTP request. An update whose event has not
+arrived TIMEOUT seconds after the last update counts as lost. The result
+is written to <output_dir>/eventing_<server_id>.json under the eventing
+track.
+"""
+
+import argparse
+import base64
+import json
+import math
+import re
+import sys
+import threading
+import time
+import urllib.error
+import urllib.request
+import uuid
+from pathlib import Path
+
+import paho.mqtt.client as mqtt
+
+# The element seed-test-data.sh creates.
+SUBMODEL_ID = "urn:example:submodel:test-1"
+ID_SHORT_PATH = "TestProperty"
+
+
+def percentile(sorted_values: list[float], p: float) -> float:
+    """Return the nearest-rank percentile p (0-100) of sorted_values."""
+    rank = max(1, min(len(sorted_values), math.ceil(p / 100 * len(sorted_values))))
+    return sorted_values[rank - 1]
+
+
+def latency_stats(latencies_ms: list[float]) -> dict | None:
+    if not latencies_ms:
+        return None
+    values = sorted(latencies_ms)
+    return {
+        "mean": sum(values) / len(values),
+        "median": percentile(values, 50),
+        "p95": percentile(values, 95),
+        "p99": percentile(values, 99),
+        "min": values[0],
+        "max": values[-1],
+    }
+
+
+class EventCollector:
+    """Records when each update's event arrives, keyed by update index."""
+
+    def __init__(self, run_token: str):
+        self.pattern = re.compile(re.escape(run_token) + r"-(\d+)")
+        self.received: dict[int, float] = {}
+        self.lock = threading.Lock()
+        self.subscribed = threading.Event()
+
+    def on_subscribe(self, client, userdata, mid, reason_codes, properties):
+        self.subscribed.set()
+
+    def on_message(self, client, userdata, message):
+        now = time.perf_counter()
+        match = self.pattern.search(message.payload.decode("utf-8", "replace"))
+        if match:
+            with self.lock:
+                self.received.setdefault(int(match.group(1)), now)
+
+
+def patch_value(url: str, value: str) -> bool:
+    req = urllib.request.Request(
+        url, data=json.dumps(value).encode(), method="PATCH",
+        headers={"Content-Type": "application/json"},
+    )
+    try:
+        with urllib.request.urlopen(req, timeout=30) as resp:
+            return 200 <= resp.status < 300
+    except urllib.error.URLError as exc:
+        print(f"  WARNING: PATCH failed: {exc}", file=sys.stderr)
+        return False
+
+
+def main():
+    parser = argparse.ArgumentParser(description=__doc__)
+    parser.add_argument("--base-url", required=True, help="Server API base URL")
+    parser.add_argument("--broker-host", default="localhost")
+    parser.add_argument("--broker-port", type=int, default=1883)
+    parser.add_argument("--topic", default="#", help="Subscription filter (default: #)")
+    parser.add_argument("--updates", type=int, default=500, help="Timed updates (default: 500)")
+    parser.add_argument("--warmup", type=int, default=20, help="Untimed updates first (default: 20)")
+    parser.add_argument("--interval", type=float, default=0.02, help="Seconds between updates (default: 0.02)")
+    parser.add_argument("--timeout", type=float, default=5.0, help="Seconds to wait for late events (default: 5)")
+    parser.add_argument("--sdk-id", required=True)
+    parser.add_argument("--output-dir", type=Path, required=True)
+    args = parser.parse_args()
+
+    submodel = base64.urlsafe_b64encode(SUBMODEL_ID.encode()).decode().rstrip("=")
+    url = f"{args.base_url}/submodels/{submodel}/submodel-elements/{ID_SHORT_PATH}/$value"
+    run_token = f"aasbench-{uuid.uuid4().hex[:8]}"
+
+    collector = EventCollector(run_token)
+    client = mqtt.Client(mqtt.CallbackAPIVersion.VERSION2, client_id=run_token)
+    client.on_subscribe = collector.on_subscribe
+    client.on_message = collector.on_message
+    client.connect(args.broker_host, args.broker_port)
+    client.subscribe(args.topic, qos=1)
+    client.loop_start()
+    if not collector.subscribed.wait(30):
+        sys.exit(f"Error: no SUBACK from {args.broker_host}:{args.broker_port}")
+
+    total = args.warmup + args.updates
+    sent: dict[int, float] = {}
+    failed_requests = 0
+    print(f"Sending {args.warmup} warm-up and {args.updates} timed updates to {url}")
+    for i in range(total):
+        start = time.perf_counter()
+        if patch_value(url, f"{run_token}-{i}"):
+            sent[i] = start
+        else:
+            failed_requests += 1
+        time.sleep(args.interval)
+
+    deadline = time.perf_counter() + args.timeout
+    timed = [i for i in range(args.warmup, total) if i in sent]
+    while time.perf_counter() < deadline:
+        with collector.lock:
+            if all(i in collector.received for i in timed):
+                break
+        time.sleep(0.05)
+    client.loop_stop()
+    client.disconnect()
+
+    with collector.lock:
+        latencies = [(collector.received[i] - sent[i]) * 1000 for i in timed if i in collector.received]
+    lost = len(timed) - len(latencies)
+    result = {
+        "track": "eventing",
+        "protocol": "mqtt",
+        "topic": args.topic,
+        "updates": len(timed),
+        "failed_requests": failed_requests,
+        "received": len(latencies),
+        "lost": lost,
+        "loss_rate": lost / len(timed) if timed else None,
+        "latency_ms": latency_stats(latencies),
+    }
+    args.output_dir.mkdir(parents=True, exist_ok=True)
+    out = args.output_dir / f"eventing_{args.sdk_id}.json"
+    out.write_text(json.dumps(result, indent=2) + "\n", encoding="utf-8")
+    print(f"Received {len(latencies)}/{len(timed)} events; wrote {out}")
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,89 @@
+import http, { AUTH_MODE, authOptions, fetchToken } from "./lib/http.js";
+import k6http from "k6/http";
+import { check } from "k6";
+import { Trend } from "k6/metrics";
+
+const BASE_URL = __ENV.BASE_URL;
+const SDK_ID = __ENV.SDK_ID;
+const OUTPUT_DIR = __ENV.OUTPUT_DIR;
+// ANON_BASE_URL is an anonymous deployment of the same server; without it
+// only the authenticated requests and, for oauth2, the token requests are
+// measured.
+const ANON_BASE_URL = __ENV.ANON_BASE_URL || "";
+const REQUESTS = parseInt(__ENV.AUTH_REQUESTS || "500", 10);
+const TOKEN_REQUESTS = parseInt(__ENV.AUTH_TOKEN_REQUESTS || "20", 10);
+
+const authenticated = new Trend("auth_authenticated_ms", true);
+const anonymous = new Trend("auth_anonymous_ms", true);
+const tokenFetch = new Trend("auth_token_ms", true);
+
+export const options = {
+  ...authOptions,
+  summaryTrendStats: ["avg", "min", "med", "max", "p(95)", "count"],
+  scenarios: {
+    auth_overhead: {
+      executor: "per-vu-iterations",
+      vus: 1,
+      iterations: REQUESTS,
+      maxDuration: "15m",
+    },
+  },
+};
+
+export function setup() {
+  if (AUTH_MODE === "oauth2") {
+    for (let i = 0; i < TOKEN_REQUESTS; i++) {
+      tokenFetch.add(fetchToken());
+    }
+  }
+}
+
+// Each iteration reads the shell list once authenticated and, when an
+// anonymous deployment is given, once anonymously, alternating the order
+// so that neither side always finds the connection warm.
+export default function () {
+  const timed = [
+    () => {
+      const res = http.get(`${BASE_URL}/shells`, { tags: { name: "authenticated" } });
+      if (check(res, { "authenticated GET /shells status is 200": (r) => r.status === 200 })) {
+        authenticated.add(res.timings.duration);
+      }
+    },
+  ];
+  if (ANON_BASE_URL) {
+    timed.push(() => {
+      const res = k6http.get(`${ANON_BASE_URL}/shells`, { tags: { name: "anonymous" } });
+      if (check(res, { "anonymous GET /shells status is 200": (r) => r.status === 200 })) {
+        anonymous.add(res.timings.duration);
+      }
+    });
+  }
+  if (__ITER % 2 === 1) {
+    timed.reverse();
+  }
+  timed.forEach((fn) => fn());
+}
+
+// handleSummary writes the request times of both sides and the overhead of
+// authenticating, as the difference of their medians.
+export function handleSummary(data) {
+  const stats = (name) => {
+    const v = data.metrics[name]?.values;
+    if (!v || !v.count) {
+      return null;
+    }
+    return { requests: v.count, mean_ms: v.avg, median_ms: v.med, p95_ms: v["p(95)"], max_ms: v.max };
+  };
+  const auth = stats("auth_authenticated_ms");
+  const anon = stats("auth_anonymous_ms");
+  const result = {
+    mode: AUTH_MODE,
+    authenticated: auth,
+    anonymous: anon,
+    token: stats("auth_token_ms"),
+    overhead_median_ms: auth && anon ? auth.median_ms - anon.median_ms : null,
+  };
+  return {
+    [`${OUTPUT_DIR}/k6_auth_${SDK_ID}.json`]: JSON.stringify(result),
+  };
+}
@@ -0,0 +1,172 @@
+import http, { authOptions } from "./lib/http.js";
+import { check } from "k6";
+import encoding from "k6/encoding";
+import { Counter, Trend } from "k6/metrics";
+
+const BASE_URL = __ENV.BASE_URL;
+const SDK_ID = __ENV.SDK_ID;
+const OUTPUT_DIR = __ENV.OUTPUT_DIR;
+// UPLOAD_PATH is a server's whole-environment import endpoint (e.g. /upload
+// on BaSyx); without it the environment is imported through POST /submodels
+// and POST /shells.
+const UPLOAD_PATH = __ENV.UPLOAD_PATH || "";
+const REPEATS = parseInt(__ENV.BULK_REPEATS || "3", 10);
+
+// Repository sizes in submodel elements, split into submodels of
+// ELEMENTS_PER_SUBMODEL Property elements under one shell.
+const SIZES = [100, 1000, 10000];
+const ELEMENTS_PER_SUBMODEL = 100;
+
+const importTrends = {};
+for (const size of SIZES) {
+  importTrends[size] = new Trend(`bulk_import_ms_${size}`, true);
+}
+const importFailures = new Counter("bulk_import_failures");
+
+export const options = {
+  ...authOptions,
+  // count lets handleSummary tell how many imports of a size succeeded.
+  summaryTrendStats: ["avg", "min", "med", "max", "p(95)", "count"],
+  scenarios: {
+    bulk_import: {
+      executor: "per-vu-iterations",
+      vus: 1,
+      iterations: SIZES.length * REPEATS,
+      maxDuration: "30m",
+    },
+  },
+};
+
+function base64UrlEncode(id) {
+  return encoding.b64encode(id, "rawurl");
+}
+
+// buildEnvironment returns an environment of size Property elements with
+// ids unique to this iteration.
+function buildEnvironment(size) {
+  const prefix = `urn:example:bulk:${size}:${__ITER}`;
+  const submodels = [];
+  for (let s = 0; s * ELEMENTS_PER_SUBMODEL < size; s++) {
+    const elements = [];
+    for (let e = 0; e < ELEMENTS_PER_SUBMODEL && s * ELEMENTS_PER_SUBMODEL + e < size; e++) {
+      elements.push({
+        idShort: `Property${e}`,
+        modelType: "Property",
+        valueType: "xs:string",
+        value: `value-${s}-${e}`,
+      });
+    }
+    submodels.push({
+      id: `${prefix}:sm:${s}`,
+      idShort: `BulkSubmodel${s}`,
+      modelType: "Submodel",
+      submodelElements: elements,
+    });
+  }
+  const shell = {
+    id: `${prefix}:aas`,
+    idShort: `BulkShell${size}`,
+    modelType: "AssetAdministrationShell",
+    assetInformation: {
+      assetKind: "Instance",
+      globalAssetId: `${prefix}:asset`,
+    },
+    submodels: submodels.map((sm) => ({
+      type: "ModelReference",
+      keys: [{ type: "Submodel", value: sm.id }],
+    })),
+  };
+  return { assetAdministrationShells: [shell], submodels: submodels };
+}
+
+// serializeEnvironment prepares the request bodies of env, so that only the
+// requests are timed.
+function serializeEnvironment(env) {
+  if (UPLOAD_PATH) {
+    return { upload: JSON.stringify(env) };
+  }
+  return {
+    submodels: env.submodels.map((sm) => JSON.stringify(sm)),
+    shell: JSON.stringify(env.assetAdministrationShells[0]),
+  };
+}
+
+// importEnvironment sends the bodies and reports whether every request
+// succeeded.
+function importEnvironment(bodies) {
+  if (UPLOAD_PATH) {
+    const res = http.post(`${BASE_URL}${UPLOAD_PATH}`, {
+      file: http.file(bodies.upload, "environment.json", "application/json"),
+    });
+    return check(res, {
+      "upload status is 2xx": (r) => r.status >= 200 && r.status < 300,
+    });
+  }
+  const params = { headers: { "Content-Type": "application/json" } };
+  let ok = true;
+  for (const body of bodies.submodels) {
+    const res = http.post(`${BASE_URL}/submodels`, body, params);
+    ok = check(res, { "POST /submodels status is 201": (r) => r.status === 201 }) && ok;
+  }
+  const res = http.post(`${BASE_URL}/shells`, bodies.shell, params);
+  return check(res, { "POST /shells status is 201": (r) => r.status === 201 }) && ok;
+}
+
+function deleteEnvironment(env) {
+  for (const shell of env.assetAdministrationShells) {
+    http.del(`${BASE_URL}/shells/${base64UrlEncode(shell.id)}`);
+  }
+  for (const sm of env.submodels) {
+    http.del(`${BASE_URL}/submodels/${base64UrlEncode(sm.id)}`);
+  }
+}
+
+export default function () {
+  const size = SIZES[Math.floor(__ITER / REPEATS)];
+  const env = buildEnvironment(size);
+  const bodies = serializeEnvironment(env);
+
+  const start = Date.now();
+  const ok = importEnvironment(bodies);
+  const elapsed = Date.now() - start;
+  if (ok) {
+    importTrends[size].add(elapsed);
+  } else {
+    importFailures.add(1, { size: String(size) });
+  }
+
+  // Clean up outside the timed window, so every size starts from the
+  // seeded repository.
+  deleteEnvironment(env);
+}
+
+// handleSummary reduces the per-size trends to import times and the
+// throughput per element, at the median import time.
+export function handleSummary(data) {
+  const sizes = SIZES.map((size) => {
+    const values = data.metrics[`bulk_import_ms_${size}`]?.values;
+    if (!values || !values.count) {
+      return { elements: size, imports: 0 };
+    }
+    return {
+      elements: size,
+      imports: values.count,
+      mean_ms: values.avg,
+      median_ms: values.med,
+      p95_ms: values["p(95)"],
+      min_ms: values.min,
+      max_ms: values.max,
+      elements_per_sec: values.med > 0 ? size / (values.med / 1000) : null,
+    };
+  });
+  const result = {
+    mode: UPLOAD_PATH ? "upload" : "post",
+    upload_path: UPLOAD_PATH || undefined,
+    elements_per_submodel: ELEMENTS_PER_SUBMODEL,
+    failures: data.metrics.bulk_import_failures?.values?.count || 0,
+    sizes: sizes,
+  };
+  return {
+    [`${OUTPUT_DIR}/k6_bulk_${SDK_ID}.json`]: JSON.stringify(result),
+  };
+}
@@ -1,4 +1,4 @@
-import http from "k6/http";
+import http, { authOptions } from "./lib/http.js";
 import { check, group } from "k6";
 import encoding from "k6/encoding";
 
@@ -7,6 +7,7 @@ const SDK_ID = __ENV.SDK_ID;
 const OUTPUT_DIR = __ENV.OUTPUT_DIR;
 
 export const options = {
+  ...authOptions,
   vus: 5,
   iterations: 100,
 };
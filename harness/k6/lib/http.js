@@ -0,0 +1,106 @@
+// k6/http with the server's authentication added to every request, so the
+// benchmark scripts work against secured deployments unchanged. The mode
+// comes from AUTH_MODE:
+//
+//   none     anonymous requests (the default)
+//   api_key  a static key in the AUTH_HEADER header (default X-API-Key),
+//            read from AUTH_API_KEY
+//   oauth2   a bearer token from the client credentials flow against
+//            AUTH_TOKEN_URL with AUTH_CLIENT_ID, AUTH_CLIENT_SECRET and the
+//            optional AUTH_SCOPE, refreshed before it expires
+//   mtls     a client certificate (AUTH_CERT and AUTH_KEY paths); scripts
+//            spread authOptions into their options for it
+import k6http from "k6/http";
+
+export const AUTH_MODE = __ENV.AUTH_MODE || "none";
+
+const API_KEY_HEADER = __ENV.AUTH_HEADER || "X-API-Key";
+// Refresh a token this long before it expires, so that no request carries
+// one that lapses in flight.
+const REFRESH_MARGIN_MS = 30 * 1000;
+
+// k6 reads certificate files in the init context only.
+const tlsAuth =
+  AUTH_MODE === "mtls"
+    ? [{ cert: open(__ENV.AUTH_CERT), key: open(__ENV.AUTH_KEY) }]
+    : undefined;
+
+// authOptions holds the k6 options an auth mode needs.
+export const authOptions = tlsAuth ? { tlsAuth: tlsAuth } : {};
+
+// Each VU keeps its own token.
+let token = null;
+let tokenExpiresAt = 0;
+
+// fetchToken runs the client credentials flow and returns the response
+// time in ms. The token request itself is not authenticated.
+export function fetchToken() {
+  const body = {
+    grant_type: "client_credentials",
+    client_id: __ENV.AUTH_CLIENT_ID,
+    client_secret: __ENV.AUTH_CLIENT_SECRET,
+  };
+  if (__ENV.AUTH_SCOPE) {
+    body.scope = __ENV.AUTH_SCOPE;
+  }
+  const res = k6http.post(__ENV.AUTH_TOKEN_URL, body, { tags: { name: "auth token" } });
+  if (res.status !== 200) {
+    throw new Error(`token request to ${__ENV.AUTH_TOKEN_URL} failed: HTTP ${res.status}`);
+  }
+  const payload = res.json();
+  token = payload.access_token;
+  tokenExpiresAt = Date.now() + (payload.expires_in || 300) * 1000;
+  return res.timings.duration;
+}
+
+// authHeaders returns the headers that authenticate one request.
+export function authHeaders() {
+  switch (AUTH_MODE) {
+    case "api_key":
+      return { [API_KEY_HEADER]: __ENV.AUTH_API_KEY };
+    case "oauth2":
+      if (!token || Date.now() > tokenExpiresAt - REFRESH_MARGIN_MS) {
+        fetchToken();
+      }
+      return { Authorization: `Bearer ${token}` };
+    default:
+      return {};
+  }
+}
+
+function withAuth(params) {
+  const p = Object.assign({}, params);
+  p.headers = Object.assign({}, authHeaders(), p.headers);
+  return p;
+}
+
+export function get(url, params) {
+  return k6http.get(url, withAuth(params));
+}
+
+export function post(url, body, params) {
+  return k6http.post(url, body, withAuth(params));
+}
+
+export function put(url, body, params) {
+  return k6http.put(url, body, withAuth(params));
+}
+
+export function patch(url, body, params) {
+  return k6http.patch(url, body, withAuth(params));
+}
+
+export function del(url, body, params) {
+  return k6http.del(url, body, withAuth(params));
+}
+
+// batch takes the [method, url, body, params] array form.
+export function batch(requests) {
+  return k6http.batch(
+    requests.map(([method, url, body, params]) => [method, url, body || null, withAuth(params)])
+  );
+}
+
+export const file = k6http.file;
+
+export default { get, post, put, patch, del, batch, file };
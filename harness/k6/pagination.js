@@ -0,0 +1,143 @@
+import http, { authOptions } from "./lib/http.js";
+import { check } from "k6";
+import encoding from "k6/encoding";
+import { Trend } from "k6/metrics";
+
+const BASE_URL = __ENV.BASE_URL;
+const SDK_ID = __ENV.SDK_ID;
+const OUTPUT_DIR = __ENV.OUTPUT_DIR;
+const ITEMS = parseInt(__ENV.PAGINATION_ITEMS || "5000", 10);
+const REPEATS = parseInt(__ENV.PAGINATION_REPEATS || "3", 10);
+
+const PAGE_SIZES = [10, 100, 1000];
+const ID_PREFIX = "urn:example:paging:sm";
+
+// Per page size: whole traversals, every page, and the pages of the first
+// and last quarter of a traversal, whose medians show whether a server's
+// pages get slower with the offset.
+const trends = {};
+for (const size of PAGE_SIZES) {
+  trends[size] = {
+    traversal: new Trend(`pagination_traversal_ms_${size}`, true),
+    page: new Trend(`pagination_page_ms_${size}`, true),
+    first: new Trend(`pagination_first_quarter_ms_${size}`, true),
+    last: new Trend(`pagination_last_quarter_ms_${size}`, true),
+  };
+}
+
+export const options = {
+  ...authOptions,
+  summaryTrendStats: ["avg", "min", "med", "max", "p(95)", "p(99)", "count"],
+  setupTimeout: "10m",
+  teardownTimeout: "10m",
+  scenarios: {
+    pagination: {
+      executor: "per-vu-iterations",
+      vus: 1,
+      iterations: PAGE_SIZES.length * REPEATS,
+      maxDuration: "30m",
+    },
+  },
+};
+
+function base64UrlEncode(id) {
+  return encoding.b64encode(id, "rawurl");
+}
+
+// setup fills the repository with ITEMS small submodels.
+export function setup() {
+  const params = { headers: { "Content-Type": "application/json" } };
+  for (let i = 0; i < ITEMS; i++) {
+    const res = http.post(
+      `${BASE_URL}/submodels`,
+      JSON.stringify({ id: `${ID_PREFIX}:${i}`, idShort: `PagingSubmodel${i}`, modelType: "Submodel" }),
+      params
+    );
+    check(res, { "seed POST /submodels status is 201": (r) => r.status === 201 });
+  }
+}
+
+export default function () {
+  const size = PAGE_SIZES[Math.floor(__ITER / REPEATS)];
+  const t = trends[size];
+  // A server that ignores limit answers in one page; the bound only stops
+  // one that keeps handing out cursors.
+  const expectedPages = Math.ceil(ITEMS / size);
+  const maxPages = expectedPages * 2 + 10;
+
+  const latencies = [];
+  let cursor = null;
+  const start = Date.now();
+  while (latencies.length < maxPages) {
+    let url = `${BASE_URL}/submodels?limit=${size}`;
+    if (cursor) {
+      url += `&cursor=${encodeURIComponent(cursor)}`;
+    }
+    const res = http.get(url);
+    if (!check(res, { "GET /submodels page status is 200": (r) => r.status === 200 })) {
+      return;
+    }
+    latencies.push(res.timings.duration);
+    const body = res.json();
+    cursor = body?.paging_metadata?.cursor;
+    if (!cursor || !Array.isArray(body.result) || body.result.length === 0) {
+      break;
+    }
+  }
+  t.traversal.add(Date.now() - start);
+
+  const quarter = Math.max(1, Math.floor(latencies.length / 4));
+  latencies.forEach((ms, i) => {
+    t.page.add(ms);
+    if (i < quarter) {
+      t.first.add(ms);
+    }
+    if (i >= latencies.length - quarter) {
+      t.last.add(ms);
+    }
+  });
+}
+
+export function teardown() {
+  for (let i = 0; i < ITEMS; i++) {
+    http.del(`${BASE_URL}/submodels/${base64UrlEncode(`${ID_PREFIX}:${i}`)}`);
+  }
+}
+
+// handleSummary reduces the trends to the traversal time and page latency
+// distribution per page size. depth_slowdown is the median latency of the
+// last quarter of pages over that of the first quarter: near 1 for a
+// server whose pagination does not degrade with the offset.
+export function handleSummary(data) {
+  const values = (name) => data.metrics[name]?.values;
+  const sizes = PAGE_SIZES.map((size) => {
+    const traversal = values(`pagination_traversal_ms_${size}`);
+    const page = values(`pagination_page_ms_${size}`);
+    if (!traversal || !traversal.count || !page) {
+      return { page_size: size, traversals: 0 };
+    }
+    const first = values(`pagination_first_quarter_ms_${size}`);
+    const last = values(`pagination_last_quarter_ms_${size}`);
+    return {
+      page_size: size,
+      traversals: traversal.count,
+      pages_per_traversal: page.count / traversal.count,
+      traversal_median_ms: traversal.med,
+      traversal_max_ms: traversal.max,
+      page_ms: {
+        mean: page.avg,
+        median: page.med,
+        p95: page["p(95)"],
+        p99: page["p(99)"],
+        min: page.min,
+        max: page.max,
+      },
+      first_quarter_median_ms: first?.med ?? null,
+      last_quarter_median_ms: last?.med ?? null,
+      depth_slowdown: first?.med > 0 && last ? last.med / first.med : null,
+    };
+  });
+  return {
+    [`${OUTPUT_DIR}/k6_pagination_${SDK_ID}.json`]: JSON.stringify({ items: ITEMS, sizes: sizes }),
+  };
+}
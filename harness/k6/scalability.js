@@ -0,0 +1,116 @@
+import http, { authOptions } from "./lib/http.js";
+import { check } from "k6";
+import encoding from "k6/encoding";
+import { Counter, Trend } from "k6/metrics";
+
+const BASE_URL = __ENV.BASE_URL;
+const SDK_ID = __ENV.SDK_ID;
+const OUTPUT_DIR = __ENV.OUTPUT_DIR;
+const LEVEL_DURATION_S = parseInt(__ENV.SCALABILITY_LEVEL_S || "20", 10);
+// Idle time between levels, so that one level's backlog does not spill
+// into the next.
+const PAUSE_S = 5;
+// A level saturates the server when it adds less than this fraction of
+// throughput over the level below.
+const SATURATION_GAIN = 0.1;
+
+const LEVELS = [1, 4, 16, 64, 256];
+
+function base64UrlEncode(id) {
+  return encoding.b64encode(id, "rawurl");
+}
+
+// The endpoints read the data seed-test-data.sh creates.
+const ENDPOINTS = {
+  shells: "/shells",
+  submodels: "/submodels",
+  shell: `/shells/${base64UrlEncode("urn:example:aas:test-1")}`,
+  submodel: `/submodels/${base64UrlEncode("urn:example:submodel:test-1")}`,
+};
+
+const trends = {};
+const errors = {};
+const scenarios = {};
+let start = 0;
+for (const endpoint of Object.keys(ENDPOINTS)) {
+  for (const clients of LEVELS) {
+    const key = `${endpoint}_c${clients}`;
+    trends[key] = new Trend(`scalability_ms_${key}`, true);
+    errors[key] = new Counter(`scalability_errors_${key}`);
+    // Closed loop: every client sends its next request as soon as the
+    // last one is answered, so throughput is what the server sustains.
+    scenarios[key] = {
+      executor: "constant-vus",
+      exec: "request",
+      vus: clients,
+      duration: `${LEVEL_DURATION_S}s`,
+      startTime: `${start}s`,
+      gracefulStop: "10s",
+      env: { ENDPOINT: endpoint, CLIENTS: String(clients) },
+    };
+    start += LEVEL_DURATION_S + PAUSE_S;
+  }
+}
+
+export const options = {
+  ...authOptions,
+  summaryTrendStats: ["avg", "med", "p(99)", "max", "count"],
+  scenarios: scenarios,
+};
+
+export function request() {
+  const key = `${__ENV.ENDPOINT}_c${__ENV.CLIENTS}`;
+  const res = http.get(`${BASE_URL}${ENDPOINTS[__ENV.ENDPOINT]}`, {
+    tags: { name: `GET ${__ENV.ENDPOINT}` },
+  });
+  if (check(res, { "scalability request status is 200": (r) => r.status === 200 })) {
+    trends[key].add(res.timings.duration);
+  } else {
+    errors[key].add(1);
+  }
+}
+
+// handleSummary reduces every level to its throughput and latency, and
+// finds each endpoint's saturation point: the last level before one that
+// adds less than SATURATION_GAIN of throughput. It is null when throughput
+// still grew at the highest level.
+export function handleSummary(data) {
+  const endpoints = {};
+  for (const endpoint of Object.keys(ENDPOINTS)) {
+    const levels = LEVELS.map((clients) => {
+      const key = `${endpoint}_c${clients}`;
+      const v = data.metrics[`scalability_ms_${key}`]?.values;
+      const requests = v?.count || 0;
+      return {
+        clients: clients,
+        requests: requests,
+        errors: data.metrics[`scalability_errors_${key}`]?.values?.count || 0,
+        throughput_rps: requests / LEVEL_DURATION_S,
+        p50_ms: v?.med ?? null,
+        p99_ms: v?.["p(99)"] ?? null,
+        max_ms: v?.max ?? null,
+      };
+    });
+    let saturation = null;
+    for (let i = 1; i < levels.length; i++) {
+      if (levels[i].throughput_rps < levels[i - 1].throughput_rps * (1 + SATURATION_GAIN)) {
+        saturation = levels[i - 1].clients;
+        break;
+      }
+    }
+    endpoints[endpoint] = {
+      path: ENDPOINTS[endpoint],
+      levels: levels,
+      peak_throughput_rps: Math.max(...levels.map((l) => l.throughput_rps)),
+      saturation_clients: saturation,
+    };
+  }
+  const result = {
+    level_duration_s: LEVEL_DURATION_S,
+    saturation_gain: SATURATION_GAIN,
+    endpoints: endpoints,
+  };
+  return {
+    [`${OUTPUT_DIR}/k6_scalability_${SDK_ID}.json`]: JSON.stringify(result),
+  };
+}
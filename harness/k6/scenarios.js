@@ -1,4 +1,4 @@
-import http from "k6/http";
+import http, { authOptions } from "./lib/http.js";
 import { check, sleep } from "k6";
 
 const BASE_URL = __ENV.BASE_URL;
@@ -33,6 +33,7 @@ const allScenarios = {
 
 // If SCENARIO env var is set, run only that scenario; otherwise run all
 export const options = {
+  ...authOptions,
   scenarios: SCENARIO
     ? { [SCENARIO]: allScenarios[SCENARIO] }
     : allScenarios,
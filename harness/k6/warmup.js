@@ -0,0 +1,134 @@
+import http, { authOptions } from "./lib/http.js";
+import { check, sleep } from "k6";
+import encoding from "k6/encoding";
+import { Gauge } from "k6/metrics";
+
+const BASE_URL = __ENV.BASE_URL;
+const SDK_ID = __ENV.SDK_ID;
+const OUTPUT_DIR = __ENV.OUTPUT_DIR;
+const HEALTH_URL = __ENV.HEALTH_URL || `${BASE_URL}/shells`;
+const HEALTH_STATUS = parseInt(__ENV.HEALTH_STATUS || "200", 10);
+const HEALTH_TIMEOUT_S = parseInt(__ENV.HEALTH_TIMEOUT_S || "180", 10);
+// Warm-up ends once the coefficient of variation of the last WARMUP_WINDOW
+// round latencies is below WARMUP_CV, or after WARMUP_MAX_S regardless.
+const WINDOW = parseInt(__ENV.WARMUP_WINDOW || "20", 10);
+const CV_THRESHOLD = parseFloat(__ENV.WARMUP_CV || "0.1");
+const MAX_S = parseInt(__ENV.WARMUP_MAX_S || "300", 10);
+
+// The data seed-test-data.sh creates, read by every warm-up round.
+const SHELL_ID = "urn:example:aas:test-1";
+const SUBMODEL_ID = "urn:example:submodel:test-1";
+
+const readyAfter = new Gauge("warmup_ready_after_s");
+const warmupDuration = new Gauge("warmup_duration_s");
+const warmupRounds = new Gauge("warmup_rounds");
+const finalCV = new Gauge("warmup_cv");
+const stable = new Gauge("warmup_stable");
+
+export const options = {
+  ...authOptions,
+  scenarios: {
+    warmup: {
+      executor: "per-vu-iterations",
+      vus: 1,
+      iterations: 1,
+      maxDuration: `${HEALTH_TIMEOUT_S + MAX_S + 60}s`,
+    },
+  },
+};
+
+function base64UrlEncode(id) {
+  return encoding.b64encode(id, "rawurl");
+}
+
+// waitForHealth polls HEALTH_URL once a second and returns the seconds it
+// took to answer with HEALTH_STATUS, or -1 on timeout.
+function waitForHealth() {
+  const start = Date.now();
+  while (Date.now() - start < HEALTH_TIMEOUT_S * 1000) {
+    const res = http.get(HEALTH_URL, { tags: { name: "health" } });
+    if (res.status === HEALTH_STATUS) {
+      return (Date.now() - start) / 1000;
+    }
+    sleep(1);
+  }
+  return -1;
+}
+
+// round sends the standard warm-up mix, the reads the timed benchmarks
+// exercise, and returns its total latency in ms.
+function round() {
+  const shell = base64UrlEncode(SHELL_ID);
+  const submodel = base64UrlEncode(SUBMODEL_ID);
+  const responses = http.batch([
+    ["GET", `${BASE_URL}/shells`],
+    ["GET", `${BASE_URL}/submodels`],
+    ["GET", `${BASE_URL}/shells/${shell}`],
+    ["GET", `${BASE_URL}/submodels/${submodel}`],
+    ["GET", `${BASE_URL}/submodels/${submodel}/submodel-elements/TestProperty`],
+  ]);
+  let total = 0;
+  for (const res of responses) {
+    check(res, { "warm-up request status is 200": (r) => r.status === 200 });
+    total += res.timings.duration;
+  }
+  return total;
+}
+
+function coefficientOfVariation(values) {
+  const mean = values.reduce((a, b) => a + b, 0) / values.length;
+  if (mean <= 0) {
+    return Infinity;
+  }
+  const variance = values.reduce((a, b) => a + (b - mean) * (b - mean), 0) / values.length;
+  return Math.sqrt(variance) / mean;
+}
+
+export default function () {
+  const ready = waitForHealth();
+  readyAfter.add(ready);
+  if (ready < 0) {
+    stable.add(0);
+    return;
+  }
+
+  const start = Date.now();
+  const latencies = [];
+  let cv = Infinity;
+  while (Date.now() - start < MAX_S * 1000) {
+    latencies.push(round());
+    if (latencies.length >= WINDOW) {
+      cv = coefficientOfVariation(latencies.slice(-WINDOW));
+      if (cv < CV_THRESHOLD) {
+        break;
+      }
+    }
+  }
+  warmupDuration.add((Date.now() - start) / 1000);
+  warmupRounds.add(latencies.length);
+  finalCV.add(Number.isFinite(cv) ? cv : -1);
+  stable.add(cv < CV_THRESHOLD ? 1 : 0);
+}
+
+// handleSummary writes the warm-up metadata. stable is false when the
+// server never became healthy or its latency never settled within
+// WARMUP_MAX_S; the timed benchmarks still run, but their numbers carry
+// the warm-up.
+export function handleSummary(data) {
+  const gauge = (name) => data.metrics[name]?.values?.value;
+  const ready = gauge("warmup_ready_after_s");
+  const cv = gauge("warmup_cv");
+  const result = {
+    healthy: ready != null && ready >= 0,
+    ready_after_s: ready != null && ready >= 0 ? ready : null,
+    warmup_s: gauge("warmup_duration_s") ?? null,
+    rounds: gauge("warmup_rounds") ?? 0,
+    window: WINDOW,
+    cv_threshold: CV_THRESHOLD,
+    final_cv: cv != null && cv >= 0 ? cv : null,
+    stable: gauge("warmup_stable") === 1,
+  };
+  return {
+    [`${OUTPUT_DIR}/warmup_${SDK_ID}.json`]: JSON.stringify(result),
+  };
+}
@@ -0,0 +1,169 @@
+#!/usr/bin/env bash
+set -euo pipefail
+
+# Runs the k6 server benchmarks against a server deployed on the current
+# kubectl context instead of the local Docker daemon. The server runs from
+# the adapter's manifest (kubernetes.manifest in sdk.yaml), with its
+# resource requests and limits, in a namespace of its own; k6 runs in a
+# separate pod and reaches it through its Service. While k6 runs, the
+# server pods' usage is sampled from the metrics API (metrics-server) into
+# k8s_metrics_<server_id>.json.
+
+ADAPTER_DIR="${1:?Usage: run.sh <adapter_dir> <output_dir>}"
+OUTPUT_DIR="${2:?Usage: run.sh <adapter_dir> <output_dir>}"
+K6_IMAGE="${K6_IMAGE:-grafana/k6:0.54.0}"
+K6_SCRIPTS="${K6_SCRIPTS:-warmup scenarios crud bulk_import pagination scalability}"
+METRICS_INTERVAL="${METRICS_INTERVAL:-5}"
+ROLLOUT_TIMEOUT="${ROLLOUT_TIMEOUT:-600s}"
+LOCAL_PORT="${LOCAL_PORT:-18080}"
+
+command -v kubectl >/dev/null 2>&1 || { echo "Error: kubectl is required but not installed." >&2; exit 1; }
+command -v yq >/dev/null 2>&1 || { echo "Error: yq is required but not installed." >&2; exit 1; }
+
+HARNESS_DIR="$(cd "$(dirname "$0")/.." && pwd)"
+SDK_YAML="$ADAPTER_DIR/sdk.yaml"
+SDK_ID="$(yq -r '.id' "$SDK_YAML")"
+NAMESPACE="aasbench-$SDK_ID"
+SERVICE="$(yq -r '.kubernetes.service' "$SDK_YAML")"
+PORT="$(yq -r '.kubernetes.port' "$SDK_YAML")"
+BASE_PATH="$(yq -r '.kubernetes.base_path // ""' "$SDK_YAML")"
+HEALTH_PATH="$(yq -r '.kubernetes.health_path // ""' "$SDK_YAML")"
+UPLOAD_PATH="$(yq -r '.bulk_import.upload_path // ""' "$SDK_YAML")"
+CONFIG_MAP="$(yq -r '.kubernetes.config_map // ""' "$SDK_YAML")"
+IN_CLUSTER_URL="http://$SERVICE:$PORT"
+
+mkdir -p "$OUTPUT_DIR"
+k() { kubectl -n "$NAMESPACE" "$@"; }
+
+PORT_FORWARD_PID=""
+cleanup() {
+  [ -n "$PORT_FORWARD_PID" ] && kill "$PORT_FORWARD_PID" 2>/dev/null || true
+  kubectl delete namespace "$NAMESPACE" --wait=false >/dev/null 2>&1 || true
+}
+trap cleanup EXIT
+
+echo "Deploying $SDK_ID to namespace $NAMESPACE ..."
+kubectl create namespace "$NAMESPACE"
+if [ -n "$CONFIG_MAP" ]; then
+  FROM_FILES=()
+  while IFS= read -r file; do
+    FROM_FILES+=("--from-file=$ADAPTER_DIR/$file")
+  done < <(yq -r '.kubernetes.config_files[]' "$SDK_YAML")
+  k create configmap "$CONFIG_MAP" "${FROM_FILES[@]}"
+fi
+k apply -f "$ADAPTER_DIR/$(yq -r '.kubernetes.manifest' "$SDK_YAML")"
+
+# Start the digests the run pinned (scripts/pin_images.py); deployments are
+# named after the compose services they stand in for.
+if [ -f "$OUTPUT_DIR/server_matrix.json" ]; then
+  jq -r '.services | to_entries[] | "\(.key) \(.value.pinned)"' "$OUTPUT_DIR/server_matrix.json" \
+    | while read -r name image; do
+        if k get deployment "$name" >/dev/null 2>&1; then
+          k set image "deployment/$name" "$name=$image"
+        fi
+      done
+fi
+k wait --for=condition=available deployment --all --timeout="$ROLLOUT_TIMEOUT"
+
+# Seed through a port-forward; the benchmarks themselves run in-cluster.
+k port-forward "service/$SERVICE" "$LOCAL_PORT:$PORT" >/dev/null &
+PORT_FORWARD_PID=$!
+bash "$HARNESS_DIR/wait-for-health.sh" "http://localhost:$LOCAL_PORT$HEALTH_PATH" 60
+bash "$HARNESS_DIR/seed-test-data.sh" "http://localhost:$LOCAL_PORT$BASE_PATH"
+kill "$PORT_FORWARD_PID"
+PORT_FORWARD_PID=""
+
+echo "Starting load generator ($K6_IMAGE): $K6_SCRIPTS"
+k create configmap k6-scripts --from-file="$HARNESS_DIR/k6"
+k create configmap k6-lib --from-file="$HARNESS_DIR/k6/lib"
+# The auth secrets of harness/k6/lib/http.js; mtls is not supported here.
+k create secret generic k6-auth \
+  --from-literal=AUTH_MODE="${AUTH_MODE:-none}" \
+  --from-literal=AUTH_HEADER="${AUTH_HEADER:-X-API-Key}" \
+  --from-literal=AUTH_API_KEY="${AUTH_API_KEY:-}" \
+  --from-literal=AUTH_TOKEN_URL="${AUTH_TOKEN_URL:-}" \
+  --from-literal=AUTH_CLIENT_ID="${AUTH_CLIENT_ID:-}" \
+  --from-literal=AUTH_CLIENT_SECRET="${AUTH_CLIENT_SECRET:-}" \
+  --from-literal=AUTH_SCOPE="${AUTH_SCOPE:-}"
+k apply -f - <<EOF
+apiVersion: v1
+kind: Pod
+metadata:
+  name: loadgen
+  labels:
+    aasbench/role: loadgen
+spec:
+  restartPolicy: Never
+  affinity:
+    # Keep k6 off the server's node where the cluster has another one.
+    podAntiAffinity:
+      preferredDuringSchedulingIgnoredDuringExecution:
+        - weight: 100
+          podAffinityTerm:
+            topologyKey: kubernetes.io/hostname
+            labelSelector:
+              matchLabels:
+                aasbench/role: server
+  containers:
+    - name: k6
+      image: $K6_IMAGE
+      command: ["sh", "-c"]
+      args:
+        - |
+          status=0
+          for script in $K6_SCRIPTS; do
+            k6 run "/scripts/\$script.js" || status=1
+          done
+          echo "\$status" > /results/.exit
+          sleep 3600
+      env:
+        - {name: BASE_URL, value: "$IN_CLUSTER_URL$BASE_PATH"}
+        - {name: HEALTH_URL, value: "$IN_CLUSTER_URL$HEALTH_PATH"}
+        - {name: UPLOAD_PATH, value: "$UPLOAD_PATH"}
+        - {name: SDK_ID, value: "$SDK_ID"}
+        - {name: OUTPUT_DIR, value: /results}
+      envFrom:
+        - secretRef: {name: k6-auth}
+      volumeMounts:
+        - {name: scripts, mountPath: /scripts}
+        - {name: lib, mountPath: /scripts/lib}
+        - {name: results, mountPath: /results}
+  volumes:
+    - name: scripts
+      configMap:
+        name: k6-scripts
+    - name: lib
+      configMap:
+        name: k6-lib
+    - name: results
+      emptyDir: {}
+EOF
+k wait --for=condition=Ready pod/loadgen --timeout=300s
+
+# Sample the server pods until k6 is done. A cluster without metrics-server
+# still runs the benchmarks, with no samples.
+SAMPLES="$(mktemp)"
+METRICS_PATH="/apis/metrics.k8s.io/v1beta1/namespaces/$NAMESPACE/pods?labelSelector=aasbench%2Frole%3Dserver"
+until k exec loadgen -- test -f /results/.exit 2>/dev/null; do
+  if [ "$(k get pod loadgen -o jsonpath='{.status.phase}')" != "Running" ]; then
+    echo "Load generator stopped before finishing" >&2
+    break
+  fi
+  kubectl get --raw "$METRICS_PATH" 2>/dev/null | jq -c '{items: .items}' >> "$SAMPLES" || true
+  sleep "$METRICS_INTERVAL"
+done
+
+k logs loadgen > "$OUTPUT_DIR/k6_kubernetes.log" || true
+k exec loadgen -- tar cf - -C /results . | tar xf - -C "$OUTPUT_DIR"
+STATUS="$(cat "$OUTPUT_DIR/.exit" 2>/dev/null || echo 1)"
+rm -f "$OUTPUT_DIR/.exit"
+
+k get pods -l aasbench/role=server -o json > "$SAMPLES.pods"
+python3 "$HARNESS_DIR/k8s/summarize_metrics.py" \
+  --samples "$SAMPLES" --pods "$SAMPLES.pods" \
+  --namespace "$NAMESPACE" --interval "$METRICS_INTERVAL" \
+  > "$OUTPUT_DIR/k8s_metrics_$SDK_ID.json"
+rm -f "$SAMPLES" "$SAMPLES.pods"
+
+echo "Kubernetes run of $SDK_ID finished (k6 status $STATUS)"
+exit "$STATUS"
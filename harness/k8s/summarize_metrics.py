@@ -0,0 +1,96 @@
+#!/usr/bin/env python3
+"""Summarize metrics API samples of the server pods into k8s_metrics_<id>.json.
+
+Input is one JSON line per sample, each holding the items of a
+metrics.k8s.io PodMetricsList, plus the pods' JSON for their resource
+requests and limits. Containers are keyed by name, which harness/k8s/run.sh
+keeps equal to the compose service names, since pod names change per run.
+"""
+
+import argparse
+import json
+import sys
+
+CPU_UNITS = {"n": 1e-6, "u": 1e-3, "m": 1.0}
+MEMORY_UNITS = {
+    "Ki": 1024, "Mi": 1024**2, "Gi": 1024**3, "Ti": 1024**4,
+    "k": 1000, "M": 1000**2, "G": 1000**3, "T": 1000**4,
+}
+
+
+def cpu_millicores(quantity: str) -> float:
+    """Convert a Kubernetes CPU quantity ("250m", "123456n", "2") to millicores."""
+    unit = quantity[-1]
+    if unit in CPU_UNITS:
+        return float(quantity[:-1]) * CPU_UNITS[unit]
+    return float(quantity) * 1000
+
+
+def memory_mib(quantity: str) -> float:
+    """Convert a Kubernetes memory quantity ("512Mi", "123456Ki") to MiB."""
+    for suffix in sorted(MEMORY_UNITS, key=len, reverse=True):
+        if quantity.endswith(suffix):
+            return float(quantity[: -len(suffix)]) * MEMORY_UNITS[suffix] / 1024**2
+    return float(quantity) / 1024**2
+
+
+def stats(values: list[float]) -> dict:
+    return {"mean": sum(values) / len(values), "max": max(values)}
+
+
+def summarize(samples: list[dict], pods: dict, namespace: str, interval: float) -> dict:
+    cpu: dict[str, list[float]] = {}
+    memory: dict[str, list[float]] = {}
+    for sample in samples:
+        for pod in sample.get("items", []):
+            for container in pod.get("containers", []):
+                usage = container.get("usage", {})
+                name = container["name"]
+                if "cpu" in usage:
+                    cpu.setdefault(name, []).append(cpu_millicores(usage["cpu"]))
+                if "memory" in usage:
+                    memory.setdefault(name, []).append(memory_mib(usage["memory"]))
+
+    containers: dict[str, dict] = {}
+    for pod in pods.get("items", []):
+        for container in pod.get("spec", {}).get("containers", []):
+            resources = container.get("resources", {})
+            containers[container["name"]] = {
+                "image": container.get("image"),
+                "requests": resources.get("requests", {}),
+                "limits": resources.get("limits", {}),
+            }
+    for name in set(cpu) | set(memory):
+        entry = containers.setdefault(name, {})
+        if name in cpu:
+            entry["cpu_millicores"] = stats(cpu[name])
+        if name in memory:
+            entry["memory_mib"] = stats(memory[name])
+
+    return {
+        "backend": "kubernetes",
+        "namespace": namespace,
+        "interval_s": interval,
+        "samples": len(samples),
+        "containers": containers,
+    }
+
+
+def main():
+    parser = argparse.ArgumentParser(description=__doc__)
+    parser.add_argument("--samples", required=True, help="JSON lines of metrics API samples")
+    parser.add_argument("--pods", required=True, help="kubectl get pods -o json of the server pods")
+    parser.add_argument("--namespace", required=True)
+    parser.add_argument("--interval", type=float, required=True, help="Sampling interval in seconds")
+    args = parser.parse_args()
+
+    with open(args.samples) as f:
+        samples = [json.loads(line) for line in f if line.strip()]
+    with open(args.pods) as f:
+        pods = json.load(f)
+    json.dump(summarize(samples, pods, args.namespace, args.interval), sys.stdout, indent=2)
+    print()
+
+
+if __name__ == "__main__":
+    main()
@@ -0,0 +1,196 @@
+#!/usr/bin/env python3
+"""Benchmark a server's OPC UA endpoint: browse, bulk reads and subscriptions.
+
+Browse walks the Objects folder down to every node, REPEATS times. The
+bulk read reads the values of all Variables found, BATCH_SIZE per Read
+request. The subscription monitors the Value of the seeded TestProperty
+while its value is set through the HTTP API, so its latency runs from
+sending the PATCH to the data change notification. The result is
+written to <output_dir>/opcua_<server_id>.json under the opcua track.
+"""
+
+import argparse
+import asyncio
+import base64
+import json
+import statistics
+import sys
+import time
+import urllib.error
+import urllib.request
+import uuid
+from pathlib import Path
+
+from asyncua import Client, ua
+
+# The element seed-test-data.sh creates.
+SUBMODEL_ID = "urn:example:submodel:test-1"
+ID_SHORT_PATH = "TestProperty"
+
+
+def summary(values_ms: list[float]) -> dict | None:
+    if not values_ms:
+        return None
+    result = {
+        "mean": statistics.fmean(values_ms),
+        "median": statistics.median(values_ms),
+        "min": min(values_ms),
+        "max": max(values_ms),
+    }
+    if len(values_ms) >= 2:
+        result["p95"] = statistics.quantiles(values_ms, n=100)[94]
+    return result
+
+
+async def browse_all(node) -> tuple[list, list]:
+    """Return every node below node and the Variables among them.
+
+    Organizes references can lead back to a node, so each is visited once.
+    """
+    nodes, variables = [], []
+    seen = {node.nodeid}
+    queue = [node]
+    while queue:
+        children = await queue.pop().get_children()
+        for child in children:
+            if child.nodeid in seen:
+                continue
+            seen.add(child.nodeid)
+            nodes.append(child)
+            if await child.read_node_class() == ua.NodeClass.Variable:
+                variables.append(child)
+            queue.append(child)
+    return nodes, variables
+
+
+async def find_value_node(variables: list):
+    """Return the Value Variable of the seeded TestProperty, if exposed."""
+    for var in variables:
+        name = await var.read_browse_name()
+        if name.Name != "Value":
+            continue
+        parent = await var.get_parent()
+        if parent is not None and (await parent.read_browse_name()).Name == ID_SHORT_PATH:
+            return var
+    return None
+
+
+def patch_value(url: str, value: str) -> bool:
+    req = urllib.request.Request(
+        url, data=json.dumps(value).encode(), method="PATCH",
+        headers={"Content-Type": "application/json"},
+    )
+    try:
+        with urllib.request.urlopen(req, timeout=30) as resp:
+            return 200 <= resp.status < 300
+    except urllib.error.URLError as exc:
+        print(f"  WARNING: PATCH failed: {exc}", file=sys.stderr)
+        return False
+
+
+class ChangeHandler:
+    """Records when each update's value arrives, keyed by update index."""
+
+    def __init__(self, token: str):
+        self.prefix = token + "-"
+        self.received: dict[int, float] = {}
+
+    def datachange_notification(self, node, val, data):
+        now = time.perf_counter()
+        if isinstance(val, str) and val.startswith(self.prefix):
+            self.received.setdefault(int(val[len(self.prefix):]), now)
+
+
+async def measure_subscription(client, node, args) -> dict:
+    submodel = base64.urlsafe_b64encode(SUBMODEL_ID.encode()).decode().rstrip("=")
+    url = f"{args.base_url}/submodels/{submodel}/submodel-elements/{ID_SHORT_PATH}/$value"
+    token = f"aasbench-{uuid.uuid4().hex[:8]}"
+    handler = ChangeHandler(token)
+    subscription = await client.create_subscription(args.publishing_interval, handler)
+    await subscription.subscribe_data_change(node)
+
+    sent: dict[int, float] = {}
+    for i in range(args.updates):
+        start = time.perf_counter()
+        ok = await asyncio.to_thread(patch_value, url, f"{token}-{i}")
+        if ok:
+            sent[i] = start
+        # One update per publishing interval, so that none is coalesced.
+        await asyncio.sleep(max(args.publishing_interval / 1000 * 2, 0.02))
+    deadline = time.perf_counter() + args.timeout
+    while time.perf_counter() < deadline and not all(i in handler.received for i in sent):
+        await asyncio.sleep(0.05)
+    await subscription.delete()
+
+    latencies = [(handler.received[i] - sent[i]) * 1000 for i in sent if i in handler.received]
+    lost = len(sent) - len(latencies)
+    return {
+        "publishing_interval_ms": args.publishing_interval,
+        "updates": len(sent),
+        "received": len(latencies),
+        "lost": lost,
+        "loss_rate": lost / len(sent) if sent else None,
+        "latency_ms": summary(latencies),
+    }
+
+
+async def run(args) -> dict:
+    async with Client(url=args.endpoint, timeout=30) as client:
+        browse_ms = []
+        for _ in range(args.repeats):
+            start = time.perf_counter()
+            nodes, variables = await browse_all(client.nodes.objects)
+            browse_ms.append((time.perf_counter() - start) * 1000)
+        print(f"Browsed {len(nodes)} nodes, {len(variables)} variables")
+
+        read_ms = []
+        for _ in range(args.repeats):
+            start = time.perf_counter()
+            for i in range(0, len(variables), args.batch_size):
+                await client.read_values(variables[i:i + args.batch_size])
+            read_ms.append((time.perf_counter() - start) * 1000)
+        read = summary(read_ms)
+        if read and read["median"] > 0:
+            read["values_per_sec"] = len(variables) / (read["median"] / 1000)
+
+        value_node = await find_value_node(variables)
+        subscription = None
+        if value_node is None:
+            print(f"  WARNING: no Value node under {ID_SHORT_PATH}; skipping the subscription", file=sys.stderr)
+        else:
+            subscription = await measure_subscription(client, value_node, args)
+
+    return {
+        "track": "opcua",
+        "endpoint": args.endpoint,
+        "nodes": len(nodes),
+        "variables": len(variables),
+        "repeats": args.repeats,
+        "browse_ms": summary(browse_ms),
+        "read": {"batch_size": args.batch_size, "ms": read},
+        "subscription": subscription,
+    }
+
+
+def main():
+    parser = argparse.ArgumentParser(description=__doc__)
+    parser.add_argument("--endpoint", required=True, help="opc.tcp:// URL of the server")
+    parser.add_argument("--base-url", required=True, help="Server HTTP API base URL, for the updates")
+    parser.add_argument("--repeats", type=int, default=5, help="Browses and bulk reads (default: 5)")
+    parser.add_argument("--batch-size", type=int, default=500, help="Nodes per Read request (default: 500)")
+    parser.add_argument("--updates", type=int, default=200, help="Subscription updates (default: 200)")
+    parser.add_argument("--publishing-interval", type=float, default=50, help="Subscription interval in ms (default: 50)")
+    parser.add_argument("--timeout", type=float, default=5.0, help="Seconds to wait for late notifications (default: 5)")
+    parser.add_argument("--sdk-id", required=True)
+    parser.add_argument("--output-dir", type=Path, required=True)
+    args = parser.parse_args()
+
+    result = asyncio.run(run(args))
+    args.output_dir.mkdir(parents=True, exist_ok=True)
+    out = args.output_dir / f"opcua_{args.sdk_id}.json"
+    out.write_text(json.dumps(result, indent=2) + "\n", encoding="utf-8")
+    print(f"Wrote {out}")
+
+
+if __name__ == "__main__":
+    main()
@@ -6,6 +6,9 @@ set -euo pipefail
 
 API_BASE="${1:?Usage: seed-test-data.sh <api_base_url>}"
 
+# shellcheck source=auth.sh
+source "$(dirname "$0")/auth.sh"
+
 echo "Seeding test data at $API_BASE ..."
 
 post_json() {
@@ -15,6 +18,7 @@ post_json() {
 
   HTTP_CODE=$(curl -s -o /tmp/seed-response.txt -w '%{http_code}' \
     -X POST "$url" \
+    "${AUTH_ARGS[@]}" \
     -H "Content-Type: application/json" \
     -d "$data")
 
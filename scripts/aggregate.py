@@ -8,6 +8,10 @@ Tiered detection:
 Regression detection (SRQ-5):
   --previous-results <path>   -> Compare against previous results.json,
                                   flag regressions/improvements with 95% CI.
+
+Platforms:
+  --by-platform               -> Add platform_comparison, the operations each
+                                  SDK ran on several platforms (os/arch).
 """
 
 import argparse
@@ -17,15 +21,32 @@ import re
 from datetime import datetime, timezone
 from pathlib import Path
 
+import metrics_manifest
+
 REPO_ROOT = Path(__file__).resolve().parent.parent
 DEFAULT_RESULTS_DIR = REPO_ROOT / "results"
 DEFAULT_OUTPUT = REPO_ROOT / "dashboard" / "data" / "results.json"
 DEFAULT_KNOWN_SDKS = REPO_ROOT / "known-sdks.json"
+# The headline metric catalog shared with aasbench (report.MetricCatalog).
+METRIC_CATALOG = REPO_ROOT / "sdks" / "aas-core3-golang" / "report" / "metrics.json"
 
 REGRESSION_THRESHOLD_PCT = 5.0
 Z_95 = 1.96
 CORE_DATASETS = {"wide", "deep", "mixed"}
 CORE_OPERATIONS = {"deserialize", "validate", "traverse", "update", "serialize"}
+VALIDATION_OPERATIONS = {"validate", "validate_first_error", "validate_collect_all"}
+# Datasets of the micro track, benchmarks of single SDK primitives.
+MICRO_DATASET_PREFIX = "micro_"
+# Size ladder datasets of the scaling track (datasets/generate.py --scaling).
+SCALING_DATASET_PREFIX = "scale_"
+# Unicode stress dataset of the i18n track (datasets/generate.py --i18n).
+I18N_DATASET = "i18n"
+# Separates an adapter-owned extension namespace from the operation name
+# ("vendorx:transform"), so extensions never collide with canonical IDs.
+NAMESPACE_SEPARATOR = ":"
+# Platform of the CI runners, assumed for reports without metadata.platform
+# (report.DefaultPlatform).
+DEFAULT_PLATFORM = "linux/amd64"
 
 
 def read_json(path: Path):
@@ -39,6 +60,10 @@ def read_json(path: Path):
 
 def canonical_operation_id(raw_op: str) -> str:
     """Normalize legacy operation names to canonical snake_case operation IDs."""
+    if NAMESPACE_SEPARATOR in raw_op:
+        namespace, name = raw_op.split(NAMESPACE_SEPARATOR, 1)
+        return f"{namespace.lower()}{NAMESPACE_SEPARATOR}{canonical_operation_id(name)}"
+
     explicit = {
         "deserializeXml": "deserialize_xml",
         "serializeXml": "serialize_xml",
@@ -68,11 +93,19 @@ def canonical_operation_id(raw_op: str) -> str:
 
 def infer_operation_track(dataset_name: str, operation_id: str) -> str:
     """Infer operation track for two-track+capability visualization."""
-    if operation_id in {"deserialize_xml", "serialize_xml"}:
+    if NAMESPACE_SEPARATOR in operation_id:
+        return "extension"
+    if operation_id in {"deserialize_xml", "serialize_xml", "deserialize_xml_nsheavy"}:
         return "xml"
     if operation_id in {"aasx_extract", "aasx_repackage"}:
         return "aasx"
-    if dataset_name.startswith("val_") and operation_id == "validate":
+    if dataset_name.startswith(MICRO_DATASET_PREFIX):
+        return "micro"
+    if dataset_name.startswith(SCALING_DATASET_PREFIX):
+        return "scaling"
+    if dataset_name == I18N_DATASET:
+        return "i18n"
+    if dataset_name.startswith("val_") and operation_id in VALIDATION_OPERATIONS:
         return "validation"
     if dataset_name in CORE_DATASETS and operation_id in CORE_OPERATIONS:
         return "core"
@@ -126,9 +159,20 @@ def normalize_pipeline_report(report: dict) -> tuple[dict, dict[str, str]]:
         merged.update(op_name_map)
         report["operation_name_map"] = merged
 
+    # Reports before schema version 3 carry no metrics_manifest; describe
+    # their fields by units.json so every entry of results.json has one.
+    if "metrics_manifest" not in report:
+        report["metrics_manifest"] = metrics_manifest.describe(report)
+
     return report, op_name_map
 
 
+def is_measured(op) -> bool:
+    """Whether an operation entry holds measurements rather than a
+    skipped_missing_dataset or other failure placeholder."""
+    return isinstance(op, dict) and op.get("failure_state", "ok") == "ok"
+
+
 def derive_capabilities(report: dict) -> tuple[dict[str, bool], bool]:
     """Derive capability flags and strict core-track eligibility."""
     capabilities = {
@@ -145,7 +189,7 @@ def derive_capabilities(report: dict) -> tuple[dict[str, bool], bool]:
 
     for ds in CORE_DATASETS:
         ds_ops = datasets.get(ds, {}).get("operations", {})
-        if not isinstance(ds_ops, dict) or not CORE_OPERATIONS.issubset(set(ds_ops.keys())):
+        if not isinstance(ds_ops, dict) or not all(is_measured(ds_ops.get(op)) for op in CORE_OPERATIONS):
             core_track_eligible = False
             break
 
@@ -155,14 +199,17 @@ def derive_capabilities(report: dict) -> tuple[dict[str, bool], bool]:
         ops = ds_data.get("operations", {})
         if not isinstance(ops, dict):
             continue
-        for op_id in ops:
+        for op_id, op in ops.items():
+            if not is_measured(op):
+                # A skipped operation was not run; it proves no capability.
+                continue
             if op_id in CORE_OPERATIONS and ds_name in CORE_DATASETS:
                 capabilities["core"] = True
-            elif op_id in {"deserialize_xml", "serialize_xml"}:
+            elif op_id in {"deserialize_xml", "serialize_xml", "deserialize_xml_nsheavy"}:
                 capabilities["xml"] = True
             elif op_id in {"aasx_extract", "aasx_repackage"}:
                 capabilities["aasx"] = True
-            elif ds_name.startswith("val_") and op_id == "validate":
+            elif ds_name.startswith("val_") and op_id in VALIDATION_OPERATIONS:
                 capabilities["validation"] = True
 
     return capabilities, core_track_eligible
@@ -171,6 +218,65 @@ def derive_capabilities(report: dict) -> tuple[dict[str, bool], bool]:
 # ── SDK (library) benchmarks ────────────────────────────────────────────
 
 
+STAT_LABELS = {
+    "mean_ns": "mean",
+    "median_ns": "median",
+    "p99_ns": "p99",
+    "peak_rss_bytes": "peak RSS",
+    "alloc_bytes_per_op": "alloc/op",
+}
+
+
+def _stat_value(op: dict, stat: str):
+    value = op.get("memory", {}).get(stat) if stat in ("peak_rss_bytes", "alloc_bytes_per_op") else op.get(stat)
+    if isinstance(value, (int, float)) and not isinstance(value, bool) and value > 0:
+        return int(value)
+    return None
+
+
+def headline_value(datasets: dict, dataset: str, operation: str, stat: str):
+    """Mirror of report.HeadlineValue: one operation's stat, or the largest
+    across the dataset when operation is empty."""
+    ops = datasets.get(dataset, {}).get("operations", {})
+    if operation:
+        for key, op in ops.items():
+            if canonical_operation_id(key) == operation:
+                return _stat_value(op, stat)
+        return None
+    values = [v for v in (_stat_value(op, stat) for op in ops.values()) if v is not None]
+    return max(values) if values else None
+
+
+def select_headline(report: dict, catalog: dict) -> list[dict]:
+    """Mirror of MetricCatalog.SelectHeadline: flagged catalog metrics first,
+    then unflagged ones, each resolved to the first stat the report has."""
+    datasets = report.get("datasets", {})
+    picked: list[dict] = []
+    for flagged in (True, False):
+        for metric in catalog.get("metrics", []):
+            if bool(metric.get("headline")) != flagged or len(picked) >= catalog.get("headline_size", 0):
+                continue
+            dataset, operation = metric["dataset"], metric.get("operation", "")
+            for stat in metric["stats"]:
+                value = headline_value(datasets, dataset, operation, stat)
+                if value is None:
+                    continue
+                name = f"{operation}/{dataset}" if operation else dataset
+                entry = {
+                    "id": metric["id"],
+                    "label": f"{name} {STAT_LABELS.get(stat, stat)}",
+                    "dataset": dataset,
+                    "stat": stat,
+                    "unit": "ns" if stat.endswith("_ns") else "bytes",
+                    "value": value,
+                }
+                if operation:
+                    entry["operation"] = operation
+                picked.append(entry)
+                break
+    return picked
+
+
 def _build_sdk_entry(entry: Path, names: dict[str, str]) -> dict | None:
     """Build an SDK benchmark entry from a directory containing report.json."""
     report = read_json(entry / "report.json")
@@ -188,12 +294,26 @@ def _build_sdk_entry(entry: Path, names: dict[str, str]) -> dict | None:
         "name": name,
         "capabilities": capabilities,
         "core_track_eligible": core_track_eligible,
+        "platform": sdk_platform({"pipeline": report}),
     }
 
     env = read_json(entry / "env.json")
     if env:
         result["env"] = env
 
+    # The adapter contract checklist (aasbench contract), when the run
+    # produced one.
+    checklist = read_json(entry / "checklist.json")
+    if checklist:
+        result["contract"] = checklist
+
+    headline = report.get("headline")
+    if not headline:
+        catalog = read_json(METRIC_CATALOG)
+        headline = select_headline(report, catalog) if catalog else []
+    if headline:
+        result["headline"] = headline
+
     # Store the full report (including metadata + datasets) so the dashboard
     # can display language, runtime version, harness, and package version.
     result["pipeline"] = report
@@ -206,6 +326,21 @@ def _build_sdk_entry(entry: Path, names: dict[str, str]) -> dict | None:
 # ── Server benchmarks ───────────────────────────────────────────────────
 
 
+# K6_OUTPUTS maps each benchmarks key of a server entry to the file the k6
+# script of harness/k6 writes it to, or for eventing and opcua, the Python
+# clients of harness/eventing and harness/opcua.
+K6_OUTPUTS = {
+    "scenarios": "k6_summary_{sdk_id}.json",
+    "crud": "k6_crud_{sdk_id}.json",
+    "bulk_import": "k6_bulk_{sdk_id}.json",
+    "pagination": "k6_pagination_{sdk_id}.json",
+    "eventing": "eventing_{sdk_id}.json",
+    "opcua": "opcua_{sdk_id}.json",
+    "latency_histograms": "k6_histograms_{sdk_id}.json",
+    "auth": "k6_auth_{sdk_id}.json",
+}
+
+
 def _build_server_entry(entry: Path, names: dict[str, str]) -> dict | None:
     """Build a server benchmark entry from a directory containing conformance_summary.json."""
     sdk_id = entry.name
@@ -222,19 +357,95 @@ def _build_server_entry(entry: Path, names: dict[str, str]) -> dict | None:
     if conformance is not None:
         result["conformance"] = conformance
 
-    scenarios = read_json(entry / f"k6_summary_{sdk_id}.json")
-    crud = read_json(entry / f"k6_crud_{sdk_id}.json")
-    if scenarios is not None or crud is not None:
-        benchmarks: dict = {}
-        if scenarios is not None:
-            benchmarks["scenarios"] = scenarios
-        if crud is not None:
-            benchmarks["crud"] = crud
+    # server_images.json lists the container images ("repo:tag") the run started.
+    images = read_json(entry / "server_images.json")
+    if images is not None:
+        result["images"] = images.get("images", [])
+
+    # server_matrix.json is this server's slice of the run's pinned matrix:
+    # the digest and version behind every compose service's tag.
+    pins = read_json(entry / "server_matrix.json")
+    if pins is not None and "services" in pins:
+        result["pinned_images"] = pins["services"]
+
+    # warmup_<id>.json is the readiness gate's record: how long the server
+    # took to become healthy and to settle before the timed benchmarks.
+    warmup = read_json(entry / f"warmup_{sdk_id}.json")
+    if warmup is not None:
+        result["warmup"] = warmup
+
+    # k8s_metrics_<id>.json marks a run on the Kubernetes backend and holds
+    # the server containers' resources and sampled usage.
+    kubernetes = read_json(entry / f"k8s_metrics_{sdk_id}.json")
+    if kubernetes is not None:
+        result["kubernetes"] = kubernetes
+
+    # The concurrency sweep is a section of its own: capacity planning reads
+    # it per level, not as one more benchmark number.
+    scalability = read_json(entry / f"k6_scalability_{sdk_id}.json")
+    if scalability is not None:
+        result["scalability"] = scalability
+
+    benchmarks: dict = {}
+    for key, pattern in K6_OUTPUTS.items():
+        data = read_json(entry / pattern.format(sdk_id=sdk_id))
+        if data is not None:
+            benchmarks[key] = data
+    if benchmarks:
         result["benchmarks"] = benchmarks
 
     return result
 
 
+# ── Platforms ───────────────────────────────────────────────────────────
+
+
+def sdk_platform(sdk: dict) -> str:
+    """Return the os/arch an SDK entry was measured on."""
+    meta = sdk.get("pipeline", {}).get("metadata", {})
+    return meta.get("platform") or DEFAULT_PLATFORM
+
+
+def platform_key(sdk: dict) -> str:
+    """Identify an SDK entry across runs: its id on the default platform,
+    "id@os/arch" elsewhere, so regressions never compare platforms."""
+    sdk_id = sdk.get("id", "")
+    platform = sdk_platform(sdk)
+    return sdk_id if platform == DEFAULT_PLATFORM else f"{sdk_id}@{platform}"
+
+
+def platform_comparison(sdk_benchmarks: list[dict]) -> list[dict]:
+    """List every operation an SDK ran on more than one platform."""
+    means: dict[tuple[str, str, str], dict[str, float]] = {}
+    for sdk in sdk_benchmarks:
+        platform = sdk_platform(sdk)
+        datasets = sdk.get("pipeline", {}).get("datasets", {})
+        for ds_name, ds in datasets.items():
+            for op_name, op in ds.get("operations", {}).items():
+                if op.get("mean_ns") is None:
+                    continue
+                key = (sdk.get("id", ""), ds_name, op_name)
+                means.setdefault(key, {})[platform] = op["mean_ns"]
+
+    rows = []
+    for (sdk_id, ds_name, op_name), platforms in sorted(means.items()):
+        if len(platforms) < 2:
+            continue
+        baseline = DEFAULT_PLATFORM if DEFAULT_PLATFORM in platforms else min(platforms)
+        base = platforms[baseline]
+        rows.append({
+            "sdk_id": sdk_id,
+            "dataset": ds_name,
+            "operation": op_name,
+            "platforms": platforms,
+            "baseline": baseline,
+            "relative": {
+                p: round(mean / base, 3) for p, mean in platforms.items()
+            } if base > 0 else {},
+        })
+    return rows
+
+
 # ── Regression detection (SRQ-5) ──────────────────────────────────────
 
 
@@ -242,8 +453,7 @@ def _compute_regressions(
     current_sdk: dict, previous_sdks: dict[str, dict]
 ) -> list[dict]:
     """Compare current SDK results against previous, return flagged regressions."""
-    sdk_id = current_sdk.get("id", "")
-    prev_sdk = previous_sdks.get(sdk_id)
+    prev_sdk = previous_sdks.get(platform_key(current_sdk))
     if prev_sdk is None:
         return []
 
@@ -306,7 +516,7 @@ def _compute_regressions(
                 direction = "unchanged"
 
             if significant:
-                regressions.append({
+                flagged = {
                     "dataset": ds_name,
                     "operation": op_name,
                     "previous_mean_ns": prev_mean,
@@ -316,22 +526,24 @@ def _compute_regressions(
                     "ci_upper_pct": round(ci_upper, 2),
                     "significant": True,
                     "direction": direction,
-                })
+                }
+                if NAMESPACE_SEPARATOR in op_name:
+                    flagged["namespace"] = op_name.split(NAMESPACE_SEPARATOR, 1)[0]
+                regressions.append(flagged)
 
     return regressions
 
 
 def _build_previous_index(previous_data: dict) -> dict[str, dict]:
-    """Build sdk_id -> SDK entry map from previous results.json."""
+    """Build platform_key -> SDK entry map from previous results.json."""
     index: dict[str, dict] = {}
     for sdk in previous_data.get("sdk_benchmarks", []):
         pipeline = sdk.get("pipeline")
         if isinstance(pipeline, dict):
             normalized, _ = normalize_pipeline_report(pipeline)
             sdk["pipeline"] = normalized
-        sdk_id = sdk.get("id", "")
-        if sdk_id:
-            index[sdk_id] = sdk
+        if sdk.get("id", ""):
+            index[platform_key(sdk)] = sdk
     return index
 
 
@@ -404,6 +616,10 @@ def main():
         "--previous-results", type=Path, default=None,
         help="Path to previous results.json for regression detection (SRQ-5).",
     )
+    parser.add_argument(
+        "--by-platform", action="store_true",
+        help="Add platform_comparison, the operations each SDK ran on several platforms.",
+    )
     args = parser.parse_args()
 
     sdk_benchmarks, server_benchmarks = aggregate(args.results_dir, args.known_sdks)
@@ -429,6 +645,8 @@ def main():
         "sdk_benchmarks": sdk_benchmarks,
         "server_benchmarks": server_benchmarks,
     }
+    if args.by_platform:
+        output["platform_comparison"] = platform_comparison(sdk_benchmarks)
 
     args.output.parent.mkdir(parents=True, exist_ok=True)
     with open(args.output, "w") as f:
@@ -0,0 +1,76 @@
+"""Describe the numeric fields of a report.json by unit and aggregation.
+
+Mirrors UnitsManifest.Describe in sdks/aas-core3-golang/report/units.go and
+reads the same units.json, so the aggregator, the report validator and the
+adapters' emit_report.py fallbacks agree with aasbench.
+"""
+
+import json
+from pathlib import Path
+
+REPO_ROOT = Path(__file__).resolve().parent.parent
+UNITS_MANIFEST = REPO_ROOT / "sdks" / "aas-core3-golang" / "report" / "units.json"
+# Schema version that added metrics_manifest (report.SchemaVersion), and the
+# last one without it (report.LegacySchemaVersion).
+SCHEMA_VERSION = 3
+LEGACY_SCHEMA_VERSION = 2
+
+
+def load_units(path: Path = UNITS_MANIFEST) -> dict:
+    with open(path, encoding="utf-8") as f:
+        return json.load(f)
+
+
+def path_matches(pattern: list[str], path: list[str]) -> bool:
+    """Whether a concrete field path matches a pattern, in which "*"
+    matches any key and "*[]" any key of an array."""
+    if len(pattern) != len(path):
+        return False
+    for p, c in zip(pattern, path):
+        if p == c:
+            continue
+        if p == "*" and not c.endswith("[]"):
+            continue
+        if p == "*[]" and c.endswith("[]"):
+            continue
+        return False
+    return True
+
+
+def describe(report: dict, units: dict | None = None) -> dict[str, dict]:
+    """Return the semantics of every numeric field in report by the pattern
+    of its path, e.g. {"datasets.*.operations.*.mean_ns": {"unit": "ns",
+    "agg": "mean"}}. Numbers no pattern matches are left out."""
+    fields = (units or load_units())["fields"]
+    patterns = [(field, field["path"].split(".")) for field in fields]
+    found: dict[str, dict] = {}
+
+    def walk(value, path):
+        if isinstance(value, dict):
+            for key in sorted(value):
+                walk(value[key], path + [key])
+        elif isinstance(value, list):
+            if not path:
+                return
+            elem = path[:-1] + [path[-1] + "[]"]
+            for item in value:
+                walk(item, elem)
+        elif isinstance(value, (int, float)) and not isinstance(value, bool):
+            for field, pattern in patterns:
+                if path_matches(pattern, path):
+                    found[field["path"]] = {k: field[k] for k in ("unit", "unit_field", "agg") if k in field}
+                    break
+
+    walk(report, [])
+    return found
+
+
+def set_schema_version(report: dict, version: int, units: dict | None = None) -> None:
+    """Make report a report of the given schema version: version 3 carries
+    a metrics_manifest, the legacy version 2 none."""
+    report.pop("metrics_manifest", None)
+    if version == SCHEMA_VERSION:
+        report["metrics_manifest"] = describe(report, units)
+    elif version != LEGACY_SCHEMA_VERSION:
+        raise ValueError(f"unsupported schema version {version} (want {SCHEMA_VERSION} or {LEGACY_SCHEMA_VERSION})")
+    report["schema_version"] = version
@@ -0,0 +1,125 @@
+#!/usr/bin/env python3
+"""Resolve the server adapters' image tags to digests and write server_matrix.json.
+
+Every enabled server in known-sdks.json is resolved once, when the matrix
+is built, so that all of a run's jobs start the same content even if a tag
+such as a BaSyx SNAPSHOT moves during the run. The output lists, per
+server, each compose service's image, digest and version, and the pinned
+reference ("repo@sha256:...") the server job starts instead of the tag.
+"""
+
+import argparse
+import json
+import subprocess
+import sys
+from datetime import datetime, timezone
+from pathlib import Path
+
+ROOT = Path(__file__).resolve().parent.parent
+KNOWN_SDKS = ROOT / "known-sdks.json"
+
+# OCI label carrying the version an image was built from.
+VERSION_LABEL = "org.opencontainers.image.version"
+
+
+def compose_images(adapter_dir: Path) -> dict[str, str]:
+    """Return the image of every service in adapter_dir's compose file."""
+    out = subprocess.run(
+        ["docker", "compose", "config", "--format", "json"],
+        cwd=adapter_dir, capture_output=True, text=True, check=True,
+    )
+    services = json.loads(out.stdout).get("services", {})
+    return {name: svc["image"] for name, svc in services.items() if svc.get("image")}
+
+
+def inspect_image(image: str) -> dict:
+    """Return the registry's digest and config of image, without pulling it."""
+    out = subprocess.run(
+        ["docker", "buildx", "imagetools", "inspect", image,
+         "--format", '{"digest": {{json .Manifest.Digest}}, "image": {{json .Image}}}'],
+        capture_output=True, text=True, check=True,
+    )
+    return json.loads(out.stdout)
+
+
+def split_tag(image: str) -> tuple[str, str]:
+    """Split "repo:tag" into repo and tag, minding registry ports."""
+    repo, sep, tag = image.rpartition(":")
+    if not sep or "/" in tag:
+        return image, "latest"
+    return repo, tag
+
+
+def image_version(config: dict | None, tag: str) -> str:
+    """Return the OCI version label, falling back to the tag.
+
+    A multi-platform index yields one config per platform; linux/amd64 is
+    the one the runners start.
+    """
+    if config and "config" not in config:
+        config = config.get("linux/amd64") or next(iter(config.values()), None)
+    labels = ((config or {}).get("config") or {}).get("Labels") or {}
+    return labels.get(VERSION_LABEL) or tag
+
+
+def pin_service(image: str, inspect=inspect_image) -> dict:
+    """Resolve one compose image to its pin."""
+    repo, tag = split_tag(image)
+    entry = {"image": image, "tag": tag}
+    if "@sha256:" in image:
+        entry.update(digest=image.rpartition("@")[2], pinned=image, version=tag)
+        return entry
+    try:
+        info = inspect(image)
+    except (subprocess.CalledProcessError, json.JSONDecodeError) as exc:
+        print(f"  WARNING: could not resolve {image}: {exc}", file=sys.stderr)
+        entry.update(digest=None, pinned=image, version=tag)
+        return entry
+    entry.update(
+        digest=info["digest"],
+        pinned=f"{repo}@{info['digest']}",
+        version=image_version(info.get("image"), tag),
+    )
+    return entry
+
+
+def build_matrix(servers: list[dict], images=compose_images, inspect=inspect_image) -> dict:
+    """Pin the images of every enabled server."""
+    pinned = []
+    for server in servers:
+        if not server.get("enabled"):
+            continue
+        print(f"Resolving {server['id']} images...", file=sys.stderr)
+        services = {
+            name: pin_service(image, inspect)
+            for name, image in sorted(images(ROOT / server["adapter_dir"]).items())
+        }
+        pinned.append({
+            "id": server["id"],
+            "name": server["name"],
+            "adapter_dir": server["adapter_dir"],
+            "services": services,
+        })
+    return {
+        "resolved_at": datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ"),
+        "servers": pinned,
+    }
+
+
+def main():
+    parser = argparse.ArgumentParser(description=__doc__)
+    parser.add_argument(
+        "--output", type=Path, default=Path("server_matrix.json"),
+        help="Where to write the pinned matrix (default: server_matrix.json)",
+    )
+    args = parser.parse_args()
+
+    with open(KNOWN_SDKS) as f:
+        servers = json.load(f).get("server_benchmarks", [])
+    matrix = build_matrix(servers)
+    args.output.write_text(json.dumps(matrix, indent=2) + "\n", encoding="utf-8")
+    print(f"Wrote {args.output}", file=sys.stderr)
+
+
+if __name__ == "__main__":
+    main()
@@ -4,8 +4,23 @@ set -euo pipefail
 KNOWN_SDKS="$(dirname "$0")/../known-sdks.json"
 MATRIX_TYPE="${1:?Usage: set-matrix.sh <sdk|server>}"
 
+# An SDK runs once per entry of its optional "platforms" list (default
+# linux/amd64), each on the runner below. Results of other platforms than the
+# default go to results/<id>-<os>-<arch>, so they never overwrite each other.
+RUNNERS='{
+  "linux/amd64": "ubuntu-latest",
+  "linux/arm64": "ubuntu-24.04-arm",
+  "windows/amd64": "windows-latest",
+  "darwin/arm64": "macos-14"
+}'
+
 if [ "$MATRIX_TYPE" = "sdk" ]; then
-  jq -c '{include: [.sdk_benchmarks[] | select(.enabled == true) | {id, name, language, adapter_dir}]}' "$KNOWN_SDKS"
+  jq -c --argjson runners "$RUNNERS" '{include: [.sdk_benchmarks[] | select(.enabled == true) | . as $sdk
+    | ($sdk.platforms // ["linux/amd64"])[]
+    | {id: $sdk.id, name: $sdk.name, language: $sdk.language, adapter_dir: $sdk.adapter_dir,
+       platform: .,
+       runner: ($runners[.] // error("\($sdk.id): unsupported platform \(.)")),
+       result_dir: (if . == "linux/amd64" then $sdk.id else $sdk.id + "-" + sub("/"; "-") end)}]}' "$KNOWN_SDKS"
 elif [ "$MATRIX_TYPE" = "server" ]; then
   jq -c '{include: [.server_benchmarks[] | select(.enabled == true) | {id, name, adapter_dir}]}' "$KNOWN_SDKS"
 else
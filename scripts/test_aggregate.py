@@ -1,7 +1,10 @@
 #!/usr/bin/env python3
 """Unit tests for report normalization and regression logic."""
 
+import json
+import tempfile
 import unittest
+from pathlib import Path
 
 import aggregate
 
@@ -30,6 +33,9 @@ class AggregateTests(unittest.TestCase):
         self.assertEqual(ops["deserialize_xml"]["operation_id"], "deserialize_xml")
         self.assertEqual(ops["deserialize_xml"]["operation_track"], "xml")
         self.assertEqual(ops["deserialize_xml"]["sample_count"], 10)
+        manifest = normalized["metrics_manifest"]
+        self.assertEqual(manifest["datasets.*.operations.*.mean_ns"], {"unit": "ns", "agg": "mean"})
+        self.assertEqual(manifest["datasets.*.operations.*.sample_count"]["unit"], "count")
 
     def test_compute_regressions_prefers_sample_count(self):
         current = {
@@ -73,6 +79,31 @@ class AggregateTests(unittest.TestCase):
         # With small sample_count and high variance, change should not be significant.
         self.assertEqual(regs, [])
 
+    def test_regressions_stay_on_one_platform(self):
+        def entry(platform, mean):
+            metadata = {"platform": platform} if platform else {}
+            op = {"mean_ns": mean, "stddev_ns": 1, "sample_count": 50}
+            return {
+                "id": "sdk-a",
+                "pipeline": {
+                    "metadata": metadata,
+                    "datasets": {"wide": {"operations": {"deserialize": op}}},
+                },
+            }
+
+        x86, arm = entry(None, 100), entry("linux/arm64", 300)
+        self.assertEqual(aggregate.platform_key(arm), "sdk-a@linux/arm64")
+        index = aggregate._build_previous_index({"sdk_benchmarks": [x86]})
+        self.assertEqual(aggregate._compute_regressions(arm, index), [])
+        self.assertEqual(
+            len(aggregate._compute_regressions(entry("linux/amd64", 300), index)), 1
+        )
+
+        rows = aggregate.platform_comparison([arm, x86])
+        self.assertEqual(len(rows), 1)
+        self.assertEqual(rows[0]["baseline"], "linux/amd64")
+        self.assertEqual(rows[0]["relative"], {"linux/arm64": 3.0, "linux/amd64": 1.0})
+
     def test_derive_capabilities_and_core_eligibility(self):
         report = {
             "datasets": {
@@ -92,6 +123,79 @@ class AggregateTests(unittest.TestCase):
         self.assertTrue(caps["xml"])
         self.assertTrue(caps["aasx"])
 
+    def test_validation_modes_join_validation_track(self):
+        for op_id in ("validate_first_error", "validate_collect_all"):
+            self.assertEqual(aggregate.infer_operation_track("val_regex", op_id), "validation")
+            self.assertEqual(aggregate.infer_operation_track("wide", op_id), "capability")
+        caps, _ = aggregate.derive_capabilities(
+            {"datasets": {"val_regex": {"operations": {"validate_collect_all": {}}}}}
+        )
+        self.assertTrue(caps["validation"])
+
+    def test_micro_datasets_form_micro_track(self):
+        self.assertEqual(aggregate.infer_operation_track("micro_xsd_value", "value_parse"), "micro")
+        self.assertEqual(aggregate.infer_operation_track("wide", "value_parse"), "capability")
+
+    def test_ladder_datasets_form_scaling_track(self):
+        self.assertEqual(aggregate.infer_operation_track("scale_deep_x4", "deserialize"), "scaling")
+
+    def test_i18n_dataset_forms_i18n_track(self):
+        self.assertEqual(aggregate.infer_operation_track("i18n", "serialize"), "i18n")
+        self.assertEqual(aggregate.infer_operation_track("i18n", "serialize_xml"), "xml")
+
+    def test_select_headline_falls_back_to_next_stat(self):
+        report = {
+            "datasets": {
+                "mixed": {"operations": {"deserialize": {"mean_ns": 900, "p99_ns": None}}},
+                "deep": {"operations": {"validate": {"mean_ns": 300, "p99_ns": None}}},
+            }
+        }
+        catalog = aggregate.read_json(aggregate.METRIC_CATALOG)
+
+        headline = aggregate.select_headline(report, catalog)
+
+        self.assertEqual([h["id"] for h in headline], ["deserialize_mixed", "validate_deep"])
+        self.assertEqual(headline[1]["stat"], "mean_ns")
+        self.assertEqual(headline[1]["label"], "validate/deep mean")
+
+    def test_server_entry_records_images(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            entry = Path(tmp) / "faaast-service"
+            entry.mkdir()
+            (entry / "conformance_summary.json").write_text("{}")
+            (entry / "server_images.json").write_text(
+                json.dumps({"images": ["fraunhoferiosb/faaast-service:1.3.0"]})
+            )
+
+            result = aggregate._build_server_entry(entry, {})
+
+        self.assertEqual(result["images"], ["fraunhoferiosb/faaast-service:1.3.0"])
+
+    def test_server_entry_collects_k6_outputs(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            entry = Path(tmp) / "basyx-java"
+            entry.mkdir()
+            (entry / "conformance_summary.json").write_text("{}")
+            bulk = {"mode": "upload", "sizes": [{"elements": 100, "elements_per_sec": 5000.0}]}
+            (entry / "k6_bulk_basyx-java.json").write_text(json.dumps(bulk))
+
+            result = aggregate._build_server_entry(entry, {})
+
+        self.assertEqual(result["benchmarks"], {"bulk_import": bulk})
+
+    def test_server_entry_records_warmup(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            entry = Path(tmp) / "basyx-java"
+            entry.mkdir()
+            (entry / "conformance_summary.json").write_text("{}")
+            warmup = {"healthy": True, "warmup_s": 41.5, "stable": True}
+            (entry / "warmup_basyx-java.json").write_text(json.dumps(warmup))
+
+            result = aggregate._build_server_entry(entry, {})
+
+        self.assertEqual(result["warmup"], warmup)
+        self.assertNotIn("benchmarks", result)
+
 
 if __name__ == "__main__":
     unittest.main()
@@ -0,0 +1,47 @@
+#!/usr/bin/env python3
+"""Unit tests for resolving server image tags to digests."""
+
+import unittest
+
+import pin_images
+
+DIGEST = "sha256:" + "ab" * 32
+
+
+class PinImagesTests(unittest.TestCase):
+    def test_build_matrix_pins_tags_to_digests(self):
+        servers = [
+            {"id": "basyx-java", "name": "BaSyx", "adapter_dir": "servers/basyx-java", "enabled": True},
+            {"id": "off", "name": "Off", "adapter_dir": "servers/off", "enabled": False},
+        ]
+        def images(_adapter_dir):
+            return {
+                "aas-env": "eclipsebasyx/aas-environment:2.0.0-SNAPSHOT-273887a",
+                "mongo": "mongo:7",
+            }
+
+        configs = {
+            "eclipsebasyx/aas-environment:2.0.0-SNAPSHOT-273887a": {
+                "linux/amd64": {"config": {"Labels": {pin_images.VERSION_LABEL: "2.0.0-SNAPSHOT"}}},
+            },
+            "mongo:7": {"config": {}},
+        }
+
+        def inspect(image):
+            return {"digest": DIGEST, "image": configs[image]}
+
+        matrix = pin_images.build_matrix(servers, images, inspect)
+
+        self.assertEqual([s["id"] for s in matrix["servers"]], ["basyx-java"])
+        services = matrix["servers"][0]["services"]
+        self.assertEqual(services["aas-env"]["pinned"], f"eclipsebasyx/aas-environment@{DIGEST}")
+        self.assertEqual(services["aas-env"]["version"], "2.0.0-SNAPSHOT")
+        self.assertEqual(services["mongo"]["version"], "7")
+
+    def test_split_tag_minds_registry_ports(self):
+        self.assertEqual(pin_images.split_tag("localhost:5000/aas"), ("localhost:5000/aas", "latest"))
+        self.assertEqual(pin_images.split_tag("localhost:5000/aas:1.2"), ("localhost:5000/aas", "1.2"))
+
+
+if __name__ == "__main__":
+    unittest.main()
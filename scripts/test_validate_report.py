@@ -0,0 +1,46 @@
+#!/usr/bin/env python3
+"""Unit tests for the exit codes and status.json of validate_report.py."""
+
+import json
+import tempfile
+import unittest
+from contextlib import redirect_stderr, redirect_stdout
+from io import StringIO
+from pathlib import Path
+
+import validate_report
+
+
+class ValidateReportExitTests(unittest.TestCase):
+    def run_main(self, report: Path, status: Path) -> int:
+        with redirect_stdout(StringIO()), redirect_stderr(StringIO()):
+            return validate_report.main([str(report), "--status", str(status)])
+
+    def test_invalid_report_is_a_schema_error(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            report, status = Path(tmp, "report.json"), Path(tmp, "status.json")
+            report.write_text(json.dumps({"datasets": {}}), encoding="utf-8")
+            self.assertEqual(self.run_main(report, status), validate_report.EXIT_SCHEMA)
+            written = json.loads(status.read_text(encoding="utf-8"))
+            self.assertEqual(written["outcome"], "schema_error")
+            self.assertEqual(written["exit_code"], 2)
+            self.assertEqual(written["details"][str(report)], ["report must contain at least one dataset"])
+
+    def test_unreadable_report_is_an_infrastructure_failure(self):
+        with tempfile.TemporaryDirectory() as tmp:
+            status = Path(tmp, "status.json")
+            self.assertEqual(self.run_main(Path(tmp, "missing.json"), status), validate_report.EXIT_INFRASTRUCTURE)
+            self.assertEqual(json.loads(status.read_text(encoding="utf-8"))["outcome"], "infrastructure_failure")
+
+
+class ValidateMetadataTests(unittest.TestCase):
+    def test_datasets_manifest_is_checked_inside_metadata(self):
+        fingerprint = {"dataset": "wide", "file": "wide.json", "format": "json", "sha256": "0a" * 32, "size_bytes": 2048}
+        errors = validate_report.validate_metadata({"datasets_manifest": [fingerprint]})
+        self.assertEqual([e for e in errors if "datasets_manifest" in e], [])
+        errors = validate_report.validate_metadata({"datasets_manifest": [dict(fingerprint, sha256="abc")]})
+        self.assertIn("metadata field 'datasets_manifest' entry 0 must have a hex 'sha256', got 'abc'", errors)
+
+
+if __name__ == "__main__":
+    unittest.main()
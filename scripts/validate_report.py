@@ -6,6 +6,15 @@ Checks:
   - no dataset has an empty operations object
   - operation keys are canonical snake_case IDs
   - operation_id field (if present) matches canonical key
+  - un-namespaced operations are canonical; extension operations carry an
+    adapter namespace ("vendorx:transform") that is not reserved
+  - metadata has the required keys, only whitelisted keys, and typed values
+  - schema version 3 reports describe every numeric field in metrics_manifest
+    with a known unit and aggregation
+
+Exits like `aasbench validate`: 0 for a valid report, 2 for an invalid one
+and 3 when it cannot be read. --status records the outcome in the
+status.json form aasbench writes.
 """
 
 from __future__ import annotations
@@ -14,10 +23,85 @@ import argparse
 import json
 import re
 import sys
+from datetime import datetime, timezone
 from pathlib import Path
 
+import metrics_manifest
+
+# Exit codes and status.json outcomes, as in
+# sdks/aas-core3-golang/cmd/aasbench/status.go.
+EXIT_OK = 0
+EXIT_SCHEMA = 2
+EXIT_INFRASTRUCTURE = 3
+OUTCOMES = {EXIT_OK: "ok", EXIT_SCHEMA: "schema_error", EXIT_INFRASTRUCTURE: "infrastructure_failure"}
+
+NAMESPACE_SEPARATOR = ":"
+CANONICAL_OPERATIONS = {
+    "deserialize",
+    "validate",
+    "traverse",
+    "update",
+    "serialize",
+    "deserialize_xml",
+    "serialize_xml",
+    "aasx_extract",
+    "aasx_repackage",
+    "diff",
+    "patch",
+    "index_build",
+    "index_lookup",
+    "deserialize_pooled",
+    "validate_first_error",
+    "validate_collect_all",
+    "enum_from_string",
+    "enum_to_string",
+    "value_parse",
+    "iri_match",
+    "lang_string_lookup",
+    "merge",
+    "hash",
+    "equals",
+    "deserialize_invalid",
+    "deserialize_file_warm",
+    "deserialize_file_cold",
+    "find_by_semantic_id",
+    "find_by_semantic_id_miss",
+    "serialize_stream",
+    "deserialize_xml_nsheavy",
+    "startup",
+    "filter",
+    "canonicalize",
+}
+RESERVED_NAMESPACES = {"aas", "core", "observatory"}
+NAMESPACE_RE = re.compile(r"^[a-z][a-z0-9_-]*$")
+OPERATION_NAME_RE = re.compile(r"^[a-z0-9]+(_[a-z0-9]+)*$")
+PLATFORM_RE = re.compile(r"^[a-z0-9]+/[a-z0-9]+$")
+SHA256_RE = re.compile(r"^[0-9a-f]{64}$")
+
+# Metadata whitelist: key -> (kind, required). Mirrors metadataFields in
+# sdks/aas-core3-golang/report/metadata.go.
+METADATA_FIELDS = {
+    "language": ("string", True),
+    "runtime_version": ("string", True),
+    "sdk_package_version": ("string", True),
+    "benchmark_harness": ("string", True),
+    "timestamp": ("timestamp", True),
+    "sdk_module": ("string", False),
+    "observatory_git_sha": ("string", False),
+    "observatory_git_dirty": ("bool", False),
+    "backfilled_at": ("timestamp", False),
+    "gomaxprocs": ("int", False),
+    "cpu_affinity": ("string", False),
+    "platform": ("platform", False),
+    "datasets_manifest": ("fingerprints", False),
+}
+
 
 def canonical_operation_id(raw_op: str) -> str:
+    if NAMESPACE_SEPARATOR in raw_op:
+        namespace, name = raw_op.split(NAMESPACE_SEPARATOR, 1)
+        return f"{namespace.lower()}{NAMESPACE_SEPARATOR}{canonical_operation_id(name)}"
+
     explicit = {
         "deserializeXml": "deserialize_xml",
         "serializeXml": "serialize_xml",
@@ -42,6 +126,102 @@ def canonical_operation_id(raw_op: str) -> str:
     return dense_map.get(snake, snake)
 
 
+def check_operation_id(op_id: str) -> str | None:
+    """Return why a canonical-form operation ID is not allowed, or None."""
+    if NAMESPACE_SEPARATOR not in op_id:
+        if op_id not in CANONICAL_OPERATIONS:
+            return (
+                f"operation {op_id!r} is not canonical; extension operations must be "
+                f"namespaced (e.g. 'vendorx{NAMESPACE_SEPARATOR}{op_id}')"
+            )
+        return None
+    namespace, name = op_id.split(NAMESPACE_SEPARATOR, 1)
+    if not NAMESPACE_RE.match(namespace):
+        return f"operation {op_id!r} has invalid namespace {namespace!r}"
+    if namespace in RESERVED_NAMESPACES:
+        return f"operation {op_id!r} uses reserved namespace {namespace!r}"
+    if not OPERATION_NAME_RE.match(name):
+        return f"operation {op_id!r} has invalid name {name!r} (want snake_case)"
+    return None
+
+
+def check_fingerprints(value: object) -> str | None:
+    """Return why a datasets_manifest is malformed, or None. Mirrors
+    checkFingerprints in sdks/aas-core3-golang/report/metadata.go."""
+    if not isinstance(value, list):
+        return f"must be a list of dataset fingerprints, got {value!r}"
+    for i, fp in enumerate(value):
+        if not isinstance(fp, dict):
+            return f"entry {i} must be an object, got {fp!r}"
+        for key in ("dataset", "file", "format"):
+            if not isinstance(fp.get(key), str):
+                return f"entry {i} must have a string {key!r}, got {fp.get(key)!r}"
+        sha = fp.get("sha256")
+        if not isinstance(sha, str) or not SHA256_RE.match(sha):
+            return f"entry {i} must have a hex 'sha256', got {sha!r}"
+        size = fp.get("size_bytes")
+        if isinstance(size, bool) or not isinstance(size, int) or size < 0:
+            return f"entry {i} must have a non-negative integer 'size_bytes', got {size!r}"
+    return None
+
+
+def validate_metadata(meta: object) -> list[str]:
+    if not isinstance(meta, dict):
+        return ["metadata must be an object"]
+    errors = [
+        f"metadata missing required field {key!r}"
+        for key, (_, required) in sorted(METADATA_FIELDS.items())
+        if required and key not in meta
+    ]
+    for key in sorted(meta):
+        if key not in METADATA_FIELDS:
+            errors.append(f"metadata has unknown field {key!r}")
+            continue
+        kind, _ = METADATA_FIELDS[key]
+        value = meta[key]
+        if kind == "fingerprints":
+            problem = check_fingerprints(value)
+            if problem:
+                errors.append(f"metadata field {key!r} {problem}")
+        elif kind == "bool":
+            if not isinstance(value, bool):
+                errors.append(f"metadata field {key!r} must be a boolean, got {value!r}")
+        elif kind == "int":
+            if isinstance(value, bool) or not isinstance(value, int) or value < 1:
+                errors.append(f"metadata field {key!r} must be a positive integer, got {value!r}")
+        elif not isinstance(value, str) or not value.strip():
+            errors.append(f"metadata field {key!r} must be a non-empty string, got {value!r}")
+        elif kind == "timestamp":
+            try:
+                datetime.fromisoformat(value.replace("Z", "+00:00"))
+            except ValueError:
+                errors.append(f"metadata field {key!r} must be an RFC 3339 timestamp: {value!r}")
+        elif kind == "platform" and not PLATFORM_RE.match(value):
+            errors.append(f"metadata field {key!r} must be an os/arch string such as 'linux/amd64': {value!r}")
+    return errors
+
+
+def validate_metrics_manifest(report: dict) -> list[str]:
+    manifest = report.get("metrics_manifest")
+    if not isinstance(manifest, dict):
+        return ["schema version 3 reports must contain a metrics_manifest object"]
+    units = metrics_manifest.load_units()
+    errors = []
+    for path in sorted(manifest):
+        entry = manifest[path]
+        if not isinstance(entry, dict):
+            errors.append(f"metrics_manifest entry {path!r} is not an object")
+            continue
+        if entry.get("unit") not in units["units"] and not entry.get("unit_field"):
+            errors.append(f"metrics_manifest entry {path!r} has unknown unit {entry.get('unit')!r}")
+        if entry.get("agg") not in units["aggregations"]:
+            errors.append(f"metrics_manifest entry {path!r} has unknown aggregation {entry.get('agg')!r}")
+    for path in sorted(metrics_manifest.describe(report, units)):
+        if path not in manifest:
+            errors.append(f"metrics_manifest does not describe {path}")
+    return errors
+
+
 def validate_report(path: Path) -> list[str]:
     errors: list[str] = []
     try:
@@ -53,6 +233,10 @@ def validate_report(path: Path) -> list[str]:
     if not isinstance(datasets, dict) or not datasets:
         return ["report must contain at least one dataset"]
 
+    errors.extend(validate_metadata(report.get("metadata")))
+    version = report.get("schema_version")
+    if isinstance(version, (int, float)) and version >= metrics_manifest.SCHEMA_VERSION:
+        errors.extend(validate_metrics_manifest(report))
     op_count = 0
     for dataset_name, dataset_entry in datasets.items():
         if not isinstance(dataset_entry, dict):
@@ -71,6 +255,9 @@ def validate_report(path: Path) -> list[str]:
                     f"dataset {dataset_name!r} contains non-canonical operation key "
                     f"{op_key!r} (canonical: {canonical!r})"
                 )
+            problem = check_operation_id(canonical)
+            if problem:
+                errors.append(f"dataset {dataset_name!r}: {problem}")
             if isinstance(op_entry, dict):
                 op_id = op_entry.get("operation_id")
                 if op_id is not None and op_id != canonical:
@@ -101,20 +288,53 @@ def validate_report(path: Path) -> list[str]:
     return errors
 
 
-def main() -> int:
+def write_status(path: Path, code: int, message: str, started: datetime, details: dict) -> None:
+    """Write the outcome to path in the schema of aasbench's status.json."""
+    status = {
+        "tool": "validate_report.py",
+        "outcome": OUTCOMES[code],
+        "exit_code": code,
+        "started_at": started.strftime("%Y-%m-%dT%H:%M:%SZ"),
+        "finished_at": datetime.now(timezone.utc).strftime("%Y-%m-%dT%H:%M:%SZ"),
+    }
+    if message:
+        status["message"] = message
+    if details:
+        status["details"] = details
+    try:
+        path.write_text(json.dumps(status, indent=2), encoding="utf-8")
+    except OSError as exc:
+        print(f"could not write status {path}: {exc}", file=sys.stderr)
+
+
+def main(argv: list[str] | None = None) -> int:
     parser = argparse.ArgumentParser(description=__doc__)
     parser.add_argument("report", type=Path, help="Path to report.json")
-    args = parser.parse_args()
+    parser.add_argument("--status", type=Path, help="write the structured outcome as JSON to this path")
+    args = parser.parse_args(argv)
+    started = datetime.now(timezone.utc)
+
+    def finish(code: int, message: str = "", details: dict | None = None) -> int:
+        if args.status:
+            write_status(args.status, code, message, started, details or {})
+        return code
+
+    try:
+        args.report.read_bytes()
+    except OSError as exc:
+        print(f"Cannot read report: {exc}", file=sys.stderr)
+        return finish(EXIT_INFRASTRUCTURE, str(exc))
 
     problems = validate_report(args.report)
+    details = {str(args.report): problems}
     if problems:
         print(f"Invalid report: {args.report}", file=sys.stderr)
         for p in problems:
             print(f"  - {p}", file=sys.stderr)
-        return 1
+        return finish(EXIT_SCHEMA, "1 invalid report(s)", details)
 
     print(f"Report valid: {args.report}")
-    return 0
+    return finish(EXIT_OK, details=details)
 
 
 if __name__ == "__main__":
@@ -1,8 +1,11 @@
 #!/usr/bin/env python3
-"""Convert BenchmarkDotNet JSON export to report.json (schema v2).
+"""Convert BenchmarkDotNet JSON export to report.json (schema v3).
 
 Usage:
-    python3 emit_report.py <benchmarkdotnet_json> <output_path>
+    python3 emit_report.py [--schema-version {2,3}] <benchmarkdotnet_json> <output_path>
+
+Schema v3 adds a metrics_manifest giving the unit and aggregation of every
+numeric field; --schema-version 2 leaves it out.
 
 Schema v2 additions:
   - memory.heap_used_bytes, gc_pause_ms, gc_count, traced_peak_bytes
@@ -13,6 +16,10 @@ import argparse
 import json
 import sys
 from datetime import datetime, timezone
+from pathlib import Path
+
+sys.path.insert(0, str(Path(__file__).resolve().parents[2] / "scripts"))
+import metrics_manifest  # noqa: E402
 
 CORE_DATASETS = {"wide", "deep", "mixed"}
 CORE_OPERATIONS = {"deserialize", "validate", "traverse", "update", "serialize"}
@@ -139,6 +146,11 @@ def main():
         help="Path to BenchmarkDotNet JSON export file",
     )
     parser.add_argument("output", help="Path to write report.json")
+    parser.add_argument(
+        "--schema-version", type=int, default=metrics_manifest.SCHEMA_VERSION,
+        choices=[metrics_manifest.LEGACY_SCHEMA_VERSION, metrics_manifest.SCHEMA_VERSION],
+        help="report.json schema version to write",
+    )
     args = parser.parse_args()
 
     with open(args.benchmarkdotnet_json, "r", encoding="utf-8") as f:
@@ -179,6 +191,7 @@ def main():
         },
         "datasets": datasets,
     }
+    metrics_manifest.set_schema_version(report, args.schema_version)
 
     with open(args.output, "w", encoding="utf-8") as f:
         json.dump(report, f, indent=2)
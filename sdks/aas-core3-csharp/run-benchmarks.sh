@@ -22,10 +22,29 @@ dotnet restore
 # --filter '*' runs all benchmarks, --exporters json produces machine-readable output
 dotnet run -c Release -- --filter '*' --exporters json
 
-# Convert BenchmarkDotNet JSON export to report.json
-# BenchmarkDotNet writes results to BenchmarkDotNet.Artifacts/results/
-python3 "$SCRIPT_DIR/emit_report.py" \
-  BenchmarkDotNet.Artifacts/results/*.json \
-  "$OUTPUT_DIR/report.json"
+# Convert the BenchmarkDotNet JSON export in BenchmarkDotNet.Artifacts/results/
+# to report.json with the shared Go tooling when it is available;
+# emit_report.py remains as a fallback.
+GO_TOOLING="$SCRIPT_DIR/../aas-core3-golang"
+if command -v aasbench >/dev/null 2>&1; then
+  AASBENCH=(aasbench)
+elif command -v go >/dev/null 2>&1; then
+  AASBENCH=(go -C "$GO_TOOLING" run ./cmd/aasbench)
+else
+  AASBENCH=()
+fi
+
+if [ ${#AASBENCH[@]} -gt 0 ]; then
+  SDK_VERSION="$(dotnet list package 2>/dev/null | sed -n 's/.*> AasCore.Aas3_0 .* \([0-9][^ ]*\) *$/\1/p' | head -n 1)"
+  "${AASBENCH[@]}" ingest --format benchmarkdotnet \
+    --input "$SCRIPT_DIR/BenchmarkDotNet.Artifacts/results" \
+    --output "$OUTPUT_DIR/report.json" \
+    --sdk-id aas-core3-csharp \
+    --meta "sdk_package_version=${SDK_VERSION:-unknown}"
+else
+  python3 "$SCRIPT_DIR/emit_report.py" \
+    BenchmarkDotNet.Artifacts/results/*.json \
+    "$OUTPUT_DIR/report.json"
+fi
 
 echo "Report written to $OUTPUT_DIR/report.json"
@@ -0,0 +1,507 @@
+// Package aggregate merges per-SDK and per-server result directories into the
+// dashboard's results.json. It is a port of scripts/aggregate.py and works on
+// generic JSON so that fields added by newer adapters pass through untouched.
+//
+// Tiered detection:
+//   - report.json present       -> SDK (library) benchmark result
+//   - conformance_summary.json  -> Server benchmark result
+package aggregate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Object is a decoded JSON object.
+type Object = map[string]interface{}
+
+// Results is the schema of the aggregated results.json.
+type Results struct {
+	GeneratedAt      string   `json:"generated_at"`
+	SDKBenchmarks    []Object `json:"sdk_benchmarks"`
+	ServerBenchmarks []Object `json:"server_benchmarks"`
+	// PlatformComparison is filled by merge --by-platform.
+	PlatformComparison []PlatformRow `json:"platform_comparison,omitempty"`
+}
+
+// ReadJSON returns the parsed JSON object at path, or nil if the file is
+// missing or malformed.
+func ReadJSON(path string) Object {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var obj Object
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil
+	}
+	return obj
+}
+
+// NormalizePipelineReport canonicalizes operation IDs in place and adds
+// non-breaking schema defaults. It returns the raw->canonical renames.
+func NormalizePipelineReport(rep Object) map[string]string {
+	opNameMap := make(map[string]string)
+	datasets, ok := rep["datasets"].(Object)
+	if !ok {
+		return opNameMap
+	}
+
+	for dsName, dsRaw := range datasets {
+		ds, ok := dsRaw.(Object)
+		if !ok {
+			continue
+		}
+		ops, ok := ds["operations"].(Object)
+		if !ok {
+			continue
+		}
+
+		normalized := make(Object)
+		for _, rawOp := range sortedKeys(ops) {
+			op, ok := ops[rawOp].(Object)
+			if !ok {
+				continue
+			}
+
+			opID, _ := op["operation_id"].(string)
+			if opID == "" {
+				opID = report.NormalizeOperationID(rawOp)
+			}
+			op["operation_id"] = opID
+			setDefault(op, "operation_track", report.InferOperationTrack(dsName, opID))
+			setDefault(op, "measurement_semantics", "mean_ns_per_operation")
+			setDefault(op, "failure_state", "ok")
+
+			// Backward-compatible fallback for legacy reports.
+			sampleCount, present := op["sample_count"]
+			if !present || sampleCount == nil {
+				sampleCount = op["iterations"]
+			}
+			op["sample_count"] = toInt(sampleCount)
+
+			existing, ok := normalized[opID].(Object)
+			if !ok || toInt(op["sample_count"]) > toInt(existing["sample_count"]) {
+				normalized[opID] = op
+			}
+			if rawOp != opID {
+				opNameMap[rawOp] = opID
+			}
+		}
+		ds["operations"] = normalized
+	}
+
+	if len(opNameMap) > 0 {
+		merged, _ := rep["operation_name_map"].(Object)
+		if merged == nil {
+			merged = make(Object)
+		}
+		for k, v := range opNameMap {
+			merged[k] = v
+		}
+		rep["operation_name_map"] = merged
+	}
+
+	// Reports before schema version 3 carry no metrics_manifest; describe
+	// their fields by units.json so every entry of results.json has one.
+	if _, ok := rep["metrics_manifest"]; !ok {
+		rep["metrics_manifest"] = report.DefaultUnits().Describe(rep)
+	}
+	return opNameMap
+}
+
+// DeriveCapabilities derives capability flags and strict core-track
+// eligibility (every core operation on every core dataset).
+func DeriveCapabilities(rep Object) (map[string]bool, bool) {
+	caps := map[string]bool{"core": false, "xml": false, "aasx": false, "validation": false}
+	datasets, ok := rep["datasets"].(Object)
+	if !ok {
+		return caps, false
+	}
+
+	eligible := true
+	for ds := range report.CoreDatasets {
+		dsObj, _ := datasets[ds].(Object)
+		ops, _ := dsObj["operations"].(Object)
+		for op := range report.CoreOperations {
+			if !measured(ops[op]) {
+				eligible = false
+			}
+		}
+	}
+
+	for dsName, dsRaw := range datasets {
+		ds, ok := dsRaw.(Object)
+		if !ok {
+			continue
+		}
+		ops, ok := ds["operations"].(Object)
+		if !ok {
+			continue
+		}
+		for opID, op := range ops {
+			if !measured(op) {
+				// A skipped operation was not run; it proves no capability.
+				continue
+			}
+			switch {
+			case report.CoreOperations[opID] && report.CoreDatasets[dsName]:
+				caps["core"] = true
+			case opID == "deserialize_xml" || opID == "serialize_xml" || opID == "deserialize_xml_nsheavy":
+				caps["xml"] = true
+			case opID == "aasx_extract" || opID == "aasx_repackage":
+				caps["aasx"] = true
+			case strings.HasPrefix(dsName, "val_") && report.ValidationOperations[opID]:
+				caps["validation"] = true
+			}
+		}
+	}
+	return caps, eligible
+}
+
+// measured reports whether op is an operation object with measurements,
+// not a skipped or failed placeholder.
+func measured(op interface{}) bool {
+	obj, ok := op.(Object)
+	if !ok {
+		return false
+	}
+	state, _ := obj["failure_state"].(string)
+	return state == "" || state == report.FailureOK
+}
+
+func buildSDKEntry(dir string, names map[string]string) Object {
+	rep := ReadJSON(filepath.Join(dir, "report.json"))
+	if rep == nil {
+		return nil
+	}
+	opNameMap := NormalizePipelineReport(rep)
+
+	sdkID, _ := rep["sdk_id"].(string)
+	if sdkID == "" {
+		sdkID = filepath.Base(dir)
+	}
+	name, ok := names[sdkID]
+	if !ok {
+		meta, _ := rep["metadata"].(Object)
+		if metaName, _ := meta["name"].(string); metaName != "" {
+			name = metaName
+		} else {
+			name = sdkID
+		}
+	}
+	caps, eligible := DeriveCapabilities(rep)
+
+	entry := Object{
+		"id":                  sdkID,
+		"name":                name,
+		"capabilities":        caps,
+		"core_track_eligible": eligible,
+	}
+	entry["platform"] = Platform(Object{"pipeline": rep})
+	if env := ReadJSON(filepath.Join(dir, "env.json")); env != nil {
+		entry["env"] = env
+	}
+	// The adapter contract checklist (aasbench contract), when the run
+	// produced one.
+	if checklist := ReadJSON(filepath.Join(dir, "checklist.json")); checklist != nil {
+		entry["contract"] = checklist
+	}
+	if headline := Headline(rep); len(headline) > 0 {
+		entry["headline"] = headline
+	}
+	// Store the full report so the dashboard can display language, runtime
+	// version, harness, and package version.
+	entry["pipeline"] = rep
+	if len(opNameMap) > 0 {
+		entry["operation_name_map"] = opNameMap
+	}
+	return entry
+}
+
+// Headline returns the report's headline block, selecting one from the
+// default catalog for adapters that do not write it.
+func Headline(rep Object) []report.HeadlineMetric {
+	var typed struct {
+		Headline []report.HeadlineMetric        `json:"headline"`
+		Datasets map[string]report.DatasetEntry `json:"datasets"`
+	}
+	data, err := json.Marshal(rep)
+	if err != nil || json.Unmarshal(data, &typed) != nil {
+		return nil
+	}
+	if len(typed.Headline) > 0 {
+		return typed.Headline
+	}
+	return report.DefaultCatalog().SelectHeadline(typed.Datasets)
+}
+
+func buildServerEntry(dir string, names map[string]string) Object {
+	id := filepath.Base(dir)
+	entry := Object{"id": id}
+
+	if env := ReadJSON(filepath.Join(dir, "env.json")); env != nil {
+		name, ok := names[id]
+		if !ok {
+			if envName, _ := env["sdk_name"].(string); envName != "" {
+				name = envName
+			} else {
+				name = id
+			}
+		}
+		entry["name"] = name
+		entry["env"] = env
+	} else if name, ok := names[id]; ok {
+		entry["name"] = name
+	} else {
+		entry["name"] = id
+	}
+
+	if conformance := ReadJSON(filepath.Join(dir, "conformance_summary.json")); conformance != nil {
+		entry["conformance"] = conformance
+	}
+	// server_images.json lists the container images ("repo:tag") the run
+	// started.
+	if images := ReadJSON(filepath.Join(dir, "server_images.json")); images != nil {
+		entry["images"] = images["images"]
+	}
+	// server_matrix.json is this server's slice of the run's pinned matrix:
+	// the digest and version behind every compose service's tag.
+	if pins := ReadJSON(filepath.Join(dir, "server_matrix.json")); pins != nil {
+		if services, ok := pins["services"]; ok {
+			entry["pinned_images"] = services
+		}
+	}
+	// warmup_<id>.json is the readiness gate's record: how long the server
+	// took to become healthy and to settle before the timed benchmarks.
+	if warmup := ReadJSON(filepath.Join(dir, fmt.Sprintf("warmup_%s.json", id))); warmup != nil {
+		entry["warmup"] = warmup
+	}
+	// k8s_metrics_<id>.json marks a run on the Kubernetes backend and holds
+	// the server containers' resources and sampled usage.
+	if kubernetes := ReadJSON(filepath.Join(dir, fmt.Sprintf("k8s_metrics_%s.json", id))); kubernetes != nil {
+		entry["kubernetes"] = kubernetes
+	}
+
+	// The concurrency sweep is a section of its own: capacity planning reads
+	// it per level, not as one more benchmark number.
+	if scalability := ReadJSON(filepath.Join(dir, fmt.Sprintf("k6_scalability_%s.json", id))); scalability != nil {
+		entry["scalability"] = scalability
+	}
+
+	benchmarks := Object{}
+	for _, out := range k6Outputs {
+		if data := ReadJSON(filepath.Join(dir, fmt.Sprintf(out.file, id))); data != nil {
+			benchmarks[out.key] = data
+		}
+	}
+	if len(benchmarks) > 0 {
+		entry["benchmarks"] = benchmarks
+	}
+	return entry
+}
+
+// k6Outputs maps each benchmarks key of a server entry to the file the k6
+// script of harness/k6 writes it to, or for eventing and opcua, the Python
+// clients of harness/eventing and harness/opcua.
+var k6Outputs = []struct{ key, file string }{
+	{"scenarios", "k6_summary_%s.json"},
+	{"crud", "k6_crud_%s.json"},
+	{"bulk_import", "k6_bulk_%s.json"},
+	{"pagination", "k6_pagination_%s.json"},
+	{"eventing", "eventing_%s.json"},
+	{"opcua", "opcua_%s.json"},
+	{"latency_histograms", "k6_histograms_%s.json"},
+	{"auth", "k6_auth_%s.json"},
+}
+
+// ComputeRegressions compares an SDK entry against the previous run's entry
+// with the same id and platform and returns the significant changes.
+func ComputeRegressions(current Object, previous map[string]Object) []compare.Delta {
+	prev, ok := previous[PlatformKey(current)]
+	if !ok {
+		return nil
+	}
+	currDatasets := pipelineDatasets(current)
+	prevDatasets := pipelineDatasets(prev)
+
+	var deltas []compare.Delta
+	for _, dsName := range sortedKeys(currDatasets) {
+		prevDS, ok := prevDatasets[dsName].(Object)
+		if !ok {
+			continue
+		}
+		currDS, _ := currDatasets[dsName].(Object)
+		currOps, _ := currDS["operations"].(Object)
+		prevOps, _ := prevDS["operations"].(Object)
+		for _, opName := range sortedKeys(currOps) {
+			prevOp, ok := prevOps[opName].(Object)
+			if !ok {
+				continue
+			}
+			currOp, _ := currOps[opName].(Object)
+			if currOp["mean_ns"] == nil || prevOp["mean_ns"] == nil {
+				continue
+			}
+			d, ok := compare.Operation(sampleOf(prevOp), sampleOf(currOp), compare.DefaultThresholdPct, compare.DefaultAlpha)
+			if !ok || !d.Significant {
+				continue
+			}
+			d.Dataset, d.Operation = dsName, opName
+			d.Namespace, _ = report.SplitOperationID(opName)
+			deltas = append(deltas, d)
+		}
+	}
+	return deltas
+}
+
+// BuildPreviousIndex builds a PlatformKey -> SDK entry map from a previous
+// results.json, normalizing each stored pipeline report.
+func BuildPreviousIndex(previous Object) map[string]Object {
+	index := make(map[string]Object)
+	sdks, _ := previous["sdk_benchmarks"].([]interface{})
+	for _, raw := range sdks {
+		sdk, ok := raw.(Object)
+		if !ok {
+			continue
+		}
+		if pipeline, ok := sdk["pipeline"].(Object); ok {
+			NormalizePipelineReport(pipeline)
+		}
+		if id, _ := sdk["id"].(string); id != "" {
+			index[PlatformKey(sdk)] = sdk
+		}
+	}
+	return index
+}
+
+// LoadNames loads the id->name mapping from known-sdks.json.
+func LoadNames(knownSDKs string) map[string]string {
+	names := make(map[string]string)
+	ks := ReadJSON(knownSDKs)
+	for _, key := range []string{"sdk_benchmarks", "server_benchmarks"} {
+		entries, _ := ks[key].([]interface{})
+		for _, raw := range entries {
+			entry, _ := raw.(Object)
+			id, _ := entry["id"].(string)
+			if id == "" {
+				continue
+			}
+			if name, _ := entry["name"].(string); name != "" {
+				names[id] = name
+			} else {
+				names[id] = id
+			}
+		}
+	}
+	return names
+}
+
+// Aggregate walks resultsDir and classifies each sub-directory as an SDK or
+// server result.
+func Aggregate(resultsDir, knownSDKs string) (sdks, servers []Object) {
+	sdks, servers = []Object{}, []Object{}
+	entries, err := os.ReadDir(resultsDir)
+	if err != nil {
+		return sdks, servers
+	}
+	names := LoadNames(knownSDKs)
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(resultsDir, e.Name())
+		// Detection: report.json -> SDK benchmark, otherwise server
+		if _, err := os.Stat(filepath.Join(dir, "report.json")); err == nil {
+			if entry := buildSDKEntry(dir, names); entry != nil {
+				sdks = append(sdks, entry)
+			}
+			continue
+		}
+		servers = append(servers, buildServerEntry(dir, names))
+	}
+	return sdks, servers
+}
+
+// Run aggregates resultsDir and, when previousPath is non-empty, attaches
+// regressions against the previous results.json to each SDK entry. It
+// returns the merged results and the number of flagged changes.
+func Run(resultsDir, knownSDKs, previousPath string) (*Results, int) {
+	sdks, servers := Aggregate(resultsDir, knownSDKs)
+
+	flagged := 0
+	if previousPath != "" {
+		if previous := ReadJSON(previousPath); previous != nil {
+			index := BuildPreviousIndex(previous)
+			for _, sdk := range sdks {
+				if regs := ComputeRegressions(sdk, index); len(regs) > 0 {
+					sdk["regressions"] = regs
+					flagged += len(regs)
+				}
+			}
+		}
+	}
+
+	return &Results{
+		GeneratedAt:      time.Now().UTC().Format(time.RFC3339Nano),
+		SDKBenchmarks:    sdks,
+		ServerBenchmarks: servers,
+	}, flagged
+}
+
+func pipelineDatasets(sdk Object) Object {
+	pipeline, _ := sdk["pipeline"].(Object)
+	datasets, _ := pipeline["datasets"].(Object)
+	return datasets
+}
+
+func sampleOf(op Object) compare.Sample {
+	n := toInt(op["iterations"])
+	if v, ok := op["sample_count"]; ok {
+		n = toInt(v)
+	}
+	return compare.Sample{
+		MeanNs:   toFloat(op["mean_ns"]),
+		StddevNs: toFloat(op["stddev_ns"]),
+		N:        n,
+	}
+}
+
+func setDefault(obj Object, key string, value interface{}) {
+	if _, ok := obj[key]; !ok {
+		obj[key] = value
+	}
+}
+
+// toFloat reads a JSON number; normalized reports also hold Go ints.
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case float64:
+		return n
+	case int:
+		return float64(n)
+	}
+	return 0
+}
+
+func toInt(v interface{}) int {
+	return int(toFloat(v))
+}
+
+func sortedKeys(m Object) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
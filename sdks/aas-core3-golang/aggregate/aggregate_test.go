@@ -0,0 +1,181 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func decode(t *testing.T, s string) Object {
+	t.Helper()
+	var obj Object
+	if err := json.Unmarshal([]byte(s), &obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+func TestNormalizePipelineReportCanonicalizesLegacyOps(t *testing.T) {
+	rep := decode(t, `{"datasets": {"wide": {"operations": {
+		"deserializeXml": {"iterations": 10, "mean_ns": 100, "stddev_ns": 10}
+	}}}}`)
+
+	opMap := NormalizePipelineReport(rep)
+	ops := rep["datasets"].(Object)["wide"].(Object)["operations"].(Object)
+	op, ok := ops["deserialize_xml"].(Object)
+	if !ok {
+		t.Fatalf("deserialize_xml missing from %v", ops)
+	}
+	if opMap["deserializeXml"] != "deserialize_xml" {
+		t.Errorf("op map = %v", opMap)
+	}
+	if op["operation_id"] != "deserialize_xml" {
+		t.Errorf("operation_id = %v", op["operation_id"])
+	}
+	if op["operation_track"] != "xml" {
+		t.Errorf("operation_track = %v", op["operation_track"])
+	}
+	if op["sample_count"] != 10 {
+		t.Errorf("sample_count = %v", op["sample_count"])
+	}
+	manifest, ok := rep["metrics_manifest"].(map[string]report.MetricSemantics)
+	if !ok {
+		t.Fatalf("a schema v2 report gained no metrics_manifest: %v", rep["metrics_manifest"])
+	}
+	for path, unit := range map[string]string{
+		"datasets.*.operations.*.mean_ns":      "ns",
+		"datasets.*.operations.*.sample_count": "count",
+	} {
+		if manifest[path].Unit != unit {
+			t.Errorf("metrics_manifest[%s] = %+v, want unit %s", path, manifest[path], unit)
+		}
+	}
+}
+
+func TestComputeRegressionsPrefersSampleCount(t *testing.T) {
+	op := func(mean int) string {
+		b, _ := json.Marshal(map[string]int{
+			"mean_ns": mean, "stddev_ns": 100, "iterations": 10000, "sample_count": 2,
+		})
+		return `{"id": "sdk-a", "pipeline": {"datasets": {"wide": {"operations": {"deserialize": ` +
+			string(b) + `}}}}}`
+	}
+	current := decode(t, op(110))
+	previous := map[string]Object{"sdk-a": decode(t, op(100))}
+
+	// With small sample_count and high variance, change should not be significant.
+	if regs := ComputeRegressions(current, previous); len(regs) != 0 {
+		t.Errorf("regressions = %+v, want none", regs)
+	}
+}
+
+func TestComputeRegressionsMatchesNamespacedOperations(t *testing.T) {
+	sdk := func(mean int) Object {
+		op := `{"mean_ns": ` + fmt.Sprint(mean) + `, "stddev_ns": 10, "sample_count": 5}`
+		obj := decode(t, `{"id": "sdk-a", "pipeline": {"datasets": {"wide": {"operations": {
+			"deserialize": `+op+`, "VendorX:bulkUpdate": `+op+`}}}}}`)
+		NormalizePipelineReport(obj["pipeline"].(Object))
+		return obj
+	}
+	previous := map[string]Object{"sdk-a": sdk(1000)}
+
+	regs := ComputeRegressions(sdk(2000), previous)
+	if len(regs) != 2 {
+		t.Fatalf("regressions = %+v, want 2", regs)
+	}
+	if regs[1].Operation != "vendorx:bulk_update" || regs[1].Namespace != "vendorx" {
+		t.Errorf("extension delta = %+v", regs[1])
+	}
+	if regs[0].Namespace != "" {
+		t.Errorf("canonical delta has namespace %q", regs[0].Namespace)
+	}
+}
+
+func TestDeriveCapabilitiesAndCoreEligibility(t *testing.T) {
+	coreOps := Object{}
+	for op := range report.CoreOperations {
+		coreOps[op] = Object{}
+	}
+	rep := Object{"datasets": Object{
+		"wide":       Object{"operations": coreOps},
+		"deep":       Object{"operations": coreOps},
+		"mixed":      Object{"operations": coreOps},
+		"val_regex":  Object{"operations": Object{"validate": Object{}}},
+		"wide_xml":   Object{"operations": Object{"deserialize_xml": Object{}}},
+		"aasx_small": Object{"operations": Object{"aasx_extract": Object{}}},
+	}}
+
+	caps, eligible := DeriveCapabilities(rep)
+	if !eligible {
+		t.Error("expected core-track eligibility")
+	}
+	for _, c := range []string{"core", "validation", "xml", "aasx"} {
+		if !caps[c] {
+			t.Errorf("capability %q not derived", c)
+		}
+	}
+}
+
+func TestValidationModesJoinValidationTrack(t *testing.T) {
+	rep := Object{"datasets": Object{
+		"val_regex": Object{"operations": Object{"validate_first_error": Object{}}},
+	}}
+	if caps, _ := DeriveCapabilities(rep); !caps["validation"] {
+		t.Error("validate_first_error on a val_ dataset should derive validation")
+	}
+	for _, op := range []string{"validate_first_error", "validate_collect_all"} {
+		if track := report.InferOperationTrack("val_regex", op); track != "validation" {
+			t.Errorf("track of %s = %q, want validation", op, track)
+		}
+		if track := report.InferOperationTrack("wide", op); track != "capability" {
+			t.Errorf("track of %s on wide = %q, want capability", op, track)
+		}
+	}
+}
+
+func TestMicroDatasetsFormMicroTrack(t *testing.T) {
+	if track := report.InferOperationTrack("micro_model_type", "enum_from_string"); track != "micro" {
+		t.Errorf("track = %q, want micro", track)
+	}
+	if caps, eligible := DeriveCapabilities(Object{"datasets": Object{
+		"micro_lang_string": Object{"operations": Object{"lang_string_lookup": Object{}}},
+	}}); eligible || caps["core"] {
+		t.Errorf("micro operations alone derived %v (eligible %v)", caps, eligible)
+	}
+}
+
+func TestI18nDatasetFormsI18nTrack(t *testing.T) {
+	if track := report.InferOperationTrack("i18n", "deserialize"); track != "i18n" {
+		t.Errorf("track = %q, want i18n", track)
+	}
+	if track := report.InferOperationTrack("i18n", "deserialize_xml"); track != "xml" {
+		t.Errorf("track of deserialize_xml = %q, want xml", track)
+	}
+}
+
+func TestDeriveCapabilitiesIgnoresSkippedOperations(t *testing.T) {
+	coreOps := Object{}
+	for op := range report.CoreOperations {
+		coreOps[op] = Object{"failure_state": report.FailureOK}
+	}
+	skipped := Object{}
+	for op := range report.CoreOperations {
+		skipped[op] = Object{"failure_state": report.FailureSkippedMissingDataset}
+	}
+	rep := Object{"datasets": Object{
+		"wide":     Object{"operations": skipped},
+		"deep":     Object{"operations": coreOps},
+		"mixed":    Object{"operations": coreOps},
+		"wide_xml": Object{"operations": Object{"deserialize_xml": Object{"failure_state": report.FailureSkippedMissingDataset}}},
+	}}
+
+	caps, eligible := DeriveCapabilities(rep)
+	if eligible {
+		t.Error("core-track eligibility derived from skipped operations")
+	}
+	if caps["xml"] {
+		t.Error("xml capability derived from a skipped operation")
+	}
+}
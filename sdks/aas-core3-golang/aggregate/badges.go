@@ -0,0 +1,94 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Badge is a shields.io endpoint badge
+// (https://shields.io/badges/endpoint-badge).
+type Badge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// LoadResults reads an aggregated results.json.
+func LoadResults(path string) (*Results, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var results Results
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, err
+	}
+	return &results, nil
+}
+
+// WriteBadges writes shields.io endpoint badges for every SDK and returns
+// how many it wrote: one per headline metric to dir/<sdk id>/<metric
+// id>.json, and one per measured operation with its mean time, e.g.
+// "deserialize wide: 1.2 ms", to dir/<sdk id>/<dataset>/<operation>.json.
+func WriteBadges(results *Results, dir string) (int, error) {
+	written := 0
+	write := func(path string, b Badge) error {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		data, err := json.Marshal(b)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			return err
+		}
+		written++
+		return nil
+	}
+
+	for _, sdk := range results.SDKBenchmarks {
+		id, _ := sdk["id"].(string)
+		if id == "" {
+			continue
+		}
+		sdkDir := filepath.Join(dir, id)
+		pipeline, _ := sdk["pipeline"].(Object)
+		headline, ok := sdk["headline"].([]report.HeadlineMetric)
+		if !ok && pipeline != nil {
+			// Decoded from results.json rather than built by Run.
+			headline = Headline(Object{"headline": sdk["headline"], "datasets": pipeline["datasets"]})
+		}
+		for _, h := range headline {
+			if err := write(filepath.Join(sdkDir, h.ID+".json"), Badge{SchemaVersion: 1, Label: h.Label, Message: h.Display(), Color: "blue"}); err != nil {
+				return written, err
+			}
+		}
+
+		datasets, _ := pipeline["datasets"].(Object)
+		for _, ds := range sortedKeys(datasets) {
+			dsEntry, _ := datasets[ds].(Object)
+			ops, _ := dsEntry["operations"].(Object)
+			for _, op := range sortedKeys(ops) {
+				entry, _ := ops[op].(Object)
+				mean := toFloat(entry["mean_ns"])
+				if !measured(ops[op]) || mean <= 0 {
+					continue
+				}
+				// Extension operations are namespaced with a colon, which
+				// does not belong in a URL path.
+				name := strings.ReplaceAll(op, report.NamespaceSeparator, "-")
+				badge := Badge{SchemaVersion: 1, Label: op + " " + ds, Message: report.FormatValue(mean, "ns"), Color: "blue"}
+				if err := write(filepath.Join(sdkDir, ds, name+".json"), badge); err != nil {
+					return written, err
+				}
+			}
+		}
+	}
+	return written, nil
+}
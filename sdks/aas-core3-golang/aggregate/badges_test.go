@@ -0,0 +1,55 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteBadgesFromResultsJSON(t *testing.T) {
+	dir := t.TempDir()
+	resultsPath := filepath.Join(dir, "results.json")
+	if err := os.WriteFile(resultsPath, []byte(`{"sdk_benchmarks": [{
+		"id": "aas-core3-golang",
+		"pipeline": {"datasets": {"wide": {"operations": {
+			"deserialize": {"mean_ns": 1234567, "failure_state": "ok"},
+			"validate": {"mean_ns": 0, "failure_state": "unsupported"},
+			"acme:roundtrip": {"mean_ns": 950}
+		}}}}
+	}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	results, err := LoadResults(resultsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := filepath.Join(dir, "badges")
+	if _, err := WriteBadges(results, out); err != nil {
+		t.Fatal(err)
+	}
+	read := func(rel string) Badge {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(out, "aas-core3-golang", rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b Badge
+		if err := json.Unmarshal(data, &b); err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	b := read("wide/deserialize.json")
+	if b.SchemaVersion != 1 || b.Label != "deserialize wide" || b.Message != "1.23 ms" {
+		t.Errorf("deserialize badge = %+v", b)
+	}
+	if b := read("wide/acme-roundtrip.json"); b.Message != "950 ns" {
+		t.Errorf("extension badge = %+v", b)
+	}
+	if _, err := os.Stat(filepath.Join(out, "aas-core3-golang", "wide", "validate.json")); !os.IsNotExist(err) {
+		t.Errorf("badge written for an unsupported operation: %v", err)
+	}
+}
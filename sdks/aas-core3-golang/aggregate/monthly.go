@@ -0,0 +1,278 @@
+package aggregate
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// DefaultMonthlyTop is how many improvements and regressions a monthly
+// report lists.
+const DefaultMonthlyTop = 5
+
+// Snapshot is one stored results.json.
+type Snapshot struct {
+	Path        string
+	GeneratedAt time.Time
+	Results     Object
+}
+
+// LoadSnapshot reads a stored results.json. Its generated_at places it in
+// time, so a file without one is rejected.
+func LoadSnapshot(path string) (Snapshot, error) {
+	results := ReadJSON(path)
+	if results == nil {
+		return Snapshot{}, fmt.Errorf("%s: not a readable results.json", path)
+	}
+	stamp, _ := results["generated_at"].(string)
+	at, err := time.Parse(time.RFC3339Nano, stamp)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("%s: no valid generated_at (%q)", path, stamp)
+	}
+	return Snapshot{Path: path, GeneratedAt: at.UTC(), Results: results}, nil
+}
+
+// MonthlyChange is a significant change of one SDK operation over the
+// period.
+type MonthlyChange struct {
+	SDKID   string `json:"sdk_id"`
+	SDKName string `json:"sdk_name"`
+	compare.Delta
+}
+
+// ServerTag is a server image benchmarked for the first time in the period.
+type ServerTag struct {
+	ServerID  string `json:"server_id"`
+	Name      string `json:"name"`
+	Image     string `json:"image"`
+	FirstSeen string `json:"first_seen"`
+}
+
+// CoverageGap lists what an SDK or server is missing from the latest run:
+// core operations that were not measured, declared capabilities without a
+// measured operation, or the whole run for an enabled entry of
+// known-sdks.json.
+type CoverageGap struct {
+	Kind    string   `json:"kind"`
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Missing []string `json:"missing"`
+}
+
+// Monthly is the "state of AAS performance" summary of one period.
+type Monthly struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Runs counts the snapshots generated within the period.
+	Runs int `json:"runs"`
+	// Baseline and Latest are the generated_at of the snapshots compared.
+	Baseline      string          `json:"baseline"`
+	Latest        string          `json:"latest"`
+	SDKs          int             `json:"sdks"`
+	Servers       int             `json:"servers"`
+	Improvements  []MonthlyChange `json:"improvements"`
+	Regressions   []MonthlyChange `json:"regressions"`
+	NewServerTags []ServerTag     `json:"new_server_tags"`
+	CoverageGaps  []CoverageGap   `json:"coverage_gaps"`
+}
+
+// BuildMonthly summarizes the snapshots generated in [from, to). Changes
+// are measured from the last snapshot before from (or, without one, the
+// first in the period) to the last in the period, and the top of each
+// direction kept; coverage gaps are those of the last snapshot. known is
+// the parsed known-sdks.json, or nil.
+func BuildMonthly(snapshots []Snapshot, from, to time.Time, known Object, top int) (*Monthly, error) {
+	sorted := append([]Snapshot(nil), snapshots...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].GeneratedAt.Before(sorted[j].GeneratedAt) })
+
+	var baseline *Snapshot
+	var period []Snapshot
+	for i := range sorted {
+		at := sorted[i].GeneratedAt
+		switch {
+		case at.Before(from):
+			baseline = &sorted[i]
+		case at.Before(to):
+			period = append(period, sorted[i])
+		}
+	}
+	if len(period) == 0 {
+		return nil, fmt.Errorf("no results generated between %s and %s", from.Format(time.DateOnly), to.Format(time.DateOnly))
+	}
+	if baseline == nil {
+		baseline = &period[0]
+	}
+	latest := period[len(period)-1]
+
+	m := &Monthly{
+		From:          from,
+		To:            to,
+		Runs:          len(period),
+		Baseline:      baseline.GeneratedAt.Format(time.RFC3339),
+		Latest:        latest.GeneratedAt.Format(time.RFC3339),
+		Improvements:  []MonthlyChange{},
+		Regressions:   []MonthlyChange{},
+		NewServerTags: []ServerTag{},
+		CoverageGaps:  []CoverageGap{},
+	}
+
+	sdks := entries(latest.Results, "sdk_benchmarks")
+	servers := entries(latest.Results, "server_benchmarks")
+	m.SDKs, m.Servers = len(sdks), len(servers)
+
+	previous := BuildPreviousIndex(baseline.Results)
+	for _, sdk := range sdks {
+		if pipeline, ok := sdk["pipeline"].(Object); ok {
+			NormalizePipelineReport(pipeline)
+		}
+		id, _ := sdk["id"].(string)
+		name, _ := sdk["name"].(string)
+		for _, d := range ComputeRegressions(sdk, previous) {
+			c := MonthlyChange{SDKID: id, SDKName: name, Delta: d}
+			if d.Direction == compare.Regression {
+				m.Regressions = append(m.Regressions, c)
+			} else {
+				m.Improvements = append(m.Improvements, c)
+			}
+		}
+	}
+	// Largest relative change first.
+	sort.SliceStable(m.Regressions, func(i, j int) bool { return m.Regressions[i].ChangePct > m.Regressions[j].ChangePct })
+	sort.SliceStable(m.Improvements, func(i, j int) bool { return m.Improvements[i].ChangePct < m.Improvements[j].ChangePct })
+	if top >= 0 && len(m.Regressions) > top {
+		m.Regressions = m.Regressions[:top]
+	}
+	if top >= 0 && len(m.Improvements) > top {
+		m.Improvements = m.Improvements[:top]
+	}
+
+	m.NewServerTags = newServerTags(baseline, period)
+	m.CoverageGaps = coverageGaps(sdks, servers, known)
+	return m, nil
+}
+
+// entries returns the objects of a results.json list.
+func entries(results Object, key string) []Object {
+	raw, _ := results[key].([]interface{})
+	out := make([]Object, 0, len(raw))
+	for _, r := range raw {
+		if obj, ok := r.(Object); ok {
+			out = append(out, obj)
+		}
+	}
+	return out
+}
+
+// serverImages returns the images a server entry ran.
+func serverImages(server Object) []string {
+	raw, _ := server["images"].([]interface{})
+	var images []string
+	for _, r := range raw {
+		if s, ok := r.(string); ok && s != "" {
+			images = append(images, s)
+		}
+	}
+	return images
+}
+
+// newServerTags lists the images first run in the period: those not in the
+// baseline snapshot nor in an earlier snapshot of the period.
+func newServerTags(baseline *Snapshot, period []Snapshot) []ServerTag {
+	seen := make(map[string]bool)
+	for _, server := range entries(baseline.Results, "server_benchmarks") {
+		id, _ := server["id"].(string)
+		for _, image := range serverImages(server) {
+			seen[id+"\x00"+image] = true
+		}
+	}
+	tags := []ServerTag{}
+	for _, snap := range period {
+		for _, server := range entries(snap.Results, "server_benchmarks") {
+			id, _ := server["id"].(string)
+			name, _ := server["name"].(string)
+			for _, image := range serverImages(server) {
+				if seen[id+"\x00"+image] {
+					continue
+				}
+				seen[id+"\x00"+image] = true
+				tags = append(tags, ServerTag{ServerID: id, Name: name, Image: image, FirstSeen: snap.GeneratedAt.Format(time.RFC3339)})
+			}
+		}
+	}
+	return tags
+}
+
+// coverageGaps lists what each SDK did not measure in the latest run (core
+// operations, and capabilities known-sdks.json declares), and the enabled
+// entries of known-sdks.json without results at all.
+func coverageGaps(sdks, servers []Object, known Object) []CoverageGap {
+	gaps := []CoverageGap{}
+	present := make(map[string]bool)
+	for _, server := range servers {
+		id, _ := server["id"].(string)
+		present[id] = true
+	}
+
+	// Capabilities count as gaps only where known-sdks.json declares them.
+	declared := make(map[string]Object)
+	for _, entry := range entries(known, "sdk_benchmarks") {
+		id, _ := entry["id"].(string)
+		declared[id], _ = entry["capabilities"].(Object)
+	}
+
+	coreDatasets := sortedSet(report.CoreDatasets)
+	coreOps := sortedSet(report.CoreOperations)
+	for _, sdk := range sdks {
+		id, _ := sdk["id"].(string)
+		name, _ := sdk["name"].(string)
+		present[id] = true
+		datasets := pipelineDatasets(sdk)
+		var missing []string
+		for _, op := range coreOps {
+			for _, ds := range coreDatasets {
+				dsObj, _ := datasets[ds].(Object)
+				ops, _ := dsObj["operations"].(Object)
+				if !measured(ops[op]) {
+					missing = append(missing, op+" on "+ds)
+				}
+			}
+		}
+		caps, _ := sdk["capabilities"].(Object)
+		for _, c := range sortedKeys(declared[id]) {
+			if declared[id][c] == true && caps[c] != true {
+				missing = append(missing, c+" capability")
+			}
+		}
+		if len(missing) > 0 {
+			gaps = append(gaps, CoverageGap{Kind: "sdk", ID: id, Name: name, Missing: missing})
+		}
+	}
+
+	for _, key := range []string{"sdk_benchmarks", "server_benchmarks"} {
+		kind := "sdk"
+		if key == "server_benchmarks" {
+			kind = "server"
+		}
+		for _, entry := range entries(known, key) {
+			id, _ := entry["id"].(string)
+			if enabled, _ := entry["enabled"].(bool); !enabled || id == "" || present[id] {
+				continue
+			}
+			name, _ := entry["name"].(string)
+			gaps = append(gaps, CoverageGap{Kind: kind, ID: id, Name: name, Missing: []string{"no results"}})
+		}
+	}
+	return gaps
+}
+
+func sortedSet(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
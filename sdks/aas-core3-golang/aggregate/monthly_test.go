@@ -0,0 +1,77 @@
+package aggregate
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// monthlySnapshot is a results.json with one SDK measuring deserialize on
+// wide and one server running image.
+func monthlySnapshot(t *testing.T, at string, meanNs int, image string) Snapshot {
+	t.Helper()
+	results := decode(t, fmt.Sprintf(`{
+		"generated_at": %q,
+		"sdk_benchmarks": [{"id": "go", "name": "Go", "capabilities": {"xml": false},
+			"pipeline": {"datasets": {"wide": {"operations": {
+				"deserialize": {"mean_ns": %d, "stddev_ns": 10, "sample_count": 10}
+			}}}}}],
+		"server_benchmarks": [{"id": "faaast", "name": "FA3ST", "images": [%q]}]
+	}`, at, meanNs, image))
+	generated, err := time.Parse(time.RFC3339, at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Snapshot{GeneratedAt: generated, Results: results}
+}
+
+func TestBuildMonthlyComparesAgainstPreviousMonth(t *testing.T) {
+	snapshots := []Snapshot{
+		monthlySnapshot(t, "2026-10-20T03:00:00Z", 700, "faaast:1.3.0"),
+		monthlySnapshot(t, "2026-09-01T03:00:00Z", 1000, "faaast:1.2.0"),
+		monthlySnapshot(t, "2026-10-01T03:00:00Z", 1000, "faaast:1.2.0"),
+		monthlySnapshot(t, "2026-11-01T03:00:00Z", 2000, "faaast:1.4.0"),
+	}
+	known := decode(t, `{"sdk_benchmarks": [
+		{"id": "go", "enabled": true, "capabilities": {"xml": true, "aasx": false}},
+		{"id": "rust", "name": "Rust", "enabled": true},
+		{"id": "csharp", "enabled": false}
+	]}`)
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	m, err := BuildMonthly(snapshots, from, from.AddDate(0, 1, 0), known, DefaultMonthlyTop)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Runs != 2 || m.Baseline != "2026-09-01T03:00:00Z" || m.Latest != "2026-10-20T03:00:00Z" {
+		t.Errorf("runs %d, baseline %s, latest %s", m.Runs, m.Baseline, m.Latest)
+	}
+	if len(m.Regressions) != 0 || len(m.Improvements) != 1 || m.Improvements[0].CurrentMeanNs != 700 {
+		t.Errorf("improvements %+v, regressions %+v", m.Improvements, m.Regressions)
+	}
+	if len(m.NewServerTags) != 1 || m.NewServerTags[0].Image != "faaast:1.3.0" {
+		t.Errorf("new server tags = %+v", m.NewServerTags)
+	}
+
+	gaps := map[string]CoverageGap{}
+	for _, g := range m.CoverageGaps {
+		gaps[g.ID] = g
+	}
+	if len(gaps) != 2 {
+		t.Fatalf("coverage gaps = %+v, want go and rust", m.CoverageGaps)
+	}
+	if missing := gaps["go"].Missing; missing[len(missing)-1] != "xml capability" {
+		t.Errorf("go gaps = %v", missing)
+	}
+	if missing := gaps["rust"].Missing; len(missing) != 1 || missing[0] != "no results" {
+		t.Errorf("rust gaps = %v", missing)
+	}
+}
+
+func TestBuildMonthlyWithoutRunsInPeriod(t *testing.T) {
+	snapshots := []Snapshot{monthlySnapshot(t, "2026-09-01T03:00:00Z", 1000, "faaast:1.2.0")}
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := BuildMonthly(snapshots, from, from.AddDate(0, 1, 0), nil, DefaultMonthlyTop); err == nil {
+		t.Error("no error for a month without runs")
+	}
+}
@@ -0,0 +1,108 @@
+package aggregate
+
+import (
+	"math"
+	"sort"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// PlatformRow compares one SDK's operation across the platforms it ran on.
+type PlatformRow struct {
+	SDKID     string `json:"sdk_id"`
+	Dataset   string `json:"dataset"`
+	Operation string `json:"operation"`
+	// Platforms maps "os/arch" to the mean ns/op measured there.
+	Platforms map[string]float64 `json:"platforms"`
+	// Baseline is the platform the others are relative to: the default CI
+	// platform or, without it, the first platform by name.
+	Baseline string `json:"baseline"`
+	// Relative maps each platform to its mean over the baseline's.
+	Relative map[string]float64 `json:"relative"`
+}
+
+// Platform returns the platform an SDK entry was measured on, from its
+// report metadata.
+func Platform(sdk Object) string {
+	pipeline, _ := sdk["pipeline"].(Object)
+	meta, _ := pipeline["metadata"].(Object)
+	if p, _ := meta["platform"].(string); p != "" {
+		return p
+	}
+	return report.DefaultPlatform
+}
+
+// PlatformKey identifies an SDK entry across runs: its id on the default
+// platform, "id@os/arch" elsewhere, so that regressions are never computed
+// between platforms.
+func PlatformKey(sdk Object) string {
+	id, _ := sdk["id"].(string)
+	if p := Platform(sdk); p != report.DefaultPlatform {
+		return id + "@" + p
+	}
+	return id
+}
+
+// PlatformComparison lists every operation an SDK ran on more than one
+// platform, sorted by SDK, dataset and operation.
+func PlatformComparison(sdks []Object) []PlatformRow {
+	type key struct{ sdk, dataset, operation string }
+	means := make(map[key]map[string]float64)
+	for _, sdk := range sdks {
+		id, _ := sdk["id"].(string)
+		platform := Platform(sdk)
+		datasets := pipelineDatasets(sdk)
+		for _, dsName := range sortedKeys(datasets) {
+			ds, _ := datasets[dsName].(Object)
+			ops, _ := ds["operations"].(Object)
+			for _, opName := range sortedKeys(ops) {
+				op, _ := ops[opName].(Object)
+				if op["mean_ns"] == nil {
+					continue
+				}
+				k := key{id, dsName, opName}
+				if means[k] == nil {
+					means[k] = make(map[string]float64)
+				}
+				means[k][platform] = toFloat(op["mean_ns"])
+			}
+		}
+	}
+
+	rows := []PlatformRow{}
+	for k, platforms := range means {
+		if len(platforms) < 2 {
+			continue
+		}
+		baseline := report.DefaultPlatform
+		if _, ok := platforms[baseline]; !ok {
+			names := make([]string, 0, len(platforms))
+			for p := range platforms {
+				names = append(names, p)
+			}
+			sort.Strings(names)
+			baseline = names[0]
+		}
+		relative := make(map[string]float64, len(platforms))
+		for p, mean := range platforms {
+			if base := platforms[baseline]; base > 0 {
+				relative[p] = math.Round(mean/base*1000) / 1000
+			}
+		}
+		rows = append(rows, PlatformRow{
+			SDKID: k.sdk, Dataset: k.dataset, Operation: k.operation,
+			Platforms: platforms, Baseline: baseline, Relative: relative,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if a.SDKID != b.SDKID {
+			return a.SDKID < b.SDKID
+		}
+		if a.Dataset != b.Dataset {
+			return a.Dataset < b.Dataset
+		}
+		return a.Operation < b.Operation
+	})
+	return rows
+}
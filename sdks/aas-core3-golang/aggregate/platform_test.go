@@ -0,0 +1,61 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func platformEntry(t *testing.T, platform string, mean int) Object {
+	t.Helper()
+	meta := map[string]string{}
+	if platform != "" {
+		meta["platform"] = platform
+	}
+	b, _ := json.Marshal(map[string]interface{}{
+		"id": "sdk-a",
+		"pipeline": map[string]interface{}{
+			"metadata": meta,
+			"datasets": map[string]interface{}{"wide": map[string]interface{}{"operations": map[string]interface{}{
+				"deserialize": map[string]int{"mean_ns": mean, "stddev_ns": 1, "sample_count": 50},
+			}}},
+		},
+	})
+	return decode(t, string(b))
+}
+
+func TestRegressionsStayOnOnePlatform(t *testing.T) {
+	x86 := platformEntry(t, "", 100)
+	arm := platformEntry(t, "linux/arm64", 300)
+	if PlatformKey(x86) != "sdk-a" || PlatformKey(arm) != "sdk-a@linux/arm64" {
+		t.Fatalf("keys = %q, %q", PlatformKey(x86), PlatformKey(arm))
+	}
+
+	index := BuildPreviousIndex(Object{"sdk_benchmarks": []interface{}{x86}})
+	if regs := ComputeRegressions(arm, index); len(regs) != 0 {
+		t.Errorf("arm64 compared against amd64: %+v", regs)
+	}
+	if regs := ComputeRegressions(platformEntry(t, "linux/amd64", 300), index); len(regs) != 1 {
+		t.Errorf("amd64 regressions = %+v, want one", regs)
+	}
+}
+
+func TestPlatformComparison(t *testing.T) {
+	rows := PlatformComparison([]Object{
+		platformEntry(t, "linux/arm64", 300),
+		platformEntry(t, "", 100),
+	})
+	if len(rows) != 1 {
+		t.Fatalf("rows = %+v, want one", rows)
+	}
+	row := rows[0]
+	if row.Baseline != "linux/amd64" || row.Platforms["linux/arm64"] != 300 {
+		t.Errorf("row = %+v", row)
+	}
+	if row.Relative["linux/arm64"] != 3 || row.Relative["linux/amd64"] != 1 {
+		t.Errorf("relative = %v", row.Relative)
+	}
+
+	if rows := PlatformComparison([]Object{platformEntry(t, "", 100)}); len(rows) != 0 {
+		t.Errorf("single platform rows = %+v, want none", rows)
+	}
+}
@@ -0,0 +1,258 @@
+package aggregate
+
+import (
+	"fmt"
+	"html"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Page kinds of the static site, also the directories their pages are
+// written to.
+const (
+	SiteTracks   = "tracks"
+	SiteDatasets = "datasets"
+	SiteSDKs     = "sdks"
+)
+
+// SiteBar is one bar of a chart: the mean time of an SDK or an operation.
+type SiteBar struct {
+	Label  string  `json:"label"`
+	MeanNs float64 `json:"mean_ns"`
+}
+
+// Display formats the bar's mean time.
+func (b SiteBar) Display() string {
+	return report.FormatValue(b.MeanNs, "ns")
+}
+
+// SiteChart is a bar chart of mean times, fastest first, written to File
+// (relative to the site root) as SVG.
+type SiteChart struct {
+	Title string    `json:"title"`
+	File  string    `json:"file"`
+	Bars  []SiteBar `json:"bars"`
+}
+
+// SitePage is one page of the static site: a track, a dataset or an SDK
+// with its charts.
+type SitePage struct {
+	Kind   string      `json:"kind"`
+	Key    string      `json:"key"`
+	Title  string      `json:"title"`
+	Path   string      `json:"path"`
+	Charts []SiteChart `json:"charts"`
+}
+
+// Site is the static comparison site built from a results.json.
+type Site struct {
+	GeneratedAt string     `json:"generated_at"`
+	Tracks      []SitePage `json:"tracks"`
+	Datasets    []SitePage `json:"datasets"`
+	SDKs        []SitePage `json:"sdks"`
+}
+
+// Pages returns every page of the site, tracks first.
+func (s *Site) Pages() []SitePage {
+	pages := append([]SitePage(nil), s.Tracks...)
+	pages = append(pages, s.Datasets...)
+	return append(pages, s.SDKs...)
+}
+
+// siteResult is one measured operation of one SDK entry.
+type siteResult struct {
+	sdk, dataset, operation, track string
+	meanNs                         float64
+}
+
+// BuildSite lays out the static site of results: one page per track and
+// per dataset comparing the SDKs on every operation, and one page per SDK
+// comparing its operations on every dataset. An SDK that ran on several
+// platforms is one SDK per platform. Skipped and failed operations are
+// left out.
+func BuildSite(results *Results) *Site {
+	var measurements []siteResult
+	labels := sdkLabels(results.SDKBenchmarks)
+	for i, sdk := range results.SDKBenchmarks {
+		datasets := pipelineDatasets(sdk)
+		for _, ds := range sortedKeys(datasets) {
+			dsEntry, _ := datasets[ds].(Object)
+			ops, _ := dsEntry["operations"].(Object)
+			for _, op := range sortedKeys(ops) {
+				entry, _ := ops[op].(Object)
+				mean := toFloat(entry["mean_ns"])
+				if !measured(ops[op]) || mean <= 0 {
+					continue
+				}
+				track, _ := entry["operation_track"].(string)
+				if track == "" {
+					track = report.InferOperationTrack(ds, op)
+				}
+				measurements = append(measurements, siteResult{labels[i], ds, op, track, mean})
+			}
+		}
+	}
+
+	site := &Site{GeneratedAt: results.GeneratedAt}
+	// Tracks and datasets compare the SDKs per dataset and operation.
+	byTrack := groupSite(measurements, func(m siteResult) string { return m.track })
+	for _, track := range sortedGroupKeys(byTrack) {
+		page := newSitePage(SiteTracks, track, "Track: "+track)
+		page.Charts = siteCharts(page, byTrack[track],
+			func(m siteResult) string { return m.dataset + " / " + m.operation },
+			func(m siteResult) string { return m.sdk })
+		site.Tracks = append(site.Tracks, page)
+	}
+	byDataset := groupSite(measurements, func(m siteResult) string { return m.dataset })
+	for _, ds := range sortedGroupKeys(byDataset) {
+		page := newSitePage(SiteDatasets, ds, "Dataset: "+ds)
+		page.Charts = siteCharts(page, byDataset[ds],
+			func(m siteResult) string { return m.operation },
+			func(m siteResult) string { return m.sdk })
+		site.Datasets = append(site.Datasets, page)
+	}
+	// An SDK's page compares its operations per dataset.
+	bySDK := groupSite(measurements, func(m siteResult) string { return m.sdk })
+	for _, sdk := range sortedGroupKeys(bySDK) {
+		page := newSitePage(SiteSDKs, sdk, "SDK: "+sdk)
+		page.Charts = siteCharts(page, bySDK[sdk],
+			func(m siteResult) string { return m.dataset },
+			func(m siteResult) string { return m.operation })
+		site.SDKs = append(site.SDKs, page)
+	}
+	return site
+}
+
+// sdkLabels names every SDK entry for the site: its name, with the
+// platform added when several entries share the ID.
+func sdkLabels(sdks []Object) []string {
+	count := make(map[string]int)
+	for _, sdk := range sdks {
+		id, _ := sdk["id"].(string)
+		count[id]++
+	}
+	labels := make([]string, len(sdks))
+	for i, sdk := range sdks {
+		id, _ := sdk["id"].(string)
+		name, _ := sdk["name"].(string)
+		if name == "" {
+			name = id
+		}
+		if count[id] > 1 {
+			name += " (" + Platform(sdk) + ")"
+		}
+		labels[i] = name
+	}
+	return labels
+}
+
+func newSitePage(kind, key, title string) SitePage {
+	return SitePage{Kind: kind, Key: key, Title: title, Path: path.Join(kind, siteSlug(key)+".html")}
+}
+
+// siteCharts makes one chart per chart key of the measurements, with one
+// bar per bar key.
+func siteCharts(page SitePage, measurements []siteResult, chartKey, barKey func(siteResult) string) []SiteChart {
+	byChart := groupSite(measurements, chartKey)
+	var charts []SiteChart
+	for _, title := range sortedGroupKeys(byChart) {
+		chart := SiteChart{
+			Title: title,
+			File:  path.Join("charts", page.Kind, siteSlug(page.Key), siteSlug(title)+".svg"),
+		}
+		for _, m := range byChart[title] {
+			chart.Bars = append(chart.Bars, SiteBar{Label: barKey(m), MeanNs: m.meanNs})
+		}
+		sort.SliceStable(chart.Bars, func(i, j int) bool {
+			if chart.Bars[i].MeanNs != chart.Bars[j].MeanNs {
+				return chart.Bars[i].MeanNs < chart.Bars[j].MeanNs
+			}
+			return chart.Bars[i].Label < chart.Bars[j].Label
+		})
+		charts = append(charts, chart)
+	}
+	return charts
+}
+
+func groupSite(measurements []siteResult, key func(siteResult) string) map[string][]siteResult {
+	groups := make(map[string][]siteResult)
+	for _, m := range measurements {
+		groups[key(m)] = append(groups[key(m)], m)
+	}
+	return groups
+}
+
+func sortedGroupKeys(groups map[string][]siteResult) []string {
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// siteSlug makes a file name of a page or chart key: lower case, with
+// runs of anything but letters, digits, dots and underscores replaced by
+// a dash.
+func siteSlug(key string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(key) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '.' || r == '_' {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// Dimensions of a chart, in pixels.
+const (
+	chartWidth       = 720
+	chartLabelWidth  = 220
+	chartValueWidth  = 80
+	chartBarHeight   = 20
+	chartBarGap      = 6
+	chartTitleHeight = 28
+)
+
+// SVG renders the chart as a standalone horizontal bar chart, the bars
+// scaled to the slowest.
+func (c SiteChart) SVG() string {
+	height := chartTitleHeight + len(c.Bars)*(chartBarHeight+chartBarGap) + chartBarGap
+	maxNs := 0.0
+	for _, bar := range c.Bars {
+		maxNs = max(maxNs, bar.MeanNs)
+	}
+	span := float64(chartWidth - chartLabelWidth - chartValueWidth)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="system-ui, sans-serif" font-size="12">`+"\n",
+		chartWidth, height, chartWidth, height)
+	fmt.Fprintf(&b, `<title>%s</title>`+"\n", html.EscapeString(c.Title))
+	fmt.Fprintf(&b, `<text x="0" y="18" font-size="14" font-weight="600" fill="#1f2328">%s</text>`+"\n", html.EscapeString(c.Title))
+	for i, bar := range c.Bars {
+		y := chartTitleHeight + i*(chartBarHeight+chartBarGap)
+		width := 0.0
+		if maxNs > 0 {
+			width = max(bar.MeanNs/maxNs*span, 1)
+		}
+		textY := y + chartBarHeight/2 + 4
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="end" fill="#1f2328">%s</text>`+"\n",
+			chartLabelWidth-8, textY, html.EscapeString(bar.Label))
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%.1f" height="%d" fill="#0969da"/>`+"\n",
+			chartLabelWidth, y, width, chartBarHeight)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" fill="#57606a">%s</text>`+"\n",
+			float64(chartLabelWidth)+width+6, textY, html.EscapeString(bar.Display()))
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
@@ -0,0 +1,72 @@
+package aggregate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestBuildSiteComparesSDKsPerTrackDatasetAndSDK(t *testing.T) {
+	var results Results
+	if err := json.Unmarshal([]byte(`{"generated_at": "2026-10-01T00:00:00Z", "sdk_benchmarks": [
+		{"id": "aas-core3-golang", "name": "Go", "pipeline": {"datasets": {
+			"wide": {"operations": {
+				"deserialize": {"mean_ns": 2000, "operation_track": "core"},
+				"validate": {"mean_ns": 0, "failure_state": "unsupported"}
+			}},
+			"val_broken": {"operations": {"validate": {"mean_ns": 500, "operation_track": "validation"}}}
+		}}},
+		{"id": "aas-core3-python", "name": "Python <3>", "pipeline": {"datasets": {
+			"wide": {"operations": {"deserialize": {"mean_ns": 9000, "operation_track": "core"}}}
+		}}}
+	]}`), &results); err != nil {
+		t.Fatal(err)
+	}
+	site := BuildSite(&results)
+
+	if len(site.Tracks) != 2 || site.Tracks[0].Key != "core" || site.Tracks[1].Key != "validation" {
+		t.Fatalf("tracks = %+v", site.Tracks)
+	}
+	core := site.Tracks[0]
+	if core.Path != "tracks/core.html" || len(core.Charts) != 1 {
+		t.Fatalf("core page = %+v", core)
+	}
+	chart := core.Charts[0]
+	if chart.Title != "wide / deserialize" || chart.File != "charts/tracks/core/wide-deserialize.svg" {
+		t.Errorf("core chart = %+v", chart)
+	}
+	if len(chart.Bars) != 2 || chart.Bars[0].Label != "Go" || chart.Bars[1].Label != "Python <3>" {
+		t.Errorf("bars not fastest first: %+v", chart.Bars)
+	}
+	if len(site.Datasets) != 2 || site.Datasets[1].Key != "wide" || len(site.Datasets[1].Charts) != 1 {
+		t.Errorf("the unsupported validate on wide was charted: %+v", site.Datasets)
+	}
+	if len(site.SDKs) != 2 || site.SDKs[0].Key != "Go" || len(site.SDKs[0].Charts) != 2 {
+		t.Errorf("SDK pages = %+v", site.SDKs)
+	}
+	if got := site.SDKs[1].Path; got != "sdks/python-3.html" {
+		t.Errorf("SDK page path = %q", got)
+	}
+
+	svg := chart.SVG()
+	for _, want := range []string{`<svg xmlns="http://www.w3.org/2000/svg"`, "Python &lt;3&gt;", "9 µs", `width="420.0"`} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG lacks %q:\n%s", want, svg)
+		}
+	}
+}
+
+func TestSiteLabelsPlatformsOfOneSDKApart(t *testing.T) {
+	labels := sdkLabels([]Object{
+		{"id": "go", "name": "Go"},
+		{"id": "go", "name": "Go", "pipeline": Object{"metadata": Object{"platform": "linux/arm64"}}},
+		{"id": "py", "name": "Python"},
+	})
+	want := []string{"Go (linux/amd64)", "Go (linux/arm64)", "Python"}
+	for i := range want {
+		if labels[i] != want[i] {
+			t.Errorf("labels = %q, want %q", labels, want)
+			break
+		}
+	}
+}
@@ -2,12 +2,17 @@ package main
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/xml"
+	"flag"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"runtime"
+	"runtime/metrics"
+	"sort"
 	"strings"
 	"testing"
 
@@ -15,9 +20,12 @@ import (
 	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
 	aasverification "github.com/aas-core-works/aas-core3.0-golang/verification"
 	aasxml "github.com/aas-core-works/aas-core3.0-golang/xmlization"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/logging"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
 )
 
-// memorySnapshot captures a single ReadMemStats measurement.
+// memorySnapshot is one sampled read of the runtime's memory metrics.
 type memorySnapshot struct {
 	HeapAllocBytes  uint64 `json:"heap_alloc_bytes"`
 	HeapSysBytes    uint64 `json:"heap_sys_bytes"`
@@ -31,59 +39,164 @@ type memoryStatsFile struct {
 	Before memorySnapshot            `json:"before"`
 	After  memorySnapshot            `json:"after"`
 	Groups map[string]memorySnapshot `json:"groups"`
+	// Peaks is the peak intermediate allocation of operations that probe
+	// it, by "operation/dataset".
+	Peaks map[string]uint64 `json:"peaks,omitempty"`
+}
+
+// memoryCapture is false when MEMORY_STATS=0 (aasbench run
+// --memory-stats=false): pure-latency runs then neither sample memory nor
+// write memory_stats.json and gc_pauses.json.
+var memoryCapture = os.Getenv("MEMORY_STATS") != "0"
+
+// memorySamples are the runtime/metrics read by captureMemSnapshot, in the
+// order it consumes them.
+var memorySamples = []metrics.Sample{
+	{Name: "/gc/heap/live:bytes"},
+	{Name: "/memory/classes/heap/objects:bytes"},
+	{Name: "/memory/classes/heap/unused:bytes"},
+	{Name: "/memory/classes/heap/free:bytes"},
+	{Name: "/memory/classes/heap/released:bytes"},
+	{Name: "/gc/heap/allocs:bytes"},
+	{Name: "/gc/cycles/total:gc-cycles"},
+	{Name: "/cpu/classes/gc/pause:cpu-seconds"},
 }
 
-// captureMemSnapshot reads runtime.MemStats and returns a snapshot.
+// captureMemSnapshot samples runtime/metrics. Unlike ReadMemStats the read
+// does not stop the world, and no GC is forced: the heap figure is the live
+// heap marked by the last cycle, and the pause total is the runtime's
+// estimate of GC pause CPU time divided by GOMAXPROCS. Benchmarks run
+// sequentially, so the shared sample slice is not raced.
 func captureMemSnapshot() memorySnapshot {
-	runtime.GC() // force GC so HeapAlloc is more accurate
-	var m runtime.MemStats
-	runtime.ReadMemStats(&m)
+	if !memoryCapture {
+		return memorySnapshot{}
+	}
+	defer globalStages.time(report.StageMemoryCapture, "", "")()
+	metrics.Read(memorySamples)
+	v := make([]uint64, len(memorySamples))
+	for i, s := range memorySamples {
+		switch s.Value.Kind() {
+		case metrics.KindUint64:
+			v[i] = s.Value.Uint64()
+		case metrics.KindFloat64:
+			v[i] = uint64(s.Value.Float64() * 1e9 / float64(runtime.GOMAXPROCS(0)))
+		}
+	}
 	return memorySnapshot{
-		HeapAllocBytes:  m.HeapAlloc,
-		HeapSysBytes:    m.HeapSys,
-		TotalAllocBytes: m.TotalAlloc,
-		NumGC:           m.NumGC,
-		PauseTotalNs:    m.PauseTotalNs,
+		HeapAllocBytes:  v[0],
+		HeapSysBytes:    v[1] + v[2] + v[3] + v[4],
+		TotalAllocBytes: v[5],
+		NumGC:           uint32(v[6]),
+		PauseTotalNs:    v[7],
 	}
 }
 
 // globalMemStats accumulates per-group snapshots written at the end.
 var globalMemStats = memoryStatsFile{
 	Groups: make(map[string]memorySnapshot),
+	Peaks:  make(map[string]uint64),
 }
 
-// datasetFiles returns the list of JSON dataset files from DATASETS_DIR.
-func datasetFiles(b *testing.B) []string {
+// blobHeavyDataset carries Blobs of 1-50 MB base64 (datasets/generate.py
+// --blob-heavy). Only deserialize and serialize measure it: on it every
+// other operation would time base64 handling instead of what it is named
+// for.
+const blobHeavyDataset = "blob_heavy"
+
+// datasetFiles returns the list of JSON dataset files from DATASETS_DIR,
+// without the blob-heavy dataset.
+func datasetFiles(b testing.TB) []string {
 	b.Helper()
-	dir := os.Getenv("DATASETS_DIR")
-	if dir == "" {
-		b.Skip("DATASETS_DIR not set")
-	}
+	dir := datasetsDir(b)
 	matches, err := filepath.Glob(filepath.Join(dir, "*.json"))
 	if err != nil {
 		b.Fatalf("Failed to glob datasets: %v", err)
 	}
-	if len(matches) == 0 {
-		b.Skipf("No JSON files found in %s", dir)
+	files := matches[:0]
+	for _, f := range matches {
+		if datasetName(f) != blobHeavyDataset && globalSelection.dataset(datasetName(f)) {
+			files = append(files, f)
+		}
+	}
+	if len(files) == 0 {
+		b.Skipf("No selected JSON files found in %s", dir)
+	}
+	return files
+}
+
+// payloadDatasetFiles returns datasetFiles plus the blob-heavy dataset
+// when DATASETS_DIR has it.
+func payloadDatasetFiles(b testing.TB) []string {
+	b.Helper()
+	files := datasetFiles(b)
+	path := filepath.Join(datasetsDir(b), blobHeavyDataset+".json")
+	if _, err := os.Stat(path); err == nil && globalSelection.dataset(blobHeavyDataset) {
+		files = append(files, path)
 	}
-	return matches
+	return files
 }
 
-// datasetXmlFiles returns the list of XML dataset files from DATASETS_DIR.
-func datasetXmlFiles(b *testing.B) []string {
+// xmlDataset is one XML benchmark input.
+type xmlDataset struct {
+	name string
+	raw  []byte
+}
+
+// datasetXmlInputs returns one XML input per dataset in DATASETS_DIR. An
+// .xml fixture is used when present; datasets provided only as JSON are
+// converted (JSON -> env -> XML bytes) during setup so that the XML
+// operations are benchmarked for every dataset but the blob-heavy one.
+func datasetXmlInputs(b testing.TB) []xmlDataset {
 	b.Helper()
-	dir := os.Getenv("DATASETS_DIR")
-	if dir == "" {
-		b.Skip("DATASETS_DIR not set")
+	dir := datasetsDir(b)
+	jsonFiles, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		b.Fatalf("Failed to glob datasets: %v", err)
 	}
-	matches, err := filepath.Glob(filepath.Join(dir, "*.xml"))
+	xmlFiles, err := filepath.Glob(filepath.Join(dir, "*.xml"))
 	if err != nil {
 		b.Fatalf("Failed to glob XML datasets: %v", err)
 	}
-	if len(matches) == 0 {
-		b.Skipf("No XML files found in %s", dir)
+
+	sources := make(map[string]string)
+	for _, f := range jsonFiles {
+		if datasetName(f) != blobHeavyDataset && globalSelection.dataset(datasetName(f)) {
+			sources[datasetName(f)] = f
+		}
+	}
+	for _, f := range xmlFiles {
+		if globalSelection.dataset(datasetName(f)) {
+			sources[datasetName(f)] = f // an XML fixture wins over conversion
+		}
+	}
+	if len(sources) == 0 {
+		b.Skipf("No selected JSON or XML files found in %s", dir)
+	}
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
 	}
-	return matches
+	sort.Strings(names)
+
+	inputs := make([]xmlDataset, 0, len(names))
+	for _, name := range names {
+		f := sources[name]
+		if filepath.Ext(f) == ".xml" {
+			inputs = append(inputs, xmlDataset{name: name, raw: loadRawXML(b, f)})
+			continue
+		}
+		env, err := deserializeEnv(loadRawJSON(b, f))
+		if err != nil {
+			b.Fatalf("Setup failed converting %s to XML: %v", name, err)
+		}
+		raw, err := serializeXmlEnv(env)
+		if err != nil {
+			b.Fatalf("Setup failed converting %s to XML: %v", name, err)
+		}
+		slog.Info("derived XML input", "dataset", name, "source", filepath.Base(f))
+		inputs = append(inputs, xmlDataset{name: name, raw: raw})
+	}
+	return inputs
 }
 
 // datasetName extracts the dataset name from a file path (e.g. "wide" from "/path/wide.json").
@@ -93,8 +206,9 @@ func datasetName(path string) string {
 }
 
 // loadRawJSON reads a dataset file and returns its raw bytes.
-func loadRawJSON(b *testing.B, path string) []byte {
+func loadRawJSON(b testing.TB, path string) []byte {
 	b.Helper()
+	defer globalStages.time(report.StageDatasetLoad, "", datasetName(path))()
 	data, err := os.ReadFile(path)
 	if err != nil {
 		b.Fatalf("Failed to read %s: %v", path, err)
@@ -103,8 +217,9 @@ func loadRawJSON(b *testing.B, path string) []byte {
 }
 
 // loadRawXML reads an XML dataset file and returns its raw bytes.
-func loadRawXML(b *testing.B, path string) []byte {
+func loadRawXML(b testing.TB, path string) []byte {
 	b.Helper()
+	defer globalStages.time(report.StageDatasetLoad, "", datasetName(path))()
 	data, err := os.ReadFile(path)
 	if err != nil {
 		b.Fatalf("Failed to read XML %s: %v", path, err)
@@ -152,37 +267,30 @@ func deserializeXmlEnv(raw []byte) (aastypes.IEnvironment, error) {
 	return env, nil
 }
 
-// BenchmarkDeserialize benchmarks JSON -> AAS Environment deserialization.
-func BenchmarkDeserialize(b *testing.B) {
-	before := captureMemSnapshot()
-	files := datasetFiles(b)
-	for _, f := range files {
-		name := datasetName(f)
-		raw := loadRawJSON(b, f)
-		b.Run(name, func(b *testing.B) {
-			b.ResetTimer()
-			for i := 0; i < b.N; i++ {
-				env, err := deserializeEnv(raw)
-				if err != nil {
-					b.Fatal(err)
-				}
-				_ = env
-			}
-		})
+// serializeXmlEnv marshals an AAS Environment to XML bytes.
+func serializeXmlEnv(env aastypes.IEnvironment) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := xml.NewEncoder(&buf)
+	if err := aasxml.Marshal(encoder, env, true); err != nil {
+		return nil, fmt.Errorf("xml marshal: %w", err)
 	}
-	after := captureMemSnapshot()
-	globalMemStats.Groups["deserialize"] = after
-	_ = before
+	if err := encoder.Flush(); err != nil {
+		return nil, fmt.Errorf("xml marshal: %w", err)
+	}
+	return buf.Bytes(), nil
 }
 
+// BenchmarkDeserialize benchmarks JSON -> AAS Environment deserialization,
+// including base64 decoding on the blob-heavy dataset.
+func BenchmarkDeserialize(b *testing.B) { benchmarkOperation(b, "deserialize") }
+
 // BenchmarkDeserializeXml benchmarks XML -> AAS Environment deserialization.
 func BenchmarkDeserializeXml(b *testing.B) {
+	selectOperation(b, "deserialize_xml")
 	before := captureMemSnapshot()
-	files := datasetXmlFiles(b)
-	for _, f := range files {
-		name := datasetName(f)
-		raw := loadRawXML(b, f)
-		b.Run(name, func(b *testing.B) {
+	for _, in := range datasetXmlInputs(b) {
+		raw := in.raw
+		runObserved(b, "deserialize_xml", in.name, func(b *testing.B) {
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
 				env, err := deserializeXmlEnv(raw)
@@ -195,155 +303,98 @@ func BenchmarkDeserializeXml(b *testing.B) {
 	}
 	after := captureMemSnapshot()
 	globalMemStats.Groups["deserialize_xml"] = after
+	globalHeap.writeProfile("deserialize_xml")
 	_ = before
 }
 
 // BenchmarkValidate benchmarks verification of a deserialized AAS Environment.
-func BenchmarkValidate(b *testing.B) {
-	before := captureMemSnapshot()
-	files := datasetFiles(b)
-	for _, f := range files {
+func BenchmarkValidate(b *testing.B) { benchmarkOperation(b, "validate") }
+
+// BenchmarkValidateFirstError benchmarks verification that stops at the
+// first violation, the cost of a pass/fail check. On a valid environment it
+// still walks everything, so it differs from validate only where a dataset
+// has violations.
+func BenchmarkValidateFirstError(b *testing.B) {
+	selectOperation(b, "validate_first_error")
+	for _, f := range datasetFiles(b) {
 		name := datasetName(f)
-		raw := loadRawJSON(b, f)
-		env, err := deserializeEnv(raw)
+		env, err := deserializeEnv(loadRawJSON(b, f))
 		if err != nil {
 			b.Fatalf("Setup failed for %s: %v", name, err)
 		}
-		b.Run(name, func(b *testing.B) {
+		runObserved(b, "validate_first_error", name, func(b *testing.B) {
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				errorCount := 0
-				aasverification.Verify(env, func(_ *aasverification.VerificationError) bool {
-					errorCount++
-					return false // continue verification
+				var first *aasverification.VerificationError
+				aasverification.Verify(env, func(verr *aasverification.VerificationError) bool {
+					first = verr
+					return true // abort verification
 				})
-				_ = errorCount
+				_ = first
 			}
 		})
 	}
-	after := captureMemSnapshot()
-	globalMemStats.Groups["validate"] = after
-	_ = before
+	globalMemStats.Groups["validate_first_error"] = captureMemSnapshot()
+	globalHeap.writeProfile("validate_first_error")
 }
 
-// BenchmarkTraverse benchmarks descending through all nodes in an AAS Environment.
-func BenchmarkTraverse(b *testing.B) {
-	before := captureMemSnapshot()
-	files := datasetFiles(b)
-	for _, f := range files {
+// BenchmarkValidateCollectAll benchmarks verification that keeps every
+// violation, the cost of a full validation report. Unlike validate, which
+// only counts them, the errors are retained.
+func BenchmarkValidateCollectAll(b *testing.B) {
+	selectOperation(b, "validate_collect_all")
+	for _, f := range datasetFiles(b) {
 		name := datasetName(f)
-		raw := loadRawJSON(b, f)
-		env, err := deserializeEnv(raw)
+		env, err := deserializeEnv(loadRawJSON(b, f))
 		if err != nil {
 			b.Fatalf("Setup failed for %s: %v", name, err)
 		}
-		b.Run(name, func(b *testing.B) {
+		runObserved(b, "validate_collect_all", name, func(b *testing.B) {
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
-				count := 0
-				env.Descend(func(_ aastypes.IClass) bool {
-					count++
-					return false // continue descending
+				var errs []*aasverification.VerificationError
+				aasverification.Verify(env, func(verr *aasverification.VerificationError) bool {
+					errs = append(errs, verr)
+					return false // continue verification
 				})
-				_ = count
+				_ = errs
 			}
 		})
 	}
-	after := captureMemSnapshot()
-	globalMemStats.Groups["traverse"] = after
-	_ = before
+	globalMemStats.Groups["validate_collect_all"] = captureMemSnapshot()
+	globalHeap.writeProfile("validate_collect_all")
 }
 
-// BenchmarkUpdate benchmarks finding all Property instances and updating their values.
-func BenchmarkUpdate(b *testing.B) {
-	before := captureMemSnapshot()
-	files := datasetFiles(b)
-	for _, f := range files {
-		name := datasetName(f)
-		raw := loadRawJSON(b, f)
-		env, err := deserializeEnv(raw)
-		if err != nil {
-			b.Fatalf("Setup failed for %s: %v", name, err)
-		}
-		b.Run(name, func(b *testing.B) {
-			b.ResetTimer()
-			for i := 0; i < b.N; i++ {
-				touchedProps := make([]aastypes.IProperty, 0, 128)
-				originalVals := make([]string, 0, 128)
-				count := 0
-				env.Descend(func(node aastypes.IClass) bool {
-					if prop, ok := node.(aastypes.IProperty); ok {
-						val := prop.Value()
-						if val != nil {
-							original := *val
-							updated := original + "_updated"
-							prop.SetValue(&updated)
-							touchedProps = append(touchedProps, prop)
-							originalVals = append(originalVals, original)
-							count++
-						}
-					}
-					return false // continue descending
-				})
+// BenchmarkTraverse benchmarks descending through all nodes in an AAS Environment.
+func BenchmarkTraverse(b *testing.B) { benchmarkOperation(b, "traverse") }
 
-				// Restore baseline state so every iteration starts identically.
-				for idx, prop := range touchedProps {
-					original := originalVals[idx]
-					prop.SetValue(&original)
-				}
-				_ = count
-			}
-		})
-	}
-	after := captureMemSnapshot()
-	globalMemStats.Groups["update"] = after
-	_ = before
-}
+// BenchmarkUpdate benchmarks finding all Property instances and updating their values.
+func BenchmarkUpdate(b *testing.B) { benchmarkOperation(b, "update") }
 
-// BenchmarkSerialize benchmarks AAS Environment -> JSON serialization.
-func BenchmarkSerialize(b *testing.B) {
-	before := captureMemSnapshot()
-	files := datasetFiles(b)
-	for _, f := range files {
-		name := datasetName(f)
-		raw := loadRawJSON(b, f)
-		env, err := deserializeEnv(raw)
-		if err != nil {
-			b.Fatalf("Setup failed for %s: %v", name, err)
-		}
-		b.Run(name, func(b *testing.B) {
-			b.ResetTimer()
-			for i := 0; i < b.N; i++ {
-				jsonable, serErr := aas.ToJsonable(env)
-				if serErr != nil {
-					b.Fatal(serErr)
-				}
-				data, marshalErr := json.Marshal(jsonable)
-				if marshalErr != nil {
-					b.Fatal(marshalErr)
-				}
-				_ = data
-			}
-		})
-	}
-	after := captureMemSnapshot()
-	globalMemStats.Groups["serialize"] = after
-	_ = before
-}
+// BenchmarkSerialize benchmarks AAS Environment -> JSON serialization,
+// including base64 encoding on the blob-heavy dataset.
+func BenchmarkSerialize(b *testing.B) { benchmarkOperation(b, "serialize") }
 
 // BenchmarkSerializeXml benchmarks AAS Environment -> XML serialization.
 func BenchmarkSerializeXml(b *testing.B) {
+	selectOperation(b, "serialize_xml")
 	before := captureMemSnapshot()
-	files := datasetXmlFiles(b)
-	for _, f := range files {
-		name := datasetName(f)
-		// Load XML, deserialize to env, then re-serialize to XML
-		raw := loadRawXML(b, f)
-		env, err := deserializeXmlEnv(raw)
+	for _, in := range datasetXmlInputs(b) {
+		// Deserialize the XML input to env, then re-serialize to XML
+		env, err := deserializeXmlEnv(in.raw)
 		if err != nil {
-			b.Fatalf("Setup failed for XML %s: %v", name, err)
+			b.Fatalf("Setup failed for XML %s: %v", in.name, err)
 		}
-		b.Run(name, func(b *testing.B) {
+		if globalSelection.operation("serialize_xml") && globalSelection.dataset(in.name) {
+			doc, err := serializeXmlEnv(env)
+			if err == nil {
+				err = globalOutputSizes.record("serialize_xml", in.name, doc)
+			}
+			if err != nil {
+				b.Fatalf("Output size failed for XML %s: %v", in.name, err)
+			}
+		}
+		runObserved(b, "serialize_xml", in.name, func(b *testing.B) {
 			b.ResetTimer()
 			for i := 0; i < b.N; i++ {
 				var buf bytes.Buffer
@@ -358,35 +409,111 @@ func BenchmarkSerializeXml(b *testing.B) {
 	}
 	after := captureMemSnapshot()
 	globalMemStats.Groups["serialize_xml"] = after
+	globalHeap.writeProfile("serialize_xml")
 	_ = before
 }
 
-// TestMain runs after all benchmarks and writes memory_stats.json.
+// sideChannelShim prints side channels to stdout instead of writing them,
+// for aasbench run --wasm to extract from the go test output.
+var sideChannelShim = os.Getenv("SIDE_CHANNEL_SHIM") != ""
+
+// writeSideChannel marshals v into OUTPUT_DIR/name. Failures only warn so
+// that benchmark results are never lost to a side-channel problem.
+func writeSideChannel(outputDir, name string, v interface{}) {
+	path := filepath.Join(outputDir, name)
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		slog.Warn("failed to marshal side channel", "file", name, "err", err)
+		return
+	}
+	if sideChannelShim {
+		fmt.Printf("%s%s %s\n", report.SideChannelShimPrefix, name, base64.StdEncoding.EncodeToString(data))
+		return
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		slog.Warn("failed to create output dir", "err", err)
+		return
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		slog.Warn("failed to write side channel", "file", name, "err", err)
+		return
+	}
+	slog.Info("wrote side channel", "file", name, "path", path)
+}
+
+// TestMain logs like the aasbench process that started it and, after all
+// benchmarks, writes memory_stats.json, gc_pauses.json, events.json,
+// build_info.json, control.json, skipped.json, operations.json,
+// output_sizes.json when a serializing operation ran, robustness.json
+// when deserialize_invalid ran or FUZZ_CORPUS was probed and, with
+// HEAP_PROFILE, PERF_COUNTERS, ENERGY or SOAK_DURATION set,
+// heap_hotspots.json, hardware_counters.json, energy.json or soak.json.
 func TestMain(m *testing.M) {
+	if os.Getenv(startupProbeEnv) != "" {
+		os.Exit(startupProbe())
+	}
+	logging.FromEnv()
+	flag.Parse()
+	selection, err := newSelection(*operationsFlag, *datasetsFlag)
+	if err != nil {
+		slog.Error("invalid selection", "err", err)
+		os.Exit(2)
+	}
+	globalSelection = selection
+	scheduling, err := applyScheduling()
+	if err != nil {
+		slog.Error("scheduling failed", "err", err)
+		os.Exit(1)
+	}
+
+	globalPartial.reset()
+
 	// Capture overall "before" snapshot
 	globalMemStats.Before = captureMemSnapshot()
+	globalEvents.Start()
 
 	// Run all tests and benchmarks
 	exitCode := m.Run()
+	removeMiniDatasets()
 
 	// Capture overall "after" snapshot
+	globalEvents.Stop()
 	globalMemStats.After = captureMemSnapshot()
 
-	// Write memory_stats.json to OUTPUT_DIR if set
-	outputDir := os.Getenv("OUTPUT_DIR")
-	if outputDir != "" {
-		memPath := filepath.Join(outputDir, "memory_stats.json")
-		data, err := json.MarshalIndent(globalMemStats, "", "  ")
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to marshal memory stats: %v\n", err)
-		} else {
-			if err := os.MkdirAll(outputDir, 0755); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to create output dir: %v\n", err)
-			} else if err := os.WriteFile(memPath, data, 0644); err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to write memory stats: %v\n", err)
-			} else {
-				fmt.Fprintf(os.Stderr, "Wrote memory stats to %s\n", memPath)
-			}
+	// Write side-channel files to OUTPUT_DIR if set
+	if outputDir := os.Getenv("OUTPUT_DIR"); outputDir != "" {
+		// After the snapshots, so that hung decoders burden nothing else.
+		globalFuzz.probeCorpus()
+		if soak := globalSoak.run(); soak != nil {
+			writeSideChannel(outputDir, "soak.json", soak)
+		}
+		if memoryCapture {
+			writeSideChannel(outputDir, "memory_stats.json", globalMemStats)
+			writeSideChannel(outputDir, "gc_pauses.json", globalGCPauses.snapshot())
+		}
+		writeSideChannel(outputDir, "events.json", globalEvents.snapshot())
+		writeSideChannel(outputDir, "build_info.json", collectBuildInfo())
+		writeSideChannel(outputDir, "scheduling.json", scheduling)
+		writeSideChannel(outputDir, "skipped.json", globalSkipped.snapshot())
+		writeSideChannel(outputDir, "operations.json", registeredOperations())
+		writeSideChannel(outputDir, "stages.json", globalStages.snapshot())
+		if sizes := globalOutputSizes.snapshot(); len(sizes) > 0 {
+			writeSideChannel(outputDir, "output_sizes.json", sizes)
+		}
+		if f := globalRobustness.snapshot(); len(f.Entries) > 0 {
+			writeSideChannel(outputDir, "robustness.json", f)
+		}
+		if globalControl.enabled {
+			writeSideChannel(outputDir, "control.json", globalControl.snapshot())
+		}
+		if globalHeap.enabled {
+			writeSideChannel(outputDir, "heap_hotspots.json", globalHeap.hotspots())
+		}
+		if globalPerf.enabled {
+			writeSideChannel(outputDir, "hardware_counters.json", globalPerf.snapshot())
+		}
+		if globalEnergy.enabled {
+			writeSideChannel(outputDir, "energy.json", globalEnergy.snapshot())
 		}
 	}
 
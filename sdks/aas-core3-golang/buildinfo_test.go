@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os/exec"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// sdkModule is the module whose version a report is about, unless another
+// SDK adapter is built in.
+const sdkModule = "github.com/aas-core-works/aas-core3.0-golang"
+
+// stdModule is the Module of an SDK adapter built on the standard library
+// alone. No dependency is linked for it, so the version recorded is the
+// toolchain's, which the standard library ships with.
+const stdModule = "std"
+
+// buildInfoFile is the schema of build_info.json: the toolchain and SDK
+// version actually linked into this test binary, so -modfile pins and
+// replace directives are reported as built, plus the observatory commit.
+type buildInfoFile struct {
+	// SDKID is the sdk_id of the SDK adapter built in.
+	SDKID      string `json:"sdk_id,omitempty"`
+	GoVersion  string `json:"go_version"`
+	SDKModule  string `json:"sdk_module"`
+	SDKVersion string `json:"sdk_version"`
+	SDKSum     string `json:"sdk_sum,omitempty"`
+	GitCommit  string `json:"git_commit,omitempty"`
+	GitDirty   *bool  `json:"git_dirty,omitempty"`
+	// Platform is the GOOS/GOARCH the binary was built for, which is not
+	// the host's when it runs under a WebAssembly runtime.
+	Platform string `json:"platform,omitempty"`
+}
+
+func collectBuildInfo() buildInfoFile {
+	info := buildInfoFile{SDKID: sdkAdapter.SDKID(), SDKModule: sdkAdapter.Module(), Platform: runtime.GOOS + "/" + runtime.GOARCH}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		if info.SDKModule == stdModule {
+			info.SDKVersion = bi.GoVersion
+		}
+		for _, dep := range bi.Deps {
+			if dep.Path != info.SDKModule {
+				continue
+			}
+			info.SDKVersion, info.SDKSum = dep.Version, dep.Sum
+			if r := dep.Replace; r != nil {
+				info.SDKVersion, info.SDKSum = r.Version, r.Sum
+				if r.Version == "" {
+					info.SDKVersion = "replaced by " + r.Path
+				}
+			}
+		}
+	}
+	// Test binaries carry no vcs.* build settings, so ask git directly. The
+	// working directory of a test is its package directory.
+	if out, err := exec.Command("git", "rev-parse", "HEAD").Output(); err == nil {
+		info.GitCommit = strings.TrimSpace(string(out))
+		if out, err := exec.Command("git", "status", "--porcelain").Output(); err == nil {
+			dirty := len(strings.TrimSpace(string(out))) > 0
+			info.GitDirty = &dirty
+		}
+	}
+	return info
+}
@@ -0,0 +1,30 @@
+// Command baseline is the sdk program without the SDK: the same JSON round
+// trip from stdin to stdout, so that its size is what any such Go program
+// costs and the difference is the SDK's share. It is never run.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+func main() {
+	raw, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fail(err)
+	}
+	var jsonable interface{}
+	if err := json.Unmarshal(raw, &jsonable); err != nil {
+		fail(err)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(jsonable); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
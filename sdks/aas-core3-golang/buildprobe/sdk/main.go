@@ -0,0 +1,46 @@
+// Command sdk is the minimal program using the SDK whose compile time and
+// binary size aasbench run records in build_metrics: it reads an
+// environment as JSON on stdin, verifies it and writes it back to stdout.
+// It is never run.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	aas "github.com/aas-core-works/aas-core3.0-golang/jsonization"
+	aasverification "github.com/aas-core-works/aas-core3.0-golang/verification"
+)
+
+func main() {
+	raw, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fail(err)
+	}
+	var jsonable interface{}
+	if err := json.Unmarshal(raw, &jsonable); err != nil {
+		fail(err)
+	}
+	env, deserErr := aas.EnvironmentFromJsonable(jsonable)
+	if deserErr != nil {
+		fail(deserErr)
+	}
+	aasverification.Verify(env, func(verr *aasverification.VerificationError) bool {
+		fmt.Fprintln(os.Stderr, verr.Error())
+		return false
+	})
+	out, err := aas.ToJsonable(env)
+	if err != nil {
+		fail(err)
+	}
+	if err := json.NewEncoder(os.Stdout).Encode(out); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// noop is the function the noop_call calibration calls.
+//
+//go:noinline
+func noop() {}
+
+// calibrationSink keeps the compiler from eliminating the unmarshal result.
+var calibrationSink interface{}
+
+// BenchmarkCalibration measures what the harness costs per iteration
+// without the SDK: an empty loop, a noop call, and parsing every dataset
+// into an interface{}. emit-report lists the results under the report's
+// calibration section. It is not an operation, so -operations does not
+// deselect it; datasets do restrict the unmarshal workload.
+func BenchmarkCalibration(b *testing.B) {
+	b.Run(report.CalibrationEmptyLoop, func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+		}
+	})
+	b.Run(report.CalibrationNoopCall, func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			noop()
+		}
+	})
+	for _, f := range datasetFiles(b) {
+		raw := loadRawJSON(b, f)
+		b.Run(report.CalibrationUnmarshalAnyPrefix+datasetName(f), func(b *testing.B) {
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				var v interface{}
+				if err := json.Unmarshal(raw, &v); err != nil {
+					b.Fatal(err)
+				}
+				calibrationSink = v
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	aas "github.com/aas-core-works/aas-core3.0-golang/jsonization"
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+)
+
+// canonicalize writes the environment as RFC 8785 (JCS) canonical JSON,
+// the form AAS signing profiles sign: keys sorted by UTF-16 code units,
+// no whitespace, minimal string escaping and ECMAScript number formatting.
+// Comparing it with serialize shows what deterministic output costs.
+func init() {
+	registerOperation(benchOperation{
+		ID: "canonicalize", Benchmark: "Canonicalize", Needs: []string{"deserialize"}, Payload: true,
+		Setup: setupCanonicalize,
+		Run: func(input interface{}) error {
+			_, err := canonicalJSON(input.(aastypes.IEnvironment))
+			return err
+		},
+		Output: func(input interface{}) ([]byte, error) {
+			return canonicalJSON(input.(aastypes.IEnvironment))
+		},
+	})
+}
+
+// setupCanonicalize deserializes a dataset and checks that its canonical
+// JSON holds the document serialize marshals.
+func setupCanonicalize(raw []byte) (interface{}, error) {
+	env, err := deserializeEnv(raw)
+	if err != nil {
+		return nil, err
+	}
+	jsonable, err := aas.ToJsonable(env)
+	if err != nil {
+		return nil, err
+	}
+	marshaled, err := json.Marshal(jsonable)
+	if err != nil {
+		return nil, err
+	}
+	canonical, err := canonicalJSON(env)
+	if err != nil {
+		return nil, err
+	}
+	var want, got interface{}
+	if err := json.Unmarshal(marshaled, &want); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(canonical, &got); err != nil {
+		return nil, fmt.Errorf("canonical JSON does not parse: %w", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		return nil, fmt.Errorf("canonical JSON differs from the marshaled document")
+	}
+	return env, nil
+}
+
+// canonicalJSON returns env as RFC 8785 canonical JSON.
+func canonicalJSON(env aastypes.IEnvironment) ([]byte, error) {
+	jsonable, err := aas.ToJsonable(env)
+	if err != nil {
+		return nil, err
+	}
+	return appendCanonical(nil, jsonable)
+}
+
+// appendCanonical appends the canonical form of a jsonable as
+// aas.ToJsonable builds them (objects, arrays, strings, float64s and
+// bools) to b.
+func appendCanonical(b []byte, v interface{}) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sortCanonicalKeys(keys)
+		b = append(b, '{')
+		for i, k := range keys {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = appendCanonicalString(b, k); err != nil {
+				return nil, err
+			}
+			b = append(b, ':')
+			if b, err = appendCanonical(b, v[k]); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, '}')
+	case []interface{}:
+		b = append(b, '[')
+		for i, item := range v {
+			if i > 0 {
+				b = append(b, ',')
+			}
+			if b, err = appendCanonical(b, item); err != nil {
+				return nil, err
+			}
+		}
+		b = append(b, ']')
+	case string:
+		return appendCanonicalString(b, v)
+	case float64:
+		return appendCanonicalNumber(b, v)
+	case bool:
+		b = strconv.AppendBool(b, v)
+	case nil:
+		b = append(b, "null"...)
+	default:
+		return nil, fmt.Errorf("unexpected jsonable %T", v)
+	}
+	return b, nil
+}
+
+// sortCanonicalKeys sorts keys by their UTF-16 code units, as RFC 8785
+// requires. That is byte order except where a key has characters above
+// U+FFFF, whose surrogates sort before U+E000 to U+FFFF.
+func sortCanonicalKeys(keys []string) {
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		for a != "" && b != "" {
+			ra, na := utf8.DecodeRuneInString(a)
+			rb, nb := utf8.DecodeRuneInString(b)
+			if ra != rb {
+				return utf16Unit(ra) < utf16Unit(rb)
+			}
+			a, b = a[na:], b[nb:]
+		}
+		return len(a) < len(b)
+	})
+}
+
+// utf16Unit returns the first UTF-16 code unit of r.
+func utf16Unit(r rune) rune {
+	if hi, _ := utf16.EncodeRune(r); hi != utf8.RuneError {
+		return hi
+	}
+	return r
+}
+
+// appendCanonicalNumber appends f the way ECMAScript's Number.toString
+// writes it: plain notation from 1e-6 to 1e21, exponent notation with a
+// sign and no padding outside, and -0 as 0.
+func appendCanonicalNumber(b []byte, f float64) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return nil, fmt.Errorf("unsupported number %v", f)
+	}
+	if f == 0 {
+		return append(b, '0'), nil
+	}
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs < 1e-6 || abs >= 1e21 {
+		format = 'e'
+	}
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+	if format == 'e' {
+		// clean up e+09 and e-09 to e+9 and e-9
+		if n := len(b); b[n-4] == 'e' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b, nil
+}
+
+// appendCanonicalString appends s quoted as RFC 8785 requires: only the
+// quote, the backslash and control characters are escaped, with the short
+// forms where JSON has them. Unlike json.Marshal it leaves <, >, & and
+// U+2028 and U+2029 as they are, and invalid UTF-8 is an error, since it
+// has no canonical form.
+func appendCanonicalString(b []byte, s string) ([]byte, error) {
+	if !utf8.ValidString(s) {
+		return nil, fmt.Errorf("invalid UTF-8 in %q", s)
+	}
+	b = append(b, '"')
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 0x20 && c != '"' && c != '\\' {
+			continue
+		}
+		b = append(b, s[start:i]...)
+		switch c {
+		case '"', '\\':
+			b = append(b, '\\', c)
+		case '\n':
+			b = append(b, `\n`...)
+		case '\r':
+			b = append(b, `\r`...)
+		case '\t':
+			b = append(b, `\t`...)
+		case '\b':
+			b = append(b, `\b`...)
+		case '\f':
+			b = append(b, `\f`...)
+		default:
+			b = append(b, `\u00`...)
+			b = append(b, hexDigits[c>>4], hexDigits[c&0xF])
+		}
+		start = i + 1
+	}
+	b = append(b, s[start:]...)
+	return append(b, '"'), nil
+}
+
+// BenchmarkCanonicalize benchmarks AAS Environment -> RFC 8785 canonical
+// JSON.
+func BenchmarkCanonicalize(b *testing.B) { benchmarkOperation(b, "canonicalize") }
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// adaptiveTopUpFile is where one top-up process writes its output before
+// it is appended to bench_raw.json.
+const adaptiveTopUpFile = "bench_adaptive.json"
+
+// adaptiveSampler keeps collecting runs of the operations whose relative
+// error is above targetPct, -count more per round, until every operation
+// meets it or budget has passed.
+type adaptiveSampler struct {
+	targetPct float64
+	budget    time.Duration
+	// outlierPolicy trims the samples first, as the report will.
+	outlierPolicy string
+	aliases       report.AliasTable
+	manifest      *runManifest
+}
+
+// run tops up the samples in dir/bench_raw.json and writes adaptive.json to
+// dir. The extra runs of each benchmark function go in a process of their
+// own, selecting only its imprecise datasets, with env and no OUTPUT_DIR,
+// so they cannot overwrite the side channels of the initial run.
+func (a adaptiveSampler) run(h harness, env []string, dir string, run int) error {
+	rawPath := filepath.Join(dir, report.BenchRawFile)
+	start := time.Now()
+	initial := map[string]int{}
+	rec := report.AdaptiveSampling{TargetErrorPct: a.targetPct, BudgetS: a.budget.Seconds()}
+	var results map[string]*report.BenchResult
+	for prev := -1; ; {
+		var err error
+		if results, _, err = report.ParseBenchResults(rawPath, report.ParseLenient, a.aliases); err != nil {
+			return err
+		}
+		if rec.Rounds == 0 {
+			for key, r := range results {
+				initial[key] = len(r.Runs)
+			}
+		}
+		n := sampleCount(results)
+		if n == prev {
+			// Nothing ran, most likely because the budget ran out mid-round;
+			// another round would not fare better.
+			slog.Warn("adaptive sampling round added no samples", "round", rec.Rounds)
+			break
+		}
+		prev = n
+		pending := a.imprecise(results)
+		if len(pending) == 0 || time.Since(start) >= a.budget {
+			break
+		}
+		rec.Rounds++
+		err = a.manifest.stage(fmt.Sprintf("adaptive_%d", rec.Rounds), run, func() error {
+			return a.topUp(h, env, dir, pending, start)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	a.record(&rec, results, initial)
+	rec.ElapsedS = time.Since(start).Seconds()
+	return writeJSON(filepath.Join(dir, report.AdaptiveFile), rec)
+}
+
+// imprecise returns the datasets of every benchmark function, by function
+// name, on which some operation has not met the target. A single run has
+// no spread to judge by and always counts as imprecise.
+func (a adaptiveSampler) imprecise(results map[string]*report.BenchResult) map[string][]string {
+	pending := map[string][]string{}
+	for _, r := range results {
+		if r.Operation == report.CalibrationOperation || r.Benchmark == "" {
+			continue
+		}
+		runs, _ := report.FilterOutliers(r.Runs, a.outlierPolicy)
+		if len(runs) >= 2 && report.RelativeErrorPct(runs) <= a.targetPct {
+			continue
+		}
+		pending[r.Benchmark] = append(pending[r.Benchmark], r.Dataset)
+	}
+	return pending
+}
+
+// topUp runs every benchmark function in pending once more on its
+// imprecise datasets and appends the output to dir/bench_raw.json. It stops
+// early once the budget since start has passed.
+func (a adaptiveSampler) topUp(h harness, env []string, dir string, pending map[string][]string, start time.Time) error {
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	rawPath := filepath.Join(dir, report.BenchRawFile)
+	topUpPath := filepath.Join(dir, adaptiveTopUpFile)
+	defer os.Remove(topUpPath)
+	for _, name := range names {
+		if time.Since(start) >= a.budget {
+			slog.Warn("adaptive sampling budget expired", "budget", a.budget)
+			return nil
+		}
+		// Variants of an operation share its dataset.
+		datasets := pending[name]
+		sort.Strings(datasets)
+		datasets = slices.Compact(datasets)
+		for i, ds := range datasets {
+			datasets[i] = regexp.QuoteMeta(ds)
+		}
+		g := h
+		g.bench = "^Benchmark" + regexp.QuoteMeta(name) + "$/^(" + strings.Join(datasets, "|") + ")$"
+		slog.Info("collecting more runs", "benchmark", name, "datasets", len(datasets), "count", h.count)
+		if err := g.run(topUpPath, append([]string{"OUTPUT_DIR="}, env...)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if err := appendFile(rawPath, topUpPath, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// record fills rec with the precision every operation in results ended
+// with, and whether all of them met the target.
+func (a adaptiveSampler) record(rec *report.AdaptiveSampling, results map[string]*report.BenchResult, initial map[string]int) {
+	rec.Converged = true
+	rec.Operations = []report.AdaptiveOperation{}
+	for key, r := range results {
+		if r.Operation == report.CalibrationOperation {
+			continue
+		}
+		runs, _ := report.FilterOutliers(r.Runs, a.outlierPolicy)
+		relErr := report.RelativeErrorPct(runs)
+		op := report.AdaptiveOperation{
+			Dataset:          r.Dataset,
+			OperationID:      r.Operation,
+			Variant:          r.Variant,
+			SampleCount:      len(runs),
+			ExtraRuns:        len(r.Runs) - initial[key],
+			RelativeErrorPct: math.Round(relErr*100) / 100,
+			Converged:        len(runs) >= 2 && relErr <= a.targetPct,
+		}
+		rec.Converged = rec.Converged && op.Converged
+		rec.Operations = append(rec.Operations, op)
+	}
+	sort.Slice(rec.Operations, func(i, j int) bool {
+		x, y := rec.Operations[i], rec.Operations[j]
+		if x.Dataset != y.Dataset {
+			return x.Dataset < y.Dataset
+		}
+		if x.OperationID != y.OperationID {
+			return x.OperationID < y.OperationID
+		}
+		return x.Variant < y.Variant
+	})
+	slog.Info("adaptive sampling finished", "rounds", rec.Rounds, "converged", rec.Converged)
+}
+
+// sampleCount is the number of runs across results.
+func sampleCount(results map[string]*report.BenchResult) int {
+	n := 0
+	for _, r := range results {
+		n += len(r.Runs)
+	}
+	return n
+}
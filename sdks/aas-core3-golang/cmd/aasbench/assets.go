@@ -0,0 +1,91 @@
+package main
+
+import (
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// assets holds the JSON schemas and output templates shipped inside the
+// binary, so a downloaded aasbench needs no repository checkout.
+//
+//go:embed assets
+var assets embed.FS
+
+// schemaNames lists the embedded schemas, e.g. "report" for
+// assets/report.schema.json.
+func schemaNames() []string {
+	entries, err := assets.ReadDir("assets")
+	if err != nil {
+		return nil
+	}
+	var names []string
+	for _, e := range entries {
+		if name, ok := strings.CutSuffix(e.Name(), ".schema.json"); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// schema returns the embedded schema with the given name.
+func schema(name string) ([]byte, error) {
+	data, err := assets.ReadFile("assets/" + name + ".schema.json")
+	if err != nil {
+		return nil, fmt.Errorf("unknown schema %q (available: %s)", name, strings.Join(schemaNames(), ", "))
+	}
+	return data, nil
+}
+
+// diffMarkdown renders a comparison with the embedded markdown template,
+// suitable for PR comments and job summaries.
+var diffMarkdown = template.Must(template.New("diff.md.tmpl").Funcs(diffFuncs).ParseFS(assets, "assets/diff.md.tmpl"))
+
+// commentMarkdown renders a comparison as a pull request comment, with a
+// collapsible table per track that starts open when the track regressed.
+var commentMarkdown = template.Must(template.New("comment.md.tmpl").Funcs(diffFuncs).ParseFS(assets, "assets/comment.md.tmpl"))
+
+// diffFuncs mark the direction of a delta and format its allocation change
+// and significance test.
+var diffFuncs = template.FuncMap{
+	"marker": func(direction string) string {
+		switch direction {
+		case compare.Regression:
+			return "🔴 regression"
+		case compare.Improvement:
+			return "🟢 improvement"
+		}
+		return ""
+	},
+	"allocs": allocationDisplay,
+	"test":   testDisplay,
+}
+
+// overlayHTML renders an overlay of several reports as a self-contained page
+// with toggleable series; html/template escapes the embedded overlay JSON.
+var overlayHTML = htmltemplate.Must(htmltemplate.ParseFS(assets, "assets/overlay.html.tmpl"))
+
+// monthlyFuncs format the figures of a monthly report.
+var monthlyFuncs = map[string]interface{}{
+	"ns":        func(ns int64) string { return report.FormatValue(float64(ns), "ns") },
+	"join":      strings.Join,
+	"threshold": func() float64 { return compare.DefaultThresholdPct },
+}
+
+// monthlyMarkdown and monthlyHTML render the "state of AAS performance"
+// summary of a period.
+var (
+	monthlyMarkdown = template.Must(template.New("monthly.md.tmpl").Funcs(monthlyFuncs).ParseFS(assets, "assets/monthly.md.tmpl"))
+	monthlyHTML     = htmltemplate.Must(htmltemplate.New("monthly.html.tmpl").Funcs(monthlyFuncs).ParseFS(assets, "assets/monthly.html.tmpl"))
+)
+
+// siteHTML renders the index and every page of the static comparison site;
+// a nil Page renders the index.
+var siteHTML = htmltemplate.Must(htmltemplate.ParseFS(assets, "assets/site.html.tmpl"))
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// backfillDetails is the status.json detail block of backfill.
+type backfillDetails struct {
+	Bundles     int      `json:"bundles"`
+	Regenerated int      `json:"regenerated"`
+	Failed      []string `json:"failed"`
+}
+
+func runBackfill(inv *invocation, args []string) error {
+	flags := newFlagSet(inv, "backfill", "--archive <dir> (--output <dir> | --in-place) [flags]")
+	archive := flags.String("archive", "", "directory tree of archived harness outputs holding bench_raw.json (required)")
+	outputDir := flags.String("output", "", "directory to write regenerated reports to, mirroring the archive layout")
+	inPlace := flags.Bool("in-place", false, "replace each report.json, keeping the previous one as report.json.orig")
+	dryRun := flags.Bool("dry-run", false, "regenerate and validate reports without writing them")
+	stabilityThreshold := flags.Float64("stability-threshold", report.DefaultStabilityThresholdPct, "sweep spread in percent above which an operation is unstable")
+	noiseThreshold := flags.Float64("noise-threshold", report.DefaultNoiseThresholdPct, noiseThresholdUsage)
+	var outlierPolicy string
+	outlierFlag(flags, &outlierPolicy)
+	aliases := flags.String("aliases", "", aliasesUsage)
+	strict := flags.Bool("strict", false, strictUsage)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(flags, "archive"); err != nil {
+		return err
+	}
+	if (*outputDir != "") == *inPlace && !*dryRun {
+		flags.Usage()
+		return fmt.Errorf("want exactly one of --output or --in-place")
+	}
+
+	dirs, err := bundleDirs(*archive)
+	if err != nil {
+		return err
+	}
+	if len(dirs) == 0 {
+		return fmt.Errorf("no %s found under %s", report.BenchRawFile, *archive)
+	}
+
+	details := backfillDetails{Bundles: len(dirs), Failed: []string{}}
+	inv.details = &details
+	for _, dir := range dirs {
+		dest := filepath.Join(dir, report.ReportFile)
+		if *outputDir != "" {
+			rel, err := filepath.Rel(*archive, dir)
+			if err != nil {
+				return err
+			}
+			dest = filepath.Join(*outputDir, rel, report.ReportFile)
+		}
+		slog.Info("backfilling", "bundle", dir)
+		if err := backfill(dir, dest, *aliases, parseMode(*strict), *stabilityThreshold, *noiseThreshold, outlierPolicy, *inPlace, *dryRun); err != nil {
+			slog.Error("backfill failed", "bundle", dir, "err", err)
+			details.Failed = append(details.Failed, dir)
+			continue
+		}
+		details.Regenerated++
+	}
+
+	slog.Info("backfill done", "regenerated", details.Regenerated, "bundles", details.Bundles)
+	if len(details.Failed) > 0 {
+		return fmt.Errorf("%d bundle(s) could not be backfilled", len(details.Failed))
+	}
+	return nil
+}
+
+// bundleDirs returns every directory under root holding a bench_raw.json.
+func bundleDirs(root string) ([]string, error) {
+	var dirs []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() && d.Name() == report.BenchRawFile {
+			dirs = append(dirs, filepath.Dir(path))
+		}
+		return nil
+	})
+	sort.Strings(dirs)
+	return dirs, err
+}
+
+// backfill regenerates the report of the bundle in dir and writes it to
+// dest. Run metadata and the dataset manifest come from the bundle's
+// previous report, since neither can be recovered from the raw output.
+func backfill(dir, dest, aliases string, mode report.ParseMode, stabilityThreshold, noiseThreshold float64, outlierPolicy string, inPlace, dryRun bool) error {
+	bundle := report.BundleInDir(dir)
+	bundle.Mode = mode
+	bundle.Aliases = aliases
+	log := slog.With("bundle", dir)
+	results, opts, err := bundle.Load(log)
+	if err != nil {
+		return fmt.Errorf("parsing benchmark results: %w", err)
+	}
+
+	previousPath := filepath.Join(dir, report.ReportFile)
+	previous, err := report.Load(previousPath)
+	if err != nil && !os.IsNotExist(err) {
+		log.Warn("ignoring unreadable previous report", "err", err)
+		previous = nil
+	}
+	if previous != nil {
+		for _, note := range previous.Metadata.MigrationNotes() {
+			log.Info("migrated", "note", note)
+		}
+		opts.DatasetsManifest = previous.Metadata.DatasetsManifest
+		if opts.Environment == nil {
+			opts.Environment = previous.Environment
+		}
+	}
+	opts.StabilityThresholdPct = stabilityThreshold
+	opts.NoiseThresholdPct = noiseThreshold
+	opts.OutlierPolicy = outlierPolicy
+
+	rep := report.Build(results, opts)
+	if opts.BuildInfo == nil || opts.BuildInfo.GoVersion == "" {
+		rep.Metadata.RuntimeVersion = "unknown"
+	}
+	if info, err := os.Stat(bundle.BenchRaw); err == nil {
+		rep.Metadata.Timestamp = info.ModTime().UTC().Format(time.RFC3339)
+	}
+	if previous != nil {
+		rep.Metadata = preferPrevious(previous.Metadata, rep.Metadata)
+		if previous.SDKID != "" {
+			rep.SDKID = previous.SDKID
+		}
+	}
+	rep.Metadata.BackfilledAt = time.Now().UTC().Format(time.RFC3339)
+
+	if dryRun {
+		// Validate through a scratch file so dry runs apply the same checks.
+		tmp, err := os.CreateTemp("", "aasbench-backfill-*.json")
+		if err != nil {
+			return err
+		}
+		tmp.Close()
+		defer os.Remove(tmp.Name())
+		dest = tmp.Name()
+	} else if inPlace {
+		// Keep the first original across repeated backfills.
+		if _, err := os.Stat(previousPath + ".orig"); os.IsNotExist(err) {
+			if err := os.Rename(previousPath, previousPath+".orig"); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+	} else if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+
+	if err := report.SetSchemaVersion(rep, report.SchemaVersion); err != nil {
+		return err
+	}
+	if err := report.Write(dest, rep); err != nil {
+		return err
+	}
+	if err := checkWritten(dest); err != nil {
+		return err
+	}
+	if !dryRun {
+		log.Info("wrote report", "path", dest)
+	}
+	return nil
+}
+
+// preferPrevious keeps every field the previous report recorded and fills
+// the ones it lacks, such as keys added since it was written, from rebuilt.
+func preferPrevious(previous, rebuilt report.Metadata) report.Metadata {
+	fill := func(dst *string, v string) {
+		if *dst == "" {
+			*dst = v
+		}
+	}
+	fill(&previous.Language, rebuilt.Language)
+	fill(&previous.RuntimeVersion, rebuilt.RuntimeVersion)
+	fill(&previous.SDKPackageVersion, rebuilt.SDKPackageVersion)
+	fill(&previous.BenchmarkHarness, rebuilt.BenchmarkHarness)
+	fill(&previous.Timestamp, rebuilt.Timestamp)
+	fill(&previous.SDKModule, rebuilt.SDKModule)
+	fill(&previous.ObservatoryGitSHA, rebuilt.ObservatoryGitSHA)
+	if previous.ObservatoryGitDirty == nil {
+		previous.ObservatoryGitDirty = rebuilt.ObservatoryGitDirty
+	}
+	return previous
+}
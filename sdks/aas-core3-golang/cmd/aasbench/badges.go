@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/aggregate"
+)
+
+func runBadges(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "badges", "--results results.json --output <dir>")
+	resultsPath := fs.String("results", "dashboard/data/results.json", "aggregated results.json to read")
+	outputDir := fs.String("output", "", "directory to write the shields.io endpoint JSON files to (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "output"); err != nil {
+		return err
+	}
+
+	results, err := aggregate.LoadResults(*resultsPath)
+	if err != nil {
+		return loadErr(err)
+	}
+	n, err := aggregate.WriteBadges(results, *outputDir)
+	if err != nil {
+		return err
+	}
+	inv.details = badgesDetails{Output: *outputDir, SDKs: len(results.SDKBenchmarks), Badges: n}
+	fmt.Printf("Wrote %d badge(s) for %d SDK(s) to %s\n", n, len(results.SDKBenchmarks), *outputDir)
+	return nil
+}
+
+// badgesDetails is the status.json detail block of badges.
+type badgesDetails struct {
+	Output string `json:"output"`
+	SDKs   int    `json:"sdks"`
+	Badges int    `json:"badges"`
+}
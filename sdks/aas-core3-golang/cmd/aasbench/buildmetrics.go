@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// The programs measureBuild compiles, in the harness module. Both read
+// JSON on stdin and write it back; only the sdk one goes through the SDK.
+const (
+	buildProbeSDK      = "./buildprobe/sdk"
+	buildProbeBaseline = "./buildprobe/baseline"
+)
+
+// measureBuild compiles the build probes with the harness's module file
+// and target, and writes their compile times and sizes to
+// dir/build_metrics.json. The build cache starts empty; the baseline is
+// built first, so the sdk program's compile time covers the SDK packages
+// and the link but not the standard library.
+func measureBuild(h harness, dir string) error {
+	tmp, err := os.MkdirTemp("", "aasbench-build-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	env := []string{"GOCACHE=" + filepath.Join(tmp, "cache")}
+	if h.wasm != nil {
+		env = append(env, "GOOS="+h.wasm.goos, "GOARCH=wasm")
+	}
+
+	m := report.BuildMetrics{SDKModule: sdkModule}
+	cmd := exec.Command("go", "env", "GOOS", "GOARCH", "GOVERSION")
+	cmd.Env = append(os.Environ(), env...)
+	goEnv, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("go env: %w", err)
+	}
+	if f := strings.Fields(string(goEnv)); len(f) == 3 {
+		m.Target, m.GoVersion = f[0]+"/"+f[1], f[2]
+	}
+
+	builds := []struct {
+		pkg, ldflags string
+		elapsed      *float64
+		size         *int64
+	}{
+		{buildProbeBaseline, "", &m.BaselineCompileS, &m.BaselineBinarySizeBytes},
+		{buildProbeSDK, "", &m.CompileS, &m.BinarySizeBytes},
+		{buildProbeBaseline, "-s -w", nil, &m.BaselineStrippedSizeBytes},
+		{buildProbeSDK, "-s -w", nil, &m.StrippedSizeBytes},
+	}
+	for i, b := range builds {
+		out := filepath.Join(tmp, fmt.Sprintf("probe-%d", i))
+		elapsed, size, err := h.buildProbe(b.pkg, b.ldflags, out, env)
+		if err != nil {
+			return err
+		}
+		if b.elapsed != nil {
+			*b.elapsed = math.Round(elapsed.Seconds()*1000) / 1000
+		}
+		*b.size = size
+	}
+	m.SDKStrippedSizeBytes = m.StrippedSizeBytes - m.BaselineStrippedSizeBytes
+	slog.Info("measured SDK build", "target", m.Target, "compile_s", m.CompileS, "stripped_size_bytes", m.StrippedSizeBytes, "sdk_stripped_size_bytes", m.SDKStrippedSizeBytes)
+	return writeJSON(filepath.Join(dir, report.BuildMetricsFile), m)
+}
+
+// buildProbe builds pkg of the harness module to out and returns how long
+// that took and the size of the binary.
+func (h harness) buildProbe(pkg, ldflags, out string, env []string) (time.Duration, int64, error) {
+	args := []string{"build", "-trimpath", "-o", out}
+	if ldflags != "" {
+		args = append(args, "-ldflags="+ldflags)
+	}
+	if h.modfile != "" {
+		args = append(args, "-modfile="+h.modfile)
+	}
+	cmd := exec.Command("go", append(args, pkg)...)
+	cmd.Dir = h.dir
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+	start := time.Now()
+	err := cmd.Run()
+	elapsed := time.Since(start)
+	h.manifest.process(cmd, env, elapsed, err)
+	if err != nil {
+		return 0, 0, fmt.Errorf("go build %s: %w", pkg, err)
+	}
+	fi, err := os.Stat(out)
+	if err != nil {
+		return 0, 0, err
+	}
+	return elapsed, fi.Size(), nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// completeCommand is the hidden command the shell scripts call to ask the
+// binary for candidates, so completions never drift from the real flags.
+const completeCommand = "__complete"
+
+// subcommands lists the nested commands of commands that have them.
+var subcommands = map[string][]string{
+	"dataset": {"manifest", "verify", "pack", "fetch"},
+	"runs":    {"list", "prune"},
+}
+
+// positionals lists fixed positional arguments a command accepts.
+var positionals = map[string]func() []string{
+	"schema":     schemaNames,
+	"completion": func() []string { return []string{"bash", "zsh", "fish"} },
+}
+
+var completionScripts = map[string]string{
+	"bash": `# bash completion for aasbench
+_aasbench() {
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local IFS=$'\n'
+    COMPREPLY=($(compgen -W "$(aasbench ` + completeCommand + ` "${COMP_WORDS[@]:1:COMP_CWORD-1}" 2>/dev/null)" -- "$cur"))
+}
+complete -o default -F _aasbench aasbench
+`,
+	"zsh": `#compdef aasbench
+# zsh completion for aasbench
+_aasbench() {
+    local -a candidates
+    candidates=(${(f)"$(aasbench ` + completeCommand + ` ${words[2,CURRENT-1]} 2>/dev/null)"})
+    if (( ${#candidates} )); then
+        compadd -a candidates
+    else
+        _files
+    fi
+}
+if [ "$funcstack[1]" = "_aasbench" ]; then
+    _aasbench "$@"
+else
+    compdef _aasbench aasbench
+fi
+`,
+	"fish": `# fish completion for aasbench
+complete -c aasbench -a '(aasbench ` + completeCommand + ` (commandline -opc)[2..-1] 2>/dev/null)'
+`,
+}
+
+func runCompletion(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "completion", "bash|zsh|fish")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	script, ok := completionScripts[fs.Arg(0)]
+	if fs.NArg() != 1 || !ok {
+		fs.Usage()
+		return fmt.Errorf("want exactly one shell: bash, zsh or fish")
+	}
+	fmt.Fprintf(os.Stderr, "# Load with: source <(aasbench completion %s)\n", fs.Arg(0))
+	_, err := io.WriteString(os.Stdout, script)
+	return err
+}
+
+// complete prints the candidates for the word following words, one per line.
+// It prints nothing where the shell should fall back to file names.
+func complete(w io.Writer, words []string) {
+	if len(words) == 0 {
+		for _, c := range commands {
+			fmt.Fprintln(w, c.name)
+		}
+		return
+	}
+	c, ok := lookup(words[0])
+	if !ok {
+		return
+	}
+	args := words[1:]
+	if subs := subcommands[c.name]; len(subs) > 0 {
+		if len(args) == 0 {
+			for _, s := range subs {
+				fmt.Fprintln(w, s)
+			}
+			return
+		}
+		args = args[:1] // complete the subcommand's flags
+	} else {
+		args = nil
+	}
+
+	fs := commandFlags(c, args)
+	if fs == nil {
+		return
+	}
+	if last := words[len(words)-1]; strings.HasPrefix(last, "-") && !strings.Contains(last, "=") {
+		if f := fs.Lookup(strings.TrimLeft(last, "-")); f != nil && !isBoolFlag(f) {
+			return // a flag value: let the shell complete files
+		}
+	}
+
+	var names []string
+	fs.VisitAll(func(f *flag.Flag) { names = append(names, "--"+f.Name) })
+	sort.Strings(names)
+	if values, ok := positionals[c.name]; ok {
+		names = append(values(), names...)
+	}
+	for _, n := range names {
+		fmt.Fprintln(w, n)
+	}
+}
+
+// commandFlags returns the flag set c registers, found by running it with
+// -h and its output discarded.
+func commandFlags(c command, args []string) *flag.FlagSet {
+	inv := &invocation{completing: true}
+	c.run(inv, append(args, "-h"))
+	return inv.flags
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
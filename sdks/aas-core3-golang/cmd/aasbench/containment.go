@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/containment"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// runContainment runs every benchmark function h selects once, each in its
+// own process under runner's memory cap, and writes containment.json to
+// dir. The test binary is built once; each process gets env and no
+// OUTPUT_DIR, so it cannot overwrite the side channels of the timed run.
+func runContainment(h harness, runner *containment.Runner, aliases report.AliasTable, env []string, dir string) error {
+	tmp, err := os.MkdirTemp("", "aasbench-containment-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	binary := filepath.Join(tmp, "harness.test")
+	args := []string{"test", "-c", "-o", binary}
+	if h.modfile != "" {
+		args = append(args, "-modfile="+h.modfile)
+	}
+	build := exec.Command("go", append(args, h.pkg)...)
+	build.Dir = h.dir
+	build.Stdout = os.Stderr
+	build.Stderr = os.Stderr
+	if err := build.Run(); err != nil {
+		return fmt.Errorf("build test binary: %w", err)
+	}
+
+	groups, rest, err := benchmarkGroups(binary, h.bench)
+	if err != nil {
+		return err
+	}
+	c := report.Containment{CapBytes: runner.CapBytes(), Method: runner.Method(), Groups: []report.ContainmentGroup{}}
+	for _, name := range groups {
+		cmd := exec.Command(binary, append([]string{
+			"-test.run=^$",
+			"-test.bench=^" + name + "$" + rest,
+			"-test.benchmem",
+			"-test.count=1",
+			"-test.timeout=" + h.timeout,
+		}, h.args...)...)
+		cmd.Dir = h.dir
+		cmd.Env = append(append(os.Environ(), "OUTPUT_DIR="), env...)
+		slog.Info("running under memory cap", "benchmark", name, "cap_bytes", c.CapBytes, "method", c.Method)
+		g := runner.Run(name, cmd)
+		g.OperationID = aliases.Resolve(strings.TrimPrefix(name, "Benchmark"))
+		slog.Info("contained run finished", "benchmark", name, "outcome", g.Outcome, "wall_ms", g.WallMs)
+		c.Groups = append(c.Groups, g)
+	}
+	return writeJSON(filepath.Join(dir, report.ContainmentFile), c)
+}
+
+// benchmarkGroups lists the benchmark functions of binary that the first
+// element of the -bench pattern selects, and returns the remaining
+// elements to append to each function's pattern.
+func benchmarkGroups(binary, bench string) (groups []string, rest string, err error) {
+	out, err := exec.Command(binary, "-test.list", "^Benchmark").Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("list benchmarks: %w", err)
+	}
+	return selectBenchmarks(strings.Fields(string(out)), bench)
+}
+
+// selectBenchmarks picks the benchmark functions among names that the
+// first element of the -bench pattern selects, and returns the remaining
+// elements.
+func selectBenchmarks(names []string, bench string) (groups []string, rest string, err error) {
+	first, sub, nested := strings.Cut(bench, "/")
+	if nested {
+		rest = "/" + sub
+	}
+	match, err := regexp.Compile(first)
+	if err != nil {
+		return nil, "", fmt.Errorf("--bench: %w", err)
+	}
+	for _, name := range names {
+		if strings.HasPrefix(name, "Benchmark") && match.MatchString(name) {
+			groups = append(groups, name)
+		}
+	}
+	return groups, rest, nil
+}
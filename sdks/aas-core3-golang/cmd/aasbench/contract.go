@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/contract"
+)
+
+func runContract(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "contract", "[flags] <report.json>")
+	output := fs.String("output", "", "path to write checklist.json (default: next to the report; - skips it)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("want exactly one report")
+	}
+	path := fs.Arg(0)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return loadErr(err)
+	}
+
+	cl := contract.Run(data)
+	inv.details = contractDetails{Passed: cl.Passed, Failed: cl.Failed()}
+	for _, check := range cl.Checks {
+		fmt.Printf("%-5s %-14s %s\n", strings.ToUpper(check.Status), check.ID, check.Description)
+		for _, p := range check.Problems {
+			fmt.Printf("        - %s\n", p)
+		}
+	}
+
+	if *output == "" {
+		*output = filepath.Join(filepath.Dir(path), contract.ChecklistFile)
+	}
+	if *output != "-" {
+		if err := writeJSON(*output, cl); err != nil {
+			return err
+		}
+		slog.Info("wrote checklist", "path", *output)
+	}
+	if !cl.Passed {
+		return schemaErr(fmt.Errorf("%s does not meet the adapter contract: %s failed", path, strings.Join(cl.Failed(), ", ")))
+	}
+	return nil
+}
+
+// contractDetails is the status.json detail block of contract.
+type contractDetails struct {
+	Passed bool     `json:"passed"`
+	Failed []string `json:"failed,omitempty"`
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func runCrossCheck(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "crosscheck", "--left a.json --right b.json [flags]")
+	leftPath := fs.String("left", "", "report.json of one language adapter (required)")
+	rightPath := fs.String("right", "", "report.json of another language adapter (required)")
+	outputPath := fs.String("output", "", "optional path to write the findings as JSON")
+	strict := fs.Bool("strict", false, "also fail on methodology findings and inputs without fingerprints")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "left", "right"); err != nil {
+		return err
+	}
+
+	left, err := report.Load(*leftPath)
+	if err != nil {
+		return loadErr(err)
+	}
+	right, err := report.Load(*rightPath)
+	if err != nil {
+		return loadErr(err)
+	}
+
+	c := compare.CrossCheckReports(left, right)
+	inv.details = c
+	fmt.Printf("%s vs %s: %d paired operation(s), %d with verified identical inputs\n",
+		c.LeftSDKID, c.RightSDKID, c.Paired, c.Verified)
+	if len(c.Findings) > 0 {
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "KIND\tINPUT\tOPERATIONS\tDETAIL")
+		for _, f := range c.Findings {
+			input := "-"
+			if f.Dataset != "" {
+				input = f.Dataset
+				if f.Format != "" {
+					input += "." + f.Format
+				}
+			}
+			ops := "-"
+			if len(f.Operations) > 0 {
+				ops = strings.Join(f.Operations, ",")
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", f.Kind, input, ops, f.Detail)
+		}
+		tw.Flush()
+	}
+
+	if *outputPath != "" {
+		out, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*outputPath, out, 0644); err != nil {
+			return err
+		}
+		slog.Info("wrote cross-check", "path", *outputPath)
+	}
+	if n := c.Mismatches(); n > 0 {
+		return fmt.Errorf("%d input mismatch(es) between %s and %s", n, c.LeftSDKID, c.RightSDKID)
+	}
+	if *strict && len(c.Findings) > 0 {
+		return fmt.Errorf("%d finding(s) between %s and %s", len(c.Findings), c.LeftSDKID, c.RightSDKID)
+	}
+	return nil
+}
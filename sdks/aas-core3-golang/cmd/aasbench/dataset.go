@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/dataset"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func runDataset(inv *invocation, args []string) error {
+	if len(args) == 0 {
+		datasetUsage()
+		return fmt.Errorf("no dataset subcommand given")
+	}
+	switch args[0] {
+	case "manifest":
+		return runDatasetManifest(inv, args[1:])
+	case "verify":
+		return runDatasetVerify(inv, args[1:])
+	case "pack":
+		return runDatasetPack(inv, args[1:])
+	case "fetch":
+		return runDatasetFetch(inv, args[1:])
+	case "-h", "--help", "help":
+		datasetUsage()
+		return nil
+	}
+	datasetUsage()
+	return fmt.Errorf("unknown dataset subcommand %q", args[0])
+}
+
+func datasetUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: aasbench dataset <subcommand> [flags]\n\nSubcommands:\n")
+	fmt.Fprintf(os.Stderr, "  manifest   Fingerprint every dataset file in a directory\n")
+	fmt.Fprintf(os.Stderr, "  verify     Check dataset files against a report's datasets_manifest\n")
+	fmt.Fprintf(os.Stderr, "  pack       Bundle dataset files with their manifest and checksum for a release\n")
+	fmt.Fprintf(os.Stderr, "  fetch      Download, verify and cache a released dataset bundle\n")
+}
+
+func runDatasetManifest(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "dataset manifest", "--datasets <dir> [flags]")
+	datasetsDir := fs.String("datasets", "", "directory containing dataset files (required)")
+	outputPath := fs.String("output", "", "optional path to write the manifest JSON (default stdout)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "datasets"); err != nil {
+		return err
+	}
+
+	manifest, err := dataset.Manifest(*datasetsDir, nil)
+	if err != nil {
+		return err
+	}
+	inv.details = map[string]int{"files": len(manifest)}
+	out, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if *outputPath == "" {
+		fmt.Println(string(out))
+		return nil
+	}
+	if err := os.WriteFile(*outputPath, out, 0644); err != nil {
+		return err
+	}
+	slog.Info("wrote manifest", "files", len(manifest), "path", *outputPath)
+	return nil
+}
+
+// verifyDetails is the status.json detail block of dataset verify.
+type verifyDetails struct {
+	Checked    int      `json:"checked"`
+	Mismatched []string `json:"mismatched"`
+}
+
+func runDatasetVerify(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "dataset verify", "--datasets <dir> --report report.json")
+	datasetsDir := fs.String("datasets", "", "directory containing dataset files (required)")
+	reportPath := fs.String("report", "", "report.json whose datasets_manifest is the reference (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "datasets", "report"); err != nil {
+		return err
+	}
+
+	rep, err := report.Load(*reportPath)
+	if err != nil {
+		return loadErr(err)
+	}
+	if len(rep.Metadata.DatasetsManifest) == 0 {
+		return schemaErr(fmt.Errorf("%s has no datasets_manifest", *reportPath))
+	}
+
+	details := verifyDetails{Mismatched: []string{}}
+	inv.details = &details
+	changed := 0
+	for _, want := range rep.Metadata.DatasetsManifest {
+		details.Checked++
+		got, err := dataset.Fingerprint(filepath.Join(*datasetsDir, want.File))
+		switch {
+		case err != nil:
+			fmt.Fprintf(os.Stderr, "MISSING   %s: %v\n", want.File, err)
+		case got.SHA256 != want.SHA256:
+			fmt.Fprintf(os.Stderr, "CHANGED   %s: sha256 %s, report has %s\n", want.File, got.SHA256, want.SHA256)
+			changed++
+		default:
+			fmt.Printf("OK        %s\n", want.File)
+			continue
+		}
+		details.Mismatched = append(details.Mismatched, want.File)
+	}
+	if len(details.Mismatched) == 0 {
+		return nil
+	}
+	err = fmt.Errorf("%d of %d dataset file(s) differ from %s", len(details.Mismatched), details.Checked, *reportPath)
+	// A file whose content changed breaks the report's contract with its
+	// data; one that cannot be read is a problem of the runner.
+	if changed > 0 {
+		return schemaErr(err)
+	}
+	return err
+}
+
+func runDatasetPack(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "dataset pack", "--datasets <dir> --output <dir>")
+	datasetsDir := fs.String("datasets", "", "directory containing dataset files (required)")
+	outputDir := fs.String("output", "", "directory to write the bundle and its .sha256 to (required)")
+	asset := fs.String("asset", dataset.BundleAsset, "file name of the bundle, e.g. "+compare.ReferenceAsset+" for a directory of reference reports")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "datasets", "output", "asset"); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*outputDir, 0755); err != nil {
+		return err
+	}
+	path := filepath.Join(*outputDir, *asset)
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	h := sha256.New()
+	manifest, err := dataset.Pack(*datasetsDir, io.MultiWriter(f, h))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	if err := os.WriteFile(path+".sha256", []byte(sum+"  "+*asset+"\n"), 0644); err != nil {
+		return err
+	}
+	inv.details = map[string]interface{}{"files": len(manifest), "sha256": sum}
+	slog.Info("wrote dataset bundle", "files", len(manifest), "path", path, "sha256", sum)
+	return nil
+}
+
+// fetchDetails is the status.json detail block of dataset fetch.
+type fetchDetails struct {
+	URL    string `json:"url"`
+	Dir    string `json:"dir"`
+	Cached bool   `json:"cached"`
+}
+
+func runDatasetFetch(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "dataset fetch", "[--release <tag>] [flags]")
+	repo := fs.String("repo", dataset.DefaultRepository, "GitHub repository (owner/name) whose releases carry the dataset bundle")
+	release := fs.String("release", "latest", "release tag to fetch the bundle of")
+	url := fs.String("url", "", "bundle URL to fetch instead of a release's, with its checksum at <url>.sha256")
+	sum := fs.String("sha256", "", "expected sha256 of the bundle (default: the published .sha256)")
+	cacheDir := fs.String("cache", "", "directory bundles are unpacked into (default $"+datasetCacheEnv+" or the user cache directory's aasbench/datasets)")
+	timeout := fs.Duration("timeout", 10*time.Minute, "give up on the download after this long")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	f := &dataset.Fetcher{CacheDir: *cacheDir, HTTP: &http.Client{}}
+	if f.CacheDir == "" {
+		f.CacheDir = os.Getenv(datasetCacheEnv)
+	}
+	if f.CacheDir == "" {
+		dir, err := dataset.DefaultCacheDir()
+		if err != nil {
+			return fmt.Errorf("no cache directory: %w (set --cache)", err)
+		}
+		f.CacheDir = dir
+	}
+	if *url == "" {
+		*url = dataset.ReleaseURL(*repo, *release)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+	slog.Info("fetching datasets", "url", *url)
+	dir, cached, err := f.Fetch(ctx, *url, *sum)
+	if err != nil {
+		return err
+	}
+	inv.details = fetchDetails{URL: *url, Dir: dir, Cached: cached}
+	slog.Info("datasets ready", "dir", dir, "cached", cached)
+
+	// A child process cannot change its parent's environment: print an
+	// export line for eval, and hand the variable to later workflow steps
+	// through $GITHUB_ENV.
+	fmt.Printf("export DATASETS_DIR=%q\n", dir)
+	if env := os.Getenv("GITHUB_ENV"); env != "" {
+		ghEnv, err := os.OpenFile(env, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(ghEnv, "DATASETS_DIR=%s\n", dir)
+		if cerr := ghEnv.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	}
+	return nil
+}
+
+// datasetCacheEnv overrides the directory dataset fetch caches bundles in.
+const datasetCacheEnv = "AASBENCH_DATASETS_CACHE"
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func runDiff(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "diff", "(--baseline old.json | --against reference) --current new.json [flags]")
+	baselinePath := fs.String("baseline", "", "baseline report.json (required unless --against)")
+	currentPath := fs.String("current", "", "current report.json (required)")
+	outputPath := fs.String("output", "", "optional path to write comparison.json")
+	threshold := fs.Float64("threshold", compare.DefaultThresholdPct, "minimum significant change in percent")
+	alpha := fs.Float64("alpha", compare.DefaultAlpha, "p-value below which a Mann-Whitney U test of the per-run samples finds a change significant")
+	format := fs.String("format", "text", "stdout format: text or markdown")
+	gateExtensions := fs.Bool("gate-extensions", false, "also fail on regressions in namespaced extension operations")
+	baselineHeap := fs.String("baseline-heap", "", "baseline heap_hotspots.json (default: next to --baseline, if present)")
+	currentHeap := fs.String("current-heap", "", "current heap_hotspots.json (default: next to --current, if present)")
+	against := fs.String("against", "", "instead of --baseline, compare with \"reference\": the published reference report of the SDK, to check this machine's numbers are in the expected ballpark")
+	referenceSrc := fs.String("reference", compare.DefaultReferenceURL, "directory or bundle URL of the reference reports for --against reference")
+	ballparkFactor := fs.Float64("ballpark-factor", compare.DefaultBallparkFactor, "with --against reference, how many times slower or faster than the reference is still the ballpark")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	switch *against {
+	case "":
+	case "reference":
+		if err := requireFlags(fs, "current"); err != nil {
+			return err
+		}
+		if *ballparkFactor <= 1 {
+			return fmt.Errorf("--ballpark-factor must be greater than 1")
+		}
+		return runBallpark(inv, *currentPath, *referenceSrc, *ballparkFactor, *outputPath)
+	default:
+		return fmt.Errorf("unknown --against %q (want reference)", *against)
+	}
+	if err := requireFlags(fs, "baseline", "current"); err != nil {
+		return err
+	}
+	if *alpha <= 0 || *alpha >= 1 {
+		return fmt.Errorf("--alpha must be between 0 and 1")
+	}
+	if *format != "text" && *format != "markdown" {
+		return fmt.Errorf("unknown --format %q (want text or markdown)", *format)
+	}
+
+	baseline, err := report.Load(*baselinePath)
+	if err != nil {
+		return loadErr(err)
+	}
+	current, err := report.Load(*currentPath)
+	if err != nil {
+		return loadErr(err)
+	}
+
+	c := compare.Reports(baseline, current, *threshold, *alpha)
+	for _, d := range c.EnvironmentDifferences {
+		slog.Warn("runs were measured on different hosts", "difference", d)
+	}
+	if err := diffAllocations(c, heapSidecar(*baselineHeap, *baselinePath), heapSidecar(*currentHeap, *currentPath)); err != nil {
+		return err
+	}
+	if *format == "markdown" {
+		if err := diffMarkdown.Execute(os.Stdout, c); err != nil {
+			return err
+		}
+	} else {
+		printComparison(c)
+	}
+	inv.details = c.Summary
+
+	if *outputPath != "" {
+		out, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*outputPath, out, 0644); err != nil {
+			return err
+		}
+		slog.Info("wrote comparison", "path", *outputPath)
+	}
+	if n := c.Summary.GatingRegressions(*gateExtensions); n > 0 {
+		return regressionsErr(fmt.Errorf("%d significant regression(s)", n))
+	}
+	return nil
+}
+
+// heapSidecar returns explicit, or the heap_hotspots.json a run left next to
+// reportPath when it exists.
+func heapSidecar(explicit, reportPath string) string {
+	if explicit != "" {
+		return explicit
+	}
+	path := filepath.Join(filepath.Dir(reportPath), report.HeapHotspotsFile)
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// diffAllocations attaches a per-operation allocation site diff when both
+// runs were heap profiled.
+func diffAllocations(c *compare.Comparison, baselinePath, currentPath string) error {
+	if baselinePath == "" || currentPath == "" {
+		return nil
+	}
+	baseline, err := report.LoadHeapHotspots(baselinePath)
+	if err != nil {
+		return loadErr(err)
+	}
+	current, err := report.LoadHeapHotspots(currentPath)
+	if err != nil {
+		return loadErr(err)
+	}
+	c.AllocationDiff = compare.AllocationDiff(baseline, current, compare.DefaultAllocationSites)
+	if len(c.AllocationDiff) == 0 {
+		slog.Warn("no operation has iteration counts in both heap profiles; skipping allocation diff", "baseline", baselinePath, "current", currentPath)
+	}
+	return nil
+}
+
+// allocationDisplay formats an allocs/op change with its classification.
+func allocationDisplay(a *compare.AllocationChange) string {
+	if a == nil {
+		return "–"
+	}
+	if a.ChangePct == nil {
+		return fmt.Sprintf("%d → %d (%s)", a.PreviousPerOp, a.CurrentPerOp, a.Classification)
+	}
+	return fmt.Sprintf("%d → %d (%+.1f%%, %s)", a.PreviousPerOp, a.CurrentPerOp, *a.ChangePct, a.Classification)
+}
+
+// testDisplay formats the Mann-Whitney p-value and effect size of a delta,
+// or a dash when its confidence interval judged it.
+func testDisplay(d compare.Delta) string {
+	if d.PValue == nil || d.CliffsDelta == nil {
+		return "–"
+	}
+	return fmt.Sprintf("%.4f (δ %+.2f, %s)", *d.PValue, *d.CliffsDelta, d.Effect)
+}
+
+func printComparison(c *compare.Comparison) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	if len(c.Headline) > 0 {
+		fmt.Fprintln(tw, "HEADLINE\tBASELINE\tCURRENT\tCHANGE")
+		for _, h := range c.Headline {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", h.Label, h.PreviousDisplay(), h.Display(), h.ChangeDisplay())
+		}
+		fmt.Fprintln(tw)
+	}
+	if len(c.Build) > 0 {
+		fmt.Fprintln(tw, "BUILD\tBASELINE\tCURRENT\tCHANGE")
+		for _, b := range c.Build {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", b.Metric, b.PreviousDisplay(), b.CurrentDisplay(), b.ChangeDisplay())
+		}
+		fmt.Fprintln(tw)
+	}
+	fmt.Fprintln(tw, "DATASET\tOPERATION\tBASELINE ns\tCURRENT ns\tCHANGE %\t95% CI\tP (CLIFF'S δ)\tDIRECTION\tCLASS\tALLOCS/OP")
+	for _, d := range c.Deltas {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%+.2f\t[%+.2f, %+.2f]\t%s\t%s\t%s\t%s\n",
+			d.Dataset, d.Operation, d.PreviousMeanNs, d.CurrentMeanNs,
+			d.ChangePct, d.CILowerPct, d.CIUpperPct, testDisplay(d), d.Direction, d.Classification, allocationDisplay(d.Allocations))
+	}
+	tw.Flush()
+	fmt.Printf("\n%d regression(s), %d improvement(s), %d unchanged\n",
+		c.Summary.Regressions, c.Summary.Improvements, c.Summary.Unchanged)
+	if c.Summary.ExtensionRegressions > 0 {
+		fmt.Printf("%d of the regressions are in extension operations\n", c.Summary.ExtensionRegressions)
+	}
+
+	for _, oa := range c.AllocationDiff {
+		fmt.Printf("\nAllocations of %s: %.0f -> %.0f bytes/op (%+.0f)\n", oa.Operation, oa.BaselineBytes, oa.CurrentBytes, oa.DeltaBytes)
+		tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, d := range oa.Grew {
+			fmt.Fprintf(tw, "  %+.0f B/op\t%+.1f objs/op\t%s\n", d.DeltaBytes, d.DeltaObjects, d.Function)
+		}
+		for _, d := range oa.Shrank {
+			fmt.Fprintf(tw, "  %+.0f B/op\t%+.1f objs/op\t%s\n", d.DeltaBytes, d.DeltaObjects, d.Function)
+		}
+		tw.Flush()
+	}
+}
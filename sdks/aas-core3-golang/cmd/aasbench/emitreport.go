@@ -0,0 +1,470 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/dataset"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// reportInputs are the files the report command reads and writes.
+type reportInputs struct {
+	bundle   report.Bundle
+	output   string
+	datasets string
+	// sdkVersion is recorded as sdk_package_version when the bundle has no
+	// build_info.json saying which version was linked.
+	sdkVersion string
+	// stabilityThreshold is the sweep spread in percent above which an
+	// operation is flagged unstable; 0 uses the report default.
+	stabilityThreshold float64
+	// noiseThreshold is the control benchmark drift in percent above which
+	// the run's environment is flagged noisy; 0 uses the report default.
+	noiseThreshold float64
+	// outlierPolicy trims each operation's samples before its statistics.
+	outlierPolicy string
+	// sdkID replaces the default sdk_id when set.
+	sdkID string
+	// meta overrides metadata fields after the report is built.
+	meta metaOverrides
+	// limits caps the report's list sections.
+	limits reportLimits
+	// benchText is where to write the results in Go benchmark format for
+	// benchstat; empty skips it.
+	benchText string
+	// expected are the operations the run was to measure, for a partial
+	// report of a run that crashed.
+	expected []string
+}
+
+// reportLimits are the size guardrail and publishing flags of run and
+// emit-report.
+type reportLimits struct {
+	summary  bool
+	caps     sectionCaps
+	maxBytes int64
+	redact   bool
+	// schemaVersion is the report.json schema version to write.
+	schemaVersion int
+}
+
+func (l *reportLimits) register(fs *flag.FlagSet) {
+	fs.BoolVar(&l.summary, "summary", false, "drop every capped section ("+strings.Join(report.Sections, ", ")+") from report.json")
+	fs.Var(&l.caps, "cap", "section=n keeping at most n entries per list of a section; -1 keeps all, 0 drops it (repeatable)")
+	fs.Int64Var(&l.maxBytes, "max-report-bytes", 5<<20, "fall back to --summary when report.json would be larger; 0 disables the check")
+	fs.BoolVar(&l.redact, "redact", false, redactUsage)
+	schemaVersionFlag(fs, &l.schemaVersion)
+}
+
+// schemaVersionFlag registers --schema-version, the report.json schema
+// version to write, on fs.
+func schemaVersionFlag(fs *flag.FlagSet, version *int) {
+	*version = report.SchemaVersion
+	fs.Func("schema-version", fmt.Sprintf("report.json schema `version` to write: %d adds metrics_manifest, the unit and aggregation of every numeric field; %d omits it for older consumers (default %d)",
+		report.SchemaVersion, report.LegacySchemaVersion, report.SchemaVersion), func(v string) error {
+		n, err := strconv.Atoi(v)
+		if err != nil || (n != report.SchemaVersion && n != report.LegacySchemaVersion) {
+			return fmt.Errorf("unsupported version %q (want %d or %d)", v, report.SchemaVersion, report.LegacySchemaVersion)
+		}
+		*version = n
+		return nil
+	})
+}
+
+const redactUsage = "strip the host environment, hostnames, usernames and absolute paths for publishing, keeping them in <report>.private.json"
+
+// redactReport strips rep for publishing and writes what it removed next
+// to output.
+func redactReport(output string, rep *report.Report) error {
+	private, err := report.HostRedactor().Redact(rep)
+	if err != nil {
+		return fmt.Errorf("redact: %w", err)
+	}
+	path := report.PrivateFileName(output)
+	if err := writeJSON(path, private); err != nil {
+		return err
+	}
+	slog.Info("redacted report", "redacted", len(rep.Redacted), "private", path)
+	return nil
+}
+
+// limits returns the default or summary limits with the --cap overrides.
+func (l reportLimits) limits() report.Limits {
+	limits := report.DefaultLimits()
+	if l.summary {
+		limits = report.SummaryLimits()
+	}
+	for _, c := range l.caps {
+		limits[c.section] = c.n
+	}
+	return limits
+}
+
+// sectionCaps collects repeated --cap section=n flags.
+type sectionCaps []struct {
+	section string
+	n       int
+}
+
+func (c *sectionCaps) String() string {
+	parts := make([]string, len(*c))
+	for i, s := range *c {
+		parts[i] = s.section + "=" + strconv.Itoa(s.n)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (c *sectionCaps) Set(v string) error {
+	section, value, ok := strings.Cut(v, "=")
+	n, err := strconv.Atoi(value)
+	if !ok || err != nil {
+		return fmt.Errorf("want section=n, got %q", v)
+	}
+	if err := (report.Limits{}).Set(section, n); err != nil {
+		return err
+	}
+	*c = append(*c, struct {
+		section string
+		n       int
+	}{section, n})
+	return nil
+}
+
+// sdkIDPattern matches the ids of known-sdks.json (results/<sdk-id>).
+var sdkIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// metaOverrides collects repeated --meta key=value flags. Keys and values
+// are checked against the metadata whitelist as they are parsed.
+type metaOverrides [][2]string
+
+func (m *metaOverrides) String() string {
+	parts := make([]string, len(*m))
+	for i, kv := range *m {
+		parts[i] = kv[0] + "=" + kv[1]
+	}
+	return strings.Join(parts, ",")
+}
+
+func (m *metaOverrides) Set(v string) error {
+	key, value, ok := strings.Cut(v, "=")
+	if !ok || key == "" {
+		return fmt.Errorf("want key=value, got %q", v)
+	}
+	var scratch report.Metadata
+	if err := scratch.Set(key, value); err != nil {
+		return err
+	}
+	*m = append(*m, [2]string{key, value})
+	return nil
+}
+
+// apply sets every override on meta, later flags winning.
+func (m metaOverrides) apply(meta *report.Metadata) error {
+	for _, kv := range m {
+		if err := meta.Set(kv[0], kv[1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sweepInputs collects repeated --sweep benchtime=path flags.
+type sweepInputs []report.SweepFile
+
+func (s *sweepInputs) String() string {
+	parts := make([]string, len(*s))
+	for i, in := range *s {
+		parts[i] = in.Benchtime + "=" + in.Path
+	}
+	return strings.Join(parts, ",")
+}
+
+func (s *sweepInputs) Set(v string) error {
+	benchtime, path, ok := strings.Cut(v, "=")
+	if !ok || benchtime == "" || path == "" {
+		return fmt.Errorf("want benchtime=path, got %q", v)
+	}
+	*s = append(*s, report.SweepFile{Benchtime: benchtime, Path: path})
+	return nil
+}
+
+func runEmitReport(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "emit-report", "--input bench_raw.json --output report.json [flags]")
+	var in reportInputs
+	var sweeps sweepInputs
+	fs.StringVar(&in.bundle.BenchRaw, "input", "", "go test -json benchmark output (required)")
+	fs.StringVar(&in.output, "output", "", "path to write report.json (required)")
+	fs.StringVar(&in.bundle.MemoryStats, "memory-stats", "", "optional memory_stats.json side channel")
+	fs.StringVar(&in.bundle.GCPauses, "gc-pauses", "", "optional gc_pauses.json with per-group GC pause histograms")
+	fs.StringVar(&in.bundle.Events, "events", "", "optional events.json timeline")
+	fs.StringVar(&in.bundle.HeapHotspots, "heap-hotspots", "", "optional heap_hotspots.json from a HEAP_PROFILE run")
+	fs.StringVar(&in.bundle.HardwareCounters, "hardware-counters", "", "optional hardware_counters.json from a PERF_COUNTERS run")
+	fs.StringVar(&in.bundle.Energy, "energy", "", "optional energy.json from an ENERGY run")
+	fs.StringVar(&in.bundle.Environment, "environment", "", "optional environment.json describing the benchmark host")
+	fs.StringVar(&in.bundle.BuildInfo, "build-info", "", "optional build_info.json with the linked SDK version and git commit")
+	fs.StringVar(&in.bundle.Scheduling, "scheduling", "", "optional scheduling.json with the GOMAXPROCS and CPU affinity of the run")
+	fs.StringVar(&in.bundle.Control, "control", "", "optional control.json with the interleaved control benchmark samples")
+	fs.StringVar(&in.bundle.Skipped, "skipped", "", "optional skipped.json listing operations not run for missing datasets")
+	fs.StringVar(&in.bundle.Robustness, "robustness", "", "optional robustness.json with the outcome of each deserialize_invalid input")
+	fs.StringVar(&in.bundle.Containment, "containment", "", "optional containment.json with the outcome of each operation group under run --memory-cap")
+	fs.StringVar(&in.bundle.Flamegraphs, "flamegraphs", "", "optional flamegraphs.json from run --cpu-profile, its SVG paths relative to the report")
+	fs.StringVar(&in.bundle.ExecTraces, "exec-traces", "", "optional exec_traces.json from run --trace, its trace paths relative to the report")
+	fs.StringVar(&in.bundle.Soak, "soak", "", "optional soak.json from run --soak")
+	fs.StringVar(&in.bundle.OutputSizes, "output-sizes", "", "optional output_sizes.json with the plain and compressed size of every serializing operation's document")
+	fs.StringVar(&in.bundle.Operations, "operations", "", "optional operations.json manifest with the benchmark names and tracks of the run's operations")
+	fs.StringVar(&in.bundle.Partial, "partial", "", "optional partial/ directory of per-operation progress files left by a run that crashed")
+	expect := fs.String("expect", "", "comma-separated operations the run was to measure; those without results are marked incomplete")
+	fs.StringVar(&in.bundle.Aliases, "aliases", "", aliasesUsage)
+	fs.StringVar(&in.datasets, "datasets", "", "optional dataset directory to fingerprint into datasets_manifest")
+	fs.StringVar(&in.benchText, "bench-txt", "", "optional path to also write the results in Go benchmark format, for benchstat")
+	fs.StringVar(&in.sdkID, "sdk-id", report.DefaultSDKID, "sdk_id to record, for adapters and wrapper repositories reusing this emitter")
+	fs.Var(&in.meta, "meta", "key=value overriding a whitelisted metadata field, e.g. benchmark_harness=... (repeatable)")
+	fs.Var(&sweeps, "sweep", "benchtime=bench_raw.json from a benchtime sweep (repeatable)")
+	fs.Float64Var(&in.stabilityThreshold, "stability-threshold", report.DefaultStabilityThresholdPct, "sweep spread in percent above which an operation is unstable")
+	fs.Float64Var(&in.noiseThreshold, "noise-threshold", report.DefaultNoiseThresholdPct, noiseThresholdUsage)
+	outlierFlag(fs, &in.outlierPolicy)
+	strict := fs.Bool("strict", false, strictUsage)
+	in.limits.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "input", "output", "sdk-id"); err != nil {
+		return err
+	}
+	if !sdkIDPattern.MatchString(in.sdkID) {
+		return fmt.Errorf("invalid --sdk-id %q (want lower-case letters, digits, '.', '_' or '-')", in.sdkID)
+	}
+	in.bundle.Sweeps = sweeps
+	in.bundle.Mode = parseMode(*strict)
+	in.expected = splitList(*expect)
+	return emitReport(inv, in)
+}
+
+const (
+	strictUsage         = "fail on corrupt or failed benchmark output instead of recording it in parse_diagnostics"
+	noiseThresholdUsage = "control benchmark drift in percent above which environment_noise is high"
+	aliasesUsage        = `JSON file {"aliases": {"BenchName": "operation_id"}} extending the embedded benchmark name aliases`
+)
+
+// outlierFlag registers --outliers, the outlier policy, on fs.
+func outlierFlag(fs *flag.FlagSet, policy *string) {
+	*policy = report.DefaultOutlierPolicy
+	fs.Func("outliers", "outlier `policy` trimming each operation's samples before its statistics: "+
+		strings.Join(report.OutlierPolicies, ", ")+" (none reports the raw samples; default "+report.DefaultOutlierPolicy+")", func(v string) error {
+		if !report.ValidOutlierPolicy(v) {
+			return fmt.Errorf("unknown policy %q (want %s)", v, strings.Join(report.OutlierPolicies, ", "))
+		}
+		*policy = v
+		return nil
+	})
+}
+
+func parseMode(strict bool) report.ParseMode {
+	if strict {
+		return report.ParseStrict
+	}
+	return report.ParseLenient
+}
+
+// reportDetails is the status.json detail block of run and emit-report.
+type reportDetails struct {
+	Report     string `json:"report"`
+	Datasets   int    `json:"datasets"`
+	Operations int    `json:"operations"`
+	// Unstable counts operations whose ns/op depends on the benchtime.
+	Unstable int `json:"unstable,omitempty"`
+	// EnvironmentNoise is the report's environment_noise verdict.
+	EnvironmentNoise string `json:"environment_noise,omitempty"`
+	// Runs is how many runs a merged report combines.
+	Runs int `json:"runs,omitempty"`
+	// SuperLinear lists "<shape>/<operation>" for every scaling curve
+	// steeper than report.DefaultSuperLinearExponent.
+	SuperLinear []string `json:"super_linear,omitempty"`
+}
+
+// emitReport parses a harness bundle and writes report.json. Unreadable
+// side channels only warn.
+func emitReport(inv *invocation, in reportInputs) error {
+	results, opts, err := in.bundle.Load(slog.Default())
+	if err != nil {
+		return fmt.Errorf("parsing benchmark results: %w", err)
+	}
+
+	if in.datasets != "" {
+		used := make(map[string]bool)
+		for _, r := range results {
+			used[r.Dataset] = true
+		}
+		manifest, err := dataset.Manifest(in.datasets, used)
+		if err != nil {
+			return fmt.Errorf("fingerprinting datasets: %w", err)
+		}
+		opts.DatasetsManifest = manifest
+		slog.Info("fingerprinted datasets", "files", len(manifest), "dir", in.datasets)
+	}
+	opts.StabilityThresholdPct = in.stabilityThreshold
+	opts.NoiseThresholdPct = in.noiseThreshold
+	opts.OutlierPolicy = in.outlierPolicy
+	opts.SDKVersion = in.sdkVersion
+	opts.SDKID = in.sdkID
+	opts.ExpectedOperations = in.expected
+
+	rep := report.Build(results, opts)
+	if err := in.meta.apply(&rep.Metadata); err != nil {
+		return err
+	}
+	if in.benchText != "" {
+		if err := writeBenchText(in.benchText, rep, results); err != nil {
+			return err
+		}
+	}
+	return writeReport(inv, in.output, rep, in.limits)
+}
+
+// writeReport writes rep to output within limits and records its summary
+// as the status details.
+func writeReport(inv *invocation, output string, rep *report.Report, limits reportLimits) error {
+	if limits.redact {
+		if err := redactReport(output, rep); err != nil {
+			return err
+		}
+	}
+	if err := report.SetSchemaVersion(rep, limits.schemaVersion); err != nil {
+		return fmt.Errorf("--schema-version: %w", err)
+	}
+	if err := writeLimited(output, rep, limits); err != nil {
+		return err
+	}
+	if err := checkWritten(output); err != nil {
+		return err
+	}
+	details := summarize(output, rep)
+	if details.Unstable > 0 {
+		slog.Warn("operations are unstable across benchtimes (see \"stability\" in the report)", "count", details.Unstable)
+	}
+	if len(details.SuperLinear) > 0 {
+		slog.Warn("super-linear scaling (see \"scaling\" in the report)",
+			"exponent_above", report.DefaultSuperLinearExponent, "operations", strings.Join(details.SuperLinear, ", "))
+	}
+	if c := rep.ControlBenchmark; rep.EnvironmentNoise == report.NoiseHigh {
+		slog.Warn("environment noise is high", "control_drift_pct", c.DriftPct,
+			"threshold_pct", c.ThresholdPct, "slowest_before", c.SlowestBefore)
+	}
+	inv.details = details
+	slog.Info("wrote report", "path", output)
+	return nil
+}
+
+// writeLimited writes rep to path trimmed to the limits. When anything is
+// cut, the untrimmed report is kept next to it as <name>.full.json and
+// linked from full_report. A report still larger than maxBytes is reduced
+// to a summary.
+func writeLimited(path string, rep *report.Report, limits reportLimits) error {
+	full, err := json.MarshalIndent(rep, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+	fullPath := strings.TrimSuffix(path, ".json") + ".full.json"
+	keepFull := func() error {
+		if rep.FullReport != "" {
+			return nil
+		}
+		if err := os.WriteFile(fullPath, full, 0644); err != nil {
+			return fmt.Errorf("write full report: %w", err)
+		}
+		rep.FullReport = filepath.Base(fullPath)
+		return nil
+	}
+
+	if cut := limits.limits().Apply(rep); len(cut) > 0 {
+		if err := keepFull(); err != nil {
+			return err
+		}
+		slog.Warn("truncated lists in report sections", "lists", len(cut), "full_report", fullPath)
+	} else {
+		// Do not leave an untrimmed report from an earlier run next to
+		// this one.
+		os.Remove(fullPath)
+	}
+	if err := report.Write(path, rep); err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil || limits.maxBytes <= 0 || info.Size() <= limits.maxBytes {
+		return err
+	}
+
+	slog.Warn("report is over --max-report-bytes; writing a summary instead", "path", path, "bytes", info.Size(), "max_bytes", limits.maxBytes)
+	if err := keepFull(); err != nil {
+		return err
+	}
+	// Start over from the untrimmed report so the markers count the
+	// original lists.
+	var summary report.Report
+	if err := json.Unmarshal(full, &summary); err != nil {
+		return err
+	}
+	report.SummaryLimits().Apply(&summary)
+	summary.FullReport = rep.FullReport
+	*rep = summary
+	return report.Write(path, rep)
+}
+
+// writeBenchText writes the results of rep to path in Go benchmark format.
+func writeBenchText(path string, rep *report.Report, results map[string]*report.BenchResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := report.WriteBenchText(f, rep, results); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// checkWritten validates a report just written to path, rejecting operation
+// IDs that collide with or impersonate canonical ones.
+func checkWritten(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if problems := report.Validate(data); len(problems) > 0 {
+		for _, p := range problems {
+			slog.Error("emitted report is invalid", "path", path, "problem", p)
+		}
+		return schemaErr(fmt.Errorf("emitted report %s is invalid", path))
+	}
+	return nil
+}
+
+// summarize counts the datasets, operations and unstable operations of rep.
+func summarize(path string, rep *report.Report) reportDetails {
+	details := reportDetails{Report: path, Datasets: len(rep.Datasets), EnvironmentNoise: rep.EnvironmentNoise}
+	for _, ds := range rep.Datasets {
+		details.Operations += len(ds.Operations)
+		for _, op := range ds.Operations {
+			if op.Stability != nil && !op.Stability.Stable {
+				details.Unstable++
+			}
+		}
+	}
+	if rep.RunVariance != nil {
+		details.Runs = rep.RunVariance.Runs
+	}
+	for _, c := range rep.Scaling {
+		if c.SuperLinear {
+			details.SuperLinear = append(details.SuperLinear, c.Shape+"/"+c.OperationID)
+		}
+	}
+	return details
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/exectrace"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// summarizeExecTraces summarizes the execution traces an EXEC_TRACE run
+// left in dir through go tool trace, the parser of the toolchain that
+// wrote them, and writes the summaries to dir/exec_traces.json. It returns
+// nil when dir holds no traces.
+func summarizeExecTraces(h harness, dir string) (report.ExecTraces, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+report.ExecTraceSuffix))
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+	sort.Strings(paths)
+	traces := make(report.ExecTraces)
+	for _, path := range paths {
+		cmd := exec.Command("go", "tool", "trace", "-d=parsed", path)
+		cmd.Dir = h.dir
+		cmd.Stderr = os.Stderr
+		out, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, err
+		}
+		start := time.Now()
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("go tool trace: %w", err)
+		}
+		operation, t, serr := exectrace.Summarize(out)
+		// Summarize stops at the region's end; drain the rest so the
+		// tool does not block on a full pipe.
+		_, _ = io.Copy(io.Discard, out)
+		err = cmd.Wait()
+		h.manifest.process(cmd, nil, time.Since(start), err)
+		if serr != nil {
+			return nil, fmt.Errorf("%s: %w", path, serr)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("go tool trace %s: %w", path, err)
+		}
+		if want := strings.TrimSuffix(filepath.Base(path), report.ExecTraceSuffix); operation != want {
+			return nil, fmt.Errorf("%s: traced region is of %s", path, operation)
+		}
+		t.File = filepath.Base(path)
+		traces[operation] = t
+	}
+	return traces, writeJSON(filepath.Join(dir, report.ExecTracesFile), traces)
+}
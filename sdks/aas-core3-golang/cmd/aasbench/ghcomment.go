@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/ghcomment"
+)
+
+func runGHComment(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "gh-comment", "--comparison comparison.json [flags]")
+	comparisonPath := fs.String("comparison", "", "comparison.json written by diff --output (required)")
+	repo := fs.String("repo", os.Getenv("GITHUB_REPOSITORY"), "repository as owner/name (default $GITHUB_REPOSITORY)")
+	pr := fs.Int("pr", pullRequestFromRef(os.Getenv("GITHUB_REF")), "pull request number (default: from $GITHUB_REF on pull_request events)")
+	apiURL := fs.String("api-url", os.Getenv("GITHUB_API_URL"), "GitHub REST API URL (default $GITHUB_API_URL or "+ghcomment.DefaultAPIURL+")")
+	key := fs.String("key", "", "identifies the comment to update on reruns (default: the current report's sdk_id)")
+	runURL := fs.String("run-url", actionsRunURL(), "workflow run to link from the comment (default: the current GitHub Actions run)")
+	dryRun := fs.Bool("dry-run", false, "print the comment instead of posting it")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "comparison"); err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*comparisonPath)
+	if err != nil {
+		return loadErr(err)
+	}
+	var c compare.Comparison
+	if err := json.Unmarshal(data, &c); err != nil {
+		return loadErr(fmt.Errorf("%s: %w", *comparisonPath, err))
+	}
+	if *key == "" {
+		*key = c.CurrentSDKID
+	}
+
+	var body bytes.Buffer
+	err = commentMarkdown.Execute(&body, struct {
+		*compare.Comparison
+		Tracks []compare.TrackDeltas
+		RunURL string
+	}{&c, c.ByTrack(), *runURL})
+	if err != nil {
+		return err
+	}
+	details := ghCommentDetails{Regressions: c.Summary.Regressions, Tracks: len(c.ByTrack())}
+	inv.details = &details
+	if *dryRun {
+		fmt.Print(ghcomment.Marker(*key) + "\n" + body.String())
+		return nil
+	}
+
+	if *repo == "" || *pr <= 0 {
+		return fmt.Errorf("--repo and --pr are required outside a pull_request workflow")
+	}
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return fmt.Errorf("GITHUB_TOKEN is not set")
+	}
+	url, updated, err := ghcomment.NewClient(*apiURL, token).Upsert(*repo, *pr, *key, body.String())
+	if err != nil {
+		return err
+	}
+	details.URL, details.Updated = url, updated
+	slog.Info("posted benchmark comment", "repo", *repo, "pr", *pr, "url", url, "updated", updated)
+	return nil
+}
+
+// pullRequestFromRef returns N for a refs/pull/N/merge ref, else 0.
+func pullRequestFromRef(ref string) int {
+	rest, ok := strings.CutPrefix(ref, "refs/pull/")
+	if !ok {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSuffix(rest, "/merge"))
+	return n
+}
+
+// actionsRunURL links the current GitHub Actions run, if any.
+func actionsRunURL() string {
+	server, repo, id := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID")
+	if server == "" || repo == "" || id == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/%s/actions/runs/%s", server, repo, id)
+}
+
+// ghCommentDetails is the status.json detail block of gh-comment.
+type ghCommentDetails struct {
+	Regressions int    `json:"regressions"`
+	Tracks      int    `json:"tracks"`
+	URL         string `json:"url,omitempty"`
+	Updated     bool   `json:"updated"`
+}
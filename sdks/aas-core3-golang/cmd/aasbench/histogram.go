@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/hdr"
+)
+
+func runHistogram(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "histogram", "--output k6_histograms_<server_id>.json <k6 --out json file>...")
+	output := fs.String("output", "", "histograms file to write (required)")
+	digits := fs.Int("digits", hdr.DefaultSignificantDigits, "significant digits the histograms keep (1-5)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "output"); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no k6 output given")
+	}
+
+	// The streams are read as one, so an operation several scripts share
+	// gets one histogram.
+	var readers []io.Reader
+	for _, path := range fs.Args() {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		readers = append(readers, f, strings.NewReader("\n"))
+	}
+	file, err := hdr.ReadK6(io.MultiReader(readers...), *digits)
+	if err != nil {
+		return schemaErr(err)
+	}
+	if err := writeJSON(*output, file); err != nil {
+		return err
+	}
+
+	ops := make([]string, 0, len(file.Operations))
+	for op := range file.Operations {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		s := file.Operations[op]
+		fmt.Printf("%-40s n=%-7d p50=%.2fms p99=%.2fms p99.9=%.2fms\n", op, s.Count,
+			float64(s.P50)/1000, float64(s.P99)/1000, float64(s.P999)/1000)
+	}
+	inv.details = histogramDetails{Output: *output, Operations: len(ops)}
+	return nil
+}
+
+// histogramDetails is the status.json detail block of histogram.
+type histogramDetails struct {
+	Output     string `json:"output"`
+	Operations int    `json:"operations"`
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/ingest"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// ingestFormats maps --format values to the readers of other harnesses'
+// output.
+var ingestFormats = map[string]func(path string) (*ingest.Result, error){
+	"benchmarkdotnet":  ingest.BenchmarkDotNet,
+	"criterion":        ingest.Criterion,
+	"jmh":              ingest.JMH,
+	"pytest-benchmark": ingest.PytestBenchmark,
+}
+
+func runIngest(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "ingest", "--format <format> --input <path> --output report.json --sdk-id <id> [flags]")
+	format := fs.String("format", "", "harness output format: "+strings.Join(ingestFormatNames(), ", ")+" (required)")
+	input := fs.String("input", "", "harness output to convert, e.g. target/criterion or jmh_results.json (required)")
+	output := fs.String("output", "", "path to write report.json (required)")
+	sdkID := fs.String("sdk-id", "", "sdk_id of the adapter the results belong to (required)")
+	var meta metaOverrides
+	fs.Var(&meta, "meta", "key=value setting a whitelisted metadata field, e.g. sdk_package_version=0.4.0 (repeatable)")
+	redact := fs.Bool("redact", false, redactUsage)
+	var schemaVersion int
+	schemaVersionFlag(fs, &schemaVersion)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "format", "input", "output", "sdk-id"); err != nil {
+		return err
+	}
+	read, ok := ingestFormats[*format]
+	if !ok {
+		return fmt.Errorf("unknown --format %q (want one of %s)", *format, strings.Join(ingestFormatNames(), ", "))
+	}
+	if !sdkIDPattern.MatchString(*sdkID) {
+		return fmt.Errorf("invalid --sdk-id %q (want lower-case letters, digits, '.', '_' or '-')", *sdkID)
+	}
+
+	result, err := read(*input)
+	if err != nil {
+		return loadErr(err)
+	}
+	rep := result.Report(*sdkID)
+	if err := meta.apply(&rep.Metadata); err != nil {
+		return err
+	}
+	if *redact {
+		if err := redactReport(*output, rep); err != nil {
+			return err
+		}
+	}
+	if err := report.SetSchemaVersion(rep, schemaVersion); err != nil {
+		return fmt.Errorf("--schema-version: %w", err)
+	}
+	if err := report.Write(*output, rep); err != nil {
+		return err
+	}
+	if err := checkWritten(*output); err != nil {
+		return err
+	}
+	details := summarize(*output, rep)
+	inv.details = details
+	slog.Info("converted benchmark output", "format", *format, "operations", details.Operations,
+		"datasets", details.Datasets, "path", *output)
+	return nil
+}
+
+func ingestFormatNames() []string {
+	names := make([]string, 0, len(ingestFormats))
+	for name := range ingestFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/plan"
+)
+
+func runInit(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "init", "[flags]")
+	root := fs.String("root", "", "repository root holding known-sdks.json (default: search upwards from .)")
+	datasetsDir := fs.String("datasets", "", "dataset directory (default: $DATASETS_DIR, datasets/generated, /tmp/aas-datasets)")
+	outputPath := fs.String("output", "aasbench.plan.json", "path to write the plan")
+	yes := fs.Bool("yes", false, "accept every detected default without prompting")
+	force := fs.Bool("force", false, "overwrite an existing plan file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if _, err := os.Stat(*outputPath); err == nil && !*force {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", *outputPath)
+	}
+
+	if *root == "" {
+		r, err := plan.FindRoot(".")
+		if err != nil {
+			return err
+		}
+		*root = r
+	}
+	sdks, err := plan.DetectSDKs(*root)
+	if err != nil {
+		return err
+	}
+	servers, err := plan.DetectServers(*root)
+	if err != nil {
+		return err
+	}
+	dsDir, dsNames := plan.DetectDatasets([]string{
+		*datasetsDir,
+		os.Getenv("DATASETS_DIR"),
+		filepath.Join(*root, "datasets", "generated"),
+		"/tmp/aas-datasets",
+	})
+
+	fmt.Fprintf(os.Stderr, "Repository root: %s\n", *root)
+	fmt.Fprintf(os.Stderr, "Detected %d SDK adapter(s):\n", len(sdks))
+	for _, s := range sdks {
+		v := s.Version
+		if v == "" {
+			v = "version unknown"
+		}
+		fmt.Fprintf(os.Stderr, "  %-22s %s (%s)\n", s.ID, s.Package, v)
+	}
+	fmt.Fprintf(os.Stderr, "Detected %d server(s):\n", len(servers))
+	for _, s := range servers {
+		fmt.Fprintf(os.Stderr, "  %-22s %s\n", s.ID, s.Image)
+	}
+	if dsDir == "" {
+		fmt.Fprintf(os.Stderr, "No dataset files found; generate them with: python3 datasets/generate.py --output-dir datasets/generated\n")
+	} else {
+		fmt.Fprintf(os.Stderr, "Detected datasets in %s: %s\n", dsDir, strings.Join(dsNames, ", "))
+	}
+	fmt.Fprintln(os.Stderr)
+
+	ask := &prompter{in: bufio.NewReader(os.Stdin), yes: *yes}
+	p := &plan.Plan{Version: plan.FormatVersion}
+	p.DatasetsDir = ask.line("Datasets directory", dsDir)
+	if p.DatasetsDir != dsDir {
+		_, dsNames = plan.DetectDatasets([]string{p.DatasetsDir})
+	}
+	p.Datasets = ask.subset("Datasets", dsNames)
+
+	sdkIDs := make([]string, len(sdks))
+	for i, s := range sdks {
+		sdkIDs[i] = s.ID
+	}
+	for _, id := range ask.subset("SDK adapters", sdkIDs) {
+		for _, s := range sdks {
+			if s.ID == id {
+				p.SDKs = append(p.SDKs, s)
+			}
+		}
+	}
+
+	serverIDs := make([]string, len(servers))
+	for i, s := range servers {
+		serverIDs[i] = s.ID
+	}
+	for _, id := range ask.subset("Servers", serverIDs) {
+		for _, s := range servers {
+			if s.ID == id {
+				p.Servers = append(p.Servers, s)
+			}
+		}
+	}
+
+	p.OutputDir = ask.line("Output directory", "/tmp/aas-results")
+	p.Count = ask.number("Runs per benchmark", 5)
+	p.HeapProfile = ask.line("Capture heap profiles (y/N)", "n") == "y"
+
+	if problems := p.Validate(); len(problems) > 0 {
+		for _, pr := range problems {
+			fmt.Fprintf(os.Stderr, "  - %s\n", pr)
+		}
+		return schemaErr(fmt.Errorf("generated plan is invalid"))
+	}
+	if err := plan.Write(*outputPath, p); err != nil {
+		return err
+	}
+	inv.details = map[string]string{"plan": *outputPath}
+	fmt.Fprintf(os.Stderr, "Wrote plan to %s; run it with: aasbench run --plan %s\n", *outputPath, *outputPath)
+	return nil
+}
+
+// prompter asks questions on stderr and reads answers from stdin. An empty
+// answer, end of input or yes mode accepts the default.
+type prompter struct {
+	in  *bufio.Reader
+	yes bool
+}
+
+func (p *prompter) line(question, def string) string {
+	if p.yes {
+		return def
+	}
+	fmt.Fprintf(os.Stderr, "%s [%s]: ", question, def)
+	answer, err := p.in.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if err == io.EOF {
+		fmt.Fprintln(os.Stderr)
+	}
+	if answer == "" {
+		return def
+	}
+	return answer
+}
+
+// subset asks for a comma-separated selection from choices; the default is
+// every choice. Unknown names are rejected and the question repeated.
+func (p *prompter) subset(question string, choices []string) []string {
+	valid := make(map[string]bool, len(choices))
+	for _, c := range choices {
+		valid[c] = true
+	}
+	def := strings.Join(choices, ",")
+	for {
+		var picked, unknown []string
+		for _, name := range strings.Split(p.line(question, def), ",") {
+			name = strings.TrimSpace(name)
+			switch {
+			case name == "":
+			case valid[name]:
+				picked = append(picked, name)
+			default:
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) == 0 {
+			return picked
+		}
+		fmt.Fprintf(os.Stderr, "Unknown %s: %s\n", strings.ToLower(question), strings.Join(unknown, ", "))
+	}
+}
+
+func (p *prompter) number(question string, def int) int {
+	for {
+		answer := p.line(question, strconv.Itoa(def))
+		n, err := strconv.Atoi(answer)
+		if err == nil && n > 0 {
+			return n
+		}
+		fmt.Fprintf(os.Stderr, "Please enter a positive number.\n")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// runIsolated runs every benchmark function h selects in a go test process
+// of its own, with OUTPUT_DIR set to dir/isolated/<function>, and merges
+// their outputs into dir. Each operation group thus starts on a fresh heap,
+// so its heap_used_bytes and GC figures are not carried over from the
+// groups before it. A process that fails stops the run after its output
+// has been merged, so the crash still yields a partial report.
+func runIsolated(h harness, env []string, dir string) error {
+	groups, rest, err := h.list()
+	if err != nil {
+		return err
+	}
+	root := filepath.Join(dir, report.IsolatedDir)
+	if err := os.RemoveAll(root); err != nil {
+		return err
+	}
+	var procDirs []string
+	var runErr error
+	for _, name := range groups {
+		procDir := filepath.Join(root, name)
+		if err := os.MkdirAll(procDir, 0755); err != nil {
+			return err
+		}
+		procDirs = append(procDirs, procDir)
+		g := h
+		g.bench = "^" + name + "$" + rest
+		slog.Info("running isolated", "benchmark", name)
+		rawPath := filepath.Join(procDir, report.BenchRawFile)
+		runErr = g.run(rawPath, append(env, "OUTPUT_DIR="+procDir))
+		if h.wasm != nil {
+			if _, err := report.ExtractSideChannels(rawPath, procDir); err != nil {
+				slog.Warn("could not extract side channels", "benchmark", name, "err", err)
+			}
+		}
+		if runErr != nil {
+			runErr = fmt.Errorf("%s: %w", name, runErr)
+			break
+		}
+	}
+	if err := report.MergeIsolated(dir, procDirs); err != nil {
+		return fmt.Errorf("merge isolated runs: %w", err)
+	}
+	slog.Info("merged isolated runs", "processes", len(procDirs), "dir", dir)
+	return runErr
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// schemaProblems checks the JSON document data against the embedded schema
+// with the given name and returns one message per violation, prefixed with
+// the JSON pointer of the offending value. It implements the subset of
+// JSON Schema 2020-12 the embedded schemas use; format is an annotation
+// and other keywords are ignored. A document that does not parse has no
+// schema problems, since Validate already reports it.
+func schemaProblems(name string, data []byte) ([]string, error) {
+	raw, err := schema(name)
+	if err != nil {
+		return nil, err
+	}
+	var root map[string]interface{}
+	if err := json.Unmarshal(raw, &root); err != nil {
+		return nil, fmt.Errorf("embedded %s schema: %w", name, err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, nil
+	}
+	c := &schemaChecker{root: root}
+	c.check(root, doc, "")
+	return c.problems, nil
+}
+
+// schemaChecker collects the violations of one document.
+type schemaChecker struct {
+	root     map[string]interface{}
+	problems []string
+}
+
+func (c *schemaChecker) fail(pointer, format string, args ...interface{}) {
+	if pointer == "" {
+		pointer = "/"
+	}
+	c.problems = append(c.problems, fmt.Sprintf("schema: %s: %s", pointer, fmt.Sprintf(format, args...)))
+}
+
+// matches reports whether v satisfies s without recording problems, for
+// anyOf and if.
+func (c *schemaChecker) matches(s, v interface{}, pointer string) bool {
+	sub := &schemaChecker{root: c.root}
+	sub.check(s, v, pointer)
+	return len(sub.problems) == 0
+}
+
+func (c *schemaChecker) check(s, v interface{}, pointer string) {
+	switch s := s.(type) {
+	case bool:
+		if !s {
+			c.fail(pointer, "not allowed")
+		}
+		return
+	case map[string]interface{}:
+		c.checkObjectSchema(s, v, pointer)
+	}
+}
+
+func (c *schemaChecker) checkObjectSchema(s map[string]interface{}, v interface{}, pointer string) {
+	if ref, ok := s["$ref"].(string); ok {
+		target, err := c.resolve(ref)
+		if err != nil {
+			c.fail(pointer, "%v", err)
+			return
+		}
+		c.check(target, v, pointer)
+	}
+	if t, ok := s["type"]; ok && !hasSchemaType(t, v) {
+		c.fail(pointer, "want %s, got %s", schemaTypeList(t), jsonTypeOf(v))
+		return
+	}
+	if enum, ok := s["enum"].([]interface{}); ok && !inEnum(enum, v) {
+		c.fail(pointer, "%s is not one of %s", compactJSON(v), compactJSON(enum))
+	}
+	if anyOf, ok := s["anyOf"].([]interface{}); ok {
+		matched := false
+		for _, alt := range anyOf {
+			if c.matches(alt, v, pointer) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			c.fail(pointer, "matches none of the anyOf alternatives")
+		}
+	}
+	if cond, ok := s["if"]; ok {
+		if then, ok := s["then"]; ok && c.matches(cond, v, pointer) {
+			c.check(then, v, pointer)
+		}
+	}
+
+	switch v := v.(type) {
+	case string:
+		if min, ok := s["minLength"].(float64); ok && float64(utf8.RuneCountInString(v)) < min {
+			c.fail(pointer, "%q is shorter than %v characters", v, min)
+		}
+		if pattern, ok := s["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				c.fail(pointer, "invalid pattern %q: %v", pattern, err)
+			} else if !re.MatchString(v) {
+				c.fail(pointer, "%q does not match %q", v, pattern)
+			}
+		}
+	case float64:
+		if min, ok := s["minimum"].(float64); ok && v < min {
+			c.fail(pointer, "%v is less than %v", v, min)
+		}
+		if max, ok := s["maximum"].(float64); ok && v > max {
+			c.fail(pointer, "%v is greater than %v", v, max)
+		}
+		if min, ok := s["exclusiveMinimum"].(float64); ok && v <= min {
+			c.fail(pointer, "%v is not greater than %v", v, min)
+		}
+	case []interface{}:
+		if min, ok := s["minItems"].(float64); ok && float64(len(v)) < min {
+			c.fail(pointer, "has %d items, want at least %v", len(v), min)
+		}
+		if items, ok := s["items"]; ok {
+			for i, item := range v {
+				c.check(items, item, fmt.Sprintf("%s/%d", pointer, i))
+			}
+		}
+	case map[string]interface{}:
+		c.checkObject(s, v, pointer)
+	}
+}
+
+func (c *schemaChecker) checkObject(s, v map[string]interface{}, pointer string) {
+	if required, ok := s["required"].([]interface{}); ok {
+		for _, key := range required {
+			if key, ok := key.(string); ok {
+				if _, present := v[key]; !present {
+					c.fail(pointer, "missing required property %q", key)
+				}
+			}
+		}
+	}
+	if min, ok := s["minProperties"].(float64); ok && float64(len(v)) < min {
+		c.fail(pointer, "has %d properties, want at least %v", len(v), min)
+	}
+	properties, _ := s["properties"].(map[string]interface{})
+	keys := make([]string, 0, len(v))
+	for key := range v {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		child := pointer + "/" + escapePointer(key)
+		if names, ok := s["propertyNames"]; ok && !c.matches(names, key, child) {
+			c.fail(child, "property name %q is not allowed", key)
+		}
+		if sub, ok := properties[key]; ok {
+			c.check(sub, v[key], child)
+		} else if additional, ok := s["additionalProperties"]; ok {
+			if additional == false {
+				c.fail(pointer, "unknown property %q", key)
+			} else {
+				c.check(additional, v[key], child)
+			}
+		}
+	}
+}
+
+// resolve looks up a reference to the schema's own $defs.
+func (c *schemaChecker) resolve(ref string) (interface{}, error) {
+	name, ok := strings.CutPrefix(ref, "#/$defs/")
+	if !ok {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	defs, _ := c.root["$defs"].(map[string]interface{})
+	target, ok := defs[name]
+	if !ok {
+		return nil, fmt.Errorf("unresolved $ref %q", ref)
+	}
+	return target, nil
+}
+
+// hasSchemaType reports whether v is of the type, or one of the types, t
+// names.
+func hasSchemaType(t, v interface{}) bool {
+	switch t := t.(type) {
+	case string:
+		return isSchemaType(t, v)
+	case []interface{}:
+		for _, name := range t {
+			if name, ok := name.(string); ok && isSchemaType(name, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isSchemaType(name string, v interface{}) bool {
+	switch v := v.(type) {
+	case nil:
+		return name == "null"
+	case bool:
+		return name == "boolean"
+	case string:
+		return name == "string"
+	case float64:
+		return name == "number" || name == "integer" && v == math.Trunc(v)
+	case []interface{}:
+		return name == "array"
+	case map[string]interface{}:
+		return name == "object"
+	}
+	return false
+}
+
+func schemaTypeList(t interface{}) string {
+	if list, ok := t.([]interface{}); ok {
+		names := make([]string, len(list))
+		for i, name := range list {
+			names[i] = fmt.Sprint(name)
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+func jsonTypeOf(v interface{}) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case []interface{}:
+		return "array"
+	}
+	return "object"
+}
+
+func inEnum(enum []interface{}, v interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func compactJSON(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
+
+// escapePointer escapes a property name for a JSON pointer (RFC 6901).
+func escapePointer(key string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(key, "~", "~0"), "/", "~1")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// TestBuiltReportMatchesSchema holds a report built the way emit-report
+// builds one to the embedded report.schema.json, so the schema cannot drift
+// from what the emitter writes.
+func TestBuiltReportMatchesSchema(t *testing.T) {
+	memTotal := int64(16 << 30)
+	elements := int64(1200)
+	results := map[string]*report.BenchResult{
+		"wide/deserialize": {Dataset: "wide", Operation: "deserialize", Benchmark: "Deserialize", N: 100, NsPerOp: 1050,
+			BytesPerOp: 4096, AllocsPerOp: 12, Runs: []float64{1000, 1100}, BytesRuns: []int64{4096, 4096}, AllocsRuns: []int64{12, 12}},
+		"wide/validate": {Dataset: "wide", Operation: "validate", Benchmark: "Validate", N: 300, NsPerOp: 330, Runs: []float64{320, 340}},
+	}
+	rep := report.Build(results, report.Options{
+		SDKVersion:       "v1.0.0",
+		BuildInfo:        &report.BuildInfo{GoVersion: "go1.22.0", SDKModule: "github.com/aas-core-works/aas-core3.0-golang", SDKVersion: "v1.0.0", Platform: "linux/amd64"},
+		Scheduling:       &report.Scheduling{GOMAXPROCS: 4, CPUAffinity: "0-3", Pinned: true},
+		DatasetsManifest: []report.DatasetFingerprint{{Dataset: "wide", File: "wide.json", Format: "json", SHA256: strings.Repeat("ab", 32), SizeBytes: 2048, ElementCount: &elements}},
+		Environment:      &report.Environment{OS: "linux", Arch: "amd64", CPU: report.CPUInfo{Model: "test", LogicalCores: 4}, MemoryTotalBytes: &memTotal},
+		ParseDiagnostics: &report.ParseDiagnostics{Mode: report.ParseLenient, LinesRead: 10, BenchmarksMatched: 2},
+		Skipped:          &report.Skipped{Entries: []report.SkippedEntry{{Operation: "validate", Dataset: "deep", Reason: "no deep.json in the datasets directory"}}},
+		Control: &report.Control{Workload: "sha256", Iterations: 1000, Samples: []report.ControlSample{
+			{Before: "deserialize", At: time.Unix(0, 0).UTC(), Ns: 100},
+			{Before: "validate", At: time.Unix(1, 0).UTC(), Ns: 104},
+		}},
+	})
+	if err := report.SetSchemaVersion(rep, report.SchemaVersion); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(rep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems, err := schemaProblems("report", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		t.Error(p)
+	}
+}
+
+func TestSchemaProblems(t *testing.T) {
+	data := []byte(`{
+		"schema_version": 2,
+		"sdk_id": "",
+		"metadata": {"language": "go", "runtime_version": "go1.22", "sdk_package_version": "v1", "benchmark_harness": "testing", "timestamp": "2026-01-01T00:00:00Z", "colour": "blue"},
+		"datasets": {"wide": {"operations": {"Deserialize": {"operation_id": "deserialize", "operation_track": "core", "sample_count": -1, "measurement_semantics": "per_op", "failure_state": "ok", "mean_ns": 1.5}}}},
+		"environment_noise": "medium"
+	}`)
+	problems, err := schemaProblems("report", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`schema: /sdk_id: "" is shorter than 1 characters`,
+		`schema: /metadata: unknown property "colour"`,
+		`schema: /datasets/wide/operations/Deserialize: property name "Deserialize" is not allowed`,
+		`schema: /datasets/wide/operations/Deserialize/mean_ns: want integer, got number`,
+		`schema: /datasets/wide/operations/Deserialize/sample_count: -1 is less than 0`,
+		`schema: /environment_noise: "medium" is not one of ["low","high"]`,
+	}
+	got := strings.Join(problems, "\n")
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("missing problem %q in:\n%s", w, got)
+		}
+	}
+	if len(problems) != len(want) {
+		t.Errorf("got %d problems, want %d:\n%s", len(problems), len(want), got)
+	}
+
+	if problems, err := schemaProblems("report", []byte("{")); err != nil || problems != nil {
+		t.Errorf("unparsable report = %v, %v; want no schema problems", problems, err)
+	}
+}
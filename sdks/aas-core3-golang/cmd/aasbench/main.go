@@ -0,0 +1,160 @@
+// Command aasbench is the single-binary distribution of the AAS benchmark
+// observatory tooling: running the Go benchmarks, emitting report.json,
+// diffing and merging reports, validating adapter output and fingerprinting
+// datasets. The report schemas and output templates are embedded, so adapter
+// repositories and CI only need to download this one artifact.
+//
+// Install a release binary from the GitHub releases page, or build from source:
+//
+//	go install github.com/aas-benchmark-observatory/sdks/aas-core3-golang/cmd/aasbench@latest
+//
+// Usage:
+//
+//	aasbench <command> [flags]
+//
+// Every command exits 0 on success, 1 when regressions were detected, 2 on
+// report schema errors and 3 on infrastructure failures, and writes the same
+// outcome as JSON to the path given by --status.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/logging"
+)
+
+// command is one aasbench subcommand.
+type command struct {
+	name    string
+	summary string
+	run     func(inv *invocation, args []string) error
+	// aliases are earlier names kept so existing scripts keep working.
+	aliases []string
+}
+
+var commands = []command{
+	{"run", "Run the Go benchmark suite and emit report.json", runBenchmarks, []string{"bench"}},
+	{"runs", "List or prune the runs kept under a run --results directory", runRuns, nil},
+	{"emit-report", "Convert go test -json benchmark output to report.json", runEmitReport, []string{"report"}},
+	{"ingest", "Convert another harness's benchmark output (Criterion, JMH, BenchmarkDotNet, pytest-benchmark) to report.json", runIngest, nil},
+	{"diff", "Compare two report.json files and flag significant changes", runDiff, []string{"compare"}},
+	{"gh-comment", "Post or update a pull request comment summarizing a comparison.json", runGHComment, nil},
+	{"crosscheck", "Check that two language adapters' reports measured identical inputs the same way", runCrossCheck, nil},
+	{"render", "Render up to 8 reports as an overlaid HTML chart and merged table", runRender, nil},
+	{"merge", "Merge per-adapter result directories into results.json", runMerge, []string{"aggregate"}},
+	{"monthly", "Summarize a month of stored results.json as a markdown or HTML report", runMonthly, nil},
+	{"trend", "Find changepoints in the performance history of stored reports", runTrend, nil},
+	{"histogram", "Record k6 request samples as HDR latency histograms per operation", runHistogram, nil},
+	{"badges", "Write shields.io endpoint badges from an aggregated results.json", runBadges, nil},
+	{"site", "Write a static comparison site with SVG charts from an aggregated results.json", runSite, nil},
+	{"validate", "Validate report.json integrity and canonical operation naming", runValidate, nil},
+	{"contract", "Check a report against the SDK adapter contract and write checklist.json", runContract, nil},
+	{"backfill", "Regenerate reports from archived bench_raw.json bundles", runBackfill, nil},
+	{"dataset", "Fingerprint, verify, pack or fetch dataset files", runDataset, nil},
+	{"schema", "Print an embedded JSON schema (report, status, plan)", runSchema, nil},
+	{"init", "Detect datasets, SDKs and servers and write a benchmark plan", runInit, nil},
+	{"completion", "Print a bash, zsh or fish completion script", runCompletion, nil},
+	{"version", "Print the aasbench version", runVersion, nil},
+}
+
+// lookup finds a command by name or alias.
+func lookup(name string) (command, bool) {
+	for _, c := range commands {
+		if c.name == name {
+			return c, true
+		}
+		for _, alias := range c.aliases {
+			if alias == name {
+				return c, true
+			}
+		}
+	}
+	return command{}, false
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage: aasbench <command> [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(os.Stderr, "\nRun 'aasbench <command> -h' for command flags.\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(exitInfrastructure)
+	}
+	name := os.Args[1]
+	if name == "-h" || name == "--help" || name == "help" {
+		usage()
+		return
+	}
+	if name == completeCommand {
+		complete(os.Stdout, os.Args[2:])
+		return
+	}
+	c, ok := lookup(name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "aasbench: unknown command %q\n\n", name)
+		usage()
+		os.Exit(exitInfrastructure)
+	}
+	logging.FromEnv()
+	inv := &invocation{}
+	started := time.Now()
+	err := c.run(inv, os.Args[2:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	code := exitCode(err)
+	inv.writeStatus("aasbench "+c.name, code, err, started)
+	if err != nil {
+		slog.Error("command failed", "command", c.name, "err", err)
+	}
+	os.Exit(code)
+}
+
+// newFlagSet returns a flag set whose usage line names the subcommand and
+// that accepts the common --status and logging flags. The logging flags
+// reconfigure the logger as they are parsed and export their values, so the
+// processes a command starts log the same way under the same run ID.
+func newFlagSet(inv *invocation, name, args string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	inv.flags = fs
+	if inv.completing {
+		fs.SetOutput(io.Discard)
+	}
+	fs.StringVar(&inv.statusPath, "status", "", "write the structured outcome as JSON to this path")
+	fs.Func("log-format", "log line `format`: text or json (default $"+logging.EnvFormat+" or text)", func(v string) error {
+		return logging.Setup(v, os.Getenv(logging.EnvLevel))
+	})
+	fs.Func("log-level", "lowest `level` logged: debug, info, warn or error (default $"+logging.EnvLevel+" or info)", func(v string) error {
+		return logging.Setup(os.Getenv(logging.EnvFormat), v)
+	})
+	fs.Func("run-id", "`ID` attached to every log line (default $"+logging.EnvRunID+" or generated)", func(v string) error {
+		os.Setenv(logging.EnvRunID, v)
+		return logging.Setup(os.Getenv(logging.EnvFormat), os.Getenv(logging.EnvLevel))
+	})
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: aasbench %s %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// requireFlags reports an error naming the first required flag left empty.
+func requireFlags(fs *flag.FlagSet, names ...string) error {
+	for _, name := range names {
+		if f := fs.Lookup(name); f != nil && f.Value.String() == "" {
+			return fmt.Errorf("missing required flag --%s", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/logging"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// runManifestFile is where aasbench run records its runManifest, next to
+// report.json.
+const runManifestFile = "run_manifest.json"
+
+// manifestEnv are the inherited environment variables a runManifest
+// records besides the Go toolchain and runtime settings (GO*): the
+// switches of the harness and of aasbench. Anything else, credentials in
+// particular, is left out.
+var manifestEnv = map[string]bool{
+	"BENCH_GOMAXPROCS": true, "CONTROL_BENCHMARK": true, "CPU_AFFINITY": true, "CPU_PROFILE": true,
+	"DATASETS_DIR": true, "ENERGY": true, "EVENTS_SAMPLE_INTERVAL": true, "EXEC_TRACE": true,
+	"FUZZ_CORPUS": true, "FUZZ_DEADLINE": true, "HEAP_PROFILE": true,
+	"MEMORY_STATS": true, "OUTPUT_DIR": true, "PERF_COUNTERS": true,
+	"POOLED_BENCHMARKS": true, "SERVER_CONTAINERS": true, "SIDE_CHANNEL_SHIM": true,
+	"SOAK_DATASET": true, "SOAK_DURATION": true, "SOAK_INTERVAL": true,
+	"WASM_RUNTIME": true, logging.EnvFormat: true, logging.EnvLevel: true,
+}
+
+// runManifest is run_manifest.json: the command line, environment and
+// exit code of one aasbench run and the wall-clock duration of each of its
+// stages, down to the dataset loads, benchmarks and memory captures inside
+// the harness. It shows where the time went when a nightly run suddenly
+// takes longer.
+type runManifest struct {
+	RunID     string           `json:"run_id"`
+	Command   []string         `json:"command"`
+	Dir       string           `json:"dir"`
+	Env       []string         `json:"env,omitempty"`
+	StartedAt time.Time        `json:"started_at"`
+	DurationS float64          `json:"duration_s"`
+	ExitCode  int              `json:"exit_code"`
+	Error     string           `json:"error,omitempty"`
+	Stages    []*manifestStage `json:"stages"`
+
+	// current is the stage running, which processes are recorded under.
+	current *manifestStage
+}
+
+// manifestStage is one stage of a run.
+type manifestStage struct {
+	Name string `json:"name"`
+	// Run numbers the --runs repetition the stage belongs to, from 1; it is
+	// 0 for a single run and for the stages around the repetitions.
+	Run       int       `json:"run,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	DurationS float64   `json:"duration_s"`
+	Error     string    `json:"error,omitempty"`
+	// Processes are the go test processes the stage ran.
+	Processes []manifestProcess `json:"processes,omitempty"`
+	// HarnessTotalsS sums the harness stages by name, in seconds, and
+	// Harness lists them as the harness timed them (stages.json).
+	HarnessTotalsS map[string]float64 `json:"harness_totals_s,omitempty"`
+	Harness        []report.StageSpan `json:"harness,omitempty"`
+}
+
+// manifestProcess is one process a stage ran.
+type manifestProcess struct {
+	Command []string `json:"command"`
+	Dir     string   `json:"dir"`
+	// Env is what the stage added to the inherited environment.
+	Env       []string `json:"env,omitempty"`
+	DurationS float64  `json:"duration_s"`
+	// ExitCode is -1 when the process could not start or was killed.
+	ExitCode int `json:"exit_code"`
+}
+
+func newRunManifest() *runManifest {
+	dir, _ := os.Getwd()
+	m := &runManifest{
+		RunID:     logging.RunID(),
+		Command:   os.Args,
+		Dir:       dir,
+		StartedAt: time.Now().UTC(),
+		Stages:    []*manifestStage{},
+	}
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		if value != "" && (strings.HasPrefix(name, "GO") || manifestEnv[name]) {
+			m.Env = append(m.Env, kv)
+		}
+	}
+	sort.Strings(m.Env)
+	return m
+}
+
+// stage runs fn as the stage name of repetition run and records how long
+// it took. A nil manifest just runs fn.
+func (m *runManifest) stage(name string, run int, fn func() error) error {
+	if m == nil {
+		return fn()
+	}
+	s := &manifestStage{Name: name, Run: run, StartedAt: time.Now().UTC()}
+	m.Stages = append(m.Stages, s)
+	prev := m.current
+	m.current = s
+	err := fn()
+	m.current = prev
+	s.DurationS = time.Since(s.StartedAt).Seconds()
+	if err != nil {
+		s.Error = err.Error()
+	}
+	return err
+}
+
+// process records cmd, which ran for elapsed with env added to its
+// environment and ended with err, under the running stage.
+func (m *runManifest) process(cmd *exec.Cmd, env []string, elapsed time.Duration, err error) {
+	if m == nil || m.current == nil {
+		return
+	}
+	code := 0
+	if err != nil {
+		code = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code = exitErr.ExitCode()
+		}
+	}
+	m.current.Processes = append(m.current.Processes, manifestProcess{
+		Command:   cmd.Args,
+		Dir:       cmd.Dir,
+		Env:       env,
+		DurationS: elapsed.Seconds(),
+		ExitCode:  code,
+	})
+}
+
+// harnessStages adds the stages the harness timed in dir/stages.json to
+// the running stage. A harness that crashed may have written none.
+func (m *runManifest) harnessStages(dir string) {
+	if m == nil || m.current == nil {
+		return
+	}
+	t, err := report.LoadStageTimings(filepath.Join(dir, report.StagesFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			slog.Warn("could not read harness stage timings", "err", err)
+		}
+		return
+	}
+	s := m.current
+	s.Harness = append(s.Harness, t.Spans...)
+	if s.HarnessTotalsS == nil {
+		s.HarnessTotalsS = make(map[string]float64)
+	}
+	for _, span := range t.Spans {
+		s.HarnessTotalsS[span.Stage] += time.Duration(span.DurationNs).Seconds()
+	}
+}
+
+// write finishes the manifest with the outcome of the run, err, and writes
+// it to path.
+func (m *runManifest) write(path string, err error) {
+	m.DurationS = time.Since(m.StartedAt).Seconds()
+	m.ExitCode = exitCode(err)
+	if err != nil {
+		m.Error = err.Error()
+	}
+	if werr := writeJSON(path, m); werr != nil {
+		slog.Warn("could not write run manifest", "path", path, "err", werr)
+		return
+	}
+	slog.Info("wrote run manifest", "path", path, "duration_s", m.DurationS)
+}
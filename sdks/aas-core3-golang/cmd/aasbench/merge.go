@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/aggregate"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+)
+
+func runMerge(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "merge", "--results-dir <dir> --output results.json [flags]")
+	resultsDir := fs.String("results-dir", "results", "directory containing per-SDK result folders")
+	outputPath := fs.String("output", "dashboard/data/results.json", "output path for aggregated JSON")
+	knownSDKs := fs.String("known-sdks", "known-sdks.json", "path to known-sdks.json for name lookup")
+	previous := fs.String("previous-results", "", "previous results.json for regression detection")
+	byPlatform := fs.Bool("by-platform", false, "add platform_comparison, the operations each SDK ran on several platforms (os/arch)")
+	badgesDir := fs.String("badges-dir", "", "optional directory to write shields.io endpoint badges per SDK headline metric and operation")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	results, flagged := aggregate.Run(*resultsDir, *knownSDKs, *previous)
+	if *byPlatform {
+		results.PlatformComparison = aggregate.PlatformComparison(results.SDKBenchmarks)
+	}
+	details := mergeDetails{
+		Output:  *outputPath,
+		SDKs:    len(results.SDKBenchmarks),
+		Servers: len(results.ServerBenchmarks),
+	}
+	for _, sdk := range results.SDKBenchmarks {
+		deltas, _ := sdk["regressions"].([]compare.Delta)
+		for _, d := range deltas {
+			switch {
+			case d.Direction != compare.Regression:
+				details.Improvements++
+			case d.Namespace != "":
+				// Extension operations are reported but do not gate.
+				details.ExtensionRegressions++
+			default:
+				details.Regressions++
+			}
+		}
+	}
+	inv.details = details
+	if *previous != "" {
+		if flagged > 0 {
+			fmt.Printf("Detected %d regression(s)/improvement(s)\n", flagged)
+		} else {
+			fmt.Println("No significant regressions detected")
+		}
+	}
+
+	out, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(*outputPath), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(*outputPath, out, 0644); err != nil {
+		return err
+	}
+
+	if *badgesDir != "" {
+		n, err := aggregate.WriteBadges(results, *badgesDir)
+		if err != nil {
+			return err
+		}
+		details.Badges = n
+		inv.details = details
+		fmt.Printf("Wrote %d badge(s) to %s\n", n, *badgesDir)
+	}
+
+	fmt.Printf("Aggregated %d result(s) (%d SDK, %d server) -> %s\n",
+		len(results.SDKBenchmarks)+len(results.ServerBenchmarks),
+		len(results.SDKBenchmarks), len(results.ServerBenchmarks), *outputPath)
+	if details.Regressions > 0 {
+		return regressionsErr(fmt.Errorf("%d significant regression(s) against %s", details.Regressions, *previous))
+	}
+	return nil
+}
+
+// mergeDetails is the status.json detail block of merge.
+type mergeDetails struct {
+	Output       string `json:"output"`
+	SDKs         int    `json:"sdks"`
+	Servers      int    `json:"servers"`
+	Regressions  int    `json:"regressions"`
+	Improvements int    `json:"improvements"`
+	// ExtensionRegressions are regressions in namespaced extension operations.
+	ExtensionRegressions int `json:"extension_regressions"`
+	Badges               int `json:"badges,omitempty"`
+}
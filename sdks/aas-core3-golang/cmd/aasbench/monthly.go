@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/aggregate"
+)
+
+func runMonthly(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "monthly", "[flags] <results.json or directory>...")
+	month := fs.String("month", "", "month to summarize as YYYY-MM (default: the month of the newest results)")
+	knownSDKs := fs.String("known-sdks", "known-sdks.json", "known-sdks.json whose enabled entries count as coverage gaps when they have no results")
+	format := fs.String("format", "markdown", "output format: markdown or html")
+	outputPath := fs.String("output", "", "optional path to write the report (default stdout)")
+	jsonPath := fs.String("json", "", "optional path to write the summary as JSON")
+	top := fs.Int("top", aggregate.DefaultMonthlyTop, "improvements and regressions to list (negative lists all)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "markdown" && *format != "html" {
+		return fmt.Errorf("unknown --format %q (want markdown or html)", *format)
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no results given")
+	}
+
+	// Stored runs that cannot be placed in time are skipped, so a
+	// placeholder for a missing previous run does not stop the report.
+	var snapshots []aggregate.Snapshot
+	for _, path := range monthlyInputs(fs.Args()) {
+		snap, err := aggregate.LoadSnapshot(path)
+		if err != nil {
+			slog.Warn("skipping report", "err", err)
+			continue
+		}
+		snapshots = append(snapshots, snap)
+	}
+	if len(snapshots) == 0 {
+		return fmt.Errorf("no usable results.json among %d input(s)", fs.NArg())
+	}
+
+	from, err := monthStart(*month, snapshots)
+	if err != nil {
+		return err
+	}
+	m, err := aggregate.BuildMonthly(snapshots, from, from.AddDate(0, 1, 0), aggregate.ReadJSON(*knownSDKs), *top)
+	if err != nil {
+		return err
+	}
+	inv.details = monthlyDetails{
+		Month:         from.Format("2006-01"),
+		Runs:          m.Runs,
+		Improvements:  len(m.Improvements),
+		Regressions:   len(m.Regressions),
+		NewServerTags: len(m.NewServerTags),
+		CoverageGaps:  len(m.CoverageGaps),
+	}
+
+	var out io.Writer = os.Stdout
+	if *outputPath != "" {
+		f, err := os.Create(*outputPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	if *format == "html" {
+		err = monthlyHTML.Execute(out, m)
+	} else {
+		err = monthlyMarkdown.Execute(out, m)
+	}
+	if err != nil {
+		return err
+	}
+	if *outputPath != "" {
+		slog.Info("wrote monthly report", "month", from.Format("2006-01"), "path", *outputPath)
+	}
+
+	if *jsonPath != "" {
+		data, err := json.MarshalIndent(m, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*jsonPath, data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// monthlyInputs expands directories to the JSON files they contain.
+func monthlyInputs(args []string) []string {
+	var paths []string
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err == nil && info.IsDir() {
+			matches, _ := filepath.Glob(filepath.Join(arg, "*.json"))
+			paths = append(paths, matches...)
+			continue
+		}
+		paths = append(paths, arg)
+	}
+	return paths
+}
+
+// monthStart parses month, defaulting to the month of the newest snapshot.
+func monthStart(month string, snapshots []aggregate.Snapshot) (time.Time, error) {
+	if month != "" {
+		t, err := time.Parse("2006-01", month)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid --month %q (want YYYY-MM)", month)
+		}
+		return t, nil
+	}
+	newest := snapshots[0].GeneratedAt
+	for _, s := range snapshots[1:] {
+		if s.GeneratedAt.After(newest) {
+			newest = s.GeneratedAt
+		}
+	}
+	return time.Date(newest.Year(), newest.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+}
+
+// monthlyDetails is the status.json detail block of monthly.
+type monthlyDetails struct {
+	Month         string `json:"month"`
+	Runs          int    `json:"runs"`
+	Improvements  int    `json:"improvements"`
+	Regressions   int    `json:"regressions"`
+	NewServerTags int    `json:"new_server_tags"`
+	CoverageGaps  int    `json:"coverage_gaps"`
+}
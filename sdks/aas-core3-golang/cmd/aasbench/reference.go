@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/dataset"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// referenceCacheEnv overrides the directory fetched reference bundles are
+// cached in.
+const referenceCacheEnv = "AASBENCH_REFERENCE_CACHE"
+
+// runBallpark is diff --against reference: it checks the report at
+// currentPath against the reference report of its SDK from src.
+func runBallpark(inv *invocation, currentPath, src string, factor float64, outputPath string) error {
+	current, err := report.Load(currentPath)
+	if err != nil {
+		return loadErr(err)
+	}
+	refs, err := loadReferences(src)
+	if err != nil {
+		return err
+	}
+	ref, err := compare.FindReference(refs, current.SDKID, current.Metadata.SDKPackageVersion, current.Metadata.PlatformOf())
+	if err != nil {
+		return err
+	}
+	c := compare.Ballpark(ref, current, factor, compare.DefaultNoisyCVPct)
+	inv.details = c
+	if len(c.Rows) == 0 {
+		return fmt.Errorf("%s has no operation in common with the %s reference", currentPath, c.SDKID)
+	}
+
+	for _, note := range c.Notes {
+		slog.Warn("the reference differs from this run", "difference", note)
+	}
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "DATASET\tOPERATION\tREFERENCE\tTHIS RUN\tRATIO\tCV\tVERDICT")
+	for _, r := range c.Rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%.2fx\t%.1f%%\t%s\n", r.Dataset, r.Operation,
+			report.FormatValue(float64(r.ReferenceNs), "ns"), report.FormatValue(float64(r.CurrentNs), "ns"), r.Ratio, r.CVPct, r.Verdict)
+	}
+	tw.Flush()
+	fmt.Printf("\n%s %s against reference %s: %.2fx overall, %s\n", c.SDKID, c.CurrentVersion, c.ReferenceVersion, c.Ratio, c.Verdict)
+	for _, reason := range c.Noise {
+		fmt.Printf("  too noisy: %s\n", reason)
+	}
+
+	if outputPath != "" {
+		out, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(outputPath, out, 0644); err != nil {
+			return err
+		}
+		slog.Info("wrote ballpark check", "path", outputPath)
+	}
+	switch c.Verdict {
+	case compare.BallparkNoisy:
+		return regressionsErr(fmt.Errorf("this machine is too noisy to benchmark on: %s", strings.Join(c.Noise, "; ")))
+	case compare.BallparkSlower, compare.BallparkFaster:
+		return regressionsErr(fmt.Errorf("this machine is %.2fx the reference, outside the %gx ballpark", c.Ratio, factor))
+	}
+	return nil
+}
+
+// loadReferences reads the reference reports in src, a directory or the
+// URL of a bundle, which is fetched into the cache first.
+func loadReferences(src string) ([]*report.Report, error) {
+	dir := src
+	if strings.HasPrefix(src, "http://") || strings.HasPrefix(src, "https://") {
+		f := &dataset.Fetcher{CacheDir: os.Getenv(referenceCacheEnv), HTTP: &http.Client{}}
+		if f.CacheDir == "" {
+			datasets, err := dataset.DefaultCacheDir()
+			if err != nil {
+				return nil, fmt.Errorf("no cache directory: %w (set $%s)", err, referenceCacheEnv)
+			}
+			f.CacheDir = filepath.Join(filepath.Dir(datasets), "reference")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		defer cancel()
+		slog.Info("fetching reference reports", "url", src)
+		var err error
+		if dir, _, err = f.Fetch(ctx, src, ""); err != nil {
+			return nil, err
+		}
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	var refs []*report.Report
+	for _, path := range paths {
+		if filepath.Base(path) == dataset.BundleManifest {
+			continue
+		}
+		r, err := report.Load(path)
+		if err != nil {
+			return nil, loadErr(err)
+		}
+		refs = append(refs, r)
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("no reference reports in %s", src)
+	}
+	return refs, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func runRender(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "render", "--output overlay.html [flags] <report.json>...")
+	outputPath := fs.String("output", "overlay.html", "path of the HTML page to write")
+	csvPath := fs.String("csv", "", "optional path to write the merged table as CSV")
+	labels := fs.String("labels", "", "comma-separated series labels, one per report (default: derived from the differing SDK, Go version and architecture)")
+	title := fs.String("title", "AAS benchmark overlay", "page title")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no report given")
+	}
+
+	var reports []*report.Report
+	for _, path := range fs.Args() {
+		rep, err := report.Load(path)
+		if err != nil {
+			return loadErr(err)
+		}
+		reports = append(reports, rep)
+	}
+	var labelList []string
+	if *labels != "" {
+		for _, l := range strings.Split(*labels, ",") {
+			labelList = append(labelList, strings.TrimSpace(l))
+		}
+	}
+	overlay, err := compare.BuildOverlay(reports, fs.Args(), labelList)
+	if err != nil {
+		return err
+	}
+
+	for i, rep := range reports {
+		overlay.Series[i].Flamegraphs = flamegraphLinks(rep.Flamegraphs, fs.Arg(i), *outputPath)
+	}
+	if err := writeOverlayHTML(*outputPath, *title, overlay); err != nil {
+		return err
+	}
+	slog.Info("wrote overlay", "series", len(overlay.Series), "rows", len(overlay.Rows), "path", *outputPath)
+	if *csvPath != "" {
+		f, err := os.Create(*csvPath)
+		if err != nil {
+			return err
+		}
+		if err := overlay.WriteCSV(f); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		slog.Info("wrote merged table", "path", *csvPath)
+	}
+	inv.details = renderDetails{Output: *outputPath, CSV: *csvPath, Series: len(overlay.Series), Rows: len(overlay.Rows)}
+	return nil
+}
+
+// flamegraphLinks resolves the flamegraphs of the report at reportPath,
+// relative to it, to links from the page at pagePath. A flamegraph whose
+// file is missing is left out.
+func flamegraphLinks(graphs report.Flamegraphs, reportPath, pagePath string) map[string]string {
+	pageDir, err := filepath.Abs(filepath.Dir(pagePath))
+	if err != nil {
+		return nil
+	}
+	links := make(map[string]string)
+	for operation, rel := range graphs {
+		svg, err := filepath.Abs(filepath.Join(filepath.Dir(reportPath), filepath.FromSlash(rel)))
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(svg); err != nil {
+			slog.Warn("flamegraph not found", "operation", operation, "path", svg)
+			continue
+		}
+		if link, err := filepath.Rel(pageDir, svg); err == nil {
+			links[operation] = filepath.ToSlash(link)
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return links
+}
+
+func writeOverlayHTML(path, title string, overlay *compare.Overlay) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	flamegraphs := false
+	for _, s := range overlay.Series {
+		flamegraphs = flamegraphs || len(s.Flamegraphs) > 0
+	}
+	data := struct {
+		Title       string
+		Overlay     *compare.Overlay
+		Flamegraphs bool
+	}{title, overlay, flamegraphs}
+	if err := overlayHTML.Execute(f, data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// renderDetails is the status.json detail block of render.
+type renderDetails struct {
+	Output string `json:"output"`
+	CSV    string `json:"csv,omitempty"`
+	Series int    `json:"series"`
+	Rows   int    `json:"rows"`
+}
@@ -0,0 +1,667 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/containment"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/logging"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/plan"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/runstore"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/sysinfo"
+)
+
+func runBenchmarks(inv *invocation, args []string) (err error) {
+	fs := newFlagSet(inv, "run", "--datasets <dir> (--output <dir> | --results <dir>) [flags]")
+	datasetsDir := fs.String("datasets", "", "directory containing dataset files (required)")
+	outputDir := fs.String("output", "", "directory for bench_raw.json, side channels and report.json (required unless --results)")
+	resultsDir := fs.String("results", "", "instead of --output, write the run to a new <dir>/runs/<timestamp>-<run id>/ and point <dir>/latest at it once it succeeds")
+	pkgDir := fs.String("dir", ".", "directory of the benchmark harness module")
+	pkg := fs.String("pkg", ".", "package pattern passed to go test")
+	bench := fs.String("bench", ".", "benchmark regex passed to go test -bench")
+	onlyOperations := fs.String("only-operations", "", "comma-separated operation IDs or tracks (core, validation, xml, aasx) to run, with the operations they need (harness -operations)")
+	onlyDatasets := fs.String("only-datasets", "", "comma-separated dataset names to run (harness -datasets)")
+	count := fs.Int("count", 5, "number of runs per benchmark (go test -count)")
+	targetError := fs.Float64("target-error", 0, "keep collecting -count more runs of every operation whose 95% confidence interval half-width exceeds this percentage of its mean, until all meet it or --adaptive-budget expires (0 disables)")
+	adaptiveBudget := fs.Duration("adaptive-budget", 10*time.Minute, "time --target-error may spend on extra runs, per suite")
+	timeout := fs.String("timeout", "30m", "go test timeout")
+	heapProfile := fs.Bool("heap-profile", false, "capture <op>.heap.pprof and allocation hotspots (perturbs memory figures)")
+	cpuProfile := fs.Bool("cpu-profile", false, "capture a CPU profile of every sub-benchmark and render one flamegraph SVG per operation (slightly perturbs timings)")
+	execTrace := fs.Bool("trace", false, "capture a Go execution trace of one iteration of every operation as <op>.trace and summarize its goroutines, GC assist and blocked time in exec_traces")
+	perfCounters := fs.Bool("perf-counters", false, "count instructions, cache misses and branch misses per operation (Linux perf_event_open)")
+	energy := fs.Bool("energy", false, "meter the energy of every operation group through the RAPL counters (Linux powercap)")
+	memoryStats := fs.Bool("memory-stats", true, "sample memory_stats.json and gc_pauses.json; disable for pure-latency runs")
+	buildMetrics := fs.Bool("build-metrics", true, "compile a minimal program using the SDK and record its compile time and binary size in build_metrics")
+	cpus := fs.String("cpus", "", "pin the benchmark process to these CPUs, e.g. 0,2-3 (Linux; GOMAXPROCS follows unless --gomaxprocs is set)")
+	gomaxprocs := fs.Int("gomaxprocs", 0, "run the benchmarks with this GOMAXPROCS (0 keeps the runtime default)")
+	pooled := fs.Bool("pooled", false, "also benchmark deserialize_pooled, deserialization with pooled decoder state")
+	planPath := fs.String("plan", "", "plan file from 'aasbench init'; explicit flags override it")
+	benchtimeSweep := fs.String("benchtime-sweep", "", "comma-separated benchtimes (e.g. 100ms,1s,5s) to re-run at and record ns/op stability")
+	stabilityThreshold := fs.Float64("stability-threshold", report.DefaultStabilityThresholdPct, "sweep spread in percent above which an operation is unstable")
+	noiseThreshold := fs.Float64("noise-threshold", report.DefaultNoiseThresholdPct, noiseThresholdUsage)
+	var outlierPolicy string
+	outlierFlag(fs, &outlierPolicy)
+	aliases := fs.String("aliases", "", aliasesUsage)
+	strict := fs.Bool("strict", false, strictUsage)
+	runs := fs.Int("runs", 1, "run the whole suite this many times, each in a fresh process, and merge the reports by median")
+	cooldown := fs.Duration("cooldown", 30*time.Second, "pause between --runs to let the host settle")
+	sdkVersion := fs.String("sdk-version", "", "benchmark this "+sdkModule+" version instead of the one in go.mod (e.g. v1.0.6)")
+	memoryCap := fs.String("memory-cap", "", "also run each benchmark function alone under this memory cap (e.g. 512MiB) and record whether it completes, thrashes or runs out of memory")
+	wasmRuntime := fs.String("wasm", "", "compile the suite to WebAssembly and run it under this runtime: node (GOOS=js) or wasmtime (GOOS=wasip1), reporting as "+wasmSDKID)
+	fuzzCorpus := fs.String("fuzz-corpus", "", "after the benchmarks, decode every input of this fuzz corpus (datasets/generate.py --fuzz-corpus) and record panics, hangs and runaway memory in the robustness section")
+	fuzzDeadline := fs.Duration("fuzz-deadline", 5*time.Second, "time one --fuzz-corpus input may take before it counts as hung")
+	soak := fs.Duration("soak", 0, "after the benchmarks, loop one dataset through deserialize, update and serialize this long (e.g. 10m), sampling the live heap, and record its memory_growth_bytes_per_min in the soak section (extends --timeout by as much)")
+	soakInterval := fs.Duration("soak-interval", 10*time.Second, "time between two heap samples of --soak")
+	soakDataset := fs.String("soak-dataset", "mixed", "dataset --soak loops")
+	isolate := fs.Bool("isolate", false, "run every benchmark function in a go test process of its own, so each operation group starts on a fresh heap and GC state")
+	cgroupParent := fs.String("cgroup-parent", "", "cgroup v2 directory to create the --memory-cap cgroups under (default: the current cgroup)")
+	var limits reportLimits
+	limits.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *planPath != "" {
+		if err := applyPlan(fs, *planPath); err != nil {
+			return err
+		}
+	}
+	if err := requireFlags(fs, "datasets"); err != nil {
+		return err
+	}
+	switch {
+	case *outputDir == "" && *resultsDir == "":
+		return fmt.Errorf("missing required flag --output or --results")
+	case *outputDir != "" && *resultsDir != "":
+		return fmt.Errorf("--output and --results are mutually exclusive")
+	}
+	if *runs < 1 {
+		return fmt.Errorf("--runs must be at least 1")
+	}
+	if *targetError < 0 {
+		return fmt.Errorf("--target-error must not be negative")
+	}
+	if *soak < 0 || *soakInterval <= 0 {
+		return fmt.Errorf("--soak must not be negative and --soak-interval must be positive")
+	}
+	if *soak > 0 && *isolate {
+		// Every isolated process would soak on its own.
+		return fmt.Errorf("--soak cannot be combined with --isolate")
+	}
+	if *wasmRuntime != "" {
+		// These rely on Linux system calls or cgroups around a native process,
+		// or for fuzz-corpus, on preempting a hung decoder.
+		native := []struct {
+			name string
+			set  bool
+		}{{"cpus", *cpus != ""}, {"perf-counters", *perfCounters}, {"energy", *energy}, {"memory-cap", *memoryCap != ""}, {"fuzz-corpus", *fuzzCorpus != ""}, {"cpu-profile", *cpuProfile}, {"trace", *execTrace}}
+		for _, f := range native {
+			if f.set {
+				return fmt.Errorf("--%s cannot be combined with --wasm", f.name)
+			}
+		}
+	}
+	var runner *containment.Runner
+	if *memoryCap != "" {
+		capBytes, err := containment.ParseSize(*memoryCap)
+		if err != nil {
+			return fmt.Errorf("--memory-cap: %w", err)
+		}
+		var reason string
+		runner, reason = containment.New(capBytes, *cgroupParent)
+		if reason != "" {
+			slog.Warn("no memory cgroup available; capping with GOMEMLIMIT instead", "reason", reason)
+		}
+	}
+
+	// The harness runs with its own working directory, so hand it absolute paths.
+	absDatasets, err := filepath.Abs(*datasetsDir)
+	if err != nil {
+		return err
+	}
+	if *resultsDir != "" {
+		root, err := filepath.Abs(*resultsDir)
+		if err != nil {
+			return err
+		}
+		if *outputDir, err = runstore.Create(root, logging.RunID(), time.Now()); err != nil {
+			return fmt.Errorf("create run dir: %w", err)
+		}
+		slog.Info("writing run", "dir", *outputDir)
+		defer func() {
+			if err != nil {
+				return
+			}
+			if lerr := runstore.SetLatest(root, *outputDir); lerr != nil {
+				slog.Warn("could not point latest at the run", "err", lerr)
+			}
+		}()
+	}
+	absOutput, err := filepath.Abs(*outputDir)
+	if err != nil {
+		return err
+	}
+	inv.defaultStatus(absOutput)
+	if err := os.MkdirAll(absOutput, 0755); err != nil {
+		return fmt.Errorf("create output dir: %w", err)
+	}
+	manifest := newRunManifest()
+	defer func() { manifest.write(filepath.Join(absOutput, runManifestFile), err) }()
+
+	h := harness{dir: *pkgDir, pkg: *pkg, bench: *bench, count: *count, timeout: *timeout, manifest: manifest}
+	if *soak > 0 {
+		// The soak runs inside the go test process, after the benchmarks.
+		d, err := time.ParseDuration(*timeout)
+		if err != nil {
+			return fmt.Errorf("--timeout: %w", err)
+		}
+		h.timeout = (d + *soak).String()
+	}
+	if *onlyOperations != "" {
+		// Fail before building anything on a term the harness would reject.
+		if _, err := report.DefaultOperations().Select(splitList(*onlyOperations)); err != nil {
+			return fmt.Errorf("--only-operations: %w", err)
+		}
+		h.args = append(h.args, "-operations="+*onlyOperations)
+	}
+	if *onlyDatasets != "" {
+		h.args = append(h.args, "-datasets="+*onlyDatasets)
+	}
+	if *sdkVersion != "" {
+		var cleanup func()
+		err := manifest.stage("pin_sdk", 0, func() (err error) {
+			h.modfile, cleanup, err = pinSDK(*pkgDir, *sdkVersion)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+	}
+	var env []string
+	if *wasmRuntime != "" {
+		if h.wasm, err = newWasmTarget(*wasmRuntime); err != nil {
+			return err
+		}
+		env = append(env, "SIDE_CHANNEL_SHIM=1")
+	}
+	if *heapProfile {
+		env = append(env, "HEAP_PROFILE=1")
+	}
+	if *cpuProfile {
+		env = append(env, "CPU_PROFILE=1")
+	}
+	if *execTrace {
+		env = append(env, "EXEC_TRACE=1")
+	}
+	if *perfCounters {
+		env = append(env, "PERF_COUNTERS=1")
+	}
+	if *energy {
+		env = append(env, "ENERGY=1")
+	}
+	if *pooled {
+		env = append(env, "POOLED_BENCHMARKS=1")
+	}
+	if *cpus != "" {
+		env = append(env, "CPU_AFFINITY="+*cpus)
+	}
+	if *gomaxprocs > 0 {
+		env = append(env, "BENCH_GOMAXPROCS="+strconv.Itoa(*gomaxprocs))
+	}
+	if !*memoryStats {
+		env = append(env, "MEMORY_STATS=0")
+	}
+	if *fuzzCorpus != "" {
+		absCorpus, err := filepath.Abs(*fuzzCorpus)
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(filepath.Join(absCorpus, "corpus.json")); err != nil {
+			return fmt.Errorf("--fuzz-corpus: %w", err)
+		}
+		env = append(env, "FUZZ_CORPUS="+absCorpus, "FUZZ_DEADLINE="+fuzzDeadline.String())
+	}
+	if *soak > 0 {
+		env = append(env, "SOAK_DURATION="+soak.String(), "SOAK_INTERVAL="+soakInterval.String(), "SOAK_DATASET="+*soakDataset)
+	}
+
+	aliasTable := report.DefaultAliases()
+	if *aliases != "" {
+		if aliasTable, err = report.LoadAliases(*aliases); err != nil {
+			return loadErr(err)
+		}
+	}
+	inputs := func(dir string) reportInputs {
+		bundle := report.BundleInDir(dir)
+		bundle.Sweeps = nil // ignore sweep files left by earlier runs
+		bundle.Containment = ""
+		bundle.Adaptive = ""
+		bundle.BuildMetrics = ""
+		bundle.Flamegraphs = ""
+		bundle.ExecTraces = ""
+		bundle.Mode = parseMode(*strict)
+		bundle.Aliases = *aliases
+		in := reportInputs{
+			bundle:   bundle,
+			output:   filepath.Join(dir, report.ReportFile),
+			datasets: absDatasets,
+			// bench.txt lets a run be compared with local go test output
+			// in benchstat.
+			benchText: filepath.Join(dir, report.BenchTextFile),
+
+			sdkVersion:         *sdkVersion,
+			stabilityThreshold: *stabilityThreshold,
+			noiseThreshold:     *noiseThreshold,
+			outlierPolicy:      outlierPolicy,
+			limits:             limits,
+		}
+		if h.wasm != nil {
+			in.sdkID = wasmSDKID
+			in.meta = metaOverrides{{"benchmark_harness", h.wasm.harnessName()}}
+		}
+		return in
+	}
+
+	// runSuite runs the suite once into dir and emits its report.json. The
+	// containment pass, when asked for, runs with the first suite only. run
+	// numbers the suite among --runs, 0 when it runs once.
+	runSuite := func(dir string, run int) error {
+		first := run <= 1
+		// Probe the host before the benchmarks load it.
+		err := manifest.stage("environment_probe", run, func() error {
+			return writeJSON(filepath.Join(dir, report.EnvironmentFile), sysinfo.Collect())
+		})
+		if err != nil {
+			return err
+		}
+
+		// OUTPUT_DIR lets TestMain write the memory_stats.json and
+		// events.json side channels.
+		rawPath := filepath.Join(dir, report.BenchRawFile)
+		err = manifest.stage("harness", run, func() error {
+			var err error
+			if *isolate {
+				err = runIsolated(h, append([]string{"DATASETS_DIR=" + absDatasets}, env...), dir)
+			} else {
+				err = h.run(rawPath, append([]string{"DATASETS_DIR=" + absDatasets, "OUTPUT_DIR=" + dir}, env...))
+			}
+			if h.wasm != nil && !*isolate {
+				names, xerr := report.ExtractSideChannels(rawPath, dir)
+				if xerr != nil {
+					slog.Warn("could not extract side channels", "err", xerr)
+				}
+				slog.Info("extracted side channels", "files", names)
+			}
+			manifest.harnessStages(dir)
+			return err
+		})
+		if err != nil {
+			// A crashed run still leaves the results go test printed and
+			// the progress the harness flushed; report them before failing.
+			perr := manifest.stage("emit_partial_report", run, func() error {
+				return emitPartial(inv, h, aliasTable, inputs(dir))
+			})
+			if perr != nil {
+				slog.Warn("could not assemble a partial report", "err", perr)
+			}
+			return err
+		}
+
+		// Sweep and adaptive runs only add samples; an empty OUTPUT_DIR
+		// keeps them from overwriting the side channels of the primary run.
+		in := inputs(dir)
+		if *cpuProfile {
+			var graphs report.Flamegraphs
+			err := manifest.stage("flamegraphs", run, func() (err error) {
+				graphs, err = report.RenderFlamegraphs(dir)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if graphs == nil {
+				slog.Warn("no CPU profiles to render", "dir", dir)
+			} else {
+				in.bundle.Flamegraphs = filepath.Join(dir, report.FlamegraphsFile)
+			}
+		}
+		if *execTrace {
+			var traces report.ExecTraces
+			err := manifest.stage("exec_traces", run, func() (err error) {
+				traces, err = summarizeExecTraces(h, dir)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			if traces == nil {
+				slog.Warn("no execution traces to summarize", "dir", dir)
+			} else {
+				in.bundle.ExecTraces = filepath.Join(dir, report.ExecTracesFile)
+			}
+		}
+		if *targetError > 0 {
+			sampler := adaptiveSampler{
+				targetPct:     *targetError,
+				budget:        *adaptiveBudget,
+				outlierPolicy: outlierPolicy,
+				aliases:       aliasTable,
+				manifest:      manifest,
+			}
+			if outlierPolicy == "" {
+				sampler.outlierPolicy = report.DefaultOutlierPolicy
+			}
+			err := manifest.stage("adaptive", run, func() error {
+				return sampler.run(h, append([]string{"DATASETS_DIR=" + absDatasets}, env...), dir, run)
+			})
+			if err != nil {
+				return err
+			}
+			in.bundle.Adaptive = filepath.Join(dir, report.AdaptiveFile)
+		}
+		for _, bt := range splitList(*benchtimeSweep) {
+			path := filepath.Join(dir, report.SweepFileName(bt))
+			err := manifest.stage("sweep_"+bt, run, func() error {
+				return h.run(path, []string{"DATASETS_DIR=" + absDatasets, "OUTPUT_DIR="}, "-benchtime="+bt)
+			})
+			if err != nil {
+				return err
+			}
+			in.bundle.Sweeps = append(in.bundle.Sweeps, report.SweepFile{Benchtime: bt, Path: path})
+		}
+
+		if *buildMetrics && first {
+			err := manifest.stage("build_metrics", run, func() error { return measureBuild(h, dir) })
+			if err != nil {
+				return err
+			}
+			in.bundle.BuildMetrics = filepath.Join(dir, report.BuildMetricsFile)
+		}
+
+		if runner != nil && first {
+			err := manifest.stage("containment", run, func() error {
+				return runContainment(h, runner, aliasTable, append([]string{"DATASETS_DIR=" + absDatasets}, env...), dir)
+			})
+			if err != nil {
+				return err
+			}
+			in.bundle.Containment = filepath.Join(dir, report.ContainmentFile)
+		}
+
+		return manifest.stage("emit_report", run, func() error { return emitReport(inv, in) })
+	}
+	if *runs == 1 {
+		return runSuite(absOutput, 0)
+	}
+
+	// Every run is a separate go test process with its own bundle in
+	// run-<i>/; report.json merges them.
+	var reps []*report.Report
+	for i := 1; i <= *runs; i++ {
+		if i > 1 && *cooldown > 0 {
+			slog.Info("cooling down", "duration", *cooldown, "run", i, "runs", *runs)
+			manifest.stage("cooldown", i, func() error {
+				time.Sleep(*cooldown)
+				return nil
+			})
+		}
+		dir := filepath.Join(absOutput, fmt.Sprintf("run-%d", i))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("create run dir: %w", err)
+		}
+		if err := runSuite(dir, i); err != nil {
+			return fmt.Errorf("run %d of %d: %w", i, *runs, err)
+		}
+		// The untrimmed report, if one was kept, holds every section.
+		rep, err := loadUntrimmed(filepath.Join(dir, report.ReportFile))
+		if err != nil {
+			return err
+		}
+		reps = append(reps, rep)
+		// Concatenated, the runs are more samples of each benchmark.
+		if err := appendFile(filepath.Join(absOutput, report.BenchTextFile), filepath.Join(dir, report.BenchTextFile), i == 1); err != nil {
+			return err
+		}
+	}
+	return manifest.stage("merge_runs", 0, func() error {
+		merged, err := report.MergeRuns(reps)
+		if err != nil {
+			return err
+		}
+		// The first run's flamegraphs and traces are linked from the
+		// merged report.
+		merged.Flamegraphs = merged.Flamegraphs.Under("run-1")
+		merged.ExecTraces = merged.ExecTraces.Under("run-1")
+		return writeReport(inv, filepath.Join(absOutput, report.ReportFile), merged, limits)
+	})
+}
+
+// emitPartial writes the report of a harness run that crashed, marking
+// every benchmark function h selects that has no result as incomplete.
+func emitPartial(inv *invocation, h harness, aliases report.AliasTable, in reportInputs) error {
+	names, _, err := h.list()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		in.expected = append(in.expected, aliases.Resolve(strings.TrimPrefix(name, "Benchmark")))
+	}
+	return emitReport(inv, in)
+}
+
+// appendFile appends the contents of src to dst, truncating dst first when
+// fresh is set.
+func appendFile(dst, src string, fresh bool) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if fresh {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(dst, flags, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// loadUntrimmed reads the report at path, or the untrimmed report it links
+// to when it was cut to the limits.
+func loadUntrimmed(path string) (*report.Report, error) {
+	rep, err := report.Load(path)
+	if err != nil || rep.FullReport == "" {
+		return rep, err
+	}
+	return report.Load(filepath.Join(filepath.Dir(path), rep.FullReport))
+}
+
+// sdkModule is the SDK the harness benchmarks.
+const sdkModule = "github.com/aas-core-works/aas-core3.0-golang"
+
+// pinSDK writes a copy of the harness go.mod/go.sum requiring version of
+// the SDK and returns its path for go test -modfile, leaving the checked-in
+// module untouched.
+func pinSDK(dir, version string) (modfile string, cleanup func(), err error) {
+	tmp, err := os.MkdirTemp("", "aasbench-sdk-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() { os.RemoveAll(tmp) }
+	for _, name := range []string{"go.mod", "go.sum"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil && !(name == "go.sum" && os.IsNotExist(err)) {
+			cleanup()
+			return "", nil, err
+		}
+		if err := os.WriteFile(filepath.Join(tmp, name), data, 0644); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+	modfile = filepath.Join(tmp, "go.mod")
+	cmd := exec.Command("go", "get", "-modfile="+modfile, sdkModule+"@"+version)
+	cmd.Dir = dir
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+	slog.Info("pinning SDK", "module", sdkModule, "version", version)
+	if err := cmd.Run(); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("go get %s@%s: %w", sdkModule, version, err)
+	}
+	return modfile, cleanup, nil
+}
+
+// harness runs the benchmark package with go test -json.
+type harness struct {
+	dir, pkg, bench string
+	count           int
+	timeout         string
+	// modfile replaces the module's go.mod when set (see pinSDK).
+	modfile string
+	// wasm runs the suite compiled to WebAssembly when set.
+	wasm *wasmTarget
+	// args are harness flags, passed to the test binary.
+	args []string
+	// manifest records every go test process run when set.
+	manifest *runManifest
+}
+
+// list returns the benchmark functions the suite selects and the rest of
+// the -bench pattern, as selectBenchmarks does.
+func (h harness) list() (groups []string, rest string, err error) {
+	args := []string{"test", "-list", "^Benchmark"}
+	if h.modfile != "" {
+		args = append(args, "-modfile="+h.modfile)
+	}
+	if h.wasm != nil {
+		args = append(args, "-exec="+h.wasm.exec)
+	}
+	cmd := exec.Command("go", append(args, h.pkg)...)
+	cmd.Dir = h.dir
+	if h.wasm != nil {
+		cmd.Env = append(os.Environ(), h.wasm.env(nil)...)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, "", fmt.Errorf("list benchmarks: %w", err)
+	}
+	return selectBenchmarks(strings.Fields(string(out)), h.bench)
+}
+
+// run writes go test -json output to outPath. env is added to the
+// environment and extra to the go test flags.
+func (h harness) run(outPath string, env []string, extra ...string) error {
+	out, err := os.Create(outPath)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+
+	// -benchmem for allocation stats.
+	args := []string{"test",
+		"-run=^$",
+		"-bench=" + h.bench,
+		"-benchmem",
+		fmt.Sprintf("-count=%d", h.count),
+		"-json",
+		"-timeout=" + h.timeout,
+	}
+	if h.modfile != "" {
+		args = append(args, "-modfile="+h.modfile)
+	}
+	if h.wasm != nil {
+		args = append(args, "-exec="+h.wasm.exec)
+		env = append(env, h.wasm.env(env)...)
+	}
+	args = append(append(append(args, extra...), h.pkg), h.args...)
+	cmd := exec.Command("go", args...)
+	cmd.Dir = h.dir
+	cmd.Stdout = out
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), env...)
+	slog.Info("running harness", "args", cmd.Args, "dir", h.dir)
+	start := time.Now()
+	err = cmd.Run()
+	h.manifest.process(cmd, env, time.Since(start), err)
+	if err != nil {
+		return fmt.Errorf("go test: %w (raw output kept in %s)", err, outPath)
+	}
+	return nil
+}
+
+func writeJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// planSDKID is the plan entry that selects this harness.
+const planSDKID = report.DefaultSDKID
+
+// applyPlan fills every flag not given on the command line from the plan.
+// The plan's output_dir is a results root; this harness writes to its
+// aas-core3-golang sub-directory, matching the results/<sdk-id> layout,
+// unless --results asks for a run directory instead.
+func applyPlan(fs *flag.FlagSet, path string) error {
+	p, err := plan.Load(path)
+	if err != nil {
+		return loadErr(err)
+	}
+	if problems := p.Validate(); len(problems) > 0 {
+		return schemaErr(fmt.Errorf("invalid plan %s: %s", path, strings.Join(problems, "; ")))
+	}
+	if !p.HasSDK(planSDKID) {
+		return fmt.Errorf("plan %s does not include %s", path, planSDKID)
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	values := map[string]string{
+		"datasets":     p.DatasetsDir,
+		"output":       filepath.Join(p.OutputDir, planSDKID),
+		"count":        strconv.Itoa(p.Count),
+		"heap-profile": strconv.FormatBool(p.HeapProfile),
+		// Sub-benchmarks are named after datasets.
+		"bench": "./^(" + strings.Join(p.Datasets, "|") + ")$",
+	}
+	if set["results"] {
+		delete(values, "output")
+	}
+	for name, value := range values {
+		if set[name] {
+			continue
+		}
+		if err := fs.Set(name, value); err != nil {
+			return fmt.Errorf("plan %s: %s: %w", path, name, err)
+		}
+	}
+	return nil
+}
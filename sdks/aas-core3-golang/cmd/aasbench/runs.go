@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/runstore"
+)
+
+func runRuns(inv *invocation, args []string) error {
+	if len(args) == 0 {
+		runsUsage()
+		return fmt.Errorf("no runs subcommand given")
+	}
+	switch args[0] {
+	case "list":
+		return runRunsList(inv, args[1:])
+	case "prune":
+		return runRunsPrune(inv, args[1:])
+	case "-h", "--help", "help":
+		runsUsage()
+		return nil
+	}
+	runsUsage()
+	return fmt.Errorf("unknown runs subcommand %q", args[0])
+}
+
+func runsUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: aasbench runs <subcommand> [flags]\n\nSubcommands:\n")
+	fmt.Fprintf(os.Stderr, "  list       List the runs run --results kept under a results directory\n")
+	fmt.Fprintf(os.Stderr, "  prune      Remove the runs a retention policy does not keep\n")
+}
+
+func runRunsList(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "runs list", "--results <dir>")
+	resultsDir := fs.String("results", "", "results directory given to run --results (required)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "results"); err != nil {
+		return err
+	}
+
+	runs, err := runstore.List(*resultsDir)
+	if err != nil {
+		return err
+	}
+	if runs == nil {
+		runs = []runstore.Run{}
+	}
+	inv.details = map[string]int{"runs": len(runs)}
+	out, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+// pruneDetails is the status.json detail block of runs prune.
+type pruneDetails struct {
+	Removed []string `json:"removed"`
+	Kept    int      `json:"kept"`
+	DryRun  bool     `json:"dry_run"`
+}
+
+func runRunsPrune(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "runs prune", "--results <dir> (--keep N | --older-than D) [flags]")
+	resultsDir := fs.String("results", "", "results directory given to run --results (required)")
+	keep := fs.Int("keep", 0, "keep this many of the newest runs (0: no limit)")
+	olderThan := fs.Duration("older-than", 0, "remove runs that started longer ago than this, e.g. 720h (0: no limit)")
+	dryRun := fs.Bool("dry-run", false, "list the runs that would be removed without removing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "results"); err != nil {
+		return err
+	}
+	if *keep < 0 || *olderThan < 0 {
+		return fmt.Errorf("--keep and --older-than must not be negative")
+	}
+	if *keep == 0 && *olderThan == 0 {
+		return fmt.Errorf("give --keep, --older-than or both")
+	}
+
+	policy := runstore.Policy{Keep: *keep, MaxAge: *olderThan}
+	runs, err := runstore.List(*resultsDir)
+	if err != nil {
+		return err
+	}
+	expired := policy.Expired(runs, time.Now())
+	if !*dryRun {
+		expired, err = runstore.Prune(*resultsDir, policy, time.Now())
+	}
+	details := pruneDetails{Removed: []string{}, Kept: len(runs) - len(expired), DryRun: *dryRun}
+	inv.details = &details
+	verb := "REMOVED"
+	if *dryRun {
+		verb = "EXPIRED"
+	}
+	for _, r := range expired {
+		details.Removed = append(details.Removed, r.Name)
+		fmt.Printf("%-9s %s\n", verb, r.Name)
+	}
+	if err != nil {
+		return err
+	}
+	slog.Info("pruned runs", "removed", len(expired), "kept", details.Kept, "dry_run", *dryRun)
+	return nil
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func runSchema(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "schema", "[name]")
+	list := fs.Bool("list", false, "list the embedded schemas")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *list {
+		for _, name := range schemaNames() {
+			fmt.Println(name)
+		}
+		return nil
+	}
+
+	name := "report"
+	if fs.NArg() > 0 {
+		name = fs.Arg(0)
+	}
+	data, err := schema(name)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/aggregate"
+)
+
+func runSite(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "site", "--results results.json --output <dir>")
+	resultsPath := fs.String("results", "dashboard/data/results.json", "aggregated results.json to read")
+	outputDir := fs.String("output", "", "directory to write the static site to (required)")
+	title := fs.String("title", "AAS SDK comparison", "site title")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "output"); err != nil {
+		return err
+	}
+
+	results, err := aggregate.LoadResults(*resultsPath)
+	if err != nil {
+		return loadErr(err)
+	}
+	site := aggregate.BuildSite(results)
+	charts, err := writeSite(*outputDir, *title, site)
+	if err != nil {
+		return err
+	}
+	pages := len(site.Pages())
+	inv.details = siteDetails{Output: *outputDir, Pages: pages, Charts: charts}
+	fmt.Printf("Wrote %d page(s) and %d chart(s) to %s\n", pages+1, charts, *outputDir)
+	return nil
+}
+
+// writeSite writes the index, every page and its SVG charts under dir and
+// returns how many charts it wrote. Links are relative, so the site works
+// from any path of a GitHub Pages deployment.
+func writeSite(dir, title string, site *aggregate.Site) (int, error) {
+	type pageData struct {
+		Title string
+		Root  string
+		Site  *aggregate.Site
+		Page  *aggregate.SitePage
+	}
+	write := func(rel string, data pageData) error {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := siteHTML.Execute(f, data); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+
+	if err := write("index.html", pageData{Title: title, Site: site}); err != nil {
+		return 0, err
+	}
+	charts := 0
+	for _, page := range site.Pages() {
+		page := page
+		for _, chart := range page.Charts {
+			path := filepath.Join(dir, filepath.FromSlash(chart.File))
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				return charts, err
+			}
+			if err := os.WriteFile(path, []byte(chart.SVG()), 0644); err != nil {
+				return charts, err
+			}
+			charts++
+		}
+		// Pages are one directory below the root.
+		if err := write(page.Path, pageData{Title: title, Root: "../", Site: site, Page: &page}); err != nil {
+			return charts, err
+		}
+	}
+	return charts, nil
+}
+
+// siteDetails is the status.json detail block of site.
+type siteDetails struct {
+	Output string `json:"output"`
+	Pages  int    `json:"pages"`
+	Charts int    `json:"charts"`
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Exit codes shared by every aasbench command. Orchestration scripts
+// should branch on these (or on status.json) rather than on stderr text.
+const (
+	exitOK             = 0 // command succeeded
+	exitRegressions    = 1 // significant performance regressions detected
+	exitSchema         = 2 // input or output violates the report schema
+	exitInfrastructure = 3 // I/O, invocation, or benchmark execution failure
+)
+
+// outcomes names each exit code in status.json.
+var outcomes = map[int]string{
+	exitOK:             "ok",
+	exitRegressions:    "regressions",
+	exitSchema:         "schema_error",
+	exitInfrastructure: "infrastructure_failure",
+}
+
+// cmdError attaches an exit code to a command failure. Errors that are not
+// a cmdError map to exitInfrastructure.
+type cmdError struct {
+	code int
+	err  error
+}
+
+func (e *cmdError) Error() string { return e.err.Error() }
+func (e *cmdError) Unwrap() error { return e.err }
+
+func regressionsErr(err error) error { return &cmdError{exitRegressions, err} }
+func schemaErr(err error) error      { return &cmdError{exitSchema, err} }
+
+// loadErr classifies a failure to read a JSON input: malformed content is a
+// schema error, anything else (missing file, permissions) is infrastructure.
+func loadErr(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+		return schemaErr(err)
+	}
+	return err
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	var ce *cmdError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	return exitInfrastructure
+}
+
+// invocation is the per-run state shared between main and a command.
+type invocation struct {
+	// statusPath is where status.json is written; empty disables it.
+	statusPath string
+	// details is command-specific structured outcome data for status.json.
+	details interface{}
+	// flags is the flag set the command registered.
+	flags *flag.FlagSet
+	// completing silences usage output while completion inspects flags.
+	completing bool
+}
+
+// status is the schema of status.json.
+type status struct {
+	Tool       string      `json:"tool"`
+	Outcome    string      `json:"outcome"`
+	ExitCode   int         `json:"exit_code"`
+	Message    string      `json:"message,omitempty"`
+	StartedAt  string      `json:"started_at"`
+	FinishedAt string      `json:"finished_at"`
+	Details    interface{} `json:"details,omitempty"`
+}
+
+// defaultStatus sets the status path to dir/status.json unless --status was given.
+func (inv *invocation) defaultStatus(dir string) {
+	if inv.statusPath == "" {
+		inv.statusPath = filepath.Join(dir, "status.json")
+	}
+}
+
+func (inv *invocation) writeStatus(tool string, code int, err error, started time.Time) {
+	if inv.statusPath == "" {
+		return
+	}
+	st := status{
+		Tool:       tool,
+		Outcome:    outcomes[code],
+		ExitCode:   code,
+		StartedAt:  started.UTC().Format(time.RFC3339),
+		FinishedAt: time.Now().UTC().Format(time.RFC3339),
+		Details:    inv.details,
+	}
+	if err != nil {
+		st.Message = err.Error()
+	}
+	out, mErr := json.MarshalIndent(st, "", "  ")
+	if mErr == nil {
+		mErr = os.WriteFile(inv.statusPath, out, 0644)
+	}
+	if mErr != nil {
+		slog.Warn("could not write status", "path", inv.statusPath, "err", mErr)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/dataset"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func TestDatasetVerifyExitCodes(t *testing.T) {
+	dir := t.TempDir()
+	wide := filepath.Join(dir, "wide.json")
+	if err := os.WriteFile(wide, []byte(`{"submodels": []}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	fp, err := dataset.Fingerprint(wide)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reportPath := filepath.Join(dir, "report.json")
+	writeManifest := func(manifest ...report.DatasetFingerprint) {
+		t.Helper()
+		if err := report.Write(reportPath, &report.Report{Metadata: report.Metadata{DatasetsManifest: manifest}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	verify := func() int {
+		return exitCode(runDatasetVerify(&invocation{}, []string{"--datasets", dir, "--report", reportPath}))
+	}
+
+	writeManifest(fp)
+	if code := verify(); code != exitOK {
+		t.Errorf("unchanged dataset: exit %d, want %d", code, exitOK)
+	}
+
+	// A file the runner cannot find is an infrastructure failure.
+	missing := fp
+	missing.File = "deep.json"
+	writeManifest(fp, missing)
+	if code := verify(); code != exitInfrastructure {
+		t.Errorf("missing dataset: exit %d, want %d", code, exitInfrastructure)
+	}
+
+	// A file whose content changed breaks the report's contract.
+	if err := os.WriteFile(wide, []byte(`{"submodels": [{}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeManifest(fp, missing)
+	if code := verify(); code != exitSchema {
+		t.Errorf("changed dataset: exit %d, want %d", code, exitSchema)
+	}
+}
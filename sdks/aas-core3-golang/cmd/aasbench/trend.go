@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/aggregate"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/trend"
+)
+
+func runTrend(inv *invocation, args []string) error {
+	flags := newFlagSet(inv, "trend", "[flags] <report.json, results.json or directory>...")
+	minShift := flags.Float64("min-shift", compare.DefaultThresholdPct, "smallest shift to report, in percent")
+	penalty := flags.Float64("penalty", trend.DefaultPenaltyFactor, "changepoint penalty factor; higher finds fewer shifts")
+	minSegment := flags.Int("min-segment", trend.DefaultMinSegment, "fewest runs a performance level must hold")
+	sdks := flags.String("sdk", "", "comma-separated SDK IDs to analyze (default all)")
+	operations := flags.String("operation", "", "comma-separated operation IDs to analyze (default all)")
+	jsonPath := flags.String("json", "", "optional path to write the series and shifts as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("no reports given")
+	}
+
+	h := trend.NewHistory()
+	reports := 0
+	for _, in := range trendInputs(flags.Args()) {
+		// Directories also hold bench_raw.json and other side channels;
+		// only files named on the command line must load.
+		obj := aggregate.ReadJSON(in.path)
+		if obj == nil {
+			if in.explicit {
+				slog.Warn("skipping input: not readable JSON", "path", in.path)
+			}
+			continue
+		}
+		n, err := h.Add(obj)
+		if err != nil {
+			if in.explicit {
+				slog.Warn("skipping input", "path", in.path, "err", err)
+			}
+			continue
+		}
+		reports += n
+	}
+	if reports == 0 {
+		return fmt.Errorf("no dated report among %d input(s)", flags.NArg())
+	}
+
+	keepSDK, keepOp := nameFilter(*sdks), nameFilter(*operations)
+	opts := trend.Options{MinSegment: *minSegment, PenaltyFactor: *penalty, MinShiftPct: *minShift}
+	var series []*trend.Series
+	shifts := []trend.Shift{}
+	regressions := 0
+	for _, s := range h.Series() {
+		if !keepSDK(s.SDKID) || !keepOp(s.Operation) {
+			continue
+		}
+		series = append(series, s)
+		for _, shift := range trend.Analyze(s, opts) {
+			shifts = append(shifts, shift)
+			if shift.Direction == compare.Regression {
+				regressions++
+			}
+		}
+	}
+	inv.details = trendDetails{Reports: reports, Series: len(series), Shifts: len(shifts), Regressions: regressions}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SDK\tDATASET\tOPERATION\tBEFORE ns\tAFTER ns\tCHANGE %\tDIRECTION\tLAST BEFORE\tFIRST AFTER")
+	for _, s := range shifts {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%.0f\t%.0f\t%+.2f\t%s\t%s\t%s\n",
+			s.SDKID, s.Dataset, s.Operation, s.BeforeNs, s.AfterNs, s.ChangePct, s.Direction,
+			runLabel(s.LastBefore), runLabel(s.FirstAfter))
+	}
+	tw.Flush()
+	fmt.Printf("\n%d shift(s), %d regression(s), across %d series from %d report(s)\n",
+		len(shifts), regressions, len(series), reports)
+
+	if *jsonPath != "" {
+		data, err := json.MarshalIndent(struct {
+			Series []*trend.Series `json:"series"`
+			Shifts []trend.Shift   `json:"shifts"`
+		}{series, shifts}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(*jsonPath, data, 0644); err != nil {
+			return err
+		}
+		slog.Info("wrote trend analysis", "path", *jsonPath)
+	}
+	return nil
+}
+
+// trendInput is a file to load and whether it was named on the command line.
+type trendInput struct {
+	path     string
+	explicit bool
+}
+
+// trendInputs expands directories, recursively, to the JSON files they
+// contain, so both a flat archive of dated reports and one run directory
+// per night are accepted.
+func trendInputs(args []string) []trendInput {
+	var inputs []trendInput
+	for _, arg := range args {
+		if info, err := os.Stat(arg); err != nil || !info.IsDir() {
+			inputs = append(inputs, trendInput{arg, true})
+			continue
+		}
+		filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err == nil && !d.IsDir() && strings.HasSuffix(path, ".json") {
+				inputs = append(inputs, trendInput{path, false})
+			}
+			return nil
+		})
+	}
+	return inputs
+}
+
+// nameFilter returns a predicate keeping the names of a comma-separated
+// list, or every name when the list is empty.
+func nameFilter(list string) func(string) bool {
+	names := splitList(list)
+	if len(names) == 0 {
+		return func(string) bool { return true }
+	}
+	keep := make(map[string]bool, len(names))
+	for _, n := range names {
+		keep[n] = true
+	}
+	return func(name string) bool { return keep[name] }
+}
+
+// runLabel names a run by its date and, when known, its commit.
+func runLabel(r trend.Run) string {
+	label := r.Time.Format("2006-01-02")
+	if r.Commit != "" {
+		sha := r.Commit
+		if len(sha) > 12 {
+			sha = sha[:12]
+		}
+		label += " " + sha
+	}
+	return label
+}
+
+// trendDetails is the status.json detail block of trend.
+type trendDetails struct {
+	Reports     int `json:"reports"`
+	Series      int `json:"series"`
+	Shifts      int `json:"shifts"`
+	Regressions int `json:"regressions"`
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func runValidate(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "validate", "[flags] <report.json>...")
+	calibrationPath := fs.String("calibration", "", "report.json whose calibration section sets the noop floor (default: each report's own)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return fmt.Errorf("no report given")
+	}
+
+	var floor int64
+	if *calibrationPath != "" {
+		cal, err := report.Load(*calibrationPath)
+		if err != nil {
+			return loadErr(err)
+		}
+		if floor = report.CalibrationFloor(cal.Calibration); floor == 0 {
+			return fmt.Errorf("%s has no %s calibration", *calibrationPath, report.CalibrationNoopCall)
+		}
+	}
+
+	invalid := 0
+	problemsByReport := make(map[string][]string)
+	inv.details = problemsByReport
+	for _, path := range fs.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		problems := report.Validate(data)
+		schemaViolations, err := schemaProblems("report", data)
+		if err != nil {
+			return err
+		}
+		problems = append(problems, schemaViolations...)
+		problemsByReport[path] = append([]string{}, problems...)
+		if len(problems) > 0 {
+			invalid++
+			fmt.Fprintf(os.Stderr, "Invalid report: %s\n", path)
+			for _, p := range problems {
+				fmt.Fprintf(os.Stderr, "  - %s\n", p)
+			}
+			continue
+		}
+		fmt.Printf("Report valid: %s\n", path)
+		warnBelowCalibrationFloor(path, floor)
+	}
+	if invalid > 0 {
+		return schemaErr(fmt.Errorf("%d invalid report(s)", invalid))
+	}
+	return nil
+}
+
+// warnBelowCalibrationFloor warns about the operations of the report at
+// path that ran faster than a noop call. floor is the noop call's mean, or
+// 0 to take it from the report itself. Such timings are suspect, not
+// invalid, so they do not fail validation.
+func warnBelowCalibrationFloor(path string, floor int64) {
+	rep, err := report.Load(path)
+	if err != nil {
+		return
+	}
+	if floor == 0 {
+		floor = report.CalibrationFloor(rep.Calibration)
+	}
+	for _, w := range report.BelowCalibrationFloor(rep, floor) {
+		fmt.Fprintf(os.Stderr, "Warning: %s: %s\n", path, w)
+	}
+}
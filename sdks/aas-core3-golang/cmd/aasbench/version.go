@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// version is stamped by goreleaser via -ldflags "-X main.version=...".
+var version = ""
+
+// buildVersion falls back to the module version recorded by go install.
+func buildVersion() string {
+	if version != "" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		return info.Main.Version
+	}
+	return "(devel)"
+}
+
+func runVersion(inv *invocation, args []string) error {
+	fs := newFlagSet(inv, "version", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	fmt.Printf("aasbench %s (%s %s/%s)\n", buildVersion(), runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	return nil
+}
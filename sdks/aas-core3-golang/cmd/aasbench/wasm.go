@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// wasmSDKID is the sdk_id of reports from a WebAssembly run, kept apart
+// from the native harness on the dashboard.
+const wasmSDKID = report.DefaultSDKID + "-wasm"
+
+// wasmGOOS maps each supported runtime of run --wasm to the GOOS it runs.
+var wasmGOOS = map[string]string{
+	"node":     "js",
+	"wasmtime": "wasip1",
+}
+
+// wasmTarget runs the harness compiled to WebAssembly under a runtime,
+// through the go_<goos>_wasm_exec wrapper the Go distribution ships.
+type wasmTarget struct {
+	runtime string
+	goos    string
+	exec    string
+}
+
+func newWasmTarget(runtime string) (*wasmTarget, error) {
+	goos, ok := wasmGOOS[runtime]
+	if !ok {
+		return nil, fmt.Errorf("--wasm: unsupported runtime %q (want node or wasmtime)", runtime)
+	}
+	if _, err := exec.LookPath(runtime); err != nil {
+		return nil, fmt.Errorf("--wasm: %w", err)
+	}
+	out, err := exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		return nil, fmt.Errorf("--wasm: go env GOROOT: %w", err)
+	}
+	goroot := strings.TrimSpace(string(out))
+	name := "go_" + goos + "_wasm_exec"
+	// Go 1.24 moved the wrappers from misc/wasm to lib/wasm.
+	for _, dir := range []string{"lib/wasm", "misc/wasm"} {
+		path := filepath.Join(goroot, dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return &wasmTarget{runtime: runtime, goos: goos, exec: path}, nil
+		}
+	}
+	return nil, fmt.Errorf("--wasm: %s not found under %s", name, goroot)
+}
+
+// env returns the go test environment that builds for the target and
+// hands vars to the harness. Node passes its whole environment on;
+// wasmtime only PWD and PATH, so vars go through GOWASIRUNTIMEARGS, which
+// the wrapper splits on spaces: their values must not contain any.
+func (t *wasmTarget) env(vars []string) []string {
+	env := []string{"GOOS=" + t.goos, "GOARCH=wasm"}
+	if t.goos == "wasip1" {
+		args := make([]string, 0, len(vars))
+		for _, v := range vars {
+			args = append(args, "--env "+v)
+		}
+		env = append(env, "GOWASIRUNTIME="+t.runtime, "GOWASIRUNTIMEARGS="+strings.Join(args, " "))
+	}
+	return env
+}
+
+// harnessName is the benchmark_harness metadata of a run under the target.
+func (t *wasmTarget) harnessName() string {
+	return fmt.Sprintf("testing.B (go test -bench, GOOS=%s GOARCH=wasm under %s)", t.goos, t.runtime)
+}
@@ -0,0 +1,137 @@
+package compare
+
+import (
+	"sort"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// DefaultAllocationSites is how many grown and shrunk call sites
+// AllocationDiff keeps per operation.
+const DefaultAllocationSites = 10
+
+// SiteDelta is the change of one call site's sampled allocations per
+// benchmark iteration.
+type SiteDelta struct {
+	Function         string  `json:"function"`
+	BaselineBytes    float64 `json:"baseline_bytes_per_op"`
+	CurrentBytes     float64 `json:"current_bytes_per_op"`
+	DeltaBytes       float64 `json:"delta_bytes_per_op"`
+	BaselineObjects  float64 `json:"baseline_objects_per_op"`
+	CurrentObjects   float64 `json:"current_objects_per_op"`
+	DeltaObjects     float64 `json:"delta_objects_per_op"`
+	NewInCurrent     bool    `json:"new_in_current,omitempty"`
+	GoneFromBaseline bool    `json:"gone_from_baseline,omitempty"`
+}
+
+// OperationAllocations diffs one operation group's heap profile between two
+// runs, like `go tool pprof -diff_base` but normalized per iteration so runs
+// with different b.N stay comparable.
+type OperationAllocations struct {
+	Operation     string      `json:"operation"`
+	BaselineBytes float64     `json:"baseline_bytes_per_op"`
+	CurrentBytes  float64     `json:"current_bytes_per_op"`
+	DeltaBytes    float64     `json:"delta_bytes_per_op"`
+	Grew          []SiteDelta `json:"grew"`
+	Shrank        []SiteDelta `json:"shrank"`
+}
+
+// AllocationDiff diffs the allocation sites of every operation group
+// profiled in both runs, keeping the top n grown and shrunk sites. Groups
+// without iteration counts (heap_hotspots.json from older harnesses) are
+// skipped, since their totals depend on b.N.
+func AllocationDiff(baseline, current *report.HeapHotspots, n int) []OperationAllocations {
+	ops := make([]string, 0, len(current.Iterations))
+	for op := range current.Iterations {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	var out []OperationAllocations
+	for _, op := range ops {
+		baseIters, curIters := baseline.Iterations[op], current.Iterations[op]
+		if baseIters <= 0 || curIters <= 0 {
+			continue
+		}
+		base := perOp(allSites(baseline, op), baseIters)
+		curr := perOp(allSites(current, op), curIters)
+
+		oa := OperationAllocations{Operation: op, Grew: []SiteDelta{}, Shrank: []SiteDelta{}}
+		for fn := range union(base, curr) {
+			b, inBase := base[fn]
+			c, inCurr := curr[fn]
+			d := SiteDelta{
+				Function:         fn,
+				BaselineBytes:    b.bytes,
+				CurrentBytes:     c.bytes,
+				DeltaBytes:       round2(c.bytes - b.bytes),
+				BaselineObjects:  b.objects,
+				CurrentObjects:   c.objects,
+				DeltaObjects:     round2(c.objects - b.objects),
+				NewInCurrent:     !inBase,
+				GoneFromBaseline: !inCurr,
+			}
+			oa.BaselineBytes += b.bytes
+			oa.CurrentBytes += c.bytes
+			switch {
+			case d.DeltaBytes > 0:
+				oa.Grew = append(oa.Grew, d)
+			case d.DeltaBytes < 0:
+				oa.Shrank = append(oa.Shrank, d)
+			}
+		}
+		oa.BaselineBytes = round2(oa.BaselineBytes)
+		oa.CurrentBytes = round2(oa.CurrentBytes)
+		oa.DeltaBytes = round2(oa.CurrentBytes - oa.BaselineBytes)
+		oa.Grew = topDeltas(oa.Grew, n, func(d SiteDelta) float64 { return d.DeltaBytes })
+		oa.Shrank = topDeltas(oa.Shrank, n, func(d SiteDelta) float64 { return -d.DeltaBytes })
+		out = append(out, oa)
+	}
+	return out
+}
+
+type siteRate struct{ bytes, objects float64 }
+
+// allSites prefers the complete site list and falls back to the top-N.
+func allSites(h *report.HeapHotspots, op string) []report.AllocationSite {
+	if sites, ok := h.Sites[op]; ok {
+		return sites
+	}
+	return h.Groups[op]
+}
+
+func perOp(sites []report.AllocationSite, iterations int64) map[string]siteRate {
+	out := make(map[string]siteRate, len(sites))
+	for _, s := range sites {
+		out[s.Function] = siteRate{
+			bytes:   round2(float64(s.Bytes) / float64(iterations)),
+			objects: round2(float64(s.Objects) / float64(iterations)),
+		}
+	}
+	return out
+}
+
+func union(a, b map[string]siteRate) map[string]bool {
+	out := make(map[string]bool, len(a)+len(b))
+	for fn := range a {
+		out[fn] = true
+	}
+	for fn := range b {
+		out[fn] = true
+	}
+	return out
+}
+
+// topDeltas sorts by descending key, ties broken by name, and keeps n.
+func topDeltas(ds []SiteDelta, n int, key func(SiteDelta) float64) []SiteDelta {
+	sort.Slice(ds, func(i, j int) bool {
+		if key(ds[i]) != key(ds[j]) {
+			return key(ds[i]) > key(ds[j])
+		}
+		return ds[i].Function < ds[j].Function
+	})
+	if n >= 0 && len(ds) > n {
+		ds = ds[:n]
+	}
+	return ds
+}
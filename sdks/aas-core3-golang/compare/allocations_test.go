@@ -0,0 +1,39 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func TestAllocationDiffNormalizesPerIteration(t *testing.T) {
+	baseline := &report.HeapHotspots{
+		Sites: map[string][]report.AllocationSite{"deserialize": {
+			{Function: "pkg.decode", Bytes: 1000, Objects: 10},
+			{Function: "pkg.old", Bytes: 500, Objects: 5},
+		}},
+		Iterations: map[string]int64{"deserialize": 10},
+	}
+	current := &report.HeapHotspots{
+		Sites: map[string][]report.AllocationSite{"deserialize": {
+			{Function: "pkg.decode", Bytes: 4000, Objects: 20},
+			{Function: "pkg.new", Bytes: 600, Objects: 20},
+		}},
+		Iterations: map[string]int64{"deserialize": 20, "serialize": 5},
+	}
+
+	diff := AllocationDiff(baseline, current, DefaultAllocationSites)
+	if len(diff) != 1 {
+		t.Fatalf("got %d operations, want only deserialize: %+v", len(diff), diff)
+	}
+	oa := diff[0]
+	if oa.BaselineBytes != 150 || oa.CurrentBytes != 230 || oa.DeltaBytes != 80 {
+		t.Errorf("totals = %v -> %v (%v), want 150 -> 230 (80)", oa.BaselineBytes, oa.CurrentBytes, oa.DeltaBytes)
+	}
+	if len(oa.Grew) != 2 || oa.Grew[0].Function != "pkg.decode" || oa.Grew[0].DeltaBytes != 100 || !oa.Grew[1].NewInCurrent {
+		t.Errorf("grew = %+v", oa.Grew)
+	}
+	if len(oa.Shrank) != 1 || oa.Shrank[0].Function != "pkg.old" || !oa.Shrank[0].GoneFromBaseline {
+		t.Errorf("shrank = %+v", oa.Shrank)
+	}
+}
@@ -0,0 +1,453 @@
+// Package compare detects statistically significant changes between two
+// benchmark reports. When both reports carry the per-run samples of an
+// operation, a Mann-Whitney U test decides; otherwise it uses the same
+// Welch-style 95% confidence interval on the relative change as the
+// regression detection in scripts/aggregate.py.
+package compare
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+const (
+	// DefaultThresholdPct is the minimum relative change (in percent) the
+	// whole confidence interval must exceed to count as significant.
+	DefaultThresholdPct = 5.0
+
+	// ResolutionNs is the smallest mean difference that counts on a
+	// sub-millisecond operation; below it a change is timer and scheduling
+	// jitter whatever the statistics say.
+	ResolutionNs = 1000
+
+	// AllocThresholdPct is the change in allocs/op beyond which an
+	// allocation count is classified as improved or regressed. Allocation
+	// counts are near-deterministic, so a fixed bound serves where timings
+	// need their spread.
+	AllocThresholdPct = 5.0
+
+	// DefaultAlpha is the p-value below which a Mann-Whitney U test finds
+	// a change significant.
+	DefaultAlpha = 0.05
+
+	z95 = 1.96
+
+	subMillisecondNs = 1e6
+)
+
+// Direction values for a Delta.
+const (
+	Regression  = "regression"
+	Improvement = "improvement"
+	Unchanged   = "unchanged"
+)
+
+// Classification values annotate a delta with what the change means for
+// its operation. Unlike Direction they are judged against the operation's
+// own spread, not the gating threshold.
+const (
+	Improved  = "improved"
+	Regressed = "regressed"
+	Neutral   = "neutral"
+	Noise     = "noise"
+)
+
+// Significance tests a Delta can be judged by.
+const (
+	// WelchTest requires the 95% confidence interval of the relative
+	// change to lie wholly beyond the threshold.
+	WelchTest = "welch_ci"
+	// MannWhitneyTest requires a Mann-Whitney U p-value below alpha and a
+	// change beyond the threshold.
+	MannWhitneyTest = "mann_whitney_u"
+)
+
+// Sample summarizes one side of a comparison.
+type Sample struct {
+	MeanNs   float64
+	StddevNs float64
+	N        int
+	// Values are the per-run ns/op samples, when the report carries them.
+	Values []float64
+}
+
+// Delta is the comparison result for one dataset/operation pair.
+type Delta struct {
+	Dataset        string  `json:"dataset"`
+	Operation      string  `json:"operation"`
+	PreviousMeanNs int64   `json:"previous_mean_ns"`
+	CurrentMeanNs  int64   `json:"current_mean_ns"`
+	ChangePct      float64 `json:"change_pct"`
+	CILowerPct     float64 `json:"ci_lower_pct"`
+	CIUpperPct     float64 `json:"ci_upper_pct"`
+	Significant    bool    `json:"significant"`
+	Direction      string  `json:"direction"`
+	// Test is the significance test that judged the change: WelchTest or
+	// MannWhitneyTest.
+	Test string `json:"test"`
+	// PValue is the Mann-Whitney U test's, and CliffsDelta its effect size
+	// with Effect naming how large it is, so that a tiny but significant
+	// change and a large but noisy one read differently. All three are
+	// set under MannWhitneyTest only.
+	PValue      *float64 `json:"p_value,omitempty"`
+	CliffsDelta *float64 `json:"cliffs_delta,omitempty"`
+	Effect      string   `json:"effect,omitempty"`
+	// Classification is Improved, Regressed, Neutral or Noise; see classify.
+	Classification string `json:"classification"`
+	// Allocations compares allocs/op when both reports carry them.
+	Allocations *AllocationChange `json:"allocations,omitempty"`
+	// Namespace is set for extension operations ("vendorx:transform").
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// AllocationChange compares the allocation counts of one operation,
+// separately from its timing.
+type AllocationChange struct {
+	PreviousPerOp int64 `json:"previous_allocs_per_op"`
+	CurrentPerOp  int64 `json:"current_allocs_per_op"`
+	// ChangePct is nil when the baseline did not allocate.
+	ChangePct      *float64 `json:"change_pct"`
+	Classification string   `json:"classification"`
+}
+
+// Summary counts deltas by direction.
+type Summary struct {
+	Regressions  int `json:"regressions"`
+	Improvements int `json:"improvements"`
+	Unchanged    int `json:"unchanged"`
+	// ExtensionRegressions is how many of Regressions are in namespaced
+	// extension operations, which do not gate by default.
+	ExtensionRegressions int `json:"extension_regressions"`
+}
+
+// GatingRegressions returns the regressions that should fail a run:
+// canonical operations only, or all of them when includeExtensions is set.
+func (s Summary) GatingRegressions(includeExtensions bool) int {
+	if includeExtensions {
+		return s.Regressions
+	}
+	return s.Regressions - s.ExtensionRegressions
+}
+
+// Comparison is the schema of comparison.json.
+type Comparison struct {
+	BaselineSDKID string  `json:"baseline_sdk_id"`
+	CurrentSDKID  string  `json:"current_sdk_id"`
+	ThresholdPct  float64 `json:"threshold_pct"`
+	// Alpha is the significance level of the Mann-Whitney U tests.
+	Alpha   float64 `json:"alpha"`
+	Summary Summary `json:"summary"`
+	Deltas  []Delta `json:"deltas"`
+	// EnvironmentDifferences lists host properties that differ between the
+	// runs; timing deltas across different hosts are not meaningful.
+	EnvironmentDifferences []string `json:"environment_differences,omitempty"`
+	// AllocationDiff is set when both runs were heap profiled.
+	AllocationDiff []OperationAllocations `json:"allocation_diff,omitempty"`
+	// Headline compares the current report's headline metrics with the
+	// same stats of the baseline.
+	Headline []HeadlineChange `json:"headline,omitempty"`
+	// Build compares the SDK's compile time and binary sizes when both
+	// reports recorded build_metrics.
+	Build []BuildChange `json:"build,omitempty"`
+}
+
+// BuildChange is one build metric of the current report next to the
+// baseline's. Like the headline, it is not tested for significance and
+// never counts as a regression.
+type BuildChange struct {
+	Metric    string   `json:"metric"`
+	Unit      string   `json:"unit"`
+	Previous  float64  `json:"previous"`
+	Current   float64  `json:"current"`
+	ChangePct *float64 `json:"change_pct"`
+}
+
+// PreviousDisplay formats Previous in its unit.
+func (b BuildChange) PreviousDisplay() string { return formatBuild(b.Previous, b.Unit) }
+
+// CurrentDisplay formats Current in its unit.
+func (b BuildChange) CurrentDisplay() string { return formatBuild(b.Current, b.Unit) }
+
+// ChangeDisplay formats ChangePct as a signed percentage.
+func (b BuildChange) ChangeDisplay() string {
+	if b.ChangePct == nil {
+		return "–"
+	}
+	return fmt.Sprintf("%+.2f%%", *b.ChangePct)
+}
+
+func formatBuild(v float64, unit string) string {
+	if unit == "s" {
+		return fmt.Sprintf("%.2f s", v)
+	}
+	return report.FormatValue(v, unit)
+}
+
+// HeadlineChange is one headline metric of the current report next to the
+// baseline's value. Previous and ChangePct are nil when the baseline lacks
+// the metric. No significance test applies; the deltas carry that.
+type HeadlineChange struct {
+	report.HeadlineMetric
+	Previous  *int64   `json:"previous"`
+	ChangePct *float64 `json:"change_pct"`
+}
+
+// PreviousDisplay formats Previous like HeadlineMetric.Display.
+func (h HeadlineChange) PreviousDisplay() string {
+	if h.Previous == nil {
+		return "–"
+	}
+	return report.FormatValue(float64(*h.Previous), h.Unit)
+}
+
+// ChangeDisplay formats ChangePct as a signed percentage.
+func (h HeadlineChange) ChangeDisplay() string {
+	if h.ChangePct == nil {
+		return "–"
+	}
+	return fmt.Sprintf("%+.2f%%", *h.ChangePct)
+}
+
+// Operation compares two samples. ok is false when the pair is not
+// comparable (missing baseline mean or fewer than two samples on a side).
+// With at least two values on each side the change is significant when a
+// Mann-Whitney U test puts it below alpha and it exceeds thresholdPct;
+// otherwise when its confidence interval lies beyond thresholdPct.
+func Operation(prev, curr Sample, thresholdPct, alpha float64) (d Delta, ok bool) {
+	if prev.MeanNs == 0 || prev.N <= 1 || curr.N <= 1 {
+		return Delta{}, false
+	}
+
+	changePct := (curr.MeanNs - prev.MeanNs) / prev.MeanNs * 100.0
+
+	// Standard error of the difference, expressed relative to the baseline.
+	seDiff := math.Sqrt(curr.StddevNs*curr.StddevNs/float64(curr.N) +
+		prev.StddevNs*prev.StddevNs/float64(prev.N))
+	sePct := seDiff / prev.MeanNs * 100.0
+	ciLower := changePct - z95*sePct
+	ciUpper := changePct + z95*sePct
+
+	d = Delta{
+		PreviousMeanNs: int64(math.Round(prev.MeanNs)),
+		CurrentMeanNs:  int64(math.Round(curr.MeanNs)),
+		ChangePct:      round2(changePct),
+		CILowerPct:     round2(ciLower),
+		CIUpperPct:     round2(ciUpper),
+		Direction:      Unchanged,
+		Test:           WelchTest,
+		Classification: classify(prev, curr, ciLower, ciUpper),
+	}
+	if len(prev.Values) >= 2 && len(curr.Values) >= 2 {
+		_, p := MannWhitneyU(prev.Values, curr.Values)
+		delta := CliffsDelta(prev.Values, curr.Values)
+		p, delta = math.Round(p*1e4)/1e4, round2(delta)
+		d.Test, d.PValue, d.CliffsDelta, d.Effect = MannWhitneyTest, &p, &delta, EffectMagnitude(delta)
+		switch {
+		case p >= alpha:
+		case changePct > thresholdPct:
+			d.Significant, d.Direction = true, Regression
+		case changePct < -thresholdPct:
+			d.Significant, d.Direction = true, Improvement
+		}
+		return d, true
+	}
+	switch {
+	case ciLower > thresholdPct:
+		d.Significant, d.Direction = true, Regression
+	case ciUpper < -thresholdPct:
+		d.Significant, d.Direction = true, Improvement
+	}
+	return d, true
+}
+
+// classify judges a timing change by the operation's own measurements. A
+// difference below ResolutionNs on a sub-millisecond operation, or within
+// the larger of the two standard deviations, is noise. Beyond that, the
+// change is improved or regressed when its 95% confidence interval, whose
+// width the per-operation stddev sets, excludes zero, and neutral when it
+// does not.
+func classify(prev, curr Sample, ciLower, ciUpper float64) string {
+	diff := math.Abs(curr.MeanNs - prev.MeanNs)
+	if prev.MeanNs < subMillisecondNs && diff < ResolutionNs {
+		return Noise
+	}
+	if diff <= math.Max(prev.StddevNs, curr.StddevNs) {
+		return Noise
+	}
+	switch {
+	case ciLower > 0:
+		return Regressed
+	case ciUpper < 0:
+		return Improved
+	}
+	return Neutral
+}
+
+// Allocations compares the allocs/op of two memory entries against
+// AllocThresholdPct. It returns nil unless both carry a count.
+func Allocations(prev, curr report.MemoryEntry) *AllocationChange {
+	if prev.AllocCountPerOp == nil || curr.AllocCountPerOp == nil {
+		return nil
+	}
+	a := &AllocationChange{
+		PreviousPerOp:  *prev.AllocCountPerOp,
+		CurrentPerOp:   *curr.AllocCountPerOp,
+		Classification: Neutral,
+	}
+	if a.PreviousPerOp == 0 {
+		if a.CurrentPerOp > 0 {
+			a.Classification = Regressed
+		}
+		return a
+	}
+	pct := round2(float64(a.CurrentPerOp-a.PreviousPerOp) / float64(a.PreviousPerOp) * 100)
+	a.ChangePct = &pct
+	switch {
+	case pct > AllocThresholdPct:
+		a.Classification = Regressed
+	case pct < -AllocThresholdPct:
+		a.Classification = Improved
+	}
+	return a
+}
+
+// SampleOf extracts the comparison inputs from a report operation,
+// preferring independent sample_count over loop iterations.
+func SampleOf(op report.OperationEntry) Sample {
+	n := op.SampleCount
+	if n == 0 {
+		n = op.Iterations
+	}
+	s := Sample{MeanNs: float64(op.MeanNs), StddevNs: float64(op.StddevNs), N: n}
+	for _, ns := range op.SamplesNs {
+		s.Values = append(s.Values, float64(ns))
+	}
+	return s
+}
+
+// Reports compares every dataset/operation present in both reports.
+// Operation keys are matched by normalized ID including the namespace, so
+// an extension operation is never compared against a canonical one.
+func Reports(baseline, current *report.Report, thresholdPct, alpha float64) *Comparison {
+	c := &Comparison{
+		BaselineSDKID: baseline.SDKID,
+		CurrentSDKID:  current.SDKID,
+		ThresholdPct:  thresholdPct,
+		Alpha:         alpha,
+		Deltas:        []Delta{},
+	}
+	if baseline.Environment != nil && current.Environment != nil {
+		c.EnvironmentDifferences = baseline.Environment.Differences(current.Environment)
+	}
+	c.Headline = headlineChanges(baseline, current)
+	c.Build = buildChanges(baseline.BuildMetrics, current.BuildMetrics)
+	for _, dsName := range sortedDatasets(current) {
+		prevDS, ok := baseline.Datasets[dsName]
+		if !ok {
+			continue
+		}
+		prevOps := normalizedOperations(prevDS)
+		currOps := normalizedOperations(current.Datasets[dsName])
+		for _, opID := range sortedKeys(currOps) {
+			prevOp, ok := prevOps[opID]
+			if !ok {
+				continue
+			}
+			d, ok := Operation(SampleOf(prevOp), SampleOf(currOps[opID]), thresholdPct, alpha)
+			if !ok {
+				continue
+			}
+			d.Dataset, d.Operation = dsName, opID
+			d.Allocations = Allocations(prevOp.Memory, currOps[opID].Memory)
+			d.Namespace, _ = report.SplitOperationID(opID)
+			switch d.Direction {
+			case Regression:
+				c.Summary.Regressions++
+				if d.Namespace != "" {
+					c.Summary.ExtensionRegressions++
+				}
+			case Improvement:
+				c.Summary.Improvements++
+			default:
+				c.Summary.Unchanged++
+			}
+			c.Deltas = append(c.Deltas, d)
+		}
+	}
+	return c
+}
+
+// headlineChanges looks up each headline metric of current in baseline.
+// Reports written before headlines existed get theirs selected on the fly.
+func headlineChanges(baseline, current *report.Report) []HeadlineChange {
+	headline := current.Headline
+	if headline == nil {
+		headline = report.DefaultCatalog().SelectHeadline(current.Datasets)
+	}
+	changes := make([]HeadlineChange, 0, len(headline))
+	for _, h := range headline {
+		hc := HeadlineChange{HeadlineMetric: h}
+		if prev, ok := report.HeadlineValue(baseline.Datasets, h.Dataset, h.Operation, h.Stat); ok {
+			pct := round2(float64(h.Value-prev) / float64(prev) * 100)
+			hc.Previous, hc.ChangePct = &prev, &pct
+		}
+		changes = append(changes, hc)
+	}
+	return changes
+}
+
+// buildChanges compares the build metrics that say most about adopting
+// the SDK: its compile time, the binary sizes and its share of a stripped
+// binary.
+func buildChanges(baseline, current *report.BuildMetrics) []BuildChange {
+	if baseline == nil || current == nil {
+		return nil
+	}
+	changes := []BuildChange{
+		{Metric: "compile_s", Unit: "s", Previous: baseline.CompileS, Current: current.CompileS},
+		{Metric: "binary_size_bytes", Unit: "bytes", Previous: float64(baseline.BinarySizeBytes), Current: float64(current.BinarySizeBytes)},
+		{Metric: "stripped_size_bytes", Unit: "bytes", Previous: float64(baseline.StrippedSizeBytes), Current: float64(current.StrippedSizeBytes)},
+		{Metric: "sdk_stripped_size_bytes", Unit: "bytes", Previous: float64(baseline.SDKStrippedSizeBytes), Current: float64(current.SDKStrippedSizeBytes)},
+	}
+	for i, b := range changes {
+		if b.Previous != 0 {
+			pct := round2((b.Current - b.Previous) / b.Previous * 100)
+			changes[i].ChangePct = &pct
+		}
+	}
+	return changes
+}
+
+func sortedDatasets(r *report.Report) []string {
+	names := make([]string, 0, len(r.Datasets))
+	for name := range r.Datasets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// normalizedOperations keys a dataset's operations by normalized ID.
+func normalizedOperations(ds report.DatasetEntry) map[string]report.OperationEntry {
+	ops := make(map[string]report.OperationEntry, len(ds.Operations))
+	for key, op := range ds.Operations {
+		ops[report.NormalizeOperationID(key)] = op
+	}
+	return ops
+}
+
+func sortedKeys(ops map[string]report.OperationEntry) []string {
+	names := make([]string, 0, len(ops))
+	for name := range ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,120 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func TestReportsComparesHeadline(t *testing.T) {
+	baseline := overlayReport("go1.22.5", map[string]int64{"deserialize": 1000})
+	current := overlayReport("go1.22.5", map[string]int64{"deserialize": 1100, "serialize": 400})
+	current.Headline = report.DefaultCatalog().SelectHeadline(current.Datasets)
+
+	c := Reports(baseline, current, DefaultThresholdPct, DefaultAlpha)
+	if len(c.Headline) != 2 {
+		t.Fatalf("headline = %+v, want deserialize and serialize on mixed", c.Headline)
+	}
+	if h := c.Headline[0]; h.Previous == nil || *h.Previous != 1000 || h.ChangeDisplay() != "+10.00%" {
+		t.Errorf("deserialize = %+v (%s)", h, h.ChangeDisplay())
+	}
+	if h := c.Headline[1]; h.Previous != nil || h.PreviousDisplay() != "–" {
+		t.Errorf("serialize has no baseline but got %+v", h)
+	}
+}
+
+func TestReportsComparesBuildMetrics(t *testing.T) {
+	baseline := overlayReport("go1.22.5", map[string]int64{"deserialize": 1000})
+	current := overlayReport("go1.22.5", map[string]int64{"deserialize": 1000})
+	if c := Reports(baseline, current, DefaultThresholdPct, DefaultAlpha); c.Build != nil {
+		t.Fatalf("build = %+v without build_metrics", c.Build)
+	}
+	baseline.BuildMetrics = &report.BuildMetrics{CompileS: 8, BinarySizeBytes: 4 << 20, StrippedSizeBytes: 3 << 20, SDKStrippedSizeBytes: 2 << 20}
+	current.BuildMetrics = &report.BuildMetrics{CompileS: 10, BinarySizeBytes: 4 << 20, StrippedSizeBytes: 3 << 20, SDKStrippedSizeBytes: 3 << 20}
+
+	c := Reports(baseline, current, DefaultThresholdPct, DefaultAlpha)
+	if len(c.Build) != 4 {
+		t.Fatalf("build = %+v, want four metrics", c.Build)
+	}
+	if b := c.Build[0]; b.Metric != "compile_s" || b.ChangeDisplay() != "+25.00%" || b.CurrentDisplay() != "10.00 s" {
+		t.Errorf("compile_s = %+v (%s, %s)", b, b.CurrentDisplay(), b.ChangeDisplay())
+	}
+	if b := c.Build[3]; b.ChangeDisplay() != "+50.00%" || b.PreviousDisplay() != "2 MiB" {
+		t.Errorf("sdk_stripped_size_bytes = %+v (%s)", b, b.PreviousDisplay())
+	}
+	if c.Summary.Regressions != 0 {
+		t.Errorf("build changes counted %d regressions", c.Summary.Regressions)
+	}
+}
+
+func TestOperationClassifiesByOwnSpread(t *testing.T) {
+	cases := []struct {
+		name       string
+		prev, curr Sample
+		want       string
+	}{
+		{"sub-resolution on a sub-ms op", Sample{MeanNs: 2000, StddevNs: 1, N: 1000}, Sample{MeanNs: 2800, StddevNs: 1, N: 1000}, Noise},
+		{"within the stddev", Sample{MeanNs: 5e6, StddevNs: 4e5, N: 30}, Sample{MeanNs: 5.3e6, StddevNs: 4e5, N: 30}, Noise},
+		{"tight spread", Sample{MeanNs: 5e6, StddevNs: 1e4, N: 30}, Sample{MeanNs: 5.1e6, StddevNs: 1e4, N: 30}, Regressed},
+		{"faster", Sample{MeanNs: 5e6, StddevNs: 1e4, N: 30}, Sample{MeanNs: 4.9e6, StddevNs: 1e4, N: 30}, Improved},
+		{"beyond the stddev, too few samples", Sample{MeanNs: 5e6, StddevNs: 4e5, N: 2}, Sample{MeanNs: 5.5e6, StddevNs: 4e5, N: 2}, Neutral},
+	}
+	for _, tc := range cases {
+		d, ok := Operation(tc.prev, tc.curr, DefaultThresholdPct, DefaultAlpha)
+		if !ok || d.Classification != tc.want {
+			t.Errorf("%s: classification = %q, want %q (%+v)", tc.name, d.Classification, tc.want, d)
+		}
+	}
+}
+
+func TestAllocationsClassifiedApartFromTiming(t *testing.T) {
+	count := func(n int64) report.MemoryEntry { return report.MemoryEntry{AllocCountPerOp: &n} }
+	if a := Allocations(count(100), count(104)); a.Classification != Neutral {
+		t.Errorf("+4%% = %+v, want neutral", a)
+	}
+	if a := Allocations(count(100), count(110)); a.Classification != Regressed || *a.ChangePct != 10 {
+		t.Errorf("+10%% = %+v, want regressed", a)
+	}
+	if a := Allocations(count(0), count(3)); a.Classification != Regressed || a.ChangePct != nil {
+		t.Errorf("0 -> 3 = %+v, want regressed without a percentage", a)
+	}
+	if a := Allocations(report.MemoryEntry{}, count(3)); a != nil {
+		t.Errorf("missing baseline count = %+v, want nil", a)
+	}
+}
+
+func TestOperationTestsSamplesWithMannWhitney(t *testing.T) {
+	sample := func(values ...float64) Sample {
+		mean, _, stddev, _, _ := report.ComputeStats(values)
+		return Sample{MeanNs: mean, StddevNs: stddev, N: len(values), Values: values}
+	}
+	cases := []struct {
+		name        string
+		prev, curr  Sample
+		significant bool
+		effect      string
+	}{
+		{"tight shift",
+			sample(1.00e6, 1.01e6, 1.02e6, 1.03e6, 1.04e6),
+			sample(1.10e6, 1.11e6, 1.12e6, 1.13e6, 1.14e6), true, EffectLarge},
+		{"large but noisy",
+			sample(1.0e6, 2.0e6, 1.5e6, 1.2e6, 1.8e6),
+			sample(1.3e6, 2.4e6, 1.6e6, 1.9e6, 2.1e6), false, EffectLarge},
+	}
+	for _, tc := range cases {
+		d, ok := Operation(tc.prev, tc.curr, DefaultThresholdPct, DefaultAlpha)
+		if !ok || d.Test != MannWhitneyTest || d.PValue == nil {
+			t.Fatalf("%s: %+v, want a Mann-Whitney delta", tc.name, d)
+		}
+		if d.Significant != tc.significant || d.Effect != tc.effect {
+			t.Errorf("%s: significant %v (p %v), effect %s; want %v, %s", tc.name, d.Significant, *d.PValue, d.Effect, tc.significant, tc.effect)
+		}
+	}
+
+	// Without samples on both sides the confidence interval decides.
+	prev := sample(1.00e6, 1.01e6, 1.02e6, 1.03e6, 1.04e6)
+	prev.Values = nil
+	if d, _ := Operation(prev, cases[0].curr, DefaultThresholdPct, DefaultAlpha); d.Test != WelchTest || d.PValue != nil {
+		t.Errorf("one-sided samples: %+v, want the Welch CI", d)
+	}
+}
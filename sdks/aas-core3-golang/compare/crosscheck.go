@@ -0,0 +1,199 @@
+package compare
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Kinds of CrossCheck findings.
+const (
+	// FindingDatasetHash: the two adapters read different bytes for the
+	// same dataset and serialization.
+	FindingDatasetHash = "dataset_hash"
+	// FindingElementCount: the inputs hold different numbers of AAS
+	// elements, so the work per operation differs.
+	FindingElementCount = "element_count"
+	// FindingUnverified: a report carries no fingerprint for an input, so
+	// the equivalence of the inputs cannot be shown.
+	FindingUnverified = "unverified_input"
+	// FindingMethodology: the operations were measured differently.
+	FindingMethodology = "methodology"
+)
+
+// Finding is one reason a cross-language comparison is not like for like.
+// Dataset and Format are empty for findings about the runs as a whole;
+// Operations lists the paired operations the finding affects.
+type Finding struct {
+	Kind       string   `json:"kind"`
+	Dataset    string   `json:"dataset,omitempty"`
+	Format     string   `json:"format,omitempty"`
+	Operations []string `json:"operations,omitempty"`
+	Detail     string   `json:"detail"`
+}
+
+// CrossCheck is the result of checking that two adapters' reports measured
+// the same thing.
+type CrossCheck struct {
+	LeftSDKID  string `json:"left_sdk_id"`
+	RightSDKID string `json:"right_sdk_id"`
+	// Paired counts the dataset/operation pairs both reports measured.
+	Paired int `json:"paired_operations"`
+	// Verified counts the pairs whose inputs were shown identical.
+	Verified int       `json:"verified_operations"`
+	Findings []Finding `json:"findings"`
+}
+
+// Mismatches counts the findings showing the adapters read different
+// inputs, as opposed to inputs that could not be checked.
+func (c *CrossCheck) Mismatches() int {
+	n := 0
+	for _, f := range c.Findings {
+		if f.Kind == FindingDatasetHash || f.Kind == FindingElementCount {
+			n++
+		}
+	}
+	return n
+}
+
+// CrossCheckReports pairs the canonical operations measured by both
+// reports and checks that each pair read identical inputs: the fingerprint
+// of the serialization the operation consumes (JSON, XML or AASX) must have
+// the same SHA-256 and element count in both datasets_manifest blocks.
+// Reports without a manifest fall back to the datasets' element counts and
+// the pair stays unverified. Differences in measurement semantics, sample
+// counts and host are flagged as methodology findings.
+func CrossCheckReports(left, right *report.Report) *CrossCheck {
+	c := &CrossCheck{LeftSDKID: left.SDKID, RightSDKID: right.SDKID, Findings: []Finding{}}
+	if left.Environment != nil && right.Environment != nil {
+		if diffs := left.Environment.Differences(right.Environment); len(diffs) > 0 {
+			c.Findings = append(c.Findings, Finding{
+				Kind:   FindingMethodology,
+				Detail: "measured on different hosts: " + strings.Join(diffs, "; "),
+			})
+		}
+	}
+
+	for _, dsName := range sortedDatasets(left) {
+		rightDS, ok := right.Datasets[dsName]
+		if !ok {
+			continue
+		}
+		leftOps := normalizedOperations(left.Datasets[dsName])
+		rightOps := normalizedOperations(rightDS)
+		// Operations of a dataset share their input per serialization.
+		byFormat := map[string][]string{}
+		for _, opID := range sortedKeys(leftOps) {
+			l, r := leftOps[opID], rightOps[opID]
+			if ns, _ := report.SplitOperationID(opID); ns != "" {
+				continue
+			}
+			if _, ok := rightOps[opID]; !ok || !l.Measured() || !r.Measured() {
+				continue
+			}
+			c.Paired++
+			format := inputFormat(dsName, opID)
+			byFormat[format] = append(byFormat[format], opID)
+			c.Findings = append(c.Findings, methodologyFindings(dsName, opID, l, r)...)
+		}
+
+		formats := make([]string, 0, len(byFormat))
+		for format := range byFormat {
+			formats = append(formats, format)
+		}
+		sort.Strings(formats)
+		for _, format := range formats {
+			ops := byFormat[format]
+			found := c.checkInput(left, right, dsName, format, ops)
+			if len(found) == 0 {
+				c.Verified += len(ops)
+			}
+			c.Findings = append(c.Findings, found...)
+		}
+	}
+	return c
+}
+
+// inputFormat is the dataset serialization an operation reads.
+func inputFormat(dataset, opID string) string {
+	switch report.InferOperationTrack(dataset, opID) {
+	case "xml":
+		return "xml"
+	case "aasx":
+		return "aasx"
+	}
+	return "json"
+}
+
+func (c *CrossCheck) checkInput(left, right *report.Report, dataset, format string, ops []string) []Finding {
+	finding := func(kind, detail string) Finding {
+		return Finding{Kind: kind, Dataset: dataset, Format: format, Operations: ops, Detail: detail}
+	}
+	lfp, lok := fingerprint(left, dataset, format)
+	rfp, rok := fingerprint(right, dataset, format)
+	if !lok || !rok {
+		var missing []string
+		if !lok {
+			missing = append(missing, left.SDKID)
+		}
+		if !rok {
+			missing = append(missing, right.SDKID)
+		}
+		found := []Finding{finding(FindingUnverified, fmt.Sprintf("no %s fingerprint in %s", format, strings.Join(missing, " and ")))}
+		// The datasets' own element counts still expose different inputs.
+		if format == "json" {
+			lc, rc := left.Datasets[dataset].ElementCount, right.Datasets[dataset].ElementCount
+			if lc != nil && rc != nil && *lc != *rc {
+				found = append(found, finding(FindingElementCount, fmt.Sprintf("%s has %d elements, %s has %d", left.SDKID, *lc, right.SDKID, *rc)))
+			}
+		}
+		return found
+	}
+
+	var found []Finding
+	if lfp.SHA256 != rfp.SHA256 {
+		found = append(found, finding(FindingDatasetHash, fmt.Sprintf("%s read %s (sha256 %.12s), %s read %s (sha256 %.12s)",
+			left.SDKID, lfp.File, lfp.SHA256, right.SDKID, rfp.File, rfp.SHA256)))
+	}
+	if lfp.ElementCount != nil && rfp.ElementCount != nil && *lfp.ElementCount != *rfp.ElementCount {
+		found = append(found, finding(FindingElementCount, fmt.Sprintf("%s has %d elements, %s has %d",
+			left.SDKID, *lfp.ElementCount, right.SDKID, *rfp.ElementCount)))
+	}
+	return found
+}
+
+// fingerprint finds the manifest entry for a dataset's serialization.
+func fingerprint(r *report.Report, dataset, format string) (report.DatasetFingerprint, bool) {
+	for _, fp := range r.Metadata.DatasetsManifest {
+		if fp.Dataset == dataset && fp.Format == format {
+			return fp, true
+		}
+	}
+	return report.DatasetFingerprint{}, false
+}
+
+// methodologyFindings flags differences in how a paired operation was
+// measured.
+func methodologyFindings(dataset, opID string, l, r report.OperationEntry) []Finding {
+	var details []string
+	if l.MeasurementSemantics != r.MeasurementSemantics {
+		details = append(details, fmt.Sprintf("measurement_semantics %q vs %q", l.MeasurementSemantics, r.MeasurementSemantics))
+	}
+	// A single sample has no spread, so no confidence interval can be
+	// put on a difference between the two.
+	ln, rn := SampleOf(l).N, SampleOf(r).N
+	if (ln < 2) != (rn < 2) {
+		details = append(details, fmt.Sprintf("%d vs %d independent samples", ln, rn))
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	return []Finding{{
+		Kind:       FindingMethodology,
+		Dataset:    dataset,
+		Operations: []string{opID},
+		Detail:     strings.Join(details, "; "),
+	}}
+}
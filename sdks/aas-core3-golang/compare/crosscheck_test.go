@@ -0,0 +1,69 @@
+package compare
+
+import (
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func crossReport(sdkID, mixedHash string, mixedCount int64) *report.Report {
+	ops := func() map[string]report.OperationEntry {
+		return map[string]report.OperationEntry{
+			"deserialize":     {OperationID: "deserialize", MeasurementSemantics: "mean_ns_per_operation", SampleCount: 10},
+			"deserialize_xml": {OperationID: "deserialize_xml", MeasurementSemantics: "mean_ns_per_operation", SampleCount: 10},
+			"acme:roundtrip":  {OperationID: "acme:roundtrip", SampleCount: 10},
+		}
+	}
+	deepCount := int64(456)
+	return &report.Report{
+		SDKID: sdkID,
+		Datasets: map[string]report.DatasetEntry{
+			"deep":  {Operations: ops()},
+			"mixed": {Operations: ops()},
+		},
+		Metadata: report.Metadata{DatasetsManifest: []report.DatasetFingerprint{
+			{Dataset: "deep", File: "deep.json", Format: "json", SHA256: "aa", ElementCount: &deepCount},
+			{Dataset: "mixed", File: "mixed.json", Format: "json", SHA256: mixedHash, ElementCount: &mixedCount},
+		}},
+	}
+}
+
+func TestCrossCheckReportsFlagsDifferentInputs(t *testing.T) {
+	left := crossReport("aas-core3-golang", "bb", 2165)
+	right := crossReport("aas-core3-rust", "cc", 2000)
+	right.Datasets["deep"].Operations["deserialize"] = report.OperationEntry{MeasurementSemantics: "median_ns_per_operation", SampleCount: 1}
+
+	c := CrossCheckReports(left, right)
+	if c.Paired != 4 {
+		t.Errorf("paired = %d, want 4 (extension operations are not paired)", c.Paired)
+	}
+	// Only deep's JSON input matches; no report fingerprints the XML files.
+	if c.Verified != 1 {
+		t.Errorf("verified = %d, want 1", c.Verified)
+	}
+	kinds := map[string]int{}
+	for _, f := range c.Findings {
+		kinds[f.Kind]++
+	}
+	want := map[string]int{FindingDatasetHash: 1, FindingElementCount: 1, FindingUnverified: 2, FindingMethodology: 1}
+	for kind, n := range want {
+		if kinds[kind] != n {
+			t.Errorf("%d %s finding(s), want %d: %+v", kinds[kind], kind, n, c.Findings)
+		}
+	}
+	if c.Mismatches() != 2 {
+		t.Errorf("mismatches = %d, want 2", c.Mismatches())
+	}
+}
+
+func TestCrossCheckReportsIdenticalInputs(t *testing.T) {
+	c := CrossCheckReports(crossReport("a", "bb", 2165), crossReport("b", "bb", 2165))
+	for _, f := range c.Findings {
+		if f.Kind != FindingUnverified || f.Format != "xml" {
+			t.Errorf("unexpected finding %+v", f)
+		}
+	}
+	if c.Mismatches() != 0 {
+		t.Errorf("mismatches = %d", c.Mismatches())
+	}
+}
@@ -0,0 +1,147 @@
+package compare
+
+import (
+	"math"
+	"sort"
+)
+
+// Effect sizes of a Cliff's delta, by the thresholds of Romano et al.
+// (2006): below 0.147 negligible, below 0.33 small, below 0.474 medium.
+const (
+	EffectNegligible = "negligible"
+	EffectSmall      = "small"
+	EffectMedium     = "medium"
+	EffectLarge      = "large"
+)
+
+// exactMaxSamples is the largest combined sample size whose Mann-Whitney
+// p-value is computed from the exact distribution of U; larger samples use
+// the normal approximation, which is close by then.
+const exactMaxSamples = 40
+
+// MannWhitneyU tests whether x and y come from the same distribution
+// against the two-sided alternative that one tends to be larger. u counts
+// the pairs in which y is larger, ties counting half. Without ties and
+// with at most exactMaxSamples values, p is exact, the share of all
+// assignments of the values to x and y with a U as extreme; otherwise it
+// comes from the normal approximation with tie and continuity correction.
+func MannWhitneyU(x, y []float64) (u, p float64) {
+	n1, n2 := len(x), len(y)
+	if n1 == 0 || n2 == 0 {
+		return 0, 1
+	}
+	for _, a := range x {
+		for _, b := range y {
+			switch {
+			case b > a:
+				u++
+			case b == a:
+				u += 0.5
+			}
+		}
+	}
+
+	all := make([]float64, 0, n1+n2)
+	all = append(append(all, x...), y...)
+	sort.Float64s(all)
+	ties := 0.0
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j] == all[i] {
+			j++
+		}
+		if t := float64(j - i); t > 1 {
+			ties += t*t*t - t
+		}
+		i = j
+	}
+
+	if ties == 0 && n1+n2 <= exactMaxSamples {
+		return u, exactMannWhitneyP(n1, n2, u)
+	}
+	n := float64(n1 + n2)
+	mean := float64(n1*n2) / 2
+	variance := float64(n1*n2) / 12 * (n + 1 - ties/(n*(n-1)))
+	if variance <= 0 {
+		return u, 1
+	}
+	z := math.Max(math.Abs(u-mean)-0.5, 0) / math.Sqrt(variance)
+	return u, math.Erfc(z / math.Sqrt2)
+}
+
+// exactMannWhitneyP returns the two-sided p-value of u for samples of n1
+// and n2 distinct values: twice the probability of a U at least as far
+// into the nearer tail, at most 1.
+func exactMannWhitneyP(n1, n2 int, u float64) float64 {
+	// counts[i][j][k] is the number of orderings of i x- and j y-values
+	// in which k pairs have the y-value larger.
+	counts := make([][][]float64, n1+1)
+	for i := range counts {
+		counts[i] = make([][]float64, n2+1)
+		for j := range counts[i] {
+			c := make([]float64, i*j+1)
+			switch {
+			case i == 0 || j == 0:
+				c[0] = 1
+			default:
+				// The largest value is a y-value, larger than all i
+				// x-values, or an x-value, larger than no y-value.
+				for k := range c {
+					if k >= i {
+						c[k] += counts[i][j-1][k-i]
+					}
+					if k <= i*j-j {
+						c[k] += counts[i-1][j][k]
+					}
+				}
+			}
+			counts[i][j] = c
+		}
+	}
+	dist := counts[n1][n2]
+	total, below, above := 0.0, 0.0, 0.0
+	for k, c := range dist {
+		total += c
+		if float64(k) <= u {
+			below += c
+		}
+		if float64(k) >= u {
+			above += c
+		}
+	}
+	return math.Min(1, 2*math.Min(below, above)/total)
+}
+
+// CliffsDelta returns the effect size of y against x: the share of pairs
+// in which y is larger minus the share in which it is smaller, from -1
+// (every y smaller) to 1 (every y larger).
+func CliffsDelta(x, y []float64) float64 {
+	if len(x) == 0 || len(y) == 0 {
+		return 0
+	}
+	dominance := 0
+	for _, a := range x {
+		for _, b := range y {
+			switch {
+			case b > a:
+				dominance++
+			case b < a:
+				dominance--
+			}
+		}
+	}
+	return float64(dominance) / float64(len(x)*len(y))
+}
+
+// EffectMagnitude names the size of a Cliff's delta.
+func EffectMagnitude(delta float64) string {
+	switch d := math.Abs(delta); {
+	case d < 0.147:
+		return EffectNegligible
+	case d < 0.33:
+		return EffectSmall
+	case d < 0.474:
+		return EffectMedium
+	}
+	return EffectLarge
+}
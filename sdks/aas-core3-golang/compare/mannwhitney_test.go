@@ -0,0 +1,52 @@
+package compare
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMannWhitneyU(t *testing.T) {
+	cases := []struct {
+		name  string
+		x, y  []float64
+		wantU float64
+		wantP float64
+	}{
+		// Exact: 2 of the 252 assignments of ten values to two groups of
+		// five separate them completely.
+		{"separated", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10}, 25, 2.0 / 252},
+		{"interleaved", []float64{1, 3, 5, 7, 9}, []float64{2, 4, 6, 8, 10}, 15, 0.6905},
+		// Ties leave the normal approximation without any spread.
+		{"all tied", []float64{1, 1, 1, 1}, []float64{1, 1, 1, 1}, 8, 1},
+	}
+	for _, tc := range cases {
+		u, p := MannWhitneyU(tc.x, tc.y)
+		if u != tc.wantU || math.Abs(p-tc.wantP) > 1e-4 {
+			t.Errorf("%s: U = %v, p = %.5f; want %v, %.5f", tc.name, u, p, tc.wantU, tc.wantP)
+		}
+	}
+
+	// Beyond exactMaxSamples the normal approximation takes over.
+	var x, y []float64
+	for i := 0; i < 30; i++ {
+		x = append(x, 100+float64(i%7))
+		y = append(y, 104+float64(i%7))
+	}
+	if _, p := MannWhitneyU(x, y); p > 0.001 {
+		t.Errorf("shifted samples of 30: p = %v, want < 0.001", p)
+	}
+}
+
+func TestCliffsDelta(t *testing.T) {
+	if d := CliffsDelta([]float64{1, 2, 3}, []float64{2, 3, 4}); math.Abs(d-5.0/9) > 1e-9 {
+		t.Errorf("delta = %v, want 5/9", d)
+	}
+	if d := CliffsDelta([]float64{5, 6}, []float64{1, 2}); d != -1 {
+		t.Errorf("delta = %v, want -1", d)
+	}
+	for delta, want := range map[float64]string{0.1: EffectNegligible, -0.2: EffectSmall, 0.4: EffectMedium, -0.9: EffectLarge} {
+		if got := EffectMagnitude(delta); got != want {
+			t.Errorf("EffectMagnitude(%v) = %s, want %s", delta, got, want)
+		}
+	}
+}
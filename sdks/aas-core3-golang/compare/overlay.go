@@ -0,0 +1,216 @@
+package compare
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// MaxOverlayReports bounds how many reports one overlay holds; more series
+// than this are unreadable as grouped bars.
+const MaxOverlayReports = 8
+
+// OverlaySeries describes one report of an overlay. Label names it after
+// the dimensions that differ between the overlaid reports.
+type OverlaySeries struct {
+	Label          string `json:"label"`
+	Source         string `json:"source"`
+	SDKID          string `json:"sdk_id"`
+	SDKVersion     string `json:"sdk_version"`
+	RuntimeVersion string `json:"runtime_version"`
+	Arch           string `json:"arch"`
+	Timestamp      string `json:"timestamp"`
+	// Flamegraphs links the report's flamegraphs, relative to the page
+	// the overlay is rendered into; BuildOverlay leaves it to the caller.
+	Flamegraphs map[string]string `json:"flamegraphs,omitempty"`
+}
+
+// OverlayCell is one series' measurement of a dataset/operation pair.
+type OverlayCell struct {
+	MeanNs          int64  `json:"mean_ns"`
+	MedianNs        int64  `json:"median_ns"`
+	StddevNs        int64  `json:"stddev_ns"`
+	AllocBytesPerOp *int64 `json:"alloc_bytes_per_op"`
+}
+
+// OverlayRow holds every series' cell for one dataset/operation pair;
+// Values[i] belongs to Series[i] and is nil when that report lacks the pair.
+type OverlayRow struct {
+	Dataset   string         `json:"dataset"`
+	Operation string         `json:"operation"`
+	Values    []*OverlayCell `json:"values"`
+}
+
+// Overlay is the merged table of several reports.
+type Overlay struct {
+	Series []OverlaySeries `json:"series"`
+	// Dimensions lists, for each series dimension (sdk_id, sdk_version,
+	// runtime_version, arch) with more than one value, the sorted values.
+	Dimensions map[string][]string `json:"dimensions"`
+	Rows       []OverlayRow        `json:"rows"`
+}
+
+// BuildOverlay merges reports into one table keyed by dataset and
+// normalized operation ID. sources name the reports (usually their paths)
+// and labels, when non-empty, override the generated series labels.
+func BuildOverlay(reports []*report.Report, sources, labels []string) (*Overlay, error) {
+	if len(reports) == 0 || len(reports) > MaxOverlayReports {
+		return nil, fmt.Errorf("overlay wants 1 to %d reports, got %d", MaxOverlayReports, len(reports))
+	}
+	if len(labels) > 0 && len(labels) != len(reports) {
+		return nil, fmt.Errorf("got %d label(s) for %d report(s)", len(labels), len(reports))
+	}
+
+	o := &Overlay{Series: make([]OverlaySeries, len(reports))}
+	for i, rep := range reports {
+		s := OverlaySeries{
+			Source:         sources[i],
+			SDKID:          rep.SDKID,
+			SDKVersion:     rep.Metadata.SDKPackageVersion,
+			RuntimeVersion: rep.Metadata.RuntimeVersion,
+			Timestamp:      rep.Metadata.Timestamp,
+		}
+		if rep.Environment != nil {
+			s.Arch = rep.Environment.Arch
+		}
+		o.Series[i] = s
+	}
+	o.Dimensions = seriesDimensions(o.Series)
+	for i, label := range seriesLabels(o.Series) {
+		o.Series[i].Label = label
+		if len(labels) > 0 {
+			o.Series[i].Label = labels[i]
+		}
+	}
+
+	rows := make(map[[2]string]*OverlayRow)
+	for i, rep := range reports {
+		for dsName, ds := range rep.Datasets {
+			for opID, op := range normalizedOperations(ds) {
+				if !op.Measured() {
+					continue
+				}
+				key := [2]string{dsName, opID}
+				row, ok := rows[key]
+				if !ok {
+					row = &OverlayRow{Dataset: dsName, Operation: opID, Values: make([]*OverlayCell, len(reports))}
+					rows[key] = row
+				}
+				row.Values[i] = &OverlayCell{
+					MeanNs:          op.MeanNs,
+					MedianNs:        op.MedianNs,
+					StddevNs:        op.StddevNs,
+					AllocBytesPerOp: op.Memory.AllocBytesPerOp,
+				}
+			}
+		}
+	}
+	for _, row := range rows {
+		o.Rows = append(o.Rows, *row)
+	}
+	sort.Slice(o.Rows, func(i, j int) bool {
+		if o.Rows[i].Dataset != o.Rows[j].Dataset {
+			return o.Rows[i].Dataset < o.Rows[j].Dataset
+		}
+		return o.Rows[i].Operation < o.Rows[j].Operation
+	})
+	return o, nil
+}
+
+// seriesLabels joins the dimensions whose values differ across series, so
+// two Go versions of one SDK are labelled "go1.22.5" and "go1.23.1". Series
+// that agree on every dimension are numbered.
+func seriesLabels(series []OverlaySeries) []string {
+	dims := []func(OverlaySeries) string{
+		func(s OverlaySeries) string { return s.SDKID },
+		func(s OverlaySeries) string { return s.SDKVersion },
+		func(s OverlaySeries) string { return s.RuntimeVersion },
+		func(s OverlaySeries) string { return s.Arch },
+	}
+	labels := make([]string, len(series))
+	for _, dim := range dims {
+		distinct := make(map[string]bool)
+		for _, s := range series {
+			distinct[dim(s)] = true
+		}
+		if len(distinct) < 2 {
+			continue
+		}
+		for i, s := range series {
+			v := dim(s)
+			if v == "" {
+				v = "?"
+			}
+			if labels[i] != "" {
+				labels[i] += " · "
+			}
+			labels[i] += v
+		}
+	}
+	seen := make(map[string]int)
+	for i := range labels {
+		if labels[i] == "" {
+			labels[i] = series[i].SDKID
+		}
+		seen[labels[i]]++
+	}
+	for i, label := range labels {
+		if seen[label] > 1 {
+			labels[i] = label + " #" + strconv.Itoa(i+1)
+		}
+	}
+	return labels
+}
+
+// WriteCSV writes the overlay as one line per dataset, operation and
+// series, leaving out series that lack the pair.
+func (o *Overlay) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"dataset", "operation", "series", "sdk_id", "sdk_version", "runtime_version", "arch",
+		"mean_ns", "median_ns", "stddev_ns", "alloc_bytes_per_op"}); err != nil {
+		return err
+	}
+	for _, row := range o.Rows {
+		for i, cell := range row.Values {
+			if cell == nil {
+				continue
+			}
+			s := o.Series[i]
+			alloc := ""
+			if cell.AllocBytesPerOp != nil {
+				alloc = strconv.FormatInt(*cell.AllocBytesPerOp, 10)
+			}
+			if err := cw.Write([]string{row.Dataset, row.Operation, s.Label, s.SDKID, s.SDKVersion, s.RuntimeVersion, s.Arch,
+				strconv.FormatInt(cell.MeanNs, 10), strconv.FormatInt(cell.MedianNs, 10), strconv.FormatInt(cell.StddevNs, 10), alloc}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func seriesDimensions(series []OverlaySeries) map[string][]string {
+	values := map[string]map[string]bool{"sdk_id": {}, "sdk_version": {}, "runtime_version": {}, "arch": {}}
+	for _, s := range series {
+		values["sdk_id"][s.SDKID] = true
+		values["sdk_version"][s.SDKVersion] = true
+		values["runtime_version"][s.RuntimeVersion] = true
+		values["arch"][s.Arch] = true
+	}
+	dims := make(map[string][]string)
+	for name, set := range values {
+		if len(set) < 2 {
+			continue
+		}
+		for v := range set {
+			dims[name] = append(dims[name], v)
+		}
+		sort.Strings(dims[name])
+	}
+	return dims
+}
@@ -0,0 +1,66 @@
+package compare
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func overlayReport(goVersion string, ops map[string]int64) *report.Report {
+	entries := make(map[string]report.OperationEntry, len(ops))
+	for id, mean := range ops {
+		entries[id] = report.OperationEntry{OperationID: id, MeanNs: mean, MedianNs: mean}
+	}
+	return &report.Report{
+		SDKID:    "aas-core3-golang",
+		Metadata: report.Metadata{RuntimeVersion: goVersion, SDKPackageVersion: "v1.0.7"},
+		Datasets: map[string]report.DatasetEntry{"mixed": {Operations: entries}},
+	}
+}
+
+func TestBuildOverlayLabelsByDifferingDimension(t *testing.T) {
+	reports := []*report.Report{
+		overlayReport("go1.22.5", map[string]int64{"deserialize": 100, "validate": 50}),
+		overlayReport("go1.23.1", map[string]int64{"deserialize": 90}),
+	}
+	o, err := BuildOverlay(reports, []string{"a.json", "b.json"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Series[0].Label != "go1.22.5" || o.Series[1].Label != "go1.23.1" {
+		t.Errorf("labels = %q, %q", o.Series[0].Label, o.Series[1].Label)
+	}
+	if got := o.Dimensions["runtime_version"]; len(got) != 2 || len(o.Dimensions) != 1 {
+		t.Errorf("dimensions = %v, want only runtime_version", o.Dimensions)
+	}
+	if len(o.Rows) != 2 || o.Rows[0].Operation != "deserialize" || o.Rows[1].Operation != "validate" {
+		t.Fatalf("rows = %+v", o.Rows)
+	}
+	if o.Rows[1].Values[0] == nil || o.Rows[1].Values[1] != nil {
+		t.Errorf("validate cells = %+v, want only the first series", o.Rows[1].Values)
+	}
+
+	var buf bytes.Buffer
+	if err := o.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(buf.String()), "\n"); len(lines) != 4 {
+		t.Errorf("csv has %d lines, want header and 3 cells:\n%s", len(lines), buf.String())
+	}
+}
+
+func TestBuildOverlayNumbersIdenticalSeries(t *testing.T) {
+	a := overlayReport("go1.22.5", map[string]int64{"deserialize": 100})
+	o, err := BuildOverlay([]*report.Report{a, a}, []string{"a.json", "b.json"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Series[0].Label != "aas-core3-golang #1" || o.Series[1].Label != "aas-core3-golang #2" {
+		t.Errorf("labels = %q, %q", o.Series[0].Label, o.Series[1].Label)
+	}
+	if _, err := BuildOverlay([]*report.Report{a, a}, []string{"a.json", "b.json"}, []string{"only one"}); err == nil {
+		t.Error("label count mismatch accepted")
+	}
+}
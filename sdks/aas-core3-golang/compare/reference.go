@@ -0,0 +1,187 @@
+package compare
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// ReferenceAsset is the bundle of reference reports, one per SDK at the
+// version the observatory pins, that the nightly run publishes with its
+// checksum in ReferenceAsset+".sha256". It has the format of a dataset
+// bundle (see dataset.Pack), so dataset.Fetcher downloads and verifies it.
+const ReferenceAsset = "aas-reference-reports.tar.gz"
+
+// DefaultReferenceURL is where the nightly run publishes ReferenceAsset.
+const DefaultReferenceURL = "https://hadijannat.github.io/aas-benchmark-observatory/data/" + ReferenceAsset
+
+const (
+	// DefaultBallparkFactor is how many times slower or faster than the
+	// reference a machine may be and still be in the ballpark: hosts
+	// differ, but not by more than that on the same SDK and inputs.
+	DefaultBallparkFactor = 3.0
+	// DefaultNoisyCVPct is the coefficient of variation beyond which an
+	// operation's samples are too spread to compare.
+	DefaultNoisyCVPct = 10.0
+)
+
+// Verdicts of a BallparkCheck and its rows.
+const (
+	BallparkExpected = "expected"
+	BallparkSlower   = "slower"
+	BallparkFaster   = "faster"
+	// BallparkNoisy: the machine is too noisy for its numbers to be useful,
+	// whether or not they are in the ballpark.
+	BallparkNoisy = "noisy"
+)
+
+// BallparkRow compares one operation with the reference. Ratio is the
+// current mean over the reference mean.
+type BallparkRow struct {
+	Dataset     string  `json:"dataset"`
+	Operation   string  `json:"operation"`
+	ReferenceNs int64   `json:"reference_ns"`
+	CurrentNs   int64   `json:"current_ns"`
+	Ratio       float64 `json:"ratio"`
+	// CVPct is the coefficient of variation of the current samples.
+	CVPct   float64 `json:"cv_pct"`
+	Verdict string  `json:"verdict"`
+}
+
+// BallparkCheck tells whether a local run produced numbers in the range
+// the reference report for its SDK leads one to expect, and whether the
+// machine was quiet enough for them to mean anything.
+type BallparkCheck struct {
+	SDKID            string  `json:"sdk_id"`
+	ReferenceVersion string  `json:"reference_version"`
+	CurrentVersion   string  `json:"current_version"`
+	Factor           float64 `json:"factor"`
+	// Ratio is the geometric mean of the rows' ratios: how much slower (>1)
+	// or faster (<1) the machine is than the reference's overall.
+	Ratio float64       `json:"ratio"`
+	Rows  []BallparkRow `json:"rows"`
+	// Noise lists why the machine is too noisy; empty when it is not.
+	Noise []string `json:"noise,omitempty"`
+	// Notes lists differences from the reference that make the check less
+	// conclusive, such as another SDK version or platform.
+	Notes []string `json:"notes,omitempty"`
+	// Verdict is empty when the reports have no operation in common.
+	Verdict string `json:"verdict"`
+}
+
+// FindReference picks the reference for a report of sdkID at version on
+// platform among refs: of the SDK's reports, the one at the same version,
+// on the same platform, and the newest, in that order of preference.
+func FindReference(refs []*report.Report, sdkID, version, platform string) (*report.Report, error) {
+	var best *report.Report
+	bestScore := -1
+	for _, r := range refs {
+		if r.SDKID != sdkID {
+			continue
+		}
+		score := 0
+		if r.Metadata.SDKPackageVersion == version {
+			score += 2
+		}
+		if r.Metadata.PlatformOf() == platform {
+			score++
+		}
+		if score > bestScore || score == bestScore && r.Metadata.Timestamp > best.Metadata.Timestamp {
+			best, bestScore = r, score
+		}
+	}
+	if best == nil {
+		ids := make([]string, 0, len(refs))
+		for _, r := range refs {
+			ids = append(ids, r.SDKID)
+		}
+		sort.Strings(ids)
+		return nil, fmt.Errorf("no reference report for %s (references: %s)", sdkID, strings.Join(ids, ", "))
+	}
+	return best, nil
+}
+
+// Ballpark compares every canonical operation both reports measured. An
+// operation is in the ballpark when it is at most factor times slower or
+// faster than the reference; the machine is when the geometric mean of
+// the ratios is. The machine is noisy when its control benchmark drifted
+// or when more than a quarter of the operations vary by more than
+// noisyCVPct.
+func Ballpark(reference, current *report.Report, factor, noisyCVPct float64) *BallparkCheck {
+	c := &BallparkCheck{
+		SDKID:            current.SDKID,
+		ReferenceVersion: reference.Metadata.SDKPackageVersion,
+		CurrentVersion:   current.Metadata.SDKPackageVersion,
+		Factor:           factor,
+		Rows:             []BallparkRow{},
+	}
+	if c.ReferenceVersion != c.CurrentVersion {
+		c.Notes = append(c.Notes, fmt.Sprintf("reference measured %s %s, this run %s", c.SDKID, c.ReferenceVersion, c.CurrentVersion))
+	}
+	if rp, cp := reference.Metadata.PlatformOf(), current.Metadata.PlatformOf(); rp != cp {
+		c.Notes = append(c.Notes, fmt.Sprintf("reference ran on %s, this run on %s", rp, cp))
+	}
+
+	var logSum float64
+	noisy := 0
+	for _, dsName := range sortedDatasets(current) {
+		refDS, ok := reference.Datasets[dsName]
+		if !ok {
+			continue
+		}
+		curOps, refOps := normalizedOperations(current.Datasets[dsName]), normalizedOperations(refDS)
+		for _, opID := range sortedKeys(curOps) {
+			cur, ref := curOps[opID], refOps[opID]
+			if ns, _ := report.SplitOperationID(opID); ns != "" {
+				continue
+			}
+			if _, ok := refOps[opID]; !ok || !cur.Measured() || !ref.Measured() || cur.MeanNs <= 0 || ref.MeanNs <= 0 {
+				continue
+			}
+			ratio := float64(cur.MeanNs) / float64(ref.MeanNs)
+			row := BallparkRow{
+				Dataset:     dsName,
+				Operation:   opID,
+				ReferenceNs: ref.MeanNs,
+				CurrentNs:   cur.MeanNs,
+				Ratio:       round2(ratio),
+				CVPct:       round2(float64(cur.StddevNs) / float64(cur.MeanNs) * 100),
+				Verdict:     ballparkVerdict(ratio, factor),
+			}
+			if row.CVPct > noisyCVPct {
+				noisy++
+			}
+			logSum += math.Log(ratio)
+			c.Rows = append(c.Rows, row)
+		}
+	}
+
+	if current.EnvironmentNoise == report.NoiseHigh {
+		c.Noise = append(c.Noise, "the control benchmark drifted during the run (environment_noise high)")
+	}
+	if n := len(c.Rows); n > 0 && noisy*4 > n {
+		c.Noise = append(c.Noise, fmt.Sprintf("%d of %d operations vary by more than %.0f%%", noisy, n, noisyCVPct))
+	}
+	if n := len(c.Rows); n > 0 {
+		ratio := math.Exp(logSum / float64(n))
+		c.Ratio = round2(ratio)
+		c.Verdict = ballparkVerdict(ratio, factor)
+	}
+	if len(c.Noise) > 0 {
+		c.Verdict = BallparkNoisy
+	}
+	return c
+}
+
+func ballparkVerdict(ratio, factor float64) string {
+	switch {
+	case ratio > factor:
+		return BallparkSlower
+	case ratio < 1/factor:
+		return BallparkFaster
+	}
+	return BallparkExpected
+}
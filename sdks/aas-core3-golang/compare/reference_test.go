@@ -0,0 +1,86 @@
+package compare
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// referenceReport measures deserialize and validate on mixed at means and
+// a stddev of cvPct percent.
+func referenceReport(version string, deserializeNs, validateNs int64, cvPct int64) *report.Report {
+	op := func(mean int64) report.OperationEntry {
+		return report.OperationEntry{MeanNs: mean, StddevNs: mean * cvPct / 100, SampleCount: 5}
+	}
+	return &report.Report{
+		SDKID:    "aas-core3-golang",
+		Metadata: report.Metadata{SDKPackageVersion: version},
+		Datasets: map[string]report.DatasetEntry{
+			"mixed": {Operations: map[string]report.OperationEntry{
+				"deserialize":    op(deserializeNs),
+				"validate":       op(validateNs),
+				"acme:roundtrip": op(1),
+			}},
+		},
+	}
+}
+
+func TestFindReferencePrefersSameVersionAndPlatform(t *testing.T) {
+	old := referenceReport("v1.0.5", 1, 1, 0)
+	old.Metadata.Timestamp = "2026-01-01T00:00:00Z"
+	pinned := referenceReport("v1.0.6", 1, 1, 0)
+	pinned.Metadata.Timestamp = "2026-02-01T00:00:00Z"
+	arm := referenceReport("v1.0.6", 1, 1, 0)
+	arm.Metadata.Platform = "linux/arm64"
+	refs := []*report.Report{old, pinned, arm}
+
+	if got, err := FindReference(refs, "aas-core3-golang", "v1.0.5", "linux/amd64"); err != nil || got != old {
+		t.Errorf("same version: got %v, %v", got, err)
+	}
+	if got, err := FindReference(refs, "aas-core3-golang", "v1.0.6", "linux/arm64"); err != nil || got != arm {
+		t.Errorf("same platform: got %v, %v", got, err)
+	}
+	if got, err := FindReference(refs, "aas-core3-golang", "v2.0.0", "darwin/arm64"); err != nil || got != pinned {
+		t.Errorf("other version: got %v, %v, want the newest reference", got, err)
+	}
+	if _, err := FindReference(refs, "aas-core3-rust", "", ""); err == nil || !strings.Contains(err.Error(), "aas-core3-golang") {
+		t.Errorf("unknown SDK: err = %v, want one listing the references", err)
+	}
+}
+
+func TestBallparkJudgesTheMachine(t *testing.T) {
+	ref := referenceReport("v1.0.6", 1_000_000, 2_000_000, 2)
+
+	// A somewhat slower machine is not a broken one; one operation far
+	// off is listed but does not sway the verdict.
+	c := Ballpark(ref, referenceReport("v1.0.6", 1_500_000, 10_000_000, 2), DefaultBallparkFactor, DefaultNoisyCVPct)
+	if c.Verdict != BallparkExpected || len(c.Rows) != 2 {
+		t.Fatalf("check = %+v, want expected over 2 rows (extensions are not compared)", c)
+	}
+	if c.Rows[1].Operation != "validate" || c.Rows[1].Ratio != 5 || c.Rows[1].Verdict != BallparkSlower {
+		t.Errorf("validate row = %+v, want ratio 5, slower", c.Rows[1])
+	}
+	if c.Ratio != 2.74 || len(c.Notes) != 0 {
+		t.Errorf("ratio = %v, notes %v, want 2.74 and none", c.Ratio, c.Notes)
+	}
+
+	c = Ballpark(ref, referenceReport("v1.0.7", 10_000_000, 20_000_000, 2), DefaultBallparkFactor, DefaultNoisyCVPct)
+	if c.Verdict != BallparkSlower || len(c.Notes) != 1 {
+		t.Errorf("10x slower: verdict %q, notes %v, want slower with a version note", c.Verdict, c.Notes)
+	}
+}
+
+func TestBallparkFlagsNoisyMachines(t *testing.T) {
+	ref := referenceReport("v1.0.6", 1_000_000, 2_000_000, 2)
+	c := Ballpark(ref, referenceReport("v1.0.6", 1_000_000, 2_000_000, 25), DefaultBallparkFactor, DefaultNoisyCVPct)
+	if c.Verdict != BallparkNoisy || len(c.Noise) != 1 {
+		t.Errorf("spread samples: verdict %q, noise %v, want noisy", c.Verdict, c.Noise)
+	}
+
+	quiet := referenceReport("v1.0.6", 1_000_000, 2_000_000, 2)
+	quiet.EnvironmentNoise = report.NoiseHigh
+	if c := Ballpark(ref, quiet, DefaultBallparkFactor, DefaultNoisyCVPct); c.Verdict != BallparkNoisy {
+		t.Errorf("drifting control benchmark: verdict %q, want noisy", c.Verdict)
+	}
+}
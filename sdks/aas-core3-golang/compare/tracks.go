@@ -0,0 +1,42 @@
+package compare
+
+import "github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+
+// trackOrder is the order tracks are listed in, core first.
+var trackOrder = []string{"core", "xml", "aasx", "validation", "micro", "scaling", "i18n", "capability", "extension"}
+
+// TrackDeltas are the deltas of one dashboard track.
+type TrackDeltas struct {
+	Track        string  `json:"track"`
+	Regressions  int     `json:"regressions"`
+	Improvements int     `json:"improvements"`
+	Deltas       []Delta `json:"deltas"`
+}
+
+// ByTrack groups the deltas by report.InferOperationTrack, keeping their
+// order within a track. Tracks without deltas are left out.
+func (c *Comparison) ByTrack() []TrackDeltas {
+	byName := make(map[string]*TrackDeltas)
+	for _, d := range c.Deltas {
+		name := report.InferOperationTrack(d.Dataset, d.Operation)
+		t := byName[name]
+		if t == nil {
+			t = &TrackDeltas{Track: name}
+			byName[name] = t
+		}
+		t.Deltas = append(t.Deltas, d)
+		switch d.Direction {
+		case Regression:
+			t.Regressions++
+		case Improvement:
+			t.Improvements++
+		}
+	}
+	var tracks []TrackDeltas
+	for _, name := range trackOrder {
+		if t := byName[name]; t != nil {
+			tracks = append(tracks, *t)
+		}
+	}
+	return tracks
+}
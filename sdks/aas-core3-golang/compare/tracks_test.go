@@ -0,0 +1,22 @@
+package compare
+
+import "testing"
+
+func TestByTrackGroupsInTrackOrder(t *testing.T) {
+	c := &Comparison{Deltas: []Delta{
+		{Dataset: "mixed", Operation: "vendorx:transform", Direction: Regression},
+		{Dataset: "mixed", Operation: "deserialize", Direction: Regression},
+		{Dataset: "mixed", Operation: "deserialize_xml", Direction: Unchanged},
+		{Dataset: "mixed", Operation: "serialize", Direction: Improvement},
+	}}
+	tracks := c.ByTrack()
+	if len(tracks) != 3 || tracks[0].Track != "core" || tracks[1].Track != "xml" || tracks[2].Track != "extension" {
+		t.Fatalf("tracks = %+v, want core, xml, extension", tracks)
+	}
+	if core := tracks[0]; len(core.Deltas) != 2 || core.Regressions != 1 || core.Improvements != 1 {
+		t.Errorf("core = %+v", core)
+	}
+	if tracks[1].Regressions != 0 || tracks[2].Regressions != 1 {
+		t.Errorf("xml and extension = %+v, %+v", tracks[1], tracks[2])
+	}
+}
@@ -0,0 +1,143 @@
+package containment
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// cgroupRoot is where the unified cgroup v2 hierarchy is mounted.
+const cgroupRoot = "/sys/fs/cgroup"
+
+// cgroupParent is a cgroup v2 directory with the memory controller enabled
+// for its children.
+type cgroupParent struct {
+	dir string
+}
+
+// openCgroupParent resolves dir, or the current process's cgroup when dir
+// is empty, and makes sure its children get the memory controller.
+func openCgroupParent(dir string) (*cgroupParent, error) {
+	if _, err := os.Stat(filepath.Join(cgroupRoot, "cgroup.controllers")); err != nil {
+		return nil, fmt.Errorf("no cgroup v2 hierarchy at %s", cgroupRoot)
+	}
+	if dir == "" {
+		self, err := os.ReadFile("/proc/self/cgroup")
+		if err != nil {
+			return nil, err
+		}
+		rel, ok := ownCgroup(string(self))
+		if !ok {
+			return nil, fmt.Errorf("cannot find the process's cgroup v2 path")
+		}
+		dir = filepath.Join(cgroupRoot, rel)
+	}
+	control := filepath.Join(dir, "cgroup.subtree_control")
+	enabled, err := os.ReadFile(control)
+	if err != nil {
+		return nil, err
+	}
+	if !hasController(string(enabled), "memory") {
+		if err := os.WriteFile(control, []byte("+memory"), 0); err != nil {
+			return nil, fmt.Errorf("enable the memory controller in %s: %w", dir, err)
+		}
+	}
+	return &cgroupParent{dir: dir}, nil
+}
+
+// ownCgroup returns the cgroup v2 path in a /proc/self/cgroup listing: the
+// line of hierarchy 0 with no controllers, "0::/path".
+func ownCgroup(listing string) (string, bool) {
+	for _, line := range strings.Split(listing, "\n") {
+		if rel, ok := strings.CutPrefix(line, "0::"); ok {
+			return rel, true
+		}
+	}
+	return "", false
+}
+
+func hasController(list, name string) bool {
+	for _, c := range strings.Fields(list) {
+		if c == name {
+			return true
+		}
+	}
+	return false
+}
+
+// run executes cmd in a new child cgroup capped at capBytes without swap,
+// so that the cap cannot be evaded by swapping out, and removes the cgroup
+// afterwards.
+func (p *cgroupParent) run(name string, capBytes int64, cmd *exec.Cmd) (Usage, error) {
+	dir := filepath.Join(p.dir, fmt.Sprintf("aasbench-%d-%s", os.Getpid(), name))
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return Usage{}, fmt.Errorf("create cgroup: %w", err)
+	}
+	defer os.Remove(dir)
+	if err := os.WriteFile(filepath.Join(dir, "memory.max"), []byte(strconv.FormatInt(capBytes, 10)), 0); err != nil {
+		return Usage{}, fmt.Errorf("set memory.max: %w", err)
+	}
+	// memory.swap.max is absent when swap accounting is off.
+	_ = os.WriteFile(filepath.Join(dir, "memory.swap.max"), []byte("0"), 0)
+
+	fd, err := os.Open(dir)
+	if err != nil {
+		return Usage{}, err
+	}
+	defer fd.Close()
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(fd.Fd())
+	runErr := cmd.Run()
+
+	var u Usage
+	if events, err := os.ReadFile(filepath.Join(dir, "memory.events")); err == nil {
+		counts := parseEvents(string(events))
+		u.LimitEvents, u.OOMKills = counts["max"], counts["oom_kill"]
+	}
+	// memory.peak needs Linux 5.19; older kernels fall back to the RSS.
+	if peak, err := os.ReadFile(filepath.Join(dir, "memory.peak")); err == nil {
+		if n, err := strconv.ParseInt(strings.TrimSpace(string(peak)), 10, 64); err == nil {
+			u.PeakBytes = &n
+		}
+	}
+	if u.PeakBytes == nil {
+		u.PeakBytes = peakRSS(cmd)
+	}
+	return u, runErr
+}
+
+// parseEvents reads the "key count" lines of memory.events.
+func parseEvents(events string) map[string]int64 {
+	counts := make(map[string]int64)
+	for _, line := range strings.Split(events, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		if n, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			counts[fields[0]] = n
+		}
+	}
+	return counts
+}
+
+// peakRSS is the maximum resident set size of the exited cmd; Linux
+// reports it in KiB.
+func peakRSS(cmd *exec.Cmd) *int64 {
+	if cmd.ProcessState == nil {
+		return nil
+	}
+	ru, ok := cmd.ProcessState.SysUsage().(*syscall.Rusage)
+	if !ok || ru.Maxrss <= 0 {
+		return nil
+	}
+	n := ru.Maxrss * 1024
+	return &n
+}
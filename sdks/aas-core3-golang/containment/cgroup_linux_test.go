@@ -0,0 +1,16 @@
+package containment
+
+import "testing"
+
+func TestOwnCgroupAndEvents(t *testing.T) {
+	if rel, ok := ownCgroup("12:memory:/old\n0::/user.slice/session-3.scope\n"); !ok || rel != "/user.slice/session-3.scope" {
+		t.Errorf("ownCgroup = %q, %v", rel, ok)
+	}
+	if _, ok := ownCgroup("4:cpu:/docker/abc\n"); ok {
+		t.Error("ownCgroup found a v2 path in a v1-only listing")
+	}
+	counts := parseEvents("low 0\nhigh 0\nmax 17\noom 1\noom_kill 1\n")
+	if counts["max"] != 17 || counts["oom_kill"] != 1 {
+		t.Errorf("parseEvents = %v", counts)
+	}
+}
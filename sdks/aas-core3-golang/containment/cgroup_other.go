@@ -0,0 +1,23 @@
+//go:build !linux
+
+package containment
+
+import (
+	"errors"
+	"os/exec"
+)
+
+type cgroupParent struct{}
+
+func openCgroupParent(dir string) (*cgroupParent, error) {
+	return nil, errors.New("memory cgroups need Linux cgroup v2")
+}
+
+func (p *cgroupParent) run(name string, capBytes int64, cmd *exec.Cmd) (Usage, error) {
+	return Usage{}, errors.New("memory cgroups need Linux cgroup v2")
+}
+
+// peakRSS is unknown off Linux, where rusage units differ by platform.
+func peakRSS(cmd *exec.Cmd) *int64 {
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package containment runs a process under a memory cap and classifies how
+// it ended: completed, thrashed at the cap, or ran out of memory. On Linux
+// with a writable cgroup v2 hierarchy the cap is a memory.max of a child
+// cgroup created for the process. Elsewhere GOMEMLIMIT stands in, and on
+// Linux the outcome is inferred from the process's peak RSS; other systems
+// only tell completion from failure.
+package containment
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// thrashFraction is the share of the cap a GOMEMLIMIT run may peak at
+// before it counts as thrashed: close to the limit, the runtime spends its
+// time collecting rather than running the benchmark.
+const thrashFraction = 0.9
+
+// ParseSize parses a memory size such as 512MiB, 2GiB, 800MB or a plain
+// byte count.
+func ParseSize(s string) (int64, error) {
+	units := []struct {
+		suffix string
+		scale  int64
+	}{
+		{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30},
+		{"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9},
+		{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30},
+		{"B", 1},
+	}
+	num, scale := strings.TrimSpace(s), int64(1)
+	for _, u := range units {
+		if strings.HasSuffix(num, u.suffix) {
+			num, scale = strings.TrimSpace(strings.TrimSuffix(num, u.suffix)), u.scale
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(num, 64)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid memory size %q (want e.g. 512MiB)", s)
+	}
+	return int64(n * float64(scale)), nil
+}
+
+// Usage is what a contained run left behind. PeakBytes is nil when neither
+// the cgroup nor the process reported a peak.
+type Usage struct {
+	PeakBytes   *int64
+	LimitEvents int64
+	OOMKills    int64
+}
+
+// Classify decides the outcome of a run under a cap of capBytes enforced by
+// method, from its usage and the error it exited with.
+func Classify(method string, capBytes int64, u Usage, runErr error) string {
+	overCap := u.PeakBytes != nil && *u.PeakBytes > capBytes
+	nearCap := u.PeakBytes != nil && float64(*u.PeakBytes) > thrashFraction*float64(capBytes)
+	switch {
+	case u.OOMKills > 0:
+		return report.ContainmentOOM
+	case method == report.ContainmentGOMEMLIMIT && overCap:
+		// A hard cap would have killed it, whether or not it finished.
+		return report.ContainmentOOM
+	case runErr != nil:
+		return report.ContainmentFailed
+	case method == report.ContainmentCgroup && u.LimitEvents > 0:
+		return report.ContainmentThrashed
+	case method == report.ContainmentGOMEMLIMIT && nearCap:
+		return report.ContainmentThrashed
+	}
+	return report.ContainmentCompleted
+}
+
+// Runner runs processes under one memory cap.
+type Runner struct {
+	capBytes int64
+	cgroup   *cgroupParent
+}
+
+// New returns a Runner confining processes to capBytes. parent is the
+// cgroup v2 directory to create per-process cgroups under; empty uses the
+// current process's own cgroup. When no cgroup can be used, the Runner
+// falls back to GOMEMLIMIT and reason says why.
+func New(capBytes int64, parent string) (r *Runner, reason string) {
+	cg, err := openCgroupParent(parent)
+	if err != nil {
+		return &Runner{capBytes: capBytes}, err.Error()
+	}
+	return &Runner{capBytes: capBytes, cgroup: cg}, ""
+}
+
+// Method is how the Runner enforces the cap.
+func (r *Runner) Method() string {
+	if r.cgroup != nil {
+		return report.ContainmentCgroup
+	}
+	return report.ContainmentGOMEMLIMIT
+}
+
+// CapBytes is the cap the Runner enforces.
+func (r *Runner) CapBytes() int64 {
+	return r.capBytes
+}
+
+// Run starts cmd under the cap, waits for it and classifies the outcome.
+// The combined output of cmd is captured; its last line is the detail of
+// a run that did not complete.
+func (r *Runner) Run(name string, cmd *exec.Cmd) report.ContainmentGroup {
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	start := time.Now()
+	var u Usage
+	var err error
+	if r.cgroup != nil {
+		u, err = r.cgroup.run(name, r.capBytes, cmd)
+	} else {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, "GOMEMLIMIT="+strconv.FormatInt(r.capBytes, 10))
+		err = cmd.Run()
+		u.PeakBytes = peakRSS(cmd)
+	}
+	g := report.ContainmentGroup{
+		Benchmark:   name,
+		Outcome:     Classify(r.Method(), r.capBytes, u, err),
+		PeakBytes:   u.PeakBytes,
+		LimitEvents: u.LimitEvents,
+		OOMKills:    u.OOMKills,
+		WallMs:      float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if g.Outcome != report.ContainmentCompleted && err != nil {
+		g.Detail = err.Error()
+		if last := lastLine(output.Bytes()); last != "" {
+			g.Detail += ": " + last
+		}
+	}
+	return g
+}
+
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
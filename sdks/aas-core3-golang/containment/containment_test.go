@@ -0,0 +1,74 @@
+package containment
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func TestParseSize(t *testing.T) {
+	for in, want := range map[string]int64{
+		"512MiB":    512 << 20,
+		"2GiB":      2 << 30,
+		"1.5 GiB":   3 << 29,
+		"800MB":     800e6,
+		"64M":       64 << 20,
+		"123456789": 123456789,
+	} {
+		if got, err := ParseSize(in); err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v; want %d", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "MiB", "-1GiB", "lots"} {
+		if _, err := ParseSize(in); err == nil {
+			t.Errorf("ParseSize(%q) accepted", in)
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	const capBytes = 1000
+	peak := func(n int64) *int64 { return &n }
+	failed := errors.New("exit status 2")
+	for _, c := range []struct {
+		name   string
+		method string
+		usage  Usage
+		err    error
+		want   string
+	}{
+		{"cgroup comfortable", report.ContainmentCgroup, Usage{PeakBytes: peak(950)}, nil, report.ContainmentCompleted},
+		{"cgroup reclaimed at the cap", report.ContainmentCgroup, Usage{PeakBytes: peak(1000), LimitEvents: 12}, nil, report.ContainmentThrashed},
+		{"cgroup killed", report.ContainmentCgroup, Usage{PeakBytes: peak(1000), LimitEvents: 40, OOMKills: 1}, failed, report.ContainmentOOM},
+		{"cgroup crashed otherwise", report.ContainmentCgroup, Usage{PeakBytes: peak(10)}, failed, report.ContainmentFailed},
+		{"gomemlimit comfortable", report.ContainmentGOMEMLIMIT, Usage{PeakBytes: peak(500)}, nil, report.ContainmentCompleted},
+		{"gomemlimit near the cap", report.ContainmentGOMEMLIMIT, Usage{PeakBytes: peak(950)}, nil, report.ContainmentThrashed},
+		{"gomemlimit over the cap", report.ContainmentGOMEMLIMIT, Usage{PeakBytes: peak(1500)}, nil, report.ContainmentOOM},
+		{"gomemlimit peak unknown", report.ContainmentGOMEMLIMIT, Usage{}, nil, report.ContainmentCompleted},
+	} {
+		if got := Classify(c.method, capBytes, c.usage, c.err); got != c.want {
+			t.Errorf("%s: Classify = %s, want %s", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRunnerFallsBackToGOMEMLIMIT(t *testing.T) {
+	r, reason := New(1<<30, t.TempDir())
+	if r.Method() != report.ContainmentGOMEMLIMIT || reason == "" {
+		t.Fatalf("method = %s (%q), want the GOMEMLIMIT fallback for a directory that is no cgroup", r.Method(), reason)
+	}
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a POSIX shell")
+	}
+	g := r.Run("env", exec.Command("sh", "-c", `test "$GOMEMLIMIT" = 1073741824`))
+	if g.Outcome != report.ContainmentCompleted || g.Benchmark != "env" {
+		t.Errorf("group = %+v, want env completed with GOMEMLIMIT set", g)
+	}
+	g = r.Run("crash", exec.Command("sh", "-c", "echo boom >&2; exit 3"))
+	if g.Outcome != report.ContainmentFailed || g.Detail != "exit status 3: boom" {
+		t.Errorf("group = %+v, want failed with the last output line", g)
+	}
+}
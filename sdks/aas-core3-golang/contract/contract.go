@@ -0,0 +1,310 @@
+// Package contract is the conformance kit for SDK adapters: the checks a
+// report.json must pass before its adapter is enabled in known-sdks.json.
+// It goes beyond report.Validate, which only guards integrity and naming,
+// to coverage of the core track and to whether the numbers mean what the
+// schema says they mean. The result is a checklist.json next to the report,
+// which the aggregators carry into results.json.
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// ChecklistFile is the checklist written next to a report.
+const ChecklistFile = "checklist.json"
+
+// Version is bumped when a check is added or tightened, so a checklist
+// records which contract it was held to.
+const Version = 1
+
+// Check statuses. A warning does not fail the contract.
+const (
+	Pass = "pass"
+	Warn = "warn"
+	Fail = "fail"
+)
+
+// knownSemantics are the measurement_semantics values consumers understand.
+var knownSemantics = map[string]bool{"mean_ns_per_operation": true}
+
+// knownFailureStates are the failure_state values consumers understand.
+var knownFailureStates = map[string]bool{
+	report.FailureOK:                    true,
+	report.FailureSkippedMissingDataset: true,
+	report.FailureIncomplete:            true,
+}
+
+// throughputTolerance is the relative slack between throughput_ops_per_sec
+// and 1e9 / mean_ns, on top of mean_ns being rounded to whole ns.
+const throughputTolerance = 0.01
+
+// Check is the outcome of one contract check.
+type Check struct {
+	ID          string   `json:"id"`
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Problems    []string `json:"problems,omitempty"`
+}
+
+// Checklist is the schema of checklist.json.
+type Checklist struct {
+	SDKID           string  `json:"sdk_id"`
+	ContractVersion int     `json:"contract_version"`
+	Passed          bool    `json:"passed"`
+	Checks          []Check `json:"checks"`
+}
+
+// Failed returns the IDs of the failed checks.
+func (c *Checklist) Failed() []string {
+	var ids []string
+	for _, check := range c.Checks {
+		if check.Status == Fail {
+			ids = append(ids, check.ID)
+		}
+	}
+	return ids
+}
+
+// definition is a check and the function that finds its problems; warnOnly
+// checks report problems as warnings.
+type definition struct {
+	id, description string
+	warnOnly        bool
+	run             func(rep *report.Report) []string
+}
+
+var definitions = []definition{
+	{"core_coverage", "every core operation is reported on every core dataset, measured or skipped with a reason", false, checkCoverage},
+	{"units", "timings are non-negative whole nanoseconds, ordered min <= median, mean <= max, with a matching throughput", false, checkUnits},
+	{"semantics", "measurement_semantics, failure_state and operation_track use the shared vocabulary", false, checkSemantics},
+	{"samples", "measured operations have at least two samples, so regressions can be tested", false, checkSamples},
+	{"metadata", "metadata names the runtime and SDK package version", true, checkMetadata},
+	{"dataset_sizes", "core datasets record their file size and element count", true, checkDatasetSizes},
+}
+
+// Run holds the report in data to the contract. The integrity check comes
+// first; when the report does not even load, the other checks fail with it.
+func Run(data []byte) *Checklist {
+	cl := &Checklist{ContractVersion: Version}
+	integrity := Check{ID: "integrity", Description: "the report passes aasbench validate", Status: Pass}
+	integrity.Problems = report.Validate(data)
+	rep, err := loadReport(data)
+	if err != nil {
+		integrity.Problems = append(integrity.Problems, err.Error())
+	}
+	if len(integrity.Problems) > 0 {
+		integrity.Status = Fail
+	}
+	cl.Checks = append(cl.Checks, integrity)
+
+	for _, d := range definitions {
+		check := Check{ID: d.id, Description: d.description, Status: Pass}
+		switch {
+		case rep == nil:
+			check.Status, check.Problems = Fail, []string{"report does not load"}
+		default:
+			if check.Problems = d.run(rep); len(check.Problems) > 0 {
+				check.Status = Fail
+				if d.warnOnly {
+					check.Status = Warn
+				}
+			}
+		}
+		cl.Checks = append(cl.Checks, check)
+	}
+	if rep != nil {
+		cl.SDKID = rep.SDKID
+	}
+	cl.Passed = len(cl.Failed()) == 0
+	return cl
+}
+
+// Test fails t for every failed check of the report at path, so a Go
+// adapter can hold its own output to the contract in go test. Warnings are
+// logged.
+func Test(t testing.TB, path string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, check := range Run(data).Checks {
+		for _, p := range check.Problems {
+			if check.Status == Fail {
+				t.Errorf("%s: %s", check.ID, p)
+			} else {
+				t.Logf("%s (warning): %s", check.ID, p)
+			}
+		}
+	}
+}
+
+func loadReport(data []byte) (*report.Report, error) {
+	var rep report.Report
+	if err := json.Unmarshal(data, &rep); err != nil {
+		return nil, fmt.Errorf("report does not decode: %v", err)
+	}
+	return &rep, nil
+}
+
+func checkCoverage(rep *report.Report) []string {
+	var problems []string
+	for _, ds := range sortedNames(report.CoreDatasets) {
+		for _, op := range sortedNames(report.CoreOperations) {
+			entry, ok := rep.Datasets[ds].Operations[op]
+			switch {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("%s/%s is missing", ds, op))
+			case !entry.Measured() && entry.SkipReason == "":
+				problems = append(problems, fmt.Sprintf("%s/%s is %s without a skip_reason", ds, op, entry.FailureState))
+			}
+		}
+	}
+	return problems
+}
+
+func checkUnits(rep *report.Report) []string {
+	var problems []string
+	eachMeasured(rep, func(key string, op report.OperationEntry) {
+		timings := []struct {
+			name string
+			ns   int64
+		}{{"mean_ns", op.MeanNs}, {"median_ns", op.MedianNs}, {"stddev_ns", op.StddevNs}, {"min_ns", op.MinNs}, {"max_ns", op.MaxNs}}
+		for _, v := range timings {
+			if v.ns < 0 {
+				problems = append(problems, fmt.Sprintf("%s: %s is negative (%d)", key, v.name, v.ns))
+			}
+		}
+		if op.MeanNs == 0 {
+			problems = append(problems, fmt.Sprintf("%s: mean_ns is 0 for a measured operation", key))
+			return
+		}
+		// Adapters without a min/max leave them 0.
+		if op.MaxNs > 0 && (op.MinNs > op.MedianNs || op.MedianNs > op.MaxNs || op.MinNs > op.MeanNs || op.MeanNs > op.MaxNs) {
+			problems = append(problems, fmt.Sprintf("%s: min %d, median %d, mean %d, max %d are out of order",
+				key, op.MinNs, op.MedianNs, op.MeanNs, op.MaxNs))
+		}
+		if op.P75Ns != nil && op.P99Ns != nil && *op.P75Ns > *op.P99Ns {
+			problems = append(problems, fmt.Sprintf("%s: p75_ns %d exceeds p99_ns %d", key, *op.P75Ns, *op.P99Ns))
+		}
+		// The throughput may come from the unrounded mean.
+		lo := 1e9 / float64(op.MeanNs+1) * (1 - throughputTolerance)
+		hi := 1e9 / math.Max(float64(op.MeanNs)-1, 0.5) * (1 + throughputTolerance)
+		if op.ThroughputOpsPerSec < lo || op.ThroughputOpsPerSec > hi {
+			problems = append(problems, fmt.Sprintf("%s: throughput_ops_per_sec %.4g does not match 1e9/mean_ns = %.4g (is mean_ns in ns?)",
+				key, op.ThroughputOpsPerSec, 1e9/float64(op.MeanNs)))
+		}
+		if b := op.Memory.AllocBytesPerOp; b != nil && *b < 0 {
+			problems = append(problems, fmt.Sprintf("%s: memory.alloc_bytes_per_op is negative", key))
+		}
+	})
+	return problems
+}
+
+func checkSemantics(rep *report.Report) []string {
+	var problems []string
+	for _, ds := range sortedDatasets(rep) {
+		for _, id := range sortedOperations(rep.Datasets[ds]) {
+			op := rep.Datasets[ds].Operations[id]
+			key := ds + "/" + id
+			if !knownSemantics[op.MeasurementSemantics] {
+				problems = append(problems, fmt.Sprintf("%s: unknown measurement_semantics %q", key, op.MeasurementSemantics))
+			}
+			if !knownFailureStates[op.FailureState] {
+				problems = append(problems, fmt.Sprintf("%s: unknown failure_state %q", key, op.FailureState))
+			}
+			if want := report.InferOperationTrack(ds, id); op.OperationTrack != want {
+				problems = append(problems, fmt.Sprintf("%s: operation_track %q, want %q", key, op.OperationTrack, want))
+			}
+		}
+	}
+	return problems
+}
+
+func checkSamples(rep *report.Report) []string {
+	var problems []string
+	eachMeasured(rep, func(key string, op report.OperationEntry) {
+		if op.SampleCount < 2 {
+			problems = append(problems, fmt.Sprintf("%s: sample_count %d", key, op.SampleCount))
+		}
+	})
+	return problems
+}
+
+func checkMetadata(rep *report.Report) []string {
+	var problems []string
+	m := rep.Metadata
+	for name, v := range map[string]string{"language": m.Language, "runtime_version": m.RuntimeVersion, "benchmark_harness": m.BenchmarkHarness} {
+		if v == "" {
+			problems = append(problems, "metadata."+name+" is empty")
+		}
+	}
+	if m.SDKPackageVersion == "" || m.SDKPackageVersion == "unknown" {
+		problems = append(problems, "metadata.sdk_package_version is unknown")
+	}
+	sort.Strings(problems)
+	return problems
+}
+
+func checkDatasetSizes(rep *report.Report) []string {
+	var problems []string
+	for _, name := range sortedNames(report.CoreDatasets) {
+		ds, ok := rep.Datasets[name]
+		if !ok {
+			continue
+		}
+		if ds.FileSizeBytes == nil {
+			problems = append(problems, name+": file_size_bytes is null")
+		}
+		if ds.ElementCount == nil {
+			problems = append(problems, name+": element_count is null")
+		}
+	}
+	return problems
+}
+
+// eachMeasured calls fn for the measured operations in dataset and
+// operation order, keyed "<dataset>/<operation>".
+func eachMeasured(rep *report.Report, fn func(key string, op report.OperationEntry)) {
+	for _, ds := range sortedDatasets(rep) {
+		for _, id := range sortedOperations(rep.Datasets[ds]) {
+			if op := rep.Datasets[ds].Operations[id]; op.Measured() {
+				fn(ds+"/"+id, op)
+			}
+		}
+	}
+}
+
+func sortedDatasets(rep *report.Report) []string {
+	names := make([]string, 0, len(rep.Datasets))
+	for name := range rep.Datasets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedOperations(ds report.DatasetEntry) []string {
+	names := make([]string, 0, len(ds.Operations))
+	for name := range ds.Operations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,111 @@
+package contract
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// conformingReport covers every core operation on every core dataset.
+func conformingReport() *report.Report {
+	size, count := int64(2048), int64(120)
+	rep := &report.Report{
+		SchemaVersion: report.SchemaVersion,
+		SDKID:         "aas-core3-example",
+		Metadata: report.Metadata{
+			Language:          "example",
+			RuntimeVersion:    "1.0",
+			SDKPackageVersion: "v1.2.3",
+			BenchmarkHarness:  "example-bench",
+			Timestamp:         "2026-10-01T03:00:00Z",
+		},
+		Datasets: map[string]report.DatasetEntry{},
+	}
+	for ds := range report.CoreDatasets {
+		entry := report.DatasetEntry{FileSizeBytes: &size, ElementCount: &count, Operations: map[string]report.OperationEntry{}}
+		for op := range report.CoreOperations {
+			entry.Operations[op] = report.OperationEntry{
+				OperationID:          op,
+				OperationTrack:       report.InferOperationTrack(ds, op),
+				SampleCount:          6,
+				MeasurementSemantics: "mean_ns_per_operation",
+				FailureState:         report.FailureOK,
+				MeanNs:               2000,
+				MedianNs:             1990,
+				MinNs:                1900,
+				MaxNs:                2200,
+				ThroughputOpsPerSec:  500000,
+			}
+		}
+		rep.Datasets[ds] = entry
+	}
+	return rep
+}
+
+func run(t *testing.T, rep *report.Report) *Checklist {
+	t.Helper()
+	if err := report.SetSchemaVersion(rep, report.SchemaVersion); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(rep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return Run(data)
+}
+
+func statuses(cl *Checklist) map[string]string {
+	m := map[string]string{}
+	for _, check := range cl.Checks {
+		m[check.ID] = check.Status
+	}
+	return m
+}
+
+func TestRunConformingReportPasses(t *testing.T) {
+	cl := run(t, conformingReport())
+	if !cl.Passed || cl.SDKID != "aas-core3-example" || cl.ContractVersion != Version {
+		t.Fatalf("checklist = %+v, want a passed checklist for aas-core3-example", cl)
+	}
+	for _, check := range cl.Checks {
+		if check.Status != Pass {
+			t.Errorf("%s = %s %v, want pass", check.ID, check.Status, check.Problems)
+		}
+	}
+}
+
+func TestRunFindsContractViolations(t *testing.T) {
+	rep := conformingReport()
+	delete(rep.Datasets["deep"].Operations, "update")
+	op := rep.Datasets["wide"].Operations["serialize"]
+	op.ThroughputOpsPerSec = 0.0005 // mean reported in ms
+	op.SampleCount = 1
+	rep.Datasets["wide"].Operations["serialize"] = op
+	rep.Metadata.SDKPackageVersion = "unknown"
+
+	cl := run(t, rep)
+	got := statuses(cl)
+	want := map[string]string{"integrity": Pass, "core_coverage": Fail, "units": Fail, "semantics": Pass, "samples": Fail, "metadata": Warn, "dataset_sizes": Pass}
+	for id, status := range want {
+		if got[id] != status {
+			t.Errorf("%s = %s, want %s", id, got[id], status)
+		}
+	}
+	if cl.Passed || strings.Join(cl.Failed(), ",") != "core_coverage,units,samples" {
+		t.Errorf("passed = %v, failed = %v", cl.Passed, cl.Failed())
+	}
+}
+
+func TestRunUnloadableReportFailsEveryCheck(t *testing.T) {
+	cl := Run([]byte(`{"datasets": []}`))
+	if cl.Passed {
+		t.Fatal("an unloadable report passed the contract")
+	}
+	for _, check := range cl.Checks {
+		if check.Status != Fail {
+			t.Errorf("%s = %s, want fail", check.ID, check.Status)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// Control workload sizing: each sample is the fastest of controlRepeats
+// runs of controlIterations xorshift steps, a few milliseconds in total.
+// Taking the fastest filters a single preemption; sustained slowdowns from
+// noisy neighbors or throttling still show.
+const (
+	controlIterations = 1 << 21
+	controlRepeats    = 5
+)
+
+// controlSample is one timing of the control workload.
+type controlSample struct {
+	Before string    `json:"before"`
+	At     time.Time `json:"at"`
+	Ns     int64     `json:"ns"`
+}
+
+// controlFile is the schema of control.json.
+type controlFile struct {
+	Workload   string          `json:"workload"`
+	Iterations int             `json:"iterations"`
+	Samples    []controlSample `json:"samples"`
+}
+
+// controlRecorder times a fixed CPU-bound loop before every observed
+// sub-benchmark. The loop does not depend on the SDK, so its drift across
+// the run measures the host, not the code under test. Disabled with
+// CONTROL_BENCHMARK=0.
+type controlRecorder struct {
+	enabled bool
+	mu      sync.Mutex
+	samples []controlSample
+}
+
+var globalControl = &controlRecorder{enabled: os.Getenv("CONTROL_BENCHMARK") != "0"}
+
+// controlSink keeps the compiler from eliminating the loop.
+var controlSink uint64
+
+func controlLoop() uint64 {
+	x := uint64(88172645463325252)
+	for i := 0; i < controlIterations; i++ {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+	}
+	return x
+}
+
+// sample records the control timing taken before the named sub-benchmark.
+func (c *controlRecorder) sample(before string) {
+	if !c.enabled {
+		return
+	}
+	best := time.Duration(0)
+	for i := 0; i < controlRepeats; i++ {
+		start := time.Now()
+		controlSink += controlLoop()
+		if d := time.Since(start); best == 0 || d < best {
+			best = d
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.samples = append(c.samples, controlSample{Before: before, At: time.Now().UTC(), Ns: best.Nanoseconds()})
+}
+
+func (c *controlRecorder) snapshot() controlFile {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return controlFile{
+		Workload:   "xorshift64",
+		Iterations: controlIterations,
+		Samples:    append([]controlSample(nil), c.samples...),
+	}
+}
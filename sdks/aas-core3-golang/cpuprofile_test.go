@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// cpuProfiler writes a CPU profile of every observed sub-benchmark to
+// OUTPUT_DIR/<operation>.<dataset>.cpu.pprof, which aasbench run renders
+// into flamegraphs. Enabled with CPU_PROFILE=1. Sampling costs a little
+// CPU time of its own, so timings from a profiled run are slightly high.
+type cpuProfiler struct {
+	enabled bool
+}
+
+var globalCPU = &cpuProfiler{enabled: os.Getenv("CPU_PROFILE") != ""}
+
+// profile runs fn, the sub-benchmark of operation on dataset, under the
+// CPU profiler.
+func (c *cpuProfiler) profile(operation, dataset string, fn func()) {
+	outputDir := os.Getenv("OUTPUT_DIR")
+	if !c.enabled || outputDir == "" {
+		fn()
+		return
+	}
+	var buf bytes.Buffer
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		slog.Warn("failed to start CPU profile", "operation", operation, "dataset", dataset, "err", err)
+		fn()
+		return
+	}
+	fn()
+	pprof.StopCPUProfile()
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		slog.Warn("failed to create output dir", "err", err)
+		return
+	}
+	path := filepath.Join(outputDir, operation+"."+dataset+report.CPUProfileSuffix)
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		slog.Warn("failed to write CPU profile", "path", path, "err", err)
+	}
+}
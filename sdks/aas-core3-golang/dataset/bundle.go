@@ -0,0 +1,302 @@
+package dataset
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// BundleAsset is the name of the dataset bundle attached to every
+// observatory release, with its checksum in BundleAsset+".sha256".
+const BundleAsset = "aas-datasets.tar.gz"
+
+// BundleManifest is the datasets manifest inside a bundle, listing the
+// fingerprint of every file it holds.
+const BundleManifest = "manifest.json"
+
+// DefaultRepository is the GitHub repository whose releases carry the
+// dataset bundles.
+const DefaultRepository = "hadijannat/aas-benchmark-observatory"
+
+// ReleaseURL is the download URL of the bundle attached to release tag of
+// repo ("owner/name"), or to its latest release when tag is empty or
+// "latest".
+func ReleaseURL(repo, tag string) string {
+	if tag == "" || tag == "latest" {
+		return fmt.Sprintf("https://github.com/%s/releases/latest/download/%s", repo, BundleAsset)
+	}
+	return fmt.Sprintf("https://github.com/%s/releases/download/%s/%s", repo, tag, BundleAsset)
+}
+
+// Pack writes the dataset files in dir, as Manifest finds them, to w as a
+// gzipped tar with their manifest in BundleManifest, and returns the
+// manifest.
+func Pack(dir string, w io.Writer) ([]report.DatasetFingerprint, error) {
+	manifest, err := Manifest(dir, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(manifest) == 0 {
+		return nil, fmt.Errorf("no dataset files in %s", dir)
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	// A fixed modification time keeps the bundle of identical files
+	// byte-identical, and so its checksum.
+	mtime := time.Unix(0, 0)
+	hdr := &tar.Header{Name: BundleManifest, Mode: 0644, Size: int64(len(manifestJSON)), ModTime: mtime}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(manifestJSON); err != nil {
+		return nil, err
+	}
+	for _, fp := range manifest {
+		if err := packFile(tw, filepath.Join(dir, fp.File), fp, mtime); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, gz.Close()
+}
+
+func packFile(tw *tar.Writer, path string, fp report.DatasetFingerprint, mtime time.Time) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	hdr := &tar.Header{Name: fp.File, Mode: 0644, Size: fp.SizeBytes, ModTime: mtime}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := io.Copy(tw, f); err != nil {
+		return fmt.Errorf("pack %s: %w", path, err)
+	}
+	return nil
+}
+
+// Fetcher downloads dataset bundles into a cache directory, one
+// subdirectory per bundle checksum, so a bundle is only downloaded and
+// unpacked once.
+type Fetcher struct {
+	CacheDir string
+	HTTP     *http.Client
+}
+
+// DefaultCacheDir is the user cache directory's aasbench/datasets.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "aasbench", "datasets"), nil
+}
+
+// Fetch returns the directory holding the unpacked bundle at url. The
+// bundle must hash to wantSHA256, or, when that is empty, to the checksum
+// published at url+".sha256". Every unpacked file must match the
+// fingerprint the bundle's manifest gives it. cached reports that the
+// bundle was already in the cache.
+func (f *Fetcher) Fetch(ctx context.Context, url, wantSHA256 string) (dir string, cached bool, err error) {
+	if wantSHA256 == "" {
+		if wantSHA256, err = f.checksum(ctx, url+".sha256"); err != nil {
+			return "", false, err
+		}
+	}
+	wantSHA256 = strings.ToLower(wantSHA256)
+	if len(wantSHA256) != sha256.Size*2 {
+		return "", false, fmt.Errorf("invalid sha256 %q", wantSHA256)
+	}
+	dir = filepath.Join(f.CacheDir, wantSHA256)
+	if _, err := os.Stat(filepath.Join(dir, BundleManifest)); err == nil {
+		return dir, true, nil
+	}
+	if err := os.MkdirAll(f.CacheDir, 0755); err != nil {
+		return "", false, err
+	}
+	tmp, err := os.MkdirTemp(f.CacheDir, ".fetch-")
+	if err != nil {
+		return "", false, err
+	}
+	defer os.RemoveAll(tmp)
+
+	archive := filepath.Join(tmp, BundleAsset)
+	got, err := f.download(ctx, url, archive)
+	if err != nil {
+		return "", false, err
+	}
+	if got != wantSHA256 {
+		return "", false, fmt.Errorf("%s: sha256 %s, want %s", url, got, wantSHA256)
+	}
+	unpacked := filepath.Join(tmp, "datasets")
+	if err := unpack(archive, unpacked); err != nil {
+		return "", false, fmt.Errorf("unpack %s: %w", url, err)
+	}
+	if err := verifyBundle(unpacked); err != nil {
+		return "", false, fmt.Errorf("%s: %w", url, err)
+	}
+	if err := os.Rename(unpacked, dir); err != nil {
+		// A concurrent fetch of the same bundle got there first.
+		if _, statErr := os.Stat(filepath.Join(dir, BundleManifest)); statErr == nil {
+			return dir, true, nil
+		}
+		return "", false, err
+	}
+	return dir, false, nil
+}
+
+func (f *Fetcher) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	client := f.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp, nil
+}
+
+// checksum reads a sha256sum-style checksum file and returns its first
+// hash.
+func (f *Fetcher) checksum(ctx context.Context, url string) (string, error) {
+	resp, err := f.get(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("%s is empty", url)
+	}
+	return fields[0], nil
+}
+
+// download writes url to path and returns its sha256.
+func (f *Fetcher) download(ctx context.Context, url, path string) (string, error) {
+	resp, err := f.get(ctx, url)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	out, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(out, h), resp.Body); err != nil {
+		out.Close()
+		return "", fmt.Errorf("download %s: %w", url, err)
+	}
+	if err := out.Close(); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// unpack extracts a bundle into dir. Bundles are flat, so any entry that
+// is not a plain file name is refused rather than written outside dir.
+func unpack(archive, dir string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	if err := os.Mkdir(dir, 0755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg || hdr.Name != filepath.Base(hdr.Name) || hdr.Name == "." || hdr.Name == ".." {
+			return fmt.Errorf("unexpected entry %q", hdr.Name)
+		}
+		out, err := os.OpenFile(filepath.Join(dir, hdr.Name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, tr); err != nil {
+			out.Close()
+			return err
+		}
+		if err := out.Close(); err != nil {
+			return err
+		}
+	}
+}
+
+// verifyBundle checks the files in dir against its BundleManifest: every
+// listed file must be there with its sha256, and no other file may be.
+func verifyBundle(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, BundleManifest))
+	if err != nil {
+		return fmt.Errorf("bundle has no %s", BundleManifest)
+	}
+	var manifest []report.DatasetFingerprint
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return fmt.Errorf("parse %s: %w", BundleManifest, err)
+	}
+	listed := map[string]bool{BundleManifest: true}
+	for _, want := range manifest {
+		listed[want.File] = true
+		got, err := Fingerprint(filepath.Join(dir, want.File))
+		if err != nil {
+			return fmt.Errorf("bundle file %s: %w", want.File, err)
+		}
+		if got.SHA256 != want.SHA256 {
+			return fmt.Errorf("bundle file %s: sha256 %s, manifest has %s", want.File, got.SHA256, want.SHA256)
+		}
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !listed[e.Name()] {
+			return fmt.Errorf("bundle file %s is not in %s", e.Name(), BundleManifest)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package dataset
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// serveBundle packs a dataset directory holding mixed.json and serves it
+// with its checksum file.
+func serveBundle(t *testing.T) (*httptest.Server, []byte) {
+	t.Helper()
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "mixed.json"), []byte(envJSON), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var bundle bytes.Buffer
+	if _, err := Pack(src, &bundle); err != nil {
+		t.Fatal(err)
+	}
+	return serve(t, bundle.Bytes()), bundle.Bytes()
+}
+
+func serve(t *testing.T, bundle []byte) *httptest.Server {
+	sum := sha256.Sum256(bundle)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/" + BundleAsset:
+			w.Write(bundle)
+		case "/" + BundleAsset + ".sha256":
+			w.Write([]byte(hex.EncodeToString(sum[:]) + "  " + BundleAsset + "\n"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestFetchVerifiesAndCaches(t *testing.T) {
+	srv, _ := serveBundle(t)
+	f := &Fetcher{CacheDir: t.TempDir(), HTTP: srv.Client()}
+	dir, cached, err := f.Fetch(context.Background(), srv.URL+"/"+BundleAsset, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached {
+		t.Error("first fetch was reported as cached")
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "mixed.json"))
+	if err != nil || string(data) != envJSON {
+		t.Fatalf("mixed.json = %q, %v", data, err)
+	}
+	srv.Close()
+	again, cached, err := f.Fetch(context.Background(), srv.URL+"/"+BundleAsset, filepath.Base(dir))
+	if err != nil || !cached || again != dir {
+		t.Errorf("second fetch = %s, cached %v, %v; want %s from the cache", again, cached, err, dir)
+	}
+}
+
+func TestFetchRejectsChecksumMismatch(t *testing.T) {
+	srv, _ := serveBundle(t)
+	f := &Fetcher{CacheDir: t.TempDir(), HTTP: srv.Client()}
+	_, _, err := f.Fetch(context.Background(), srv.URL+"/"+BundleAsset, strings.Repeat("0", 64))
+	if err == nil || !strings.Contains(err.Error(), "sha256") {
+		t.Fatalf("err = %v, want a sha256 mismatch", err)
+	}
+	if entries, _ := os.ReadDir(f.CacheDir); len(entries) != 0 {
+		t.Errorf("a rejected bundle left %d cache entries", len(entries))
+	}
+}
+
+func TestFetchRejectsEscapingEntries(t *testing.T) {
+	var bundle bytes.Buffer
+	gz := gzip.NewWriter(&bundle)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "../evil.json", Mode: 0644, Size: 2})
+	tw.Write([]byte("{}"))
+	tw.Close()
+	gz.Close()
+	srv := serve(t, bundle.Bytes())
+	f := &Fetcher{CacheDir: t.TempDir(), HTTP: srv.Client()}
+	if _, _, err := f.Fetch(context.Background(), srv.URL+"/"+BundleAsset, ""); err == nil || !strings.Contains(err.Error(), "unexpected entry") {
+		t.Fatalf("err = %v, want the entry refused", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(f.CacheDir), "evil.json")); err == nil {
+		t.Error("the entry was written outside the cache")
+	}
+}
+
+func TestReleaseURL(t *testing.T) {
+	if got := ReleaseURL("o/r", "latest"); got != "https://github.com/o/r/releases/latest/download/"+BundleAsset {
+		t.Errorf("latest = %s", got)
+	}
+	if got := ReleaseURL("o/r", "v1.2.0"); got != "https://github.com/o/r/releases/download/v1.2.0/"+BundleAsset {
+		t.Errorf("v1.2.0 = %s", got)
+	}
+}
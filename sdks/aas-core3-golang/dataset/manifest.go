@@ -0,0 +1,257 @@
+// Package dataset fingerprints benchmark dataset files so that two reports
+// can be proven to have consumed identical inputs, and packs them into the
+// checksummed bundles releases carry for adapters to fetch.
+package dataset
+
+import (
+	"archive/zip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Extensions are the dataset file formats the manifest covers.
+var Extensions = []string{".json", ".xml", ".aasx"}
+
+// modelTypes are the AAS classes counted as elements. JSON carries them as
+// "modelType" values and XML as lowerCamel element names, so the same
+// environment yields the same count in either serialization.
+var modelTypes = map[string]bool{
+	"AssetAdministrationShell":     true,
+	"Submodel":                     true,
+	"ConceptDescription":           true,
+	"Property":                     true,
+	"MultiLanguageProperty":        true,
+	"Range":                        true,
+	"Blob":                         true,
+	"File":                         true,
+	"ReferenceElement":             true,
+	"RelationshipElement":          true,
+	"AnnotatedRelationshipElement": true,
+	"Entity":                       true,
+	"BasicEventElement":            true,
+	"Operation":                    true,
+	"Capability":                   true,
+	"SubmodelElementCollection":    true,
+	"SubmodelElementList":          true,
+}
+
+// Name extracts the dataset name from a file path ("wide" from "/d/wide.json").
+func Name(path string) string {
+	base := filepath.Base(path)
+	return strings.TrimSuffix(base, filepath.Ext(base))
+}
+
+// Manifest fingerprints every dataset file in dir whose name is in names.
+// A nil names set includes every file. Entries are sorted by file name.
+func Manifest(dir string, names map[string]bool) ([]report.DatasetFingerprint, error) {
+	var files []string
+	for _, ext := range Extensions {
+		matches, err := filepath.Glob(filepath.Join(dir, "*"+ext))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	manifest := []report.DatasetFingerprint{}
+	for _, f := range files {
+		if names != nil && !names[Name(f)] {
+			continue
+		}
+		fp, err := Fingerprint(f)
+		if err != nil {
+			return nil, err
+		}
+		manifest = append(manifest, fp)
+	}
+	return manifest, nil
+}
+
+// Fingerprint hashes one dataset file and counts its AAS elements. The
+// element count is left nil when the format has no countable environment.
+func Fingerprint(path string) (report.DatasetFingerprint, error) {
+	fp := report.DatasetFingerprint{
+		Dataset: Name(path),
+		File:    filepath.Base(path),
+		Format:  strings.TrimPrefix(filepath.Ext(path), "."),
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fp, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return fp, fmt.Errorf("hash %s: %w", path, err)
+	}
+	fp.SHA256 = hex.EncodeToString(h.Sum(nil))
+	fp.SizeBytes = size
+
+	count, err := countElements(path)
+	if err != nil {
+		return fp, fmt.Errorf("count elements in %s: %w", path, err)
+	}
+	fp.ElementCount = count
+	return fp, nil
+}
+
+func countElements(path string) (*int64, error) {
+	var (
+		n   int64
+		err error
+	)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		n, err = countFile(path, countJSON)
+	case ".xml":
+		n, err = countFile(path, countXML)
+	case ".aasx":
+		return countAASX(path)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func countFile(path string, count func(io.Reader) (int64, error)) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	return count(f)
+}
+
+// jsonFrame tracks an open JSON container while streaming tokens.
+type jsonFrame struct {
+	object  bool
+	wantKey bool   // object: the next scalar token is a key
+	key     string // object: key of the value being read
+}
+
+// countJSON streams the document and counts "modelType" values naming an
+// element class, without materializing the whole environment.
+func countJSON(r io.Reader) (int64, error) {
+	dec := json.NewDecoder(r)
+	var (
+		n     int64
+		stack []jsonFrame
+	)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = &stack[len(stack)-1]
+		}
+
+		if d, ok := tok.(json.Delim); ok {
+			switch d {
+			case '{', '[':
+				if top != nil && top.object {
+					top.wantKey = true // the container is the pending value
+				}
+				stack = append(stack, jsonFrame{object: d == '{', wantKey: d == '{'})
+			case '}', ']':
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		if top == nil || !top.object {
+			continue
+		}
+		if top.wantKey {
+			top.key, _ = tok.(string)
+			top.wantKey = false
+			continue
+		}
+		if v, ok := tok.(string); ok && top.key == "modelType" && modelTypes[v] {
+			n++
+		}
+		top.wantKey = true
+	}
+}
+
+// countXML counts start elements whose name is a lowerCamel element class.
+func countXML(r io.Reader) (int64, error) {
+	dec := xml.NewDecoder(r)
+	var n int64
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		if se, ok := tok.(xml.StartElement); ok && modelTypes[upperFirst(se.Name.Local)] {
+			n++
+		}
+	}
+}
+
+// countAASX counts the elements of the environment inside an AASX package.
+func countAASX(path string) (*int64, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	for _, zf := range zr.File {
+		if !strings.HasPrefix(zf.Name, "aasx/") || strings.Contains(zf.Name, "supplementary") {
+			continue
+		}
+		var count func(io.Reader) (int64, error)
+		switch strings.ToLower(filepath.Ext(zf.Name)) {
+		case ".json":
+			count = countJSON
+		case ".xml":
+			count = countXML
+		default:
+			continue
+		}
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		n, err := count(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		return &n, nil
+	}
+	return nil, nil
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,54 @@
+package dataset
+
+import (
+	"strings"
+	"testing"
+)
+
+const envJSON = `{
+  "submodels": [
+    {
+      "id": "urn:sm",
+      "modelType": "Submodel",
+      "submodelElements": [
+        {"idShort": "p", "modelType": "Property", "valueType": "xs:string", "value": "modelType"},
+        {
+          "idShort": "c",
+          "modelType": "SubmodelElementCollection",
+          "value": [{"idShort": "q", "modelType": "Property", "valueType": "xs:int"}],
+          "semanticId": {"type": "ExternalReference", "keys": [{"type": "GlobalReference", "value": "Submodel"}]}
+        }
+      ]
+    }
+  ]
+}`
+
+const envXML = `<environment xmlns="https://admin-shell.io/aas/3/0">
+  <submodels>
+    <submodel>
+      <id>urn:sm</id>
+      <submodelElements>
+        <property><idShort>p</idShort><valueType>xs:string</valueType><value>modelType</value></property>
+        <submodelElementCollection>
+          <idShort>c</idShort>
+          <semanticId><type>ExternalReference</type><keys><key><type>GlobalReference</type><value>Submodel</value></key></keys></semanticId>
+          <value><property><idShort>q</idShort><valueType>xs:int</valueType></property></value>
+        </submodelElementCollection>
+      </submodelElements>
+    </submodel>
+  </submodels>
+</environment>`
+
+func TestElementCountMatchesAcrossFormats(t *testing.T) {
+	fromJSON, err := countJSON(strings.NewReader(envJSON))
+	if err != nil {
+		t.Fatalf("countJSON: %v", err)
+	}
+	fromXML, err := countXML(strings.NewReader(envXML))
+	if err != nil {
+		t.Fatalf("countXML: %v", err)
+	}
+	if fromJSON != 4 || fromXML != 4 {
+		t.Fatalf("element counts: json=%d xml=%d, want 4 for both", fromJSON, fromXML)
+	}
+}
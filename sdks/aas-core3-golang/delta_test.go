@@ -0,0 +1,274 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+)
+
+// deltaEditEvery is the stride of element values changed in the second
+// version of an environment for the diff and patch benchmarks.
+const deltaEditEvery = 10
+
+// Kinds of deltaOp.
+const (
+	deltaAdd    = "add"
+	deltaRemove = "remove"
+	deltaModify = "modify"
+)
+
+// deltaOp is one difference between two versions of an environment. Path
+// is an elementIndex key. Value is the new value of a modify, as
+// elementValue returns it.
+type deltaOp struct {
+	Kind  string
+	Path  string
+	Value interface{}
+}
+
+// rangeValue is the value of a Range.
+type rangeValue struct {
+	Min, Max *string
+}
+
+// elementValue returns the value of a Property, MultiLanguageProperty,
+// Range, Blob or File, and false for every other kind of element.
+func elementValue(el aastypes.IClass) (interface{}, bool) {
+	switch e := el.(type) {
+	case aastypes.IProperty:
+		return e.Value(), true
+	case aastypes.IMultiLanguageProperty:
+		return e.Value(), true
+	case aastypes.IRange:
+		return rangeValue{Min: e.Min(), Max: e.Max()}, true
+	case aastypes.IBlob:
+		return e.Value(), true
+	case aastypes.IFile:
+		return e.Value(), true
+	}
+	return nil, false
+}
+
+// setElementValue sets the value of el to v, which must be of the type
+// elementValue returns for el.
+func setElementValue(el aastypes.IClass, v interface{}) bool {
+	switch e := el.(type) {
+	case aastypes.IProperty:
+		s, ok := v.(*string)
+		if ok {
+			e.SetValue(s)
+		}
+		return ok
+	case aastypes.IMultiLanguageProperty:
+		texts, ok := v.([]aastypes.ILangStringTextType)
+		if ok {
+			e.SetValue(texts)
+		}
+		return ok
+	case aastypes.IRange:
+		r, ok := v.(rangeValue)
+		if ok {
+			e.SetMin(r.Min)
+			e.SetMax(r.Max)
+		}
+		return ok
+	case aastypes.IBlob:
+		data, ok := v.([]byte)
+		if ok {
+			e.SetValue(data)
+		}
+		return ok
+	case aastypes.IFile:
+		s, ok := v.(*string)
+		if ok {
+			e.SetValue(s)
+		}
+		return ok
+	}
+	return false
+}
+
+// equalValues compares two element values by content. Lang strings are
+// compared field by field, so copies from separate deserializations match.
+func equalValues(a, b interface{}) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// diffEnvironments computes the structural delta that turns from into to:
+// elements present on one side only, and changed values of the elements
+// elementValue covers.
+func diffEnvironments(from, to aastypes.IEnvironment) []deltaOp {
+	before, after := indexEnvironment(from), indexEnvironment(to)
+	var ops []deltaOp
+	for path, el := range after {
+		old, ok := before[path]
+		if !ok {
+			ops = append(ops, deltaOp{Kind: deltaAdd, Path: path})
+			continue
+		}
+		newValue, ok1 := elementValue(el)
+		oldValue, ok2 := elementValue(old)
+		if ok1 && ok2 && !equalValues(oldValue, newValue) {
+			ops = append(ops, deltaOp{Kind: deltaModify, Path: path, Value: newValue})
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			ops = append(ops, deltaOp{Kind: deltaRemove, Path: path})
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].Path < ops[j].Path })
+	return ops
+}
+
+// patchEnvironment applies the modify operations of delta in place to the
+// environment indexed by idx and returns the delta that undoes them.
+// Structural add and remove operations need the source elements, so they
+// are rejected; diff reports them, but patch covers value synchronization
+// only.
+func patchEnvironment(idx elementIndex, delta []deltaOp) ([]deltaOp, error) {
+	undo := make([]deltaOp, 0, len(delta))
+	for _, op := range delta {
+		if op.Kind != deltaModify {
+			return nil, fmt.Errorf("patch: unsupported %s at %s", op.Kind, op.Path)
+		}
+		el := idx[op.Path]
+		old, ok := elementValue(el)
+		if !ok {
+			return nil, fmt.Errorf("patch: no value element at %s", op.Path)
+		}
+		if !setElementValue(el, op.Value) {
+			return nil, fmt.Errorf("patch: value of type %T does not fit %s", op.Value, op.Path)
+		}
+		undo = append(undo, deltaOp{Kind: deltaModify, Path: op.Path, Value: old})
+	}
+	return undo, nil
+}
+
+// editValues changes the value of every nth element elementValue covers
+// and returns how many it changed, producing the second version for the
+// delta benchmarks. Elements without a value are skipped.
+func editValues(env aastypes.IEnvironment, n int) int {
+	seen, edited := 0, 0
+	env.Descend(func(node aastypes.IClass) bool {
+		if !hasValue(node) {
+			return false
+		}
+		if seen%n == 0 {
+			editValue(node)
+			edited++
+		}
+		seen++
+		return false
+	})
+	return edited
+}
+
+func hasValue(node aastypes.IClass) bool {
+	v, ok := elementValue(node)
+	if !ok {
+		return false
+	}
+	switch v := v.(type) {
+	case *string:
+		return v != nil
+	case []aastypes.ILangStringTextType:
+		return len(v) > 0
+	case rangeValue:
+		return v.Max != nil
+	case []byte:
+		return v != nil
+	}
+	return false
+}
+
+// editValue changes the value of node, which hasValue accepted.
+func editValue(node aastypes.IClass) {
+	switch e := node.(type) {
+	case aastypes.IProperty:
+		updated := *e.Value() + "_v2"
+		e.SetValue(&updated)
+	case aastypes.IMultiLanguageProperty:
+		text := e.Value()[0]
+		text.SetText(text.Text() + "_v2")
+	case aastypes.IRange:
+		updated := *e.Max() + "0"
+		e.SetMax(&updated)
+	case aastypes.IBlob:
+		e.SetValue(append(append([]byte{}, e.Value()...), 0))
+	case aastypes.IFile:
+		updated := *e.Value() + "_v2"
+		e.SetValue(&updated)
+	}
+}
+
+// deltaPair deserializes raw twice and edits the second copy.
+func deltaPair(b testing.TB, name string, raw []byte) (original, edited aastypes.IEnvironment) {
+	b.Helper()
+	original, err := deserializeEnv(raw)
+	if err != nil {
+		b.Fatalf("Setup failed for %s: %v", name, err)
+	}
+	edited, err = deserializeEnv(raw)
+	if err != nil {
+		b.Fatalf("Setup failed for %s: %v", name, err)
+	}
+	editValues(edited, deltaEditEvery)
+	return original, edited
+}
+
+// BenchmarkDiff benchmarks computing the structural diff between an
+// environment and a version with every tenth element value changed.
+func BenchmarkDiff(b *testing.B) {
+	selectOperation(b, "diff")
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		original, edited := deltaPair(b, name, loadRawJSON(b, f))
+		runObserved(b, "diff", name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				delta := diffEnvironments(original, edited)
+				_ = delta
+			}
+		})
+	}
+	globalMemStats.Groups["diff"] = captureMemSnapshot()
+	globalHeap.writeProfile("diff")
+}
+
+// BenchmarkPatch benchmarks applying that diff to the original
+// environment, locating each changed element by path in an index built
+// once beforehand. Iterations alternate between applying the delta and
+// its undo, so each one is a single patch of the same size and every pair
+// leaves the original unchanged.
+func BenchmarkPatch(b *testing.B) {
+	selectOperation(b, "patch")
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		original, edited := deltaPair(b, name, loadRawJSON(b, f))
+		delta := diffEnvironments(original, edited)
+		idx := indexEnvironment(original)
+		runObserved(b, "patch", name, func(b *testing.B) {
+			next := delta
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				undo, err := patchEnvironment(idx, next)
+				if err != nil {
+					b.Fatal(err)
+				}
+				next = undo
+			}
+			b.StopTimer()
+			if b.N%2 == 1 {
+				if _, err := patchEnvironment(idx, next); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	globalMemStats.Groups["patch"] = captureMemSnapshot()
+	globalHeap.writeProfile("patch")
+}
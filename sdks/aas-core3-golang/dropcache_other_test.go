@@ -0,0 +1,9 @@
+//go:build !linux || !(amd64 || arm64)
+
+package main
+
+import "errors"
+
+func dropFileCache(path string) error {
+	return errors.New("dropping the page cache needs Linux posix_fadvise")
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// powercapRoot is where Linux exposes the RAPL energy counters.
+var powercapRoot = "/sys/class/powercap"
+
+// energyGroup is the energy one operation group consumed.
+type energyGroup struct {
+	Iterations int64   `json:"iterations"`
+	Seconds    float64 `json:"seconds"`
+	// Joules maps a RAPL domain ("package-0", "package-0/dram") to its
+	// consumption; TotalJoules sums the top-level domains, whose counters
+	// already include their subdomains.
+	Joules      map[string]float64 `json:"joules"`
+	TotalJoules float64            `json:"total_joules"`
+}
+
+// energyFile is the schema written to energy.json. Unavailable explains
+// why no group was measured.
+type energyFile struct {
+	Source      string                 `json:"source"`
+	Domains     []string               `json:"domains"`
+	Unavailable string                 `json:"unavailable,omitempty"`
+	Groups      map[string]energyGroup `json:"groups"`
+}
+
+// raplDomain is one energy counter of the powercap intel-rapl interface.
+type raplDomain struct {
+	name string
+	// top is false for a subdomain, counted inside its package.
+	top bool
+	// path is the domain's energy_uj file.
+	path string
+	// rangeUJ is max_energy_range_uj, where the counter wraps.
+	rangeUJ uint64
+}
+
+// energyMeter attributes RAPL energy readings to operation groups.
+// Enabled with ENERGY=1; Linux only. The counters cover the whole CPU
+// package, so everything else running on the host is charged too: on an
+// otherwise idle machine the figures compare SDKs, not absolute costs.
+type energyMeter struct {
+	enabled bool
+	mu      sync.Mutex
+	// domains are found on first use; unavailable is why none were.
+	domains     []raplDomain
+	probed      bool
+	unavailable string
+	groups      map[string]energyGroup
+}
+
+var globalEnergy = &energyMeter{
+	enabled: os.Getenv("ENERGY") != "",
+	groups:  make(map[string]energyGroup),
+}
+
+// findRAPLDomains lists the intel-rapl zones under root. Readable counters
+// usually need root since the energy_uj files were restricted (CVE-2020-8694).
+func findRAPLDomains(root string) ([]raplDomain, error) {
+	zones, err := filepath.Glob(filepath.Join(root, "intel-rapl:*"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(zones)
+	names := make(map[string]string, len(zones))
+	var domains []raplDomain
+	for _, zone := range zones {
+		name, err := readTrimmed(filepath.Join(zone, "name"))
+		if err != nil {
+			return nil, err
+		}
+		rangeUJ, err := readUint(filepath.Join(zone, "max_energy_range_uj"))
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(zone, "energy_uj")
+		if _, err := readUint(path); err != nil {
+			return nil, err
+		}
+		id := filepath.Base(zone)
+		d := raplDomain{name: name, top: strings.Count(id, ":") == 1, path: path, rangeUJ: rangeUJ}
+		if !d.top {
+			parent := id[:strings.LastIndex(id, ":")]
+			d.name = names[parent] + "/" + name
+		}
+		names[id] = d.name
+		domains = append(domains, d)
+	}
+	if len(domains) == 0 {
+		return nil, errors.New("no intel-rapl zones under " + root)
+	}
+	return domains, nil
+}
+
+func readTrimmed(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func readUint(path string) (uint64, error) {
+	s, err := readTrimmed(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
+
+// readEnergy returns the counter of every domain in µJ.
+func readEnergy(domains []raplDomain) ([]uint64, error) {
+	values := make([]uint64, len(domains))
+	for i, d := range domains {
+		v, err := readUint(d.path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", d.name, err)
+		}
+		values[i] = v
+	}
+	return values, nil
+}
+
+// energyDelta is the µJ between two readings of d, allowing for one wrap.
+func energyDelta(d raplDomain, before, after uint64) uint64 {
+	if after >= before {
+		return after - before
+	}
+	return d.rangeUJ - before + after
+}
+
+// measure wraps a sub-benchmark body so every call, including b.N ramp-up,
+// is metered and charged to the operation group.
+func (m *energyMeter) measure(operation string, fn func(b *testing.B)) func(b *testing.B) {
+	if !m.enabled {
+		return fn
+	}
+	return func(b *testing.B) {
+		domains := m.available()
+		if domains == nil {
+			fn(b)
+			return
+		}
+		before, err := readEnergy(domains)
+		if err != nil {
+			m.disable(err)
+			fn(b)
+			return
+		}
+		start := time.Now()
+		fn(b)
+		elapsed := time.Since(start)
+		after, err := readEnergy(domains)
+		if err != nil {
+			m.disable(err)
+			return
+		}
+		m.add(operation, int64(b.N), elapsed, domains, before, after)
+	}
+}
+
+// available returns the RAPL domains, probing them on first use, or nil
+// once metering was disabled.
+func (m *energyMeter) available() []raplDomain {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.probed {
+		m.probed = true
+		domains, err := findRAPLDomains(powercapRoot)
+		if err != nil {
+			m.unavailable = err.Error()
+			slog.Warn("energy counters unavailable", "err", err)
+		}
+		m.domains = domains
+	}
+	if m.unavailable != "" {
+		return nil
+	}
+	return m.domains
+}
+
+func (m *energyMeter) disable(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.unavailable == "" {
+		m.unavailable = err.Error()
+		slog.Warn("energy counters unavailable", "err", err)
+	}
+}
+
+func (m *energyMeter) add(operation string, iterations int64, elapsed time.Duration, domains []raplDomain, before, after []uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	g := m.groups[operation]
+	if g.Joules == nil {
+		g.Joules = make(map[string]float64, len(domains))
+	}
+	g.Iterations += iterations
+	g.Seconds += elapsed.Seconds()
+	for i, d := range domains {
+		joules := float64(energyDelta(d, before[i], after[i])) / 1e6
+		g.Joules[d.name] += joules
+		if d.top {
+			g.TotalJoules += joules
+		}
+	}
+	m.groups[operation] = g
+}
+
+func (m *energyMeter) snapshot() energyFile {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := energyFile{
+		Source:      "intel-rapl (powercap)",
+		Domains:     make([]string, 0, len(m.domains)),
+		Unavailable: m.unavailable,
+		Groups:      make(map[string]energyGroup, len(m.groups)),
+	}
+	for _, d := range m.domains {
+		out.Domains = append(out.Domains, d.name)
+	}
+	for op, g := range m.groups {
+		out.Groups[op] = g
+	}
+	return out
+}
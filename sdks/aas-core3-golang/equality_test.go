@@ -0,0 +1,140 @@
+package main
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"testing"
+
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+)
+
+// structuralHasher hashes an environment by walking every field of every
+// object in it, so that any change to content changes the hash. The SDK
+// offers neither hashing nor equality, so the walk is generic over the
+// SDK's structs, as change detection in an application would be.
+type structuralHasher struct {
+	h   hash.Hash64
+	buf []byte
+}
+
+func newStructuralHasher() *structuralHasher {
+	return &structuralHasher{h: fnv.New64a(), buf: make([]byte, 0, 64)}
+}
+
+// sum returns the structural hash of env.
+func (s *structuralHasher) sum(env aastypes.IEnvironment) uint64 {
+	s.h.Reset()
+	s.value(reflect.ValueOf(env))
+	return s.h.Sum64()
+}
+
+// value feeds v into the hash. Nil pointers and interfaces, slice lengths
+// and string lengths are written too, so that differently shaped values
+// cannot hash alike by concatenation.
+func (s *structuralHasher) value(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			s.writeUint(0)
+			return
+		}
+		s.writeUint(1)
+		s.value(v.Elem())
+	case reflect.Struct:
+		s.writeString(v.Type().Name())
+		for i := 0; i < v.NumField(); i++ {
+			s.value(v.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		s.writeUint(uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			s.value(v.Index(i))
+		}
+	case reflect.String:
+		s.writeString(v.String())
+	case reflect.Bool:
+		if v.Bool() {
+			s.writeUint(1)
+		} else {
+			s.writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s.writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		s.writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		s.writeUint(math.Float64bits(v.Float()))
+	}
+}
+
+func (s *structuralHasher) writeUint(n uint64) {
+	s.buf = binary.LittleEndian.AppendUint64(s.buf[:0], n)
+	s.h.Write(s.buf)
+}
+
+func (s *structuralHasher) writeString(str string) {
+	s.writeUint(uint64(len(str)))
+	s.buf = append(s.buf[:0], str...)
+	s.h.Write(s.buf)
+}
+
+// environmentsEqual reports whether a and b are deeply equal, comparing
+// values rather than object identity.
+func environmentsEqual(a, b aastypes.IEnvironment) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// BenchmarkHash benchmarks computing the structural hash of a full
+// environment.
+func BenchmarkHash(b *testing.B) {
+	selectOperation(b, "hash")
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		env, err := deserializeEnv(loadRawJSON(b, f))
+		if err != nil {
+			b.Fatalf("Setup failed for %s: %v", name, err)
+		}
+		hasher := newStructuralHasher()
+		runObserved(b, "hash", name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				sum := hasher.sum(env)
+				_ = sum
+			}
+		})
+	}
+	globalMemStats.Groups["hash"] = captureMemSnapshot()
+	globalHeap.writeProfile("hash")
+}
+
+// BenchmarkEquals benchmarks deep equality of two separately deserialized
+// copies of an environment. The copies are equal, so every field is
+// compared.
+func BenchmarkEquals(b *testing.B) {
+	selectOperation(b, "equals")
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		raw := loadRawJSON(b, f)
+		a, err := deserializeEnv(raw)
+		if err != nil {
+			b.Fatalf("Setup failed for %s: %v", name, err)
+		}
+		c, err := deserializeEnv(raw)
+		if err != nil {
+			b.Fatalf("Setup failed for %s: %v", name, err)
+		}
+		runObserved(b, "equals", name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if !environmentsEqual(a, c) {
+					b.Fatal("copies of the same dataset compare unequal")
+				}
+			}
+		})
+	}
+	globalMemStats.Groups["equals"] = captureMemSnapshot()
+	globalHeap.writeProfile("equals")
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// observationWindow is the wall-clock span of one sub-benchmark run.
+type observationWindow struct {
+	Operation string    `json:"operation"`
+	Dataset   string    `json:"dataset"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
+
+// systemEvent is a disruptive system event detected between two samples.
+// Start is the previous sample time and End the sample that observed it,
+// so the event is known to have happened somewhere inside [Start, End].
+type systemEvent struct {
+	Kind   string    `json:"kind"`
+	Source string    `json:"source"`
+	Detail string    `json:"detail"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// eventsFile is the schema written to events.json.
+type eventsFile struct {
+	SampleIntervalMs int64               `json:"sample_interval_ms"`
+	Windows          []observationWindow `json:"windows"`
+	Events           []systemEvent       `json:"events"`
+}
+
+// eventRecorder collects observation windows and polls the system for
+// thermal throttling and swap activity. Container restarts cost a docker
+// process per container, so they are sampled only between sub-benchmarks,
+// outside every measurement window.
+type eventRecorder struct {
+	mu         sync.Mutex
+	interval   time.Duration
+	containers []string
+	restarts   systemCounters
+	windows    []observationWindow
+	events     []systemEvent
+	stop       chan struct{}
+	done       chan struct{}
+}
+
+// systemCounters is one poll of the monotonically increasing counters the
+// recorder watches; events are derived from deltas between polls.
+type systemCounters struct {
+	at         time.Time
+	throttles  int64
+	swapIn     int64
+	swapOut    int64
+	restarts   map[string]int64
+	haveVMStat bool
+}
+
+// globalEvents is started by TestMain and written to events.json at the end.
+var globalEvents = newEventRecorder()
+
+func newEventRecorder() *eventRecorder {
+	interval := time.Second
+	if v := os.Getenv("EVENTS_SAMPLE_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			interval = d
+		} else {
+			slog.Warn("invalid EVENTS_SAMPLE_INTERVAL", "value", v, "err", err)
+		}
+	}
+	var containers []string
+	for _, c := range strings.Split(os.Getenv("SERVER_CONTAINERS"), ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			containers = append(containers, c)
+		}
+	}
+	return &eventRecorder{interval: interval, containers: containers}
+}
+
+// runObserved wraps b.Run and records the sub-benchmark's measurement window,
+// its GC pauses and, when enabled, its allocation sites, hardware counter
+// readings, energy and execution trace, and flushes the operation's progress before and after. A
+// control sample is taken first, outside the window.
+func runObserved(b *testing.B, operation, dataset string, fn func(b *testing.B)) {
+	b.Helper()
+	if !globalSelection.operation(operation) || !globalSelection.dataset(dataset) {
+		return
+	}
+	globalControl.sample(operation + "/" + dataset)
+	globalEvents.sampleContainers()
+	globalSkipped.observe(operation, dataset)
+	globalPartial.begin(operation, dataset)
+	heapBase := globalHeap.snapshot()
+	pauseBase := readGCPauses()
+	start := time.Now().UTC()
+	// ReportAllocs makes every benchmark print B/op and allocs/op, also in
+	// runs without -benchmem.
+	measured := globalTrace.trace(operation, dataset, func(b *testing.B) {
+		b.ReportAllocs()
+		fn(b)
+	})
+	stop := globalStages.time(report.StageBenchmark, operation, dataset)
+	globalCPU.profile(operation, dataset, func() {
+		b.Run(dataset, globalPartial.measure(operation, dataset, globalHeap.count(operation, globalPerf.count(operation, globalEnergy.measure(operation, measured)))))
+	})
+	stop()
+	globalPartial.finish(operation, dataset)
+	globalEvents.recordWindow(operation, dataset, start, time.Now().UTC())
+	globalGCPauses.observe(operation, pauseBase)
+	globalHeap.observe(operation, heapBase)
+}
+
+func (r *eventRecorder) recordWindow(operation, dataset string, start, end time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.windows = append(r.windows, observationWindow{
+		Operation: operation,
+		Dataset:   dataset,
+		Start:     start,
+		End:       end,
+	})
+}
+
+// Start takes the first container sample and launches the polling
+// goroutine. A non-positive interval disables polling; windows are still
+// recorded.
+func (r *eventRecorder) Start() {
+	r.sampleContainers()
+	if r.interval <= 0 {
+		return
+	}
+	r.stop = make(chan struct{})
+	r.done = make(chan struct{})
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		prev := r.readCounters()
+		for {
+			select {
+			case <-r.stop:
+				r.compare(prev, r.readCounters())
+				return
+			case <-ticker.C:
+				curr := r.readCounters()
+				r.compare(prev, curr)
+				prev = curr
+			}
+		}
+	}()
+}
+
+// Stop takes a final sample and waits for the polling goroutine to exit.
+func (r *eventRecorder) Stop() {
+	r.sampleContainers()
+	if r.stop == nil {
+		return
+	}
+	close(r.stop)
+	<-r.done
+}
+
+// sampleContainers reads the restart count of every watched container and
+// records an event for each that restarted since the previous sample. It
+// runs docker, so callers invoke it only outside measurement windows.
+func (r *eventRecorder) sampleContainers() {
+	if len(r.containers) == 0 {
+		return
+	}
+	curr := systemCounters{at: time.Now().UTC(), restarts: make(map[string]int64)}
+	for _, name := range r.containers {
+		if n, err := containerRestartCount(name); err == nil {
+			curr.restarts[name] = n
+		}
+	}
+	if r.restarts.restarts != nil {
+		r.compare(r.restarts, curr)
+	}
+	r.restarts = curr
+}
+
+func (r *eventRecorder) snapshot() eventsFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return eventsFile{
+		SampleIntervalMs: r.interval.Milliseconds(),
+		Windows:          append([]observationWindow{}, r.windows...),
+		Events:           append([]systemEvent{}, r.events...),
+	}
+}
+
+func (r *eventRecorder) readCounters() systemCounters {
+	c := systemCounters{at: time.Now().UTC()}
+	c.throttles = readThrottleCount()
+	c.swapIn, c.swapOut, c.haveVMStat = readSwapCounters()
+	return c
+}
+
+func (r *eventRecorder) compare(prev, curr systemCounters) {
+	var found []systemEvent
+	if d := curr.throttles - prev.throttles; d > 0 {
+		found = append(found, systemEvent{
+			Kind:   "thermal_throttle",
+			Source: "/sys/devices/system/cpu",
+			Detail: fmt.Sprintf("%d new throttle events", d),
+		})
+	}
+	if prev.haveVMStat && curr.haveVMStat {
+		in, out := curr.swapIn-prev.swapIn, curr.swapOut-prev.swapOut
+		if in > 0 || out > 0 {
+			found = append(found, systemEvent{
+				Kind:   "swap_activity",
+				Source: "/proc/vmstat",
+				Detail: fmt.Sprintf("pswpin=+%d pswpout=+%d", in, out),
+			})
+		}
+	}
+	for name, n := range curr.restarts {
+		if before, ok := prev.restarts[name]; ok && n > before {
+			found = append(found, systemEvent{
+				Kind:   "container_restart",
+				Source: name,
+				Detail: fmt.Sprintf("restart count %d -> %d", before, n),
+			})
+		}
+	}
+	if len(found) == 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, ev := range found {
+		ev.Start, ev.End = prev.at, curr.at
+		r.events = append(r.events, ev)
+	}
+}
+
+// readThrottleCount sums per-core thermal throttle counters. Returns 0 where
+// the sysfs interface is unavailable (non-Linux, most VMs).
+func readThrottleCount() int64 {
+	matches, _ := filepath.Glob("/sys/devices/system/cpu/cpu[0-9]*/thermal_throttle/core_throttle_count")
+	var total int64
+	for _, path := range matches {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		if n, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64); err == nil {
+			total += n
+		}
+	}
+	return total
+}
+
+// readSwapCounters returns the cumulative pages swapped in and out.
+func readSwapCounters() (in, out int64, ok bool) {
+	data, err := os.ReadFile("/proc/vmstat")
+	if err != nil {
+		return 0, 0, false
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		switch fields[0] {
+		case "pswpin":
+			in, _ = strconv.ParseInt(fields[1], 10, 64)
+		case "pswpout":
+			out, _ = strconv.ParseInt(fields[1], 10, 64)
+		}
+	}
+	return in, out, true
+}
+
+// containerRestartCount asks Docker how often a container has been restarted.
+func containerRestartCount(name string) (int64, error) {
+	out, err := exec.Command("docker", "inspect", "--format", "{{.RestartCount}}", name).Output()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(out)), 10, 64)
+}
@@ -0,0 +1,250 @@
+// Package exectrace summarizes the Go execution traces the harness captures
+// of one iteration of each operation. The trace format itself changes with
+// every Go release, so the package reads the events as the toolchain's own
+// `go tool trace -d=parsed` prints them, rather than decoding the binary
+// format with a dependency on golang.org/x/exp/trace.
+package exectrace
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// regionPrefix starts the type of the user region the harness wraps the
+// traced iteration in.
+const regionPrefix = "aasbench:"
+
+// Region is the type of the user region around the traced iteration of
+// operation on dataset.
+func Region(operation, dataset string) string {
+	return regionPrefix + operation + "/" + dataset
+}
+
+// gcAssist names the range of a GC mark assist on a goroutine.
+const gcAssist = "GC mark assist"
+
+// systemWait is the wait reason of the runtime's own goroutines, such as
+// the GC workers a goroutine starting a cycle creates; they are not the
+// operation's.
+const systemWait = "system goroutine wait"
+
+// event is one line of the parsed trace: "M=1 P=0 G=7 Kind Time=... k=v ...".
+type event struct {
+	g      int64
+	kind   string
+	time   int64
+	fields map[string]string
+	// from and to are the states of a StateTransition.
+	from, to string
+}
+
+// parseEvent parses a line of the parsed trace, reporting false for the
+// lines that are not events, such as the frames of a stack.
+func parseEvent(line string) (event, bool) {
+	if !strings.HasPrefix(line, "M=") {
+		return event{}, false
+	}
+	e := event{fields: make(map[string]string)}
+	for rest := line; rest != ""; {
+		rest = strings.TrimLeft(rest, " ")
+		key, value, ok := strings.Cut(rest, "=")
+		if sp := strings.IndexByte(rest, ' '); !ok || sp >= 0 && sp < len(key) {
+			// A bare word: the event kind or a state transition.
+			word, tail, _ := strings.Cut(rest, " ")
+			if from, to, ok := strings.Cut(word, "->"); ok {
+				e.from, e.to = from, to
+			} else if e.kind == "" {
+				e.kind = word
+			}
+			rest = tail
+			continue
+		}
+		if strings.HasPrefix(value, `"`) {
+			quoted, err := strconv.QuotedPrefix(value)
+			if err != nil {
+				break
+			}
+			e.fields[key], _ = strconv.Unquote(quoted)
+			rest = value[len(quoted):]
+			continue
+		}
+		if key == "Attributes" {
+			// The last field, and the only one holding spaces unquoted.
+			break
+		}
+		e.fields[key], rest, _ = strings.Cut(value, " ")
+	}
+	e.g, _ = strconv.ParseInt(e.fields["G"], 10, 64)
+	e.time, _ = strconv.ParseInt(e.fields["Time"], 10, 64)
+	return e, e.kind != ""
+}
+
+// goroutineScope returns the goroutine of a "Goroutine(7)" scope.
+func goroutineScope(scope string) (int64, bool) {
+	if !strings.HasPrefix(scope, "Goroutine(") || !strings.HasSuffix(scope, ")") {
+		return 0, false
+	}
+	g, err := strconv.ParseInt(scope[len("Goroutine("):len(scope)-1], 10, 64)
+	return g, err == nil
+}
+
+// summary accumulates the iteration's figures while the events stream by.
+type summary struct {
+	report.ExecTrace
+	start    int64
+	involved map[int64]bool
+	live     int
+	// waiting, runnable and assisting hold when an involved goroutine
+	// entered the state, and reason why it waits.
+	waiting   map[int64]int64
+	reason    map[int64]string
+	runnable  map[int64]int64
+	assisting map[int64]int64
+}
+
+// Summarize reads a trace as go tool trace -d=parsed prints it and
+// summarizes the harness's region in it: its wall time and, counting the
+// goroutine that ran the region and those it started, the goroutines
+// created and alive at once, the time spent in GC assists, blocked and
+// waiting to run. It also returns the operation the region names.
+func Summarize(r io.Reader) (operation string, t report.ExecTrace, err error) {
+	var s *summary
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	for sc.Scan() {
+		e, ok := parseEvent(sc.Text())
+		if !ok {
+			continue
+		}
+		if s == nil {
+			if e.kind == "RegionBegin" && strings.HasPrefix(e.fields["Type"], regionPrefix) {
+				name := strings.TrimPrefix(e.fields["Type"], regionPrefix)
+				var dataset string
+				operation, dataset, _ = strings.Cut(name, "/")
+				s = &summary{
+					start:     e.time,
+					involved:  map[int64]bool{e.g: true},
+					live:      1,
+					waiting:   make(map[int64]int64),
+					reason:    make(map[int64]string),
+					runnable:  make(map[int64]int64),
+					assisting: make(map[int64]int64),
+				}
+				s.Dataset = dataset
+				s.MaxGoroutines = 1
+			}
+			continue
+		}
+		if e.kind == "RegionEnd" && strings.HasPrefix(e.fields["Type"], regionPrefix) {
+			s.finish(e.time)
+			return operation, s.ExecTrace, nil
+		}
+		s.observe(e)
+	}
+	if err := sc.Err(); err != nil {
+		return "", report.ExecTrace{}, err
+	}
+	if s == nil {
+		return "", report.ExecTrace{}, fmt.Errorf("trace has no %s region", regionPrefix+"<operation>/<dataset>")
+	}
+	return "", report.ExecTrace{}, fmt.Errorf("trace ends inside the %s region", regionPrefix+operation)
+}
+
+func (s *summary) observe(e event) {
+	switch e.kind {
+	case "StateTransition":
+		id, err := strconv.ParseInt(e.fields["GoID"], 10, 64)
+		if err != nil {
+			return
+		}
+		if e.from == "NotExist" && s.involved[e.g] {
+			s.involved[id] = true
+			s.GoroutinesCreated++
+			s.live++
+			s.MaxGoroutines = max(s.MaxGoroutines, s.live)
+		}
+		if !s.involved[id] {
+			return
+		}
+		switch e.from {
+		case "Waiting":
+			if since, ok := s.waiting[id]; ok {
+				s.block(id, e.time-since)
+				delete(s.waiting, id)
+			}
+		case "Runnable":
+			if since, ok := s.runnable[id]; ok {
+				s.RunnableNs += e.time - since
+				delete(s.runnable, id)
+			}
+		}
+		switch e.to {
+		case "Waiting":
+			if e.fields["Reason"] == systemWait {
+				s.disown(id)
+				return
+			}
+			s.waiting[id] = e.time
+			s.reason[id] = e.fields["Reason"]
+		case "Runnable":
+			s.runnable[id] = e.time
+		case "NotExist":
+			s.live--
+		}
+	case "RangeBegin", "RangeEnd":
+		if e.fields["Name"] != gcAssist {
+			return
+		}
+		id, ok := goroutineScope(e.fields["Scope"])
+		if !ok || !s.involved[id] {
+			return
+		}
+		if e.kind == "RangeBegin" {
+			s.assisting[id] = e.time
+		} else if since, ok := s.assisting[id]; ok {
+			s.GCAssistNs += e.time - since
+			delete(s.assisting, id)
+		}
+	}
+}
+
+// disown stops counting goroutine id, which turned out to be the runtime's.
+func (s *summary) disown(id int64) {
+	delete(s.involved, id)
+	delete(s.runnable, id)
+	delete(s.assisting, id)
+	s.GoroutinesCreated--
+	s.live--
+}
+
+// block charges d of waiting to goroutine id's wait reason.
+func (s *summary) block(id, d int64) {
+	s.BlockedNs += d
+	if s.BlockedByReason == nil {
+		s.BlockedByReason = make(map[string]int64)
+	}
+	reason := s.reason[id]
+	if reason == "" {
+		reason = "unknown"
+	}
+	s.BlockedByReason[reason] += d
+}
+
+// finish closes the region at end, charging the states still open.
+func (s *summary) finish(end int64) {
+	s.WallNs = end - s.start
+	for id, since := range s.waiting {
+		s.block(id, end-since)
+	}
+	for _, since := range s.runnable {
+		s.RunnableNs += end - since
+	}
+	for _, since := range s.assisting {
+		s.GCAssistNs += end - since
+	}
+}
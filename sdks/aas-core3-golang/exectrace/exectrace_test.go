@@ -0,0 +1,91 @@
+package exectrace
+
+import (
+	"strings"
+	"testing"
+)
+
+// parsed is a trace as go tool trace -d=parsed prints it, trimmed to the
+// events Summarize reads. G=7 runs the region and starts G=20, which
+// starts G=21; G=30 is unrelated and must not count.
+const parsed = `M=1 P=0 G=7 StateTransition Time=900 GoID=20 NotExist->Runnable Reason=""
+M=1 P=0 G=7 RegionBegin Time=1000 Task=0 Type="aasbench:deserialize/mixed"
+M=1 P=0 G=7 StateTransition Time=1100 GoID=20 NotExist->Runnable Reason=""
+TransitionStack=
+	main.work @ 0x4a1b2c
+		/src/main.go:12
+M=1 P=0 G=7 StateTransition Time=1200 GoID=7 Running->Waiting Reason="sync"
+M=1 P=1 G=-1 StateTransition Time=1250 GoID=20 Runnable->Running Reason=""
+M=1 P=1 G=20 StateTransition Time=1300 GoID=21 NotExist->Runnable Reason=""
+M=1 P=1 G=20 RangeBegin Time=1400 Name="GC mark assist" Scope=Goroutine(20)
+M=1 P=1 G=20 RangeEnd Time=1500 Name="GC mark assist" Scope=Goroutine(20) Attributes=[]
+M=1 P=1 G=30 RangeBegin Time=1400 Name="GC mark assist" Scope=Goroutine(30)
+M=1 P=1 G=30 RangeEnd Time=1900 Name="GC mark assist" Scope=Goroutine(30) Attributes=[]
+M=1 P=1 G=20 StateTransition Time=1600 GoID=20 Running->NotExist Reason=""
+M=1 P=1 G=30 StateTransition Time=1650 GoID=30 Running->Waiting Reason="chan receive"
+M=1 P=1 G=21 StateTransition Time=1700 GoID=7 Waiting->Runnable Reason=""
+M=1 P=0 G=-1 StateTransition Time=1800 GoID=7 Runnable->Running Reason=""
+M=1 P=0 G=7 StateTransition Time=1850 GoID=7 Running->Waiting Reason="chan receive"
+M=1 P=0 G=-1 StateTransition Time=1900 GoID=7 Waiting->Running Reason=""
+M=1 P=0 G=7 RegionEnd Time=2000 Task=0 Type="aasbench:deserialize/mixed"
+M=1 P=0 G=7 StateTransition Time=2100 GoID=7 Running->Waiting Reason="sync"
+`
+
+func TestSummarizeRegion(t *testing.T) {
+	operation, got, err := Summarize(strings.NewReader(parsed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if operation != "deserialize" || got.Dataset != "mixed" {
+		t.Errorf("region = %s/%s, want deserialize/mixed", operation, got.Dataset)
+	}
+	if got.WallNs != 1000 {
+		t.Errorf("wall = %d, want 1000", got.WallNs)
+	}
+	// G=20 and G=21, three alive at once with G=7.
+	if got.GoroutinesCreated != 2 || got.MaxGoroutines != 3 {
+		t.Errorf("goroutines = %d created, %d max, want 2 and 3", got.GoroutinesCreated, got.MaxGoroutines)
+	}
+	if got.GCAssistNs != 100 {
+		t.Errorf("gc assist = %d, want 100", got.GCAssistNs)
+	}
+	// G=7 waits 500 on sync and 50 on a channel; G=21 stays runnable from
+	// 1300 to the end, G=20 from 1100 to 1250 and G=7 from 1700 to 1800.
+	if got.BlockedNs != 550 || got.BlockedByReason["sync"] != 500 || got.BlockedByReason["chan receive"] != 50 {
+		t.Errorf("blocked = %d %v, want 550 split 500 sync, 50 chan receive", got.BlockedNs, got.BlockedByReason)
+	}
+	if got.RunnableNs != 700+150+100 {
+		t.Errorf("runnable = %d, want 950", got.RunnableNs)
+	}
+}
+
+func TestSummarizeWithoutRegion(t *testing.T) {
+	if _, _, err := Summarize(strings.NewReader("M=1 P=0 G=7 StateTransition Time=1 GoID=7 Running->Waiting Reason=\"sync\"\n")); err == nil {
+		t.Error("want an error for a trace without the harness's region")
+	}
+	open := parsed[:strings.Index(parsed, "M=1 P=0 G=7 RegionEnd")]
+	if _, _, err := Summarize(strings.NewReader(open)); err == nil {
+		t.Error("want an error for a trace ending inside the region")
+	}
+}
+
+func TestRegionNamesOperationAndDataset(t *testing.T) {
+	if got := Region("deserialize", "mixed"); got != "aasbench:deserialize/mixed" {
+		t.Errorf("Region = %q", got)
+	}
+}
+
+func TestSummarizeDisownsSystemGoroutines(t *testing.T) {
+	trace := `M=1 P=0 G=7 RegionBegin Time=1000 Task=0 Type="aasbench:validate/mixed"
+M=1 P=0 G=7 StateTransition Time=1100 GoID=40 NotExist->Runnable Reason=""
+M=1 P=1 G=40 StateTransition Time=1200 GoID=40 Running->Waiting Reason="system goroutine wait"
+M=1 P=0 G=7 RegionEnd Time=2000 Task=0 Type="aasbench:validate/mixed"
+`
+	_, got, err := Summarize(strings.NewReader(trace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.GoroutinesCreated != 0 || got.BlockedNs != 0 {
+		t.Errorf("GC worker counted: %+v", got)
+	}
+}
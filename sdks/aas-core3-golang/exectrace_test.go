@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/trace"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/exectrace"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// execTracer writes a Go execution trace of one iteration of every
+// operation, the first b.N=1 round on its first dataset, to
+// OUTPUT_DIR/<operation>.trace, which aasbench run summarizes into
+// exec_traces.json. Enabled with EXEC_TRACE=1. The traced round is the
+// b.N ramp-up's, so the benchmark's own result is not affected, unless
+// -benchtime=1x makes it the only round.
+type execTracer struct {
+	enabled bool
+	traced  map[string]bool
+}
+
+var globalTrace = &execTracer{enabled: os.Getenv("EXEC_TRACE") != "", traced: make(map[string]bool)}
+
+// trace wraps fn, the sub-benchmark of operation on dataset, to trace its
+// first single-iteration round inside an exectrace.Region.
+func (t *execTracer) trace(operation, dataset string, fn func(b *testing.B)) func(b *testing.B) {
+	outputDir := os.Getenv("OUTPUT_DIR")
+	if !t.enabled || outputDir == "" || t.traced[operation] {
+		return fn
+	}
+	return func(b *testing.B) {
+		if b.N != 1 || t.traced[operation] {
+			fn(b)
+			return
+		}
+		t.traced[operation] = true
+		var buf bytes.Buffer
+		if err := trace.Start(&buf); err != nil {
+			slog.Warn("failed to start execution trace", "operation", operation, "err", err)
+			fn(b)
+			return
+		}
+		trace.WithRegion(context.Background(), exectrace.Region(operation, dataset), func() { fn(b) })
+		trace.Stop()
+		if err := os.MkdirAll(outputDir, 0755); err != nil {
+			slog.Warn("failed to create output dir", "err", err)
+			return
+		}
+		path := filepath.Join(outputDir, operation+report.ExecTraceSuffix)
+		if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+			slog.Warn("failed to write execution trace", "path", path, "err", err)
+		}
+	}
+}
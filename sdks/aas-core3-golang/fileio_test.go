@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+// deserializeFile reads a dataset from disk and deserializes it, the way a
+// deployment that parses from files does.
+func deserializeFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	_, err = deserializeEnv(raw)
+	return err
+}
+
+// BenchmarkDeserializeFileWarm benchmarks open file -> read -> deserialize
+// with the file in the page cache, which a first untimed read ensures.
+func BenchmarkDeserializeFileWarm(b *testing.B) {
+	selectOperation(b, "deserialize_file_warm")
+	for _, f := range payloadDatasetFiles(b) {
+		path := f
+		if err := deserializeFile(path); err != nil {
+			b.Fatalf("Setup failed for %s: %v", datasetName(path), err)
+		}
+		runObserved(b, "deserialize_file_warm", datasetName(path), func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := deserializeFile(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	globalMemStats.Groups["deserialize_file_warm"] = captureMemSnapshot()
+	globalHeap.writeProfile("deserialize_file_warm")
+}
+
+// BenchmarkDeserializeFileCold benchmarks open file -> read -> deserialize
+// with the file evicted from the page cache before every iteration, outside
+// the timed window. Where eviction is not permitted the operation is
+// skipped rather than silently measured warm.
+func BenchmarkDeserializeFileCold(b *testing.B) {
+	selectOperation(b, "deserialize_file_cold")
+	files := payloadDatasetFiles(b)
+	if err := dropFileCache(files[0]); err != nil {
+		b.Skipf("cannot drop the page cache: %v", err)
+	}
+	for _, f := range files {
+		path := f
+		runObserved(b, "deserialize_file_cold", datasetName(path), func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				if err := dropFileCache(path); err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+				if err := deserializeFile(path); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	globalMemStats.Groups["deserialize_file_cold"] = captureMemSnapshot()
+	globalHeap.writeProfile("deserialize_file_cold")
+}
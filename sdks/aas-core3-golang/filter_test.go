@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	aas "github.com/aas-core-works/aas-core3.0-golang/jsonization"
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+)
+
+// The generated submodels carry no semanticIds either, so setup tags
+// submodel i with filterSemanticIDPrefix followed by i modulo
+// filterSemanticIDKinds, and filter keeps the kinds in filterSemanticIDs:
+// half of the submodels, always including the first.
+const (
+	filterSemanticIDPrefix = "urn:benchmark:semantic:submodel:"
+	filterSemanticIDKinds  = 4
+)
+
+var filterSemanticIDs = map[string]bool{
+	filterSemanticIDPrefix + "0": true,
+	filterSemanticIDPrefix + "2": true,
+}
+
+// tagSubmodelSemanticIDs sets the submodel semanticIds filter selects by.
+func tagSubmodelSemanticIDs(env aastypes.IEnvironment) {
+	for i, sm := range env.Submodels() {
+		sm.SetSemanticID(aastypes.NewReference(
+			aastypes.ReferenceTypesExternalReference,
+			[]aastypes.IKey{aastypes.NewKey(aastypes.KeyTypesGlobalReference,
+				filterSemanticIDPrefix+strconv.Itoa(i%filterSemanticIDKinds))},
+		))
+	}
+}
+
+// filterEnvironment returns a copy of env reduced to the submodels whose
+// semanticId is in semanticIDs, as an edge gateway forwards only the
+// submodels its consumers subscribed to. The shells referencing a kept
+// submodel are kept with their references to the others dropped; concept
+// descriptions are all kept. Everything kept is deep-copied, so the copy
+// shares nothing with env; the SDK has no copy function, so the copy goes
+// through the jsonable form.
+func filterEnvironment(env aastypes.IEnvironment, semanticIDs map[string]bool) (aastypes.IEnvironment, error) {
+	kept := make(map[string]bool)
+	var submodels []aastypes.ISubmodel
+	for _, sm := range env.Submodels() {
+		ref := sm.SemanticID()
+		if ref == nil {
+			continue
+		}
+		if keys := ref.Keys(); len(keys) != 1 || !semanticIDs[keys[0].Value()] {
+			continue
+		}
+		jsonable, err := aas.ToJsonable(sm)
+		if err != nil {
+			return nil, err
+		}
+		copied, err := aas.SubmodelFromJsonable(jsonable)
+		if err != nil {
+			return nil, err
+		}
+		kept[sm.ID()] = true
+		submodels = append(submodels, copied)
+	}
+
+	var shells []aastypes.IAssetAdministrationShell
+	for _, shell := range env.AssetAdministrationShells() {
+		var keep []int
+		for i, ref := range shell.Submodels() {
+			if keys := ref.Keys(); len(keys) > 0 && kept[keys[0].Value()] {
+				keep = append(keep, i)
+			}
+		}
+		if len(keep) == 0 {
+			continue
+		}
+		jsonable, err := aas.ToJsonable(shell)
+		if err != nil {
+			return nil, err
+		}
+		copied, err := aas.AssetAdministrationShellFromJsonable(jsonable)
+		if err != nil {
+			return nil, err
+		}
+		all := copied.Submodels()
+		refs := make([]aastypes.IReference, len(keep))
+		for j, i := range keep {
+			refs[j] = all[i]
+		}
+		copied.SetSubmodels(refs)
+		shells = append(shells, copied)
+	}
+
+	var conceptDescriptions []aastypes.IConceptDescription
+	for _, cd := range env.ConceptDescriptions() {
+		jsonable, err := aas.ToJsonable(cd)
+		if err != nil {
+			return nil, err
+		}
+		copied, err := aas.ConceptDescriptionFromJsonable(jsonable)
+		if err != nil {
+			return nil, err
+		}
+		conceptDescriptions = append(conceptDescriptions, copied)
+	}
+
+	filtered := aastypes.NewEnvironment()
+	filtered.SetAssetAdministrationShells(shells)
+	filtered.SetSubmodels(submodels)
+	filtered.SetConceptDescriptions(conceptDescriptions)
+	return filtered, nil
+}
+
+// BenchmarkFilter benchmarks projecting an environment onto a semanticId
+// set: the selection traversal and the construction of the filtered copy.
+func BenchmarkFilter(b *testing.B) {
+	selectOperation(b, "filter")
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		env, err := deserializeEnv(loadRawJSON(b, f))
+		if err != nil {
+			b.Fatalf("Setup failed for %s: %v", name, err)
+		}
+		tagSubmodelSemanticIDs(env)
+		if err := checkFiltered(env); err != nil {
+			b.Fatalf("Setup failed for %s: %v", name, err)
+		}
+		runObserved(b, "filter", name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				filtered, err := filterEnvironment(env, filterSemanticIDs)
+				if err != nil {
+					b.Fatal(err)
+				}
+				_ = filtered
+			}
+		})
+	}
+	globalMemStats.Groups["filter"] = captureMemSnapshot()
+	globalHeap.writeProfile("filter")
+}
+
+// checkFiltered fails unless filtering the tagged env keeps exactly the
+// submodels of the selected kinds, so the benchmark cannot time a filter
+// that drops or keeps everything.
+func checkFiltered(env aastypes.IEnvironment) error {
+	filtered, err := filterEnvironment(env, filterSemanticIDs)
+	if err != nil {
+		return err
+	}
+	want := 0
+	for i := range env.Submodels() {
+		if filterSemanticIDs[filterSemanticIDPrefix+strconv.Itoa(i%filterSemanticIDKinds)] {
+			want++
+		}
+	}
+	if got := len(filtered.Submodels()); got != want || want == 0 {
+		return fmt.Errorf("filter kept %d of %d submodels, want %d", got, len(env.Submodels()), want)
+	}
+	return nil
+}
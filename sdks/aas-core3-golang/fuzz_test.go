@@ -0,0 +1,150 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime/metrics"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Outcomes of a fuzz input beyond those of a corrupted one. Both fail the
+// input's class, like a panic.
+const (
+	outcomeHung           = "hung"
+	outcomeMemoryExceeded = "memory_exceeded"
+)
+
+// fuzzCorpusIndex is the schema of fuzz/corpus.json, written by
+// datasets/generate.py --fuzz-corpus.
+type fuzzCorpusIndex struct {
+	Inputs []fuzzCorpusInput `json:"inputs"`
+}
+
+// fuzzCorpusInput is one mutant: which dataset it was derived from and
+// how.
+type fuzzCorpusInput struct {
+	File     string `json:"file"`
+	Dataset  string `json:"dataset"`
+	Mutation string `json:"mutation"`
+	Seed     int64  `json:"seed"`
+}
+
+// fuzzProber feeds a fuzz corpus to the JSON deserializer after the
+// benchmarks. Enabled with FUZZ_CORPUS set to the corpus directory;
+// FUZZ_DEADLINE is how long one input may take (default 5s).
+type fuzzProber struct {
+	dir      string
+	deadline time.Duration
+	// memoryFactor times the input size, but at least minMemoryBudget, is
+	// how far the live heap may grow while one input is decoded.
+	memoryFactor    uint64
+	minMemoryBudget uint64
+}
+
+var globalFuzz = newFuzzProber()
+
+func newFuzzProber() *fuzzProber {
+	p := &fuzzProber{
+		dir:             os.Getenv("FUZZ_CORPUS"),
+		deadline:        5 * time.Second,
+		memoryFactor:    200,
+		minMemoryBudget: 256 << 20,
+	}
+	if v := os.Getenv("FUZZ_DEADLINE"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			p.deadline = d
+		} else {
+			slog.Warn("invalid FUZZ_DEADLINE", "value", v, "err", err)
+		}
+	}
+	return p
+}
+
+// probeCorpus decodes every input of the corpus once and records its
+// outcome in globalRobustness, under the class fuzz_<mutation>.
+func (p *fuzzProber) probeCorpus() {
+	if p.dir == "" {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(p.dir, "corpus.json"))
+	if err != nil {
+		slog.Error("fuzz corpus unreadable", "dir", p.dir, "err", err)
+		return
+	}
+	var index fuzzCorpusIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		slog.Error("fuzz corpus unreadable", "dir", p.dir, "err", err)
+		return
+	}
+	hung := 0
+	for _, in := range index.Inputs {
+		raw, err := os.ReadFile(filepath.Join(p.dir, in.File))
+		if err != nil {
+			slog.Error("fuzz input unreadable", "file", in.File, "err", err)
+			continue
+		}
+		outcome, detail := p.probe(raw)
+		if outcome == outcomeHung {
+			hung++
+		}
+		globalRobustness.record(robustnessEntry{
+			Dataset: in.Dataset, Format: "json", Class: report.FuzzClassPrefix + in.Mutation,
+			Input: in.File, Outcome: outcome, Detail: detail,
+		})
+	}
+	slog.Info("probed fuzz corpus", "inputs", len(index.Inputs), "hung", hung)
+}
+
+// probe decodes raw in a goroutine of its own, so a hang or a runaway
+// allocation can be abandoned. An abandoned goroutine keeps running until
+// the process exits and burdens the inputs after it.
+func (p *fuzzProber) probe(raw []byte) (outcome, detail string) {
+	type result struct{ outcome, detail string }
+	done := make(chan result, 1)
+	budget := max(p.memoryFactor*uint64(len(raw)), p.minMemoryBudget)
+	base := liveHeapBytes()
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{outcomePanicked, fmt.Sprint(r)}
+			}
+		}()
+		if _, err := sdkAdapter.Deserialize(raw); err != nil {
+			done <- result{outcomeRejected, err.Error()}
+			return
+		}
+		done <- result{outcomeAccepted, ""}
+	}()
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	timeout := time.NewTimer(p.deadline)
+	defer timeout.Stop()
+	for {
+		select {
+		case r := <-done:
+			return r.outcome, r.detail
+		case <-timeout.C:
+			return outcomeHung, fmt.Sprintf("no result within %s", p.deadline)
+		case <-ticker.C:
+			if live := liveHeapBytes(); live > base && live-base > budget {
+				return outcomeMemoryExceeded, fmt.Sprintf("live heap grew by %d bytes decoding %d, over the budget of %d", live-base, len(raw), budget)
+			}
+		}
+	}
+}
+
+// liveHeapBytes reads the bytes of live and not yet swept heap objects
+// without stopping the world, unlike runtime.ReadMemStats.
+func liveHeapBytes() uint64 {
+	sample := []metrics.Sample{{Name: "/memory/classes/heap/objects:bytes"}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"math"
+	"runtime/metrics"
+	"sort"
+	"sync"
+)
+
+// gcPauseMetric is the runtime/metrics histogram of stop-the-world pause
+// latencies caused by the garbage collector.
+const gcPauseMetric = "/sched/pauses/total/gc:seconds"
+
+// gcPauseBucket is one non-empty bucket of a pause histogram.
+type gcPauseBucket struct {
+	LowerNs int64  `json:"lower_ns"`
+	UpperNs int64  `json:"upper_ns"`
+	Count   uint64 `json:"count"`
+}
+
+// gcPausesFile is the schema written to gc_pauses.json.
+type gcPausesFile struct {
+	Metric string                     `json:"metric"`
+	Groups map[string][]gcPauseBucket `json:"groups"`
+}
+
+// gcPauseRecorder attributes GC pauses to operation groups. The runtime
+// only keeps a cumulative histogram, so each sub-benchmark is charged the
+// difference between readings taken around it. Pauses are process-wide:
+// a group also collects pauses triggered by garbage of earlier groups.
+type gcPauseRecorder struct {
+	mu sync.Mutex
+	// boundaries are the histogram's bucket boundaries in seconds; they do
+	// not change during a run.
+	boundaries []float64
+	groups     map[string][]uint64
+}
+
+var globalGCPauses = &gcPauseRecorder{groups: make(map[string][]uint64)}
+
+// readGCPauses returns the cumulative pause histogram, or nil when the
+// runtime does not export it or memory capture is off.
+func readGCPauses() *metrics.Float64Histogram {
+	if !memoryCapture {
+		return nil
+	}
+	samples := []metrics.Sample{{Name: gcPauseMetric}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() != metrics.KindFloat64Histogram {
+		return nil
+	}
+	return samples[0].Value.Float64Histogram()
+}
+
+// observe charges the pauses since base to operation.
+func (r *gcPauseRecorder) observe(operation string, base *metrics.Float64Histogram) {
+	now := readGCPauses()
+	if base == nil || now == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.boundaries == nil {
+		r.boundaries = now.Buckets
+	}
+	counts := r.groups[operation]
+	if counts == nil {
+		counts = make([]uint64, len(now.Counts))
+		r.groups[operation] = counts
+	}
+	for i := range now.Counts {
+		counts[i] += now.Counts[i] - base.Counts[i]
+	}
+}
+
+func (r *gcPauseRecorder) snapshot() gcPausesFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := gcPausesFile{Metric: gcPauseMetric, Groups: make(map[string][]gcPauseBucket, len(r.groups))}
+	ops := make([]string, 0, len(r.groups))
+	for op := range r.groups {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		buckets := []gcPauseBucket{}
+		for i, n := range r.groups[op] {
+			if n == 0 {
+				continue
+			}
+			// The outermost boundaries may be infinite.
+			lower, upper := r.boundaries[i], r.boundaries[i+1]
+			if math.IsInf(lower, -1) {
+				lower = 0
+			}
+			if math.IsInf(upper, 1) {
+				upper = lower
+			}
+			buckets = append(buckets, gcPauseBucket{
+				LowerNs: int64(math.Round(lower * 1e9)),
+				UpperNs: int64(math.Round(upper * 1e9)),
+				Count:   n,
+			})
+		}
+		out.Groups[op] = buckets
+	}
+	return out
+}
@@ -0,0 +1,141 @@
+// Package ghcomment posts a pull request comment through the GitHub REST
+// API and keeps it up to date: a hidden marker in the body identifies the
+// comment, so a rerun edits it instead of adding another. It uses net/http
+// directly so the harness keeps a single pinned dependency.
+package ghcomment
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultAPIURL is the REST endpoint of github.com.
+const DefaultAPIURL = "https://api.github.com"
+
+// pageSize is the most comments GitHub returns per page.
+const pageSize = 100
+
+// Client talks to one GitHub API endpoint.
+type Client struct {
+	// APIURL is DefaultAPIURL, or the /api/v3 URL of a GitHub Enterprise
+	// Server.
+	APIURL string
+	Token  string
+	HTTP   *http.Client
+}
+
+// NewClient returns a client for apiURL (DefaultAPIURL when empty).
+func NewClient(apiURL, token string) *Client {
+	if apiURL == "" {
+		apiURL = DefaultAPIURL
+	}
+	return &Client{
+		APIURL: strings.TrimSuffix(apiURL, "/"),
+		Token:  token,
+		HTTP:   &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Marker is the hidden HTML comment identifying the comment of key.
+func Marker(key string) string {
+	return fmt.Sprintf("<!-- aasbench-comment:%s -->", key)
+}
+
+// comment is the part of a GitHub issue comment Upsert needs.
+type comment struct {
+	ID      int64  `json:"id"`
+	Body    string `json:"body"`
+	HTMLURL string `json:"html_url"`
+}
+
+// Upsert posts body, prefixed by Marker(key), on pull request pr of repo
+// ("owner/name"), or edits the comment an earlier call posted there. It
+// returns the comment URL and whether an existing comment was updated.
+func (c *Client) Upsert(repo string, pr int, key, body string) (url string, updated bool, err error) {
+	marker := Marker(key)
+	body = marker + "\n" + body
+
+	existing, err := c.find(repo, pr, marker)
+	if err != nil {
+		return "", false, err
+	}
+	var posted comment
+	if existing != nil {
+		err = c.do(http.MethodPatch, fmt.Sprintf("/repos/%s/issues/comments/%d", repo, existing.ID), body, &posted)
+	} else {
+		err = c.do(http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", repo, pr), body, &posted)
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return posted.HTMLURL, existing != nil, nil
+}
+
+// find returns the first comment on pr whose body carries marker.
+func (c *Client) find(repo string, pr int, marker string) (*comment, error) {
+	for page := 1; ; page++ {
+		var comments []comment
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=%d&page=%d", repo, pr, pageSize, page)
+		if err := c.do(http.MethodGet, path, "", &comments); err != nil {
+			return nil, err
+		}
+		for i := range comments {
+			if strings.Contains(comments[i].Body, marker) {
+				return &comments[i], nil
+			}
+		}
+		if len(comments) < pageSize {
+			return nil, nil
+		}
+	}
+}
+
+// do sends a request with an optional comment body and decodes the JSON
+// response into out.
+func (c *Client) do(method, path, body string, out interface{}) error {
+	var payload io.Reader
+	if body != "" {
+		data, err := json.Marshal(map[string]string{"body": body})
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.APIURL+path, payload)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &apiErr) != nil || apiErr.Message == "" {
+			apiErr.Message = strings.TrimSpace(string(data))
+		}
+		return fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, apiErr.Message)
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,80 @@
+package ghcomment
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeIssue serves the comments of pull request 7 of acme/widgets.
+type fakeIssue struct {
+	comments []comment
+	auth     string
+}
+
+func (f *fakeIssue) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.auth = r.Header.Get("Authorization")
+	var req struct {
+		Body string `json:"body"`
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/acme/widgets/issues/7/comments":
+		page := f.comments
+		if r.URL.Query().Get("page") != "1" {
+			page = nil
+		}
+		json.NewEncoder(w).Encode(page)
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/acme/widgets/issues/7/comments":
+		json.NewDecoder(r.Body).Decode(&req)
+		c := comment{ID: int64(len(f.comments) + 1), Body: req.Body}
+		c.HTMLURL = fmt.Sprintf("https://github.com/acme/widgets/pull/7#issuecomment-%d", c.ID)
+		f.comments = append(f.comments, c)
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(c)
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/repos/acme/widgets/issues/comments/"):
+		json.NewDecoder(r.Body).Decode(&req)
+		for i := range f.comments {
+			if r.URL.Path == fmt.Sprintf("/repos/acme/widgets/issues/comments/%d", f.comments[i].ID) {
+				f.comments[i].Body = req.Body
+				json.NewEncoder(w).Encode(f.comments[i])
+				return
+			}
+		}
+		http.NotFound(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"Not Found"}`))
+	}
+}
+
+func TestUpsertPostsThenUpdates(t *testing.T) {
+	issue := &fakeIssue{comments: []comment{{ID: 1, Body: "LGTM"}}}
+	srv := httptest.NewServer(issue)
+	defer srv.Close()
+	c := NewClient(srv.URL+"/", "s3cret")
+
+	url, updated, err := c.Upsert("acme/widgets", 7, "aas-core3-golang", "first")
+	if err != nil || updated || !strings.HasSuffix(url, "issuecomment-2") {
+		t.Fatalf("first Upsert = %q, %v, %v; want a new comment", url, updated, err)
+	}
+	if issue.auth != "Bearer s3cret" {
+		t.Errorf("Authorization = %q", issue.auth)
+	}
+	if _, updated, err := c.Upsert("acme/widgets", 7, "aas-core3-golang", "second"); err != nil || !updated {
+		t.Fatalf("second Upsert updated = %v, %v; want an edit", updated, err)
+	}
+	if len(issue.comments) != 2 || issue.comments[1].Body != Marker("aas-core3-golang")+"\nsecond" {
+		t.Errorf("comments = %+v", issue.comments)
+	}
+
+	// Another key gets a comment of its own.
+	if _, updated, _ := c.Upsert("acme/widgets", 7, "other-sdk", "x"); updated || len(issue.comments) != 3 {
+		t.Errorf("other key updated = %v with %d comments", updated, len(issue.comments))
+	}
+	if _, _, err := c.Upsert("acme/gadgets", 7, "k", "x"); err == nil || !strings.Contains(err.Error(), "Not Found") {
+		t.Errorf("unknown repo error = %v", err)
+	}
+}
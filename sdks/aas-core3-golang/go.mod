@@ -2,4 +2,7 @@ module github.com/aas-benchmark-observatory/sdks/aas-core3-golang
 
 go 1.22
 
-require github.com/aas-core-works/aas-core3.0-golang v1.0.7
+require (
+	github.com/aas-core-works/aas-core3.0-golang v1.0.7
+	github.com/klauspost/compress v1.18.0
+)
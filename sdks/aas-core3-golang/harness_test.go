@@ -0,0 +1,489 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+	aasverification "github.com/aas-core-works/aas-core3.0-golang/verification"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func TestDatasetFilesFallsBackToEmbedded(t *testing.T) {
+	t.Setenv("DATASETS_DIR", "")
+	var names []string
+	for _, f := range datasetFiles(t) {
+		names = append(names, datasetName(f))
+	}
+	if want := []string{"deep", "mixed", "wide"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("datasets = %v, want %v", names, want)
+	}
+}
+
+func TestMiniDatasetsRoundTrip(t *testing.T) {
+	t.Setenv("DATASETS_DIR", "")
+	for _, f := range datasetFiles(t) {
+		env, err := deserializeEnv(loadRawJSON(t, f))
+		if err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		aasverification.Verify(env, func(e *aasverification.VerificationError) bool {
+			t.Errorf("%s: %s", datasetName(f), e.Error())
+			return false
+		})
+		raw, err := serializeXmlEnv(env)
+		if err != nil {
+			t.Fatalf("%s: %v", f, err)
+		}
+		if _, err := deserializeXmlEnv(raw); err != nil {
+			t.Errorf("%s: XML round trip: %v", f, err)
+		}
+	}
+}
+
+func TestBlobHeavyDatasetOnlyForPayloadOperations(t *testing.T) {
+	t.Setenv("DATASETS_DIR", "")
+	wide := loadRawJSON(t, filepath.Join(datasetsDir(t), "wide.json"))
+	dir := t.TempDir()
+	t.Setenv("DATASETS_DIR", dir)
+	for _, name := range []string{"wide.json", blobHeavyDataset + ".json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), wide, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if files := datasetFiles(t); len(files) != 1 || datasetName(files[0]) != "wide" {
+		t.Errorf("datasetFiles = %v, want wide only", files)
+	}
+	if files := payloadDatasetFiles(t); len(files) != 2 || datasetName(files[1]) != blobHeavyDataset {
+		t.Errorf("payloadDatasetFiles = %v, want wide and %s", files, blobHeavyDataset)
+	}
+	if inputs := datasetXmlInputs(t); len(inputs) != 1 || inputs[0].name != "wide" {
+		t.Errorf("XML inputs = %v, want wide only", inputs)
+	}
+}
+
+func TestDatasetXmlInputsPrefersXmlFixture(t *testing.T) {
+	t.Setenv("DATASETS_DIR", "")
+	mini := datasetsDir(t)
+	dir := t.TempDir()
+	t.Setenv("DATASETS_DIR", dir)
+
+	deep := loadRawJSON(t, filepath.Join(mini, "deep.json"))
+	fixture := []byte(`<?xml version="1.0"?><environment xmlns="https://admin-shell.io/aas/3/0"></environment>`)
+	for name, data := range map[string][]byte{"deep.json": deep, "deep.xml": fixture, "wide.json": deep} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	inputs := datasetXmlInputs(t)
+	if len(inputs) != 2 || inputs[0].name != "deep" || inputs[1].name != "wide" {
+		t.Fatalf("inputs = %v", inputs)
+	}
+	if string(inputs[0].raw) != string(fixture) {
+		t.Error("deep should use the XML fixture, not a conversion")
+	}
+	if _, err := deserializeXmlEnv(inputs[1].raw); err != nil {
+		t.Errorf("derived XML for wide does not parse: %v", err)
+	}
+}
+
+func TestDeserializeReportsCorruptInput(t *testing.T) {
+	if _, err := deserializeEnv([]byte(`{"submodels": [`)); err == nil {
+		t.Error("truncated JSON was accepted")
+	}
+	if _, err := deserializeEnv([]byte(`{"submodels": [{"modelType": "Property"}]}`)); err == nil {
+		t.Error("invalid environment was accepted")
+	}
+	if _, err := deserializeXmlEnv([]byte(`<submodel xmlns="https://admin-shell.io/aas/3/0"/>`)); err == nil {
+		t.Error("non-environment XML was accepted")
+	}
+}
+
+func TestWriteSideChannel(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	writeSideChannel(dir, "memory_stats.json", memoryStatsFile{Groups: map[string]memorySnapshot{"deserialize": {NumGC: 3}}})
+
+	data, err := os.ReadFile(filepath.Join(dir, "memory_stats.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got memoryStatsFile
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Groups["deserialize"].NumGC != 3 {
+		t.Errorf("groups = %+v", got.Groups)
+	}
+
+	// An unwritable destination only warns; benchmark results must survive.
+	blocker := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(blocker, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	writeSideChannel(blocker, "events.json", struct{}{})
+}
+
+func TestCollectBuildInfoFindsSDK(t *testing.T) {
+	info := collectBuildInfo()
+	if info.SDKVersion == "" || info.SDKVersion == "(devel)" {
+		t.Errorf("sdk_version = %q, want the linked %s version", info.SDKVersion, sdkAdapter.Module())
+	}
+	if info.SDKModule != sdkAdapter.Module() {
+		t.Errorf("sdk_module = %q, want %q", info.SDKModule, sdkAdapter.Module())
+	}
+	if info.GoVersion == "" {
+		t.Error("go_version is empty")
+	}
+}
+
+func TestDiffPatchRoundTrip(t *testing.T) {
+	f := filepath.Join(datasetsDir(t), "mixed.json")
+	original, edited := deltaPair(t, "mixed", loadRawJSON(t, f))
+
+	delta := diffEnvironments(original, edited)
+	if len(delta) == 0 {
+		t.Fatal("diff of edited environment is empty")
+	}
+	idx := indexEnvironment(original)
+	kinds := make(map[string]bool)
+	for _, op := range delta {
+		if op.Kind != deltaModify {
+			t.Fatalf("unexpected %s at %s for a value-only edit", op.Kind, op.Path)
+		}
+		kinds[fmt.Sprintf("%T", idx[op.Path])] = true
+	}
+	if len(kinds) < 2 {
+		t.Errorf("diff only covers %v, want changes to several element kinds", kinds)
+	}
+
+	undo, err := patchEnvironment(idx, delta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rest := diffEnvironments(original, edited); len(rest) != 0 {
+		t.Errorf("patched environment still differs: %+v", rest[0])
+	}
+	if _, err := patchEnvironment(idx, undo); err != nil {
+		t.Fatal(err)
+	}
+	if again := diffEnvironments(original, edited); len(again) != len(delta) {
+		t.Errorf("undo left %d differences, want %d", len(again), len(delta))
+	}
+}
+
+func TestIndexEnvironmentCoversEveryProperty(t *testing.T) {
+	env, err := deserializeEnv(loadRawJSON(t, filepath.Join(datasetsDir(t), "deep.json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	idx := indexEnvironment(env)
+	indexed := make(map[aastypes.IClass]bool, len(idx))
+	for _, el := range idx {
+		indexed[el] = true
+	}
+	env.Descend(func(node aastypes.IClass) bool {
+		if _, ok := node.(aastypes.IProperty); ok && !indexed[node] {
+			t.Errorf("property %v is not indexed", node.(aastypes.IProperty).IDShort())
+		}
+		return false
+	})
+	if keys := lookupKeys(idx); len(keys) != len(idx) {
+		t.Errorf("lookupKeys returned %d keys for %d entries", len(keys), len(idx))
+	}
+}
+
+func TestMergeRestoresSplitEnvironment(t *testing.T) {
+	env, err := deserializeEnv(loadRawJSON(t, filepath.Join(datasetsDir(t), "mixed.json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	docs, err := splitEnvironment(env, mergePartCount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := make([]aastypes.IEnvironment, len(docs))
+	for i, doc := range docs {
+		if parts[i], err = deserializeEnv(doc); err != nil {
+			t.Fatalf("part %d: %v", i, err)
+		}
+	}
+	merged := mergeEnvironments(parts)
+	if got, want := len(merged.AssetAdministrationShells()), len(env.AssetAdministrationShells()); got != want {
+		t.Errorf("merged %d shells, want %d", got, want)
+	}
+	if got, want := len(merged.ConceptDescriptions()), len(env.ConceptDescriptions()); got != want {
+		t.Errorf("merged %d concept descriptions, want %d", got, want)
+	}
+	if delta := diffEnvironments(env, merged); len(delta) != 0 {
+		t.Errorf("merged environment differs from the original: %+v", delta[0])
+	}
+}
+
+func TestMergeKeepsIdsSharedAcrossKinds(t *testing.T) {
+	const id = "urn:example:shared"
+	sm := aastypes.NewSubmodel(id)
+	cd := aastypes.NewConceptDescription(id)
+	first := aastypes.NewEnvironment()
+	first.SetSubmodels([]aastypes.ISubmodel{sm})
+	second := aastypes.NewEnvironment()
+	second.SetSubmodels([]aastypes.ISubmodel{aastypes.NewSubmodel(id)})
+	second.SetConceptDescriptions([]aastypes.IConceptDescription{cd})
+
+	merged := mergeEnvironments([]aastypes.IEnvironment{first, second})
+	if got := merged.Submodels(); len(got) != 1 || got[0] != sm {
+		t.Errorf("merged submodels = %v, want the first copy only", got)
+	}
+	if got := merged.ConceptDescriptions(); len(got) != 1 || got[0] != cd {
+		t.Errorf("merged concept descriptions = %v, want the one sharing the submodel's id", got)
+	}
+}
+
+func TestFilterKeepsSelectedSubmodelsAsCopies(t *testing.T) {
+	env, err := deserializeEnv(loadRawJSON(t, filepath.Join(datasetsDir(t), "mixed.json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagSubmodelSemanticIDs(env)
+	if err := checkFiltered(env); err != nil {
+		t.Fatal(err)
+	}
+	filtered, err := filterEnvironment(env, filterSemanticIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := env.Submodels()[0]
+	kept := filtered.Submodels()[0]
+	if kept == original || kept.ID() != original.ID() {
+		t.Errorf("kept submodel %q is not a copy of %q", kept.ID(), original.ID())
+	}
+	shells := filtered.AssetAdministrationShells()
+	if len(shells) != 1 || len(shells[0].Submodels()) != len(filtered.Submodels()) {
+		t.Fatalf("filtered shells = %d, want one referencing the %d kept submodels", len(shells), len(filtered.Submodels()))
+	}
+	if got := shells[0].Submodels()[0].Keys()[0].Value(); got != kept.ID() {
+		t.Errorf("filtered shell references %q, want %q", got, kept.ID())
+	}
+	if len(env.AssetAdministrationShells()[0].Submodels()) != len(env.Submodels()) {
+		t.Error("filtering pruned the original shell's references")
+	}
+}
+
+func TestCanonicalJSONFollowsRFC8785(t *testing.T) {
+	// The key order and numbers of RFC 8785, sections 3.2.3 and 3.2.2.3.
+	doc := map[string]interface{}{
+		"\u20AC": "Euro Sign", "\r": "Carriage Return", "\uFB33": "Hebrew Letter Dalet With Dagesh",
+		"1": "One", "\U0001F600": "Emoji: Grinning Face", "\u0080": "Control", "\u00F6": "Latin Small Letter O With Diaeresis",
+		"numbers": []interface{}{math.Copysign(0, -1), 1e21, 1e-7, 333333333.3333333, 1e-6, 9007199254740992.0, 5e-324, -1e23},
+		"escapes": "<&>\u2028\x1f\"\\\n\x7f",
+		"flags":   []interface{}{true, nil},
+	}
+	got, err := appendCanonical(nil, doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"\r":"Carriage Return","1":"One",` +
+		`"escapes":"<&>` + "\u2028" + `\u001f\"\\\n` + "\x7f" + `","flags":[true,null],` +
+		`"numbers":[0,1e+21,1e-7,333333333.3333333,0.000001,9007199254740992,5e-324,-1e+23],` +
+		"\"\u0080\":\"Control\",\"\u00F6\":\"Latin Small Letter O With Diaeresis\"," +
+		"\"\u20AC\":\"Euro Sign\",\"\U0001F600\":\"Emoji: Grinning Face\",\"\uFB33\":\"Hebrew Letter Dalet With Dagesh\"}"
+	if string(got) != want {
+		t.Errorf("canonical JSON =\n%q\nwant\n%q", got, want)
+	}
+	if _, err := appendCanonical(nil, "\xff"); err == nil {
+		t.Error("invalid UTF-8 was canonicalized")
+	}
+
+	raw := loadRawJSON(t, filepath.Join(datasetsDir(t), "mixed.json"))
+	if _, err := setupCanonicalize(raw); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMeasureOutputSizeCompresses(t *testing.T) {
+	doc := loadRawJSON(t, filepath.Join(datasetsDir(t), "wide.json"))
+	size, err := measureOutputSize(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size.Bytes != int64(len(doc)) {
+		t.Errorf("bytes = %d, want %d", size.Bytes, len(doc))
+	}
+	// The repetitive dataset shrinks under either compressor.
+	if size.GzipBytes <= 0 || size.GzipBytes >= size.Bytes || size.ZstdBytes <= 0 || size.ZstdBytes >= size.Bytes {
+		t.Errorf("compressed %d bytes to gzip %d and zstd %d", size.Bytes, size.GzipBytes, size.ZstdBytes)
+	}
+}
+
+func TestHashAndEqualsDetectChanges(t *testing.T) {
+	raw := loadRawJSON(t, filepath.Join(datasetsDir(t), "deep.json"))
+	original, edited := deltaPair(t, "deep", raw)
+	copied, err := deserializeEnv(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hasher := newStructuralHasher()
+	if !environmentsEqual(original, copied) || hasher.sum(original) != hasher.sum(copied) {
+		t.Error("two copies of the same dataset differ")
+	}
+	if environmentsEqual(original, edited) || hasher.sum(original) == hasher.sum(edited) {
+		t.Error("an edited copy compares equal")
+	}
+}
+
+func TestCorruptInputsAreRejected(t *testing.T) {
+	raw := loadRawJSON(t, filepath.Join(datasetsDir(t), "mixed.json"))
+	inputs, err := corruptJSON(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := deserializeEnv(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	xmlRaw, err := serializeXmlEnv(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs = append(inputs, corruptXML(xmlRaw)...)
+
+	classes := make(map[string]bool)
+	for _, in := range inputs {
+		classes[in.format+"/"+in.class] = true
+		if outcome, _ := in.probe(); outcome != outcomeRejected {
+			t.Errorf("%s/%s was %s, want rejected", in.format, in.class, outcome)
+		}
+	}
+	for _, format := range []string{"json", "xml"} {
+		for _, class := range []string{corruptMissingRequired, corruptWrongType, corruptTruncated} {
+			if !classes[format+"/"+class] {
+				t.Errorf("no %s input for %s", format, class)
+			}
+		}
+	}
+}
+
+func TestNsHeavyXMLDeserializesUnchanged(t *testing.T) {
+	env, err := deserializeEnv(loadRawJSON(t, filepath.Join(datasetsDir(t), "deep.json")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, err := serializeXmlEnv(env)
+	if err != nil {
+		t.Fatal(err)
+	}
+	heavy, err := nsHeavyXML(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`xmlns:aas3=`, `<aas:`, `<n3:`, `xmlns:xsi=`} {
+		if !bytes.Contains(heavy, []byte(want)) {
+			t.Errorf("rewritten document lacks %s", want)
+		}
+	}
+	got, err := deserializeXmlEnv(heavy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !environmentsEqual(env, got) {
+		t.Error("the namespace-heavy document deserializes differently")
+	}
+}
+
+func TestStartupProbeDeserializes(t *testing.T) {
+	wait, err := startProbe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegistryRunsCoreOperations(t *testing.T) {
+	m := registeredOperations()
+	declared := make(map[string]bool)
+	for _, op := range m.Operations {
+		for _, track := range op.Tracks {
+			if track == report.TrackCore {
+				declared[op.ID] = true
+			}
+		}
+	}
+	if !reflect.DeepEqual(declared, report.CoreOperations) {
+		t.Errorf("registry declares core %v, report expects %v", declared, report.CoreOperations)
+	}
+
+	raw := loadRawJSON(t, filepath.Join(datasetsDir(t), "mixed.json"))
+	for _, op := range operationRegistry {
+		input, err := op.Setup(raw)
+		if err != nil {
+			t.Fatalf("%s: setup: %v", op.ID, err)
+		}
+		if err := op.Run(input); err != nil {
+			t.Errorf("%s: %v", op.ID, err)
+		}
+	}
+}
+
+func TestEnergyMeterReadsPowercap(t *testing.T) {
+	root := t.TempDir()
+	zone := func(id, name, energy string) {
+		dir := filepath.Join(root, id)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for file, content := range map[string]string{"name": name, "max_energy_range_uj": "1000000", "energy_uj": energy} {
+			if err := os.WriteFile(filepath.Join(dir, file), []byte(content+"\n"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	zone("intel-rapl:0", "package-0", "999000")
+	zone("intel-rapl:0:0", "dram", "100")
+	domains, err := findRAPLDomains(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(domains) != 2 || domains[1].name != "package-0/dram" || domains[1].top {
+		t.Fatalf("domains = %+v", domains)
+	}
+
+	m := &energyMeter{groups: make(map[string]energyGroup)}
+	// The package counter wrapped: 999000 -> 3000 is 4000 µJ.
+	m.add("validate", 10, time.Second, domains, []uint64{999000, 100}, []uint64{3000, 600})
+	g := m.snapshot().Groups["validate"]
+	if g.TotalJoules != 0.004 || g.Joules["package-0/dram"] != 0.0005 || g.Iterations != 10 {
+		t.Errorf("validate = %+v", g)
+	}
+}
+
+func TestSelectionFollowsRegisteredNeeds(t *testing.T) {
+	s, err := newSelection("update, validation", "wide")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"update", "deserialize", "validate", "validate_first_error"} {
+		if !s.operation(id) {
+			t.Errorf("%s not selected", id)
+		}
+	}
+	if s.operation("serialize") || s.operation("deserialize_xml") {
+		t.Errorf("selection = %v, want only update, the validation track and their needs", s.operations)
+	}
+	if !s.dataset("wide") || s.dataset("deep") || !s.dataset(report.MicroDatasetPrefix+"lang_string") {
+		t.Errorf("datasets = %v", s.datasets)
+	}
+	if _, err := newSelection("fast", ""); err == nil {
+		t.Error("want an error for an unknown track")
+	}
+}
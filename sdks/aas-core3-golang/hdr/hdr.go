@@ -0,0 +1,224 @@
+// Package hdr records latencies in HdrHistogram-style histograms: buckets
+// of a fixed number of significant digits on a log-linear scale, so a full
+// distribution fits in a few kilobytes at any range and percentiles keep
+// their relative precision. Means and medians hide bimodal latencies such
+// as GC pauses; the whole histogram does not.
+package hdr
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/bits"
+)
+
+// DefaultSignificantDigits keeps every recorded value within 0.1%.
+const DefaultSignificantDigits = 3
+
+// Histogram counts values of at least 1 at a precision of its significant
+// digits. Values below 1 are recorded as 1.
+type Histogram struct {
+	significantDigits int
+	// subBucketHalfCountMagnitude is log2 of half the sub-buckets per
+	// bucket; the first bucket covers [0, 2*half) linearly and every
+	// further bucket doubles the range at the same sub-bucket count.
+	subBucketHalfCountMagnitude uint
+	subBucketHalfCount          int64
+	subBucketMask               int64
+
+	counts []int64
+	total  int64
+	min    int64
+	max    int64
+	sum    float64
+}
+
+// New returns an empty histogram keeping significantDigits (1 to 5)
+// significant digits.
+func New(significantDigits int) (*Histogram, error) {
+	if significantDigits < 1 || significantDigits > 5 {
+		return nil, fmt.Errorf("significant digits %d out of range 1-5", significantDigits)
+	}
+	largest := 2 * int64(math.Pow10(significantDigits))
+	subBucketCountMagnitude := uint(math.Ceil(math.Log2(float64(largest))))
+	h := &Histogram{
+		significantDigits:           significantDigits,
+		subBucketHalfCountMagnitude: subBucketCountMagnitude - 1,
+		subBucketHalfCount:          1 << (subBucketCountMagnitude - 1),
+		subBucketMask:               1<<subBucketCountMagnitude - 1,
+		min:                         math.MaxInt64,
+	}
+	return h, nil
+}
+
+// SignificantDigits returns the precision h was created with.
+func (h *Histogram) SignificantDigits() int { return h.significantDigits }
+
+// Count returns the number of recorded values.
+func (h *Histogram) Count() int64 { return h.total }
+
+// Min returns the smallest recorded value, or 0 when h is empty.
+func (h *Histogram) Min() int64 {
+	if h.total == 0 {
+		return 0
+	}
+	return h.min
+}
+
+// Max returns the largest recorded value.
+func (h *Histogram) Max() int64 { return h.max }
+
+// Mean returns the mean of the recorded values, exact rather than bucketed.
+func (h *Histogram) Mean() float64 {
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / float64(h.total)
+}
+
+// Record adds v.
+func (h *Histogram) Record(v int64) { h.RecordN(v, 1) }
+
+// RecordN adds v n times.
+func (h *Histogram) RecordN(v, n int64) {
+	if n <= 0 {
+		return
+	}
+	if v < 1 {
+		v = 1
+	}
+	h.addAt(h.countsIndex(v), n)
+	h.sum += float64(v) * float64(n)
+	h.min = min(h.min, v)
+	h.max = max(h.max, v)
+}
+
+// Merge adds every value of other, which must have the same precision.
+func (h *Histogram) Merge(other *Histogram) error {
+	if other.significantDigits != h.significantDigits {
+		return fmt.Errorf("cannot merge %d into %d significant digits", other.significantDigits, h.significantDigits)
+	}
+	for i, c := range other.counts {
+		if c > 0 {
+			h.addAt(i, c)
+		}
+	}
+	h.sum += other.sum
+	if other.total > 0 {
+		h.min = min(h.min, other.min)
+		h.max = max(h.max, other.max)
+	}
+	return nil
+}
+
+// ValueAtQuantile returns the value below which a fraction q (0 to 1) of
+// the recorded values fall, as the highest value of its bucket.
+func (h *Histogram) ValueAtQuantile(q float64) int64 {
+	if h.total == 0 {
+		return 0
+	}
+	q = math.Min(math.Max(q, 0), 1)
+	target := int64(q*float64(h.total) + 0.5)
+	if target < 1 {
+		target = 1
+	}
+	var seen int64
+	for i, c := range h.counts {
+		seen += c
+		if seen >= target {
+			return min(h.highestEquivalent(h.valueAt(i)), h.max)
+		}
+	}
+	return h.max
+}
+
+// bucketIndex and subBucketIndex locate v: the power of two range it falls
+// in, and its linear slot there.
+func (h *Histogram) bucketIndex(v int64) int {
+	pow2Ceiling := 64 - bits.LeadingZeros64(uint64(v|h.subBucketMask))
+	return pow2Ceiling - int(h.subBucketHalfCountMagnitude+1)
+}
+
+func (h *Histogram) countsIndex(v int64) int {
+	bucket := h.bucketIndex(v)
+	sub := v >> uint(bucket)
+	return (bucket+1)<<h.subBucketHalfCountMagnitude + int(sub-h.subBucketHalfCount)
+}
+
+// valueAt is the lowest value counted at index i.
+func (h *Histogram) valueAt(i int) int64 {
+	bucket := i>>h.subBucketHalfCountMagnitude - 1
+	sub := int64(i)&(h.subBucketHalfCount-1) + h.subBucketHalfCount
+	if bucket < 0 {
+		sub -= h.subBucketHalfCount
+		bucket = 0
+	}
+	return sub << uint(bucket)
+}
+
+// highestEquivalent is the highest value counted in the same slot as v.
+func (h *Histogram) highestEquivalent(v int64) int64 {
+	bucket := uint(h.bucketIndex(v))
+	return v>>bucket<<bucket + 1<<bucket - 1
+}
+
+func (h *Histogram) addAt(i int, c int64) {
+	if i >= len(h.counts) {
+		grown := make([]int64, i+1)
+		copy(grown, h.counts)
+		h.counts = grown
+	}
+	h.counts[i] += c
+	h.total += c
+}
+
+// Encode returns h's non-empty slots as a compact base64 string: pairs of
+// uvarints, the index delta from the previous slot and its count.
+func (h *Histogram) Encode() string {
+	buf := make([]byte, 0, 64)
+	prev := 0
+	for i, c := range h.counts {
+		if c == 0 {
+			continue
+		}
+		buf = binary.AppendUvarint(buf, uint64(i-prev))
+		buf = binary.AppendUvarint(buf, uint64(c))
+		prev = i
+	}
+	return base64.RawStdEncoding.EncodeToString(buf)
+}
+
+// Decode rebuilds a histogram of significantDigits precision from the
+// output of Encode. Min, max and mean are those of the slots, not of the
+// original values.
+func Decode(encoded string, significantDigits int) (*Histogram, error) {
+	h, err := New(significantDigits)
+	if err != nil {
+		return nil, err
+	}
+	buf, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	i := 0
+	for len(buf) > 0 {
+		delta, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return nil, errors.New("truncated histogram index")
+		}
+		buf = buf[n:]
+		c, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return nil, errors.New("truncated histogram count")
+		}
+		buf = buf[n:]
+		i += int(delta)
+		h.addAt(i, int64(c))
+		h.sum += float64(h.valueAt(i)) * float64(c)
+		h.min = min(h.min, h.valueAt(i))
+		h.max = max(h.max, h.highestEquivalent(h.valueAt(i)))
+	}
+	return h, nil
+}
@@ -0,0 +1,91 @@
+package hdr
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestQuantilesKeepTheirPrecision(t *testing.T) {
+	h, err := New(DefaultSignificantDigits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A bimodal distribution: 9,900 fast calls and 100 GC-paused ones.
+	for v := int64(1); v <= 9900; v++ {
+		h.Record(1000 + v%100)
+	}
+	for v := int64(0); v < 100; v++ {
+		h.Record(50_000_000 + v*1000)
+	}
+
+	if h.Count() != 10000 || h.Min() != 1000 || h.Max() != 50_099_000 {
+		t.Fatalf("count/min/max = %d/%d/%d", h.Count(), h.Min(), h.Max())
+	}
+	within := func(got, want int64) bool {
+		return math.Abs(float64(got-want)) <= float64(want)*0.001+1
+	}
+	if p50 := h.ValueAtQuantile(0.5); !within(p50, 1050) {
+		t.Errorf("p50 = %d, want about 1050", p50)
+	}
+	if p99 := h.ValueAtQuantile(0.99); !within(p99, 1099) {
+		t.Errorf("p99 = %d, want about 1099, the last fast call", p99)
+	}
+	if p999 := h.ValueAtQuantile(0.999); !within(p999, 50_089_000) {
+		t.Errorf("p99.9 = %d, want about 50089000, in the paused mode", p999)
+	}
+}
+
+func TestEncodeRoundTrips(t *testing.T) {
+	h, _ := New(DefaultSignificantDigits)
+	for _, v := range []int64{0, 1, 7, 2047, 2048, 123_456, 9_999_999_999} {
+		h.RecordN(v, 3)
+	}
+	decoded, err := Decode(h.Encode(), DefaultSignificantDigits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Count() != h.Count() {
+		t.Fatalf("decoded count = %d, want %d", decoded.Count(), h.Count())
+	}
+	// The decoded maximum is its slot's highest value, so the top
+	// quantiles may move within the precision.
+	for _, q := range []float64{0, 0.25, 0.5, 0.9, 0.99, 1} {
+		got, want := decoded.ValueAtQuantile(q), h.ValueAtQuantile(q)
+		if math.Abs(float64(got-want)) > float64(want)*0.001 {
+			t.Errorf("q%.2f = %d after decoding, want %d", q, got, want)
+		}
+	}
+	if len(h.Encode()) > 64 {
+		t.Errorf("encoding of 7 slots is %d bytes", len(h.Encode()))
+	}
+
+	other, _ := New(2)
+	if err := h.Merge(other); err == nil {
+		t.Error("merged histograms of different precision")
+	}
+}
+
+func TestReadK6GroupsRequestsByOperation(t *testing.T) {
+	stream := strings.Join([]string{
+		`{"type":"Metric","metric":"http_req_duration","data":{"type":"trend"}}`,
+		`{"type":"Point","metric":"http_req_duration","data":{"value":1.5,"tags":{"group":"::Read AAS Shell","method":"GET","name":"http://localhost:8081/shells/dXJu","expected_response":"true"}}}`,
+		`{"type":"Point","metric":"http_req_duration","data":{"value":2.5,"tags":{"group":"::Read AAS Shell","method":"GET","name":"http://localhost:8081/shells/eHl6","expected_response":"false"}}}`,
+		`{"type":"Point","metric":"http_req_duration","data":{"value":12,"tags":{"group":"","method":"GET","name":"http://localhost:8081/shells","scenario":"load"}}}`,
+		`{"type":"Point","metric":"http_reqs","data":{"value":1,"tags":{"method":"GET"}}}`,
+	}, "\n")
+	file, err := ReadK6(strings.NewReader(stream), DefaultSignificantDigits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := file.Operations["Read AAS Shell"]
+	if read.Count != 2 || read.Errors != 1 || read.Min != 1500 || read.Max != 2500 {
+		t.Errorf("Read AAS Shell = %+v, want 2 samples of 1500-2500us with 1 error", read)
+	}
+	if list := file.Operations["load/GET /shells"]; list.Count != 1 || list.P999 != 12000 {
+		t.Errorf("load/GET /shells = %+v", list)
+	}
+	if len(file.Operations) != 2 {
+		t.Errorf("operations = %v", file.Operations)
+	}
+}
@@ -0,0 +1,124 @@
+package hdr
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"strings"
+)
+
+// Summary is one operation's entry of a histograms file: the percentiles
+// read from its histogram, and the histogram itself as Encode returns it.
+// Values are in the file's unit.
+type Summary struct {
+	Count     int64   `json:"count"`
+	Errors    int64   `json:"errors"`
+	Min       int64   `json:"min"`
+	Mean      float64 `json:"mean"`
+	P50       int64   `json:"p50"`
+	P90       int64   `json:"p90"`
+	P99       int64   `json:"p99"`
+	P999      int64   `json:"p999"`
+	Max       int64   `json:"max"`
+	Histogram string  `json:"histogram"`
+}
+
+// File is a histograms companion file, keyed by operation.
+type File struct {
+	Unit              string             `json:"unit"`
+	SignificantDigits int                `json:"significant_digits"`
+	Operations        map[string]Summary `json:"operations"`
+}
+
+// Summarize reads the percentiles of h.
+func Summarize(h *Histogram, errors int64) Summary {
+	return Summary{
+		Count:     h.Count(),
+		Errors:    errors,
+		Min:       h.Min(),
+		Mean:      h.Mean(),
+		P50:       h.ValueAtQuantile(0.50),
+		P90:       h.ValueAtQuantile(0.90),
+		P99:       h.ValueAtQuantile(0.99),
+		P999:      h.ValueAtQuantile(0.999),
+		Max:       h.Max(),
+		Histogram: h.Encode(),
+	}
+}
+
+// k6Point is one line of k6's --out json stream that carries a sample.
+type k6Point struct {
+	Type   string `json:"type"`
+	Metric string `json:"metric"`
+	Data   struct {
+		Value float64           `json:"value"`
+		Tags  map[string]string `json:"tags"`
+	} `json:"data"`
+}
+
+// K6Operation names the operation of a k6 request sample: its group, or
+// else its method and URL path, prefixed with its scenario unless that is
+// the default one.
+func K6Operation(tags map[string]string) string {
+	op := strings.TrimPrefix(tags["group"], "::")
+	if op == "" {
+		path := tags["name"]
+		if u, err := url.Parse(path); err == nil && u.Path != "" {
+			path = u.Path
+		}
+		op = strings.TrimSpace(tags["method"] + " " + path)
+	}
+	if scenario := tags["scenario"]; scenario != "" && scenario != "default" {
+		op = scenario + "/" + op
+	}
+	return op
+}
+
+// ReadK6 records the http_req_duration samples of a k6 --out json stream
+// in one histogram per operation, in microseconds. Samples k6 did not
+// count as an expected response are recorded too, and counted as errors.
+func ReadK6(r io.Reader, significantDigits int) (File, error) {
+	hists := make(map[string]*Histogram)
+	errors := make(map[string]int64)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var p k6Point
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			return File{}, fmt.Errorf("line %d: %w", line, err)
+		}
+		if p.Type != "Point" || p.Metric != "http_req_duration" {
+			continue
+		}
+		op := K6Operation(p.Data.Tags)
+		h := hists[op]
+		if h == nil {
+			var err error
+			if h, err = New(significantDigits); err != nil {
+				return File{}, err
+			}
+			hists[op] = h
+		}
+		h.Record(int64(math.Round(p.Data.Value * 1000)))
+		if p.Data.Tags["expected_response"] == "false" {
+			errors[op]++
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return File{}, err
+	}
+	file := File{Unit: "us", SignificantDigits: significantDigits, Operations: make(map[string]Summary, len(hists))}
+	for op, h := range hists {
+		file.Operations[op] = Summarize(h, errors[op])
+	}
+	return file, nil
+}
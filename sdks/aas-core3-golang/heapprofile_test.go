@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/profile"
+)
+
+// heapHotspotCount is the number of allocation sites kept per group.
+const heapHotspotCount = 10
+
+// heapHotspotsFile is the schema written to heap_hotspots.json. Sites and
+// Iterations let two runs be diffed per operation.
+type heapHotspotsFile struct {
+	TopN       int                       `json:"top_n"`
+	Groups     map[string][]profile.Site `json:"groups"`
+	Sites      map[string][]profile.Site `json:"sites"`
+	Iterations map[string]int64          `json:"iterations"`
+}
+
+// heapProfiler attributes sampled heap allocations to operation groups.
+// Enabled with HEAP_PROFILE=1. It forces a GC around every sub-benchmark, so
+// memory figures from a profiled run should not be compared with plain runs.
+type heapProfiler struct {
+	enabled bool
+	groups  map[string]profile.Sites
+	// iterations counts every benchmark iteration run inside the observed
+	// windows, including b.N ramp-up, so sites can be expressed per op.
+	iterations map[string]int64
+}
+
+var globalHeap = &heapProfiler{
+	enabled:    os.Getenv("HEAP_PROFILE") != "",
+	groups:     make(map[string]profile.Sites),
+	iterations: make(map[string]int64),
+}
+
+// profileBytes returns the current heap profile in pprof protobuf format.
+// Allocation counters are only published at the end of a GC cycle.
+func (h *heapProfiler) profileBytes() ([]byte, error) {
+	runtime.GC()
+	var buf bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&buf, 0); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// snapshot returns cumulative allocation sites, or nil when disabled.
+func (h *heapProfiler) snapshot() profile.Sites {
+	if !h.enabled {
+		return nil
+	}
+	data, err := h.profileBytes()
+	if err != nil {
+		slog.Warn("failed to capture heap profile", "err", err)
+		return nil
+	}
+	p, err := profile.Parse(data)
+	if err != nil {
+		slog.Warn("failed to parse heap profile", "err", err)
+		return nil
+	}
+	sites, err := p.AllocationSites()
+	if err != nil {
+		slog.Warn("failed to read allocation sites", "err", err)
+		return nil
+	}
+	return sites
+}
+
+// observe charges allocations made since base to the operation group. Only
+// the timed sub-benchmark runs are bracketed, so dataset setup is excluded.
+func (h *heapProfiler) observe(operation string, base profile.Sites) {
+	if base == nil {
+		return
+	}
+	after := h.snapshot()
+	if after == nil {
+		return
+	}
+	acc, ok := h.groups[operation]
+	if !ok {
+		acc = make(profile.Sites)
+		h.groups[operation] = acc
+	}
+	acc.Add(after.Sub(base))
+}
+
+// count wraps a sub-benchmark body so its iterations are charged to the
+// operation group.
+func (h *heapProfiler) count(operation string, fn func(b *testing.B)) func(b *testing.B) {
+	if !h.enabled {
+		return fn
+	}
+	return func(b *testing.B) {
+		h.iterations[operation] += int64(b.N)
+		fn(b)
+	}
+}
+
+// writeProfile stores the raw heap profile as OUTPUT_DIR/<operation>.heap.pprof
+// for inspection with `go tool pprof -sample_index=alloc_space`.
+func (h *heapProfiler) writeProfile(operation string) {
+	outputDir := os.Getenv("OUTPUT_DIR")
+	if !h.enabled || outputDir == "" {
+		return
+	}
+	data, err := h.profileBytes()
+	if err != nil {
+		slog.Warn("failed to capture heap profile", "err", err)
+		return
+	}
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		slog.Warn("failed to create output dir", "err", err)
+		return
+	}
+	path := filepath.Join(outputDir, operation+".heap.pprof")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		slog.Warn("failed to write heap profile", "path", path, "err", err)
+	}
+}
+
+func (h *heapProfiler) hotspots() heapHotspotsFile {
+	out := heapHotspotsFile{
+		TopN:       heapHotspotCount,
+		Groups:     make(map[string][]profile.Site),
+		Sites:      make(map[string][]profile.Site),
+		Iterations: h.iterations,
+	}
+	for op, sites := range h.groups {
+		out.Groups[op] = sites.Top(heapHotspotCount)
+		out.Sites[op] = sites.Top(-1)
+	}
+	return out
+}
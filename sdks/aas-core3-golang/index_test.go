@@ -0,0 +1,127 @@
+package main
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"testing"
+
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+)
+
+// indexLookupSeed fixes the lookup order so runs are comparable.
+const indexLookupSeed = 1
+
+// elementIndex maps element paths to elements: an identifiable's id, or
+// "<submodel id>/<idShort path>" for a submodel element, with "[i]" for
+// the i-th element of a SubmodelElementList.
+type elementIndex map[string]aastypes.IClass
+
+// indexEnvironment indexes every identifiable by id and every submodel
+// element by its idShort path below its submodel.
+func indexEnvironment(env aastypes.IEnvironment) elementIndex {
+	idx := make(elementIndex)
+	for _, shell := range env.AssetAdministrationShells() {
+		idx[shell.ID()] = shell
+	}
+	for _, cd := range env.ConceptDescriptions() {
+		idx[cd.ID()] = cd
+	}
+	for _, sm := range env.Submodels() {
+		idx[sm.ID()] = sm
+		indexElements(idx, sm.ID(), sm.SubmodelElements())
+	}
+	return idx
+}
+
+func indexElements(idx elementIndex, prefix string, elements []aastypes.ISubmodelElement) {
+	for i, el := range elements {
+		// Elements of a SubmodelElementList have no idShort.
+		segment := "[" + strconv.Itoa(i) + "]"
+		if idShort := el.IDShort(); idShort != nil {
+			segment = "/" + *idShort
+		}
+		path := prefix + segment
+		idx[path] = el
+		indexElements(idx, path, childElements(el))
+	}
+}
+
+// childElements returns the submodel elements nested directly in el.
+func childElements(el aastypes.ISubmodelElement) []aastypes.ISubmodelElement {
+	switch e := el.(type) {
+	case aastypes.ISubmodelElementCollection:
+		return e.Value()
+	case aastypes.ISubmodelElementList:
+		return e.Value()
+	case aastypes.IEntity:
+		return e.Statements()
+	case aastypes.IAnnotatedRelationshipElement:
+		annotations := e.Annotations()
+		out := make([]aastypes.ISubmodelElement, len(annotations))
+		for i, a := range annotations {
+			out[i] = a
+		}
+		return out
+	}
+	return nil
+}
+
+// lookupKeys returns every key of idx in a fixed pseudo-random order.
+func lookupKeys(idx elementIndex) []string {
+	keys := make([]string, 0, len(idx))
+	for k := range idx {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	rng := rand.New(rand.NewSource(indexLookupSeed))
+	rng.Shuffle(len(keys), func(i, j int) { keys[i], keys[j] = keys[j], keys[i] })
+	return keys
+}
+
+// BenchmarkIndexBuild benchmarks building the id and idShort-path index
+// over a deserialized environment.
+func BenchmarkIndexBuild(b *testing.B) {
+	selectOperation(b, "index_build")
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		env, err := deserializeEnv(loadRawJSON(b, f))
+		if err != nil {
+			b.Fatalf("Setup failed for %s: %v", name, err)
+		}
+		runObserved(b, "index_build", name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				idx := indexEnvironment(env)
+				_ = idx
+			}
+		})
+	}
+	globalMemStats.Groups["index_build"] = captureMemSnapshot()
+	globalHeap.writeProfile("index_build")
+}
+
+// BenchmarkIndexLookup benchmarks one lookup per iteration in a built
+// index, cycling through every key in random order.
+func BenchmarkIndexLookup(b *testing.B) {
+	selectOperation(b, "index_lookup")
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		env, err := deserializeEnv(loadRawJSON(b, f))
+		if err != nil {
+			b.Fatalf("Setup failed for %s: %v", name, err)
+		}
+		idx := indexEnvironment(env)
+		keys := lookupKeys(idx)
+		runObserved(b, "index_lookup", name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if idx[keys[i%len(keys)]] == nil {
+					b.Fatalf("lookup of %s failed", keys[i%len(keys)])
+				}
+			}
+		})
+	}
+	globalMemStats.Groups["index_lookup"] = captureMemSnapshot()
+	globalHeap.writeProfile("index_lookup")
+}
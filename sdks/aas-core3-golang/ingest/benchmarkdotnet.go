@@ -0,0 +1,168 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// bdnExport is the part of a BenchmarkDotNet JSON export (full or brief,
+// optionally compressed) the converter reads.
+type bdnExport struct {
+	HostEnvironmentInfo struct {
+		RuntimeVersion   string `json:"RuntimeVersion"`
+		DotNetCliVersion string `json:"DotNetCliVersion"`
+	} `json:"HostEnvironmentInfo"`
+	Benchmarks []bdnBenchmark `json:"Benchmarks"`
+}
+
+type bdnBenchmark struct {
+	FullName   string `json:"FullName"`
+	Method     string `json:"Method"`
+	Parameters string `json:"Parameters"`
+	Statistics *struct {
+		N                 int     `json:"N"`
+		Mean              float64 `json:"Mean"`
+		Median            float64 `json:"Median"`
+		StandardDeviation float64 `json:"StandardDeviation"`
+		Min               float64 `json:"Min"`
+		Max               float64 `json:"Max"`
+	} `json:"Statistics"`
+	Memory *struct {
+		Gen0Collections            *int64 `json:"Gen0Collections"`
+		Gen1Collections            *int64 `json:"Gen1Collections"`
+		Gen2Collections            *int64 `json:"Gen2Collections"`
+		BytesAllocatedPerOperation *int64 `json:"BytesAllocatedPerOperation"`
+	} `json:"Memory"`
+	// Measurements are only in the full export.
+	Measurements []struct {
+		IterationMode  string  `json:"IterationMode"`
+		IterationStage string  `json:"IterationStage"`
+		Operations     float64 `json:"Operations"`
+		Nanoseconds    float64 `json:"Nanoseconds"`
+	} `json:"Measurements"`
+}
+
+// BenchmarkDotNet reads BenchmarkDotNet JSON exports: one file, or every
+// *.json file of a BenchmarkDotNet.Artifacts/results directory. The method
+// names the operation and the Dataset parameter the dataset. Statistics are
+// already per operation in ns. The full export's workload measurements add
+// p75 and p99, and MemoryDiagnoser fills alloc_bytes_per_op and gc_count
+// (collections of all generations).
+func BenchmarkDotNet(path string) (*Result, error) {
+	files := []string{path}
+	if info, err := os.Stat(path); err != nil {
+		return nil, err
+	} else if info.IsDir() {
+		files, _ = filepath.Glob(filepath.Join(path, "*.json"))
+		sort.Strings(files)
+		if len(files) == 0 {
+			return nil, fmt.Errorf("no BenchmarkDotNet JSON export in %s", path)
+		}
+	}
+	r := &Result{Language: "csharp", Harness: "benchmarkdotnet"}
+	for _, file := range files {
+		if err := r.addBenchmarkDotNet(file); err != nil {
+			return nil, err
+		}
+	}
+	if len(r.Datasets) == 0 {
+		return nil, fmt.Errorf("no BenchmarkDotNet benchmarks with statistics in %s", path)
+	}
+	return r, nil
+}
+
+func (r *Result) addBenchmarkDotNet(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var export bdnExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return fmt.Errorf("parse %s: %w", path, err)
+	}
+	if r.RuntimeVersion == "" {
+		host := export.HostEnvironmentInfo
+		switch {
+		case host.RuntimeVersion != "":
+			r.RuntimeVersion = host.RuntimeVersion
+		case host.DotNetCliVersion != "":
+			r.RuntimeVersion = ".NET SDK " + host.DotNetCliVersion
+		}
+	}
+	for _, b := range export.Benchmarks {
+		// Benchmarks that failed to run have no statistics.
+		if b.Statistics == nil {
+			continue
+		}
+		params := bdnParameters(b.Parameters)
+		dataset := params["Dataset"]
+		if dataset == "" {
+			dataset = params["XmlDataset"]
+		}
+		if dataset == "" {
+			return fmt.Errorf("%s: %s has no Dataset parameter", path, b.FullName)
+		}
+		op := bdnOperation(dataset, report.NormalizeOperationID(b.Method), b)
+		if _, dup := r.Datasets[dataset].Operations[op.OperationID]; dup {
+			return fmt.Errorf("%s: more than one benchmark maps to %s/%s", path, op.OperationID, dataset)
+		}
+		r.add(dataset, op)
+	}
+	return nil
+}
+
+// bdnParameters splits a Parameters string such as
+// "Dataset=wide, Format=json" into its values.
+func bdnParameters(s string) map[string]string {
+	params := make(map[string]string)
+	for _, part := range strings.Split(s, ",") {
+		if name, value, ok := strings.Cut(strings.TrimSpace(part), "="); ok {
+			params[name] = value
+		}
+	}
+	return params
+}
+
+func bdnOperation(dataset, operationID string, b bdnBenchmark) report.OperationEntry {
+	stats := b.Statistics
+	op := operation(dataset, operationID, stats.Mean)
+	op.SampleCount = stats.N
+	op.MedianNs = int64(math.Round(stats.Median))
+	op.StddevNs = int64(math.Round(stats.StandardDeviation))
+
+	// The Result stage is the actual workload with the overhead
+	// subtracted, which is what the statistics are computed from.
+	var perOp []float64
+	for _, m := range b.Measurements {
+		if m.IterationMode == "Workload" && m.IterationStage == "Result" && m.Operations > 0 {
+			perOp = append(perOp, m.Nanoseconds/m.Operations)
+			op.Iterations += int(m.Operations)
+		}
+	}
+	setSampleStats(&op, perOp)
+	op.MinNs = int64(math.Round(stats.Min))
+	op.MaxNs = int64(math.Round(stats.Max))
+
+	if mem := b.Memory; mem != nil {
+		op.Memory.AllocBytesPerOp = mem.BytesAllocatedPerOperation
+		var total int64
+		counted := false
+		for _, n := range []*int64{mem.Gen0Collections, mem.Gen1Collections, mem.Gen2Collections} {
+			if n != nil {
+				total += *n
+				counted = true
+			}
+		}
+		if counted {
+			op.Memory.GcCount = &total
+		}
+	}
+	return op
+}
@@ -0,0 +1,60 @@
+package ingest
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestBenchmarkDotNetReadsFullExport(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "AasBenchmark.PipelineBenchmarks-report-full-compressed.json"), `{
+  "HostEnvironmentInfo": {"RuntimeVersion": ".NET 8.0.1 (8.0.123.58001)", "DotNetCliVersion": "8.0.101"},
+  "Benchmarks": [
+    {
+      "FullName": "AasBenchmark.PipelineBenchmarks.Deserialize(Dataset: \"wide\")",
+      "Method": "Deserialize", "Parameters": "Dataset=wide",
+      "Statistics": {"N": 3, "Mean": 2000.4, "Median": 1990, "StandardDeviation": 12.6, "Min": 1900, "Max": 2100},
+      "Memory": {"Gen0Collections": 4, "Gen1Collections": 1, "Gen2Collections": 0, "BytesAllocatedPerOperation": 4096},
+      "Measurements": [
+        {"IterationMode": "Overhead", "IterationStage": "Actual", "Operations": 100, "Nanoseconds": 100},
+        {"IterationMode": "Workload", "IterationStage": "Actual", "Operations": 100, "Nanoseconds": 999999},
+        {"IterationMode": "Workload", "IterationStage": "Result", "Operations": 100, "Nanoseconds": 190000},
+        {"IterationMode": "Workload", "IterationStage": "Result", "Operations": 100, "Nanoseconds": 200000},
+        {"IterationMode": "Workload", "IterationStage": "Result", "Operations": 100, "Nanoseconds": 210000}
+      ]
+    },
+    {
+      "FullName": "AasBenchmark.PipelineBenchmarks.SerializeXml(Dataset: \"mixed\")",
+      "Method": "SerializeXml", "Parameters": "Dataset=mixed",
+      "Statistics": {"N": 1, "Mean": 500, "Median": 500, "StandardDeviation": 0, "Min": 500, "Max": 500}
+    },
+    {"FullName": "AasBenchmark.PipelineBenchmarks.Validate(Dataset: \"deep\")", "Method": "Validate", "Parameters": "Dataset=deep"}
+  ]
+}`)
+	writeFile(t, filepath.Join(dir, "AasBenchmark.PipelineBenchmarks-report.csv"), "not json")
+
+	r, err := BenchmarkDotNet(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.RuntimeVersion != ".NET 8.0.1 (8.0.123.58001)" {
+		t.Errorf("runtime version = %q", r.RuntimeVersion)
+	}
+	op := r.Datasets["wide"].Operations["deserialize"]
+	if op.MeanNs != 2000 || op.MedianNs != 1990 || op.StddevNs != 13 || op.MinNs != 1900 || op.MaxNs != 2100 {
+		t.Errorf("deserialize/wide = %+v", op)
+	}
+	if op.SampleCount != 3 || op.Iterations != 300 || op.P75Ns == nil || *op.P75Ns != 2050 || op.P99Ns == nil || *op.P99Ns != 2098 {
+		t.Errorf("deserialize/wide samples = %d, iterations %d, p75 %v, p99 %v", op.SampleCount, op.Iterations, op.P75Ns, op.P99Ns)
+	}
+	m := op.Memory
+	if m.AllocBytesPerOp == nil || *m.AllocBytesPerOp != 4096 || m.GcCount == nil || *m.GcCount != 5 {
+		t.Errorf("deserialize/wide memory = %+v", m)
+	}
+	if xml := r.Datasets["mixed"].Operations["serialize_xml"]; xml.OperationTrack != "xml" || xml.P99Ns != nil {
+		t.Errorf("serialize_xml/mixed = %+v", xml)
+	}
+	if _, ok := r.Datasets["deep"]; ok {
+		t.Error("benchmark without statistics was ingested")
+	}
+}
@@ -0,0 +1,201 @@
+package ingest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Criterion reads a Criterion.rs output directory (target/criterion). Each
+// benchmark keeps its latest run in <group>/<function>/new/: estimates.json
+// has the bootstrapped mean, median and standard deviation, and sample.json
+// or raw.csv the per-sample timings. The group names the operation and the
+// function the dataset, as in benchmark_group("deserialize") /
+// bench_function("wide").
+func Criterion(dir string) (*Result, error) {
+	r := &Result{Language: "rust", Harness: "criterion"}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || d.Name() != "estimates.json" || filepath.Base(filepath.Dir(path)) != "new" {
+			return nil
+		}
+		benchDir := filepath.Dir(path)
+		group, function, err := criterionID(dir, benchDir)
+		if err != nil {
+			return err
+		}
+		op, err := criterionOperation(benchDir, function, report.NormalizeOperationID(group))
+		if err != nil {
+			return fmt.Errorf("%s: %w", benchDir, err)
+		}
+		if _, dup := r.Datasets[function].Operations[op.OperationID]; dup {
+			return fmt.Errorf("%s: more than one benchmark maps to %s/%s", benchDir, op.OperationID, function)
+		}
+		r.add(function, op)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(r.Datasets) == 0 {
+		return nil, fmt.Errorf("no Criterion estimates.json found under %s", dir)
+	}
+	return r, nil
+}
+
+// criterionBenchmark is the part of new/benchmark.json naming a benchmark.
+type criterionBenchmark struct {
+	GroupID    string  `json:"group_id"`
+	FunctionID *string `json:"function_id"`
+	ValueStr   *string `json:"value_str"`
+}
+
+// criterionID returns the group and function of the benchmark whose latest
+// run is in benchDir, from benchmark.json or else the directory names.
+func criterionID(root, benchDir string) (group, function string, err error) {
+	if data, err := os.ReadFile(filepath.Join(benchDir, "benchmark.json")); err == nil {
+		var b criterionBenchmark
+		if err := json.Unmarshal(data, &b); err != nil {
+			return "", "", fmt.Errorf("parse %s: %w", filepath.Join(benchDir, "benchmark.json"), err)
+		}
+		switch {
+		case b.FunctionID != nil && *b.FunctionID != "":
+			return b.GroupID, *b.FunctionID, nil
+		case b.ValueStr != nil && *b.ValueStr != "":
+			return b.GroupID, *b.ValueStr, nil
+		}
+	}
+	rel, err := filepath.Rel(root, filepath.Dir(benchDir))
+	if err != nil {
+		return "", "", err
+	}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	if len(parts) < 2 {
+		return "", "", fmt.Errorf("%s: cannot tell group and function apart; want <group>/<function>/new", benchDir)
+	}
+	return parts[0], parts[len(parts)-1], nil
+}
+
+// criterionEstimate is one statistic of estimates.json.
+type criterionEstimate struct {
+	PointEstimate float64 `json:"point_estimate"`
+}
+
+type criterionEstimates struct {
+	Mean   *criterionEstimate `json:"mean"`
+	Median *criterionEstimate `json:"median"`
+	StdDev *criterionEstimate `json:"std_dev"`
+}
+
+func criterionOperation(benchDir, dataset, operationID string) (report.OperationEntry, error) {
+	data, err := os.ReadFile(filepath.Join(benchDir, "estimates.json"))
+	if err != nil {
+		return report.OperationEntry{}, err
+	}
+	var est criterionEstimates
+	if err := json.Unmarshal(data, &est); err != nil {
+		return report.OperationEntry{}, fmt.Errorf("parse estimates.json: %w", err)
+	}
+	if est.Mean == nil {
+		return report.OperationEntry{}, fmt.Errorf("estimates.json has no mean")
+	}
+
+	op := operation(dataset, operationID, est.Mean.PointEstimate)
+	if est.Median != nil {
+		op.MedianNs = int64(math.Round(est.Median.PointEstimate))
+	}
+	if est.StdDev != nil {
+		op.StddevNs = int64(math.Round(est.StdDev.PointEstimate))
+	}
+
+	perOp, iterations, err := criterionSamples(benchDir)
+	if err != nil {
+		return report.OperationEntry{}, err
+	}
+	op.SampleCount = len(perOp)
+	op.Iterations = iterations
+	setSampleStats(&op, perOp)
+	return op, nil
+}
+
+// criterionSamples returns the ns per iteration of every sample and the
+// total iteration count, preferring sample.json over raw.csv. Neither file
+// is required; without them only the estimates are reported.
+func criterionSamples(benchDir string) (perOp []float64, iterations int, err error) {
+	if data, err := os.ReadFile(filepath.Join(benchDir, "sample.json")); err == nil {
+		var s struct {
+			Iters []float64 `json:"iters"`
+			Times []float64 `json:"times"`
+		}
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, 0, fmt.Errorf("parse sample.json: %w", err)
+		}
+		if len(s.Iters) != len(s.Times) {
+			return nil, 0, fmt.Errorf("sample.json has %d iters but %d times", len(s.Iters), len(s.Times))
+		}
+		for i, n := range s.Iters {
+			if n > 0 {
+				perOp = append(perOp, s.Times[i]/n)
+				iterations += int(n)
+			}
+		}
+		return perOp, iterations, nil
+	}
+
+	f, err := os.Open(filepath.Join(benchDir, "raw.csv"))
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, 0, fmt.Errorf("parse raw.csv: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, 0, nil
+	}
+	col := make(map[string]int)
+	for i, name := range rows[0] {
+		col[name] = i
+	}
+	for _, name := range []string{"sample_measured_value", "unit", "iteration_count"} {
+		if _, ok := col[name]; !ok {
+			return nil, 0, fmt.Errorf("raw.csv has no %s column", name)
+		}
+	}
+	for line, row := range rows[1:] {
+		measured, err1 := strconv.ParseFloat(row[col["sample_measured_value"]], 64)
+		n, err2 := strconv.ParseFloat(row[col["iteration_count"]], 64)
+		scale, ok := timeUnits[row[col["unit"]]]
+		if err1 != nil || err2 != nil || !ok {
+			return nil, 0, fmt.Errorf("raw.csv line %d: unreadable sample %q", line+2, strings.Join(row, ","))
+		}
+		if n > 0 {
+			perOp = append(perOp, measured*scale/n)
+			iterations += int(n)
+		}
+	}
+	return perOp, iterations, nil
+}
+
+// timeUnits converts the time units harnesses print to nanoseconds.
+var timeUnits = map[string]float64{
+	"ns": 1,
+	"us": 1e3,
+	"µs": 1e3,
+	"ms": 1e6,
+	"s":  1e9,
+}
@@ -0,0 +1,64 @@
+package ingest
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCriterionReadsEstimatesAndSamples(t *testing.T) {
+	dir := t.TempDir()
+	estimates := `{"mean":{"point_estimate":1500.4},"median":{"point_estimate":1490},"std_dev":{"point_estimate":25.6}}`
+	// deserialize/wide has sample.json and benchmark.json.
+	writeFile(t, filepath.Join(dir, "deserialize/wide/new/estimates.json"), estimates)
+	writeFile(t, filepath.Join(dir, "deserialize/wide/new/benchmark.json"), `{"group_id":"deserialize","function_id":"wide","value_str":null}`)
+	writeFile(t, filepath.Join(dir, "deserialize/wide/new/sample.json"), `{"iters":[10,20,30],"times":[14000,30000,48000]}`)
+	// The previous run in base/ must be ignored.
+	writeFile(t, filepath.Join(dir, "deserialize/wide/base/estimates.json"), `{"mean":{"point_estimate":9}}`)
+	// serializeXml/mixed only has raw.csv, in microseconds.
+	writeFile(t, filepath.Join(dir, "serializeXml/mixed/new/estimates.json"), estimates)
+	writeFile(t, filepath.Join(dir, "serializeXml/mixed/new/raw.csv"),
+		"group,function,value,throughput_num,throughput_type,sample_measured_value,unit,iteration_count\n"+
+			"serializeXml,mixed,,,,2.0,us,2\n"+
+			"serializeXml,mixed,,,,9.0,us,4\n")
+
+	r, err := Criterion(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := r.Datasets["wide"].Operations["deserialize"]
+	if op.MeanNs != 1500 || op.MedianNs != 1490 || op.StddevNs != 26 || op.OperationTrack != "core" {
+		t.Errorf("deserialize/wide = %+v", op)
+	}
+	if op.SampleCount != 3 || op.Iterations != 60 || op.MinNs != 1400 || op.MaxNs != 1600 {
+		t.Errorf("deserialize/wide samples = %d, iterations %d, range [%d, %d]", op.SampleCount, op.Iterations, op.MinNs, op.MaxNs)
+	}
+	if op.P99Ns == nil || *op.P99Ns != 1598 || op.Memory.AllocBytesPerOp != nil {
+		t.Errorf("deserialize/wide p99 = %v, alloc = %v", op.P99Ns, op.Memory.AllocBytesPerOp)
+	}
+	xml, ok := r.Datasets["mixed"].Operations["serialize_xml"]
+	if !ok || xml.SampleCount != 2 || xml.MinNs != 1000 || xml.MaxNs != 2250 || xml.OperationTrack != "xml" {
+		t.Errorf("serialize_xml/mixed = %+v (found %v)", xml, ok)
+	}
+
+	rep := r.Report("basyx-rust")
+	if rep.Metadata.Language != "rust" || rep.Metadata.BenchmarkHarness != "criterion" || rep.Metadata.SDKPackageVersion != "unknown" {
+		t.Errorf("metadata = %+v", rep.Metadata)
+	}
+}
+
+func TestCriterionRejectsEmptyDirectory(t *testing.T) {
+	if _, err := Criterion(t.TempDir()); err == nil {
+		t.Error("empty directory accepted")
+	}
+}
@@ -0,0 +1,93 @@
+// Package ingest converts the output of other languages' benchmark
+// harnesses into report.json, so adapters reuse the Go report tooling
+// instead of reimplementing report emission.
+package ingest
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Result is the benchmark data read from one harness's output directory or
+// file, with what it says about the run.
+type Result struct {
+	Language string
+	Harness  string
+	// RuntimeVersion is set when the output records it (JMH does).
+	RuntimeVersion string
+	Datasets       map[string]report.DatasetEntry
+	// CompressionSweep holds the aasx_repackage compression levels, when
+	// the output has them.
+	CompressionSweep []report.CompressionPoint
+}
+
+// Report wraps r in a report for sdkID. Metadata the output does not carry
+// is "unknown"; callers fill it in with report.Metadata.Set.
+func (r *Result) Report(sdkID string) *report.Report {
+	meta := report.Metadata{
+		Language:          r.Language,
+		RuntimeVersion:    r.RuntimeVersion,
+		SDKPackageVersion: "unknown",
+		BenchmarkHarness:  r.Harness,
+		Timestamp:         time.Now().UTC().Format(time.RFC3339),
+		Platform:          report.HostPlatform(),
+	}
+	if meta.RuntimeVersion == "" {
+		meta.RuntimeVersion = "unknown"
+	}
+	return &report.Report{
+		SchemaVersion:    report.SchemaVersion,
+		SDKID:            sdkID,
+		Metadata:         meta,
+		Datasets:         r.Datasets,
+		CompressionSweep: r.CompressionSweep,
+		Headline:         report.DefaultCatalog().SelectHeadline(r.Datasets),
+	}
+}
+
+// add stores op under dataset, creating the dataset entry on first use.
+func (r *Result) add(dataset string, op report.OperationEntry) {
+	if r.Datasets == nil {
+		r.Datasets = make(map[string]report.DatasetEntry)
+	}
+	ds, ok := r.Datasets[dataset]
+	if !ok {
+		ds = report.DatasetEntry{Operations: make(map[string]report.OperationEntry)}
+		r.Datasets[dataset] = ds
+	}
+	ds.Operations[op.OperationID] = op
+}
+
+// operation returns an entry with the fields every ingested operation
+// shares. Memory metrics start unknown (null).
+func operation(dataset, operationID string, meanNs float64) report.OperationEntry {
+	op := report.OperationEntry{
+		OperationID:          operationID,
+		OperationTrack:       report.InferOperationTrack(dataset, operationID),
+		MeasurementSemantics: "mean_ns_per_operation",
+		FailureState:         "ok",
+		MeanNs:               int64(math.Round(meanNs)),
+	}
+	if meanNs > 0 {
+		op.ThroughputOpsPerSec = math.Round(1e9/meanNs*100) / 100
+	}
+	return op
+}
+
+// setSampleStats fills the range and percentiles of op from per-operation
+// sample times in ns.
+func setSampleStats(op *report.OperationEntry, perOp []float64) {
+	if len(perOp) == 0 {
+		return
+	}
+	sorted := append([]float64(nil), perOp...)
+	sort.Float64s(sorted)
+	op.MinNs = int64(math.Round(sorted[0]))
+	op.MaxNs = int64(math.Round(sorted[len(sorted)-1]))
+	p75 := int64(math.Round(report.Percentile(sorted, 75)))
+	p99 := int64(math.Round(report.Percentile(sorted, 99)))
+	op.P75Ns, op.P99Ns = &p75, &p99
+}
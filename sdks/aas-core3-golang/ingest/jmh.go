@@ -0,0 +1,165 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// jmhNoDataset is the @Param value a JMH harness uses when a tier has no
+// datasets; its results are placeholders and are dropped.
+const jmhNoDataset = "__none__"
+
+// jmhMetric is a primary or secondary metric of a JMH result.
+type jmhMetric struct {
+	Score            float64            `json:"score"`
+	ScoreUnit        string             `json:"scoreUnit"`
+	ScorePercentiles map[string]float64 `json:"scorePercentiles"`
+	RawData          [][]float64        `json:"rawData"`
+}
+
+// jmhResult is one benchmark/parameter combination of JMH's -rf json
+// output.
+type jmhResult struct {
+	Benchmark        string               `json:"benchmark"`
+	Mode             string               `json:"mode"`
+	JDKVersion       string               `json:"jdkVersion"`
+	VMName           string               `json:"vmName"`
+	VMVersion        string               `json:"vmVersion"`
+	Params           map[string]string    `json:"params"`
+	PrimaryMetric    jmhMetric            `json:"primaryMetric"`
+	SecondaryMetrics map[string]jmhMetric `json:"secondaryMetrics"`
+}
+
+// JMH reads the JSON results file JMH writes with -rf json. The last
+// segment of the benchmark method names the operation and the "dataset"
+// parameter (or "xmlDataset" for XML benchmarks) the dataset. Scores in any
+// time-per-op or ops-per-time unit are converted to ns/op; the GC
+// profiler's secondary metrics (-prof gc) fill alloc_bytes_per_op,
+// gc_count and gc_pause_ms.
+func JMH(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var results []jmhResult
+	if err := json.Unmarshal(data, &results); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	r := &Result{Language: "java", Harness: "jmh"}
+	for _, res := range results {
+		if r.RuntimeVersion == "" {
+			r.RuntimeVersion = jmhRuntime(res)
+		}
+		operationID := report.NormalizeOperationID(res.Benchmark[strings.LastIndex(res.Benchmark, ".")+1:])
+		dataset := res.Params["dataset"]
+		if operationID == "deserialize_xml" || operationID == "serialize_xml" {
+			dataset = res.Params["xmlDataset"]
+		}
+		if dataset == jmhNoDataset {
+			continue
+		}
+		if dataset == "" {
+			return nil, fmt.Errorf("%s: %s has no dataset parameter", path, res.Benchmark)
+		}
+		op, err := jmhOperation(dataset, operationID, res)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s (%s): %w", path, res.Benchmark, dataset, err)
+		}
+		if _, dup := r.Datasets[dataset].Operations[op.OperationID]; dup {
+			return nil, fmt.Errorf("%s: more than one benchmark maps to %s/%s", path, op.OperationID, dataset)
+		}
+		r.add(dataset, op)
+	}
+	if len(r.Datasets) == 0 {
+		return nil, fmt.Errorf("no JMH results with a dataset in %s", path)
+	}
+	return r, nil
+}
+
+// jmhRuntime describes the JVM a result was measured on.
+func jmhRuntime(res jmhResult) string {
+	switch {
+	case res.JDKVersion != "" && res.VMName != "":
+		return fmt.Sprintf("%s (%s %s)", res.JDKVersion, res.VMName, res.VMVersion)
+	case res.JDKVersion != "":
+		return res.JDKVersion
+	}
+	return res.VMVersion
+}
+
+func jmhOperation(dataset, operationID string, res jmhResult) (report.OperationEntry, error) {
+	toNs, err := jmhToNs(res.PrimaryMetric.ScoreUnit)
+	if err != nil {
+		return report.OperationEntry{}, err
+	}
+	op := operation(dataset, operationID, toNs(res.PrimaryMetric.Score))
+
+	var perOp []float64
+	for _, fork := range res.PrimaryMetric.RawData {
+		for _, v := range fork {
+			perOp = append(perOp, toNs(v))
+		}
+	}
+	op.SampleCount = len(perOp)
+	op.Iterations = len(perOp)
+	_, median, stddev, _, _ := report.ComputeStats(perOp)
+	op.MedianNs = int64(math.Round(median))
+	op.StddevNs = int64(math.Round(stddev))
+	setSampleStats(&op, perOp)
+
+	// JMH's own percentiles cover every sample in sample-time mode, not
+	// just the per-iteration averages in rawData, so they win when the
+	// score is a time. Inverted throughput percentiles would swap ends.
+	if strings.HasSuffix(res.PrimaryMetric.ScoreUnit, "/op") {
+		if v, ok := res.PrimaryMetric.ScorePercentiles["50.0"]; ok {
+			op.MedianNs = int64(math.Round(toNs(v)))
+		}
+		if v, ok := res.PrimaryMetric.ScorePercentiles["99.0"]; ok {
+			p99 := int64(math.Round(toNs(v)))
+			op.P99Ns = &p99
+		}
+	}
+
+	for name, m := range res.SecondaryMetrics {
+		// JMH before 1.37 prefixes profiler metrics with a middle dot.
+		switch strings.TrimPrefix(name, "·") {
+		case "gc.alloc.rate.norm":
+			v := int64(math.Round(m.Score))
+			op.Memory.AllocBytesPerOp = &v
+		case "gc.count":
+			v := int64(math.Round(m.Score))
+			op.Memory.GcCount = &v
+		case "gc.time":
+			v := math.Round(m.Score*1000) / 1000
+			op.Memory.GcPauseMs = &v
+		}
+	}
+	return op, nil
+}
+
+// jmhToNs returns the conversion of a JMH score unit, time per op as in
+// average-time and sample-time modes or ops per time as in throughput mode,
+// to ns/op.
+func jmhToNs(unit string) (func(float64) float64, error) {
+	if t, ok := strings.CutSuffix(unit, "/op"); ok {
+		if scale, ok := timeUnits[t]; ok {
+			return func(v float64) float64 { return v * scale }, nil
+		}
+	}
+	if t, ok := strings.CutPrefix(unit, "ops/"); ok {
+		if scale, ok := timeUnits[t]; ok {
+			return func(v float64) float64 {
+				if v <= 0 {
+					return 0
+				}
+				return scale / v
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unsupported score unit %q", unit)
+}
@@ -0,0 +1,75 @@
+package ingest
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestJMHConvertsScoresAndGCMetrics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jmh_results.json")
+	writeFile(t, path, `[
+  {
+    "benchmark": "com.aas.benchmark.PipelineBenchmarks.deserialize",
+    "mode": "avgt", "jdkVersion": "17.0.9", "vmName": "OpenJDK 64-Bit Server VM", "vmVersion": "17.0.9+9",
+    "params": {"dataset": "wide", "xmlDataset": "__none__"},
+    "primaryMetric": {
+      "score": 1.5, "scoreUnit": "us/op",
+      "scorePercentiles": {"0.0": 1.4, "50.0": 1.45, "99.0": 1.6, "100.0": 1.6},
+      "rawData": [[1.4, 1.5], [1.6, 1.5]]
+    },
+    "secondaryMetrics": {
+      "·gc.alloc.rate.norm": {"score": 2048.4, "scoreUnit": "B/op"},
+      "·gc.count": {"score": 3, "scoreUnit": "counts"},
+      "gc.time": {"score": 4.25, "scoreUnit": "ms"}
+    }
+  },
+  {
+    "benchmark": "com.aas.benchmark.PipelineBenchmarks.serializeXml",
+    "mode": "thrpt",
+    "params": {"dataset": "wide", "xmlDataset": "mixed"},
+    "primaryMetric": {"score": 1000, "scoreUnit": "ops/ms", "rawData": [[500, 2000]]}
+  },
+  {
+    "benchmark": "com.aas.benchmark.PipelineBenchmarks.deserializeXml",
+    "mode": "avgt",
+    "params": {"dataset": "wide", "xmlDataset": "__none__"},
+    "primaryMetric": {"score": 1, "scoreUnit": "ns/op", "rawData": [[1]]}
+  }
+]`)
+
+	r, err := JMH(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.RuntimeVersion != "17.0.9 (OpenJDK 64-Bit Server VM 17.0.9+9)" {
+		t.Errorf("runtime version = %q", r.RuntimeVersion)
+	}
+	op := r.Datasets["wide"].Operations["deserialize"]
+	if op.MeanNs != 1500 || op.MedianNs != 1450 || op.MinNs != 1400 || op.MaxNs != 1600 || op.SampleCount != 4 {
+		t.Errorf("deserialize/wide = %+v", op)
+	}
+	if op.P99Ns == nil || *op.P99Ns != 1600 || op.P75Ns == nil || *op.P75Ns != 1525 {
+		t.Errorf("deserialize/wide p75 = %v, p99 = %v", op.P75Ns, op.P99Ns)
+	}
+	m := op.Memory
+	if m.AllocBytesPerOp == nil || *m.AllocBytesPerOp != 2048 || m.GcCount == nil || *m.GcCount != 3 || m.GcPauseMs == nil || *m.GcPauseMs != 4.25 {
+		t.Errorf("deserialize/wide memory = %+v", m)
+	}
+
+	xml, ok := r.Datasets["mixed"].Operations["serialize_xml"]
+	if !ok || xml.MeanNs != 1000 || xml.MinNs != 500 || xml.MaxNs != 2000 || xml.OperationTrack != "xml" {
+		t.Errorf("serialize_xml/mixed = %+v (found %v)", xml, ok)
+	}
+	if _, ok := r.Datasets["__none__"]; ok {
+		t.Error("placeholder __none__ dataset was ingested")
+	}
+}
+
+func TestJMHRejectsUnknownUnit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jmh_results.json")
+	writeFile(t, path, `[{"benchmark": "b.validate", "params": {"dataset": "deep"},
+  "primaryMetric": {"score": 1, "scoreUnit": "ops"}}]`)
+	if _, err := JMH(path); err == nil {
+		t.Error("expected an error for an unsupported score unit")
+	}
+}
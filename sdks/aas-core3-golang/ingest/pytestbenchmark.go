@@ -0,0 +1,134 @@
+package ingest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// pytestExport is the part of pytest-benchmark's --benchmark-json output
+// the converter reads. Times are in seconds.
+type pytestExport struct {
+	MachineInfo struct {
+		PythonImplementation string `json:"python_implementation"`
+		PythonVersion        string `json:"python_version"`
+	} `json:"machine_info"`
+	Benchmarks []pytestBenchmark `json:"benchmarks"`
+}
+
+type pytestBenchmark struct {
+	Name  string                 `json:"name"`
+	Extra map[string]interface{} `json:"extra_info"`
+	Stats *pytestStatistics      `json:"stats"`
+}
+
+type pytestStatistics struct {
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Mean       float64 `json:"mean"`
+	Median     float64 `json:"median"`
+	Stddev     float64 `json:"stddev"`
+	Q3         float64 `json:"q3"`
+	Rounds     int     `json:"rounds"`
+	Iterations int     `json:"iterations"`
+	// Data holds the per-round times, present with --benchmark-save-data.
+	Data []float64 `json:"data"`
+}
+
+// PytestBenchmark reads the JSON file pytest-benchmark writes with
+// --benchmark-json. Tests named test_<operation>[<dataset>] give the
+// operation and dataset. Times are converted from seconds to ns; q3 is the
+// p75, and p99 needs the per-round data. Memory figures an adapter stores
+// in benchmark.extra_info under report field names (peak_rss_bytes,
+// traced_peak_bytes, alloc_bytes_per_op) are carried over. A benchmark
+// with a compression_level in extra_info is one point of the aasx_repackage
+// compression sweep, named test_<operation>[<dataset>-<level>]; only its
+// default level is also the operation's measurement.
+func PytestBenchmark(path string) (*Result, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var export pytestExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	r := &Result{Language: "python", Harness: "pytest-benchmark"}
+	if mi := export.MachineInfo; mi.PythonVersion != "" {
+		r.RuntimeVersion = strings.TrimSpace(mi.PythonImplementation + " " + mi.PythonVersion)
+	}
+	for _, b := range export.Benchmarks {
+		if b.Stats == nil {
+			continue
+		}
+		name, dataset, ok := strings.Cut(strings.TrimSuffix(b.Name, "]"), "[")
+		if !ok || dataset == "" {
+			return nil, fmt.Errorf("%s: cannot tell the dataset of %q; want test_<operation>[<dataset>]", path, b.Name)
+		}
+		if level, ok := b.Extra["compression_level"].(string); ok {
+			if err := report.CheckCompressionLevel(level); err != nil {
+				return nil, fmt.Errorf("%s: %s: %w", path, b.Name, err)
+			}
+			dataset = strings.TrimSuffix(dataset, "-"+level)
+			point := report.CompressionPoint{Dataset: dataset, Level: level, MeanNs: int64(math.Round(b.Stats.Mean * 1e9))}
+			if size := extraInt(b.Extra, "package_size_bytes"); size != nil {
+				point.PackageSizeBytes = *size
+			}
+			r.CompressionSweep = append(r.CompressionSweep, point)
+			if level != report.CompressionDefault {
+				continue
+			}
+		}
+		op := pytestOperation(dataset, report.NormalizeOperationID(strings.TrimPrefix(name, "test_")), b)
+		if _, dup := r.Datasets[dataset].Operations[op.OperationID]; dup {
+			return nil, fmt.Errorf("%s: more than one benchmark maps to %s/%s", path, op.OperationID, dataset)
+		}
+		r.add(dataset, op)
+	}
+	if len(r.Datasets) == 0 {
+		return nil, fmt.Errorf("no pytest-benchmark results in %s", path)
+	}
+	report.SortCompressionSweep(r.CompressionSweep)
+	return r, nil
+}
+
+func pytestOperation(dataset, operationID string, b pytestBenchmark) report.OperationEntry {
+	s := b.Stats
+	ns := func(seconds float64) int64 { return int64(math.Round(seconds * 1e9)) }
+	op := operation(dataset, operationID, s.Mean*1e9)
+	op.SampleCount = s.Rounds
+	op.Iterations = s.Rounds * max(s.Iterations, 1)
+	op.MedianNs = ns(s.Median)
+	op.StddevNs = ns(s.Stddev)
+
+	// pytest-benchmark already divides each round by its iterations.
+	perOp := make([]float64, 0, len(s.Data))
+	for _, v := range s.Data {
+		perOp = append(perOp, v*1e9)
+	}
+	setSampleStats(&op, perOp)
+	op.MinNs, op.MaxNs = ns(s.Min), ns(s.Max)
+	if s.Q3 > 0 {
+		p75 := ns(s.Q3)
+		op.P75Ns = &p75
+	}
+
+	op.Memory.PeakRSSBytes = extraInt(b.Extra, "peak_rss_bytes")
+	op.Memory.TracedPeakBytes = extraInt(b.Extra, "traced_peak_bytes")
+	op.Memory.AllocBytesPerOp = extraInt(b.Extra, "alloc_bytes_per_op")
+	return op
+}
+
+// extraInt returns the numeric extra_info value key, or nil.
+func extraInt(extra map[string]interface{}, key string) *int64 {
+	v, ok := extra[key].(float64)
+	if !ok {
+		return nil
+	}
+	n := int64(math.Round(v))
+	return &n
+}
@@ -0,0 +1,92 @@
+package ingest
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+func TestPytestBenchmarkConvertsSeconds(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bench.json")
+	writeFile(t, path, `{
+  "machine_info": {"python_implementation": "CPython", "python_version": "3.12.1"},
+  "benchmarks": [
+    {
+      "name": "test_deserialize[wide]",
+      "extra_info": {"peak_rss_bytes": 1048576, "traced_peak_bytes": 2048},
+      "stats": {"min": 0.001, "max": 0.004, "mean": 0.0025, "median": 0.002, "stddev": 0.0001, "q3": 0.003,
+                "rounds": 4, "iterations": 2, "data": [0.001, 0.002, 0.003, 0.004]}
+    },
+    {
+      "name": "test_serialize_xml[mixed]",
+      "stats": {"min": 0.5, "max": 0.5, "mean": 0.5, "median": 0.5, "stddev": 0, "q3": 0.5, "rounds": 1, "iterations": 1}
+    }
+  ]
+}`)
+
+	r, err := PytestBenchmark(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.RuntimeVersion != "CPython 3.12.1" {
+		t.Errorf("runtime version = %q", r.RuntimeVersion)
+	}
+	op := r.Datasets["wide"].Operations["deserialize"]
+	if op.MeanNs != 2_500_000 || op.MedianNs != 2_000_000 || op.MinNs != 1_000_000 || op.MaxNs != 4_000_000 {
+		t.Errorf("deserialize/wide = %+v", op)
+	}
+	if op.SampleCount != 4 || op.Iterations != 8 || *op.P75Ns != 3_000_000 || op.P99Ns == nil || *op.P99Ns != 3_970_000 {
+		t.Errorf("deserialize/wide samples = %d, iterations %d, p75 %d, p99 %v", op.SampleCount, op.Iterations, *op.P75Ns, op.P99Ns)
+	}
+	if m := op.Memory; m.PeakRSSBytes == nil || *m.PeakRSSBytes != 1048576 || *m.TracedPeakBytes != 2048 || m.AllocBytesPerOp != nil {
+		t.Errorf("deserialize/wide memory = %+v", m)
+	}
+	xml := r.Datasets["mixed"].Operations["serialize_xml"]
+	if xml.MeanNs != 500_000_000 || xml.P99Ns != nil || xml.OperationTrack != "xml" {
+		t.Errorf("serialize_xml/mixed = %+v", xml)
+	}
+}
+
+func TestPytestBenchmarkRejectsUnparameterizedTest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bench.json")
+	writeFile(t, path, `{"benchmarks": [{"name": "test_deserialize", "stats": {"mean": 1}}]}`)
+	if _, err := PytestBenchmark(path); err == nil {
+		t.Error("expected an error for a test without a dataset parameter")
+	}
+}
+
+func TestPytestBenchmarkCollectsCompressionSweep(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bench.json")
+	writeFile(t, path, `{"benchmarks": [
+  {"name": "test_aasx_repackage[aasx_small-best]", "extra_info": {"compression_level": "best", "package_size_bytes": 900},
+   "stats": {"mean": 0.004, "rounds": 1, "iterations": 1}},
+  {"name": "test_aasx_repackage[aasx_small-store]", "extra_info": {"compression_level": "store", "package_size_bytes": 2000},
+   "stats": {"mean": 0.001, "rounds": 1, "iterations": 1}},
+  {"name": "test_aasx_repackage[aasx_small-default]", "extra_info": {"compression_level": "default", "package_size_bytes": 1000},
+   "stats": {"mean": 0.002, "rounds": 1, "iterations": 1}}
+]}`)
+
+	r, err := PytestBenchmark(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops := r.Datasets["aasx_small"].Operations
+	if len(ops) != 1 || ops["aasx_repackage"].MeanNs != 2_000_000 {
+		t.Errorf("operations = %+v, want only aasx_repackage at the default level", ops)
+	}
+	want := []report.CompressionPoint{
+		{Dataset: "aasx_small", Level: "store", MeanNs: 1_000_000, PackageSizeBytes: 2000},
+		{Dataset: "aasx_small", Level: "default", MeanNs: 2_000_000, PackageSizeBytes: 1000},
+		{Dataset: "aasx_small", Level: "best", MeanNs: 4_000_000, PackageSizeBytes: 900},
+	}
+	if !reflect.DeepEqual(r.CompressionSweep, want) {
+		t.Errorf("sweep = %+v, want %+v", r.CompressionSweep, want)
+	}
+
+	writeFile(t, path, `{"benchmarks": [{"name": "test_aasx_repackage[aasx_small-max]", "extra_info": {"compression_level": "max"}, "stats": {"mean": 1}}]}`)
+	if _, err := PytestBenchmark(path); err == nil {
+		t.Error("expected an error for an unknown compression level")
+	}
+}
@@ -0,0 +1,108 @@
+// Package logging sets up the structured logger shared by aasbench and the
+// benchmark harness. Every line carries the run ID, which aasbench passes
+// to the processes it starts through the environment, so the logs of one
+// run can be correlated across tools and SDK adapters.
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// Environment variables that carry the logging setup to child processes.
+// CI can set EnvRunID to tie the logs of several adapters to one run.
+const (
+	EnvRunID  = "AASBENCH_RUN_ID"
+	EnvFormat = "AASBENCH_LOG_FORMAT"
+	EnvLevel  = "AASBENCH_LOG_LEVEL"
+)
+
+// Log formats.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// RunIDKey is the attribute every log line carries the run ID under.
+const RunIDKey = "run_id"
+
+// RunID returns the run ID from EnvRunID. When it is unset, a new ID is
+// generated and exported, so that child processes inherit it.
+func RunID() string {
+	if id := os.Getenv(EnvRunID); id != "" {
+		return id
+	}
+	id := NewRunID()
+	os.Setenv(EnvRunID, id)
+	return id
+}
+
+// NewRunID returns a run ID of the start time and a random suffix, e.g.
+// 20261016T030000Z-3f9a1c.
+func NewRunID() string {
+	suffix := make([]byte, 3)
+	if _, err := rand.Read(suffix); err != nil {
+		return time.Now().UTC().Format("20060102T150405.000000000Z")
+	}
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(suffix)
+}
+
+// ParseLevel parses debug, info, warn or error; empty is info.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q (want debug, info, warn or error)", s)
+	}
+	return level, nil
+}
+
+// New returns a logger writing lines of format to w, dropping those below
+// level, with runID attached to every line.
+func New(w io.Writer, format string, level slog.Level, runID string) (*slog.Logger, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", FormatText:
+		h = slog.NewTextHandler(w, opts)
+	case FormatJSON:
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q (want %s or %s)", format, FormatText, FormatJSON)
+	}
+	return slog.New(h).With(RunIDKey, runID), nil
+}
+
+// Setup makes a logger of format and level on stderr the slog default and
+// exports both, with the run ID, to the environment of child processes.
+func Setup(format, level string) error {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	logger, err := New(os.Stderr, format, lvl, RunID())
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	os.Setenv(EnvFormat, format)
+	os.Setenv(EnvLevel, level)
+	return nil
+}
+
+// FromEnv sets up the default logger from the environment aasbench
+// exported, falling back to text at info level for values it cannot use.
+func FromEnv() {
+	if err := Setup(os.Getenv(EnvFormat), os.Getenv(EnvLevel)); err != nil {
+		_ = Setup(FormatText, "")
+		slog.Warn("ignoring logging environment", "err", err)
+	}
+}
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestJSONLinesCarryRunIDAndLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, FormatJSON, slog.LevelInfo, "run-42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger.Debug("hidden")
+	logger.Warn("could not load side channel", "path", "events.json")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("got %d lines, want the warning only:\n%s", len(lines), buf.String())
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["level"] != "WARN" || entry[RunIDKey] != "run-42" || entry["path"] != "events.json" {
+		t.Errorf("entry = %v", entry)
+	}
+}
+
+func TestSetupExportsRunIDToChildren(t *testing.T) {
+	t.Setenv(EnvRunID, "")
+	t.Setenv(EnvFormat, "")
+	t.Setenv(EnvLevel, "")
+	if err := Setup(FormatText, "debug"); err != nil {
+		t.Fatal(err)
+	}
+	id := RunID()
+	if id == "" || RunID() != id {
+		t.Errorf("run ID %q is not stable", id)
+	}
+	if err := Setup("xml", ""); err == nil {
+		t.Error("Setup accepted log format xml")
+	}
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("ParseLevel accepted loud")
+	}
+}
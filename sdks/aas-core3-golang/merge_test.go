@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	aas "github.com/aas-core-works/aas-core3.0-golang/jsonization"
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+)
+
+// mergePartCount is the number of partial environments the merge
+// benchmark splits each dataset into.
+const mergePartCount = 4
+
+// splitEnvironment serializes env as n partial environments, as separate
+// submodel exports would arrive at a repository: the submodels are dealt
+// round-robin across the parts, and every part carries all shells and
+// concept descriptions, so merging must de-duplicate them by id.
+func splitEnvironment(env aastypes.IEnvironment, n int) ([][]byte, error) {
+	parts := make([][]aastypes.ISubmodel, n)
+	for i, sm := range env.Submodels() {
+		parts[i%n] = append(parts[i%n], sm)
+	}
+	docs := make([][]byte, n)
+	for i, submodels := range parts {
+		part := aastypes.NewEnvironment()
+		part.SetAssetAdministrationShells(env.AssetAdministrationShells())
+		part.SetSubmodels(submodels)
+		part.SetConceptDescriptions(env.ConceptDescriptions())
+		jsonable, err := aas.ToJsonable(part)
+		if err != nil {
+			return nil, err
+		}
+		if docs[i], err = json.Marshal(jsonable); err != nil {
+			return nil, err
+		}
+	}
+	return docs, nil
+}
+
+// mergeEnvironments consolidates parts into one environment, keeping the
+// first identifiable seen for each id. Ids are de-duplicated per kind, so a
+// concept description sharing an id with a submodel is kept.
+func mergeEnvironments(parts []aastypes.IEnvironment) aastypes.IEnvironment {
+	seenShells := make(map[string]bool)
+	seenSubmodels := make(map[string]bool)
+	seenConceptDescriptions := make(map[string]bool)
+	var shells []aastypes.IAssetAdministrationShell
+	var submodels []aastypes.ISubmodel
+	var conceptDescriptions []aastypes.IConceptDescription
+	for _, part := range parts {
+		for _, shell := range part.AssetAdministrationShells() {
+			if !seenShells[shell.ID()] {
+				seenShells[shell.ID()] = true
+				shells = append(shells, shell)
+			}
+		}
+		for _, sm := range part.Submodels() {
+			if !seenSubmodels[sm.ID()] {
+				seenSubmodels[sm.ID()] = true
+				submodels = append(submodels, sm)
+			}
+		}
+		for _, cd := range part.ConceptDescriptions() {
+			if !seenConceptDescriptions[cd.ID()] {
+				seenConceptDescriptions[cd.ID()] = true
+				conceptDescriptions = append(conceptDescriptions, cd)
+			}
+		}
+	}
+	merged := aastypes.NewEnvironment()
+	merged.SetAssetAdministrationShells(shells)
+	merged.SetSubmodels(submodels)
+	merged.SetConceptDescriptions(conceptDescriptions)
+	return merged
+}
+
+// BenchmarkMerge benchmarks a repository import: deserializing the
+// partial environments of a dataset and merging them into one with
+// identifier de-duplication. Deserialization is included because an import
+// starts from files; comparing with deserialize on the same dataset shows
+// the cost of the consolidation itself.
+func BenchmarkMerge(b *testing.B) {
+	selectOperation(b, "merge")
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		env, err := deserializeEnv(loadRawJSON(b, f))
+		if err != nil {
+			b.Fatalf("Setup failed for %s: %v", name, err)
+		}
+		docs, err := splitEnvironment(env, mergePartCount)
+		if err != nil {
+			b.Fatalf("Setup failed splitting %s: %v", name, err)
+		}
+		runObserved(b, "merge", name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				parts := make([]aastypes.IEnvironment, len(docs))
+				for j, doc := range docs {
+					part, err := deserializeEnv(doc)
+					if err != nil {
+						b.Fatal(err)
+					}
+					parts[j] = part
+				}
+				merged := mergeEnvironments(parts)
+				_ = merged
+			}
+		})
+	}
+	globalMemStats.Groups["merge"] = captureMemSnapshot()
+	globalHeap.writeProfile("merge")
+}
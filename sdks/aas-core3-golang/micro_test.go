@@ -0,0 +1,220 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	aasstringification "github.com/aas-core-works/aas-core3.0-golang/stringification"
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+	aasverification "github.com/aas-core-works/aas-core3.0-golang/verification"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// The micro track isolates SDK primitives that large documents call once
+// per element. Its inputs are built into the harness rather than read from
+// DATASETS_DIR, and each gets a micro_* dataset name. Every iteration is a
+// single call, cycling through the inputs like index_lookup. The dataset
+// names carry report.MicroDatasetPrefix, which places them in the track.
+
+// iec61360IRI is the data specification template IRI an SDK matches to
+// recognize IEC 61360 content.
+const iec61360IRI = "https://admin-shell.io/DataSpecificationTemplates/DataSpecificationIEC61360/3/0"
+
+// modelTypeNames returns the string of every ModelType literal.
+func modelTypeNames() []string {
+	var names []string
+	for mt := aastypes.ModelType(0); ; mt++ {
+		name, ok := aasstringification.ModelTypeToString(mt)
+		if !ok {
+			return names
+		}
+		names = append(names, name)
+	}
+}
+
+// BenchmarkEnumFromString benchmarks parsing a modelType string into its
+// enum literal.
+func BenchmarkEnumFromString(b *testing.B) {
+	selectOperation(b, "enum_from_string")
+	names := modelTypeNames()
+	runObserved(b, "enum_from_string", report.MicroDatasetPrefix+"model_type", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, ok := aasstringification.ModelTypeFromString(names[i%len(names)]); !ok {
+				b.Fatalf("model type %q not recognized", names[i%len(names)])
+			}
+		}
+	})
+	globalMemStats.Groups["enum_from_string"] = captureMemSnapshot()
+	globalHeap.writeProfile("enum_from_string")
+}
+
+// BenchmarkEnumToString benchmarks rendering a ModelType literal as its
+// modelType string.
+func BenchmarkEnumToString(b *testing.B) {
+	selectOperation(b, "enum_to_string")
+	n := aastypes.ModelType(len(modelTypeNames()))
+	runObserved(b, "enum_to_string", report.MicroDatasetPrefix+"model_type", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if _, ok := aasstringification.ModelTypeToString(aastypes.ModelType(i) % n); !ok {
+				b.Fatalf("model type %d has no string", aastypes.ModelType(i)%n)
+			}
+		}
+	})
+	globalMemStats.Groups["enum_to_string"] = captureMemSnapshot()
+	globalHeap.writeProfile("enum_to_string")
+}
+
+// xsdValue is a lexical value and the xs: type it is declared as.
+type xsdValue struct {
+	valueType string
+	value     string
+}
+
+// xsdValues mixes the value types datasets use most with values that need
+// the full lexical check; the last ones are inconsistent with their type.
+var xsdValues = []xsdValue{
+	{"xs:string", "Temperature sensor"},
+	{"xs:boolean", "true"},
+	{"xs:int", "-2147483648"},
+	{"xs:long", "9223372036854775807"},
+	{"xs:unsignedShort", "65535"},
+	{"xs:integer", "123456789012345678901234567890"},
+	{"xs:decimal", "-1234.5678"},
+	{"xs:double", "6.02214076E23"},
+	{"xs:float", "INF"},
+	{"xs:date", "2024-02-29"},
+	{"xs:dateTime", "2024-06-01T12:30:00.125+02:00"},
+	{"xs:duration", "P1Y2M3DT4H5M6.7S"},
+	{"xs:anyURI", "https://example.com/ids/sm/1234_5678"},
+	{"xs:base64Binary", "QUFTIGJlbmNobWFyaw=="},
+	{"xs:hexBinary", "0fb8"},
+	{"xs:byte", "128"},
+	{"xs:date", "2023-02-29"},
+}
+
+// BenchmarkValueParse benchmarks resolving an xs: value type and checking
+// a value against it, as verification does for every Property.
+func BenchmarkValueParse(b *testing.B) {
+	selectOperation(b, "value_parse")
+	for _, v := range xsdValues {
+		if _, ok := aasstringification.DataTypeDefXSDFromString(v.valueType); !ok {
+			b.Fatalf("unknown value type %q", v.valueType)
+		}
+	}
+	runObserved(b, "value_parse", report.MicroDatasetPrefix+"xsd_value", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			v := xsdValues[i%len(xsdValues)]
+			t, _ := aasstringification.DataTypeDefXSDFromString(v.valueType)
+			_ = aasverification.ValueConsistentWithXSDType(v.value, t)
+		}
+	})
+	globalMemStats.Groups["value_parse"] = captureMemSnapshot()
+	globalHeap.writeProfile("value_parse")
+}
+
+// dataSpecificationRefs are data specification references as datasets
+// carry them: mostly the IEC 61360 template, plus its older version,
+// near-misses and unrelated IRIs.
+func dataSpecificationRefs() []aastypes.IReference {
+	iris := []string{
+		iec61360IRI,
+		iec61360IRI,
+		"https://admin-shell.io/DataSpecificationTemplates/DataSpecificationIEC61360/2/0",
+		iec61360IRI,
+		"https://admin-shell.io/DataSpecificationTemplates/DataSpecificationIEC61360/3/0/",
+		"https://admin-shell.io/aas/3/0/RC02/DataSpecificationPhysicalUnit",
+		iec61360IRI,
+		"urn:example:data-specification:custom:1",
+	}
+	refs := make([]aastypes.IReference, len(iris))
+	for i, iri := range iris {
+		refs[i] = aastypes.NewReference(aastypes.ReferenceTypesExternalReference,
+			[]aastypes.IKey{aastypes.NewKey(aastypes.KeyTypesGlobalReference, iri)})
+	}
+	return refs
+}
+
+// BenchmarkIriMatch benchmarks comparing a data specification reference
+// with the IEC 61360 template IRI.
+func BenchmarkIriMatch(b *testing.B) {
+	selectOperation(b, "iri_match")
+	refs := dataSpecificationRefs()
+	template := aastypes.NewReference(aastypes.ReferenceTypesExternalReference,
+		[]aastypes.IKey{aastypes.NewKey(aastypes.KeyTypesGlobalReference, iec61360IRI)})
+	runObserved(b, "iri_match", report.MicroDatasetPrefix+"data_specification", func(b *testing.B) {
+		b.ResetTimer()
+		matches := 0
+		for i := 0; i < b.N; i++ {
+			if aasverification.ReferenceKeyValuesEqual(refs[i%len(refs)], template) {
+				matches++
+			}
+		}
+		_ = matches
+	})
+	globalMemStats.Groups["iri_match"] = captureMemSnapshot()
+	globalHeap.writeProfile("iri_match")
+}
+
+// langLookup is one lookup of a language in a multi-language text.
+type langLookup struct {
+	texts    []aastypes.ILangStringTextType
+	language string
+}
+
+// langLookups covers a hit at the front, a hit at the back, a case
+// difference and a miss, in texts of two and six languages.
+func langLookups() []langLookup {
+	short := []aastypes.ILangStringTextType{
+		aastypes.NewLangStringTextType("en", "Maximum rotation speed"),
+		aastypes.NewLangStringTextType("de", "Maximale Drehzahl"),
+	}
+	long := []aastypes.ILangStringTextType{
+		aastypes.NewLangStringTextType("de-DE", "Maximale Drehzahl"),
+		aastypes.NewLangStringTextType("fr", "Vitesse de rotation maximale"),
+		aastypes.NewLangStringTextType("zh-Hans", "最大转速"),
+		aastypes.NewLangStringTextType("ja", "最大回転速度"),
+		aastypes.NewLangStringTextType("es", "Velocidad de rotación máxima"),
+		aastypes.NewLangStringTextType("en-US", "Maximum rotation speed"),
+	}
+	return []langLookup{
+		{short, "en"},
+		{short, "de"},
+		{long, "en"},
+		{long, "DE-de"},
+		{long, "ja"},
+		{long, "it"},
+	}
+}
+
+// lookupLangString returns the text for language, matching tags case
+// insensitively as BCP 47 requires and any English variant for "en".
+func lookupLangString(texts []aastypes.ILangStringTextType, language string) (string, bool) {
+	for _, t := range texts {
+		if language == "en" && aasverification.IsBCP47ForEnglish(t.Language()) ||
+			strings.EqualFold(t.Language(), language) {
+			return t.Text(), true
+		}
+	}
+	return "", false
+}
+
+// BenchmarkLangStringLookup benchmarks finding the text of a language in a
+// multi-language property value.
+func BenchmarkLangStringLookup(b *testing.B) {
+	selectOperation(b, "lang_string_lookup")
+	lookups := langLookups()
+	runObserved(b, "lang_string_lookup", report.MicroDatasetPrefix+"lang_string", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			l := lookups[i%len(lookups)]
+			text, ok := lookupLangString(l.texts, l.language)
+			_, _ = text, ok
+		}
+	})
+	globalMemStats.Groups["lang_string_lookup"] = captureMemSnapshot()
+	globalHeap.writeProfile("lang_string_lookup")
+}
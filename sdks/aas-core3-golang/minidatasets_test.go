@@ -0,0 +1,71 @@
+package main
+
+import (
+	"embed"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// miniDatasets are few-KB versions of wide/deep/mixed, regenerated with
+// `python3 datasets/generate.py --output-dir sdks/aas-core3-golang/testdata/mini --mini`.
+//
+//go:embed testdata/mini/*.json
+var miniDatasets embed.FS
+
+var mini struct {
+	once sync.Once
+	dir  string
+	err  error
+}
+
+// datasetsDir returns DATASETS_DIR or, when it is unset, a temporary
+// directory holding the embedded miniature datasets, so that the harness
+// tests and a smoke `go test -bench .` work in a fresh checkout.
+func datasetsDir(tb testing.TB) string {
+	tb.Helper()
+	if dir := os.Getenv("DATASETS_DIR"); dir != "" {
+		return dir
+	}
+	mini.once.Do(func() {
+		mini.dir, mini.err = extractMiniDatasets()
+		if mini.err == nil {
+			slog.Info("DATASETS_DIR not set; using embedded miniature datasets")
+		}
+	})
+	if mini.err != nil {
+		tb.Fatalf("Failed to extract embedded datasets: %v", mini.err)
+	}
+	return mini.dir
+}
+
+func extractMiniDatasets() (string, error) {
+	dir, err := os.MkdirTemp("", "aasbench-mini-")
+	if err != nil {
+		return "", err
+	}
+	files, err := fs.Glob(miniDatasets, "testdata/mini/*.json")
+	if err != nil {
+		return "", err
+	}
+	for _, name := range files {
+		data, err := miniDatasets.ReadFile(name)
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(filepath.Join(dir, filepath.Base(name)), data, 0644); err != nil {
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// removeMiniDatasets deletes the extracted datasets, if any.
+func removeMiniDatasets() {
+	if mini.dir != "" {
+		os.RemoveAll(mini.dir)
+	}
+}
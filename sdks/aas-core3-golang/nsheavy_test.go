@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	aasxml "github.com/aas-core-works/aas-core3.0-golang/xmlization"
+)
+
+// nsHeavyPrefixes are the prefixes nsHeavyXML binds to the AAS namespace
+// on the root element, besides making it the default namespace.
+var nsHeavyPrefixes = []string{"aas", "aas3"}
+
+// nsHeavyForeign are namespaces that tool exports declare without any
+// AAS element living in them. nsHeavyXML declares all of them on the root
+// and one again on every element.
+var nsHeavyForeign = []struct{ prefix, uri string }{
+	{"xsi", "http://www.w3.org/2001/XMLSchema-instance"},
+	{"xs", "http://www.w3.org/2001/XMLSchema"},
+	{"IEC61360", "https://admin-shell.io/IEC61360/3/0"},
+	{"abac", "https://admin-shell.io/aas/abac/3/0"},
+	{"dc", "http://purl.org/dc/elements/1.1/"},
+}
+
+var nsHeavyText = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+
+// nsHeavyXML rewrites an AAS XML document the way namespace-happy tool
+// exports write it. The root declares the AAS namespace as the default and
+// under every prefix in nsHeavyPrefixes, along with every namespace in
+// nsHeavyForeign. Below it the elements cycle by depth through the default
+// namespace, each prefix, and a prefix declared on the element itself;
+// every element redeclares one foreign namespace, and every eighth level
+// redeclares the default one. The SDK accepts no attributes besides
+// namespace declarations, so those are the attributes the document is
+// heavy with. The content is unchanged.
+func nsHeavyXML(raw []byte) ([]byte, error) {
+	dec := xml.NewDecoder(bytes.NewReader(raw))
+	var out bytes.Buffer
+	var open []string // qualified names of the open elements
+	for n := 0; ; n++ {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != aasxml.Namespace {
+				return nil, fmt.Errorf("element %s is not in the AAS namespace", t.Name.Local)
+			}
+			depth := len(open)
+			var qname, decls string
+			if depth == 0 {
+				qname = t.Name.Local
+				decls = fmt.Sprintf(` xmlns="%s"`, aasxml.Namespace)
+				for _, p := range nsHeavyPrefixes {
+					decls += fmt.Sprintf(` xmlns:%s="%s"`, p, aasxml.Namespace)
+				}
+				for _, f := range nsHeavyForeign {
+					decls += fmt.Sprintf(` xmlns:%s="%s"`, f.prefix, f.uri)
+				}
+			} else {
+				switch style := depth % (len(nsHeavyPrefixes) + 2); {
+				case style == 0:
+					qname = t.Name.Local
+					if depth%8 == 0 {
+						decls = fmt.Sprintf(` xmlns="%s"`, aasxml.Namespace)
+					}
+				case style <= len(nsHeavyPrefixes):
+					qname = nsHeavyPrefixes[style-1] + ":" + t.Name.Local
+				default:
+					p := fmt.Sprintf("n%d", depth)
+					qname = p + ":" + t.Name.Local
+					decls = fmt.Sprintf(` xmlns:%s="%s"`, p, aasxml.Namespace)
+				}
+				f := nsHeavyForeign[n%len(nsHeavyForeign)]
+				decls += fmt.Sprintf(` xmlns:%s="%s"`, f.prefix, f.uri)
+			}
+			open = append(open, qname)
+			out.WriteString("<" + qname + decls + ">")
+		case xml.EndElement:
+			out.WriteString("</" + open[len(open)-1] + ">")
+			open = open[:len(open)-1]
+		case xml.CharData:
+			nsHeavyText.WriteString(&out, string(t))
+		case xml.ProcInst:
+			fmt.Fprintf(&out, "<?%s %s?>", t.Target, t.Inst)
+		case xml.Comment:
+			fmt.Fprintf(&out, "<!--%s-->", t)
+		}
+	}
+	return out.Bytes(), nil
+}
+
+// BenchmarkDeserializeXmlNsheavy benchmarks XML -> AAS Environment
+// deserialization of each dataset's XML input rewritten by nsHeavyXML, so
+// that the cost of encoding/xml's namespace handling shows against
+// deserialize_xml. Setup checks that the rewritten document deserializes
+// to the same environment.
+func BenchmarkDeserializeXmlNsheavy(b *testing.B) {
+	selectOperation(b, "deserialize_xml_nsheavy")
+	for _, in := range datasetXmlInputs(b) {
+		raw, err := nsHeavyXML(in.raw)
+		if err != nil {
+			b.Fatalf("Setup failed rewriting XML %s: %v", in.name, err)
+		}
+		plain, err := deserializeXmlEnv(in.raw)
+		if err != nil {
+			b.Fatalf("Setup failed for XML %s: %v", in.name, err)
+		}
+		heavy, err := deserializeXmlEnv(raw)
+		if err != nil {
+			b.Fatalf("Setup failed for namespace-heavy XML %s: %v", in.name, err)
+		}
+		if !environmentsEqual(plain, heavy) {
+			b.Fatalf("Setup failed for XML %s: the namespace-heavy document deserializes differently", in.name)
+		}
+		runObserved(b, "deserialize_xml_nsheavy", in.name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := deserializeXmlEnv(raw); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+	globalMemStats.Groups["deserialize_xml_nsheavy"] = captureMemSnapshot()
+	globalHeap.writeProfile("deserialize_xml_nsheavy")
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// outputSizeRecorder collects the size of the document every serializing
+// operation writes, plain and compressed, for output_sizes.json. The
+// documents are written and compressed outside the timed loop.
+type outputSizeRecorder struct {
+	mu    sync.Mutex
+	sizes report.OutputSizes
+}
+
+var globalOutputSizes = &outputSizeRecorder{sizes: make(report.OutputSizes)}
+
+// record measures doc, the document operation wrote for dataset.
+func (s *outputSizeRecorder) record(operation, dataset string, doc []byte) error {
+	size, err := measureOutputSize(doc)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sizes[operation] == nil {
+		s.sizes[operation] = make(map[string]report.OutputSize)
+	}
+	s.sizes[operation][dataset] = size
+	return nil
+}
+
+func (s *outputSizeRecorder) snapshot() report.OutputSizes {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.sizes
+}
+
+// measureOutputSize compresses doc with gzip and zstd at their default
+// levels, the ones an HTTP server or message broker applies unasked.
+func measureOutputSize(doc []byte) (report.OutputSize, error) {
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	if _, err := w.Write(doc); err != nil {
+		return report.OutputSize{}, err
+	}
+	if err := w.Close(); err != nil {
+		return report.OutputSize{}, err
+	}
+	enc, err := zstd.NewWriter(nil)
+	if err != nil {
+		return report.OutputSize{}, err
+	}
+	defer enc.Close()
+	return report.OutputSize{
+		Bytes:     int64(len(doc)),
+		GzipBytes: int64(gz.Len()),
+		ZstdBytes: int64(len(enc.EncodeAll(doc, nil))),
+	}, nil
+}
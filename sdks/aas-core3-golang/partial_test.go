@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// partialResult is the last b.N of a finished dataset and its time per
+// iteration.
+type partialResult struct {
+	Iterations int     `json:"iterations"`
+	NsPerOp    float64 `json:"ns_per_op"`
+	FinishedAt string  `json:"finished_at"`
+}
+
+// partialOperation is the schema of one file in OUTPUT_DIR/partial.
+type partialOperation struct {
+	Operation string                   `json:"operation"`
+	Datasets  map[string]partialResult `json:"datasets"`
+	Running   string                   `json:"running,omitempty"`
+}
+
+// partialRecorder flushes the progress of every operation to
+// OUTPUT_DIR/partial/<operation>.json as it goes, unlike the side channels
+// TestMain writes at the end, so that a run that dies midway still leaves
+// enough behind for a partial report.
+type partialRecorder struct {
+	mu   sync.Mutex
+	dir  string
+	ops  map[string]*partialOperation
+	last map[string]partialResult
+}
+
+var globalPartial = newPartialRecorder()
+
+// newPartialRecorder returns a recorder writing under OUTPUT_DIR, or one
+// that only keeps state when OUTPUT_DIR is unset.
+func newPartialRecorder() *partialRecorder {
+	p := &partialRecorder{
+		ops:  make(map[string]*partialOperation),
+		last: make(map[string]partialResult),
+	}
+	if outputDir := os.Getenv("OUTPUT_DIR"); outputDir != "" {
+		p.dir = filepath.Join(outputDir, report.PartialDir)
+	}
+	return p
+}
+
+// reset removes the progress files of an earlier run.
+func (p *partialRecorder) reset() {
+	if p.dir != "" {
+		os.RemoveAll(p.dir)
+	}
+}
+
+// begin marks dataset of operation as being measured.
+func (p *partialRecorder) begin(operation, dataset string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	op := p.ops[operation]
+	if op == nil {
+		op = &partialOperation{Operation: operation, Datasets: make(map[string]partialResult)}
+		p.ops[operation] = op
+	}
+	op.Running = dataset
+	p.flush(op)
+}
+
+// measure wraps fn to remember the b.N and elapsed time of its last call,
+// which is the one the benchmark result reports.
+func (p *partialRecorder) measure(operation, dataset string, fn func(b *testing.B)) func(b *testing.B) {
+	return func(b *testing.B) {
+		fn(b)
+		if b.N <= 0 {
+			return
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.last[operation+"/"+dataset] = partialResult{
+			Iterations: b.N,
+			NsPerOp:    float64(b.Elapsed().Nanoseconds()) / float64(b.N),
+		}
+	}
+}
+
+// finish records the result of dataset, if it produced one, and clears the
+// running mark.
+func (p *partialRecorder) finish(operation, dataset string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	op := p.ops[operation]
+	if op == nil {
+		return
+	}
+	if r, ok := p.last[operation+"/"+dataset]; ok {
+		r.FinishedAt = time.Now().UTC().Format(time.RFC3339Nano)
+		op.Datasets[dataset] = r
+		delete(p.last, operation+"/"+dataset)
+	}
+	op.Running = ""
+	p.flush(op)
+}
+
+// flush writes op through a rename, so that a crash never leaves a torn
+// file. Failures only warn.
+func (p *partialRecorder) flush(op *partialOperation) {
+	if p.dir == "" {
+		return
+	}
+	data, err := json.MarshalIndent(op, "", "  ")
+	if err != nil {
+		slog.Warn("failed to marshal progress", "operation", op.Operation, "err", err)
+		return
+	}
+	if err := os.MkdirAll(p.dir, 0755); err != nil {
+		slog.Warn("failed to create progress dir", "err", err)
+		return
+	}
+	path := filepath.Join(p.dir, op.Operation+".json")
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		slog.Warn("failed to write progress", "path", path, "err", err)
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		slog.Warn("failed to write progress", "path", path, "err", err)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+// perf_event_open constants from linux/perf_event.h.
+const (
+	perfTypeHardware         = 0
+	perfCountHWInstructions  = 1
+	perfCountHWCacheMisses   = 3
+	perfCountHWBranchMisses  = 5
+	perfFormatTimeEnabled    = 1 << 0
+	perfFormatTimeRunning    = 1 << 1
+	perfFlagExcludeKernel    = 1 << 5
+	perfFlagExcludeHV        = 1 << 6
+	perfEventFlagFDCloexec   = 1 << 3
+	perfReadFormatValueBytes = 24
+)
+
+// perfEventAttr is struct perf_event_attr up to PERF_ATTR_SIZE_VER5
+// (112 bytes).
+type perfEventAttr struct {
+	Type             uint32
+	Size             uint32
+	Config           uint64
+	SamplePeriod     uint64
+	SampleType       uint64
+	ReadFormat       uint64
+	Flags            uint64
+	WakeupEvents     uint32
+	BPType           uint32
+	Config1          uint64
+	Config2          uint64
+	BranchSampleType uint64
+	SampleRegsUser   uint64
+	SampleStackUser  uint32
+	ClockID          int32
+	SampleRegsIntr   uint64
+	AuxWatermark     uint32
+	SampleMaxStack   uint16
+	_                uint16
+}
+
+// perfConfigs are the PERF_TYPE_HARDWARE configs of perfEvents.
+var perfConfigs = []uint64{perfCountHWInstructions, perfCountHWCacheMisses, perfCountHWBranchMisses}
+
+// perfSet is one perf event fd per hardware event, counting user-space
+// work of the calling thread. Kernel and hypervisor work is excluded so
+// the default perf_event_paranoid setting (2) allows it.
+type perfSet struct {
+	fds []int
+}
+
+func openCounters() (counterSet, error) {
+	s := &perfSet{}
+	for i, config := range perfConfigs {
+		attr := perfEventAttr{
+			Type:       perfTypeHardware,
+			Config:     config,
+			ReadFormat: perfFormatTimeEnabled | perfFormatTimeRunning,
+			Flags:      perfFlagExcludeKernel | perfFlagExcludeHV,
+		}
+		attr.Size = uint32(unsafe.Sizeof(attr))
+		// pid 0, cpu -1: this thread on any CPU.
+		fd, _, errno := syscall.Syscall6(syscall.SYS_PERF_EVENT_OPEN,
+			uintptr(unsafe.Pointer(&attr)), 0, ^uintptr(0), ^uintptr(0), perfEventFlagFDCloexec, 0)
+		if errno != 0 {
+			s.close()
+			return nil, fmt.Errorf("perf_event_open %s: %w", perfEvents[i], errno)
+		}
+		s.fds = append(s.fds, int(fd))
+	}
+	return s, nil
+}
+
+func (s *perfSet) read() ([]int64, error) {
+	counts := make([]int64, len(s.fds))
+	buf := make([]byte, perfReadFormatValueBytes)
+	for i, fd := range s.fds {
+		if n, err := syscall.Read(fd, buf); err != nil || n != len(buf) {
+			return nil, fmt.Errorf("read %s counter: %d bytes, %v", perfEvents[i], n, err)
+		}
+		value := binary.NativeEndian.Uint64(buf[0:])
+		enabled := binary.NativeEndian.Uint64(buf[8:])
+		running := binary.NativeEndian.Uint64(buf[16:])
+		if running > 0 && running < enabled {
+			value = uint64(float64(value) * float64(enabled) / float64(running))
+		}
+		counts[i] = int64(value)
+	}
+	return counts, nil
+}
+
+func (s *perfSet) close() {
+	for _, fd := range s.fds {
+		syscall.Close(fd)
+	}
+	s.fds = nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+func openCounters() (counterSet, error) {
+	return nil, errors.New("hardware counters need Linux perf_event_open")
+}
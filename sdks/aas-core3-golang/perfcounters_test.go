@@ -0,0 +1,133 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+)
+
+// perfEvents names the hardware events counted per group, in the order
+// counterSet.read returns them.
+var perfEvents = []string{"instructions", "cache_misses", "branch_misses"}
+
+// perfGroup is the hardware counter totals of one operation group.
+type perfGroup struct {
+	Iterations   int64 `json:"iterations"`
+	Instructions int64 `json:"instructions"`
+	CacheMisses  int64 `json:"cache_misses"`
+	BranchMisses int64 `json:"branch_misses"`
+}
+
+// hardwareCountersFile is the schema written to hardware_counters.json.
+// Unavailable explains why no group was counted.
+type hardwareCountersFile struct {
+	Events      []string             `json:"events"`
+	Unavailable string               `json:"unavailable,omitempty"`
+	Groups      map[string]perfGroup `json:"groups"`
+}
+
+// counterSet is an open set of hardware counters for the calling OS
+// thread, one per perfEvents entry.
+type counterSet interface {
+	// read returns the counts since the set was opened, scaled up when the
+	// kernel multiplexed the counters.
+	read() ([]int64, error)
+	close()
+}
+
+// perfCounters attributes hardware counter readings to operation groups.
+// Enabled with PERF_COUNTERS=1; Linux only. The benchmark goroutine is
+// pinned to its OS thread while counting, so work the runtime does on
+// other threads (GC workers, scavenger) is not included.
+type perfCounters struct {
+	enabled bool
+	mu      sync.Mutex
+	// unavailable is the first error opening counters; once set, counting
+	// stops for the rest of the run.
+	unavailable string
+	groups      map[string]perfGroup
+}
+
+var globalPerf = &perfCounters{
+	enabled: os.Getenv("PERF_COUNTERS") != "",
+	groups:  make(map[string]perfGroup),
+}
+
+// count wraps a sub-benchmark body so every call, including b.N ramp-up,
+// is counted and charged to the operation group together with its
+// iterations.
+func (p *perfCounters) count(operation string, fn func(b *testing.B)) func(b *testing.B) {
+	if !p.enabled {
+		return fn
+	}
+	return func(b *testing.B) {
+		if p.disabled() {
+			fn(b)
+			return
+		}
+		runtime.LockOSThread()
+		defer runtime.UnlockOSThread()
+		set, err := openCounters()
+		if err != nil {
+			p.disable(err)
+			fn(b)
+			return
+		}
+		defer set.close()
+		fn(b)
+		counts, err := set.read()
+		if err != nil {
+			p.disable(err)
+			return
+		}
+		p.add(operation, int64(b.N), counts)
+	}
+}
+
+func (p *perfCounters) disabled() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.unavailable != ""
+}
+
+func (p *perfCounters) disable(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.unavailable == "" {
+		p.unavailable = err.Error()
+		slog.Warn("hardware counters unavailable", "err", err)
+	}
+}
+
+func (p *perfCounters) add(operation string, iterations int64, counts []int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	g := p.groups[operation]
+	g.Iterations += iterations
+	g.Instructions += counts[0]
+	g.CacheMisses += counts[1]
+	g.BranchMisses += counts[2]
+	p.groups[operation] = g
+}
+
+func (p *perfCounters) snapshot() hardwareCountersFile {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := hardwareCountersFile{
+		Events:      perfEvents,
+		Unavailable: p.unavailable,
+		Groups:      make(map[string]perfGroup, len(p.groups)),
+	}
+	ops := make([]string, 0, len(p.groups))
+	for op := range p.groups {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		out.Groups[op] = p.groups[op]
+	}
+	return out
+}
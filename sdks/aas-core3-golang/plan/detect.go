@@ -0,0 +1,178 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/dataset"
+)
+
+// KnownSDKsFile marks the repository root.
+const KnownSDKsFile = "known-sdks.json"
+
+// knownSDKs is the subset of known-sdks.json the detectors read.
+type knownSDKs struct {
+	SDKBenchmarks []struct {
+		ID         string `json:"id"`
+		Language   string `json:"language"`
+		Package    string `json:"package"`
+		AdapterDir string `json:"adapter_dir"`
+		Enabled    bool   `json:"enabled"`
+	} `json:"sdk_benchmarks"`
+	ServerBenchmarks []struct {
+		ID          string `json:"id"`
+		DockerImage string `json:"docker_image"`
+		AdapterDir  string `json:"adapter_dir"`
+		Enabled     bool   `json:"enabled"`
+	} `json:"server_benchmarks"`
+}
+
+func loadKnownSDKs(root string) (*knownSDKs, error) {
+	data, err := os.ReadFile(filepath.Join(root, KnownSDKsFile))
+	if err != nil {
+		return nil, err
+	}
+	var ks knownSDKs
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", KnownSDKsFile, err)
+	}
+	return &ks, nil
+}
+
+// FindRoot walks up from start to the directory holding known-sdks.json.
+func FindRoot(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, KnownSDKsFile)); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s found in %s or any parent directory", KnownSDKsFile, start)
+		}
+		dir = parent
+	}
+}
+
+// DetectDatasets returns the first candidate directory that holds dataset
+// files, with the sorted dataset names found in it.
+func DetectDatasets(candidates []string) (dir string, names []string) {
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		manifest, err := dataset.Manifest(c, nil)
+		if err != nil || len(manifest) == 0 {
+			continue
+		}
+		seen := make(map[string]bool)
+		for _, fp := range manifest {
+			if !seen[fp.Dataset] {
+				seen[fp.Dataset] = true
+				names = append(names, fp.Dataset)
+			}
+		}
+		sort.Strings(names)
+		return c, names
+	}
+	return "", nil
+}
+
+// DetectSDKs lists the enabled SDK adapters of known-sdks.json with the SDK
+// version each adapter pins. Version is empty when it cannot be determined.
+func DetectSDKs(root string) ([]SDK, error) {
+	ks, err := loadKnownSDKs(root)
+	if err != nil {
+		return nil, err
+	}
+	var sdks []SDK
+	for _, s := range ks.SDKBenchmarks {
+		if !s.Enabled {
+			continue
+		}
+		sdks = append(sdks, SDK{
+			ID:         s.ID,
+			Language:   s.Language,
+			Package:    s.Package,
+			Version:    pinnedVersion(filepath.Join(root, s.AdapterDir), s.Language, s.Package),
+			AdapterDir: s.AdapterDir,
+		})
+	}
+	return sdks, nil
+}
+
+// DetectServers lists the enabled servers of known-sdks.json whose adapter
+// directory has a docker-compose.yml, with the image tag it pins.
+func DetectServers(root string) ([]Server, error) {
+	ks, err := loadKnownSDKs(root)
+	if err != nil {
+		return nil, err
+	}
+	var servers []Server
+	for _, s := range ks.ServerBenchmarks {
+		if !s.Enabled {
+			continue
+		}
+		compose, err := os.ReadFile(filepath.Join(root, s.AdapterDir, "docker-compose.yml"))
+		if err != nil {
+			continue
+		}
+		image := s.DockerImage
+		if m := regexp.MustCompile(`(?m)^\s*image:\s*"?(` + regexp.QuoteMeta(s.DockerImage) + `\S*?)"?\s*$`).FindSubmatch(compose); m != nil {
+			image = string(m[1])
+		}
+		servers = append(servers, Server{ID: s.ID, Image: image, AdapterDir: s.AdapterDir})
+	}
+	return servers, nil
+}
+
+// versionSources maps an adapter language to the manifest file that pins
+// the SDK and a pattern whose first group captures the version. %s is the
+// quoted package name.
+var versionSources = map[string]struct {
+	file    string
+	pattern string
+}{
+	"go":         {"go.mod", `(?m)^\s*(?:require\s+)?%s\s+(v\S+)`},
+	"python":     {"requirements.txt", `(?m)^%s==(\S+)`},
+	"typescript": {"package.json", `"%s"\s*:\s*"([^"]+)"`},
+	"rust":       {"Cargo.toml", `(?m)^%s\s*=\s*(?:"([^"]+)"|\{[^}]*version\s*=\s*"([^"]+)")`},
+	"java":       {"pom.xml", `<artifactId>%s</artifactId>\s*<version>([^<]+)</version>`},
+	"csharp":     {"*.csproj", `<PackageReference\s+Include="%s"\s+Version="([^"]+)"`},
+}
+
+// pinnedVersion reads the SDK version an adapter pins in its manifest.
+func pinnedVersion(adapterDir, language, pkg string) string {
+	src, ok := versionSources[language]
+	if !ok || pkg == "" {
+		return ""
+	}
+	if language == "java" {
+		// Maven coordinates are groupId:artifactId; the pom matches the artifact.
+		pkg = pkg[strings.LastIndex(pkg, ":")+1:]
+	}
+	files, _ := filepath.Glob(filepath.Join(adapterDir, src.file))
+	re := regexp.MustCompile(fmt.Sprintf(src.pattern, regexp.QuoteMeta(pkg)))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		if m := re.FindSubmatch(data); m != nil {
+			for _, g := range m[1:] {
+				if len(g) > 0 {
+					return string(g)
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPinnedVersionReadsAdapterManifests(t *testing.T) {
+	cases := []struct {
+		language, pkg, file, content, want string
+	}{
+		{"go", "github.com/aas-core-works/aas-core3.0-golang", "go.mod",
+			"module x\n\ngo 1.22\n\nrequire github.com/aas-core-works/aas-core3.0-golang v1.0.7\n", "v1.0.7"},
+		{"python", "aas-core3.0", "requirements.txt", "aas-core3.0==1.1.4\npytest==8.4.2\n", "1.1.4"},
+		{"rust", "basyx-rs", "Cargo.toml", "[dependencies]\nbasyx-rs = { version = \"0.2\", features = [] }\n", "0.2"},
+		{"java", "io.github.aas-core-works:aas-core3.0-java", "pom.xml",
+			"<dependency>\n  <artifactId>aas-core3.0-java</artifactId>\n  <version>1.0.1</version>\n</dependency>", "1.0.1"},
+		{"csharp", "AasCore.Aas3_0", "Bench.csproj", `<PackageReference Include="AasCore.Aas3_0" Version="*" />`, "*"},
+		{"python", "aas-core3.0", "requirements.txt", "pytest==8.4.2\n", ""},
+	}
+	for _, c := range cases {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, c.file), []byte(c.content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if got := pinnedVersion(dir, c.language, c.pkg); got != c.want {
+			t.Errorf("%s %s: got version %q, want %q", c.language, c.pkg, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+// Package plan defines the benchmark plan written by "aasbench init" and
+// read by "aasbench run --plan": which datasets to use, which SDK adapters
+// and servers take part, and how many runs to record.
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// FormatVersion is the plan file format version written by init.
+const FormatVersion = 1
+
+// SDK is one SDK adapter taking part in the plan.
+type SDK struct {
+	ID         string `json:"id"`
+	Language   string `json:"language"`
+	Package    string `json:"package"`
+	Version    string `json:"version"`
+	AdapterDir string `json:"adapter_dir"`
+}
+
+// Server is one server under test taking part in the plan.
+type Server struct {
+	ID         string `json:"id"`
+	Image      string `json:"image"`
+	AdapterDir string `json:"adapter_dir"`
+}
+
+// Plan is the schema of a plan file.
+type Plan struct {
+	Version     int      `json:"version"`
+	DatasetsDir string   `json:"datasets_dir"`
+	Datasets    []string `json:"datasets"`
+	OutputDir   string   `json:"output_dir"`
+	Count       int      `json:"count"`
+	HeapProfile bool     `json:"heap_profile"`
+	SDKs        []SDK    `json:"sdks"`
+	Servers     []Server `json:"servers"`
+}
+
+// Load reads a plan file.
+func Load(path string) (*Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Plan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &p, nil
+}
+
+// Write marshals p as indented JSON to path.
+func Write(path string, p *Plan) error {
+	out, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal plan: %w", err)
+	}
+	if err := os.WriteFile(path, append(out, '\n'), 0644); err != nil {
+		return fmt.Errorf("write plan: %w", err)
+	}
+	return nil
+}
+
+// Validate checks p for problems that would make a run fail or be
+// meaningless and returns them as human-readable strings.
+func (p *Plan) Validate() []string {
+	var problems []string
+	if p.Version != FormatVersion {
+		problems = append(problems, fmt.Sprintf("unsupported plan version %d (want %d)", p.Version, FormatVersion))
+	}
+	if p.DatasetsDir == "" {
+		problems = append(problems, "datasets_dir is empty")
+	}
+	if len(p.Datasets) == 0 {
+		problems = append(problems, "plan selects no datasets")
+	}
+	if p.OutputDir == "" {
+		problems = append(problems, "output_dir is empty")
+	}
+	if p.Count < 1 {
+		problems = append(problems, fmt.Sprintf("count must be at least 1, got %d", p.Count))
+	}
+	if len(p.SDKs) == 0 && len(p.Servers) == 0 {
+		problems = append(problems, "plan selects no SDKs or servers")
+	}
+
+	seen := make(map[string]bool)
+	for _, s := range p.SDKs {
+		if s.ID == "" {
+			problems = append(problems, "sdk entry without id")
+		} else if seen[s.ID] {
+			problems = append(problems, fmt.Sprintf("sdk %q listed twice", s.ID))
+		}
+		seen[s.ID] = true
+	}
+	for _, s := range p.Servers {
+		if s.ID == "" {
+			problems = append(problems, "server entry without id")
+		} else if seen[s.ID] {
+			problems = append(problems, fmt.Sprintf("server %q listed twice", s.ID))
+		}
+		seen[s.ID] = true
+	}
+	return problems
+}
+
+// HasSDK reports whether the plan includes the SDK adapter with the given ID.
+func (p *Plan) HasSDK(id string) bool {
+	for _, s := range p.SDKs {
+		if s.ID == id {
+			return true
+		}
+	}
+	return false
+}
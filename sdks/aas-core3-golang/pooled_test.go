@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	aas "github.com/aas-core-works/aas-core3.0-golang/jsonization"
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+)
+
+// pooledDecoder is the decoder state deserialize_pooled reuses across
+// iterations: a json.Decoder whose read buffer stays allocated, reading
+// from a bytes.Reader that is reset onto each input, into a top-level map
+// that is cleared rather than reallocated. The SDK only builds an
+// environment from a complete jsonable tree, so the nested values are
+// still allocated per call; what the variant saves is the avoidable part.
+type pooledDecoder struct {
+	src  *bytes.Reader
+	dec  *json.Decoder
+	root map[string]interface{}
+}
+
+func newPooledDecoder() *pooledDecoder {
+	src := bytes.NewReader(nil)
+	return &pooledDecoder{src: src, dec: json.NewDecoder(src), root: make(map[string]interface{})}
+}
+
+// decoderPool hands out pooledDecoders; a decoder that failed is dropped
+// rather than returned, since its stream state is undefined.
+var decoderPool = sync.Pool{New: func() interface{} { return newPooledDecoder() }}
+
+// deserialize decodes raw with d's buffers into an AAS Environment.
+func (d *pooledDecoder) deserialize(raw []byte) (aastypes.IEnvironment, error) {
+	d.src.Reset(raw)
+	clear(d.root)
+	if err := d.dec.Decode(&d.root); err != nil {
+		return nil, fmt.Errorf("json decode: %w", err)
+	}
+	env, deserErr := aas.EnvironmentFromJsonable(d.root)
+	if deserErr != nil {
+		return nil, fmt.Errorf("environment_from_jsonable: %s", deserErr.Error())
+	}
+	return env, nil
+}
+
+// BenchmarkDeserializePooled benchmarks JSON -> AAS Environment
+// deserialization with pooled decoder state, for comparison with
+// deserialize. Opt-in with POOLED_BENCHMARKS=1 (aasbench run --pooled).
+func BenchmarkDeserializePooled(b *testing.B) {
+	selectOperation(b, "deserialize_pooled")
+	if os.Getenv("POOLED_BENCHMARKS") != "1" {
+		b.Skip("set POOLED_BENCHMARKS=1 to benchmark deserialize_pooled")
+	}
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		raw := loadRawJSON(b, f)
+		runObserved(b, "deserialize_pooled", name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				d := decoderPool.Get().(*pooledDecoder)
+				env, err := d.deserialize(raw)
+				if err != nil {
+					b.Fatal(err)
+				}
+				decoderPool.Put(d)
+				_ = env
+			}
+		})
+	}
+	globalMemStats.Groups["deserialize_pooled"] = captureMemSnapshot()
+	globalHeap.writeProfile("deserialize_pooled")
+}
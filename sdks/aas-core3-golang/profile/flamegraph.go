@@ -0,0 +1,167 @@
+package profile
+
+import (
+	"fmt"
+	"hash/fnv"
+	"html"
+	"sort"
+	"strings"
+)
+
+// Folded sums the sampleType column of p per stack, in the folded format
+// of flamegraph tools: the frames root first, joined by ";".
+func (p *Profile) Folded(sampleType string) (map[string]int64, error) {
+	idx := p.Index(sampleType)
+	if idx < 0 {
+		return nil, fmt.Errorf("profile has no %s samples", sampleType)
+	}
+	folded := make(map[string]int64)
+	for _, s := range p.Samples {
+		if s.Values[idx] == 0 {
+			continue
+		}
+		frames := make([]string, len(s.Stack))
+		for i, fn := range s.Stack {
+			frames[len(frames)-1-i] = fn
+		}
+		folded[strings.Join(frames, ";")] += s.Values[idx]
+	}
+	return folded, nil
+}
+
+// Flamegraph accumulates stacks into a tree whose frames are as wide as
+// the total value of the stacks through them, and renders it as an SVG.
+type Flamegraph struct {
+	// Title heads the rendered graph.
+	Title string
+	// Unit is the unit of the values, as in the profile's sample type
+	// ("nanoseconds" is shown in milliseconds).
+	Unit string
+	root frame
+}
+
+// frame is one function on one path from the root.
+type frame struct {
+	value    int64
+	children map[string]*frame
+}
+
+// Add adds value to every frame of stack, given root first.
+func (f *Flamegraph) Add(stack []string, value int64) {
+	node := &f.root
+	node.value += value
+	for _, fn := range stack {
+		if node.children == nil {
+			node.children = make(map[string]*frame)
+		}
+		child := node.children[fn]
+		if child == nil {
+			child = &frame{}
+			node.children[fn] = child
+		}
+		child.value += value
+		node = child
+	}
+}
+
+// AddFolded adds folded stacks, as Folded returns them, under the frames
+// of prefix.
+func (f *Flamegraph) AddFolded(prefix []string, folded map[string]int64) {
+	for stack, value := range folded {
+		f.Add(append(append([]string(nil), prefix...), strings.Split(stack, ";")...), value)
+	}
+}
+
+// Total is the value of all stacks added.
+func (f *Flamegraph) Total() int64 { return f.root.value }
+
+// Dimensions of a flamegraph, in pixels.
+const (
+	flameWidth       = 1200
+	flameFrameHeight = 16
+	flameTitleHeight = 28
+	flameCharWidth   = 6.5
+	// Frames narrower than this are left out, with their children.
+	flameMinWidth = 0.3
+)
+
+// SVG renders the graph with the root at the bottom and callees stacked on
+// their callers, siblings in name order as flamegraph.pl sorts them. Every
+// frame carries a tooltip with its function, value and share of the total.
+func (f *Flamegraph) SVG() string {
+	depth := f.root.depth()
+	height := flameTitleHeight + depth*flameFrameHeight + 4
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="system-ui, sans-serif" font-size="11">`+"\n",
+		flameWidth, height, flameWidth, height)
+	fmt.Fprintf(&b, `<title>%s</title>`+"\n", html.EscapeString(f.Title))
+	fmt.Fprintf(&b, `<text x="0" y="18" font-size="14" font-weight="600" fill="#1f2328">%s</text>`+"\n", html.EscapeString(f.Title))
+	if f.root.value > 0 {
+		scale := float64(flameWidth) / float64(f.root.value)
+		f.writeFrame(&b, "all", &f.root, 0, 0, scale, height-4)
+	}
+	b.WriteString("</svg>\n")
+	return b.String()
+}
+
+func (f *Flamegraph) writeFrame(b *strings.Builder, name string, node *frame, level int, x, scale float64, bottom int) {
+	width := float64(node.value) * scale
+	if width < flameMinWidth {
+		return
+	}
+	y := bottom - (level+1)*flameFrameHeight
+	share := float64(node.value) / float64(f.root.value) * 100
+	fmt.Fprintf(b, `<g><title>%s (%s, %.2f%%)</title><rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s" rx="2"/>`,
+		html.EscapeString(name), f.format(node.value), share, x, y, width, flameFrameHeight-1, frameColor(name))
+	if label := fitLabel(name, width); label != "" {
+		fmt.Fprintf(b, `<text x="%.1f" y="%d" fill="#1f2328">%s</text>`, x+3, y+flameFrameHeight-4, html.EscapeString(label))
+	}
+	b.WriteString("</g>\n")
+	names := make([]string, 0, len(node.children))
+	for child := range node.children {
+		names = append(names, child)
+	}
+	sort.Strings(names)
+	for _, child := range names {
+		c := node.children[child]
+		f.writeFrame(b, child, c, level+1, x, scale, bottom)
+		x += float64(c.value) * scale
+	}
+}
+
+func (n *frame) depth() int {
+	d := 0
+	for _, c := range n.children {
+		d = max(d, c.depth())
+	}
+	return d + 1
+}
+
+func (f *Flamegraph) format(v int64) string {
+	if f.Unit == "nanoseconds" {
+		return fmt.Sprintf("%.2f ms", float64(v)/1e6)
+	}
+	return fmt.Sprintf("%d %s", v, f.Unit)
+}
+
+// fitLabel shortens name to the frame's width, or returns "" when the
+// frame is too narrow for any of it.
+func fitLabel(name string, width float64) string {
+	fit := int((width - 6) / flameCharWidth)
+	switch {
+	case fit >= len(name):
+		return name
+	case fit < 3:
+		return ""
+	}
+	return name[:fit-2] + ".."
+}
+
+// frameColor picks a warm color from the function's name, so a function
+// has the same color in every graph.
+func frameColor(name string) string {
+	h := fnv.New32a()
+	h.Write([]byte(name))
+	v := h.Sum32()
+	return fmt.Sprintf("rgb(%d,%d,%d)", 205+v%50, 80+(v>>8)%130, 40+(v>>16)%50)
+}
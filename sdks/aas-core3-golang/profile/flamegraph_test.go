@@ -0,0 +1,54 @@
+package profile
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFoldedSumsStacksRootFirst(t *testing.T) {
+	p := &Profile{
+		SampleTypes: []ValueType{{"samples", "count"}, {"cpu", "nanoseconds"}},
+		Samples: []Sample{
+			{Stack: []string{"leaf", "mid", "main"}, Values: []int64{1, 10}},
+			{Stack: []string{"leaf", "mid", "main"}, Values: []int64{2, 20}},
+			{Stack: []string{"other", "main"}, Values: []int64{1, 5}},
+			{Stack: []string{"idle", "main"}, Values: []int64{0, 0}},
+		},
+	}
+	folded, err := p.Folded("cpu")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(folded) != 2 || folded["main;mid;leaf"] != 30 || folded["main;other"] != 5 {
+		t.Errorf("folded = %v", folded)
+	}
+	if _, err := p.Folded("alloc_space"); err == nil {
+		t.Error("Folded accepted a sample type the profile lacks")
+	}
+}
+
+func TestFlamegraphSVG(t *testing.T) {
+	g := &Flamegraph{Title: "deserialize <cpu>", Unit: "nanoseconds"}
+	g.AddFolded([]string{"mixed"}, map[string]int64{"main;parse": 3e6, "main;verify": 1e6})
+	g.Add([]string{"wide", "main", "parse"}, 4e6)
+	if g.Total() != 8e6 {
+		t.Errorf("total = %d, want 8e6", g.Total())
+	}
+	svg := g.SVG()
+	for _, want := range []string{
+		`<svg xmlns="http://www.w3.org/2000/svg"`,
+		"deserialize &lt;cpu&gt;",
+		"<title>all (8.00 ms, 100.00%)</title>",
+		"<title>parse (3.00 ms, 37.50%)</title>",
+		`<rect x="0.0" y="`,
+		`width="600.0"`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG lacks %q:\n%s", want, svg)
+		}
+	}
+	// mixed sorts before wide, so wide's frames start halfway.
+	if !strings.Contains(svg, `<title>wide (4.00 ms, 50.00%)</title><rect x="600.0"`) {
+		t.Errorf("wide is not placed after mixed:\n%s", svg)
+	}
+}
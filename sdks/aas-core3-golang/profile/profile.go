@@ -0,0 +1,173 @@
+// Package profile decodes the subset of the pprof protobuf format needed to
+// summarize Go heap profiles into allocation hotspots and to render CPU
+// profiles as flamegraphs. It avoids a dependency on github.com/google/pprof
+// so the harness keeps a single pinned dependency.
+package profile
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ValueType names one value column of every sample (e.g. alloc_space/bytes).
+type ValueType struct {
+	Type string
+	Unit string
+}
+
+// Sample is one stack with its values, one per Profile.SampleTypes entry.
+type Sample struct {
+	// Stack holds function names, leaf first, with inlined frames expanded.
+	Stack  []string
+	Values []int64
+}
+
+// Profile is a decoded pprof profile.
+type Profile struct {
+	SampleTypes []ValueType
+	Samples     []Sample
+}
+
+// Parse decodes a (optionally gzip-compressed) pprof protobuf profile.
+func Parse(data []byte) (*Profile, error) {
+	if len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("gunzip profile: %w", err)
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, fmt.Errorf("gunzip profile: %w", err)
+		}
+	}
+	return decodeProfile(data)
+}
+
+// Index returns the value column for sampleType, or -1 if absent.
+func (p *Profile) Index(sampleType string) int {
+	for i, vt := range p.SampleTypes {
+		if vt.Type == sampleType {
+			return i
+		}
+	}
+	return -1
+}
+
+// Site is the allocation total attributed to one function.
+type Site struct {
+	Function string `json:"function"`
+	Bytes    int64  `json:"bytes"`
+	Objects  int64  `json:"objects"`
+}
+
+// Sites maps a function name to its allocation totals.
+type Sites map[string]Site
+
+// overheadPrefixes identify frames of the profiling machinery itself.
+// Writing and decoding a snapshot allocates after its counters were read, so
+// without this every delta would be topped by the profiler's own work.
+var overheadPrefixes = []string{
+	"runtime/pprof.",
+	reflect.TypeOf(Profile{}).PkgPath() + ".Parse",
+	reflect.TypeOf(Profile{}).PkgPath() + ".decode",
+}
+
+// AllocationSites aggregates alloc_space and alloc_objects of a heap profile
+// by allocation site: the first frame on each stack outside the runtime
+// package, so that runtime.makeslice and friends are charged to their caller.
+// Samples allocated by the profiling machinery are dropped.
+func (p *Profile) AllocationSites() (Sites, error) {
+	spaceIdx, objectsIdx := p.Index("alloc_space"), p.Index("alloc_objects")
+	if spaceIdx < 0 || objectsIdx < 0 {
+		return nil, errors.New("profile has no alloc_space/alloc_objects samples (not a heap profile?)")
+	}
+	sites := make(Sites)
+	for _, s := range p.Samples {
+		if isOverhead(s.Stack) {
+			continue
+		}
+		fn := allocationSite(s.Stack)
+		site := sites[fn]
+		site.Function = fn
+		site.Bytes += s.Values[spaceIdx]
+		site.Objects += s.Values[objectsIdx]
+		sites[fn] = site
+	}
+	return sites, nil
+}
+
+func isOverhead(stack []string) bool {
+	for _, fn := range stack {
+		for _, prefix := range overheadPrefixes {
+			if strings.HasPrefix(fn, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func allocationSite(stack []string) string {
+	for _, fn := range stack {
+		if !strings.HasPrefix(fn, "runtime.") {
+			return fn
+		}
+	}
+	if len(stack) > 0 {
+		return stack[0]
+	}
+	return "<unknown>"
+}
+
+// Sub returns s minus base per function, dropping sites that did not grow.
+// Heap profile allocation counters are cumulative since process start, so
+// this isolates the allocations made between two snapshots.
+func (s Sites) Sub(base Sites) Sites {
+	out := make(Sites)
+	for fn, site := range s {
+		prev := base[fn]
+		d := Site{Function: fn, Bytes: site.Bytes - prev.Bytes, Objects: site.Objects - prev.Objects}
+		if d.Bytes > 0 || d.Objects > 0 {
+			out[fn] = d
+		}
+	}
+	return out
+}
+
+// Add accumulates other into s.
+func (s Sites) Add(other Sites) {
+	for fn, site := range other {
+		acc := s[fn]
+		acc.Function = fn
+		acc.Bytes += site.Bytes
+		acc.Objects += site.Objects
+		s[fn] = acc
+	}
+}
+
+// Top returns the n sites with the most bytes allocated, ties broken by
+// object count and then name for stable output.
+func (s Sites) Top(n int) []Site {
+	list := make([]Site, 0, len(s))
+	for _, site := range s {
+		list = append(list, site)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Bytes != list[j].Bytes {
+			return list[i].Bytes > list[j].Bytes
+		}
+		if list[i].Objects != list[j].Objects {
+			return list[i].Objects > list[j].Objects
+		}
+		return list[i].Function < list[j].Function
+	})
+	if n >= 0 && len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
@@ -0,0 +1,64 @@
+package profile
+
+import (
+	"bytes"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"testing"
+)
+
+var sink []byte
+
+//go:noinline
+func allocateLargeBuffers() {
+	for i := 0; i < 8; i++ {
+		sink = make([]byte, 1<<20)
+	}
+}
+
+func heapSites(t *testing.T) Sites {
+	t.Helper()
+	runtime.GC()
+	var buf bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	p, err := Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	sites, err := p.AllocationSites()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sites
+}
+
+func TestAllocationSitesAttributesToCaller(t *testing.T) {
+	base := heapSites(t)
+	allocateLargeBuffers()
+	delta := heapSites(t).Sub(base)
+
+	var found *Site
+	for _, s := range delta.Top(-1) {
+		if strings.HasSuffix(s.Function, ".allocateLargeBuffers") {
+			s := s
+			found = &s
+			break
+		}
+	}
+	if found == nil {
+		t.Fatalf("allocateLargeBuffers not among sites: %+v", delta.Top(10))
+	}
+	if found.Bytes < 1<<20 {
+		t.Errorf("bytes = %d, want at least one sampled 1 MiB buffer", found.Bytes)
+	}
+}
+
+func TestParseRejectsTruncatedInput(t *testing.T) {
+	// Field 6 (string table), length 10, but only 2 payload bytes follow.
+	if _, err := Parse([]byte{0x32, 0x0a, 'a', 'b'}); err == nil {
+		t.Error("expected error for truncated profile")
+	}
+}
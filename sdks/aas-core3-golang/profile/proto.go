@@ -0,0 +1,304 @@
+package profile
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Field numbers from github.com/google/pprof/proto/profile.proto.
+const (
+	profileSampleType  = 1
+	profileSample      = 2
+	profileLocation    = 4
+	profileFunction    = 5
+	profileStringTable = 6
+
+	valueTypeType = 1
+	valueTypeUnit = 2
+
+	sampleLocationID = 1
+	sampleValue      = 2
+
+	locationID   = 1
+	locationLine = 4
+
+	lineFunctionID = 1
+
+	functionID   = 1
+	functionName = 2
+)
+
+// Protobuf wire types.
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+	wireFixed32 = 5
+)
+
+var errTruncated = errors.New("truncated protobuf")
+
+// buffer walks the fields of one protobuf message.
+type buffer struct {
+	data []byte
+	pos  int
+}
+
+func (b *buffer) varint() (uint64, error) {
+	var v uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if b.pos >= len(b.data) {
+			return 0, errTruncated
+		}
+		c := b.data[b.pos]
+		b.pos++
+		v |= uint64(c&0x7f) << shift
+		if c < 0x80 {
+			return v, nil
+		}
+	}
+	return 0, errors.New("varint overflow")
+}
+
+// next returns the next field number, wire type, and for varint fields the
+// value or for length-delimited fields the payload.
+func (b *buffer) next() (field int, wire int, v uint64, payload []byte, err error) {
+	key, err := b.varint()
+	if err != nil {
+		return 0, 0, 0, nil, err
+	}
+	field, wire = int(key>>3), int(key&7)
+	switch wire {
+	case wireVarint:
+		v, err = b.varint()
+	case wireFixed64:
+		if b.pos+8 > len(b.data) {
+			return 0, 0, 0, nil, errTruncated
+		}
+		b.pos += 8
+	case wireFixed32:
+		if b.pos+4 > len(b.data) {
+			return 0, 0, 0, nil, errTruncated
+		}
+		b.pos += 4
+	case wireBytes:
+		var n uint64
+		if n, err = b.varint(); err != nil {
+			return 0, 0, 0, nil, err
+		}
+		if uint64(len(b.data)-b.pos) < n {
+			return 0, 0, 0, nil, errTruncated
+		}
+		payload = b.data[b.pos : b.pos+int(n)]
+		b.pos += int(n)
+	default:
+		err = fmt.Errorf("unsupported wire type %d", wire)
+	}
+	return field, wire, v, payload, err
+}
+
+func (b *buffer) done() bool { return b.pos >= len(b.data) }
+
+// appendUints decodes a repeated integer field, which may be packed.
+func appendUints(dst []uint64, wire int, v uint64, payload []byte) ([]uint64, error) {
+	if wire == wireVarint {
+		return append(dst, v), nil
+	}
+	packed := &buffer{data: payload}
+	for !packed.done() {
+		x, err := packed.varint()
+		if err != nil {
+			return nil, err
+		}
+		dst = append(dst, x)
+	}
+	return dst, nil
+}
+
+type rawSample struct {
+	locations []uint64
+	values    []uint64
+}
+
+type rawLocation struct {
+	functions []uint64 // leaf first
+}
+
+func decodeProfile(data []byte) (*Profile, error) {
+	var (
+		strtab     []string
+		types      [][2]uint64
+		samples    []rawSample
+		locations  = make(map[uint64]rawLocation)
+		functionNm = make(map[uint64]uint64)
+	)
+
+	b := &buffer{data: data}
+	for !b.done() {
+		field, wire, _, payload, err := b.next()
+		if err != nil {
+			return nil, err
+		}
+		if wire != wireBytes {
+			continue
+		}
+		switch field {
+		case profileStringTable:
+			strtab = append(strtab, string(payload))
+		case profileSampleType:
+			vt, err := decodeValueType(payload)
+			if err != nil {
+				return nil, err
+			}
+			types = append(types, vt)
+		case profileSample:
+			s, err := decodeSample(payload)
+			if err != nil {
+				return nil, err
+			}
+			samples = append(samples, s)
+		case profileLocation:
+			id, loc, err := decodeLocation(payload)
+			if err != nil {
+				return nil, err
+			}
+			locations[id] = loc
+		case profileFunction:
+			id, name, err := decodeFunction(payload)
+			if err != nil {
+				return nil, err
+			}
+			functionNm[id] = name
+		}
+	}
+
+	str := func(i uint64) string {
+		if i < uint64(len(strtab)) {
+			return strtab[i]
+		}
+		return ""
+	}
+
+	p := &Profile{}
+	for _, vt := range types {
+		p.SampleTypes = append(p.SampleTypes, ValueType{Type: str(vt[0]), Unit: str(vt[1])})
+	}
+	for _, rs := range samples {
+		if len(rs.values) != len(p.SampleTypes) {
+			return nil, fmt.Errorf("sample has %d values, profile declares %d types", len(rs.values), len(p.SampleTypes))
+		}
+		s := Sample{Values: make([]int64, len(rs.values))}
+		for i, v := range rs.values {
+			s.Values[i] = int64(v)
+		}
+		for _, locID := range rs.locations {
+			for _, fnID := range locations[locID].functions {
+				s.Stack = append(s.Stack, str(functionNm[fnID]))
+			}
+		}
+		p.Samples = append(p.Samples, s)
+	}
+	return p, nil
+}
+
+func decodeValueType(data []byte) ([2]uint64, error) {
+	var vt [2]uint64
+	b := &buffer{data: data}
+	for !b.done() {
+		field, wire, v, _, err := b.next()
+		if err != nil {
+			return vt, err
+		}
+		if wire != wireVarint {
+			continue
+		}
+		switch field {
+		case valueTypeType:
+			vt[0] = v
+		case valueTypeUnit:
+			vt[1] = v
+		}
+	}
+	return vt, nil
+}
+
+func decodeSample(data []byte) (rawSample, error) {
+	var s rawSample
+	b := &buffer{data: data}
+	for !b.done() {
+		field, wire, v, payload, err := b.next()
+		if err != nil {
+			return s, err
+		}
+		switch field {
+		case sampleLocationID:
+			s.locations, err = appendUints(s.locations, wire, v, payload)
+		case sampleValue:
+			s.values, err = appendUints(s.values, wire, v, payload)
+		}
+		if err != nil {
+			return s, err
+		}
+	}
+	return s, nil
+}
+
+func decodeLocation(data []byte) (uint64, rawLocation, error) {
+	var (
+		id  uint64
+		loc rawLocation
+	)
+	b := &buffer{data: data}
+	for !b.done() {
+		field, wire, v, payload, err := b.next()
+		if err != nil {
+			return 0, loc, err
+		}
+		switch {
+		case field == locationID && wire == wireVarint:
+			id = v
+		case field == locationLine && wire == wireBytes:
+			fnID, err := decodeLine(payload)
+			if err != nil {
+				return 0, loc, err
+			}
+			loc.functions = append(loc.functions, fnID)
+		}
+	}
+	return id, loc, nil
+}
+
+func decodeLine(data []byte) (uint64, error) {
+	var fnID uint64
+	b := &buffer{data: data}
+	for !b.done() {
+		field, wire, v, _, err := b.next()
+		if err != nil {
+			return 0, err
+		}
+		if field == lineFunctionID && wire == wireVarint {
+			fnID = v
+		}
+	}
+	return fnID, nil
+}
+
+func decodeFunction(data []byte) (id, name uint64, err error) {
+	b := &buffer{data: data}
+	for !b.done() {
+		field, wire, v, _, err := b.next()
+		if err != nil {
+			return 0, 0, err
+		}
+		if wire != wireVarint {
+			continue
+		}
+		switch field {
+		case functionID:
+			id = v
+		case functionName:
+			name = v
+		}
+	}
+	return id, name, nil
+}
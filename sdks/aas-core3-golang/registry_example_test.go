@@ -0,0 +1,39 @@
+//go:build example_operations
+
+package main
+
+import (
+	"testing"
+
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+)
+
+// An example extension operation, built only with
+// GOFLAGS=-tags=example_operations. A vendor file follows the same shape:
+// register the operation under its namespace and add a Benchmark function
+// named after it. operations.json maps the benchmark name to the ID, so
+// neither the alias table nor emit-report need to change.
+func init() {
+	registerOperation(benchOperation{
+		ID:        "example:count_properties",
+		Benchmark: "ExampleCountProperties",
+		Needs:     []string{"deserialize"},
+		Setup:     setupEnvironment,
+		Run: func(input interface{}) error {
+			count := 0
+			input.(aastypes.IEnvironment).Descend(func(node aastypes.IClass) bool {
+				if _, ok := node.(aastypes.IProperty); ok {
+					count++
+				}
+				return false // continue descending
+			})
+			_ = count
+			return nil
+		},
+	})
+}
+
+// BenchmarkExampleCountProperties benchmarks example:count_properties.
+func BenchmarkExampleCountProperties(b *testing.B) {
+	benchmarkOperation(b, "example:count_properties")
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// benchOperation is an operation of the registry. A vendor-specific or
+// experimental operation registers one from an init function in its own
+// build-tagged file, together with a one-line Benchmark function that
+// calls benchmarkOperation; see registry_example_test.go.
+type benchOperation struct {
+	// ID is the operation ID; anything but a canonical operation must be
+	// namespaced ("vendorx:transform").
+	ID string
+	// Benchmark is the Go benchmark name without the "Benchmark" prefix.
+	Benchmark string
+	// Tracks are the tracks the operation declares (report.TrackCore, ...).
+	Tracks []string
+	// Needs are the operations whose work Setup repeats; selecting the
+	// operation with -operations selects them too.
+	Needs []string
+	// Payload adds the blob-heavy dataset to the inputs.
+	Payload bool
+	// Peak records the operation's peak intermediate allocation per
+	// dataset, probed outside the timed loop; see recordPeak.
+	Peak bool
+	// Setup prepares one dataset's input from its raw JSON outside the
+	// timed loop.
+	Setup func(raw []byte) (interface{}, error)
+	// Run executes the operation once on Setup's result.
+	Run func(input interface{}) error
+	// Metrics, if set, counts what one Run does on Setup's result, by unit
+	// ("elements/op"). It is called outside the timed loop and reported
+	// with b.ReportMetric, so the report's custom_metrics carry it.
+	Metrics func(input interface{}) map[string]float64
+	// Output, if set, returns the document one Run writes on Setup's
+	// result. It is called outside the timed loop, and the document's
+	// size, plain and compressed, goes to output_sizes.json.
+	Output func(input interface{}) ([]byte, error)
+}
+
+// operationRegistry holds the registered operations by ID.
+var operationRegistry = make(map[string]*benchOperation)
+
+// registerOperation adds op to the registry. An invalid or duplicate ID is
+// a programming error of the registering file and panics.
+func registerOperation(op benchOperation) {
+	if err := report.CheckOperationID(op.ID); err != nil {
+		panic(err)
+	}
+	if _, dup := operationRegistry[op.ID]; dup {
+		panic(fmt.Sprintf("operation %q registered twice", op.ID))
+	}
+	operationRegistry[op.ID] = &op
+}
+
+// setupEnvironment deserializes a dataset, the input of most operations.
+func setupEnvironment(raw []byte) (interface{}, error) {
+	return deserializeEnv(raw)
+}
+
+// setupAdapterEnvironment deserializes a dataset with the SDK adapter in
+// use, the input of the core operations.
+func setupAdapterEnvironment(raw []byte) (interface{}, error) {
+	return sdkAdapter.Deserialize(raw)
+}
+
+// The five core operations, run through the SDK adapter in use.
+func init() {
+	registerOperation(benchOperation{
+		ID: "deserialize", Benchmark: "Deserialize", Tracks: []string{report.TrackCore}, Payload: true,
+		Setup: func(raw []byte) (interface{}, error) { return raw, nil },
+		Run: func(input interface{}) error {
+			_, err := sdkAdapter.Deserialize(input.([]byte))
+			return err
+		},
+	})
+	registerOperation(benchOperation{
+		ID: "validate", Benchmark: "Validate", Tracks: []string{report.TrackCore, report.TrackValidation}, Needs: []string{"deserialize"},
+		Setup: setupAdapterEnvironment,
+		Run: func(input interface{}) error {
+			_ = sdkAdapter.Validate(input)
+			return nil
+		},
+		Metrics: func(input interface{}) map[string]float64 {
+			return map[string]float64{"violations/op": float64(sdkAdapter.Validate(input))}
+		},
+	})
+	registerOperation(benchOperation{
+		ID: "traverse", Benchmark: "Traverse", Tracks: []string{report.TrackCore}, Needs: []string{"deserialize"},
+		Setup: setupAdapterEnvironment,
+		Run: func(input interface{}) error {
+			_ = sdkAdapter.Traverse(input)
+			return nil
+		},
+		Metrics: func(input interface{}) map[string]float64 {
+			return map[string]float64{"elements/op": float64(sdkAdapter.Traverse(input))}
+		},
+	})
+	registerOperation(benchOperation{
+		ID: "update", Benchmark: "Update", Tracks: []string{report.TrackCore}, Needs: []string{"deserialize"},
+		Setup: setupAdapterEnvironment,
+		Run: func(input interface{}) error {
+			return sdkAdapter.Update(input)
+		},
+	})
+	registerOperation(benchOperation{
+		ID: "serialize", Benchmark: "Serialize", Tracks: []string{report.TrackCore}, Needs: []string{"deserialize"}, Payload: true, Peak: true,
+		Setup: setupAdapterEnvironment,
+		Run: func(input interface{}) error {
+			_, err := sdkAdapter.Serialize(input)
+			return err
+		},
+		Output: sdkAdapter.Serialize,
+	})
+}
+
+// updateProperties appends to the value of every Property, then restores
+// the originals so every iteration starts identically.
+func updateProperties(env aastypes.IEnvironment) {
+	touchedProps := make([]aastypes.IProperty, 0, 128)
+	originalVals := make([]string, 0, 128)
+	env.Descend(func(node aastypes.IClass) bool {
+		if prop, ok := node.(aastypes.IProperty); ok {
+			if val := prop.Value(); val != nil {
+				original := *val
+				updated := original + "_updated"
+				prop.SetValue(&updated)
+				touchedProps = append(touchedProps, prop)
+				originalVals = append(originalVals, original)
+			}
+		}
+		return false // continue descending
+	})
+	for idx, prop := range touchedProps {
+		original := originalVals[idx]
+		prop.SetValue(&original)
+	}
+}
+
+// benchmarkOperation runs the registered operation id on every dataset,
+// observed like the hand-written benchmarks.
+func benchmarkOperation(b *testing.B, id string) {
+	op, ok := operationRegistry[id]
+	if !ok {
+		b.Fatalf("operation %q is not registered", id)
+	}
+	selectOperation(b, id)
+	files := datasetFiles(b)
+	if op.Payload {
+		files = payloadDatasetFiles(b)
+	}
+	for _, f := range files {
+		name := datasetName(f)
+		input, err := op.Setup(loadRawJSON(b, f))
+		if err != nil {
+			b.Fatalf("Setup failed for %s: %v", name, err)
+		}
+		if op.Peak && globalSelection.operation(op.ID) && globalSelection.dataset(name) {
+			if err := recordPeak(op.ID, name, func() error { return op.Run(input) }); err != nil {
+				b.Fatalf("Peak probe failed for %s: %v", name, err)
+			}
+		}
+		if op.Output != nil && globalSelection.operation(op.ID) && globalSelection.dataset(name) {
+			doc, err := op.Output(input)
+			if err == nil {
+				err = globalOutputSizes.record(op.ID, name, doc)
+			}
+			if err != nil {
+				b.Fatalf("Output size failed for %s: %v", name, err)
+			}
+		}
+		var metrics map[string]float64
+		if op.Metrics != nil {
+			metrics = op.Metrics(input)
+		}
+		runObserved(b, op.ID, name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := op.Run(input); err != nil {
+					b.Fatal(err)
+				}
+			}
+			for unit, v := range metrics {
+				b.ReportMetric(v, unit)
+			}
+		})
+	}
+	globalMemStats.Groups[op.ID] = captureMemSnapshot()
+	globalHeap.writeProfile(op.ID)
+}
+
+// operationsManifest is the schema of operations.json, which tells
+// emit-report the benchmark name and tracks of every registered operation.
+type operationsManifest struct {
+	Operations []report.ManifestOperation `json:"operations"`
+}
+
+func registeredOperations() operationsManifest {
+	m := operationsManifest{Operations: make([]report.ManifestOperation, 0, len(operationRegistry))}
+	for _, op := range operationRegistry {
+		m.Operations = append(m.Operations, report.ManifestOperation{ID: op.ID, Benchmark: op.Benchmark, Tracks: op.Tracks, Needs: op.Needs})
+	}
+	sort.Slice(m.Operations, func(i, j int) bool { return m.Operations[i].ID < m.Operations[j].ID })
+	return m
+}
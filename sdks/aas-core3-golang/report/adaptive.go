@@ -0,0 +1,52 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// AdaptiveSampling is the schema of the adaptive.json file and the report's
+// adaptive_sampling section: how aasbench run --target-error kept collecting
+// runs of the operations whose relative error (see RelativeErrorPct) was
+// above the target until it was met or the time budget expired.
+type AdaptiveSampling struct {
+	TargetErrorPct float64 `json:"target_error_pct"`
+	BudgetS        float64 `json:"budget_s"`
+	ElapsedS       float64 `json:"elapsed_s"`
+	// Rounds counts the rounds of extra runs; 0 means the initial runs
+	// were precise enough.
+	Rounds int `json:"rounds"`
+	// Converged is whether every operation met the target.
+	Converged bool `json:"converged"`
+	// Operations lists every operation with the samples it ended with and
+	// the precision they achieved.
+	Operations []AdaptiveOperation `json:"operations"`
+}
+
+// AdaptiveOperation is one operation on one dataset under adaptive
+// sampling. ExtraRuns counts the runs collected beyond the initial ones.
+type AdaptiveOperation struct {
+	Dataset     string `json:"dataset"`
+	OperationID string `json:"operation_id"`
+	// Variant is the sub-benchmark path of a variant of the operation.
+	Variant          string  `json:"variant,omitempty"`
+	SampleCount      int     `json:"sample_count"`
+	ExtraRuns        int     `json:"extra_runs"`
+	RelativeErrorPct float64 `json:"relative_error_pct"`
+	Converged        bool    `json:"converged"`
+}
+
+// LoadAdaptiveSampling reads the adaptive.json file written by aasbench run
+// --target-error.
+func LoadAdaptiveSampling(path string) (*AdaptiveSampling, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var a AdaptiveSampling
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, fmt.Errorf("parse adaptive.json: %w", err)
+	}
+	return &a, nil
+}
@@ -0,0 +1,100 @@
+package report
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// defaultAliasesJSON maps the Go harness's benchmark names onto canonical
+// operation IDs where lower-casing alone does not.
+//
+//go:embed aliases.json
+var defaultAliasesJSON []byte
+
+// AliasTable maps lower-cased benchmark names (without the "Benchmark"
+// prefix) to operation IDs. Names without an entry are lower-cased.
+type AliasTable map[string]string
+
+// aliasFile is the schema of aliases.json and of override files.
+type aliasFile struct {
+	Aliases map[string]string `json:"aliases"`
+}
+
+// DefaultAliases returns a copy of the embedded alias table.
+func DefaultAliases() AliasTable {
+	t, err := parseAliases(defaultAliasesJSON, "embedded aliases.json")
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// LoadAliases returns the default table with the entries of the override
+// file at path added or replaced.
+func LoadAliases(path string) (AliasTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	override, err := parseAliases(data, path)
+	if err != nil {
+		return nil, err
+	}
+	t := DefaultAliases()
+	for name, id := range override {
+		t[name] = id
+	}
+	return t, nil
+}
+
+// parseAliases decodes and checks an alias file. Names are case-insensitive,
+// so two entries differing only in case are a collision, as is an alias
+// that renames one canonical operation into another.
+func parseAliases(data []byte, source string) (AliasTable, error) {
+	var f aliasFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", source, err)
+	}
+	names := make([]string, 0, len(f.Aliases))
+	for name := range f.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	t := make(AliasTable, len(f.Aliases))
+	var problems []string
+	for _, name := range names {
+		id := f.Aliases[name]
+		key := strings.ToLower(name)
+		if prev, dup := t[key]; dup {
+			problems = append(problems, fmt.Sprintf("alias %q collides with another entry differing only in case (-> %q)", name, prev))
+			continue
+		}
+		if err := CheckOperationID(id); err != nil {
+			problems = append(problems, fmt.Sprintf("alias %q: %v", name, err))
+			continue
+		}
+		if CanonicalOperations[key] && key != id {
+			problems = append(problems, fmt.Sprintf("alias %q would rename canonical operation %q to %q", name, key, id))
+			continue
+		}
+		t[key] = id
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s: %s", source, strings.Join(problems, "; "))
+	}
+	return t, nil
+}
+
+// Resolve returns the operation ID of a Go benchmark name.
+func (t AliasTable) Resolve(benchmark string) string {
+	key := strings.ToLower(benchmark)
+	if id, ok := t[key]; ok {
+		return id
+	}
+	return key
+}
@@ -0,0 +1,60 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeAliases(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadAliasesOverridesDefaults(t *testing.T) {
+	table, err := LoadAliases(writeAliases(t, `{"aliases": {"Parse": "deserialize", "FromXML": "deserialize_xml"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{
+		"Parse":          "deserialize",
+		"FromXml":        "deserialize_xml",
+		"SerializeXml":   "serialize_xml", // embedded default kept
+		"Validate":       "validate",
+		"AasxRepackage":  "aasx_repackage",
+		"acme:Transform": "acme:transform",
+	} {
+		if got := table.Resolve(name); got != want {
+			t.Errorf("Resolve(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestLoadAliasesRejectsCollisions(t *testing.T) {
+	for body, want := range map[string]string{
+		`{"aliases": {"Parse": "deserialize", "parse": "validate"}}`: "differing only in case",
+		`{"aliases": {"validate": "deserialize"}}`:                   "would rename canonical operation",
+		`{"aliases": {"Transform": "transform"}}`:                    "not canonical",
+	} {
+		_, err := LoadAliases(writeAliases(t, body))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("LoadAliases(%s) error = %v, want %q", body, err, want)
+		}
+	}
+}
+
+func TestParseBenchResultsRejectsAliasedDuplicates(t *testing.T) {
+	path := writeRaw(t,
+		`{"Action":"output","Test":"BenchmarkDeserialize/wide","Output":"BenchmarkDeserialize/wide-8 \t 100\t 1000 ns/op\n"}`,
+		`{"Action":"output","Test":"BenchmarkParse/wide","Output":"BenchmarkParse/wide-8 \t 100\t 900 ns/op\n"}`,
+	)
+	_, _, err := ParseBenchResults(path, ParseLenient, AliasTable{"parse": "deserialize"})
+	if err == nil || !strings.Contains(err.Error(), "both resolve to operation") {
+		t.Errorf("err = %v, want an alias collision", err)
+	}
+}
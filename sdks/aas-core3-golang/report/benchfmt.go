@@ -0,0 +1,103 @@
+package report
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BenchTextFile is the benchmark output in the standard Go format, for
+// benchstat.
+const BenchTextFile = "bench.txt"
+
+// harnessPackage is the import path of the benchmark harness, the pkg of
+// its benchmark output.
+const harnessPackage = "github.com/aas-benchmark-observatory/sdks/aas-core3-golang"
+
+// WriteBenchText writes results in the Go benchmark format that benchstat
+// reads: configuration lines from rep, then one line per -count run of
+// every benchmark under its original Go name. The per-run iteration count
+// is the run's share of the total, since only the sum is parsed; ns/op,
+// B/op and allocs/op are as measured.
+func WriteBenchText(w io.Writer, rep *Report, results map[string]*BenchResult) error {
+	bw := bufio.NewWriter(w)
+	config := [][2]string{}
+	if env := rep.Environment; env != nil {
+		config = append(config, [2]string{"goos", env.OS}, [2]string{"goarch", env.Arch})
+	}
+	config = append(config, [2]string{"pkg", harnessPackage})
+	if env := rep.Environment; env != nil {
+		config = append(config, [2]string{"cpu", env.CPU.Model})
+	}
+	// benchstat keeps unknown keys as configuration, so runs of different
+	// SDK versions are labelled in its tables.
+	config = append(config,
+		[2]string{"sdk", rep.SDKID},
+		[2]string{"sdk-version", rep.Metadata.SDKPackageVersion},
+		[2]string{"runtime", rep.Metadata.RuntimeVersion})
+	for _, kv := range config {
+		if kv[1] != "" {
+			fmt.Fprintf(bw, "%s: %s\n", kv[0], kv[1])
+		}
+	}
+
+	// Go appends -GOMAXPROCS to benchmark names unless it is 1.
+	suffix := ""
+	if procs := rep.Metadata.GOMAXPROCS; procs > 1 {
+		suffix = "-" + strconv.Itoa(procs)
+	}
+	sorted := make([]*BenchResult, 0, len(results))
+	for _, r := range results {
+		sorted = append(sorted, r)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Benchmark != sorted[j].Benchmark {
+			return sorted[i].Benchmark < sorted[j].Benchmark
+		}
+		if sorted[i].Dataset != sorted[j].Dataset {
+			return sorted[i].Dataset < sorted[j].Dataset
+		}
+		return sorted[i].Variant < sorted[j].Variant
+	})
+	for _, r := range sorted {
+		if len(r.Runs) == 0 {
+			continue
+		}
+		n := int(math.Max(1, math.Round(float64(r.N)/float64(len(r.Runs)))))
+		name := r.Benchmark + "/" + r.Dataset
+		if r.Variant != "" {
+			name += "/" + r.Variant
+		}
+		units := make([]string, 0, len(r.MetricRuns))
+		for unit := range r.MetricRuns {
+			units = append(units, unit)
+		}
+		sort.Strings(units)
+		for i, ns := range r.Runs {
+			// Custom metrics go between ns/op and B/op, as go test prints them.
+			var custom strings.Builder
+			for _, unit := range units {
+				if values := r.MetricRuns[unit]; i < len(values) {
+					fmt.Fprintf(&custom, "\t%s %s", strconv.FormatFloat(values[i], 'f', -1, 64), unit)
+				}
+			}
+			fmt.Fprintf(bw, "Benchmark%s%s\t%d\t%s ns/op%s\t%d B/op\t%d allocs/op\n",
+				name, suffix, n, strconv.FormatFloat(ns, 'f', -1, 64), custom.String(),
+				runValue(r.BytesRuns, i, r.BytesPerOp), runValue(r.AllocsRuns, i, r.AllocsPerOp))
+		}
+	}
+	return bw.Flush()
+}
+
+// runValue returns the i-th run's figure, or mean when the runs did not
+// all print one.
+func runValue(runs []int64, i int, mean int64) int64 {
+	if i < len(runs) {
+		return runs[i]
+	}
+	return mean
+}
@@ -0,0 +1,38 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteBenchTextLinePerRun(t *testing.T) {
+	rep := &Report{
+		SDKID:       DefaultSDKID,
+		Metadata:    Metadata{RuntimeVersion: "go1.22.5", SDKPackageVersion: "v1.0.7", GOMAXPROCS: 8},
+		Environment: &Environment{OS: "linux", Arch: "amd64", CPU: CPUInfo{Model: "Test CPU"}},
+	}
+	results := map[string]*BenchResult{
+		"mixed/deserialize": {Benchmark: "Deserialize", Dataset: "mixed", N: 300, BytesPerOp: 4096, AllocsPerOp: 12, Runs: []float64{1000, 1100.5}},
+		"deep/deserialize":  {Benchmark: "Deserialize", Dataset: "deep", N: 10, Runs: []float64{5}},
+	}
+	var buf bytes.Buffer
+	if err := WriteBenchText(&buf, rep, results); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"goos: linux",
+		"goarch: amd64",
+		"pkg: " + harnessPackage,
+		"cpu: Test CPU",
+		"sdk: aas-core3-golang",
+		"sdk-version: v1.0.7",
+		"runtime: go1.22.5",
+		"BenchmarkDeserialize/deep-8\t10\t5 ns/op\t0 B/op\t0 allocs/op",
+		"BenchmarkDeserialize/mixed-8\t150\t1000 ns/op\t4096 B/op\t12 allocs/op",
+		"BenchmarkDeserialize/mixed-8\t150\t1100.5 ns/op\t4096 B/op\t12 allocs/op",
+	}, "\n") + "\n"
+	if buf.String() != want {
+		t.Errorf("bench.txt =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
@@ -0,0 +1,438 @@
+package report
+
+import (
+	"math"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// Options carries the optional inputs that enrich a report beyond the raw
+// benchmark lines.
+type Options struct {
+	// MemStats is the parsed memory_stats.json side channel, if any.
+	MemStats *MemStats
+	// GCPauses is the parsed gc_pauses.json side channel, if any.
+	GCPauses *GCPauses
+	// Events is the parsed events.json timeline, if any.
+	Events *Events
+	// HeapHotspots is the parsed heap_hotspots.json side channel, if any.
+	HeapHotspots *HeapHotspots
+	// HardwareCounters is the parsed hardware_counters.json side channel,
+	// if any.
+	HardwareCounters *HardwareCounters
+	// Energy is the parsed energy.json side channel, if any.
+	Energy *Energy
+	// DatasetsManifest fingerprints the dataset files the run consumed.
+	DatasetsManifest []DatasetFingerprint
+	// Sweep holds the results of re-running at other benchtimes, if any.
+	Sweep []SweepRun
+	// StabilityThresholdPct overrides DefaultStabilityThresholdPct when > 0.
+	StabilityThresholdPct float64
+	// ParseDiagnostics describes how the benchmark output parsed, if known.
+	ParseDiagnostics *ParseDiagnostics
+	// SDKVersion is the requested SDK version, recorded when BuildInfo
+	// does not say which version was linked.
+	SDKVersion string
+	// BuildInfo is the parsed build_info.json side channel, if any.
+	BuildInfo *BuildInfo
+	// Scheduling is the parsed scheduling.json side channel, if any.
+	Scheduling *Scheduling
+	// Control is the parsed control.json side channel, if any.
+	Control *Control
+	// NoiseThresholdPct overrides DefaultNoiseThresholdPct when > 0.
+	NoiseThresholdPct float64
+	// OutlierPolicy overrides DefaultOutlierPolicy when set.
+	OutlierPolicy string
+	// Environment is the parsed environment.json side channel, if any.
+	Environment *Environment
+	// Skipped is the parsed skipped.json side channel, if any.
+	Skipped *Skipped
+	// Robustness is the parsed robustness.json side channel, if any.
+	Robustness *Robustness
+	// Containment is the parsed containment.json side channel, if any.
+	Containment *Containment
+	// Adaptive is the parsed adaptive.json side channel, if any.
+	Adaptive *AdaptiveSampling
+	// BuildMetrics is the parsed build_metrics.json side channel, if any.
+	BuildMetrics *BuildMetrics
+	// Flamegraphs is the parsed flamegraphs.json side channel, if any.
+	Flamegraphs Flamegraphs
+	// ExecTraces is the parsed exec_traces.json side channel, if any.
+	ExecTraces ExecTraces
+	// Soak is the parsed soak.json side channel, if any.
+	Soak *Soak
+	// OutputSizes is the parsed output_sizes.json side channel, if any.
+	OutputSizes OutputSizes
+	// Partial is the progress a crashed harness run flushed, if any.
+	Partial *Partial
+	// ExpectedOperations are the operations the run was to measure; those
+	// without any result get incomplete placeholders.
+	ExpectedOperations []string
+	// SDKID overrides DefaultSDKID, for wrapper repositories benchmarking
+	// another Go AAS library with this harness. Left empty or at the
+	// default, BuildInfo's SDK adapter names the sdk_id.
+	SDKID string
+	// Operations is the run's operations manifest; nil assigns tracks by
+	// the embedded one.
+	Operations OperationManifest
+}
+
+// DefaultSDKID is the sdk_id of reports built from this harness.
+const DefaultSDKID = "aas-core3-golang"
+
+// Build converts parsed benchmark results into a report.
+func Build(results map[string]*BenchResult, opts Options) *Report {
+	// Organize by dataset
+	stabilityThreshold := opts.StabilityThresholdPct
+	if stabilityThreshold <= 0 {
+		stabilityThreshold = DefaultStabilityThresholdPct
+	}
+
+	outlierPolicy := opts.OutlierPolicy
+	if outlierPolicy == "" {
+		outlierPolicy = DefaultOutlierPolicy
+	}
+
+	operation := func(r *BenchResult) OperationEntry {
+		op := buildOperation(r, opts.MemStats, outlierPolicy)
+		if opts.GCPauses != nil {
+			op.Memory.GcPauses = GCPauseStatsOf(opts.GCPauses.Groups[r.Operation])
+		}
+		if opts.Events != nil {
+			op.EnvironmentEvents = opts.Events.OverlappingEvents(r.Dataset, r.Operation)
+		}
+		if len(opts.Sweep) > 0 {
+			op.Stability = ComputeStability(sweepPoints(opts.Sweep, r.Key()), stabilityThreshold)
+		}
+		if size, ok := opts.OutputSizes[r.Operation][r.Dataset]; ok {
+			op.OutputSize = &size
+		}
+		return op
+	}
+
+	calibration := calibrationEntries(results)
+	datasets := make(map[string]DatasetEntry)
+	var variants []*BenchResult
+	for _, r := range results {
+		if r.Operation == CalibrationOperation {
+			continue
+		}
+		if r.Variant != "" {
+			variants = append(variants, r)
+			continue
+		}
+		if _, exists := datasets[r.Dataset]; !exists {
+			datasets[r.Dataset] = DatasetEntry{
+				Operations: make(map[string]OperationEntry),
+			}
+		}
+		ds := datasets[r.Dataset]
+		ds.Operations[r.Operation] = operation(r)
+		datasets[r.Dataset] = ds
+	}
+	addVariants(datasets, variants, operation)
+	if opts.Skipped != nil {
+		addSkipped(datasets, opts.Skipped)
+	}
+	if opts.Partial != nil || len(opts.ExpectedOperations) > 0 {
+		addIncomplete(datasets, opts.Partial, opts.ExpectedOperations)
+	}
+	if opts.Operations != nil {
+		assignTracks(datasets, opts.Operations)
+	}
+
+	sdkID := opts.SDKID
+	if (sdkID == "" || sdkID == DefaultSDKID) && opts.BuildInfo != nil && opts.BuildInfo.SDKID != "" {
+		// The harness was built with another SDK adapter.
+		sdkID = opts.BuildInfo.SDKID
+	}
+	if sdkID == "" {
+		sdkID = DefaultSDKID
+	}
+	rep := &Report{
+		SchemaVersion:    SchemaVersion,
+		SDKID:            sdkID,
+		Metadata:         buildMetadata(opts),
+		Datasets:         datasets,
+		Headline:         DefaultCatalog().SelectHeadline(datasets),
+		ParseDiagnostics: opts.ParseDiagnostics,
+		Environment:      opts.Environment,
+		Calibration:      calibration,
+	}
+	if outlierPolicy != OutlierNone {
+		rep.OutlierPolicy = outlierPolicy
+	}
+	noiseThreshold := opts.NoiseThresholdPct
+	if noiseThreshold <= 0 {
+		noiseThreshold = DefaultNoiseThresholdPct
+	}
+	rep.ControlBenchmark, rep.EnvironmentNoise = ComputeNoise(opts.Control, noiseThreshold)
+	if len(opts.DatasetsManifest) > 0 {
+		rep.Metadata.DatasetsManifest = opts.DatasetsManifest
+		fillDatasetSizes(datasets, opts.DatasetsManifest)
+	}
+	if opts.Robustness != nil && len(opts.Robustness.Entries) > 0 {
+		rep.Robustness = SummarizeRobustness(opts.Robustness)
+	}
+	if opts.Containment != nil && len(opts.Containment.Groups) > 0 {
+		rep.Containment = opts.Containment
+	}
+	rep.AdaptiveSampling = opts.Adaptive
+	rep.BuildMetrics = opts.BuildMetrics
+	rep.Flamegraphs = opts.Flamegraphs
+	rep.ExecTraces = opts.ExecTraces
+	rep.Soak = opts.Soak
+	rep.Scaling = ComputeScaling(datasets, DefaultSuperLinearExponent)
+	if opts.HeapHotspots != nil && len(opts.HeapHotspots.Groups) > 0 {
+		rep.AllocationHotspots = opts.HeapHotspots.Groups
+	}
+	if opts.HardwareCounters != nil && len(opts.HardwareCounters.Groups) > 0 {
+		rep.HardwareCounters = hardwareCounterEntries(opts.HardwareCounters.Groups)
+	}
+	if opts.Energy != nil && len(opts.Energy.Groups) > 0 {
+		rep.Energy = energyEntries(opts.Energy.Groups)
+	}
+	return rep
+}
+
+// hardwareCounterEntries adds per-iteration rates to the counter totals.
+func hardwareCounterEntries(groups map[string]HardwareCounterGroup) map[string]HardwareCounterEntry {
+	perOp := func(total, iterations int64) float64 {
+		if iterations <= 0 {
+			return 0
+		}
+		return math.Round(float64(total)/float64(iterations)*100) / 100
+	}
+	entries := make(map[string]HardwareCounterEntry, len(groups))
+	for op, g := range groups {
+		entries[op] = HardwareCounterEntry{
+			HardwareCounterGroup: g,
+			InstructionsPerOp:    perOp(g.Instructions, g.Iterations),
+			CacheMissesPerOp:     perOp(g.CacheMisses, g.Iterations),
+			BranchMissesPerOp:    perOp(g.BranchMisses, g.Iterations),
+		}
+	}
+	return entries
+}
+
+// energyEntries adds average power and energy per iteration to the totals.
+func energyEntries(groups map[string]EnergyGroup) map[string]EnergyEntry {
+	entries := make(map[string]EnergyEntry, len(groups))
+	for op, g := range groups {
+		e := EnergyEntry{EnergyGroup: g}
+		if g.Seconds > 0 {
+			e.Watts = math.Round(g.TotalJoules/g.Seconds*100) / 100
+		}
+		if g.Iterations > 0 {
+			e.MicrojoulesPerOp = math.Round(g.TotalJoules*1e6/float64(g.Iterations)*100) / 100
+		}
+		entries[op] = e
+	}
+	return entries
+}
+
+// addVariants adds every sub-benchmark nested below its dataset to the
+// Variants of its operation, in the order they ran. An operation only run
+// as variants has no result of its own, since go test prints none for a
+// benchmark with sub-benchmarks; its first variant stands in for it, with
+// its Params telling which it is.
+func addVariants(datasets map[string]DatasetEntry, variants []*BenchResult, build func(*BenchResult) OperationEntry) {
+	sort.Slice(variants, func(i, j int) bool { return variants[i].seq < variants[j].seq })
+	for _, r := range variants {
+		ds, ok := datasets[r.Dataset]
+		if !ok {
+			ds = DatasetEntry{Operations: make(map[string]OperationEntry)}
+			datasets[r.Dataset] = ds
+		}
+		variant := build(r)
+		variant.Variant, variant.Params = r.Variant, r.Params
+		if op, ok := ds.Operations[r.Operation]; ok {
+			op.Variants = append(op.Variants, variant)
+			ds.Operations[r.Operation] = op
+		} else {
+			ds.Operations[r.Operation] = variant
+		}
+	}
+}
+
+// assignTracks re-assigns every operation's track by the run's manifest.
+func assignTracks(datasets map[string]DatasetEntry, m OperationManifest) {
+	for dsName, ds := range datasets {
+		for id, op := range ds.Operations {
+			op.OperationTrack = m.Track(dsName, id)
+			for i := range op.Variants {
+				op.Variants[i].OperationTrack = op.OperationTrack
+			}
+			ds.Operations[id] = op
+		}
+	}
+}
+
+// addSkipped adds a placeholder for every skipped operation that has no
+// results, so consumers can tell "not run" from "not supported".
+func addSkipped(datasets map[string]DatasetEntry, skipped *Skipped) {
+	for _, e := range skipped.Entries {
+		ds, ok := datasets[e.Dataset]
+		if !ok {
+			ds = DatasetEntry{Operations: make(map[string]OperationEntry)}
+			datasets[e.Dataset] = ds
+		}
+		if _, ran := ds.Operations[e.Operation]; ran {
+			continue
+		}
+		ds.Operations[e.Operation] = OperationEntry{
+			OperationID:          e.Operation,
+			OperationTrack:       InferOperationTrack(e.Dataset, e.Operation),
+			MeasurementSemantics: "mean_ns_per_operation",
+			FailureState:         FailureSkippedMissingDataset,
+			SkipReason:           e.Reason,
+		}
+	}
+}
+
+// buildMetadata records the toolchain and SDK version the benchmarks were
+// linked against, which build_info.json knows exactly. Without it the
+// emitter's own Go version and the requested SDK version are the best guess.
+func buildMetadata(opts Options) Metadata {
+	meta := Metadata{
+		Language:          "go",
+		RuntimeVersion:    runtime.Version(),
+		SDKPackageVersion: opts.SDKVersion,
+		BenchmarkHarness:  "testing.B (go test -bench)",
+		Timestamp:         time.Now().UTC().Format(time.RFC3339),
+		Platform:          HostPlatform(),
+	}
+	if env := opts.Environment; env != nil && env.OS != "" && env.Arch != "" {
+		meta.Platform = env.OS + "/" + env.Arch
+	}
+	if bi := opts.BuildInfo; bi != nil {
+		if bi.GoVersion != "" {
+			meta.RuntimeVersion = bi.GoVersion
+		}
+		if bi.SDKVersion != "" {
+			meta.SDKPackageVersion = bi.SDKVersion
+		}
+		meta.SDKModule = bi.SDKModule
+		meta.ObservatoryGitSHA = bi.GitCommit
+		meta.ObservatoryGitDirty = bi.GitDirty
+		// The benchmark binary's own platform, js/wasm under node.
+		if bi.Platform != "" {
+			meta.Platform = bi.Platform
+		}
+	}
+	if s := opts.Scheduling; s != nil {
+		if s.GOMAXPROCS > 0 {
+			meta.GOMAXPROCS = s.GOMAXPROCS
+		}
+		// The affinity is only worth recording when it was chosen.
+		if s.Pinned {
+			meta.CPUAffinity = s.CPUAffinity
+		}
+	}
+	if meta.SDKPackageVersion == "" {
+		meta.SDKPackageVersion = "unknown"
+	}
+	return meta
+}
+
+// fillDatasetSizes sets each dataset's size and element count from its JSON
+// fingerprint, the serialization every core operation starts from.
+func fillDatasetSizes(datasets map[string]DatasetEntry, manifest []DatasetFingerprint) {
+	for _, fp := range manifest {
+		ds, ok := datasets[fp.Dataset]
+		if !ok || fp.Format != "json" {
+			continue
+		}
+		size := fp.SizeBytes
+		ds.FileSizeBytes = &size
+		ds.ElementCount = fp.ElementCount
+		datasets[fp.Dataset] = ds
+	}
+}
+
+// buildOperation summarizes r, computing its statistics over the runs
+// outlierPolicy keeps.
+func buildOperation(r *BenchResult, memStats *MemStats, outlierPolicy string) OperationEntry {
+	runs, removed := FilterOutliers(r.Runs, outlierPolicy)
+	meanNs, medianNs, stddevNs, minNs, maxNs := ComputeStats(runs)
+
+	throughput := 0.0
+	if meanNs > 0 {
+		throughput = 1e9 / meanNs
+	}
+
+	bytesPerOp := r.BytesPerOp
+	allocsPerOp := r.AllocsPerOp
+
+	mem := MemoryEntry{
+		AllocBytesPerOp: &bytesPerOp,
+		AllocCountPerOp: &allocsPerOp,
+	}
+	mem.AllocBytesPerOpMin, mem.AllocBytesPerOpMax = minMaxInt64(r.BytesRuns)
+	mem.AllocCountPerOpMin, mem.AllocCountPerOpMax = minMaxInt64(r.AllocsRuns)
+
+	// Populate heap/GC data from side-channel memory stats if available
+	if memStats != nil {
+		// Look up the group snapshot for this operation
+		if groupSnap, ok := memStats.Groups[r.Operation]; ok {
+			heapUsed := int64(groupSnap.HeapAllocBytes)
+			mem.HeapUsedBytes = &heapUsed
+
+			// GC pause: convert nanoseconds to milliseconds
+			gcPauseMs := float64(groupSnap.PauseTotalNs) / 1e6
+			mem.GcPauseMs = &gcPauseMs
+
+			gcCount := int64(groupSnap.NumGC)
+			mem.GcCount = &gcCount
+
+			// TracedPeakBytes: use HeapSys as a proxy for peak traced memory
+			tracedPeak := int64(groupSnap.HeapSysBytes)
+			mem.TracedPeakBytes = &tracedPeak
+		}
+
+		if peak, ok := memStats.Peaks[PeakKey(r.Operation, r.Dataset)]; ok {
+			mem.PeakIntermediateBytes = &peak
+		}
+
+		// Also use the overall "after" snapshot for heap data if no group match
+		if mem.HeapUsedBytes == nil {
+			heapUsed := int64(memStats.After.HeapAllocBytes)
+			mem.HeapUsedBytes = &heapUsed
+		}
+	}
+
+	op := OperationEntry{
+		OperationID:          r.Operation,
+		OperationTrack:       InferOperationTrack(r.Dataset, r.Operation),
+		SampleCount:          len(runs),
+		MeasurementSemantics: "mean_ns_per_operation",
+		FailureState:         FailureOK,
+		Iterations:           r.N,
+		MeanNs:               int64(math.Round(meanNs)),
+		MedianNs:             int64(math.Round(medianNs)),
+		StddevNs:             int64(math.Round(stddevNs)),
+		MinNs:                int64(math.Round(minNs)),
+		MaxNs:                int64(math.Round(maxNs)),
+		ThroughputOpsPerSec:  math.Round(throughput*100) / 100,
+		Memory:               mem,
+	}
+	if outlierPolicy != OutlierNone {
+		op.OutliersRemoved = &removed
+	}
+	if len(runs) >= 2 {
+		relErr := math.Round(RelativeErrorPct(runs)*100) / 100
+		op.RelativeErrorPct = &relErr
+	}
+	for _, ns := range runs {
+		op.SamplesNs = append(op.SamplesNs, int64(math.Round(ns)))
+	}
+	if len(r.MetricRuns) > 0 {
+		op.CustomMetrics = make(map[string]float64, len(r.MetricRuns))
+		for unit, values := range r.MetricRuns {
+			mean, _, _, _, _ := ComputeStats(values)
+			op.CustomMetrics[unit] = mean
+		}
+	}
+	return op
+}
@@ -0,0 +1,148 @@
+package report
+
+import "testing"
+
+func TestBuildMetadataPrefersBuildInfo(t *testing.T) {
+	dirty := true
+	meta := Build(nil, Options{
+		SDKVersion: "v1.0.6",
+		BuildInfo: &BuildInfo{
+			GoVersion:  "go1.22.5",
+			SDKModule:  "github.com/aas-core-works/aas-core3.0-golang",
+			SDKVersion: "v1.0.7",
+			GitCommit:  "0123abc",
+			GitDirty:   &dirty,
+		},
+	}).Metadata
+	if meta.RuntimeVersion != "go1.22.5" || meta.SDKPackageVersion != "v1.0.7" || meta.ObservatoryGitSHA != "0123abc" {
+		t.Errorf("metadata = %+v, want build info values", meta)
+	}
+	if meta.ObservatoryGitDirty == nil || !*meta.ObservatoryGitDirty {
+		t.Errorf("observatory_git_dirty = %v, want true", meta.ObservatoryGitDirty)
+	}
+
+	if got := Build(nil, Options{}).Metadata.SDKPackageVersion; got != "unknown" {
+		t.Errorf("sdk_package_version without build info = %q, want unknown", got)
+	}
+}
+
+func TestBuildTakesSDKIDFromBuildInfo(t *testing.T) {
+	bi := &BuildInfo{SDKID: "example-jsonmap-golang"}
+	for _, tc := range []struct {
+		opts Options
+		want string
+	}{
+		{Options{BuildInfo: bi}, "example-jsonmap-golang"},
+		{Options{BuildInfo: bi, SDKID: DefaultSDKID}, "example-jsonmap-golang"},
+		{Options{BuildInfo: bi, SDKID: "aas-core3-golang-wasm"}, "aas-core3-golang-wasm"},
+		{Options{}, DefaultSDKID},
+	} {
+		if got := Build(nil, tc.opts).SDKID; got != tc.want {
+			t.Errorf("sdk_id with --sdk-id %q = %q, want %q", tc.opts.SDKID, got, tc.want)
+		}
+	}
+}
+
+func TestBuildMetadataRecordsScheduling(t *testing.T) {
+	meta := Build(nil, Options{Scheduling: &Scheduling{GOMAXPROCS: 2, CPUAffinity: "0-1", Pinned: true}}).Metadata
+	if meta.GOMAXPROCS != 2 || meta.CPUAffinity != "0-1" {
+		t.Errorf("gomaxprocs = %d cpu_affinity = %q, want 2 and 0-1", meta.GOMAXPROCS, meta.CPUAffinity)
+	}
+	// An unpinned run's affinity is just the host's CPUs.
+	meta = Build(nil, Options{Scheduling: &Scheduling{GOMAXPROCS: 8, CPUAffinity: "0-7"}}).Metadata
+	if meta.GOMAXPROCS != 8 || meta.CPUAffinity != "" {
+		t.Errorf("gomaxprocs = %d cpu_affinity = %q, want 8 and none", meta.GOMAXPROCS, meta.CPUAffinity)
+	}
+}
+
+func TestBuildAddsSkippedPlaceholders(t *testing.T) {
+	results := map[string]*BenchResult{
+		"deep/validate": {Dataset: "deep", Operation: "validate", N: 10, NsPerOp: 105, Runs: []float64{100, 110}},
+	}
+	rep := Build(results, Options{Skipped: &Skipped{Entries: []SkippedEntry{
+		{Operation: "validate", Dataset: "wide", Reason: "no wide.json in the datasets directory"},
+		{Operation: "validate", Dataset: "deep", Reason: "stale"},
+	}}})
+
+	wide := rep.Datasets["wide"].Operations["validate"]
+	if wide.FailureState != FailureSkippedMissingDataset || wide.SkipReason == "" || wide.Measured() {
+		t.Errorf("wide/validate = %+v, want a skipped placeholder", wide)
+	}
+	deep := rep.Datasets["deep"].Operations["validate"]
+	if deep.FailureState != FailureOK || deep.SkipReason != "" || !deep.Measured() {
+		t.Errorf("deep/validate = %+v, want the measured result kept", deep)
+	}
+}
+
+func TestBuildHardwareCountersPerOp(t *testing.T) {
+	rep := Build(nil, Options{HardwareCounters: &HardwareCounters{Groups: map[string]HardwareCounterGroup{
+		"validate": {Iterations: 300, Instructions: 1_000_000, CacheMisses: 1000, BranchMisses: 50},
+		"traverse": {},
+	}}})
+	got := rep.HardwareCounters["validate"]
+	if got.Instructions != 1_000_000 || got.InstructionsPerOp != 3333.33 || got.CacheMissesPerOp != 3.33 || got.BranchMissesPerOp != 0.17 {
+		t.Errorf("validate = %+v", got)
+	}
+	if got := rep.HardwareCounters["traverse"]; got.InstructionsPerOp != 0 {
+		t.Errorf("traverse without iterations = %+v, want zero rates", got)
+	}
+	if Build(nil, Options{}).HardwareCounters != nil {
+		t.Error("hardware_counters present without the side channel")
+	}
+}
+
+func TestBuildEnergyPowerAndPerOp(t *testing.T) {
+	rep := Build(nil, Options{Energy: &Energy{Groups: map[string]EnergyGroup{
+		"serialize": {Iterations: 4000, Seconds: 2, TotalJoules: 30, Joules: map[string]float64{"package-0": 30}},
+	}}})
+	got := rep.Energy["serialize"]
+	if got.Watts != 15 || got.MicrojoulesPerOp != 7500 {
+		t.Errorf("serialize = %+v, want 15 W and 7500 µJ/op", got)
+	}
+	if Build(nil, Options{}).Energy != nil {
+		t.Error("energy present without the side channel")
+	}
+}
+
+func TestBuildPeakIntermediateBytesPerDataset(t *testing.T) {
+	results := map[string]*BenchResult{
+		"wide/serialize":   {Dataset: "wide", Operation: "serialize", N: 10, NsPerOp: 100, Runs: []float64{100}},
+		"deep/serialize":   {Dataset: "deep", Operation: "serialize", N: 10, NsPerOp: 100, Runs: []float64{100}},
+		"wide/deserialize": {Dataset: "wide", Operation: "deserialize", N: 10, NsPerOp: 100, Runs: []float64{100}},
+	}
+	rep := Build(results, Options{MemStats: &MemStats{Peaks: map[string]int64{
+		PeakKey("serialize", "wide"): 4096,
+		PeakKey("serialize", "deep"): 512,
+	}}})
+	for _, tc := range []struct {
+		dataset, operation string
+		want               int64
+	}{{"wide", "serialize", 4096}, {"deep", "serialize", 512}} {
+		got := rep.Datasets[tc.dataset].Operations[tc.operation].Memory.PeakIntermediateBytes
+		if got == nil || *got != tc.want {
+			t.Errorf("%s/%s peak_intermediate_bytes = %v, want %d", tc.dataset, tc.operation, got, tc.want)
+		}
+	}
+	if got := rep.Datasets["wide"].Operations["deserialize"].Memory.PeakIntermediateBytes; got != nil {
+		t.Errorf("deserialize peak_intermediate_bytes = %d, want none", *got)
+	}
+}
+
+func TestBuildOutputSizePerDataset(t *testing.T) {
+	results := map[string]*BenchResult{
+		"wide/serialize":   {Dataset: "wide", Operation: "serialize", N: 10, NsPerOp: 100, Runs: []float64{100}},
+		"deep/serialize":   {Dataset: "deep", Operation: "serialize", N: 10, NsPerOp: 100, Runs: []float64{100}},
+		"wide/deserialize": {Dataset: "wide", Operation: "deserialize", N: 10, NsPerOp: 100, Runs: []float64{100}},
+	}
+	wide := OutputSize{Bytes: 9000, GzipBytes: 700, ZstdBytes: 600}
+	rep := Build(results, Options{OutputSizes: OutputSizes{"serialize": {"wide": wide}}})
+	if got := rep.Datasets["wide"].Operations["serialize"].OutputSize; got == nil || *got != wide {
+		t.Errorf("wide/serialize output_size = %v, want %v", got, wide)
+	}
+	for _, key := range []string{"deep/serialize", "wide/deserialize"} {
+		r := results[key]
+		if got := rep.Datasets[r.Dataset].Operations[r.Operation].OutputSize; got != nil {
+			t.Errorf("%s output_size = %v, want none", key, *got)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// BuildMetrics is the schema of the build_metrics.json file and the
+// report's build_metrics section: what it costs to compile a minimal
+// program using the SDK, and how large that program is. The baseline
+// program does the same JSON round trip without the SDK, so the SDK's own
+// share is the difference. Compile times are from a fresh build cache; the
+// SDK program is built after the baseline, with the standard library
+// already compiled.
+type BuildMetrics struct {
+	// Target is the GOOS/GOARCH the programs were built for.
+	Target    string `json:"target"`
+	GoVersion string `json:"go_version"`
+	SDKModule string `json:"sdk_module"`
+	// CompileS is the time to compile the SDK program's packages and link
+	// it; BaselineCompileS the time to build the baseline, including the
+	// standard library.
+	CompileS         float64 `json:"compile_s"`
+	BaselineCompileS float64 `json:"baseline_compile_s"`
+	BinarySizeBytes  int64   `json:"binary_size_bytes"`
+	// StrippedSizeBytes is the size when linked with -s -w, without the
+	// symbol table and DWARF data.
+	StrippedSizeBytes         int64 `json:"stripped_size_bytes"`
+	BaselineBinarySizeBytes   int64 `json:"baseline_binary_size_bytes"`
+	BaselineStrippedSizeBytes int64 `json:"baseline_stripped_size_bytes"`
+	// SDKStrippedSizeBytes is what the SDK adds to a stripped binary.
+	SDKStrippedSizeBytes int64 `json:"sdk_stripped_size_bytes"`
+}
+
+// LoadBuildMetrics reads the build_metrics.json file written by aasbench
+// run.
+func LoadBuildMetrics(path string) (*BuildMetrics, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m BuildMetrics
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse build_metrics.json: %w", err)
+	}
+	return &m, nil
+}
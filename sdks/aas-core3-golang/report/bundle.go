@@ -0,0 +1,380 @@
+package report
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Conventional file names of a harness output directory.
+const (
+	BenchRawFile         = "bench_raw.json"
+	MemoryStatsFile      = "memory_stats.json"
+	GCPausesFile         = "gc_pauses.json"
+	EventsFile           = "events.json"
+	HeapHotspotsFile     = "heap_hotspots.json"
+	HardwareCountersFile = "hardware_counters.json"
+	EnergyFile           = "energy.json"
+	EnvironmentFile      = "environment.json"
+	BuildInfoFile        = "build_info.json"
+	SchedulingFile       = "scheduling.json"
+	ControlFile          = "control.json"
+	SkippedFile          = "skipped.json"
+	RobustnessFile       = "robustness.json"
+	ContainmentFile      = "containment.json"
+	AdaptiveFile         = "adaptive.json"
+	BuildMetricsFile     = "build_metrics.json"
+	FlamegraphsFile      = "flamegraphs.json"
+	ExecTracesFile       = "exec_traces.json"
+	SoakFile             = "soak.json"
+	OutputSizesFile      = "output_sizes.json"
+	StagesFile           = "stages.json"
+	ReportFile           = "report.json"
+
+	sweepPrefix = "bench_sweep_"
+)
+
+// SweepFile is the raw output of one benchtime sweep run.
+type SweepFile struct {
+	Benchtime string
+	Path      string
+}
+
+// Bundle names the raw outputs of one harness run: the go test -json
+// output plus optional side channels. Empty paths are skipped.
+type Bundle struct {
+	BenchRaw         string
+	MemoryStats      string
+	GCPauses         string
+	Events           string
+	HeapHotspots     string
+	HardwareCounters string
+	Energy           string
+	Environment      string
+	BuildInfo        string
+	Scheduling       string
+	Control          string
+	Skipped          string
+	Robustness       string
+	Containment      string
+	Adaptive         string
+	BuildMetrics     string
+	Flamegraphs      string
+	ExecTraces       string
+	Soak             string
+	OutputSizes      string
+	// Operations is the run's operations manifest.
+	Operations string
+	// Partial is the directory of per-operation progress files.
+	Partial string
+	// Aliases is an alias override file; empty uses DefaultAliases.
+	Aliases string
+	Sweeps  []SweepFile
+	// Mode is how corrupt benchmark output is treated; empty is lenient.
+	Mode ParseMode
+}
+
+// SweepFileName is the file a benchtime sweep run writes its output to.
+func SweepFileName(benchtime string) string {
+	return sweepPrefix + benchtime + ".json"
+}
+
+// BundleInDir returns the bundle a harness run left in dir, using the
+// conventional file names and including only files that exist.
+func BundleInDir(dir string) Bundle {
+	b := Bundle{
+		BenchRaw:         filepath.Join(dir, BenchRawFile),
+		MemoryStats:      existing(filepath.Join(dir, MemoryStatsFile)),
+		GCPauses:         existing(filepath.Join(dir, GCPausesFile)),
+		Events:           existing(filepath.Join(dir, EventsFile)),
+		HeapHotspots:     existing(filepath.Join(dir, HeapHotspotsFile)),
+		HardwareCounters: existing(filepath.Join(dir, HardwareCountersFile)),
+		Energy:           existing(filepath.Join(dir, EnergyFile)),
+		Environment:      existing(filepath.Join(dir, EnvironmentFile)),
+		BuildInfo:        existing(filepath.Join(dir, BuildInfoFile)),
+		Scheduling:       existing(filepath.Join(dir, SchedulingFile)),
+		Control:          existing(filepath.Join(dir, ControlFile)),
+		Skipped:          existing(filepath.Join(dir, SkippedFile)),
+		Robustness:       existing(filepath.Join(dir, RobustnessFile)),
+		Containment:      existing(filepath.Join(dir, ContainmentFile)),
+		Adaptive:         existing(filepath.Join(dir, AdaptiveFile)),
+		BuildMetrics:     existing(filepath.Join(dir, BuildMetricsFile)),
+		Flamegraphs:      existing(filepath.Join(dir, FlamegraphsFile)),
+		ExecTraces:       existing(filepath.Join(dir, ExecTracesFile)),
+		Soak:             existing(filepath.Join(dir, SoakFile)),
+		OutputSizes:      existing(filepath.Join(dir, OutputSizesFile)),
+		Operations:       existing(filepath.Join(dir, OperationsFile)),
+		Partial:          existing(filepath.Join(dir, PartialDir)),
+	}
+	sweeps, _ := filepath.Glob(filepath.Join(dir, sweepPrefix+"*.json"))
+	sort.Strings(sweeps)
+	for _, path := range sweeps {
+		benchtime := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(path), sweepPrefix), ".json")
+		b.Sweeps = append(b.Sweeps, SweepFile{Benchtime: benchtime, Path: path})
+	}
+	return b
+}
+
+func existing(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// Load parses the bundle into benchmark results and the Options that carry
+// its side channels and parse diagnostics. Unreadable side channels are
+// skipped with a warning to log; an invalid alias file, and benchmark
+// or sweep output without results or with any defect in strict mode, is an
+// error.
+func (b Bundle) Load(log *slog.Logger) (map[string]*BenchResult, Options, error) {
+	var opts Options
+	if b.MemoryStats != "" {
+		ms, err := LoadMemoryStats(b.MemoryStats)
+		if err != nil {
+			log.Warn("could not load memory stats", "path", b.MemoryStats, "err", err)
+		} else {
+			opts.MemStats = ms
+			log.Info("loaded memory stats", "path", b.MemoryStats)
+		}
+	}
+	if b.GCPauses != "" {
+		gp, err := LoadGCPauses(b.GCPauses)
+		if err != nil {
+			log.Warn("could not load GC pauses", "path", b.GCPauses, "err", err)
+		} else {
+			opts.GCPauses = gp
+			log.Info("loaded GC pauses", "groups", len(gp.Groups), "path", b.GCPauses)
+		}
+	}
+	if b.Events != "" {
+		ev, err := LoadEvents(b.Events)
+		if err != nil {
+			log.Warn("could not load events", "path", b.Events, "err", err)
+		} else {
+			opts.Events = ev
+			log.Info("loaded system events", "count", len(ev.Events), "path", b.Events)
+		}
+	}
+	if b.HeapHotspots != "" {
+		hs, err := LoadHeapHotspots(b.HeapHotspots)
+		if err != nil {
+			log.Warn("could not load heap hotspots", "path", b.HeapHotspots, "err", err)
+		} else {
+			opts.HeapHotspots = hs
+			log.Info("loaded heap hotspots", "groups", len(hs.Groups), "path", b.HeapHotspots)
+		}
+	}
+	if b.HardwareCounters != "" {
+		hc, err := LoadHardwareCounters(b.HardwareCounters)
+		switch {
+		case err != nil:
+			log.Warn("could not load hardware counters", "path", b.HardwareCounters, "err", err)
+		case hc.Unavailable != "":
+			log.Warn("hardware counters were unavailable during the run", "reason", hc.Unavailable)
+		default:
+			opts.HardwareCounters = hc
+			log.Info("loaded hardware counters", "groups", len(hc.Groups), "path", b.HardwareCounters)
+		}
+	}
+	if b.Energy != "" {
+		e, err := LoadEnergy(b.Energy)
+		switch {
+		case err != nil:
+			log.Warn("could not load energy readings", "path", b.Energy, "err", err)
+		case e.Unavailable != "":
+			log.Warn("energy counters were unavailable during the run", "reason", e.Unavailable)
+		default:
+			opts.Energy = e
+			log.Info("loaded energy readings", "groups", len(e.Groups), "path", b.Energy)
+		}
+	}
+	if b.Environment != "" {
+		env, err := LoadEnvironment(b.Environment)
+		if err != nil {
+			log.Warn("could not load environment", "path", b.Environment, "err", err)
+		} else {
+			opts.Environment = env
+			log.Info("loaded environment", "path", b.Environment)
+		}
+	}
+	if b.BuildInfo != "" {
+		bi, err := LoadBuildInfo(b.BuildInfo)
+		if err != nil {
+			log.Warn("could not load build info", "path", b.BuildInfo, "err", err)
+		} else {
+			opts.BuildInfo = bi
+			log.Info("loaded build info", "path", b.BuildInfo)
+		}
+	}
+	if b.Scheduling != "" {
+		s, err := LoadScheduling(b.Scheduling)
+		if err != nil {
+			log.Warn("could not load scheduling", "path", b.Scheduling, "err", err)
+		} else {
+			opts.Scheduling = s
+			log.Info("loaded scheduling", "path", b.Scheduling)
+		}
+	}
+	if b.Control != "" {
+		control, err := LoadControl(b.Control)
+		if err != nil {
+			log.Warn("could not load control benchmark", "path", b.Control, "err", err)
+		} else {
+			opts.Control = control
+			log.Info("loaded control samples", "count", len(control.Samples), "path", b.Control)
+		}
+	}
+	if b.Skipped != "" {
+		skipped, err := LoadSkipped(b.Skipped)
+		if err != nil {
+			log.Warn("could not load skipped operations", "path", b.Skipped, "err", err)
+		} else {
+			opts.Skipped = skipped
+			log.Info("loaded skipped operations", "count", len(skipped.Entries), "path", b.Skipped)
+		}
+	}
+	if b.Robustness != "" {
+		r, err := LoadRobustness(b.Robustness)
+		if err != nil {
+			log.Warn("could not load robustness outcomes", "path", b.Robustness, "err", err)
+		} else {
+			opts.Robustness = r
+			log.Info("loaded robustness outcomes", "count", len(r.Entries), "path", b.Robustness)
+		}
+	}
+	if b.Containment != "" {
+		c, err := LoadContainment(b.Containment)
+		if err != nil {
+			log.Warn("could not load containment outcomes", "path", b.Containment, "err", err)
+		} else {
+			opts.Containment = c
+			log.Info("loaded containment outcomes", "count", len(c.Groups), "path", b.Containment)
+		}
+	}
+	if b.Adaptive != "" {
+		a, err := LoadAdaptiveSampling(b.Adaptive)
+		if err != nil {
+			log.Warn("could not load adaptive sampling record", "path", b.Adaptive, "err", err)
+		} else {
+			opts.Adaptive = a
+			log.Info("loaded adaptive sampling record", "rounds", a.Rounds, "path", b.Adaptive)
+		}
+	}
+	if b.BuildMetrics != "" {
+		m, err := LoadBuildMetrics(b.BuildMetrics)
+		if err != nil {
+			log.Warn("could not load build metrics", "path", b.BuildMetrics, "err", err)
+		} else {
+			opts.BuildMetrics = m
+			log.Info("loaded build metrics", "target", m.Target, "path", b.BuildMetrics)
+		}
+	}
+	if b.Flamegraphs != "" {
+		f, err := LoadFlamegraphs(b.Flamegraphs)
+		if err != nil {
+			log.Warn("could not load flamegraphs", "path", b.Flamegraphs, "err", err)
+		} else {
+			opts.Flamegraphs = f
+			log.Info("loaded flamegraphs", "operations", len(f), "path", b.Flamegraphs)
+		}
+	}
+	if b.ExecTraces != "" {
+		t, err := LoadExecTraces(b.ExecTraces)
+		if err != nil {
+			log.Warn("could not load execution trace summaries", "path", b.ExecTraces, "err", err)
+		} else {
+			opts.ExecTraces = t
+			log.Info("loaded execution trace summaries", "operations", len(t), "path", b.ExecTraces)
+		}
+	}
+	if b.Soak != "" {
+		s, err := LoadSoak(b.Soak)
+		if err != nil {
+			log.Warn("could not load soak samples", "path", b.Soak, "err", err)
+		} else {
+			opts.Soak = s
+			log.Info("loaded soak samples", "samples", len(s.Samples), "path", b.Soak)
+		}
+	}
+	if b.OutputSizes != "" {
+		s, err := LoadOutputSizes(b.OutputSizes)
+		if err != nil {
+			log.Warn("could not load output sizes", "path", b.OutputSizes, "err", err)
+		} else {
+			opts.OutputSizes = s
+			log.Info("loaded output sizes", "operations", len(s), "path", b.OutputSizes)
+		}
+	}
+
+	if b.Partial != "" {
+		p, err := LoadPartial(b.Partial)
+		if err != nil {
+			log.Warn("could not load partial progress", "path", b.Partial, "err", err)
+		} else {
+			opts.Partial = p
+			log.Info("loaded partial progress", "operations", len(p.Operations), "path", b.Partial)
+		}
+	}
+
+	var aliases AliasTable
+	if b.Aliases != "" {
+		t, err := LoadAliases(b.Aliases)
+		if err != nil {
+			return nil, opts, fmt.Errorf("alias table: %w", err)
+		}
+		aliases = t
+		log.Info("loaded operation aliases", "count", len(t), "path", b.Aliases)
+	}
+	if b.Operations != "" {
+		m, err := LoadOperations(b.Operations)
+		if err != nil {
+			return nil, opts, fmt.Errorf("operations manifest: %w", err)
+		}
+		if aliases == nil {
+			aliases = DefaultAliases()
+		}
+		m.addAliases(aliases)
+		opts.Operations = m
+		log.Info("loaded operations manifest", "count", len(m), "path", b.Operations)
+	}
+
+	mode := b.Mode
+	if mode == "" {
+		mode = ParseLenient
+	}
+	results, diag, err := ParseBenchResults(b.BenchRaw, mode, aliases)
+	opts.ParseDiagnostics = diag
+	if opts.Partial != nil {
+		// A crashed run may have cut off its go test output, or left
+		// none; what the harness flushed fills the gaps.
+		if results == nil && errors.Is(err, ErrNoResults) {
+			results = make(map[string]*BenchResult)
+		}
+		if n := opts.Partial.Recover(results); n > 0 {
+			log.Warn("recovered results from partial progress files", "count", n, "path", b.Partial)
+		}
+		if len(results) > 0 && errors.Is(err, ErrNoResults) {
+			err = nil
+		}
+	}
+	if err != nil {
+		return nil, opts, err
+	}
+	if !diag.Clean() {
+		log.Warn("benchmark output has defects (see parse_diagnostics)", "path", b.BenchRaw,
+			"corrupt_lines", diag.LinesSkipped, "unparseable_lines", len(diag.UnmatchedBenchmarkLines), "failed_benchmarks", len(diag.FailedBenchmarks))
+	}
+	for _, sw := range b.Sweeps {
+		swResults, _, err := ParseBenchResults(sw.Path, mode, aliases)
+		if err != nil {
+			return nil, opts, err
+		}
+		opts.Sweep = append(opts.Sweep, SweepRun{Benchtime: sw.Benchtime, Results: swResults})
+	}
+	return results, opts, nil
+}
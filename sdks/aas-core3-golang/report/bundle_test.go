@@ -0,0 +1,27 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBundleInDir(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{BenchRawFile, EventsFile, SweepFileName("1s"), SweepFileName("100ms")} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b := BundleInDir(dir)
+	if b.BenchRaw != filepath.Join(dir, BenchRawFile) || b.Events != filepath.Join(dir, EventsFile) {
+		t.Errorf("unexpected bundle paths: %+v", b)
+	}
+	if b.MemoryStats != "" || b.HeapHotspots != "" {
+		t.Errorf("missing side channels should be empty: %+v", b)
+	}
+	if len(b.Sweeps) != 2 || b.Sweeps[0].Benchtime != "100ms" || b.Sweeps[1].Benchtime != "1s" {
+		t.Errorf("Sweeps = %+v, want 100ms and 1s", b.Sweeps)
+	}
+}
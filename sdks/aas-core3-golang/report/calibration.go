@@ -0,0 +1,112 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// CalibrationOperation is the operation the harness's calibration
+// benchmarks parse to. Build lists them in the report's calibration
+// section instead of among the datasets.
+const CalibrationOperation = "calibration"
+
+// Calibration workloads: what the harness itself costs per iteration.
+const (
+	// CalibrationEmptyLoop is an iteration that does nothing.
+	CalibrationEmptyLoop = "empty_loop"
+	// CalibrationNoopCall calls a function that does nothing and cannot
+	// be inlined: the least any operation can cost.
+	CalibrationNoopCall = "noop_call"
+	// CalibrationUnmarshalAny is json.Unmarshal of a dataset into an
+	// interface{}, parsing without building SDK types. It runs once per
+	// dataset, as the sub-benchmark CalibrationUnmarshalAnyPrefix+dataset.
+	CalibrationUnmarshalAny       = "unmarshal_any"
+	CalibrationUnmarshalAnyPrefix = CalibrationUnmarshalAny + "_"
+)
+
+// CalibrationEntry is the measurement of one calibration workload.
+type CalibrationEntry struct {
+	Workload string `json:"workload"`
+	// Dataset is the dataset an unmarshal workload parsed.
+	Dataset         string `json:"dataset,omitempty"`
+	Iterations      int    `json:"iterations"`
+	MeanNs          int64  `json:"mean_ns"`
+	MinNs           int64  `json:"min_ns"`
+	AllocBytesPerOp int64  `json:"alloc_bytes_per_op"`
+}
+
+// calibrationEntries returns the calibration results among results, sorted
+// by workload.
+func calibrationEntries(results map[string]*BenchResult) []CalibrationEntry {
+	var entries []CalibrationEntry
+	for _, r := range results {
+		if r.Operation != CalibrationOperation {
+			continue
+		}
+		meanNs, _, _, minNs, _ := ComputeStats(r.Runs)
+		e := CalibrationEntry{
+			Workload:        r.Dataset,
+			Iterations:      r.N,
+			MeanNs:          int64(math.Round(meanNs)),
+			MinNs:           int64(math.Round(minNs)),
+			AllocBytesPerOp: r.BytesPerOp,
+		}
+		if ds, ok := strings.CutPrefix(r.Dataset, CalibrationUnmarshalAnyPrefix); ok {
+			e.Workload, e.Dataset = CalibrationUnmarshalAny, ds
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Workload != entries[j].Workload {
+			return entries[i].Workload < entries[j].Workload
+		}
+		return entries[i].Dataset < entries[j].Dataset
+	})
+	return entries
+}
+
+// CalibrationFloor returns the mean time of the noop call calibration,
+// the least an operation can take, or 0 when calibration did not run.
+func CalibrationFloor(calibration []CalibrationEntry) int64 {
+	for _, e := range calibration {
+		if e.Workload == CalibrationNoopCall {
+			return e.MeanNs
+		}
+	}
+	return 0
+}
+
+// BelowCalibrationFloor lists the measured operations of r faster than
+// floorNs, as "dataset/operation: mean_ns". Such a result timed nothing:
+// the operation was optimized away or failed early without an error.
+func BelowCalibrationFloor(r *Report, floorNs int64) []string {
+	if floorNs <= 0 {
+		return nil
+	}
+	var out []string
+	for _, ds := range sortedDatasetNames(r.Datasets) {
+		entry := r.Datasets[ds]
+		ops := make([]string, 0, len(entry.Operations))
+		for op := range entry.Operations {
+			ops = append(ops, op)
+		}
+		sort.Strings(ops)
+		for _, op := range ops {
+			if e := entry.Operations[op]; e.Measured() && e.MeanNs < floorNs {
+				out = append(out, fmt.Sprintf("%s/%s: %d ns, under the %d ns of a noop call", ds, op, e.MeanNs, floorNs))
+			}
+		}
+	}
+	return out
+}
+
+func sortedDatasetNames(datasets map[string]DatasetEntry) []string {
+	names := make([]string, 0, len(datasets))
+	for name := range datasets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
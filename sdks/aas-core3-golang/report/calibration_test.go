@@ -0,0 +1,52 @@
+package report
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBuildListsCalibrationApart(t *testing.T) {
+	results := map[string]*BenchResult{
+		"noop_call/calibration":          {Dataset: "noop_call", Operation: CalibrationOperation, N: 1000, Runs: []float64{6, 4}},
+		"empty_loop/calibration":         {Dataset: "empty_loop", Operation: CalibrationOperation, N: 1000, Runs: []float64{1}},
+		"unmarshal_any_wide/calibration": {Dataset: "unmarshal_any_wide", Operation: CalibrationOperation, N: 2, Runs: []float64{900}, BytesPerOp: 4096},
+		"wide/deserialize":               {Dataset: "wide", Operation: "deserialize", N: 10, Runs: []float64{100}},
+	}
+	rep := Build(results, Options{})
+
+	want := []CalibrationEntry{
+		{Workload: CalibrationEmptyLoop, Iterations: 1000, MeanNs: 1, MinNs: 1},
+		{Workload: CalibrationNoopCall, Iterations: 1000, MeanNs: 5, MinNs: 4},
+		{Workload: CalibrationUnmarshalAny, Dataset: "wide", Iterations: 2, MeanNs: 900, MinNs: 900, AllocBytesPerOp: 4096},
+	}
+	if !reflect.DeepEqual(rep.Calibration, want) {
+		t.Errorf("calibration = %+v, want %+v", rep.Calibration, want)
+	}
+	for _, ds := range []string{"noop_call", "empty_loop", "unmarshal_any_wide"} {
+		if _, ok := rep.Datasets[ds]; ok {
+			t.Errorf("calibration workload %s reported as a dataset", ds)
+		}
+	}
+	if _, ok := rep.Datasets["wide"].Operations["deserialize"]; !ok {
+		t.Error("wide/deserialize missing")
+	}
+	if got := CalibrationFloor(rep.Calibration); got != 5 {
+		t.Errorf("floor = %d, want 5", got)
+	}
+}
+
+func TestBelowCalibrationFloor(t *testing.T) {
+	rep := Build(map[string]*BenchResult{
+		"wide/validate":    {Dataset: "wide", Operation: "validate", N: 10, Runs: []float64{3}},
+		"wide/deserialize": {Dataset: "wide", Operation: "deserialize", N: 10, Runs: []float64{100}},
+	}, Options{})
+
+	got := BelowCalibrationFloor(rep, 5)
+	if len(got) != 1 || !strings.HasPrefix(got[0], "wide/validate: 3 ns") {
+		t.Errorf("below floor = %q, want wide/validate only", got)
+	}
+	if got := BelowCalibrationFloor(rep, 0); got != nil {
+		t.Errorf("below a zero floor = %q, want none", got)
+	}
+}
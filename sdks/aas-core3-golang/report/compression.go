@@ -0,0 +1,53 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Zip compression levels of the aasx_repackage sweep, from no compression
+// to the smallest package. CompressionDefault is the aasx_repackage
+// measurement itself.
+const (
+	CompressionStore   = "store"
+	CompressionFastest = "fastest"
+	CompressionDefault = "default"
+	CompressionBest    = "best"
+)
+
+// CompressionLevels lists the sweep levels in order of effort.
+var CompressionLevels = []string{CompressionStore, CompressionFastest, CompressionDefault, CompressionBest}
+
+// CompressionPoint is aasx_repackage on one dataset at one compression
+// level: the time it took and the package it wrote. Integrators pick a
+// level off the curve of these points, trading CPU for bandwidth.
+type CompressionPoint struct {
+	Dataset          string `json:"dataset"`
+	Level            string `json:"level"`
+	MeanNs           int64  `json:"mean_ns"`
+	PackageSizeBytes int64  `json:"package_size_bytes"`
+}
+
+// CheckCompressionLevel fails for a level not in CompressionLevels.
+func CheckCompressionLevel(level string) error {
+	for _, l := range CompressionLevels {
+		if l == level {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown compression level %q; want one of %v", level, CompressionLevels)
+}
+
+// SortCompressionSweep orders points by dataset, then by level effort.
+func SortCompressionSweep(points []CompressionPoint) {
+	rank := make(map[string]int, len(CompressionLevels))
+	for i, l := range CompressionLevels {
+		rank[l] = i
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		if points[i].Dataset != points[j].Dataset {
+			return points[i].Dataset < points[j].Dataset
+		}
+		return rank[points[i].Level] < rank[points[j].Level]
+	})
+}
@@ -0,0 +1,65 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Outcomes of an operation group run under a memory cap. Only
+// ContainmentCompleted means the cap was comfortable; a thrashed group
+// finished only by reclaiming or collecting at the cap.
+const (
+	ContainmentCompleted = "completed"
+	ContainmentThrashed  = "thrashed"
+	ContainmentOOM       = "oom"
+	ContainmentFailed    = "failed"
+)
+
+// Methods of enforcing the cap. A cgroup cap is hard: the kernel reclaims
+// at it and kills the group beyond it. GOMEMLIMIT is the fallback where no
+// cgroup can be created; it only makes the Go runtime collect harder, so an
+// OOM under it is inferred from the peak RSS exceeding the cap.
+const (
+	ContainmentCgroup     = "cgroup"
+	ContainmentGOMEMLIMIT = "gomemlimit"
+)
+
+// Containment is the schema of the containment.json file and the report's
+// containment section: how every operation group fared in its own process
+// under one memory cap.
+type Containment struct {
+	CapBytes int64              `json:"cap_bytes"`
+	Method   string             `json:"method"`
+	Groups   []ContainmentGroup `json:"groups"`
+}
+
+// ContainmentGroup is one benchmark function run alone under the cap.
+// LimitEvents counts the times the cgroup hit memory.max and had to reclaim,
+// and OOMKills the processes the kernel killed; both stay 0 under
+// GOMEMLIMIT. PeakBytes is the cgroup's memory.peak, or the process's peak
+// RSS where that is not available.
+type ContainmentGroup struct {
+	Benchmark   string  `json:"benchmark"`
+	OperationID string  `json:"operation_id"`
+	Outcome     string  `json:"outcome"`
+	PeakBytes   *int64  `json:"peak_bytes"`
+	LimitEvents int64   `json:"limit_events"`
+	OOMKills    int64   `json:"oom_kills"`
+	WallMs      float64 `json:"wall_ms"`
+	Detail      string  `json:"detail,omitempty"`
+}
+
+// LoadContainment reads the containment.json file written by aasbench run
+// --memory-cap.
+func LoadContainment(path string) (*Containment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Containment
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("parse containment.json: %w", err)
+	}
+	return &c, nil
+}
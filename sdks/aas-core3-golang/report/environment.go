@@ -0,0 +1,94 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Environment describes the host a run was measured on. Fields that could
+// not be determined are null or empty, never guessed.
+type Environment struct {
+	OS               string           `json:"os"`
+	Arch             string           `json:"arch"`
+	KernelVersion    string           `json:"kernel_version"`
+	CPU              CPUInfo          `json:"cpu"`
+	MemoryTotalBytes *int64           `json:"memory_total_bytes"`
+	Cgroup           *CgroupLimits    `json:"cgroup"`
+	FrequencyScaling FrequencyScaling `json:"frequency_scaling"`
+	Virtualization   Virtualization   `json:"virtualization"`
+}
+
+// CPUInfo is the processor topology visible to the run.
+type CPUInfo struct {
+	Model         string   `json:"model"`
+	MHz           *float64 `json:"mhz"`
+	LogicalCores  int      `json:"logical_cores"`
+	PhysicalCores *int     `json:"physical_cores"`
+	Sockets       *int     `json:"sockets"`
+	NUMANodes     *int     `json:"numa_nodes"`
+}
+
+// CgroupLimits are the CPU and memory limits of the run's cgroup. Nil
+// limits mean unlimited.
+type CgroupLimits struct {
+	Version          string   `json:"version"`
+	CPULimitCores    *float64 `json:"cpu_limit_cores"`
+	MemoryLimitBytes *int64   `json:"memory_limit_bytes"`
+}
+
+// FrequencyScaling records whether clock speed could vary during the run.
+// Active is true for any governor other than "performance".
+type FrequencyScaling struct {
+	Governor     string `json:"governor"`
+	Active       *bool  `json:"active"`
+	TurboEnabled *bool  `json:"turbo_enabled"`
+}
+
+// Virtualization records hints that the run was not on bare metal.
+type Virtualization struct {
+	// Hypervisor is the platform vendor (e.g. "KVM", "Amazon EC2") when the
+	// CPU reports running under one.
+	Hypervisor string `json:"hypervisor"`
+	// Container is the container runtime (docker, podman, kubernetes).
+	Container string `json:"container"`
+}
+
+// Differences lists the fields of e that differ from other and make the
+// two runs' timings not directly comparable.
+func (e *Environment) Differences(other *Environment) []string {
+	var diffs []string
+	check := func(name, a, b string) {
+		if a != b {
+			diffs = append(diffs, fmt.Sprintf("%s: %q vs %q", name, a, b))
+		}
+	}
+	check("os", e.OS, other.OS)
+	check("arch", e.Arch, other.Arch)
+	check("cpu.model", e.CPU.Model, other.CPU.Model)
+	check("cpu.logical_cores", fmt.Sprint(e.CPU.LogicalCores), fmt.Sprint(other.CPU.LogicalCores))
+	check("cgroup.cpu_limit_cores", cpuLimit(e), cpuLimit(other))
+	check("frequency_scaling.governor", e.FrequencyScaling.Governor, other.FrequencyScaling.Governor)
+	check("virtualization.hypervisor", e.Virtualization.Hypervisor, other.Virtualization.Hypervisor)
+	return diffs
+}
+
+func cpuLimit(e *Environment) string {
+	if e.Cgroup == nil || e.Cgroup.CPULimitCores == nil {
+		return "unlimited"
+	}
+	return fmt.Sprint(*e.Cgroup.CPULimitCores)
+}
+
+// LoadEnvironment reads the environment.json side channel.
+func LoadEnvironment(path string) (*Environment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var env Environment
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("parse environment.json: %w", err)
+	}
+	return &env, nil
+}
@@ -0,0 +1,70 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// ExecTraceSuffix ends the name of the execution traces an EXEC_TRACE run
+// of the harness writes, one per operation: <operation>.trace.
+const ExecTraceSuffix = ".trace"
+
+// ExecTraces maps an operation to the summary of its execution trace. It
+// is the schema of the exec_traces.json file and the report's exec_traces
+// section.
+type ExecTraces map[string]ExecTrace
+
+// ExecTrace summarizes the Go execution trace of one iteration of an
+// operation, the first one the harness ran, to explain what a plain timing
+// cannot: whether the operation fanned out into goroutines, waited on them
+// or on locks, or was slowed by assisting the garbage collector. Only the
+// goroutine that ran the iteration and the goroutines it started,
+// transitively, are counted.
+type ExecTrace struct {
+	Dataset string `json:"dataset"`
+	// File is the trace, relative to the report, for go tool trace.
+	File   string `json:"file"`
+	WallNs int64  `json:"wall_ns"`
+	// GoroutinesCreated is how many goroutines the iteration started, and
+	// MaxGoroutines how many of them, with its own, were alive at once.
+	GoroutinesCreated int `json:"goroutines_created"`
+	MaxGoroutines     int `json:"max_goroutines"`
+	// GCAssistNs is the time spent in GC mark assists.
+	GCAssistNs int64 `json:"gc_assist_ns"`
+	// BlockedNs is the time spent waiting (on channels, locks, I/O, ...),
+	// summed over the goroutines, and BlockedByReason splits it by the
+	// runtime's wait reason.
+	BlockedNs       int64            `json:"blocked_ns"`
+	BlockedByReason map[string]int64 `json:"blocked_by_reason,omitempty"`
+	// RunnableNs is the time spent runnable but waiting for a P, summed
+	// over the goroutines.
+	RunnableNs int64 `json:"runnable_ns"`
+}
+
+// Under returns t with every trace file prefixed by dir, for a report
+// written one directory above the traces.
+func (t ExecTraces) Under(dir string) ExecTraces {
+	if t == nil {
+		return nil
+	}
+	out := make(ExecTraces, len(t))
+	for operation, s := range t {
+		s.File = dir + "/" + s.File
+		out[operation] = s
+	}
+	return out
+}
+
+// LoadExecTraces reads the exec_traces.json file written by aasbench run.
+func LoadExecTraces(path string) (ExecTraces, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t ExecTraces
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parse exec_traces.json: %w", err)
+	}
+	return t, nil
+}
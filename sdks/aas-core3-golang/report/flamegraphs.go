@@ -0,0 +1,107 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/profile"
+)
+
+// CPUProfileSuffix ends the name of the CPU profiles a CPU_PROFILE run of
+// the harness writes, one per sub-benchmark: <operation>.<dataset>.cpu.pprof.
+const CPUProfileSuffix = ".cpu.pprof"
+
+// FlamegraphDir holds, under an output directory, one flamegraph SVG per
+// profiled operation.
+const FlamegraphDir = "flamegraphs"
+
+// Flamegraphs maps an operation to its flamegraph SVG, relative to the
+// report. It is the schema of the flamegraphs.json file and the report's
+// flamegraphs section.
+type Flamegraphs map[string]string
+
+// RenderFlamegraphs renders the CPU profiles in dir as one flamegraph per
+// operation into dir/FlamegraphDir, writes their index to
+// dir/FlamegraphsFile and returns it. An operation's datasets are the
+// frames above its root, so the graph shows both where the time went and
+// on which dataset. It returns nil when dir holds no CPU profiles.
+func RenderFlamegraphs(dir string) (Flamegraphs, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+CPUProfileSuffix))
+	if err != nil || len(paths) == 0 {
+		return nil, err
+	}
+	sort.Strings(paths)
+	graphs := make(map[string]*profile.Flamegraph)
+	for _, path := range paths {
+		operation, dataset, ok := strings.Cut(strings.TrimSuffix(filepath.Base(path), CPUProfileSuffix), ".")
+		if !ok {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		p, err := profile.Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		folded, err := p.Folded("cpu")
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		g := graphs[operation]
+		if g == nil {
+			g = &profile.Flamegraph{Title: operation + " (CPU time by dataset)", Unit: "nanoseconds"}
+			graphs[operation] = g
+		}
+		g.AddFolded([]string{dataset}, folded)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, FlamegraphDir), 0755); err != nil {
+		return nil, err
+	}
+	index := make(Flamegraphs)
+	for operation, g := range graphs {
+		// A namespace's ":" would read as a URL scheme in a link.
+		rel := FlamegraphDir + "/" + strings.ReplaceAll(operation, ":", ".") + ".svg"
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(rel)), []byte(g.SVG()), 0644); err != nil {
+			return nil, err
+		}
+		index[operation] = rel
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return index, os.WriteFile(filepath.Join(dir, FlamegraphsFile), data, 0644)
+}
+
+// Under returns f with every path prefixed by dir, for a report written
+// one directory above the flamegraphs'.
+func (f Flamegraphs) Under(dir string) Flamegraphs {
+	if f == nil {
+		return nil
+	}
+	out := make(Flamegraphs, len(f))
+	for operation, rel := range f {
+		out[operation] = dir + "/" + rel
+	}
+	return out
+}
+
+// LoadFlamegraphs reads the flamegraphs.json file written by
+// RenderFlamegraphs.
+func LoadFlamegraphs(path string) (Flamegraphs, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f Flamegraphs
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse flamegraphs.json: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,64 @@
+package report
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime/pprof"
+	"strings"
+	"testing"
+	"time"
+)
+
+// cpuProfile profiles a busy loop of d.
+func cpuProfile(t *testing.T, d time.Duration) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := pprof.StartCPUProfile(&buf); err != nil {
+		t.Skipf("CPU profiling unavailable: %v", err)
+	}
+	x := 0
+	for start := time.Now(); time.Since(start) < d; x++ {
+	}
+	pprof.StopCPUProfile()
+	return buf.Bytes()
+}
+
+func TestRenderFlamegraphs(t *testing.T) {
+	dir := t.TempDir()
+	if graphs, err := RenderFlamegraphs(dir); graphs != nil || err != nil {
+		t.Fatalf("without profiles = %v, %v; want nothing", graphs, err)
+	}
+	data := cpuProfile(t, 200*time.Millisecond)
+	for _, name := range []string{"deserialize.mixed", "deserialize.wide", "vendorx:transform.mixed"} {
+		if err := os.WriteFile(filepath.Join(dir, name+CPUProfileSuffix), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	graphs, err := RenderFlamegraphs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Flamegraphs{"deserialize": "flamegraphs/deserialize.svg", "vendorx:transform": "flamegraphs/vendorx.transform.svg"}
+	if !reflect.DeepEqual(graphs, want) {
+		t.Errorf("graphs = %v, want %v", graphs, want)
+	}
+	svg, err := os.ReadFile(filepath.Join(dir, "flamegraphs", "deserialize.svg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"deserialize (CPU time by dataset)", "<title>mixed (", "<title>wide ("} {
+		if !strings.Contains(string(svg), want) {
+			t.Errorf("deserialize.svg lacks %q", want)
+		}
+	}
+	loaded, err := LoadFlamegraphs(BundleInDir(dir).Flamegraphs)
+	if err != nil || !reflect.DeepEqual(loaded, want) {
+		t.Errorf("flamegraphs.json = %v, %v", loaded, err)
+	}
+	if under := graphs.Under("run-1"); under["deserialize"] != "run-1/flamegraphs/deserialize.svg" {
+		t.Errorf("Under = %v", under)
+	}
+}
@@ -0,0 +1,44 @@
+package report
+
+// GCPauseStats summarizes the GC pauses of an operation group. Quantiles
+// are the upper bound of the histogram bucket they fall in, so they
+// overstate a pause by at most the bucket width.
+type GCPauseStats struct {
+	Count uint64 `json:"count"`
+	P50Ns int64  `json:"p50_ns"`
+	P99Ns int64  `json:"p99_ns"`
+	MaxNs int64  `json:"max_ns"`
+}
+
+// GCPauseStatsOf summarizes a pause histogram with buckets in ascending
+// order. A group without buckets paused zero times.
+func GCPauseStatsOf(buckets []GCPauseBucket) *GCPauseStats {
+	stats := &GCPauseStats{}
+	for _, b := range buckets {
+		stats.Count += b.Count
+	}
+	if stats.Count == 0 {
+		return stats
+	}
+	stats.P50Ns = pauseQuantile(buckets, stats.Count, 0.50)
+	stats.P99Ns = pauseQuantile(buckets, stats.Count, 0.99)
+	stats.MaxNs = buckets[len(buckets)-1].UpperNs
+	return stats
+}
+
+// pauseQuantile returns the upper bound of the bucket holding the pause of
+// rank ceil(q*total).
+func pauseQuantile(buckets []GCPauseBucket, total uint64, q float64) int64 {
+	rank := uint64(q * float64(total))
+	if float64(rank) < q*float64(total) {
+		rank++
+	}
+	var seen uint64
+	for _, b := range buckets {
+		seen += b.Count
+		if seen >= rank {
+			return b.UpperNs
+		}
+	}
+	return buckets[len(buckets)-1].UpperNs
+}
@@ -0,0 +1,33 @@
+package report
+
+import "testing"
+
+func TestGCPauseStatsOf(t *testing.T) {
+	buckets := []GCPauseBucket{
+		{LowerNs: 10000, UpperNs: 12000, Count: 60},
+		{LowerNs: 50000, UpperNs: 60000, Count: 39},
+		{LowerNs: 900000, UpperNs: 1000000, Count: 1},
+	}
+	got := GCPauseStatsOf(buckets)
+	want := GCPauseStats{Count: 100, P50Ns: 12000, P99Ns: 60000, MaxNs: 1000000}
+	if *got != want {
+		t.Errorf("stats = %+v, want %+v", *got, want)
+	}
+
+	if got := GCPauseStatsOf(nil); *got != (GCPauseStats{}) {
+		t.Errorf("no pauses: %+v", *got)
+	}
+}
+
+func TestBuildAttachesGCPausesPerGroup(t *testing.T) {
+	results := map[string]*BenchResult{
+		"deserialize/wide": {Operation: "deserialize", Dataset: "wide", N: 10, NsPerOp: 100, Runs: []float64{100}},
+	}
+	rep := Build(results, Options{GCPauses: &GCPauses{Groups: map[string][]GCPauseBucket{
+		"deserialize": {{LowerNs: 1000, UpperNs: 2000, Count: 3}},
+	}}})
+	p := rep.Datasets["wide"].Operations["deserialize"].Memory.GcPauses
+	if p == nil || p.Count != 3 || p.MaxNs != 2000 {
+		t.Errorf("gc_pauses = %+v", p)
+	}
+}
@@ -0,0 +1,368 @@
+package report
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	// CoreDatasets are the datasets every SDK must cover for the core track.
+	CoreDatasets = map[string]bool{
+		"wide":  true,
+		"deep":  true,
+		"mixed": true,
+	}
+	// CoreOperations are the operations every SDK must cover for the core
+	// track, as declared in the embedded operations manifest.
+	CoreOperations = defaultOperations.InTrack(TrackCore)
+	// ValidationOperations are the verification operations, which form the
+	// validation track on val_* datasets.
+	ValidationOperations = defaultOperations.InTrack(TrackValidation)
+)
+
+// MicroDatasetPrefix names the datasets of the micro track, benchmarks of
+// single SDK primitives whose inputs are built into the harness.
+const MicroDatasetPrefix = "micro_"
+
+// I18nDataset is the Unicode stress dataset (datasets/generate.py --i18n),
+// which forms the i18n track: string handling cost differs too much
+// between SDKs to mix it into the capability track.
+const I18nDataset = "i18n"
+
+// GoTestEvent represents a single line from `go test -json` output.
+type GoTestEvent struct {
+	Time    string  `json:"Time"`
+	Action  string  `json:"Action"`
+	Package string  `json:"Package"`
+	Test    string  `json:"Test"`
+	Output  string  `json:"Output"`
+	Elapsed float64 `json:"Elapsed"`
+}
+
+// BenchResult holds parsed benchmark results for a single sub-benchmark.
+type BenchResult struct {
+	Operation string
+	Dataset   string
+	// Benchmark is the Go benchmark name the operation was resolved from,
+	// without the "Benchmark" prefix.
+	Benchmark string
+	// Variant is the sub-benchmark path below the dataset, e.g.
+	// "parallel=8" for BenchmarkDeserialize/wide/parallel=8, and Params the
+	// parameters it names (see SubBenchmarkParams). Both are empty for a
+	// plain BenchmarkOp/dataset result.
+	Variant string
+	Params  map[string]string
+	N       int
+	NsPerOp float64
+	// BytesPerOp and AllocsPerOp are the means of BytesRuns and AllocsRuns.
+	BytesPerOp  int64
+	AllocsPerOp int64
+	Runs        []float64 // NsPerOp across -count runs
+	// BytesRuns and AllocsRuns are B/op and allocs/op across the runs that
+	// printed them; empty when none did.
+	BytesRuns  []int64
+	AllocsRuns []int64
+	// MetricRuns are the custom metrics the benchmark reported with
+	// b.ReportMetric (and MB/s, from b.SetBytes), by unit, across the runs
+	// that printed them.
+	MetricRuns map[string][]float64
+
+	// seq orders the results by their first line in the output.
+	seq int
+}
+
+// Key is the key of r in the results of ParseBenchResults:
+// dataset/operation, followed by /variant for a variant.
+func (r *BenchResult) Key() string {
+	key := r.Dataset + "/" + r.Operation
+	if r.Variant != "" {
+		key += "/" + r.Variant
+	}
+	return key
+}
+
+// ErrNoResults is returned by ParseBenchResults for output without a single
+// benchmark result.
+var ErrNoResults = errors.New("no benchmark results")
+
+// benchLineRegex matches Go benchmark output lines like:
+// BenchmarkDeserialize/wide-8   1000   1234567 ns/op   8192 B/op   100 allocs/op
+// and, for sub-benchmarks nested below the dataset, like:
+// BenchmarkDeserialize/wide/parallel=8-8   1000   1234567 ns/op
+// The <value> <unit> pairs after ns/op are left to parseMetrics.
+var benchLineRegex = regexp.MustCompile(
+	`^Benchmark(\w+)/(\w+)(?:/(\S+?))?(?:-\d+)?\s+(\d+)\s+([\d.]+)\s+ns/op(.*)`,
+)
+
+// SubBenchmarkParams maps the segments of a sub-benchmark path below the
+// dataset to parameters. A "key=value" segment, the form go test names
+// parameterized sub-benchmarks in, is parameter key; any other segment is
+// parameter "level<n>", n counting the levels below the dataset from 1.
+// When a key repeats, the deepest segment wins.
+func SubBenchmarkParams(variant string) map[string]string {
+	if variant == "" {
+		return nil
+	}
+	params := make(map[string]string)
+	for i, segment := range strings.Split(variant, "/") {
+		if key, value, ok := strings.Cut(segment, "="); ok && key != "" {
+			params[key] = value
+		} else {
+			params["level"+strconv.Itoa(i+1)] = segment
+		}
+	}
+	return params
+}
+
+// CanonicalOperationID maps a Go benchmark name to its snake_case operation
+// ID using the default alias table.
+func CanonicalOperationID(raw string) string {
+	return defaultAliases.Resolve(raw)
+}
+
+var defaultAliases = DefaultAliases()
+
+// InferOperationTrack assigns the dashboard track for a dataset/operation
+// pair by the embedded operations manifest.
+func InferOperationTrack(dataset, operationID string) string {
+	return defaultOperations.Track(dataset, operationID)
+}
+
+// ParseMode selects how ParseBenchResults treats output it cannot parse.
+type ParseMode string
+
+const (
+	// ParseLenient skips corrupt lines and records them in ParseDiagnostics.
+	ParseLenient ParseMode = "lenient"
+	// ParseStrict fails on any corrupt line, unparseable benchmark line or
+	// failed benchmark.
+	ParseStrict ParseMode = "strict"
+)
+
+// maxDiagnosticSamples bounds the skipped and unmatched lines kept verbatim.
+const maxDiagnosticSamples = 20
+
+// ParseDiagnostics describes how well a go test -json file parsed, so that
+// a harness failure shows up as more than a missing row in the report.
+type ParseDiagnostics struct {
+	Mode              ParseMode `json:"mode"`
+	LinesRead         int       `json:"lines_read"`
+	BenchmarksMatched int       `json:"benchmarks_matched"`
+	LinesSkipped      int       `json:"lines_skipped"`
+	// Skipped samples the lines that were not go test -json events.
+	Skipped []SkippedLine `json:"skipped,omitempty"`
+	// UnmatchedBenchmarkLines samples benchmark output without a parseable
+	// result, e.g. a line truncated by a crash.
+	UnmatchedBenchmarkLines []string `json:"unmatched_benchmark_lines,omitempty"`
+	FailedBenchmarks        []string `json:"failed_benchmarks,omitempty"`
+}
+
+// SkippedLine is one input line ParseBenchResults could not use.
+type SkippedLine struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// Clean reports whether the file parsed without skipped, unmatched or
+// failed benchmark lines.
+func (d *ParseDiagnostics) Clean() bool {
+	return d.LinesSkipped == 0 && len(d.UnmatchedBenchmarkLines) == 0 && len(d.FailedBenchmarks) == 0
+}
+
+func (d *ParseDiagnostics) skip(line int, reason string) {
+	d.LinesSkipped++
+	if len(d.Skipped) < maxDiagnosticSamples {
+		d.Skipped = append(d.Skipped, SkippedLine{Line: line, Reason: reason})
+	}
+}
+
+func (d *ParseDiagnostics) unmatched(output string) {
+	if len(d.UnmatchedBenchmarkLines) < maxDiagnosticSamples {
+		d.UnmatchedBenchmarkLines = append(d.UnmatchedBenchmarkLines, output)
+	}
+}
+
+// ParseBenchResults reads `go test -json` output and groups benchmark lines
+// by dataset/operation, and sub-benchmarks nested below the dataset by
+// their variant too (see BenchResult.Key). Output a test emits in several events (go 1.24+
+// splits a benchmark's name from its result) is joined back into lines.
+// Benchmark names are resolved to operation IDs through aliases, nil
+// meaning DefaultAliases. In either mode it fails when no benchmark result
+// is found or when two benchmarks resolve to the same operation.
+func ParseBenchResults(path string, mode ParseMode, aliases AliasTable) (map[string]*BenchResult, *ParseDiagnostics, error) {
+	if aliases == nil {
+		aliases = defaultAliases
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	results := make(map[string]*BenchResult)
+	diag := &ParseDiagnostics{Mode: mode}
+	partial := make(map[string]string) // unterminated output per test
+	scanner := bufio.NewScanner(f)
+	// Increase buffer for potentially long lines
+	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
+
+	for scanner.Scan() {
+		diag.LinesRead++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		// Each line of `go test -json` is a JSON object
+		var event GoTestEvent
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			diag.skip(diag.LinesRead, "invalid JSON: "+err.Error())
+			continue
+		}
+
+		if event.Action == "fail" && strings.HasPrefix(event.Test, "Benchmark") {
+			diag.FailedBenchmarks = append(diag.FailedBenchmarks, event.Test)
+			continue
+		}
+		if event.Action != "output" {
+			continue
+		}
+
+		key := event.Package + " " + event.Test
+		text := partial[key] + event.Output
+		if !strings.HasSuffix(text, "\n") {
+			partial[key] = text
+			continue
+		}
+		delete(partial, key)
+
+		for _, output := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			output = strings.TrimSpace(output)
+			matched, err := parseBenchLine(output, aliases, results)
+			if err != nil {
+				return nil, diag, fmt.Errorf("%s: %w", path, err)
+			}
+			if matched {
+				diag.BenchmarksMatched++
+			} else if isBenchResultLine(output) {
+				diag.unmatched(output)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, diag, fmt.Errorf("read %s: %w", path, err)
+	}
+	for _, text := range partial {
+		if output := strings.TrimSpace(text); strings.HasPrefix(output, "Benchmark") {
+			diag.unmatched(output)
+		}
+	}
+
+	if diag.BenchmarksMatched == 0 {
+		return nil, diag, fmt.Errorf("%w in %s (%d line(s) read, %d skipped)", ErrNoResults, path, diag.LinesRead, diag.LinesSkipped)
+	}
+	if mode == ParseStrict && !diag.Clean() {
+		return nil, diag, fmt.Errorf("%s: %d corrupt line(s), %d unparseable benchmark line(s), %d failed benchmark(s)",
+			path, diag.LinesSkipped, len(diag.UnmatchedBenchmarkLines), len(diag.FailedBenchmarks))
+	}
+	return results, diag, nil
+}
+
+// isBenchResultLine reports whether output looks like a benchmark result,
+// as opposed to the bare benchmark name go test prints before running it.
+func isBenchResultLine(output string) bool {
+	return strings.HasPrefix(output, "Benchmark") && len(strings.Fields(output)) > 1
+}
+
+// parseBenchLine adds one benchmark result line to results. It reports
+// whether output was a result line, and fails when the line's benchmark
+// resolves to an operation already produced by a different benchmark.
+func parseBenchLine(output string, aliases AliasTable, results map[string]*BenchResult) (bool, error) {
+	matches := benchLineRegex.FindStringSubmatch(output)
+	if matches == nil {
+		return false, nil
+	}
+
+	operation := aliases.Resolve(matches[1])
+	dataset := matches[2] // e.g., "wide"
+	n, _ := strconv.Atoi(matches[4])
+	nsPerOp, _ := strconv.ParseFloat(matches[5], 64)
+
+	candidate := &BenchResult{
+		Operation: operation,
+		Dataset:   dataset,
+		Benchmark: matches[1],
+		Variant:   matches[3],
+		Params:    SubBenchmarkParams(matches[3]),
+		seq:       len(results),
+	}
+	key := candidate.Key()
+	if _, exists := results[key]; !exists {
+		results[key] = candidate
+	}
+	r := results[key]
+	if r.Benchmark != matches[1] {
+		return false, fmt.Errorf("Benchmark%s and Benchmark%s both resolve to operation %q; fix the alias table",
+			r.Benchmark, matches[1], operation)
+	}
+	r.N += n
+	r.Runs = append(r.Runs, nsPerOp)
+	r.addMetrics(matches[6])
+	return true, nil
+}
+
+// addMetrics records the <value> <unit> pairs that follow ns/op on a
+// result line: B/op and allocs/op from b.ReportAllocs, anything else as a
+// custom metric. Go prints MB/s and custom metrics before B/op, so no
+// order is assumed. Parsing stops at the first pair whose value is not a
+// number, such as the rest of a truncated line.
+func (r *BenchResult) addMetrics(rest string) {
+	fields := strings.Fields(rest)
+	for i := 0; i+1 < len(fields); i += 2 {
+		value, err := strconv.ParseFloat(fields[i], 64)
+		if err != nil {
+			return
+		}
+		switch unit := fields[i+1]; unit {
+		case "B/op":
+			r.BytesRuns = append(r.BytesRuns, int64(value))
+			r.BytesPerOp = meanInt64(r.BytesRuns)
+		case "allocs/op":
+			r.AllocsRuns = append(r.AllocsRuns, int64(value))
+			r.AllocsPerOp = meanInt64(r.AllocsRuns)
+		default:
+			if r.MetricRuns == nil {
+				r.MetricRuns = make(map[string][]float64)
+			}
+			r.MetricRuns[unit] = append(r.MetricRuns[unit], value)
+		}
+	}
+}
+
+// meanInt64 returns the rounded mean of values.
+func meanInt64(values []int64) int64 {
+	var sum float64
+	for _, v := range values {
+		sum += float64(v)
+	}
+	return int64(math.Round(sum / float64(len(values))))
+}
+
+// minMaxInt64 returns the extremes of values, or nils when it is empty.
+func minMaxInt64(values []int64) (lo, hi *int64) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	minV, maxV := values[0], values[0]
+	for _, v := range values[1:] {
+		minV, maxV = min(minV, v), max(maxV, v)
+	}
+	return &minV, &maxV
+}
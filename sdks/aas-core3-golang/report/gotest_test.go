@@ -0,0 +1,139 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeRaw(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), BenchRawFile)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseBenchResultsJoinsSplitOutput(t *testing.T) {
+	path := writeRaw(t,
+		`{"Action":"output","Test":"BenchmarkDeserialize/deep","Output":"BenchmarkDeserialize/deep\n"}`,
+		`{"Action":"output","Test":"BenchmarkDeserialize/deep","Output":"BenchmarkDeserialize/deep-8   \t"}`,
+		`{"Action":"output","Test":"BenchmarkDeserialize/deep","Output":"     100\t  12345 ns/op\t  500 B/op\t  10 allocs/op\n"}`,
+		`{"Action":"output","Test":"BenchmarkSerialize/deep","Output":"BenchmarkSerialize/deep-8   \t 200\t  2000 ns/op\n"}`,
+	)
+	results, diag, err := ParseBenchResults(path, ParseStrict, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := results["deep/deserialize"]
+	if r == nil || r.N != 100 || r.Runs[0] != 12345 || r.AllocsPerOp != 10 {
+		t.Errorf("deep/deserialize = %+v", r)
+	}
+	if diag.BenchmarksMatched != 2 || !diag.Clean() {
+		t.Errorf("diagnostics = %+v", diag)
+	}
+}
+
+func TestAllocationsAveragedAcrossRuns(t *testing.T) {
+	path := writeRaw(t,
+		`{"Action":"output","Output":"BenchmarkValidate/wide-8   \t 100\t  1000 ns/op\t  500 B/op\t  10 allocs/op\n"}`,
+		`{"Action":"output","Output":"BenchmarkValidate/wide-8   \t 100\t  1100 ns/op\t  700 B/op\t  13 allocs/op\n"}`,
+		`{"Action":"output","Output":"BenchmarkValidate/wide-8   \t 100\t  1050 ns/op\t  600 B/op\t  10 allocs/op\n"}`,
+	)
+	results, _, err := ParseBenchResults(path, ParseStrict, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := results["wide/validate"]
+	if r.BytesPerOp != 600 || r.AllocsPerOp != 11 {
+		t.Errorf("wide/validate = %+v, want the means 600 B/op and 11 allocs/op, not the last run", r)
+	}
+	mem := Build(results, Options{}).Datasets["wide"].Operations["validate"].Memory
+	if *mem.AllocBytesPerOpMin != 500 || *mem.AllocBytesPerOpMax != 700 || *mem.AllocCountPerOpMin != 10 || *mem.AllocCountPerOpMax != 13 {
+		t.Errorf("memory = %+v, want the run extremes", mem)
+	}
+}
+
+func TestParseBenchResultsNestedSubBenchmarks(t *testing.T) {
+	path := writeRaw(t,
+		`{"Action":"output","Output":"BenchmarkDeserialize/wide/parallel=1-8   \t 100\t  1000 ns/op\n"}`,
+		`{"Action":"output","Output":"BenchmarkDeserialize/wide/parallel=8-8   \t 100\t  300 ns/op\n"}`,
+		`{"Action":"output","Output":"BenchmarkSerialize/wide-8   \t 100\t  500 ns/op\n"}`,
+		`{"Action":"output","Output":"BenchmarkSerialize/wide/indent=2/utf16-8   \t 100\t  700 ns/op\n"}`,
+	)
+	results, diag, err := ParseBenchResults(path, ParseStrict, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diag.BenchmarksMatched != 4 || len(diag.UnmatchedBenchmarkLines) != 0 {
+		t.Fatalf("diagnostics = %+v, want every line matched", diag)
+	}
+	r := results["wide/serialize/indent=2/utf16"]
+	if r == nil || r.Variant != "indent=2/utf16" || r.Params["indent"] != "2" || r.Params["level2"] != "utf16" {
+		t.Errorf("wide/serialize/indent=2/utf16 = %+v", r)
+	}
+
+	ops := Build(results, Options{}).Datasets["wide"].Operations
+	// Deserialize only ran as variants: the first stands in for it.
+	des := ops["deserialize"]
+	if des.Variant != "parallel=1" || des.MeanNs != 1000 || len(des.Variants) != 1 || des.Variants[0].Params["parallel"] != "8" {
+		t.Errorf("deserialize = %+v, want parallel=1 with parallel=8 as its variant", des)
+	}
+	ser := ops["serialize"]
+	if ser.Variant != "" || ser.MeanNs != 500 || len(ser.Variants) != 1 || ser.Variants[0].MeanNs != 700 {
+		t.Errorf("serialize = %+v, want the plain result with one variant", ser)
+	}
+}
+
+func TestParseBenchResultsCustomMetrics(t *testing.T) {
+	// go test prints custom metrics between ns/op and B/op.
+	path := writeRaw(t,
+		`{"Action":"output","Output":"BenchmarkTraverse/wide-8   \t 100\t  1000 ns/op\t 5000 elements/op\t 2.000 violations/op\t  0 B/op\t  0 allocs/op\n"}`,
+		`{"Action":"output","Output":"BenchmarkTraverse/wide-8   \t 100\t  1100 ns/op\t 5000 elements/op\t 3.000 violations/op\t  8 B/op\t  1 allocs/op\n"}`,
+	)
+	results, _, err := ParseBenchResults(path, ParseStrict, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := results["wide/traverse"]
+	if r.BytesPerOp != 4 || len(r.AllocsRuns) != 2 {
+		t.Errorf("wide/traverse = %+v, want B/op and allocs/op after the custom metrics", r)
+	}
+	op := Build(results, Options{}).Datasets["wide"].Operations["traverse"]
+	if got := op.CustomMetrics; len(got) != 2 || got["elements/op"] != 5000 || got["violations/op"] != 2.5 {
+		t.Errorf("custom_metrics = %v, want the means by unit", got)
+	}
+}
+
+func TestParseBenchResultsModes(t *testing.T) {
+	path := writeRaw(t,
+		`{"Action":"output","Output":"BenchmarkDeserialize/deep-8   \t 100\t  12345 ns/op\n"}`,
+		`{"Action":"output","Outp`,
+		`{"Action":"output","Output":"BenchmarkValidate/deep-8   \t 100\t  garbage\n"}`,
+		`{"Action":"fail","Test":"BenchmarkTraverse/deep"}`,
+	)
+
+	results, diag, err := ParseBenchResults(path, ParseLenient, nil)
+	if err != nil {
+		t.Fatalf("lenient: %v", err)
+	}
+	if len(results) != 1 || diag.LinesRead != 4 || diag.LinesSkipped != 1 || diag.Skipped[0].Line != 2 {
+		t.Errorf("lenient diagnostics = %+v", diag)
+	}
+	if len(diag.UnmatchedBenchmarkLines) != 1 || len(diag.FailedBenchmarks) != 1 {
+		t.Errorf("lenient diagnostics = %+v", diag)
+	}
+
+	if _, _, err := ParseBenchResults(path, ParseStrict, nil); err == nil {
+		t.Error("strict mode accepted corrupt output")
+	}
+}
+
+func TestParseBenchResultsFailsWithoutBenchmarks(t *testing.T) {
+	path := writeRaw(t, `{"Action":"output","Output":"PASS\n"}`)
+	if _, _, err := ParseBenchResults(path, ParseLenient, nil); err == nil {
+		t.Error("expected an error when no benchmark results are found")
+	}
+}
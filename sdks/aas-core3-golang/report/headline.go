@@ -0,0 +1,196 @@
+package report
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// defaultCatalogJSON lists the metrics a report can be summarized by.
+//
+//go:embed metrics.json
+var defaultCatalogJSON []byte
+
+// Stats a catalog metric can read from an operation.
+const (
+	StatMeanNs          = "mean_ns"
+	StatMedianNs        = "median_ns"
+	StatP99Ns           = "p99_ns"
+	StatPeakRSSBytes    = "peak_rss_bytes"
+	StatAllocBytesPerOp = "alloc_bytes_per_op"
+)
+
+// CatalogMetric describes one candidate headline metric. Operation may be
+// empty, in which case the metric is the largest value of the stat across
+// the dataset's operations (peak RSS of a whole dataset run). Stats are
+// tried in order, so an adapter without p99 still yields its mean.
+type CatalogMetric struct {
+	ID        string   `json:"id"`
+	Dataset   string   `json:"dataset"`
+	Operation string   `json:"operation,omitempty"`
+	Stats     []string `json:"stats"`
+	// Headline marks the metrics that represent an SDK. Unflagged metrics
+	// only fill the block when a flagged one has no data.
+	Headline bool `json:"headline,omitempty"`
+}
+
+// MetricCatalog is the schema of metrics.json.
+type MetricCatalog struct {
+	HeadlineSize int             `json:"headline_size"`
+	Metrics      []CatalogMetric `json:"metrics"`
+}
+
+// HeadlineMetric is one entry of a report's headline block.
+type HeadlineMetric struct {
+	ID        string `json:"id"`
+	Label     string `json:"label"`
+	Dataset   string `json:"dataset"`
+	Operation string `json:"operation,omitempty"`
+	Stat      string `json:"stat"`
+	Unit      string `json:"unit"`
+	Value     int64  `json:"value"`
+}
+
+// DefaultCatalog returns the embedded metric catalog.
+func DefaultCatalog() *MetricCatalog {
+	var c MetricCatalog
+	if err := json.Unmarshal(defaultCatalogJSON, &c); err != nil {
+		panic(fmt.Sprintf("parse embedded metrics.json: %v", err))
+	}
+	return &c
+}
+
+// SelectHeadline picks up to HeadlineSize metrics that datasets has data
+// for: flagged metrics first, then unflagged ones, each in catalog order.
+func (c *MetricCatalog) SelectHeadline(datasets map[string]DatasetEntry) []HeadlineMetric {
+	var picked []HeadlineMetric
+	for _, flagged := range []bool{true, false} {
+		for _, m := range c.Metrics {
+			if m.Headline != flagged || len(picked) >= c.HeadlineSize {
+				continue
+			}
+			if h, ok := m.resolve(datasets); ok {
+				picked = append(picked, h)
+			}
+		}
+	}
+	return picked
+}
+
+// resolve reads the first of m's stats that datasets has a value for.
+func (m CatalogMetric) resolve(datasets map[string]DatasetEntry) (HeadlineMetric, bool) {
+	for _, stat := range m.Stats {
+		if v, ok := HeadlineValue(datasets, m.Dataset, m.Operation, stat); ok {
+			return HeadlineMetric{
+				ID:        m.ID,
+				Label:     headlineLabel(m.Dataset, m.Operation, stat),
+				Dataset:   m.Dataset,
+				Operation: m.Operation,
+				Stat:      stat,
+				Unit:      statUnit(stat),
+				Value:     v,
+			}, true
+		}
+	}
+	return HeadlineMetric{}, false
+}
+
+// HeadlineValue reads stat of one operation, or the largest across the
+// dataset when operation is empty. ok is false when there is no value, or
+// a zero one, which adapters write for unmeasured stats.
+func HeadlineValue(datasets map[string]DatasetEntry, dataset, operation, stat string) (int64, bool) {
+	ds, ok := datasets[dataset]
+	if !ok {
+		return 0, false
+	}
+	if operation != "" {
+		for key, op := range ds.Operations {
+			if NormalizeOperationID(key) == operation {
+				return statValue(op, stat)
+			}
+		}
+		return 0, false
+	}
+	var max int64
+	for _, op := range ds.Operations {
+		if v, ok := statValue(op, stat); ok && v > max {
+			max = v
+		}
+	}
+	return max, max > 0
+}
+
+func statValue(op OperationEntry, stat string) (int64, bool) {
+	var v *int64
+	switch stat {
+	case StatMeanNs:
+		v = &op.MeanNs
+	case StatMedianNs:
+		v = &op.MedianNs
+	case StatP99Ns:
+		v = op.P99Ns
+	case StatPeakRSSBytes:
+		v = op.Memory.PeakRSSBytes
+	case StatAllocBytesPerOp:
+		v = op.Memory.AllocBytesPerOp
+	}
+	if v == nil || *v <= 0 {
+		return 0, false
+	}
+	return *v, true
+}
+
+func statUnit(stat string) string {
+	if strings.HasSuffix(stat, "_ns") {
+		return "ns"
+	}
+	return "bytes"
+}
+
+// headlineLabel names a metric the way badges and comments show it, e.g.
+// "deserialize/mixed mean" or "wide peak RSS".
+func headlineLabel(dataset, operation, stat string) string {
+	name := dataset
+	if operation != "" {
+		name = operation + "/" + dataset
+	}
+	switch stat {
+	case StatMeanNs:
+		return name + " mean"
+	case StatMedianNs:
+		return name + " median"
+	case StatP99Ns:
+		return name + " p99"
+	case StatPeakRSSBytes:
+		return name + " peak RSS"
+	case StatAllocBytesPerOp:
+		return name + " alloc/op"
+	}
+	return name + " " + stat
+}
+
+// Display formats the value for badges and comments ("1.24 ms", "3.1 MiB").
+func (h HeadlineMetric) Display() string {
+	return FormatValue(float64(h.Value), h.Unit)
+}
+
+// FormatValue scales a value in ns or bytes to a readable unit.
+func FormatValue(v float64, unit string) string {
+	var steps []string
+	var base float64
+	if unit == "ns" {
+		steps, base = []string{"ns", "µs", "ms", "s"}, 1000
+	} else {
+		steps, base = []string{"B", "KiB", "MiB", "GiB"}, 1024
+	}
+	i := 0
+	for v >= base && i < len(steps)-1 {
+		v /= base
+		i++
+	}
+	if i == 0 {
+		return fmt.Sprintf("%.0f %s", v, steps[i])
+	}
+	return fmt.Sprintf("%.3g %s", v, steps[i])
+}
@@ -0,0 +1,57 @@
+package report
+
+import "testing"
+
+func int64p(v int64) *int64 { return &v }
+
+func TestSelectHeadlineFallsBack(t *testing.T) {
+	datasets := map[string]DatasetEntry{
+		"mixed": {Operations: map[string]OperationEntry{
+			"deserialize": {MeanNs: 1240000},
+			"serialize":   {MeanNs: 800000},
+		}},
+		"deep": {Operations: map[string]OperationEntry{
+			"validate": {MeanNs: 5000, P99Ns: int64p(9000)},
+		}},
+		"wide": {Operations: map[string]OperationEntry{
+			"deserialize": {MeanNs: 1, Memory: MemoryEntry{AllocBytesPerOp: int64p(2048)}},
+			"serialize":   {MeanNs: 1, Memory: MemoryEntry{AllocBytesPerOp: int64p(4096)}},
+		}},
+	}
+	got := DefaultCatalog().SelectHeadline(datasets)
+	want := []string{"deserialize/mixed mean", "validate/deep p99", "wide alloc/op"}
+	if len(got) != len(want) {
+		t.Fatalf("headline = %+v, want %v", got, want)
+	}
+	for i, h := range got {
+		if h.Label != want[i] {
+			t.Errorf("headline[%d] = %q, want %q", i, h.Label, want[i])
+		}
+	}
+	if got[2].Value != 4096 || got[2].Display() != "4 KiB" {
+		t.Errorf("wide alloc = %d (%s), want the dataset maximum 4096", got[2].Value, got[2].Display())
+	}
+	if got[0].Display() != "1.24 ms" {
+		t.Errorf("display = %q", got[0].Display())
+	}
+
+	// Without deep, an unflagged catalog metric fills the third slot.
+	delete(datasets, "deep")
+	got = DefaultCatalog().SelectHeadline(datasets)
+	if len(got) != 3 || got[2].ID != "serialize_mixed" {
+		t.Errorf("headline without deep = %+v", got)
+	}
+}
+
+func TestDefaultCatalogStats(t *testing.T) {
+	for _, m := range DefaultCatalog().Metrics {
+		for _, stat := range m.Stats {
+			if _, ok := statValue(OperationEntry{}, stat); ok {
+				t.Errorf("metric %s: stat %q has a value on an empty operation", m.ID, stat)
+			}
+			if headlineLabel(m.Dataset, m.Operation, stat) == m.Dataset+" "+stat {
+				t.Errorf("metric %s: unknown stat %q", m.ID, stat)
+			}
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// IsolatedDir holds, under the output directory of an isolated run, one
+// directory per benchmark function, each written by its own process.
+const IsolatedDir = "isolated"
+
+// sameInEveryProcess are the side channels every process of an isolated
+// run writes alike; the first one's is kept.
+var sameInEveryProcess = map[string]bool{
+	BuildInfoFile:  true,
+	SchedulingFile: true,
+	OperationsFile: true,
+}
+
+// MergeIsolated combines the output of the processes of an isolated run,
+// each in one of procDirs, into dir, which then reads like the output of a
+// single process. The raw benchmark output is concatenated and the
+// progress files and heap profiles are copied. Side channels are merged
+// key by key: the per-operation groups of the processes add up, lists are
+// concatenated, and any other value keeps the first process's. Run-level
+// figures such as memory_stats.json's before and after snapshots are thus
+// the first process's only.
+func MergeIsolated(dir string, procDirs []string) error {
+	if err := os.MkdirAll(filepath.Join(dir, PartialDir), 0755); err != nil {
+		return err
+	}
+	raw, err := os.Create(filepath.Join(dir, BenchRawFile))
+	if err != nil {
+		return err
+	}
+	defer raw.Close()
+	merged := make(map[string]interface{})
+	var order []string
+	for _, proc := range procDirs {
+		entries, err := os.ReadDir(proc)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			name, path := e.Name(), filepath.Join(proc, e.Name())
+			switch {
+			case name == BenchRawFile:
+				err = appendTo(raw, path)
+			case name == PartialDir:
+				err = copyDir(path, filepath.Join(dir, PartialDir))
+			case e.IsDir():
+			case filepath.Ext(name) != ".json":
+				err = copyFile(path, filepath.Join(dir, name))
+			default:
+				var v interface{}
+				if v, err = readJSONValue(path); err != nil {
+					break
+				}
+				prev, seen := merged[name]
+				if !seen {
+					order = append(order, name)
+					merged[name] = v
+				} else if !sameInEveryProcess[name] {
+					merged[name] = mergeJSON(prev, v)
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("merge %s: %w", path, err)
+			}
+		}
+	}
+	for _, name := range order {
+		data, err := json.MarshalIndent(merged[name], "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeJSON merges b into a: objects key by key, lists by concatenation
+// without the elements a already has, and other values to a's.
+func mergeJSON(a, b interface{}) interface{} {
+	switch av := a.(type) {
+	case map[string]interface{}:
+		bv, ok := b.(map[string]interface{})
+		if !ok {
+			return a
+		}
+		for k, v := range bv {
+			if prev, ok := av[k]; ok {
+				av[k] = mergeJSON(prev, v)
+			} else {
+				av[k] = v
+			}
+		}
+		return av
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok {
+			return a
+		}
+		have := make(map[string]bool, len(av))
+		for _, v := range av {
+			key, _ := json.Marshal(v)
+			have[string(key)] = true
+		}
+		for _, v := range bv {
+			if key, _ := json.Marshal(v); !have[string(key)] {
+				av = append(av, v)
+			}
+		}
+		return av
+	}
+	return a
+}
+
+func readJSONValue(path string) (interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+func appendTo(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func copyFile(src, dst string) error {
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if err := appendTo(out, src); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// copyDir copies the files of src into dst.
+func copyDir(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if err := copyFile(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
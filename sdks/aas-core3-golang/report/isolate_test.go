@@ -0,0 +1,68 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMergeIsolated(t *testing.T) {
+	root := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first, second := filepath.Join(root, "BenchmarkDeserialize"), filepath.Join(root, "BenchmarkSerialize")
+	write(filepath.Join(first, BenchRawFile), "{\"Output\":\"a\"}\n")
+	write(filepath.Join(second, BenchRawFile), "{\"Output\":\"b\"}\n")
+	write(filepath.Join(first, MemoryStatsFile), `{"before": {"num_gc": 1}, "groups": {"deserialize": {"num_gc": 4}}}`)
+	write(filepath.Join(second, MemoryStatsFile), `{"before": {"num_gc": 2}, "groups": {"serialize": {"num_gc": 3}}}`)
+	write(filepath.Join(first, SkippedFile), `{"entries": [{"operation": "deserialize", "dataset": "wide"}]}`)
+	write(filepath.Join(second, SkippedFile), `{"entries": [{"operation": "serialize", "dataset": "wide"}]}`)
+	write(filepath.Join(first, EnergyFile), `{"domains": ["package-0"], "groups": {}}`)
+	write(filepath.Join(second, EnergyFile), `{"domains": ["package-0"], "groups": {}}`)
+	write(filepath.Join(first, OperationsFile), `{"operations": [{"id": "deserialize"}]}`)
+	write(filepath.Join(second, OperationsFile), `{"operations": [{"id": "deserialize"}]}`)
+	write(filepath.Join(second, PartialDir, "serialize.json"), `{}`)
+	write(filepath.Join(second, "serialize.heap.pprof"), "profile")
+
+	dir := filepath.Join(root, "out")
+	if err := MergeIsolated(dir, []string{first, second}); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(filepath.Join(dir, BenchRawFile))
+	if err != nil || string(raw) != "{\"Output\":\"a\"}\n{\"Output\":\"b\"}\n" {
+		t.Errorf("bench_raw.json = %q, %v", raw, err)
+	}
+	stats, err := LoadMemoryStats(filepath.Join(dir, MemoryStatsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Before.NumGC != 1 || stats.Groups["deserialize"].NumGC != 4 || stats.Groups["serialize"].NumGC != 3 {
+		t.Errorf("memory stats = %+v, want the first process's before and both groups", stats)
+	}
+	for file, want := range map[string]interface{}{
+		SkippedFile: map[string]interface{}{"entries": []interface{}{
+			map[string]interface{}{"operation": "deserialize", "dataset": "wide"},
+			map[string]interface{}{"operation": "serialize", "dataset": "wide"},
+		}},
+		EnergyFile:     map[string]interface{}{"domains": []interface{}{"package-0"}, "groups": map[string]interface{}{}},
+		OperationsFile: map[string]interface{}{"operations": []interface{}{map[string]interface{}{"id": "deserialize"}}},
+	} {
+		got, err := readJSONValue(filepath.Join(dir, file))
+		if err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %v, %v; want %v", file, got, err, want)
+		}
+	}
+	for _, name := range []string{filepath.Join(PartialDir, "serialize.json"), "serialize.heap.pprof"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("%s not copied: %v", name, err)
+		}
+	}
+}
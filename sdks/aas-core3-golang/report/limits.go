@@ -0,0 +1,142 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Report sections Limits can cap. Each is a list that grows with the run
+// rather than with the number of operations measured.
+const (
+	// SectionAllocationHotspots caps the sites kept per operation.
+	SectionAllocationHotspots = "allocation_hotspots"
+	// SectionEnvironmentEvents caps the events kept per operation entry.
+	SectionEnvironmentEvents = "environment_events"
+	// SectionParseDiagnostics caps each sample list of parse_diagnostics;
+	// the counts are always kept.
+	SectionParseDiagnostics = "parse_diagnostics"
+	// SectionDatasetsManifest caps the fingerprinted dataset files.
+	SectionDatasetsManifest = "datasets_manifest"
+)
+
+// Sections lists the section names Limits accepts.
+var Sections = []string{
+	SectionAllocationHotspots,
+	SectionDatasetsManifest,
+	SectionEnvironmentEvents,
+	SectionParseDiagnostics,
+}
+
+// Limits caps how many entries each list section of a report keeps, so
+// report.json stays small enough for the dashboard. A negative cap keeps
+// every entry and zero drops the section; sections not in the map are
+// kept whole.
+type Limits map[string]int
+
+// DefaultLimits keeps every section the dashboard renders in full and
+// trims the ones that only help diagnose a run.
+func DefaultLimits() Limits {
+	return Limits{
+		SectionAllocationHotspots: 10,
+		SectionEnvironmentEvents:  20,
+		SectionParseDiagnostics:   20,
+		SectionDatasetsManifest:   -1,
+	}
+}
+
+// SummaryLimits drops every capped section, leaving the measurements.
+func SummaryLimits() Limits {
+	l := Limits{}
+	for _, s := range Sections {
+		l[s] = 0
+	}
+	return l
+}
+
+// Set caps section at n, rejecting unknown sections.
+func (l Limits) Set(section string, n int) error {
+	for _, s := range Sections {
+		if s == section {
+			l[section] = n
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown report section %q (want one of %s)", section, strings.Join(Sections, ", "))
+}
+
+// Truncation marks a list Limits cut short. Path locates the list within
+// the section: the operation for allocation_hotspots, dataset/operation
+// for environment_events and the list name for parse_diagnostics.
+type Truncation struct {
+	Section string `json:"section"`
+	Path    string `json:"path,omitempty"`
+	Kept    int    `json:"kept"`
+	Total   int    `json:"total"`
+}
+
+// Apply trims r in place and records a Truncation for every list it cut,
+// in r.Truncated as well as in the returned slice.
+func (l Limits) Apply(r *Report) []Truncation {
+	var cut []Truncation
+	keep := func(section, path string, total int) int {
+		n, ok := l[section]
+		if !ok || n < 0 || total <= n {
+			return total
+		}
+		cut = append(cut, Truncation{Section: section, Path: path, Kept: n, Total: total})
+		return n
+	}
+
+	// Walk maps in key order so the markers are deterministic.
+	ops := make([]string, 0, len(r.AllocationHotspots))
+	for op := range r.AllocationHotspots {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+	for _, op := range ops {
+		sites := r.AllocationHotspots[op]
+		r.AllocationHotspots[op] = sites[:keep(SectionAllocationHotspots, op, len(sites))]
+		if len(r.AllocationHotspots[op]) == 0 {
+			delete(r.AllocationHotspots, op)
+		}
+	}
+	if len(r.AllocationHotspots) == 0 {
+		r.AllocationHotspots = nil
+	}
+
+	var eventKeys []string
+	for ds, entry := range r.Datasets {
+		for op, o := range entry.Operations {
+			if len(o.EnvironmentEvents) > 0 {
+				eventKeys = append(eventKeys, ds+"/"+op)
+			}
+		}
+	}
+	sort.Strings(eventKeys)
+	for _, key := range eventKeys {
+		ds, op, _ := strings.Cut(key, "/")
+		entry := r.Datasets[ds]
+		o := entry.Operations[op]
+		o.EnvironmentEvents = o.EnvironmentEvents[:keep(SectionEnvironmentEvents, key, len(o.EnvironmentEvents))]
+		if len(o.EnvironmentEvents) == 0 {
+			o.EnvironmentEvents = nil
+		}
+		entry.Operations[op] = o
+	}
+
+	if d := r.ParseDiagnostics; d != nil {
+		d.Skipped = d.Skipped[:keep(SectionParseDiagnostics, "skipped", len(d.Skipped))]
+		d.UnmatchedBenchmarkLines = d.UnmatchedBenchmarkLines[:keep(SectionParseDiagnostics, "unmatched_benchmark_lines", len(d.UnmatchedBenchmarkLines))]
+		d.FailedBenchmarks = d.FailedBenchmarks[:keep(SectionParseDiagnostics, "failed_benchmarks", len(d.FailedBenchmarks))]
+	}
+
+	m := &r.Metadata
+	m.DatasetsManifest = m.DatasetsManifest[:keep(SectionDatasetsManifest, "", len(m.DatasetsManifest))]
+	if len(m.DatasetsManifest) == 0 {
+		m.DatasetsManifest = nil
+	}
+
+	r.Truncated = append(r.Truncated, cut...)
+	return cut
+}
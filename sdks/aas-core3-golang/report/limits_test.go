@@ -0,0 +1,46 @@
+package report
+
+import "testing"
+
+func TestLimitsApplyTruncatesWithMarkers(t *testing.T) {
+	sites := make([]AllocationSite, 15)
+	r := &Report{
+		Datasets: map[string]DatasetEntry{"wide": {Operations: map[string]OperationEntry{
+			"validate": {EnvironmentEvents: make([]EventAnnotation, 3)},
+			"traverse": {},
+		}}},
+		AllocationHotspots: map[string][]AllocationSite{"validate": sites, "traverse": sites[:2]},
+		ParseDiagnostics:   &ParseDiagnostics{LinesSkipped: 30, Skipped: make([]SkippedLine, 30)},
+		Metadata:           Metadata{DatasetsManifest: make([]DatasetFingerprint, 3)},
+	}
+
+	limits := DefaultLimits()
+	if err := limits.Set(SectionEnvironmentEvents, 1); err != nil {
+		t.Fatal(err)
+	}
+	cut := limits.Apply(r)
+	want := []Truncation{
+		{Section: SectionAllocationHotspots, Path: "validate", Kept: 10, Total: 15},
+		{Section: SectionEnvironmentEvents, Path: "wide/validate", Kept: 1, Total: 3},
+		{Section: SectionParseDiagnostics, Path: "skipped", Kept: 20, Total: 30},
+	}
+	if len(cut) != len(want) {
+		t.Fatalf("truncations = %+v, want %+v", cut, want)
+	}
+	for i := range want {
+		if cut[i] != want[i] {
+			t.Errorf("truncation %d = %+v, want %+v", i, cut[i], want[i])
+		}
+	}
+	if len(r.Truncated) != 3 || len(r.AllocationHotspots["traverse"]) != 2 || len(r.Metadata.DatasetsManifest) != 3 || r.ParseDiagnostics.LinesSkipped != 30 {
+		t.Errorf("report after limits = %+v", r)
+	}
+
+	SummaryLimits().Apply(r)
+	if r.AllocationHotspots != nil || r.Metadata.DatasetsManifest != nil || r.Datasets["wide"].Operations["validate"].EnvironmentEvents != nil {
+		t.Errorf("summary kept detail sections: %+v", r)
+	}
+	if err := limits.Set("samples", 1); err == nil {
+		t.Error("expected an error for an unknown section")
+	}
+}
@@ -0,0 +1,341 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Metadata describes how and when a report was produced. The first five
+// fields are written by every adapter; the rest are optional.
+type Metadata struct {
+	Language          string `json:"language"`
+	RuntimeVersion    string `json:"runtime_version"`
+	SDKPackageVersion string `json:"sdk_package_version"`
+	BenchmarkHarness  string `json:"benchmark_harness"`
+	Timestamp         string `json:"timestamp"`
+
+	// SDKModule is the module path SDKPackageVersion refers to.
+	SDKModule string `json:"sdk_module,omitempty"`
+	// ObservatoryGitSHA and ObservatoryGitDirty identify the observatory
+	// commit the benchmarks were built from.
+	ObservatoryGitSHA   string `json:"observatory_git_sha,omitempty"`
+	ObservatoryGitDirty *bool  `json:"observatory_git_dirty,omitempty"`
+	// BackfilledAt is set when the report was regenerated from archived
+	// raw output by aasbench backfill.
+	BackfilledAt string `json:"backfilled_at,omitempty"`
+	// GOMAXPROCS is the GOMAXPROCS the benchmarks ran with, and
+	// CPUAffinity the CPU list the process was pinned to, if it was.
+	GOMAXPROCS  int    `json:"gomaxprocs,omitempty"`
+	CPUAffinity string `json:"cpu_affinity,omitempty"`
+	// Platform is the "os/arch" the benchmarks ran on, in Go's GOOS/GOARCH
+	// names ("linux/arm64"). Reports without it were measured on
+	// DefaultPlatform.
+	Platform string `json:"platform,omitempty"`
+	// DatasetsManifest fingerprints every dataset file used by the run.
+	DatasetsManifest []DatasetFingerprint `json:"datasets_manifest,omitempty"`
+
+	// migrationNotes records what UnmarshalJSON had to convert or drop.
+	migrationNotes []string
+}
+
+// MigrationNotes lists the conversions made when m was read from an older
+// report; it is empty for reports that already match the schema.
+func (m Metadata) MigrationNotes() []string {
+	return m.migrationNotes
+}
+
+// metadataKind is the JSON type a metadata key must have.
+type metadataKind int
+
+const (
+	metaString metadataKind = iota
+	metaTimestamp
+	metaBool
+	metaInt
+	metaPlatform
+	metaFingerprints
+)
+
+// DefaultPlatform is the platform of the CI runners, assumed for reports
+// that do not name theirs.
+const DefaultPlatform = "linux/amd64"
+
+var platformPattern = regexp.MustCompile(`^[a-z0-9]+/[a-z0-9]+$`)
+
+var sha256Pattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// HostPlatform returns the platform this process runs on.
+func HostPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// PlatformOf returns m's platform, or DefaultPlatform when it has none.
+func (m Metadata) PlatformOf() string {
+	if m.Platform == "" {
+		return DefaultPlatform
+	}
+	return m.Platform
+}
+
+// metadataFields is the whitelist of metadata keys. It must list every
+// field of Metadata; keys outside it are rejected by Validate and dropped by
+// MigrateMetadata.
+var metadataFields = map[string]struct {
+	kind     metadataKind
+	required bool
+}{
+	"language":              {metaString, true},
+	"runtime_version":       {metaString, true},
+	"sdk_package_version":   {metaString, true},
+	"benchmark_harness":     {metaString, true},
+	"timestamp":             {metaTimestamp, true},
+	"sdk_module":            {metaString, false},
+	"observatory_git_sha":   {metaString, false},
+	"observatory_git_dirty": {metaBool, false},
+	"backfilled_at":         {metaTimestamp, false},
+	"gomaxprocs":            {metaInt, false},
+	"cpu_affinity":          {metaString, false},
+	"platform":              {metaPlatform, false},
+	"datasets_manifest":     {metaFingerprints, false},
+}
+
+// validateMetadata checks a raw metadata object against metadataFields and
+// returns one message per problem.
+func validateMetadata(raw interface{}) []string {
+	meta, ok := raw.(map[string]interface{})
+	if !ok {
+		return []string{"metadata must be an object"}
+	}
+	var errors []string
+	for _, key := range sortedMetadataKeys() {
+		if _, present := meta[key]; !present && metadataFields[key].required {
+			errors = append(errors, fmt.Sprintf("metadata missing required field %q", key))
+		}
+	}
+	for _, key := range sortedKeys(meta) {
+		field, known := metadataFields[key]
+		if !known {
+			errors = append(errors, fmt.Sprintf("metadata has unknown field %q", key))
+			continue
+		}
+		if err := checkMetadataValue(field.kind, meta[key]); err != nil {
+			errors = append(errors, fmt.Sprintf("metadata field %q %v", key, err))
+		}
+	}
+	return errors
+}
+
+func checkMetadataValue(kind metadataKind, v interface{}) error {
+	switch kind {
+	case metaBool:
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("must be a boolean, got %#v", v)
+		}
+	case metaInt:
+		if _, ok := positiveInt(v); !ok {
+			return fmt.Errorf("must be a positive integer, got %#v", v)
+		}
+	case metaPlatform:
+		s, ok := v.(string)
+		if !ok || !platformPattern.MatchString(s) {
+			return fmt.Errorf("must be an os/arch string such as %q, got %#v", DefaultPlatform, v)
+		}
+	case metaFingerprints:
+		return checkFingerprints(v)
+	case metaTimestamp:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("must be an RFC 3339 timestamp string, got %#v", v)
+		}
+		if _, err := time.Parse(time.RFC3339, s); err != nil {
+			return fmt.Errorf("must be an RFC 3339 timestamp: %q", s)
+		}
+	default:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("must be a string, got %#v", v)
+		}
+		if strings.TrimSpace(s) == "" {
+			return fmt.Errorf("must not be empty")
+		}
+	}
+	return nil
+}
+
+// checkFingerprints checks a raw datasets_manifest: a list of
+// DatasetFingerprint objects, each naming its dataset, file and format with
+// a hex SHA-256 and a byte size.
+func checkFingerprints(v interface{}) error {
+	list, ok := v.([]interface{})
+	if !ok {
+		return fmt.Errorf("must be a list of dataset fingerprints, got %#v", v)
+	}
+	for i, item := range list {
+		fp, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("entry %d must be an object, got %#v", i, item)
+		}
+		for _, key := range []string{"dataset", "file", "format"} {
+			if _, ok := fp[key].(string); !ok {
+				return fmt.Errorf("entry %d must have a string %q, got %#v", i, key, fp[key])
+			}
+		}
+		if s, _ := fp["sha256"].(string); !sha256Pattern.MatchString(s) {
+			return fmt.Errorf("entry %d must have a hex \"sha256\", got %#v", i, fp["sha256"])
+		}
+		if size, ok := fp["size_bytes"].(float64); !ok || size < 0 || size != float64(int64(size)) {
+			return fmt.Errorf("entry %d must have a non-negative integer \"size_bytes\", got %#v", i, fp["size_bytes"])
+		}
+	}
+	return nil
+}
+
+// positiveInt returns v as an int if it is a JSON number holding a
+// positive integer.
+func positiveInt(v interface{}) (int, bool) {
+	f, ok := v.(float64)
+	if !ok || f < 1 || f != float64(int(f)) {
+		return 0, false
+	}
+	return int(f), true
+}
+
+func sortedMetadataKeys() []string {
+	keys := make([]string, 0, len(metadataFields))
+	for k := range metadataFields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Set overrides one metadata field by its JSON key. The key must be in the
+// whitelist and the value must parse as the field's kind, so an override
+// cannot produce a report that Validate rejects.
+func (m *Metadata) Set(key, value string) error {
+	field, known := metadataFields[key]
+	if !known {
+		return fmt.Errorf("unknown metadata key %q (known: %s)", key, strings.Join(sortedMetadataKeys(), ", "))
+	}
+	if field.kind == metaFingerprints {
+		return fmt.Errorf("metadata field %q is recorded from the dataset files and cannot be set", key)
+	}
+	if field.kind == metaBool {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("metadata field %q must be a boolean, got %q", key, value)
+		}
+		m.ObservatoryGitDirty = &b
+		return nil
+	}
+	if field.kind == metaInt {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("metadata field %q must be a positive integer, got %q", key, value)
+		}
+		m.GOMAXPROCS = n
+		return nil
+	}
+	if err := checkMetadataValue(field.kind, value); err != nil {
+		return fmt.Errorf("metadata field %q %v", key, err)
+	}
+	*m.stringFields()[key] = value
+	return nil
+}
+
+// stringFields maps the JSON keys of the string-valued fields to m's fields.
+func (m *Metadata) stringFields() map[string]*string {
+	return map[string]*string{
+		"language":            &m.Language,
+		"runtime_version":     &m.RuntimeVersion,
+		"sdk_package_version": &m.SDKPackageVersion,
+		"benchmark_harness":   &m.BenchmarkHarness,
+		"timestamp":           &m.Timestamp,
+		"sdk_module":          &m.SDKModule,
+		"observatory_git_sha": &m.ObservatoryGitSHA,
+		"backfilled_at":       &m.BackfilledAt,
+		"cpu_affinity":        &m.CPUAffinity,
+		"platform":            &m.Platform,
+	}
+}
+
+// UnmarshalJSON reads metadata written by any schema version 2 emitter,
+// including the free-form string maps of older reports; see MigrateMetadata.
+func (m *Metadata) UnmarshalJSON(data []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	migrated, notes := MigrateMetadata(raw)
+	*m = migrated
+	m.migrationNotes = notes
+	return nil
+}
+
+// MigrateMetadata converts a metadata object from an older report to the
+// typed form. Booleans and integers stored as strings ("true", "8") are
+// parsed; unknown keys
+// and unparseable values are dropped and reported as notes.
+func MigrateMetadata(raw map[string]interface{}) (Metadata, []string) {
+	var m Metadata
+	var notes []string
+	strs := m.stringFields()
+	for _, key := range sortedKeys(raw) {
+		v := raw[key]
+		if dst, ok := strs[key]; ok {
+			if s, ok := v.(string); ok {
+				*dst = s
+			} else {
+				*dst = fmt.Sprint(v)
+				notes = append(notes, fmt.Sprintf("metadata %q converted to string", key))
+			}
+			continue
+		}
+		if key == "observatory_git_dirty" {
+			switch b := v.(type) {
+			case bool:
+				m.ObservatoryGitDirty = &b
+			case string:
+				if parsed, err := strconv.ParseBool(b); err == nil {
+					m.ObservatoryGitDirty = &parsed
+					notes = append(notes, fmt.Sprintf("metadata %q converted to boolean", key))
+				} else {
+					notes = append(notes, fmt.Sprintf("metadata %q dropped: not a boolean: %q", key, b))
+				}
+			default:
+				notes = append(notes, fmt.Sprintf("metadata %q dropped: not a boolean: %v", key, v))
+			}
+			continue
+		}
+		if key == "gomaxprocs" {
+			if n, ok := positiveInt(v); ok {
+				m.GOMAXPROCS = n
+			} else if n, err := strconv.Atoi(fmt.Sprint(v)); err == nil && n > 0 {
+				m.GOMAXPROCS = n
+				notes = append(notes, fmt.Sprintf("metadata %q converted to integer", key))
+			} else {
+				notes = append(notes, fmt.Sprintf("metadata %q dropped: not a positive integer: %v", key, v))
+			}
+			continue
+		}
+		if key == "datasets_manifest" {
+			if err := checkFingerprints(v); err != nil {
+				notes = append(notes, fmt.Sprintf("metadata %q dropped: %v", key, err))
+				continue
+			}
+			data, _ := json.Marshal(v)
+			if err := json.Unmarshal(data, &m.DatasetsManifest); err != nil {
+				notes = append(notes, fmt.Sprintf("metadata %q dropped: %v", key, err))
+			}
+			continue
+		}
+		notes = append(notes, fmt.Sprintf("metadata %q dropped: not a known field", key))
+	}
+	return m, notes
+}
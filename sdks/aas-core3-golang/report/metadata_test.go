@@ -0,0 +1,95 @@
+package report
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestValidateMetadata(t *testing.T) {
+	raw := map[string]interface{}{
+		"language":              "go",
+		"runtime_version":       "go1.22.5",
+		"benchmark_harness":     "testing.B",
+		"timestamp":             "yesterday",
+		"observatory_git_dirty": "true",
+		"gomaxprocs":            "8",
+		"host":                  "ci-7",
+	}
+	got := strings.Join(validateMetadata(raw), "\n")
+	for _, want := range []string{
+		`missing required field "sdk_package_version"`,
+		`"timestamp" must be an RFC 3339 timestamp`,
+		`"observatory_git_dirty" must be a boolean`,
+		`"gomaxprocs" must be a positive integer`,
+		`unknown field "host"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("validateMetadata errors missing %q:\n%s", want, got)
+		}
+	}
+}
+
+func TestMetadataMigratesOlderReports(t *testing.T) {
+	var m Metadata
+	old := `{"language":"go","sdk_package_version":"latest","observatory_git_dirty":"false","gomaxprocs":"4","host":"ci-7"}`
+	if err := json.Unmarshal([]byte(old), &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.ObservatoryGitDirty == nil || *m.ObservatoryGitDirty {
+		t.Errorf("observatory_git_dirty = %v, want false", m.ObservatoryGitDirty)
+	}
+	if m.SDKPackageVersion != "latest" {
+		t.Errorf("sdk_package_version = %q", m.SDKPackageVersion)
+	}
+	if m.GOMAXPROCS != 4 {
+		t.Errorf("gomaxprocs = %d, want 4", m.GOMAXPROCS)
+	}
+	if notes := m.MigrationNotes(); len(notes) != 3 {
+		t.Errorf("MigrationNotes = %q, want two conversions and a drop", notes)
+	}
+}
+
+func TestReportMovesTopLevelDatasetsManifest(t *testing.T) {
+	sha := strings.Repeat("0a", 32)
+	var r Report
+	old := `{"metadata":{"language":"go"},"datasets_manifest":[{"dataset":"wide","file":"wide.json","format":"json","sha256":"` + sha + `","size_bytes":2048,"element_count":null}]}`
+	if err := json.Unmarshal([]byte(old), &r); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Metadata.DatasetsManifest; len(got) != 1 || got[0].SHA256 != sha || got[0].SizeBytes != 2048 {
+		t.Errorf("metadata datasets_manifest = %+v", got)
+	}
+	if notes := r.Metadata.MigrationNotes(); len(notes) != 1 {
+		t.Errorf("MigrationNotes = %q, want the move", notes)
+	}
+
+	bad := map[string]interface{}{"datasets_manifest": []interface{}{map[string]interface{}{"dataset": "wide", "file": "wide.json", "format": "json", "sha256": "abc", "size_bytes": 1.0}}}
+	if got := strings.Join(validateMetadata(bad), "\n"); !strings.Contains(got, `"datasets_manifest" entry 0 must have a hex "sha256"`) {
+		t.Errorf("validateMetadata errors = %s", got)
+	}
+}
+
+func TestMetadataSetRespectsWhitelist(t *testing.T) {
+	var m Metadata
+	if err := m.Set("benchmark_harness", "gobench wrapper"); err != nil || m.BenchmarkHarness != "gobench wrapper" {
+		t.Errorf("Set benchmark_harness: %v, %q", err, m.BenchmarkHarness)
+	}
+	if err := m.Set("observatory_git_dirty", "true"); err != nil || m.ObservatoryGitDirty == nil || !*m.ObservatoryGitDirty {
+		t.Errorf("Set observatory_git_dirty: %v, %v", err, m.ObservatoryGitDirty)
+	}
+	if err := m.Set("gomaxprocs", "8"); err != nil || m.GOMAXPROCS != 8 {
+		t.Errorf("Set gomaxprocs: %v, %d", err, m.GOMAXPROCS)
+	}
+	for key, value := range map[string]string{
+		"host":                  "ci-7",
+		"gomaxprocs":            "0",
+		"timestamp":             "yesterday",
+		"observatory_git_dirty": "maybe",
+		"language":              " ",
+	} {
+		if err := m.Set(key, value); err == nil {
+			t.Errorf("Set(%q, %q) accepted", key, value)
+		}
+	}
+}
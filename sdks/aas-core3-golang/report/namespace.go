@@ -0,0 +1,95 @@
+package report
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// NamespaceSeparator splits an extension operation ID into the namespace
+// owned by an adapter and the operation name ("vendorx:transform").
+const NamespaceSeparator = ":"
+
+// CanonicalOperations are the un-namespaced operation IDs defined by the
+// observatory. Any other operation must carry an adapter namespace so that
+// community extensions cannot collide with them.
+var CanonicalOperations = map[string]bool{
+	"deserialize":              true,
+	"validate":                 true,
+	"traverse":                 true,
+	"update":                   true,
+	"serialize":                true,
+	"deserialize_xml":          true,
+	"serialize_xml":            true,
+	"aasx_extract":             true,
+	"aasx_repackage":           true,
+	"diff":                     true,
+	"patch":                    true,
+	"index_build":              true,
+	"index_lookup":             true,
+	"deserialize_pooled":       true,
+	"validate_first_error":     true,
+	"validate_collect_all":     true,
+	"enum_from_string":         true,
+	"enum_to_string":           true,
+	"value_parse":              true,
+	"iri_match":                true,
+	"lang_string_lookup":       true,
+	"merge":                    true,
+	"hash":                     true,
+	"equals":                   true,
+	"deserialize_invalid":      true,
+	"deserialize_file_warm":    true,
+	"deserialize_file_cold":    true,
+	"find_by_semantic_id":      true,
+	"find_by_semantic_id_miss": true,
+	"serialize_stream":         true,
+	"deserialize_xml_nsheavy":  true,
+	"startup":                  true,
+	"filter":                   true,
+	"canonicalize":             true,
+}
+
+// reservedNamespaces cannot be claimed by extensions because they would
+// read as official observatory operations.
+var reservedNamespaces = map[string]bool{
+	"aas":         true,
+	"core":        true,
+	"observatory": true,
+}
+
+var (
+	namespacePattern     = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+	operationNamePattern = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+)
+
+// SplitOperationID returns the namespace and operation name of id. The
+// namespace is empty for canonical operations.
+func SplitOperationID(id string) (namespace, name string) {
+	if i := strings.Index(id, NamespaceSeparator); i >= 0 {
+		return id[:i], id[i+len(NamespaceSeparator):]
+	}
+	return "", id
+}
+
+// CheckOperationID reports why a canonical-form operation ID is not allowed
+// in a report, or nil when it is.
+func CheckOperationID(id string) error {
+	namespace, name := SplitOperationID(id)
+	if namespace == "" && !strings.Contains(id, NamespaceSeparator) {
+		if !CanonicalOperations[id] {
+			return fmt.Errorf("operation %q is not canonical; extension operations must be namespaced (e.g. %q)",
+				id, "vendorx"+NamespaceSeparator+id)
+		}
+		return nil
+	}
+	switch {
+	case !namespacePattern.MatchString(namespace):
+		return fmt.Errorf("operation %q has invalid namespace %q", id, namespace)
+	case reservedNamespaces[namespace]:
+		return fmt.Errorf("operation %q uses reserved namespace %q", id, namespace)
+	case !operationNamePattern.MatchString(name):
+		return fmt.Errorf("operation %q has invalid name %q (want snake_case)", id, name)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package report
+
+import "testing"
+
+func TestCheckOperationID(t *testing.T) {
+	cases := []struct {
+		id string
+		ok bool
+	}{
+		{"deserialize", true},
+		{"aasx_extract", true},
+		{"vendorx:transform", true},
+		{"vendor-x:bulk_update", true},
+		{"transform", false},         // custom operation without namespace
+		{"core:deserialize", false},  // reserved namespace
+		{":transform", false},        // empty namespace
+		{"vendorx:", false},          // empty name
+		{"vendorx:a:b", false},       // nested separator
+		{"VendorX:transform", false}, // not normalized
+	}
+	for _, c := range cases {
+		err := CheckOperationID(c.id)
+		if (err == nil) != c.ok {
+			t.Errorf("CheckOperationID(%q) = %v, want ok=%v", c.id, err, c.ok)
+		}
+	}
+}
+
+func TestNormalizeOperationIDKeepsNamespace(t *testing.T) {
+	if got := NormalizeOperationID("VendorX:bulkUpdate"); got != "vendorx:bulk_update" {
+		t.Fatalf("NormalizeOperationID = %q, want %q", got, "vendorx:bulk_update")
+	}
+}
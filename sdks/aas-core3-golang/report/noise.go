@@ -0,0 +1,61 @@
+package report
+
+import "math"
+
+// DefaultNoiseThresholdPct is the largest drift of the control benchmark
+// across a run for which the environment still counts as quiet.
+const DefaultNoiseThresholdPct = 10.0
+
+// Noise levels for Report.EnvironmentNoise.
+const (
+	NoiseLow  = "low"
+	NoiseHigh = "high"
+)
+
+// ControlSummary condenses the control benchmark samples of a run.
+type ControlSummary struct {
+	Workload     string  `json:"workload"`
+	Samples      int     `json:"samples"`
+	MedianNs     int64   `json:"median_ns"`
+	MinNs        int64   `json:"min_ns"`
+	MaxNs        int64   `json:"max_ns"`
+	DriftPct     float64 `json:"drift_pct"`
+	ThresholdPct float64 `json:"threshold_pct"`
+	// SlowestBefore names the sub-benchmark that followed the slowest
+	// sample, a hint where in the run the disturbance was.
+	SlowestBefore string `json:"slowest_before"`
+}
+
+// ComputeNoise summarizes the control samples and returns NoiseHigh when
+// their spread, relative to the median, exceeds thresholdPct. It returns
+// nil and "" with fewer than two samples.
+func ComputeNoise(control *Control, thresholdPct float64) (*ControlSummary, string) {
+	if control == nil || len(control.Samples) < 2 {
+		return nil, ""
+	}
+	ns := make([]float64, len(control.Samples))
+	slowest := control.Samples[0]
+	for i, sample := range control.Samples {
+		ns[i] = float64(sample.Ns)
+		if sample.Ns > slowest.Ns {
+			slowest = sample
+		}
+	}
+	_, median, _, minNs, maxNs := ComputeStats(ns)
+	s := &ControlSummary{
+		Workload:      control.Workload,
+		Samples:       len(control.Samples),
+		MedianNs:      int64(math.Round(median)),
+		MinNs:         int64(minNs),
+		MaxNs:         int64(maxNs),
+		ThresholdPct:  thresholdPct,
+		SlowestBefore: slowest.Before,
+	}
+	if median > 0 {
+		s.DriftPct = math.Round((maxNs-minNs)/median*10000) / 100
+	}
+	if s.DriftPct > thresholdPct {
+		return s, NoiseHigh
+	}
+	return s, NoiseLow
+}
@@ -0,0 +1,25 @@
+package report
+
+import "testing"
+
+func TestComputeNoise(t *testing.T) {
+	control := &Control{Workload: "xorshift64", Samples: []ControlSample{
+		{Before: "deserialize/wide", Ns: 2000000},
+		{Before: "validate/wide", Ns: 2050000},
+		{Before: "serialize/wide", Ns: 2600000},
+	}}
+	s, level := ComputeNoise(control, DefaultNoiseThresholdPct)
+	if level != NoiseHigh {
+		t.Errorf("level = %q, want %q", level, NoiseHigh)
+	}
+	if s.DriftPct != 29.27 || s.MedianNs != 2050000 || s.SlowestBefore != "serialize/wide" {
+		t.Errorf("summary = %+v", s)
+	}
+
+	if _, level := ComputeNoise(control, 50); level != NoiseLow {
+		t.Errorf("level at 50%% threshold = %q, want %q", level, NoiseLow)
+	}
+	if s, level := ComputeNoise(&Control{Samples: control.Samples[:1]}, 10); s != nil || level != "" {
+		t.Errorf("single sample gave %+v %q, want no verdict", s, level)
+	}
+}
@@ -0,0 +1,260 @@
+package report
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// OperationsFile is the operations manifest a harness run writes.
+const OperationsFile = "operations.json"
+
+// Operation tracks an operation can declare in a manifest. The micro,
+// scaling, i18n, extension and capability tracks follow from the dataset
+// or the namespace instead.
+const (
+	TrackCore       = "core"
+	TrackValidation = "validation"
+	TrackXML        = "xml"
+	TrackAASX       = "aasx"
+)
+
+var declarableTracks = map[string]bool{TrackCore: true, TrackValidation: true, TrackXML: true, TrackAASX: true}
+
+// formatTracks hold an operation whatever dataset it ran on.
+var formatTracks = []string{TrackXML, TrackAASX}
+
+// defaultOperationsJSON declares the tracks of the canonical operations
+// that belong to one.
+//
+//go:embed operations.json
+var defaultOperationsJSON []byte
+
+// ManifestOperation is one operation of an operations manifest.
+type ManifestOperation struct {
+	ID string `json:"id"`
+	// Benchmark is the Go benchmark name without the "Benchmark" prefix,
+	// resolved to ID like an alias.
+	Benchmark string   `json:"benchmark,omitempty"`
+	Tracks    []string `json:"tracks,omitempty"`
+	// Needs are the operations whose work this one's setup builds on
+	// (serialize needs deserialize); selecting it selects them too.
+	Needs []string `json:"needs,omitempty"`
+}
+
+// operationsFile is the schema of operations.json.
+type operationsFile struct {
+	Operations []ManifestOperation `json:"operations"`
+}
+
+// OperationManifest maps operation IDs to their manifest entries.
+// Operations without an entry have no declared track.
+type OperationManifest map[string]ManifestOperation
+
+var defaultOperations = DefaultOperations()
+
+// DefaultOperations returns a copy of the embedded manifest.
+func DefaultOperations() OperationManifest {
+	m, err := parseOperations(defaultOperationsJSON, "embedded operations.json")
+	if err == nil {
+		err = m.checkNeeds("embedded operations.json")
+	}
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// LoadOperations returns the default manifest with the entries of the
+// manifest at path added or replaced.
+func LoadOperations(path string) (OperationManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	run, err := parseOperations(data, path)
+	if err != nil {
+		return nil, err
+	}
+	m := DefaultOperations()
+	for id, op := range run {
+		m[id] = op
+	}
+	if err := m.checkNeeds(path); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// parseOperations decodes and checks a manifest: every ID must be allowed
+// in a report, listed once, and declare known tracks only.
+func parseOperations(data []byte, source string) (OperationManifest, error) {
+	var f operationsFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", source, err)
+	}
+	m := make(OperationManifest, len(f.Operations))
+	var problems []string
+	for _, op := range f.Operations {
+		if err := CheckOperationID(op.ID); err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		if _, dup := m[op.ID]; dup {
+			problems = append(problems, fmt.Sprintf("operation %q listed twice", op.ID))
+			continue
+		}
+		for _, track := range op.Tracks {
+			if !declarableTracks[track] {
+				problems = append(problems, fmt.Sprintf("operation %q declares unknown track %q", op.ID, track))
+			}
+		}
+		m[op.ID] = op
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("%s: %s", source, strings.Join(problems, "; "))
+	}
+	return m, nil
+}
+
+// checkNeeds fails when an operation needs one the manifest does not list
+// or the needs form a cycle.
+func (m OperationManifest) checkNeeds(source string) error {
+	var problems []string
+	// state is 1 while an operation's needs are walked, 2 once they were.
+	state := make(map[string]int, len(m))
+	var visit func(id string, path []string)
+	visit = func(id string, path []string) {
+		switch state[id] {
+		case 1:
+			problems = append(problems, "needs form a cycle: "+strings.Join(append(path, id), " -> "))
+			return
+		case 2:
+			return
+		}
+		state[id] = 1
+		for _, need := range m[id].Needs {
+			if _, ok := m[need]; !ok {
+				problems = append(problems, fmt.Sprintf("operation %q needs unknown operation %q", id, need))
+				continue
+			}
+			visit(need, append(path, id))
+		}
+		state[id] = 2
+	}
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		visit(id, nil)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%s: %s", source, strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Select resolves operation IDs and declarable track names (core,
+// validation, xml, aasx) to the operations to run, adding everything they
+// need. Operations the manifest does not list select only themselves.
+func (m OperationManifest) Select(terms []string) (map[string]bool, error) {
+	selected := make(map[string]bool)
+	var add func(id string)
+	add = func(id string) {
+		if selected[id] {
+			return
+		}
+		selected[id] = true
+		for _, need := range m[id].Needs {
+			add(need)
+		}
+	}
+	for _, term := range terms {
+		if declarableTracks[term] {
+			for id := range m.InTrack(term) {
+				add(id)
+			}
+			continue
+		}
+		if err := CheckOperationID(term); err != nil {
+			return nil, fmt.Errorf("not a track (core, validation, xml, aasx) or operation: %w", err)
+		}
+		add(term)
+	}
+	return selected, nil
+}
+
+// declares reports whether operationID declares track.
+func (m OperationManifest) declares(operationID, track string) bool {
+	for _, t := range m[operationID].Tracks {
+		if t == track {
+			return true
+		}
+	}
+	return false
+}
+
+// Track assigns the dashboard track for a dataset/operation pair.
+// Namespaced operations are extensions. A declared format track (xml,
+// aasx) holds whatever the dataset; otherwise micro_*, scale_* and i18n
+// datasets decide. Declared validation operations are validation on val_*
+// datasets, declared core operations core on the core datasets, and
+// everything else is capability.
+func (m OperationManifest) Track(dataset, operationID string) string {
+	if namespace, _ := SplitOperationID(operationID); namespace != "" {
+		return "extension"
+	}
+	for _, track := range formatTracks {
+		if m.declares(operationID, track) {
+			return track
+		}
+	}
+	switch {
+	case strings.HasPrefix(dataset, MicroDatasetPrefix):
+		return "micro"
+	case strings.HasPrefix(dataset, ScalingDatasetPrefix):
+		return "scaling"
+	case dataset == I18nDataset:
+		return "i18n"
+	case strings.HasPrefix(dataset, "val_") && m.declares(operationID, TrackValidation):
+		return TrackValidation
+	case CoreDatasets[dataset] && m.declares(operationID, TrackCore):
+		return TrackCore
+	}
+	return "capability"
+}
+
+// InTrack returns the operations that declare track.
+func (m OperationManifest) InTrack(track string) map[string]bool {
+	ops := make(map[string]bool)
+	for id := range m {
+		if m.declares(id, track) {
+			ops[id] = true
+		}
+	}
+	return ops
+}
+
+// addAliases adds the benchmark names of the manifest to t, keeping the
+// entries t already has.
+func (m OperationManifest) addAliases(t AliasTable) {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if m[id].Benchmark == "" {
+			continue
+		}
+		key := strings.ToLower(m[id].Benchmark)
+		if _, ok := t[key]; !ok {
+			t[key] = id
+		}
+	}
+}
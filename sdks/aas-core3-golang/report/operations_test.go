@@ -0,0 +1,79 @@
+package report
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRunManifestNamesExtensionBenchmarks(t *testing.T) {
+	dir := t.TempDir()
+	manifest := `{"operations": [
+		{"id": "deserialize", "benchmark": "Deserialize", "tracks": ["core"]},
+		{"id": "vendorx:transform", "benchmark": "VendorxTransform"}]}`
+	if err := os.WriteFile(filepath.Join(dir, OperationsFile), []byte(manifest), 0644); err != nil {
+		t.Fatal(err)
+	}
+	raw := writeRaw(t,
+		`{"Action":"output","Test":"BenchmarkVendorxTransform/wide","Output":"BenchmarkVendorxTransform/wide-8   \t 100\t  500 ns/op\n"}`,
+		`{"Action":"output","Test":"BenchmarkSerializeXml/wide","Output":"BenchmarkSerializeXml/wide-8   \t 100\t  700 ns/op\n"}`,
+	)
+	b := Bundle{BenchRaw: raw, Operations: filepath.Join(dir, OperationsFile)}
+	results, opts, err := b.Load(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rep := Build(results, opts)
+	ops := rep.Datasets["wide"].Operations
+	if op, ok := ops["vendorx:transform"]; !ok || op.OperationTrack != "extension" {
+		t.Errorf("vendorx:transform = %+v, want it resolved from the manifest", ops)
+	}
+	if op := ops["serialize_xml"]; op.OperationTrack != TrackXML {
+		t.Errorf("serialize_xml track = %q, want the embedded manifest's xml", op.OperationTrack)
+	}
+}
+
+func TestOperationsManifestRejectsUnknownTracks(t *testing.T) {
+	for _, manifest := range []string{
+		`{"operations": [{"id": "transform", "tracks": ["core"]}]}`,
+		`{"operations": [{"id": "vendorx:transform", "tracks": ["fast"]}]}`,
+		`{"operations": [{"id": "validate"}, {"id": "validate"}]}`,
+	} {
+		if _, err := parseOperations([]byte(manifest), "test"); err == nil {
+			t.Errorf("parseOperations(%s) succeeded", manifest)
+		}
+	}
+}
+
+func TestSelectAddsNeededOperations(t *testing.T) {
+	m := DefaultOperations()
+	selected, err := m.Select([]string{"serialize", "xml"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"serialize": true, "deserialize": true, "deserialize_xml": true, "serialize_xml": true, "deserialize_xml_nsheavy": true}
+	if !reflect.DeepEqual(selected, want) {
+		t.Errorf("Select = %v, want %v", selected, want)
+	}
+	if _, err := m.Select([]string{"serialise"}); err == nil {
+		t.Error("Select accepted an unknown un-namespaced operation")
+	}
+}
+
+func TestOperationsManifestRejectsBadNeeds(t *testing.T) {
+	for _, manifest := range []string{
+		`{"operations": [{"id": "vendorx:transform", "needs": ["vendorx:load"]}]}`,
+		`{"operations": [{"id": "vendorx:a", "needs": ["vendorx:b"]}, {"id": "vendorx:b", "needs": ["vendorx:a"]}]}`,
+	} {
+		path := filepath.Join(t.TempDir(), OperationsFile)
+		if err := os.WriteFile(path, []byte(manifest), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadOperations(path); err == nil {
+			t.Errorf("LoadOperations(%s) succeeded", manifest)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// OutputSize is the document a serializing operation wrote for one
+// dataset: its size, and its size compressed with gzip and zstd at their
+// default levels. SDKs differ in whitespace and field order, and where
+// AAS documents cross a network, payload size matters as much as the
+// time it took to write it.
+type OutputSize struct {
+	Bytes     int64 `json:"bytes"`
+	GzipBytes int64 `json:"gzip_bytes"`
+	ZstdBytes int64 `json:"zstd_bytes"`
+}
+
+// OutputSizes is the schema of the output_sizes.json file: the output
+// size of every serializing operation, by operation and then dataset.
+type OutputSizes map[string]map[string]OutputSize
+
+// LoadOutputSizes reads the output_sizes.json file written by the harness.
+func LoadOutputSizes(path string) (OutputSizes, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s OutputSizes
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse output_sizes.json: %w", err)
+	}
+	return s, nil
+}
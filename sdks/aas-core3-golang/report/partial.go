@@ -0,0 +1,151 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// PartialDir is the directory of a harness output directory holding one
+// progress file per operation, rewritten as each of its datasets finishes.
+const PartialDir = "partial"
+
+// PartialResult is the harness's own measurement of one finished dataset:
+// the final b.N and the time it took per iteration.
+type PartialResult struct {
+	Iterations int     `json:"iterations"`
+	NsPerOp    float64 `json:"ns_per_op"`
+	FinishedAt string  `json:"finished_at"`
+}
+
+// PartialOperation mirrors partialOperation written by partial_test.go: the
+// datasets an operation finished and, while one is being measured, its
+// name in Running. A file left with Running set means the harness died
+// measuring that dataset.
+type PartialOperation struct {
+	Operation string                   `json:"operation"`
+	Datasets  map[string]PartialResult `json:"datasets"`
+	Running   string                   `json:"running,omitempty"`
+}
+
+// Partial is the progress a harness run flushed before it ended, keyed by
+// operation.
+type Partial struct {
+	Operations map[string]*PartialOperation
+}
+
+// LoadPartial reads every progress file in dir.
+func LoadPartial(dir string) (*Partial, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	p := &Partial{Operations: make(map[string]*PartialOperation, len(paths))}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var op PartialOperation
+		if err := json.Unmarshal(data, &op); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", filepath.Base(path), err)
+		}
+		if op.Operation == "" {
+			op.Operation = strings.TrimSuffix(filepath.Base(path), ".json")
+		}
+		p.Operations[op.Operation] = &op
+	}
+	return p, nil
+}
+
+// Recover adds a result for every finished dataset in p that results lack,
+// as when the go test output was cut off before the harness died, and
+// returns how many it added. Recovered results have a single sample and no
+// allocation figures.
+func (p *Partial) Recover(results map[string]*BenchResult) int {
+	added := 0
+	for _, op := range p.Operations {
+		for dataset, r := range op.Datasets {
+			key := fmt.Sprintf("%s/%s", dataset, op.Operation)
+			if _, ok := results[key]; ok || r.Iterations <= 0 {
+				continue
+			}
+			results[key] = &BenchResult{
+				Operation: op.Operation,
+				Dataset:   dataset,
+				N:         r.Iterations,
+				NsPerOp:   r.NsPerOp,
+				Runs:      []float64{r.NsPerOp},
+			}
+			added++
+		}
+	}
+	return added
+}
+
+// addIncomplete adds a placeholder for every dataset an unfinished
+// operation has no result on: the operation a progress file was left
+// running on, and any expected operation without a single result. Which
+// datasets an operation that never started would have run on is unknown,
+// so the core datasets stand in; a micro track operation that was running
+// is only marked on its own dataset.
+func addIncomplete(datasets map[string]DatasetEntry, partial *Partial, expected []string) {
+	add := func(dataset, operation, reason string) {
+		ds, ok := datasets[dataset]
+		if !ok {
+			ds = DatasetEntry{Operations: make(map[string]OperationEntry)}
+			datasets[dataset] = ds
+		}
+		if _, done := ds.Operations[operation]; done {
+			return
+		}
+		ds.Operations[operation] = OperationEntry{
+			OperationID:          operation,
+			OperationTrack:       InferOperationTrack(dataset, operation),
+			MeasurementSemantics: "mean_ns_per_operation",
+			FailureState:         FailureIncomplete,
+			SkipReason:           reason,
+		}
+	}
+	core := make([]string, 0, len(CoreDatasets))
+	for name := range CoreDatasets {
+		core = append(core, name)
+	}
+	sort.Strings(core)
+
+	if partial != nil {
+		for _, op := range partial.Operations {
+			if op.Running == "" {
+				continue
+			}
+			const reason = "the harness exited before this operation finished"
+			add(op.Running, op.Operation, reason)
+			if !strings.HasPrefix(op.Running, MicroDatasetPrefix) {
+				for _, dataset := range core {
+					add(dataset, op.Operation, reason)
+				}
+			}
+		}
+	}
+
+	started := make(map[string]bool)
+	for _, ds := range datasets {
+		for id := range ds.Operations {
+			started[id] = true
+		}
+	}
+	for _, operation := range expected {
+		if started[operation] {
+			continue
+		}
+		for _, dataset := range core {
+			add(dataset, operation, "the harness exited before this operation ran")
+		}
+	}
+}
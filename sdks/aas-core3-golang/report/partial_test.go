@@ -0,0 +1,70 @@
+package report
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// crashedBundle is a run that died measuring deserialize on mixed: go test
+// printed the wide result only, and the progress file also has deep.
+func crashedBundle(t *testing.T, raw ...string) Bundle {
+	t.Helper()
+	b := Bundle{BenchRaw: writeRaw(t, raw...), Partial: t.TempDir()}
+	progress := `{"operation": "deserialize", "running": "mixed", "datasets": {
+		"wide": {"iterations": 100, "ns_per_op": 1000, "finished_at": "2026-10-16T03:00:00Z"},
+		"deep": {"iterations": 50, "ns_per_op": 4000, "finished_at": "2026-10-16T03:00:01Z"}}}`
+	if err := os.WriteFile(filepath.Join(b.Partial, "deserialize.json"), []byte(progress), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestPartialReportMarksUnfinishedOperations(t *testing.T) {
+	b := crashedBundle(t,
+		`{"Action":"output","Test":"BenchmarkDeserialize/wide","Output":"BenchmarkDeserialize/wide-8   \t 120\t  990 ns/op\n"}`,
+		`{"Action":"output","Test":"BenchmarkDeserialize/mixed","Output":"panic: runtime error\n"}`,
+	)
+	results, opts, err := b.Load(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := results["wide/deserialize"]; r == nil || r.N != 120 {
+		t.Errorf("wide/deserialize = %+v, want the go test result to win", r)
+	}
+	if r := results["deep/deserialize"]; r == nil || r.N != 50 || r.Runs[0] != 4000 {
+		t.Errorf("deep/deserialize = %+v, want it recovered from the progress file", r)
+	}
+
+	opts.ExpectedOperations = []string{"deserialize", "validate"}
+	rep := Build(results, opts)
+	if op := rep.Datasets["mixed"].Operations["deserialize"]; op.FailureState != FailureIncomplete || op.SkipReason == "" {
+		t.Errorf("mixed/deserialize = %+v, want incomplete", op)
+	}
+	if op := rep.Datasets["deep"].Operations["deserialize"]; !op.Measured() {
+		t.Errorf("deep/deserialize = %+v, want measured", op)
+	}
+	for _, ds := range []string{"wide", "deep", "mixed"} {
+		if op := rep.Datasets[ds].Operations["validate"]; op.FailureState != FailureIncomplete {
+			t.Errorf("%s/validate = %+v, want incomplete", ds, op)
+		}
+	}
+}
+
+func TestPartialReportWithoutGoTestResults(t *testing.T) {
+	b := crashedBundle(t, `{"Action":"output","Output":"signal: killed\n"}`)
+	results, _, err := b.Load(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("Load failed although progress files hold results: %v", err)
+	}
+	if len(results) != 2 {
+		t.Errorf("results = %v, want wide and deep recovered", results)
+	}
+
+	b.Partial = ""
+	if _, _, err := b.Load(slog.New(slog.NewTextHandler(io.Discard, nil))); err == nil {
+		t.Error("Load accepted output without results and without progress files")
+	}
+}
@@ -0,0 +1,193 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Placeholders that replace what Redact removes from report strings.
+const (
+	RedactedPath = "<path>"
+	RedactedHost = "<host>"
+	RedactedUser = "<user>"
+)
+
+var (
+	// unixPath matches an absolute path after a delimiter, which keeps the
+	// path of a URL (https://host/path) and an os/arch pair out of it. A
+	// colon ends the path, as in "open /data/wide.json: no such file".
+	unixPath = regexp.MustCompile(`(^|[\s"'=(\[,])(/[^\s"',()\[\]:]+)`)
+	// windowsPath matches a drive-letter path.
+	windowsPath = regexp.MustCompile(`(^|[\s"'=(\[,])([A-Za-z]:\\[^\s"',()\[\]:]*)`)
+)
+
+// publicPathPrefixes are kernel interfaces, the same on every Linux host,
+// which event sources name.
+var publicPathPrefixes = []string{"/proc/", "/sys/", "/dev/"}
+
+// Redactor removes what identifies the host that measured a report: its
+// name, the user that ran the benchmarks and absolute paths. Names
+// shorter than three characters are left alone, as replacing them would
+// mangle unrelated words.
+type Redactor struct {
+	Hostname string
+	Username string
+}
+
+// HostRedactor returns a Redactor for the current host and user.
+func HostRedactor() Redactor {
+	var x Redactor
+	x.Hostname, _ = os.Hostname()
+	if u, err := user.Current(); err == nil {
+		x.Username = u.Username
+	} else {
+		x.Username = os.Getenv("USER")
+	}
+	// A Windows username is DOMAIN\name.
+	if i := strings.LastIndex(x.Username, `\`); i >= 0 {
+		x.Username = x.Username[i+1:]
+	}
+	return x
+}
+
+// PrivateMetadata is what Redact removed from a report. The contributor
+// keeps it to tell their runs apart; it is never published.
+type PrivateMetadata struct {
+	SDKID     string `json:"sdk_id"`
+	Timestamp string `json:"timestamp"`
+	Hostname  string `json:"hostname,omitempty"`
+	Username  string `json:"username,omitempty"`
+	// Environment is the host description Redact dropped from the report.
+	Environment *Environment `json:"environment,omitempty"`
+	// Replaced maps the JSON path of every string Redact changed to its
+	// original value.
+	Replaced map[string]string `json:"replaced,omitempty"`
+}
+
+// PrivateFileName returns where the private metadata of the report at
+// path is kept: report.json's is report.private.json.
+func PrivateFileName(path string) string {
+	return strings.TrimSuffix(path, filepath.Ext(path)) + ".private.json"
+}
+
+// Redact strips r for publishing. The host description in environment is
+// dropped, since metadata.platform already says what the results are
+// comparable with, and hostnames, usernames and absolute paths are
+// replaced by placeholders in every string of the report. What was
+// removed is returned, and r.Redacted lists where.
+func (x Redactor) Redact(r *Report) (*PrivateMetadata, error) {
+	private := &PrivateMetadata{
+		SDKID:       r.SDKID,
+		Timestamp:   r.Metadata.Timestamp,
+		Hostname:    x.Hostname,
+		Username:    x.Username,
+		Environment: r.Environment,
+		Replaced:    make(map[string]string),
+	}
+	redacted := r.Redacted
+	if r.Environment != nil {
+		r.Environment = nil
+		redacted = append(redacted, "environment")
+	}
+
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+	tree = x.redactValue(tree, "", private.Replaced)
+	if len(private.Replaced) == 0 {
+		r.Redacted = sortedUnique(redacted)
+		return private, nil
+	}
+	for path := range private.Replaced {
+		redacted = append(redacted, path)
+	}
+	if data, err = json.Marshal(tree); err != nil {
+		return nil, err
+	}
+	var out Report
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	*r = out
+	r.Redacted = sortedUnique(redacted)
+	return private, nil
+}
+
+// redactValue walks a decoded JSON value, replacing strings and recording
+// the originals under their paths.
+func (x Redactor) redactValue(v interface{}, path string, replaced map[string]string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			v[k] = x.redactValue(child, joinPath(path, k), replaced)
+		}
+	case []interface{}:
+		for i, child := range v {
+			v[i] = x.redactValue(child, path+"["+strconv.Itoa(i)+"]", replaced)
+		}
+	case string:
+		if s := x.String(v); s != v {
+			replaced[path] = v
+			return s
+		}
+	}
+	return v
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// String replaces the absolute paths, the hostname and the username in s.
+func (x Redactor) String(s string) string {
+	for _, re := range []*regexp.Regexp{unixPath, windowsPath} {
+		s = re.ReplaceAllStringFunc(s, func(m string) string {
+			sub := re.FindStringSubmatch(m)
+			for _, prefix := range publicPathPrefixes {
+				if strings.HasPrefix(sub[2], prefix) {
+					return m
+				}
+			}
+			return sub[1] + RedactedPath
+		})
+	}
+	for _, name := range []struct{ value, placeholder string }{
+		{x.Hostname, RedactedHost},
+		{x.Username, RedactedUser},
+	} {
+		if len(name.value) < 3 {
+			continue
+		}
+		re := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(name.value) + `\b`)
+		s = re.ReplaceAllString(s, name.placeholder)
+	}
+	return s
+}
+
+func sortedUnique(items []string) []string {
+	sort.Strings(items)
+	out := items[:0]
+	for i, item := range items {
+		if i == 0 || item != items[i-1] {
+			out = append(out, item)
+		}
+	}
+	return out
+}
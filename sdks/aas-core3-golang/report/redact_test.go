@@ -0,0 +1,58 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactorString(t *testing.T) {
+	x := Redactor{Hostname: "bench-07.corp.example", Username: "alice"}
+	for in, want := range map[string]string{
+		"no deep.json in /home/alice/datasets":                 "no deep.json in <path>",
+		`read C:\Users\alice\data\wide.json: denied`:           "read <path>: denied",
+		"container alice-server on bench-07.corp.example":      "container <user>-server on <host>",
+		"/sys/devices/system/cpu":                              "/sys/devices/system/cpu",
+		"https://github.com/aas-core-works/aas-core3.0-golang": "https://github.com/aas-core-works/aas-core3.0-golang",
+		"linux/amd64": "linux/amd64",
+		"malice":      "malice",
+	} {
+		if got := x.String(in); got != want {
+			t.Errorf("String(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := (Redactor{Username: "go"}).String("go test"); got != "go test" {
+		t.Errorf("a two-letter username was replaced: %q", got)
+	}
+}
+
+func TestRedact(t *testing.T) {
+	env := &Environment{OS: "linux", Arch: "amd64", KernelVersion: "6.1.0"}
+	r := &Report{
+		SDKID:       "aas-core3-golang",
+		Metadata:    Metadata{Timestamp: "2026-01-02T03:04:05Z", BenchmarkHarness: "testing.B on bench-07"},
+		Environment: env,
+		Datasets: map[string]DatasetEntry{"wide": {Operations: map[string]OperationEntry{
+			"deserialize": {OperationID: "deserialize", FailureState: FailureSkippedMissingDataset, SkipReason: "no wide.json in /data/alice"},
+		}}},
+	}
+	private, err := Redactor{Hostname: "bench-07", Username: "alice"}.Redact(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Environment != nil || private.Environment != env {
+		t.Errorf("environment = %+v, private %+v; want it moved to the private metadata", r.Environment, private.Environment)
+	}
+	if got := r.Metadata.BenchmarkHarness; got != "testing.B on <host>" {
+		t.Errorf("benchmark_harness = %q", got)
+	}
+	if got := r.Datasets["wide"].Operations["deserialize"].SkipReason; got != "no wide.json in <path>" {
+		t.Errorf("skip_reason = %q", got)
+	}
+	wantPaths := []string{"datasets.wide.operations.deserialize.skip_reason", "environment", "metadata.benchmark_harness"}
+	if !reflect.DeepEqual(r.Redacted, wantPaths) {
+		t.Errorf("redacted = %v, want %v", r.Redacted, wantPaths)
+	}
+	if private.Replaced["metadata.benchmark_harness"] != "testing.B on bench-07" || private.SDKID != "aas-core3-golang" {
+		t.Errorf("private = %+v", private)
+	}
+}
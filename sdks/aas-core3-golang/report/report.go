@@ -0,0 +1,297 @@
+// Package report defines the report.json schema shared by all SDK adapters
+// and builds reports from Go benchmark output plus harness side channels.
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// SchemaVersion is the report.json schema version written by Build. Version
+// 3 added metrics_manifest (see SetSchemaVersion).
+const SchemaVersion = 3
+
+// MemoryEntry holds memory metrics for report output.
+type MemoryEntry struct {
+	PeakRSSBytes    *int64 `json:"peak_rss_bytes"`
+	AllocBytesPerOp *int64 `json:"alloc_bytes_per_op"`
+	AllocCountPerOp *int64 `json:"alloc_count_per_op"`
+	// The per-op allocation figures are means over the -count runs; these
+	// are their extremes, which differ when allocation varies between runs.
+	AllocBytesPerOpMin *int64   `json:"alloc_bytes_per_op_min,omitempty"`
+	AllocBytesPerOpMax *int64   `json:"alloc_bytes_per_op_max,omitempty"`
+	AllocCountPerOpMin *int64   `json:"alloc_count_per_op_min,omitempty"`
+	AllocCountPerOpMax *int64   `json:"alloc_count_per_op_max,omitempty"`
+	HeapUsedBytes      *int64   `json:"heap_used_bytes"`
+	GcPauseMs          *float64 `json:"gc_pause_ms"`
+	GcCount            *int64   `json:"gc_count"`
+	TracedPeakBytes    *int64   `json:"traced_peak_bytes"`
+	// PeakIntermediateBytes is the most the heap grew during one call of
+	// the operation, probed outside the timed loop: what it holds at once
+	// besides its input, such as a whole serialized document.
+	PeakIntermediateBytes *int64 `json:"peak_intermediate_bytes,omitempty"`
+	// GcPauses is the distribution of the GC pauses during the operation
+	// group, where gc_pause_ms is only their total.
+	GcPauses *GCPauseStats `json:"gc_pauses,omitempty"`
+}
+
+// Failure states of an operation. Consumers compare only FailureOK
+// entries; the others explain why an operation has no measurements.
+const (
+	FailureOK = "ok"
+	// FailureSkippedMissingDataset marks an operation the harness supports
+	// but could not run because the dataset's input file was absent, as
+	// opposed to an operation that is missing because it is unsupported.
+	FailureSkippedMissingDataset = "skipped_missing_dataset"
+	// FailureIncomplete marks an operation a crashed harness run did not
+	// finish, in a partial report assembled from what it flushed.
+	FailureIncomplete = "incomplete"
+)
+
+// OperationEntry is one operation in the report.
+type OperationEntry struct {
+	OperationID          string  `json:"operation_id"`
+	OperationTrack       string  `json:"operation_track"`
+	SampleCount          int     `json:"sample_count"`
+	MeasurementSemantics string  `json:"measurement_semantics"`
+	FailureState         string  `json:"failure_state"`
+	Iterations           int     `json:"iterations"`
+	MeanNs               int64   `json:"mean_ns"`
+	MedianNs             int64   `json:"median_ns"`
+	StddevNs             int64   `json:"stddev_ns"`
+	MinNs                int64   `json:"min_ns"`
+	MaxNs                int64   `json:"max_ns"`
+	P75Ns                *int64  `json:"p75_ns"`
+	P99Ns                *int64  `json:"p99_ns"`
+	ThroughputOpsPerSec  float64 `json:"throughput_ops_per_sec"`
+	// OutliersRemoved counts the samples the report's outlier policy
+	// discarded before the statistics above, which sample_count excludes.
+	// Absent when no policy was applied.
+	OutliersRemoved *int `json:"outliers_removed,omitempty"`
+	// RelativeErrorPct is the half-width of the 95% confidence interval of
+	// the mean relative to it, in percent: the precision the samples
+	// achieved. Absent for a single sample.
+	RelativeErrorPct *float64 `json:"relative_error_pct,omitempty"`
+	// SamplesNs are the per-run ns/op samples the statistics above were
+	// computed from, for nonparametric comparison of two reports.
+	SamplesNs []int64     `json:"samples_ns,omitempty"`
+	Memory    MemoryEntry `json:"memory"`
+	// EnvironmentEvents lists disruptive system events that overlapped the
+	// operation's measurement window. Omitted when the run was undisturbed.
+	EnvironmentEvents []EventAnnotation `json:"environment_events,omitempty"`
+	// Stability compares ns/op across a benchtime sweep. Present only when
+	// the run included one.
+	Stability *Stability `json:"stability,omitempty"`
+	// SkipReason says why a skipped or incomplete operation was not run.
+	SkipReason string `json:"skip_reason,omitempty"`
+	// CustomMetrics are the metrics the benchmark reported besides time and
+	// allocations, by unit as printed (b.ReportMetric units such as
+	// "elements/op", and MB/s from b.SetBytes), each the mean over the
+	// runs. Absent when it reported none.
+	CustomMetrics map[string]float64 `json:"custom_metrics,omitempty"`
+	// OutputSize is the size of the document a serializing operation
+	// wrote for the dataset, plain and compressed. Absent for operations
+	// that write no document.
+	OutputSize *OutputSize `json:"output_size,omitempty"`
+	// Variant is the sub-benchmark path below the dataset of a
+	// parameterized run of the operation, such as "parallel=8" for
+	// BenchmarkDeserialize/wide/parallel=8, and Params the parameters it
+	// names: "key=value" segments by key, others as level<n>. Absent on
+	// the operation itself, unless it only ran as variants and this is
+	// the first of them.
+	Variant string            `json:"variant,omitempty"`
+	Params  map[string]string `json:"params,omitempty"`
+	// Variants are the other parameterized runs of the operation on the
+	// dataset, in the order they ran. The metrics_manifest describes their
+	// fields by the operation's paths.
+	Variants []OperationEntry `json:"variants,omitempty"`
+}
+
+// Measured reports whether op holds measurements rather than a failure or
+// skip placeholder.
+func (op OperationEntry) Measured() bool {
+	return op.FailureState == "" || op.FailureState == FailureOK
+}
+
+// HardwareCounterEntry is the hardware counter totals of one operation
+// group with their per-iteration rates. The counts cover the benchmark
+// goroutine's OS thread in user space, including b.N ramp-up and any setup
+// inside the benchmark function, so rates are comparable across runs of
+// the same harness rather than exact per-call costs.
+type HardwareCounterEntry struct {
+	HardwareCounterGroup
+	InstructionsPerOp float64 `json:"instructions_per_op"`
+	CacheMissesPerOp  float64 `json:"cache_misses_per_op"`
+	BranchMissesPerOp float64 `json:"branch_misses_per_op"`
+}
+
+// EnergyEntry is the energy of one operation group with its average power
+// and energy per iteration. RAPL meters the whole CPU package, so the
+// figures include everything else the host ran meanwhile, and like the
+// hardware counters they include b.N ramp-up.
+type EnergyEntry struct {
+	EnergyGroup
+	Watts            float64 `json:"watts"`
+	MicrojoulesPerOp float64 `json:"microjoules_per_op"`
+}
+
+// EventAnnotation is a system event attached to an affected operation.
+type EventAnnotation struct {
+	Kind   string `json:"kind"`
+	Source string `json:"source"`
+	Detail string `json:"detail"`
+	Start  string `json:"start"`
+	End    string `json:"end"`
+}
+
+// DatasetEntry holds all operations for one dataset.
+type DatasetEntry struct {
+	FileSizeBytes *int64                    `json:"file_size_bytes"`
+	ElementCount  *int64                    `json:"element_count"`
+	Operations    map[string]OperationEntry `json:"operations"`
+}
+
+// AllocationSite is one function's share of the heap allocations sampled
+// while an operation group's timed loops ran.
+type AllocationSite struct {
+	Function string `json:"function"`
+	Bytes    int64  `json:"bytes"`
+	Objects  int64  `json:"objects"`
+}
+
+// DatasetFingerprint identifies one dataset file consumed by a run. Two
+// reports whose manifests agree were measured on byte-identical inputs.
+type DatasetFingerprint struct {
+	Dataset      string `json:"dataset"`
+	File         string `json:"file"`
+	Format       string `json:"format"`
+	SHA256       string `json:"sha256"`
+	SizeBytes    int64  `json:"size_bytes"`
+	ElementCount *int64 `json:"element_count"`
+}
+
+// Report is the top-level output schema.
+type Report struct {
+	SchemaVersion int                     `json:"schema_version"`
+	SDKID         string                  `json:"sdk_id"`
+	Metadata      Metadata                `json:"metadata"`
+	Datasets      map[string]DatasetEntry `json:"datasets"`
+	// MetricsManifest gives the unit and aggregation of every numeric field
+	// in the report by the pattern of its path; see UnitsManifest. Present
+	// from schema version 3.
+	MetricsManifest map[string]MetricSemantics `json:"metrics_manifest,omitempty"`
+	// Headline is the handful of metrics that represent the SDK on badges,
+	// PR comments and the dashboard summary; see MetricCatalog.
+	Headline []HeadlineMetric `json:"headline,omitempty"`
+	// AllocationHotspots maps an operation ID to its top allocation sites.
+	// Present only when the run was heap profiled.
+	AllocationHotspots map[string][]AllocationSite `json:"allocation_hotspots,omitempty"`
+	// HardwareCounters maps an operation ID to its CPU counter readings.
+	// Present only when the run counted them (PERF_COUNTERS on Linux).
+	HardwareCounters map[string]HardwareCounterEntry `json:"hardware_counters,omitempty"`
+	// Energy maps an operation ID to the energy its group consumed.
+	// Present only when the run metered it (ENERGY on Linux with RAPL).
+	Energy map[string]EnergyEntry `json:"energy,omitempty"`
+	// ParseDiagnostics records lines of the benchmark output that could not
+	// be used, so partial results are distinguishable from complete ones.
+	ParseDiagnostics *ParseDiagnostics `json:"parse_diagnostics,omitempty"`
+	// Environment describes the host the run was measured on.
+	Environment *Environment `json:"environment,omitempty"`
+	// EnvironmentNoise is NoiseHigh when the control benchmark interleaved
+	// with the operations drifted beyond its threshold; every timing in the
+	// report is then suspect. ControlBenchmark holds the evidence.
+	EnvironmentNoise string          `json:"environment_noise,omitempty"`
+	ControlBenchmark *ControlSummary `json:"control_benchmark,omitempty"`
+	// Calibration is what the harness itself costs per iteration: an empty
+	// loop, a noop call and parsing each dataset into generic JSON. Subtract
+	// it from operation times, and distrust any operation faster than the
+	// noop call.
+	Calibration []CalibrationEntry `json:"calibration,omitempty"`
+	// OutlierPolicy is the policy that trimmed each operation's samples
+	// before its statistics (see FilterOutliers). Absent for raw reports.
+	OutlierPolicy string `json:"outlier_policy,omitempty"`
+	// RunVariance is the spread of each operation across the separate runs
+	// MergeRuns combined. Present only for multi-run reports.
+	RunVariance *RunVariance `json:"run_variance,omitempty"`
+	// Scaling has the complexity curve of every operation measured along a
+	// size ladder. Present only when the run included ladder datasets.
+	Scaling []ScalingCurve `json:"scaling,omitempty"`
+	// Robustness is the pass/fail verdict per corruption class of the
+	// deserialize_invalid inputs. Present only when that operation ran.
+	Robustness []RobustnessClass `json:"robustness,omitempty"`
+	// CompressionSweep is the time and package size of aasx_repackage at
+	// every zip compression level. Present only when the adapter swept them.
+	CompressionSweep []CompressionPoint `json:"compression_sweep,omitempty"`
+	// AdaptiveSampling records how aasbench run --target-error topped up
+	// the samples of imprecise operations. Present only for such runs.
+	AdaptiveSampling *AdaptiveSampling `json:"adaptive_sampling,omitempty"`
+	// Containment records whether every operation group completed, thrashed
+	// or ran out of memory under a cap. Present only for runs with
+	// --memory-cap.
+	Containment *Containment `json:"containment,omitempty"`
+	// BuildMetrics is the compile time and binary size of a minimal
+	// program using the SDK. Present only when aasbench run measured them.
+	BuildMetrics *BuildMetrics `json:"build_metrics,omitempty"`
+	// Flamegraphs links every CPU-profiled operation to its flamegraph.
+	// Present only for runs with --cpu-profile.
+	Flamegraphs Flamegraphs `json:"flamegraphs,omitempty"`
+	// ExecTraces summarizes the execution trace of one iteration of every
+	// operation. Present only for runs with --trace.
+	ExecTraces ExecTraces `json:"exec_traces,omitempty"`
+	// Soak is the heap trend of a process looping one dataset through
+	// deserialize, update and serialize. Present only for runs with --soak.
+	Soak *Soak `json:"soak,omitempty"`
+	// Truncated lists the sections Limits cut short, and FullReport names
+	// the untrimmed report written next to this one, relative to it.
+	Truncated  []Truncation `json:"truncated,omitempty"`
+	FullReport string       `json:"full_report,omitempty"`
+	// Redacted lists what was stripped for publishing: "environment" and
+	// the JSON path of every string a hostname, username or absolute path
+	// was replaced in. The originals are in the private metadata file.
+	Redacted []string `json:"redacted,omitempty"`
+}
+
+// UnmarshalJSON reads a report, moving the top-level datasets_manifest of
+// reports written before it became part of the metadata into Metadata.
+func (r *Report) UnmarshalJSON(data []byte) error {
+	type plain Report
+	var legacy struct {
+		plain
+		DatasetsManifest []DatasetFingerprint `json:"datasets_manifest"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+	*r = Report(legacy.plain)
+	if len(legacy.DatasetsManifest) > 0 && len(r.Metadata.DatasetsManifest) == 0 {
+		r.Metadata.DatasetsManifest = legacy.DatasetsManifest
+		r.Metadata.migrationNotes = append(r.Metadata.migrationNotes, `top-level "datasets_manifest" moved into metadata`)
+	}
+	return nil
+}
+
+// Load reads a report.json file.
+func Load(path string) (*Report, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return &r, nil
+}
+
+// Write marshals r as indented JSON to path.
+func Write(path string, r *Report) error {
+	out, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal report: %w", err)
+	}
+	if err := os.WriteFile(path, out, 0644); err != nil {
+		return fmt.Errorf("write report: %w", err)
+	}
+	return nil
+}
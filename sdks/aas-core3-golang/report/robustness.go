@@ -0,0 +1,140 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Outcomes of a corrupted input in robustness.json. Only RobustnessRejected
+// passes: an SDK must reject bad input with an error, neither accepting it
+// nor panicking.
+const (
+	RobustnessRejected = "rejected"
+	RobustnessAccepted = "accepted"
+	RobustnessPanicked = "panicked"
+	// A fuzz input hangs when it was not decoded within the deadline, and
+	// exceeds memory when the live heap outgrew its budget meanwhile.
+	RobustnessHung           = "hung"
+	RobustnessMemoryExceeded = "memory_exceeded"
+)
+
+// FuzzClassPrefix starts the class of every fuzz corpus input, followed by
+// its mutation (fuzz_field_rename). A mutant may still be valid, so fuzz
+// classes pass when nothing panicked, hung or exceeded memory, whether
+// inputs were rejected or accepted.
+const FuzzClassPrefix = "fuzz_"
+
+// RobustnessEntry mirrors robustnessEntry written by robustness_test.go:
+// what deserializing one corrupted input of a dataset did, and for a
+// rejected input, what rejecting it cost.
+type RobustnessEntry struct {
+	Dataset     string  `json:"dataset"`
+	Format      string  `json:"format"`
+	Class       string  `json:"class"`
+	Input       string  `json:"input,omitempty"`
+	Outcome     string  `json:"outcome"`
+	Detail      string  `json:"detail,omitempty"`
+	NsPerOp     float64 `json:"ns_per_op,omitempty"`
+	BytesPerOp  int64   `json:"bytes_per_op,omitempty"`
+	AllocsPerOp int64   `json:"allocs_per_op,omitempty"`
+}
+
+// Robustness is the schema of the robustness.json file.
+type Robustness struct {
+	Entries []RobustnessEntry `json:"entries"`
+}
+
+// LoadRobustness reads the side-channel robustness.json file.
+func LoadRobustness(path string) (*Robustness, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var r Robustness
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parse robustness.json: %w", err)
+	}
+	return &r, nil
+}
+
+// RobustnessClass is the verdict on one corruption class of one input
+// format across all datasets: it passes when every input was rejected, or
+// for a fuzz class, when none panicked, hung or exceeded memory. Failures
+// lists the datasets (and fuzz inputs) that did not pass, with their
+// outcome. NsPerOp is the time to reject the class's input, per dataset.
+type RobustnessClass struct {
+	Format         string             `json:"format"`
+	Class          string             `json:"class"`
+	Inputs         int                `json:"inputs"`
+	Rejected       int                `json:"rejected"`
+	Accepted       int                `json:"accepted"`
+	Panicked       int                `json:"panicked"`
+	Hung           int                `json:"hung,omitempty"`
+	MemoryExceeded int                `json:"memory_exceeded,omitempty"`
+	Passed         bool               `json:"passed"`
+	Failures       []string           `json:"failures,omitempty"`
+	NsPerOp        map[string]float64 `json:"ns_per_op,omitempty"`
+}
+
+// SummarizeRobustness groups the entries by format and class, in that
+// order.
+func SummarizeRobustness(r *Robustness) []RobustnessClass {
+	byClass := make(map[string]*RobustnessClass)
+	for _, e := range r.Entries {
+		key := e.Format + "/" + e.Class
+		c, ok := byClass[key]
+		if !ok {
+			c = &RobustnessClass{Format: e.Format, Class: e.Class}
+			byClass[key] = c
+		}
+		c.Inputs++
+		switch e.Outcome {
+		case RobustnessRejected:
+			c.Rejected++
+		case RobustnessAccepted:
+			c.Accepted++
+		case RobustnessPanicked:
+			c.Panicked++
+		case RobustnessHung:
+			c.Hung++
+		case RobustnessMemoryExceeded:
+			c.MemoryExceeded++
+		}
+		if !robustOutcome(e.Class, e.Outcome) {
+			name := e.Dataset
+			if e.Input != "" {
+				name += "/" + e.Input
+			}
+			c.Failures = append(c.Failures, name+": "+e.Outcome)
+		} else if e.Outcome == RobustnessRejected && e.NsPerOp > 0 {
+			if c.NsPerOp == nil {
+				c.NsPerOp = make(map[string]float64)
+			}
+			c.NsPerOp[e.Dataset] = e.NsPerOp
+		}
+	}
+	classes := make([]RobustnessClass, 0, len(byClass))
+	for _, c := range byClass {
+		c.Passed = len(c.Failures) == 0
+		sort.Strings(c.Failures)
+		classes = append(classes, *c)
+	}
+	sort.Slice(classes, func(i, j int) bool {
+		if classes[i].Format != classes[j].Format {
+			return classes[i].Format < classes[j].Format
+		}
+		return classes[i].Class < classes[j].Class
+	})
+	return classes
+}
+
+// robustOutcome reports whether an input of class was handled robustly.
+func robustOutcome(class, outcome string) bool {
+	if strings.HasPrefix(class, FuzzClassPrefix) {
+		return outcome == RobustnessRejected || outcome == RobustnessAccepted
+	}
+	return outcome == RobustnessRejected
+}
@@ -0,0 +1,51 @@
+package report
+
+import "testing"
+
+func TestSummarizeRobustness(t *testing.T) {
+	classes := SummarizeRobustness(&Robustness{Entries: []RobustnessEntry{
+		{Dataset: "wide", Format: "xml", Class: "truncated", Outcome: RobustnessRejected, NsPerOp: 1200},
+		{Dataset: "wide", Format: "json", Class: "wrong_type", Outcome: RobustnessRejected},
+		{Dataset: "deep", Format: "json", Class: "wrong_type", Outcome: RobustnessPanicked, Detail: "index out of range"},
+		{Dataset: "mixed", Format: "json", Class: "wrong_type", Outcome: RobustnessAccepted},
+	}})
+	if len(classes) != 2 || classes[0].Format != "json" || classes[1].Format != "xml" {
+		t.Fatalf("classes = %+v, want json/wrong_type then xml/truncated", classes)
+	}
+	wrongType := classes[0]
+	if wrongType.Passed || wrongType.Inputs != 3 || wrongType.Rejected != 1 || wrongType.Accepted != 1 || wrongType.Panicked != 1 {
+		t.Errorf("json/wrong_type = %+v", wrongType)
+	}
+	if got := wrongType.Failures; len(got) != 2 || got[0] != "deep: panicked" || got[1] != "mixed: accepted" {
+		t.Errorf("failures = %v", got)
+	}
+	if !classes[1].Passed || classes[1].Failures != nil || classes[1].NsPerOp["wide"] != 1200 {
+		t.Errorf("xml/truncated = %+v, want passed in 1200 ns on wide", classes[1])
+	}
+	if wrongType.NsPerOp != nil {
+		t.Errorf("json/wrong_type timings = %v, want none for untimed inputs", wrongType.NsPerOp)
+	}
+}
+
+func TestSummarizeRobustnessPassesGracefulFuzzInputs(t *testing.T) {
+	classes := SummarizeRobustness(&Robustness{Entries: []RobustnessEntry{
+		{Dataset: "deep", Format: "json", Class: "fuzz_depth_explosion", Input: "deep_depth_explosion_000.json", Outcome: RobustnessAccepted},
+		{Dataset: "deep", Format: "json", Class: "fuzz_depth_explosion", Input: "deep_depth_explosion_001.json", Outcome: RobustnessRejected},
+		{Dataset: "mixed", Format: "json", Class: "fuzz_type_flip", Input: "mixed_type_flip_000.json", Outcome: RobustnessRejected},
+		{Dataset: "mixed", Format: "json", Class: "fuzz_type_flip", Input: "mixed_type_flip_001.json", Outcome: RobustnessHung},
+		{Dataset: "mixed", Format: "json", Class: "fuzz_type_flip", Input: "mixed_type_flip_002.json", Outcome: RobustnessMemoryExceeded},
+	}})
+	if len(classes) != 2 {
+		t.Fatalf("classes = %+v", classes)
+	}
+	if depth := classes[0]; !depth.Passed || depth.Accepted != 1 || depth.Rejected != 1 {
+		t.Errorf("fuzz_depth_explosion = %+v, want passed with one accepted and one rejected input", depth)
+	}
+	flip := classes[1]
+	if flip.Passed || flip.Hung != 1 || flip.MemoryExceeded != 1 {
+		t.Errorf("fuzz_type_flip = %+v, want failed with one hung and one over memory", flip)
+	}
+	if got := flip.Failures; len(got) != 2 || got[0] != "mixed/mixed_type_flip_001.json: hung" || got[1] != "mixed/mixed_type_flip_002.json: memory_exceeded" {
+		t.Errorf("failures = %v", got)
+	}
+}
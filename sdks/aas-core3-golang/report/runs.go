@@ -0,0 +1,245 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// RunVariance records how much each operation moved between the runs a
+// report was merged from. Runs of a separate process each pay their own
+// warm-up, heap layout and scheduler placement, so this spread is a truer
+// error bar than the stddev of -count samples within one process.
+type RunVariance struct {
+	Runs       int                `json:"runs"`
+	Operations []RunVarianceEntry `json:"operations"`
+}
+
+// RunVarianceEntry is one operation's mean in every run that measured it.
+type RunVarianceEntry struct {
+	Dataset     string  `json:"dataset"`
+	OperationID string  `json:"operation_id"`
+	RunMeansNs  []int64 `json:"run_means_ns"`
+	MedianNs    int64   `json:"median_ns"`
+	// CVPct is the coefficient of variation of the run means, and
+	// SpreadPct their range relative to the smallest.
+	CVPct     float64 `json:"cv_pct"`
+	SpreadPct float64 `json:"spread_pct"`
+}
+
+// MergeRuns combines reports of the same SDK, each from a full run in its
+// own process, into one. Each measured operation takes the median across
+// runs of mean_ns, median_ns, stddev_ns, p75_ns, p99_ns and iterations, the
+// extremes of min_ns and max_ns, and the sums of the sample counts and
+// discarded outliers, with the samples of every run and the relative error
+// they achieve together; its memory figures, events and stability come
+// from the run whose mean is the median. Run-level sections come from the
+// first run, except that the environment counts as noisy when any run was.
+// The spread between runs is recorded in RunVariance.
+func MergeRuns(runs []*Report) (*Report, error) {
+	if len(runs) == 0 {
+		return nil, fmt.Errorf("no runs to merge")
+	}
+	first := runs[0]
+	for _, r := range runs[1:] {
+		if r.SDKID != first.SDKID {
+			return nil, fmt.Errorf("cannot merge runs of %s and %s", first.SDKID, r.SDKID)
+		}
+	}
+
+	merged := *first
+	merged.Datasets = make(map[string]DatasetEntry)
+	merged.Truncated = nil
+	merged.FullReport = ""
+	merged.RunVariance = &RunVariance{Runs: len(runs), Operations: []RunVarianceEntry{}}
+	for _, r := range runs {
+		if r.EnvironmentNoise == NoiseHigh && merged.EnvironmentNoise != NoiseHigh {
+			merged.EnvironmentNoise, merged.ControlBenchmark = r.EnvironmentNoise, r.ControlBenchmark
+		}
+	}
+
+	for _, dsName := range datasetNames(runs) {
+		ds := DatasetEntry{Operations: make(map[string]OperationEntry)}
+		for _, r := range runs {
+			if src, ok := r.Datasets[dsName]; ok {
+				if ds.FileSizeBytes == nil {
+					ds.FileSizeBytes = src.FileSizeBytes
+				}
+				if ds.ElementCount == nil {
+					ds.ElementCount = src.ElementCount
+				}
+			}
+		}
+		for _, opID := range operationNames(runs, dsName) {
+			var entries []OperationEntry
+			var placeholder *OperationEntry
+			for _, r := range runs {
+				op, ok := r.Datasets[dsName].Operations[opID]
+				switch {
+				case !ok:
+				case op.Measured():
+					entries = append(entries, op)
+				case placeholder == nil:
+					placeholder = &op
+				}
+			}
+			if len(entries) == 0 {
+				ds.Operations[opID] = *placeholder
+				continue
+			}
+			op, variance := mergeOperation(entries)
+			variance.Dataset, variance.OperationID = dsName, opID
+			ds.Operations[opID] = op
+			merged.RunVariance.Operations = append(merged.RunVariance.Operations, variance)
+		}
+		merged.Datasets[dsName] = ds
+	}
+	merged.Headline = DefaultCatalog().SelectHeadline(merged.Datasets)
+	merged.Scaling = ComputeScaling(merged.Datasets, DefaultSuperLinearExponent)
+	return &merged, nil
+}
+
+// mergeOperation merges the measured entries of one operation.
+func mergeOperation(entries []OperationEntry) (OperationEntry, RunVarianceEntry) {
+	byMean := append([]OperationEntry(nil), entries...)
+	sort.SliceStable(byMean, func(i, j int) bool { return byMean[i].MeanNs < byMean[j].MeanNs })
+	// The lower median for an even count, so the representative is a run
+	// that actually happened.
+	op := byMean[(len(byMean)-1)/2]
+
+	means := make([]float64, len(entries))
+	medians := make([]float64, len(entries))
+	stddevs := make([]float64, len(entries))
+	iterations := make([]float64, len(entries))
+	var p75s, p99s []float64
+	var removed int
+	op.SampleCount = 0
+	op.SamplesNs = nil
+	for i, e := range entries {
+		means[i] = float64(e.MeanNs)
+		medians[i] = float64(e.MedianNs)
+		stddevs[i] = float64(e.StddevNs)
+		iterations[i] = float64(e.Iterations)
+		if e.P75Ns != nil {
+			p75s = append(p75s, float64(*e.P75Ns))
+		}
+		if e.P99Ns != nil {
+			p99s = append(p99s, float64(*e.P99Ns))
+		}
+		if e.MinNs < op.MinNs {
+			op.MinNs = e.MinNs
+		}
+		if e.MaxNs > op.MaxNs {
+			op.MaxNs = e.MaxNs
+		}
+		op.SampleCount += e.SampleCount
+		op.SamplesNs = append(op.SamplesNs, e.SamplesNs...)
+		if e.OutliersRemoved != nil {
+			removed += *e.OutliersRemoved
+		}
+	}
+	if op.OutliersRemoved != nil {
+		op.OutliersRemoved = &removed
+	}
+	if op.RelativeErrorPct != nil {
+		samples := make([]float64, len(op.SamplesNs))
+		for i, ns := range op.SamplesNs {
+			samples[i] = float64(ns)
+		}
+		relErr := math.Round(RelativeErrorPct(samples)*100) / 100
+		op.RelativeErrorPct = &relErr
+	}
+
+	meanOfMeans, medianMean, stddevMean, minMean, maxMean := ComputeStats(means)
+	op.MeanNs = int64(medianMean)
+	op.MedianNs = int64(medianOf(medians))
+	op.StddevNs = int64(medianOf(stddevs))
+	op.Iterations = int(medianOf(iterations))
+	if len(p75s) == len(entries) {
+		v := int64(medianOf(p75s))
+		op.P75Ns = &v
+	}
+	if len(p99s) == len(entries) {
+		v := int64(medianOf(p99s))
+		op.P99Ns = &v
+	}
+	op.ThroughputOpsPerSec = 0
+	if op.MeanNs > 0 {
+		op.ThroughputOpsPerSec = 1e9 / float64(op.MeanNs)
+	}
+
+	op.Variants = mergeVariants(entries)
+
+	v := RunVarianceEntry{MedianNs: op.MeanNs}
+	for _, e := range entries {
+		v.RunMeansNs = append(v.RunMeansNs, e.MeanNs)
+	}
+	if meanOfMeans > 0 {
+		v.CVPct = math.Round(stddevMean/meanOfMeans*10000) / 100
+	}
+	if minMean > 0 {
+		v.SpreadPct = math.Round((maxMean-minMean)/minMean*10000) / 100
+	}
+	return op, v
+}
+
+// mergeVariants merges the variants of the entries of one operation by
+// their Variant, in the order the runs first list them.
+func mergeVariants(entries []OperationEntry) []OperationEntry {
+	var order []string
+	byVariant := make(map[string][]OperationEntry)
+	for _, e := range entries {
+		for _, variant := range e.Variants {
+			if _, seen := byVariant[variant.Variant]; !seen {
+				order = append(order, variant.Variant)
+			}
+			if variant.Measured() {
+				byVariant[variant.Variant] = append(byVariant[variant.Variant], variant)
+			}
+		}
+	}
+	var merged []OperationEntry
+	for _, name := range order {
+		if len(byVariant[name]) > 0 {
+			op, _ := mergeOperation(byVariant[name])
+			merged = append(merged, op)
+		}
+	}
+	return merged
+}
+
+func medianOf(values []float64) float64 {
+	_, median, _, _, _ := ComputeStats(values)
+	return median
+}
+
+// datasetNames lists the datasets of any run, sorted.
+func datasetNames(runs []*Report) []string {
+	seen := make(map[string]bool)
+	for _, r := range runs {
+		for name := range r.Datasets {
+			seen[name] = true
+		}
+	}
+	return sortedNames(seen)
+}
+
+// operationNames lists the operations any run has for dataset, sorted.
+func operationNames(runs []*Report, dataset string) []string {
+	seen := make(map[string]bool)
+	for _, r := range runs {
+		for name := range r.Datasets[dataset].Operations {
+			seen[name] = true
+		}
+	}
+	return sortedNames(seen)
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,77 @@
+package report
+
+import "testing"
+
+func TestMergeRunsTakesMediansAndRecordsVariance(t *testing.T) {
+	run := func(mean, min, max int64, noise string) *Report {
+		alloc := mean / 10
+		return &Report{
+			SDKID:            "aas-core3-golang",
+			EnvironmentNoise: noise,
+			Datasets: map[string]DatasetEntry{"wide": {Operations: map[string]OperationEntry{
+				"deserialize": {
+					OperationID: "deserialize", FailureState: FailureOK, SampleCount: 5, Iterations: 100,
+					MeanNs: mean, MedianNs: mean, StddevNs: mean / 100, MinNs: min, MaxNs: max,
+					Memory: MemoryEntry{AllocBytesPerOp: &alloc},
+				},
+				"validate": {OperationID: "validate", FailureState: FailureSkippedMissingDataset},
+			}}},
+		}
+	}
+	merged, err := MergeRuns([]*Report{
+		run(1200, 1100, 1300, NoiseLow),
+		run(1000, 950, 1050, NoiseLow),
+		run(1100, 1000, 1400, NoiseHigh),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	op := merged.Datasets["wide"].Operations["deserialize"]
+	if op.MeanNs != 1100 || op.MinNs != 950 || op.MaxNs != 1400 || op.SampleCount != 15 {
+		t.Errorf("merged = mean %d min %d max %d samples %d, want 1100 950 1400 15", op.MeanNs, op.MinNs, op.MaxNs, op.SampleCount)
+	}
+	if *op.Memory.AllocBytesPerOp != 110 {
+		t.Errorf("memory not taken from the median run: %d", *op.Memory.AllocBytesPerOp)
+	}
+	if merged.Datasets["wide"].Operations["validate"].FailureState != FailureSkippedMissingDataset {
+		t.Error("skipped operation not kept")
+	}
+	if merged.EnvironmentNoise != NoiseHigh {
+		t.Errorf("environment_noise = %q, want %q from the noisy run", merged.EnvironmentNoise, NoiseHigh)
+	}
+
+	v := merged.RunVariance
+	if v == nil || v.Runs != 3 || len(v.Operations) != 1 {
+		t.Fatalf("run_variance = %+v, want 3 runs with one operation", v)
+	}
+	if e := v.Operations[0]; e.SpreadPct != 20 || e.CVPct != 9.09 || len(e.RunMeansNs) != 3 {
+		t.Errorf("variance = %+v, want spread 20%% and cv 9.09%%", e)
+	}
+
+	other := run(1000, 900, 1100, NoiseLow)
+	other.SDKID = "basyx-python"
+	if _, err := MergeRuns([]*Report{run(1000, 900, 1100, NoiseLow), other}); err == nil {
+		t.Error("want an error merging runs of different SDKs")
+	}
+}
+
+func TestMergeRunsMergesVariants(t *testing.T) {
+	run := func(mean, parallelMean int64) *Report {
+		variant := OperationEntry{OperationID: "deserialize", FailureState: FailureOK, SampleCount: 1, MeanNs: parallelMean, Variant: "parallel=8"}
+		return &Report{
+			SDKID: "aas-core3-golang",
+			Datasets: map[string]DatasetEntry{"wide": {Operations: map[string]OperationEntry{
+				"deserialize": {OperationID: "deserialize", FailureState: FailureOK, SampleCount: 1, MeanNs: mean, Variants: []OperationEntry{variant}},
+			}}},
+		}
+	}
+	merged, err := MergeRuns([]*Report{run(1000, 300), run(1100, 500), run(1200, 400)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := merged.Datasets["wide"].Operations["deserialize"]
+	if len(op.Variants) != 1 || op.Variants[0].MeanNs != 400 || op.Variants[0].SampleCount != 3 {
+		t.Errorf("variants = %+v, want parallel=8 merged to the median 400 over 3 samples", op.Variants)
+	}
+}
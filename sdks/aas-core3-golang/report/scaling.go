@@ -0,0 +1,149 @@
+package report
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ScalingDatasetPrefix marks the size ladder datasets that
+// datasets/generate.py --scaling writes, named scale_<shape>_x<factor>.
+const ScalingDatasetPrefix = "scale_"
+
+// DefaultSuperLinearExponent is the fitted scaling exponent above which an
+// operation counts as super-linear; a linear operation fits close to 1.
+const DefaultSuperLinearExponent = 1.2
+
+// ScalingPoint is an operation's mean on one rung of a size ladder.
+type ScalingPoint struct {
+	Dataset      string `json:"dataset"`
+	Factor       int    `json:"factor"`
+	ElementCount *int64 `json:"element_count"`
+	MeanNs       int64  `json:"mean_ns"`
+}
+
+// ScalingCurve is how one operation's time grows along one ladder shape.
+// Exponent is b of the least-squares fit mean_ns = a * size^b on log-log
+// axes, where size is the element count, or the factor when a rung's count
+// is unknown; RSquared says how well that power law fits.
+type ScalingCurve struct {
+	Shape       string         `json:"shape"`
+	OperationID string         `json:"operation_id"`
+	Points      []ScalingPoint `json:"points"`
+	Exponent    float64        `json:"exponent"`
+	RSquared    float64        `json:"r_squared"`
+	SuperLinear bool           `json:"super_linear"`
+}
+
+// ParseScalingDataset splits a ladder dataset name into its shape and
+// factor; ok is false for other datasets.
+func ParseScalingDataset(name string) (shape string, factor int, ok bool) {
+	rest, found := strings.CutPrefix(name, ScalingDatasetPrefix)
+	if !found {
+		return "", 0, false
+	}
+	i := strings.LastIndex(rest, "_x")
+	if i <= 0 {
+		return "", 0, false
+	}
+	factor, err := strconv.Atoi(rest[i+2:])
+	if err != nil || factor < 1 {
+		return "", 0, false
+	}
+	return rest[:i], factor, true
+}
+
+// ComputeScaling fits a curve for every operation measured on at least two
+// rungs of a ladder shape, sorted by shape and operation. It returns nil
+// when datasets hold no ladder.
+func ComputeScaling(datasets map[string]DatasetEntry, superLinearExponent float64) []ScalingCurve {
+	byCurve := make(map[[2]string][]ScalingPoint)
+	for name, ds := range datasets {
+		shape, factor, ok := ParseScalingDataset(name)
+		if !ok {
+			continue
+		}
+		for opID, op := range ds.Operations {
+			if !op.Measured() {
+				continue
+			}
+			key := [2]string{shape, opID}
+			byCurve[key] = append(byCurve[key], ScalingPoint{
+				Dataset:      name,
+				Factor:       factor,
+				ElementCount: ds.ElementCount,
+				MeanNs:       op.MeanNs,
+			})
+		}
+	}
+
+	var curves []ScalingCurve
+	for key, points := range byCurve {
+		if len(points) < 2 {
+			continue
+		}
+		sort.Slice(points, func(i, j int) bool { return points[i].Factor < points[j].Factor })
+		c := ScalingCurve{Shape: key[0], OperationID: key[1], Points: points}
+		c.Exponent, c.RSquared = fitPowerLaw(points)
+		c.SuperLinear = c.Exponent > superLinearExponent
+		curves = append(curves, c)
+	}
+	sort.Slice(curves, func(i, j int) bool {
+		if curves[i].Shape != curves[j].Shape {
+			return curves[i].Shape < curves[j].Shape
+		}
+		return curves[i].OperationID < curves[j].OperationID
+	})
+	return curves
+}
+
+// fitPowerLaw regresses log mean_ns on log size, rounding both results to
+// three decimals.
+func fitPowerLaw(points []ScalingPoint) (exponent, rSquared float64) {
+	byCount := true
+	for _, p := range points {
+		byCount = byCount && p.ElementCount != nil && *p.ElementCount > 0
+	}
+	xs := make([]float64, len(points))
+	ys := make([]float64, len(points))
+	for i, p := range points {
+		size := float64(p.Factor)
+		if byCount {
+			size = float64(*p.ElementCount)
+		}
+		xs[i], ys[i] = math.Log(size), math.Log(math.Max(float64(p.MeanNs), 1))
+	}
+
+	exponent, rSquared = linearFit(xs, ys)
+	return math.Round(exponent*1000) / 1000, math.Round(rSquared*1000) / 1000
+}
+
+// linearFit regresses ys on xs by least squares and returns the slope of
+// the line and its coefficient of determination, both zero when xs do not
+// vary.
+func linearFit(xs, ys []float64) (slope, rSquared float64) {
+	n := float64(len(xs))
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+	var sxx, sxy, syy float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		sxx += dx * dx
+		sxy += dx * dy
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return 0, 0
+	}
+	slope = sxy / sxx
+	rSquared = 1
+	if syy > 0 {
+		rSquared = sxy * sxy / (sxx * syy)
+	}
+	return slope, rSquared
+}
@@ -0,0 +1,51 @@
+package report
+
+import "testing"
+
+func TestParseScalingDataset(t *testing.T) {
+	if shape, factor, ok := ParseScalingDataset("scale_deep_x16"); !ok || shape != "deep" || factor != 16 {
+		t.Errorf("scale_deep_x16 = %q, %d, %v", shape, factor, ok)
+	}
+	for _, name := range []string{"deep", "scale_deep", "scale_x4", "scale_deep_x0", "scale_deep_xl"} {
+		if _, _, ok := ParseScalingDataset(name); ok {
+			t.Errorf("%s parsed as a ladder dataset", name)
+		}
+	}
+	if track := InferOperationTrack("scale_wide_x2", "deserialize"); track != "scaling" {
+		t.Errorf("track = %q, want scaling", track)
+	}
+}
+
+func TestComputeScalingFitsExponent(t *testing.T) {
+	rung := func(elements, meanNs int64) DatasetEntry {
+		return DatasetEntry{ElementCount: &elements, Operations: map[string]OperationEntry{
+			"deserialize": {MeanNs: meanNs, Iterations: 10},
+		}}
+	}
+	datasets := map[string]DatasetEntry{
+		"mixed": rung(500, 1000),
+		// Linear along wide, quadratic along deep.
+		"scale_wide_x1": rung(1000, 2000),
+		"scale_wide_x2": rung(2000, 4000),
+		"scale_wide_x4": rung(4000, 8000),
+		"scale_deep_x1": rung(40, 100),
+		"scale_deep_x2": rung(80, 400),
+		"scale_deep_x4": rung(160, 1600),
+		// A single rung fits nothing.
+		"scale_tall_x1": rung(10, 10),
+	}
+	curves := ComputeScaling(datasets, DefaultSuperLinearExponent)
+	if len(curves) != 2 || curves[0].Shape != "deep" || curves[1].Shape != "wide" {
+		t.Fatalf("curves = %+v, want deep and wide", curves)
+	}
+	deep, wide := curves[0], curves[1]
+	if deep.Exponent != 2 || deep.RSquared != 1 || !deep.SuperLinear {
+		t.Errorf("deep = %+v, want exponent 2, super-linear", deep)
+	}
+	if wide.Exponent != 1 || wide.SuperLinear || wide.Points[2].Factor != 4 {
+		t.Errorf("wide = %+v, want exponent 1 with points by factor", wide)
+	}
+	if ComputeScaling(map[string]DatasetEntry{"mixed": rung(500, 1000)}, DefaultSuperLinearExponent) != nil {
+		t.Error("curves without ladder datasets")
+	}
+}
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"bufio"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// SideChannelShimPrefix starts an output line carrying a side channel in
+// place of its file: "<prefix><file name> <base64 JSON>". A harness writes
+// its side channels so when SIDE_CHANNEL_SHIM is set, as under a
+// WebAssembly runtime whose view of the host file system cannot be relied
+// on; ExtractSideChannels turns them back into files.
+const SideChannelShimPrefix = "aasbench-side-channel: "
+
+// ExtractSideChannels writes every side channel carried in the go test
+// -json output at rawPath to dir and returns the file names written. A
+// later line for the same file replaces an earlier one.
+func ExtractSideChannels(rawPath, dir string) ([]string, error) {
+	f, err := os.Open(rawPath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var names []string
+	written := make(map[string]bool)
+	partial := make(map[string]string) // unterminated output per test
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
+	for scanner.Scan() {
+		var event GoTestEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || event.Action != "output" {
+			continue
+		}
+		// test2json splits long lines over several events.
+		key := event.Package + " " + event.Test
+		text := partial[key] + event.Output
+		if !strings.HasSuffix(text, "\n") {
+			partial[key] = text
+			continue
+		}
+		delete(partial, key)
+		for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+			rest, ok := strings.CutPrefix(line, SideChannelShimPrefix)
+			if !ok {
+				continue
+			}
+			name, encoded, ok := strings.Cut(rest, " ")
+			if !ok || name != filepath.Base(name) || !strings.HasSuffix(name, ".json") {
+				return names, fmt.Errorf("%s: malformed side channel line %.60q", rawPath, line)
+			}
+			data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+			if err != nil {
+				return names, fmt.Errorf("%s: side channel %s: %w", rawPath, name, err)
+			}
+			if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+				return names, err
+			}
+			if !written[name] {
+				written[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return names, fmt.Errorf("read %s: %w", rawPath, err)
+	}
+	return names, nil
+}
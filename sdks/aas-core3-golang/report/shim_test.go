@@ -0,0 +1,48 @@
+package report
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExtractSideChannelsJoinsSplitLines(t *testing.T) {
+	dir := t.TempDir()
+	payload := `{"gomaxprocs": 1, "pinned": false}`
+	line := SideChannelShimPrefix + SchedulingFile + " " + base64.StdEncoding.EncodeToString([]byte(payload)) + "\n"
+	var raw []byte
+	for _, out := range []string{"BenchmarkTraverse/wide \t 1\t 100 ns/op\n", line[:30], line[30:]} {
+		event, _ := json.Marshal(GoTestEvent{Action: "output", Package: "p", Output: out})
+		raw = append(append(raw, event...), '\n')
+	}
+	rawPath := filepath.Join(dir, BenchRawFile)
+	if err := os.WriteFile(rawPath, raw, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := ExtractSideChannels(rawPath, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 || names[0] != SchedulingFile {
+		t.Fatalf("names = %v", names)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, SchedulingFile))
+	if err != nil || string(got) != payload {
+		t.Errorf("scheduling.json = %q, %v", got, err)
+	}
+}
+
+func TestExtractSideChannelsRejectsPaths(t *testing.T) {
+	dir := t.TempDir()
+	event, _ := json.Marshal(GoTestEvent{Action: "output", Output: SideChannelShimPrefix + "../x.json e30=\n"})
+	rawPath := filepath.Join(dir, BenchRawFile)
+	if err := os.WriteFile(rawPath, append(event, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ExtractSideChannels(rawPath, dir); err == nil {
+		t.Error("want an error for a side channel outside dir")
+	}
+}
@@ -0,0 +1,357 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// MemSnapshot mirrors the snapshot struct written by bench_pipeline_test.go.
+type MemSnapshot struct {
+	HeapAllocBytes  uint64 `json:"heap_alloc_bytes"`
+	HeapSysBytes    uint64 `json:"heap_sys_bytes"`
+	TotalAllocBytes uint64 `json:"total_alloc_bytes"`
+	NumGC           uint32 `json:"num_gc"`
+	PauseTotalNs    uint64 `json:"pause_total_ns"`
+}
+
+// MemStats is the schema of the memory_stats.json file.
+type MemStats struct {
+	Before MemSnapshot            `json:"before"`
+	After  MemSnapshot            `json:"after"`
+	Groups map[string]MemSnapshot `json:"groups"`
+	// Peaks is the peak intermediate allocation of the operations that
+	// probe it, by PeakKey.
+	Peaks map[string]int64 `json:"peaks,omitempty"`
+}
+
+// PeakKey is the key of an operation's peak on dataset in MemStats.Peaks.
+func PeakKey(operation, dataset string) string {
+	return operation + "/" + dataset
+}
+
+// Harness stages timed in stages.json.
+const (
+	StageDatasetLoad   = "dataset_load"
+	StageBenchmark     = "benchmark"
+	StageMemoryCapture = "memory_capture"
+)
+
+// StageSpan mirrors stageSpan written by stages_test.go: how long one
+// harness stage took. Benchmark spans name the operation and dataset,
+// dataset loads the dataset, and memory captures the operation and dataset
+// of a peak probe.
+type StageSpan struct {
+	Stage      string    `json:"stage"`
+	Operation  string    `json:"operation,omitempty"`
+	Dataset    string    `json:"dataset,omitempty"`
+	Start      time.Time `json:"start"`
+	DurationNs int64     `json:"duration_ns"`
+}
+
+// StageTimings is the schema of the stages.json file.
+type StageTimings struct {
+	Spans []StageSpan `json:"spans"`
+}
+
+// LoadStageTimings reads the side-channel stages.json file.
+func LoadStageTimings(path string) (*StageTimings, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t StageTimings
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("parse stages.json: %w", err)
+	}
+	return &t, nil
+}
+
+// Window mirrors observationWindow written by events_test.go.
+type Window struct {
+	Operation string    `json:"operation"`
+	Dataset   string    `json:"dataset"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+}
+
+// Event mirrors systemEvent written by events_test.go.
+type Event struct {
+	Kind   string    `json:"kind"`
+	Source string    `json:"source"`
+	Detail string    `json:"detail"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// Events is the schema of the events.json file.
+type Events struct {
+	SampleIntervalMs int64    `json:"sample_interval_ms"`
+	Windows          []Window `json:"windows"`
+	Events           []Event  `json:"events"`
+}
+
+// HeapHotspots is the schema of the heap_hotspots.json file.
+type HeapHotspots struct {
+	TopN   int                         `json:"top_n"`
+	Groups map[string][]AllocationSite `json:"groups"`
+	// Sites lists every allocation site per group and Iterations the
+	// benchmark iterations they were sampled over. Older files lack both.
+	Sites      map[string][]AllocationSite `json:"sites,omitempty"`
+	Iterations map[string]int64            `json:"iterations,omitempty"`
+}
+
+// HardwareCounterGroup is the hardware counter totals of one operation
+// group, over Iterations benchmark iterations.
+type HardwareCounterGroup struct {
+	Iterations   int64 `json:"iterations"`
+	Instructions int64 `json:"instructions"`
+	CacheMisses  int64 `json:"cache_misses"`
+	BranchMisses int64 `json:"branch_misses"`
+}
+
+// HardwareCounters is the schema of the hardware_counters.json file.
+// Unavailable explains why a PERF_COUNTERS run counted nothing, e.g. no
+// PMU in a virtual machine or a restrictive perf_event_paranoid.
+type HardwareCounters struct {
+	Events      []string                        `json:"events"`
+	Unavailable string                          `json:"unavailable,omitempty"`
+	Groups      map[string]HardwareCounterGroup `json:"groups"`
+}
+
+// EnergyGroup is the RAPL energy one operation group consumed over
+// Iterations benchmark iterations and Seconds of wall time. Joules is per
+// domain ("package-0", "package-0/dram"); TotalJoules sums the packages.
+type EnergyGroup struct {
+	Iterations  int64              `json:"iterations"`
+	Seconds     float64            `json:"seconds"`
+	Joules      map[string]float64 `json:"joules"`
+	TotalJoules float64            `json:"total_joules"`
+}
+
+// Energy is the schema of the energy.json file. Unavailable explains why
+// an ENERGY run measured nothing, e.g. no RAPL zones or unreadable
+// energy_uj files.
+type Energy struct {
+	Source      string                 `json:"source"`
+	Domains     []string               `json:"domains"`
+	Unavailable string                 `json:"unavailable,omitempty"`
+	Groups      map[string]EnergyGroup `json:"groups"`
+}
+
+// GCPauseBucket is one non-empty bucket of a GC pause histogram.
+type GCPauseBucket struct {
+	LowerNs int64  `json:"lower_ns"`
+	UpperNs int64  `json:"upper_ns"`
+	Count   uint64 `json:"count"`
+}
+
+// GCPauses is the schema of the gc_pauses.json file: per operation group,
+// the GC pauses of its sub-benchmarks as a histogram of the runtime/metrics
+// Metric, buckets in ascending order.
+type GCPauses struct {
+	Metric string                     `json:"metric"`
+	Groups map[string][]GCPauseBucket `json:"groups"`
+}
+
+// BuildInfo mirrors buildInfoFile written by buildinfo_test.go.
+type BuildInfo struct {
+	SDKID      string `json:"sdk_id,omitempty"`
+	GoVersion  string `json:"go_version"`
+	SDKModule  string `json:"sdk_module"`
+	SDKVersion string `json:"sdk_version"`
+	SDKSum     string `json:"sdk_sum,omitempty"`
+	GitCommit  string `json:"git_commit,omitempty"`
+	GitDirty   *bool  `json:"git_dirty,omitempty"`
+	Platform   string `json:"platform,omitempty"`
+}
+
+// Scheduling mirrors schedulingFile written by scheduling_test.go.
+type Scheduling struct {
+	GOMAXPROCS  int    `json:"gomaxprocs"`
+	CPUAffinity string `json:"cpu_affinity,omitempty"`
+	Pinned      bool   `json:"pinned"`
+}
+
+// ControlSample mirrors controlSample written by control_test.go.
+type ControlSample struct {
+	Before string    `json:"before"`
+	At     time.Time `json:"at"`
+	Ns     int64     `json:"ns"`
+}
+
+// Control is the schema of the control.json file.
+type Control struct {
+	Workload   string          `json:"workload"`
+	Iterations int             `json:"iterations"`
+	Samples    []ControlSample `json:"samples"`
+}
+
+// SkippedEntry mirrors skippedEntry written by skipped_test.go: an
+// operation the harness runs that had no input for an expected dataset.
+type SkippedEntry struct {
+	Operation string `json:"operation"`
+	Dataset   string `json:"dataset"`
+	Reason    string `json:"reason"`
+}
+
+// Skipped is the schema of the skipped.json file.
+type Skipped struct {
+	Entries []SkippedEntry `json:"entries"`
+}
+
+// LoadMemoryStats reads the side-channel memory_stats.json file if it exists.
+func LoadMemoryStats(path string) (*MemStats, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var stats MemStats
+	if err := json.Unmarshal(data, &stats); err != nil {
+		return nil, fmt.Errorf("parse memory_stats.json: %w", err)
+	}
+	return &stats, nil
+}
+
+// LoadEvents reads the side-channel events.json timeline.
+func LoadEvents(path string) (*Events, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var events Events
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("parse events.json: %w", err)
+	}
+	return &events, nil
+}
+
+// LoadHeapHotspots reads the side-channel heap_hotspots.json file.
+func LoadHeapHotspots(path string) (*HeapHotspots, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var hotspots HeapHotspots
+	if err := json.Unmarshal(data, &hotspots); err != nil {
+		return nil, fmt.Errorf("parse heap_hotspots.json: %w", err)
+	}
+	return &hotspots, nil
+}
+
+// LoadBuildInfo reads the side-channel build_info.json file.
+func LoadBuildInfo(path string) (*BuildInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var info BuildInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return nil, fmt.Errorf("parse build_info.json: %w", err)
+	}
+	return &info, nil
+}
+
+// LoadScheduling reads the side-channel scheduling.json file.
+func LoadScheduling(path string) (*Scheduling, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Scheduling
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse scheduling.json: %w", err)
+	}
+	return &s, nil
+}
+
+// LoadControl reads the side-channel control.json file.
+func LoadControl(path string) (*Control, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var control Control
+	if err := json.Unmarshal(data, &control); err != nil {
+		return nil, fmt.Errorf("parse control.json: %w", err)
+	}
+	return &control, nil
+}
+
+// LoadSkipped reads the side-channel skipped.json file.
+func LoadSkipped(path string) (*Skipped, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var skipped Skipped
+	if err := json.Unmarshal(data, &skipped); err != nil {
+		return nil, fmt.Errorf("parse skipped.json: %w", err)
+	}
+	return &skipped, nil
+}
+
+// OverlappingEvents returns the events whose detection interval intersects
+// any measurement window recorded for dataset/operation. With -count=N the
+// same sub-benchmark has N windows; each event is reported at most once.
+func (timeline *Events) OverlappingEvents(dataset, operation string) []EventAnnotation {
+	var annotations []EventAnnotation
+	for _, ev := range timeline.Events {
+		for _, w := range timeline.Windows {
+			if w.Dataset != dataset || w.Operation != operation {
+				continue
+			}
+			if ev.Start.Before(w.End) && ev.End.After(w.Start) {
+				annotations = append(annotations, EventAnnotation{
+					Kind:   ev.Kind,
+					Source: ev.Source,
+					Detail: ev.Detail,
+					Start:  ev.Start.UTC().Format(time.RFC3339Nano),
+					End:    ev.End.UTC().Format(time.RFC3339Nano),
+				})
+				break
+			}
+		}
+	}
+	return annotations
+}
+
+// LoadGCPauses reads the side-channel gc_pauses.json file.
+func LoadGCPauses(path string) (*GCPauses, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var pauses GCPauses
+	if err := json.Unmarshal(data, &pauses); err != nil {
+		return nil, fmt.Errorf("parse gc_pauses.json: %w", err)
+	}
+	return &pauses, nil
+}
+
+// LoadHardwareCounters reads the side-channel hardware_counters.json file.
+func LoadHardwareCounters(path string) (*HardwareCounters, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var counters HardwareCounters
+	if err := json.Unmarshal(data, &counters); err != nil {
+		return nil, fmt.Errorf("parse hardware_counters.json: %w", err)
+	}
+	return &counters, nil
+}
+
+// LoadEnergy reads the side-channel energy.json file.
+func LoadEnergy(path string) (*Energy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var energy Energy
+	if err := json.Unmarshal(data, &energy); err != nil {
+		return nil, fmt.Errorf("parse energy.json: %w", err)
+	}
+	return &energy, nil
+}
@@ -0,0 +1,75 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+)
+
+// SoakWarmupFraction is the share of a soak, from its start, whose samples
+// FitSoak leaves out: the heap first grows to its working size, which is
+// no leak.
+const SoakWarmupFraction = 0.1
+
+// Soak is the schema of the soak.json file and the report's soak section:
+// the live heap of a process that looped one dataset through deserialize,
+// update and serialize for minutes, as a daemon would, sampled at a fixed
+// interval. A single-iteration benchmark cannot show memory that a
+// long-lived process keeps and never frees; the trend of these samples
+// does.
+type Soak struct {
+	Dataset    string  `json:"dataset"`
+	DurationS  float64 `json:"duration_s"`
+	IntervalS  float64 `json:"interval_s"`
+	Iterations int64   `json:"iterations"`
+	// MemoryGrowthBytesPerMin is the slope of the least-squares line
+	// through the heap samples after warm-up, and RSquared how well the
+	// line fits them: a steady leak fits near 1, a heap that only
+	// fluctuates near 0.
+	MemoryGrowthBytesPerMin float64      `json:"memory_growth_bytes_per_min"`
+	RSquared                float64      `json:"r_squared"`
+	Samples                 []SoakSample `json:"samples"`
+}
+
+// SoakSample is one reading of the soaking process, taken right after a
+// garbage collection so that only live memory counts.
+type SoakSample struct {
+	ElapsedS   float64 `json:"elapsed_s"`
+	HeapBytes  uint64  `json:"heap_bytes"`
+	Goroutines int     `json:"goroutines"`
+}
+
+// FitSoak sets the growth and fit of s from its samples, leaving both zero
+// when fewer than two samples follow the warm-up.
+func FitSoak(s *Soak) {
+	s.MemoryGrowthBytesPerMin, s.RSquared = 0, 0
+	var xs, ys []float64
+	for _, sample := range s.Samples {
+		if sample.ElapsedS < s.DurationS*SoakWarmupFraction {
+			continue
+		}
+		xs = append(xs, sample.ElapsedS/60)
+		ys = append(ys, float64(sample.HeapBytes))
+	}
+	if len(xs) < 2 {
+		return
+	}
+	slope, rSquared := linearFit(xs, ys)
+	s.MemoryGrowthBytesPerMin = math.Round(slope)
+	s.RSquared = math.Round(rSquared*1000) / 1000
+}
+
+// LoadSoak reads the soak.json file written by a SOAK_DURATION run of the
+// harness.
+func LoadSoak(path string) (*Soak, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Soak
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("parse soak.json: %w", err)
+	}
+	return &s, nil
+}
@@ -0,0 +1,27 @@
+package report
+
+import "testing"
+
+func TestFitSoakMeasuresGrowthAfterWarmup(t *testing.T) {
+	// The heap jumps to its working size in the first minute, then leaks
+	// 1 KiB a minute.
+	s := &Soak{DurationS: 600, IntervalS: 60}
+	s.Samples = append(s.Samples, SoakSample{ElapsedS: 0, HeapBytes: 1 << 20, Goroutines: 2})
+	for minute := 1; minute <= 10; minute++ {
+		s.Samples = append(s.Samples, SoakSample{ElapsedS: float64(minute * 60), HeapBytes: 50<<20 + uint64(minute)*1024, Goroutines: 2})
+	}
+	FitSoak(s)
+	if s.MemoryGrowthBytesPerMin != 1024 || s.RSquared != 1 {
+		t.Errorf("growth = %v B/min, r² %v, want 1024 and 1", s.MemoryGrowthBytesPerMin, s.RSquared)
+	}
+
+	// A flat heap does not grow, and too few samples fit nothing.
+	flat := &Soak{DurationS: 60, Samples: []SoakSample{{ElapsedS: 10, HeapBytes: 5}, {ElapsedS: 20, HeapBytes: 5}}}
+	if FitSoak(flat); flat.MemoryGrowthBytesPerMin != 0 {
+		t.Errorf("flat heap grows %v B/min", flat.MemoryGrowthBytesPerMin)
+	}
+	short := &Soak{DurationS: 60, MemoryGrowthBytesPerMin: 7, Samples: []SoakSample{{ElapsedS: 30, HeapBytes: 5}}}
+	if FitSoak(short); short.MemoryGrowthBytesPerMin != 0 || short.RSquared != 0 {
+		t.Errorf("one sample: growth %v, r² %v, want zero", short.MemoryGrowthBytesPerMin, short.RSquared)
+	}
+}
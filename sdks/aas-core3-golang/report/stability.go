@@ -0,0 +1,101 @@
+package report
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultStabilityThresholdPct is the largest spread of ns/op across a
+// benchtime sweep for which an operation still counts as stable.
+const DefaultStabilityThresholdPct = 10.0
+
+// Trend values for a Stability.
+const (
+	TrendDecreasing = "decreasing" // ns/op falls as iterations grow, e.g. GC or setup amortization
+	TrendIncreasing = "increasing" // ns/op rises as iterations grow, e.g. heap growth
+)
+
+// SweepRun is the parsed benchmark output of one -benchtime setting.
+type SweepRun struct {
+	Benchtime string
+	Results   map[string]*BenchResult
+}
+
+// StabilityPoint is an operation's mean at one benchtime.
+type StabilityPoint struct {
+	Benchtime  string `json:"benchtime"`
+	Iterations int    `json:"iterations"`
+	MeanNs     int64  `json:"mean_ns"`
+}
+
+// Stability records whether ns/op holds across benchtimes. An unstable
+// operation depends on the iteration count, so its headline mean_ns is only
+// comparable between runs that used the same benchtime.
+type Stability struct {
+	Points       []StabilityPoint `json:"benchtimes"`
+	SpreadPct    float64          `json:"spread_pct"`
+	ThresholdPct float64          `json:"threshold_pct"`
+	Stable       bool             `json:"stable"`
+	// Trend is set for unstable operations whose ns/op moves monotonically
+	// with the iteration count.
+	Trend string `json:"trend,omitempty"`
+}
+
+// ComputeStability compares an operation's means across a sweep. It returns
+// nil with fewer than two points.
+func ComputeStability(points []StabilityPoint, thresholdPct float64) *Stability {
+	if len(points) < 2 {
+		return nil
+	}
+	sorted := append([]StabilityPoint(nil), points...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Iterations < sorted[j].Iterations })
+
+	minNs, maxNs := sorted[0].MeanNs, sorted[0].MeanNs
+	decreasing, increasing := true, true
+	for i, p := range sorted {
+		if p.MeanNs < minNs {
+			minNs = p.MeanNs
+		}
+		if p.MeanNs > maxNs {
+			maxNs = p.MeanNs
+		}
+		if i > 0 {
+			prev := sorted[i-1].MeanNs
+			decreasing = decreasing && p.MeanNs < prev
+			increasing = increasing && p.MeanNs > prev
+		}
+	}
+
+	s := &Stability{Points: sorted, ThresholdPct: thresholdPct}
+	if minNs > 0 {
+		s.SpreadPct = math.Round(float64(maxNs-minNs)/float64(minNs)*10000) / 100
+	}
+	s.Stable = s.SpreadPct <= thresholdPct
+	if !s.Stable {
+		switch {
+		case decreasing:
+			s.Trend = TrendDecreasing
+		case increasing:
+			s.Trend = TrendIncreasing
+		}
+	}
+	return s
+}
+
+// sweepPoints collects the points for one dataset/operation key.
+func sweepPoints(sweep []SweepRun, key string) []StabilityPoint {
+	var points []StabilityPoint
+	for _, run := range sweep {
+		r, ok := run.Results[key]
+		if !ok || len(r.Runs) == 0 {
+			continue
+		}
+		mean, _, _, _, _ := ComputeStats(r.Runs)
+		points = append(points, StabilityPoint{
+			Benchtime:  run.Benchtime,
+			Iterations: r.N / len(r.Runs),
+			MeanNs:     int64(math.Round(mean)),
+		})
+	}
+	return points
+}
@@ -0,0 +1,32 @@
+package report
+
+import "testing"
+
+func TestComputeStabilityFlagsIterationDependence(t *testing.T) {
+	s := ComputeStability([]StabilityPoint{
+		{Benchtime: "5s", Iterations: 5000, MeanNs: 1000},
+		{Benchtime: "100ms", Iterations: 100, MeanNs: 1500},
+		{Benchtime: "1s", Iterations: 1000, MeanNs: 1100},
+	}, DefaultStabilityThresholdPct)
+	if s == nil || s.Stable {
+		t.Fatalf("stability = %+v, want unstable", s)
+	}
+	if s.SpreadPct != 50 || s.Trend != TrendDecreasing {
+		t.Errorf("spread = %v trend = %q, want 50 and %q", s.SpreadPct, s.Trend, TrendDecreasing)
+	}
+	if s.Points[0].Benchtime != "100ms" {
+		t.Errorf("points not ordered by iterations: %+v", s.Points)
+	}
+
+	s = ComputeStability([]StabilityPoint{
+		{Benchtime: "100ms", Iterations: 100, MeanNs: 1000},
+		{Benchtime: "1s", Iterations: 1000, MeanNs: 1040},
+	}, DefaultStabilityThresholdPct)
+	if s == nil || !s.Stable || s.Trend != "" {
+		t.Errorf("stability = %+v, want stable without trend", s)
+	}
+
+	if ComputeStability([]StabilityPoint{{Benchtime: "1s", Iterations: 1, MeanNs: 1}}, 10) != nil {
+		t.Error("want nil stability for a single point")
+	}
+}
@@ -0,0 +1,197 @@
+package report
+
+import (
+	"math"
+	"sort"
+)
+
+// ComputeStats returns summary statistics over per-run ns/op samples.
+// Stddev is the sample standard deviation (n-1 denominator).
+func ComputeStats(runs []float64) (mean, median, stddev, min, max float64) {
+	if len(runs) == 0 {
+		return
+	}
+
+	// Sort for median
+	sorted := make([]float64, len(runs))
+	copy(sorted, runs)
+	sort.Float64s(sorted)
+
+	min = sorted[0]
+	max = sorted[len(sorted)-1]
+
+	// Mean
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	mean = sum / float64(len(sorted))
+
+	// Median
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		median = (sorted[mid-1] + sorted[mid]) / 2
+	} else {
+		median = sorted[mid]
+	}
+
+	// Stddev
+	if len(sorted) > 1 {
+		sumSq := 0.0
+		for _, v := range sorted {
+			diff := v - mean
+			sumSq += diff * diff
+		}
+		stddev = math.Sqrt(sumSq / float64(len(sorted)-1))
+	}
+
+	return
+}
+
+// Percentile returns the p-th percentile (0-100) of sorted values, linearly
+// interpolated between the two nearest ranks.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+// Outlier policies: which samples of an operation are discarded before its
+// statistics are computed. A noisy neighbour or a GC cycle landing in one
+// -count run inflates the mean and stddev far more than the median, and
+// trimming such runs keeps one disturbed sample from flagging a
+// regression.
+const (
+	// OutlierTukey discards the samples outside Tukey's fences, more than
+	// TukeyFenceK interquartile ranges below the first or above the third
+	// quartile.
+	OutlierTukey = "tukey"
+	// OutlierMAD discards the samples whose modified z-score, their
+	// distance from the median in median absolute deviations scaled to a
+	// normal stddev, exceeds MADCutoff.
+	OutlierMAD = "mad"
+	// OutlierNone keeps every sample, for raw reporting.
+	OutlierNone = "none"
+)
+
+// DefaultOutlierPolicy is the policy reports are built with unless another
+// is chosen.
+const DefaultOutlierPolicy = OutlierTukey
+
+// OutlierPolicies lists the policies FilterOutliers knows.
+var OutlierPolicies = []string{OutlierTukey, OutlierMAD, OutlierNone}
+
+const (
+	// TukeyFenceK is the fence distance of OutlierTukey in interquartile
+	// ranges.
+	TukeyFenceK = 1.5
+	// MADCutoff is the modified z-score above which OutlierMAD discards a
+	// sample (Iglewicz and Hoaglin).
+	MADCutoff = 3.5
+	// MinOutlierSamples is the fewest samples an operation needs for any
+	// to be discarded; quartiles of fewer say nothing.
+	MinOutlierSamples = 4
+)
+
+// ValidOutlierPolicy reports whether policy is one of OutlierPolicies.
+func ValidOutlierPolicy(policy string) bool {
+	for _, p := range OutlierPolicies {
+		if p == policy {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterOutliers returns the samples of runs policy keeps, in their order,
+// and how many it discarded. Fewer than MinOutlierSamples samples, or
+// samples without spread, are kept whole, as they are under OutlierNone or
+// an unknown policy.
+func FilterOutliers(runs []float64, policy string) (kept []float64, removed int) {
+	if len(runs) < MinOutlierSamples {
+		return runs, 0
+	}
+	sorted := make([]float64, len(runs))
+	copy(sorted, runs)
+	sort.Float64s(sorted)
+
+	var outside func(v float64) bool
+	switch policy {
+	case OutlierTukey:
+		q1, q3 := Percentile(sorted, 25), Percentile(sorted, 75)
+		iqr := q3 - q1
+		if iqr == 0 {
+			return runs, 0
+		}
+		lo, hi := q1-TukeyFenceK*iqr, q3+TukeyFenceK*iqr
+		outside = func(v float64) bool { return v < lo || v > hi }
+	case OutlierMAD:
+		median := Percentile(sorted, 50)
+		deviations := make([]float64, len(sorted))
+		for i, v := range sorted {
+			deviations[i] = math.Abs(v - median)
+		}
+		sort.Float64s(deviations)
+		mad := Percentile(deviations, 50)
+		if mad == 0 {
+			return runs, 0
+		}
+		// 0.6745 is the third quartile of the standard normal: it scales
+		// the MAD to the stddev of normally distributed samples.
+		outside = func(v float64) bool { return 0.6745*math.Abs(v-median)/mad > MADCutoff }
+	default:
+		return runs, 0
+	}
+	kept = make([]float64, 0, len(runs))
+	for _, v := range runs {
+		if outside(v) {
+			removed++
+			continue
+		}
+		kept = append(kept, v)
+	}
+	return kept, removed
+}
+
+// tQuantiles975 are the 97.5% quantiles of Student's t distribution by
+// degrees of freedom, for two-sided 95% confidence intervals of a mean.
+var tQuantiles975 = []float64{
+	1: 12.706, 4.303, 3.182, 2.776, 2.571, 2.447, 2.365, 2.306, 2.262, 2.228,
+	2.201, 2.179, 2.160, 2.145, 2.131, 2.120, 2.110, 2.101, 2.093, 2.086,
+	2.080, 2.074, 2.069, 2.064, 2.060, 2.056, 2.052, 2.048, 2.045, 2.042,
+}
+
+// tQuantile975 returns the 97.5% t quantile for df degrees of freedom,
+// rounding df down to the nearest tabulated value beyond 30.
+func tQuantile975(df int) float64 {
+	switch {
+	case df < len(tQuantiles975):
+		return tQuantiles975[df]
+	case df < 40:
+		return 2.042
+	case df < 60:
+		return 2.021
+	case df < 120:
+		return 2.000
+	}
+	return 1.980
+}
+
+// RelativeErrorPct returns the half-width of the 95% confidence interval
+// of the mean of runs relative to the mean, in percent: the precision the
+// samples pin the mean down to. It is 0 for fewer than two runs, which
+// have no spread to judge by.
+func RelativeErrorPct(runs []float64) float64 {
+	if len(runs) < 2 {
+		return 0
+	}
+	mean, _, stddev, _, _ := ComputeStats(runs)
+	if mean == 0 {
+		return 0
+	}
+	return tQuantile975(len(runs)-1) * stddev / math.Sqrt(float64(len(runs))) / mean * 100
+}
@@ -0,0 +1,96 @@
+package report
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestFilterOutliers(t *testing.T) {
+	// One run disturbed by a GC cycle among five steady ones.
+	runs := []float64{100, 102, 98, 101, 400, 99}
+	for _, tc := range []struct {
+		policy  string
+		want    []float64
+		removed int
+	}{
+		{OutlierTukey, []float64{100, 102, 98, 101, 99}, 1},
+		{OutlierMAD, []float64{100, 102, 98, 101, 99}, 1},
+		{OutlierNone, runs, 0},
+	} {
+		got, removed := FilterOutliers(runs, tc.policy)
+		if !reflect.DeepEqual(got, tc.want) || removed != tc.removed {
+			t.Errorf("%s: kept %v, removed %d; want %v, %d", tc.policy, got, removed, tc.want, tc.removed)
+		}
+	}
+
+	for name, runs := range map[string][]float64{
+		"too few":   {100, 100, 400},
+		"no spread": {100, 100, 100, 100, 100},
+		"steady":    {100, 104, 97, 101, 99},
+	} {
+		for _, policy := range []string{OutlierTukey, OutlierMAD} {
+			if got, removed := FilterOutliers(runs, policy); removed != 0 || len(got) != len(runs) {
+				t.Errorf("%s under %s: discarded %d", name, policy, removed)
+			}
+		}
+	}
+}
+
+func TestBuildAppliesOutlierPolicy(t *testing.T) {
+	results := map[string]*BenchResult{
+		"wide/deserialize": {Dataset: "wide", Operation: "deserialize", N: 10, Runs: []float64{100, 102, 98, 101, 400, 99}},
+	}
+
+	rep := Build(results, Options{})
+	op := rep.Datasets["wide"].Operations["deserialize"]
+	if rep.OutlierPolicy != DefaultOutlierPolicy {
+		t.Errorf("outlier_policy = %q, want %q", rep.OutlierPolicy, DefaultOutlierPolicy)
+	}
+	if op.OutliersRemoved == nil || *op.OutliersRemoved != 1 {
+		t.Errorf("outliers_removed = %v, want 1", op.OutliersRemoved)
+	}
+	if op.SampleCount != 5 || op.MeanNs != 100 || op.MaxNs != 102 {
+		t.Errorf("sample_count %d, mean_ns %d, max_ns %d; want 5, 100, 102", op.SampleCount, op.MeanNs, op.MaxNs)
+	}
+
+	raw := Build(results, Options{OutlierPolicy: OutlierNone})
+	op = raw.Datasets["wide"].Operations["deserialize"]
+	if raw.OutlierPolicy != "" || op.OutliersRemoved != nil {
+		t.Errorf("raw report records policy %q, outliers_removed %v", raw.OutlierPolicy, op.OutliersRemoved)
+	}
+	if op.SampleCount != 6 || op.MeanNs != 150 {
+		t.Errorf("raw sample_count %d, mean_ns %d; want 6, 150", op.SampleCount, op.MeanNs)
+	}
+}
+
+func TestRelativeErrorPct(t *testing.T) {
+	// mean 100, sample stddev sqrt(16/3): t(0.975, 3) * stddev / sqrt(4).
+	want := 3.182 * math.Sqrt(16.0/3) / 2
+	if got := RelativeErrorPct([]float64{98, 102, 98, 102}); math.Abs(got-want) > 1e-9 {
+		t.Errorf("RelativeErrorPct = %v, want %v", got, want)
+	}
+	if got := RelativeErrorPct([]float64{100}); got != 0 {
+		t.Errorf("single run: RelativeErrorPct = %v, want 0", got)
+	}
+	// Beyond the table, the quantile of the nearest smaller df applies.
+	if q := tQuantile975(45); q != 2.021 {
+		t.Errorf("tQuantile975(45) = %v, want 2.021", q)
+	}
+
+	results := map[string]*BenchResult{
+		"wide/deserialize": {Dataset: "wide", Operation: "deserialize", N: 10, Runs: []float64{98, 102, 98, 102}},
+		"deep/deserialize": {Dataset: "deep", Operation: "deserialize", N: 10, Runs: []float64{100}},
+	}
+	adaptive := &AdaptiveSampling{TargetErrorPct: 2, Rounds: 1}
+	rep := Build(results, Options{Adaptive: adaptive})
+	if op := rep.Datasets["wide"].Operations["deserialize"]; op.RelativeErrorPct == nil || *op.RelativeErrorPct != 3.67 {
+		t.Errorf("relative_error_pct = %v, want 3.67", op.RelativeErrorPct)
+	}
+	if op := rep.Datasets["deep"].Operations["deserialize"]; op.RelativeErrorPct != nil {
+		t.Errorf("single sample: relative_error_pct = %v, want absent", *op.RelativeErrorPct)
+	}
+	if rep.AdaptiveSampling != adaptive {
+		t.Errorf("adaptive_sampling = %+v, want the side channel", rep.AdaptiveSampling)
+	}
+}
@@ -0,0 +1,151 @@
+package report
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultUnitsJSON gives the unit and aggregation of every numeric field a
+// report can hold.
+//
+//go:embed units.json
+var defaultUnitsJSON []byte
+
+// LegacySchemaVersion is the last report.json schema version without a
+// metrics_manifest, still written on request for older consumers.
+const LegacySchemaVersion = 2
+
+// MetricSemantics is what one numeric report field measures: its unit and
+// how its samples were aggregated into it, both named in UnitsManifest.
+// A field whose unit varies names the sibling field holding it in
+// UnitField instead (a headline value's unit).
+type MetricSemantics struct {
+	Unit      string `json:"unit,omitempty"`
+	UnitField string `json:"unit_field,omitempty"`
+	Agg       string `json:"agg"`
+}
+
+// FieldSemantics is the semantics of the numeric fields at Path, a
+// dot-separated pattern in which "*" stands for any map key and a "[]"
+// suffix for the elements of an array ("datasets.*.operations.*.mean_ns",
+// "calibration[].mean_ns").
+type FieldSemantics struct {
+	Path string `json:"path"`
+	MetricSemantics
+}
+
+// UnitsManifest is the schema of units.json: the units and aggregations
+// with their meaning, and the semantics of every numeric report field.
+type UnitsManifest struct {
+	Units        map[string]string `json:"units"`
+	Aggregations map[string]string `json:"aggregations"`
+	Fields       []FieldSemantics  `json:"fields"`
+}
+
+// DefaultUnits returns the embedded units manifest.
+func DefaultUnits() *UnitsManifest {
+	var m UnitsManifest
+	if err := json.Unmarshal(defaultUnitsJSON, &m); err != nil {
+		panic(fmt.Sprintf("parse embedded units.json: %v", err))
+	}
+	return &m
+}
+
+// SetSchemaVersion makes r a report of the given schema version. Version 3
+// (SchemaVersion) carries a metrics_manifest describing every numeric
+// field r holds; LegacySchemaVersion drops it.
+func SetSchemaVersion(r *Report, version int) error {
+	switch version {
+	case LegacySchemaVersion:
+		r.SchemaVersion, r.MetricsManifest = version, nil
+	case SchemaVersion:
+		r.MetricsManifest = nil
+		data, err := json.Marshal(r)
+		if err != nil {
+			return fmt.Errorf("marshal report: %w", err)
+		}
+		var v interface{}
+		if err := json.Unmarshal(data, &v); err != nil {
+			return fmt.Errorf("unmarshal report: %w", err)
+		}
+		r.SchemaVersion, r.MetricsManifest = version, DefaultUnits().Describe(v)
+	default:
+		return fmt.Errorf("unsupported schema version %d (want %d or %d)", version, LegacySchemaVersion, SchemaVersion)
+	}
+	return nil
+}
+
+// Describe returns the semantics of every numeric field in v, a decoded
+// report.json that may have been amended with Go integers, by the pattern
+// of its path. Numbers that no pattern
+// matches are left out.
+func (m *UnitsManifest) Describe(v interface{}) map[string]MetricSemantics {
+	patterns := make([][]string, len(m.Fields))
+	for i, f := range m.Fields {
+		patterns[i] = strings.Split(f.Path, ".")
+	}
+	found := make(map[string]MetricSemantics)
+	var walk func(v interface{}, path []string)
+	walk = func(v interface{}, path []string) {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			keys := make([]string, 0, len(v))
+			for k := range v {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				walk(v[k], append(path[:len(path):len(path)], k))
+			}
+		case []interface{}:
+			if len(path) == 0 {
+				return
+			}
+			elem := append(path[:len(path)-1:len(path)-1], path[len(path)-1]+"[]")
+			if path[len(path)-1] == "variants" {
+				// A variant is described like the operation it varies.
+				elem = path[: len(path)-1 : len(path)-1]
+			}
+			for _, e := range v {
+				walk(e, elem)
+			}
+		case float64, int, int64, json.Number:
+			for i, p := range patterns {
+				if matchPath(p, path) {
+					found[m.Fields[i].Path] = m.Fields[i].MetricSemantics
+					break
+				}
+			}
+		}
+	}
+	walk(v, nil)
+	return found
+}
+
+// matchPath reports whether the segments of a concrete field path match
+// those of a pattern, where "*" matches any key and "*[]" any key of an
+// array.
+func matchPath(pattern, path []string) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i, p := range pattern {
+		switch {
+		case p == path[i]:
+		case p == "*":
+			if strings.HasSuffix(path[i], "[]") {
+				return false
+			}
+		case p == "*[]":
+			if !strings.HasSuffix(path[i], "[]") {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package report
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// numericPaths collects the units.json path pattern of every numeric field
+// reachable from t.
+func numericPaths(t reflect.Type, path []string, seen map[reflect.Type]bool, out map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		out[strings.Join(path, ".")] = true
+	case reflect.Slice, reflect.Array:
+		if len(path) == 0 {
+			return
+		}
+		elem := append(path[:len(path)-1:len(path)-1], path[len(path)-1]+"[]")
+		numericPaths(t.Elem(), elem, seen, out)
+	case reflect.Map:
+		numericPaths(t.Elem(), append(path[:len(path):len(path)], "*"), seen, out)
+	case reflect.Struct:
+		if seen[t] {
+			return
+		}
+		seen[t] = true
+		defer delete(seen, t)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if f.Anonymous && name == "" {
+				numericPaths(f.Type, path, seen, out)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			numericPaths(f.Type, append(path[:len(path):len(path)], name), seen, out)
+		}
+	}
+}
+
+func TestUnitsCoverReport(t *testing.T) {
+	want := map[string]bool{}
+	numericPaths(reflect.TypeOf(Report{}), nil, map[reflect.Type]bool{}, want)
+	units := DefaultUnits()
+	got := map[string]bool{}
+	for _, f := range units.Fields {
+		if got[f.Path] {
+			t.Errorf("units.json describes %s twice", f.Path)
+		}
+		got[f.Path] = true
+		if _, ok := units.Units[f.Unit]; !ok && f.UnitField == "" {
+			t.Errorf("%s: unknown unit %q", f.Path, f.Unit)
+		}
+		if _, ok := units.Aggregations[f.Agg]; !ok {
+			t.Errorf("%s: unknown aggregation %q", f.Path, f.Agg)
+		}
+	}
+	var missing, stale []string
+	for p := range want {
+		if !got[p] {
+			missing = append(missing, p)
+		}
+	}
+	for p := range got {
+		if !want[p] {
+			stale = append(stale, p)
+		}
+	}
+	sort.Strings(missing)
+	sort.Strings(stale)
+	if len(missing) > 0 {
+		t.Errorf("units.json does not describe %v", missing)
+	}
+	if len(stale) > 0 {
+		t.Errorf("units.json describes fields a report does not have: %v", stale)
+	}
+}
+
+func TestSetSchemaVersion(t *testing.T) {
+	r := &Report{
+		SDKID: "aas-core3-golang",
+		Datasets: map[string]DatasetEntry{"wide": {Operations: map[string]OperationEntry{
+			"deserialize": {OperationID: "deserialize", MeanNs: 1500},
+		}}},
+	}
+	if err := SetSchemaVersion(r, SchemaVersion); err != nil {
+		t.Fatal(err)
+	}
+	if r.SchemaVersion != SchemaVersion {
+		t.Errorf("schema_version = %d, want %d", r.SchemaVersion, SchemaVersion)
+	}
+	want := MetricSemantics{Unit: "ns", Agg: "mean"}
+	if got := r.MetricsManifest["datasets.*.operations.*.mean_ns"]; got != want {
+		t.Errorf("mean_ns = %+v, want %+v", got, want)
+	}
+	if _, ok := r.MetricsManifest["datasets.*.operations.*.relative_error_pct"]; ok {
+		t.Error("the manifest describes relative_error_pct, which the report does not hold")
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if errs := validateMetricsManifest(doc); len(errs) > 0 {
+		t.Errorf("validateMetricsManifest(v3) = %v", errs)
+	}
+
+	if err := SetSchemaVersion(r, LegacySchemaVersion); err != nil {
+		t.Fatal(err)
+	}
+	if r.SchemaVersion != LegacySchemaVersion || r.MetricsManifest != nil {
+		t.Errorf("v2 report = version %d with manifest %v", r.SchemaVersion, r.MetricsManifest)
+	}
+	if err := SetSchemaVersion(r, 1); err == nil {
+		t.Error("SetSchemaVersion(1) succeeded")
+	}
+}
+
+func TestValidateMetricsManifest(t *testing.T) {
+	doc := map[string]interface{}{
+		"schema_version": 3.0,
+		"datasets": map[string]interface{}{"wide": map[string]interface{}{"operations": map[string]interface{}{
+			"deserialize": map[string]interface{}{"mean_ns": 1500.0, "median_ns": 1400.0},
+		}}},
+		"metrics_manifest": map[string]interface{}{
+			"schema_version":                  map[string]interface{}{"unit": "version", "agg": "value"},
+			"datasets.*.operations.*.mean_ns": map[string]interface{}{"unit": "furlongs", "agg": "mean"},
+		},
+	}
+	want := []string{
+		`metrics_manifest entry "datasets.*.operations.*.mean_ns" has unknown unit "furlongs"`,
+		"metrics_manifest does not describe datasets.*.operations.*.median_ns",
+	}
+	if got := validateMetricsManifest(doc); !reflect.DeepEqual(got, want) {
+		t.Errorf("validateMetricsManifest = %q, want %q", got, want)
+	}
+	delete(doc, "metrics_manifest")
+	if got := validateMetricsManifest(doc); len(got) != 1 {
+		t.Errorf("validateMetricsManifest without a manifest = %q", got)
+	}
+}
@@ -0,0 +1,173 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var camelBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// legacyOperationIDs maps dense or camel-case names used by older adapters.
+var legacyOperationIDs = map[string]string{
+	"deserializeXml": "deserialize_xml",
+	"serializeXml":   "serialize_xml",
+	"deserializexml": "deserialize_xml",
+	"serializexml":   "serialize_xml",
+	"aasxExtract":    "aasx_extract",
+	"aasxRepackage":  "aasx_repackage",
+	"aasxextract":    "aasx_extract",
+	"aasxrepackage":  "aasx_repackage",
+}
+
+// NormalizeOperationID converts any adapter's operation key (camelCase,
+// PascalCase, kebab-case or dense legacy forms) to the canonical snake_case
+// ID. An extension namespace is lower-cased and kept in front of the
+// normalized name. It matches canonical_operation_id in scripts/aggregate.py.
+func NormalizeOperationID(raw string) string {
+	if strings.Contains(raw, NamespaceSeparator) {
+		namespace, name := SplitOperationID(raw)
+		return strings.ToLower(namespace) + NamespaceSeparator + NormalizeOperationID(name)
+	}
+	if id, ok := legacyOperationIDs[raw]; ok {
+		return id
+	}
+	snake := camelBoundary.ReplaceAllString(raw, "${1}_${2}")
+	snake = strings.ToLower(strings.ReplaceAll(snake, "-", "_"))
+	if id, ok := legacyOperationIDs[snake]; ok {
+		return id
+	}
+	return snake
+}
+
+// requiredOperationFields must be present on every operation entry.
+var requiredOperationFields = []string{
+	"operation_id",
+	"operation_track",
+	"sample_count",
+	"measurement_semantics",
+	"failure_state",
+	"mean_ns",
+}
+
+// Validate checks report.json integrity and canonical operation naming. It
+// works on raw JSON so that missing fields are detected rather than zeroed,
+// and returns one message per problem found.
+func Validate(data []byte) []string {
+	var doc map[string]interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []string{fmt.Sprintf("failed to parse JSON: %v", err)}
+	}
+
+	datasets, ok := doc["datasets"].(map[string]interface{})
+	if !ok || len(datasets) == 0 {
+		return []string{"report must contain at least one dataset"}
+	}
+
+	errors := validateMetadata(doc["metadata"])
+	if version, _ := doc["schema_version"].(float64); version >= SchemaVersion {
+		errors = append(errors, validateMetricsManifest(doc)...)
+	}
+	opCount := 0
+	for _, datasetName := range sortedKeys(datasets) {
+		datasetEntry, ok := datasets[datasetName].(map[string]interface{})
+		if !ok {
+			errors = append(errors, fmt.Sprintf("dataset %q is not an object", datasetName))
+			continue
+		}
+		operations, ok := datasetEntry["operations"].(map[string]interface{})
+		if !ok || len(operations) == 0 {
+			errors = append(errors, fmt.Sprintf("dataset %q has no operations", datasetName))
+			continue
+		}
+
+		for _, opKey := range sortedKeys(operations) {
+			opCount++
+			canonical := NormalizeOperationID(opKey)
+			if opKey != canonical {
+				errors = append(errors, fmt.Sprintf(
+					"dataset %q contains non-canonical operation key %q (canonical: %q)",
+					datasetName, opKey, canonical))
+			}
+			if err := CheckOperationID(canonical); err != nil {
+				errors = append(errors, fmt.Sprintf("dataset %q: %v", datasetName, err))
+			}
+			opEntry, ok := operations[opKey].(map[string]interface{})
+			if !ok {
+				errors = append(errors, fmt.Sprintf("dataset %q operation %q is not an object", datasetName, opKey))
+				continue
+			}
+			if opID, present := opEntry["operation_id"]; present && opID != canonical {
+				errors = append(errors, fmt.Sprintf(
+					"dataset %q operation %q has mismatched operation_id=%v (expected %q)",
+					datasetName, opKey, opID, canonical))
+			}
+			var missing []string
+			for _, field := range requiredOperationFields {
+				if _, present := opEntry[field]; !present {
+					missing = append(missing, field)
+				}
+			}
+			if len(missing) > 0 {
+				errors = append(errors, fmt.Sprintf(
+					"dataset %q operation %q missing required fields: %s",
+					datasetName, opKey, strings.Join(missing, ", ")))
+			}
+		}
+	}
+
+	if opCount == 0 {
+		errors = append(errors, "report must contain at least one operation")
+	}
+	return errors
+}
+
+// validateMetricsManifest checks that the metrics_manifest of doc, a
+// report of schema version 3 or later, names known units and aggregations
+// and describes every numeric field that units.json knows.
+func validateMetricsManifest(doc map[string]interface{}) []string {
+	manifest, ok := doc["metrics_manifest"].(map[string]interface{})
+	if !ok {
+		return []string{"schema version 3 reports must contain a metrics_manifest object"}
+	}
+	units := DefaultUnits()
+	var errors []string
+	for _, path := range sortedKeys(manifest) {
+		entry, ok := manifest[path].(map[string]interface{})
+		if !ok {
+			errors = append(errors, fmt.Sprintf("metrics_manifest entry %q is not an object", path))
+			continue
+		}
+		unit, _ := entry["unit"].(string)
+		unitField, _ := entry["unit_field"].(string)
+		if _, known := units.Units[unit]; !known && unitField == "" {
+			errors = append(errors, fmt.Sprintf("metrics_manifest entry %q has unknown unit %q", path, unit))
+		}
+		agg, _ := entry["agg"].(string)
+		if _, known := units.Aggregations[agg]; !known {
+			errors = append(errors, fmt.Sprintf("metrics_manifest entry %q has unknown aggregation %q", path, agg))
+		}
+	}
+	var undescribed []string
+	for path := range units.Describe(doc) {
+		if _, ok := manifest[path]; !ok {
+			undescribed = append(undescribed, path)
+		}
+	}
+	sort.Strings(undescribed)
+	for _, path := range undescribed {
+		errors = append(errors, fmt.Sprintf("metrics_manifest does not describe %s", path))
+	}
+	return errors
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
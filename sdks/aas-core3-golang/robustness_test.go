@@ -0,0 +1,239 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Corruption classes of the deserialize_invalid inputs.
+const (
+	corruptMissingRequired = "missing_required"
+	corruptWrongType       = "wrong_type"
+	corruptTruncated       = "truncated"
+)
+
+// Outcomes of deserializing a corrupted input. Only outcomeRejected is
+// robust: accepting bad input or panicking on it fails the class.
+const (
+	outcomeRejected = "rejected"
+	outcomeAccepted = "accepted"
+	outcomePanicked = "panicked"
+)
+
+// corruptInput is one systematically corrupted document.
+type corruptInput struct {
+	format string // "json" or "xml"
+	class  string
+	raw    []byte
+}
+
+// decode deserializes the input in its format.
+func (c corruptInput) decode() error {
+	if c.format == "xml" {
+		_, err := deserializeXmlEnv(c.raw)
+		return err
+	}
+	_, err := deserializeEnv(c.raw)
+	return err
+}
+
+// probe decodes the input once, turning a panic into an outcome.
+func (c corruptInput) probe() (outcome, detail string) {
+	defer func() {
+		if r := recover(); r != nil {
+			outcome, detail = outcomePanicked, fmt.Sprint(r)
+		}
+	}()
+	if err := c.decode(); err != nil {
+		return outcomeRejected, err.Error()
+	}
+	return outcomeAccepted, ""
+}
+
+// corruptJSON derives the corrupted JSON inputs from a valid environment:
+// the first submodel without its id, the same id as a number, and the
+// document cut in half.
+func corruptJSON(raw []byte) ([]corruptInput, error) {
+	inputs := []corruptInput{{format: "json", class: corruptTruncated, raw: raw[:len(raw)/2]}}
+	for _, c := range []struct {
+		class  string
+		mutate func(sm map[string]interface{})
+	}{
+		{corruptMissingRequired, func(sm map[string]interface{}) { delete(sm, "id") }},
+		{corruptWrongType, func(sm map[string]interface{}) { sm["id"] = 42 }},
+	} {
+		var doc map[string]interface{}
+		if err := json.Unmarshal(raw, &doc); err != nil {
+			return nil, err
+		}
+		submodels, _ := doc["submodels"].([]interface{})
+		if len(submodels) == 0 {
+			continue
+		}
+		sm, ok := submodels[0].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		c.mutate(sm)
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return nil, err
+		}
+		inputs = append(inputs, corruptInput{format: "json", class: c.class, raw: data})
+	}
+	return inputs, nil
+}
+
+// corruptXML derives the corrupted XML inputs: the first submodel's <id>
+// removed, a valueType that is not an XSD type, and the document cut in
+// half. XML carries no JSON types, so an enumeration outside its literals
+// stands for a wrong type. Classes the document gives no handle for are
+// left out.
+func corruptXML(raw []byte) []corruptInput {
+	inputs := []corruptInput{{format: "xml", class: corruptTruncated, raw: raw[:len(raw)/2]}}
+	if sm := bytes.Index(raw, []byte("<submodel>")); sm >= 0 {
+		start := bytes.Index(raw[sm:], []byte("<id>"))
+		end := bytes.Index(raw[sm:], []byte("</id>"))
+		if start >= 0 && end > start {
+			data := append(append([]byte{}, raw[:sm+start]...), raw[sm+end+len("</id>"):]...)
+			inputs = append(inputs, corruptInput{format: "xml", class: corruptMissingRequired, raw: data})
+		}
+	}
+	if bytes.Contains(raw, []byte("<valueType>xs:")) {
+		data := bytes.Replace(raw, []byte("<valueType>xs:"), []byte("<valueType>xs:notAType"), 1)
+		inputs = append(inputs, corruptInput{format: "xml", class: corruptWrongType, raw: data})
+	}
+	return inputs
+}
+
+// robustnessEntry is the outcome of one corrupted input and, when it was
+// rejected, the cost of rejecting it. Input names the corpus file of a
+// fuzz input, of which a class has many per dataset.
+type robustnessEntry struct {
+	Dataset     string  `json:"dataset"`
+	Format      string  `json:"format"`
+	Class       string  `json:"class"`
+	Input       string  `json:"input,omitempty"`
+	Outcome     string  `json:"outcome"`
+	Detail      string  `json:"detail,omitempty"`
+	NsPerOp     float64 `json:"ns_per_op,omitempty"`
+	BytesPerOp  int64   `json:"bytes_per_op,omitempty"`
+	AllocsPerOp int64   `json:"allocs_per_op,omitempty"`
+}
+
+// rejectionTimeTarget is how long timeRejection keeps rejecting one
+// input before it takes the mean.
+const rejectionTimeTarget = 100 * time.Millisecond
+
+// timeRejection times rejecting one input on its own, so that each
+// corruption class gets a timing of its own next to its verdict. The
+// batch doubles until it takes rejectionTimeTarget. testing.Benchmark
+// cannot be used here: it deadlocks inside a running benchmark.
+func timeRejection(in corruptInput, e *robustnessEntry) {
+	var before, after runtime.MemStats
+	for n := 1; ; n *= 2 {
+		runtime.ReadMemStats(&before)
+		start := time.Now()
+		for i := 0; i < n; i++ {
+			_ = in.decode()
+		}
+		elapsed := time.Since(start)
+		runtime.ReadMemStats(&after)
+		if elapsed >= rejectionTimeTarget || n >= 1<<20 {
+			e.NsPerOp = float64(elapsed.Nanoseconds()) / float64(n)
+			e.BytesPerOp = int64((after.TotalAlloc - before.TotalAlloc) / uint64(n))
+			e.AllocsPerOp = int64((after.Mallocs - before.Mallocs) / uint64(n))
+			return
+		}
+	}
+}
+
+// robustnessFile is the schema of robustness.json.
+type robustnessFile struct {
+	Entries []robustnessEntry `json:"entries"`
+}
+
+// robustnessRecorder collects the outcomes probed by deserialize_invalid.
+type robustnessRecorder struct {
+	mu      sync.Mutex
+	entries map[string]robustnessEntry
+}
+
+var globalRobustness = &robustnessRecorder{entries: make(map[string]robustnessEntry)}
+
+// record keeps one outcome per dataset, format, class and input;
+// benchmark re-runs with -count overwrite it.
+func (r *robustnessRecorder) record(e robustnessEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[e.Dataset+"/"+e.Format+"/"+e.Class+"/"+e.Input] = e
+}
+
+func (r *robustnessRecorder) snapshot() robustnessFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := robustnessFile{Entries: make([]robustnessEntry, 0, len(r.entries))}
+	keys := make([]string, 0, len(r.entries))
+	for k := range r.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		f.Entries = append(f.Entries, r.entries[k])
+	}
+	return f
+}
+
+// BenchmarkDeserializeInvalid benchmarks rejecting corrupted JSON and XML
+// documents, one document per iteration, cycling through the dataset's
+// corruption classes. Each input is probed once first and its outcome
+// recorded in robustness.json; inputs that are accepted or panic are
+// reported there and left out of the timing. Every rejected input is also
+// timed on its own, and that per-class timing is recorded with its
+// outcome, since the cycle mixes all classes into one figure.
+func BenchmarkDeserializeInvalid(b *testing.B) {
+	selectOperation(b, "deserialize_invalid")
+	xmlInputs := make(map[string][]byte)
+	for _, in := range datasetXmlInputs(b) {
+		xmlInputs[in.name] = in.raw
+	}
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		inputs, err := corruptJSON(loadRawJSON(b, f))
+		if err != nil {
+			b.Fatalf("Setup failed corrupting %s: %v", name, err)
+		}
+		if raw, ok := xmlInputs[name]; ok {
+			inputs = append(inputs, corruptXML(raw)...)
+		}
+		var rejected []corruptInput
+		for _, in := range inputs {
+			outcome, detail := in.probe()
+			entry := robustnessEntry{Dataset: name, Format: in.format, Class: in.class, Outcome: outcome, Detail: detail}
+			if outcome == outcomeRejected {
+				timeRejection(in, &entry)
+				rejected = append(rejected, in)
+			}
+			globalRobustness.record(entry)
+		}
+		if len(rejected) == 0 {
+			continue
+		}
+		runObserved(b, "deserialize_invalid", name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := rejected[i%len(rejected)].decode(); err == nil {
+					b.Fatal("corrupted input was accepted")
+				}
+			}
+		})
+	}
+	globalMemStats.Groups["deserialize_invalid"] = captureMemSnapshot()
+	globalHeap.writeProfile("deserialize_invalid")
+}
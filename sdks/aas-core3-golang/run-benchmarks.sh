@@ -11,26 +11,25 @@ mkdir -p "$OUTPUT_DIR"
 # Convert to absolute paths before cd
 DATASETS_DIR="$(cd "$DATASETS_DIR" && pwd)"
 OUTPUT_DIR="$(cd "$OUTPUT_DIR" && pwd)"
-export DATASETS_DIR
-export OUTPUT_DIR
 
 cd "$SCRIPT_DIR"
 
 # Download pinned module dependencies from go.mod/go.sum
 go mod download
 
-# Run Go benchmarks with JSON output
-# -count=5 for statistical significance, -benchmem for allocation stats
-# OUTPUT_DIR is exported so TestMain can write memory_stats.json there
-go test -bench=. -benchmem -count=5 -json -timeout=30m ./... > bench_raw.json
-
-# Convert Go benchmark JSON to report.json
-# Pass memory_stats.json as optional third arg for SRQ-2 memory enrichment
-MEMORY_STATS="$OUTPUT_DIR/memory_stats.json"
-if [ -f "$MEMORY_STATS" ]; then
-    go run emit_report.go bench_raw.json "$OUTPUT_DIR/report.json" "$MEMORY_STATS"
-else
-    go run emit_report.go bench_raw.json "$OUTPUT_DIR/report.json"
-fi
+# Run Go benchmarks and convert the go test -json output to report.json.
+# -count=5 for statistical significance; bench_raw.json and the harness side
+# channels (memory_stats.json, events.json) are kept next to report.json.
+# Set SERVER_CONTAINERS=<name>[,<name>...] to watch server containers for restarts;
+# they are checked with docker inspect between sub-benchmarks, never during one.
+# Set WASM_RUNTIME=node or wasmtime to benchmark the WebAssembly build instead
+# (sdk_id aas-core3-golang-wasm). Set FUZZ_CORPUS=<datasets>/fuzz to also probe
+# the deserializer with the fuzz corpus (datasets/generate.py --fuzz-corpus).
+go run ./cmd/aasbench run \
+    --datasets "$DATASETS_DIR" \
+    --output "$OUTPUT_DIR" \
+    --count 5 \
+    ${WASM_RUNTIME:+--wasm "$WASM_RUNTIME"} \
+    ${FUZZ_CORPUS:+--fuzz-corpus "$FUZZ_CORPUS"}
 
 echo "Report written to $OUTPUT_DIR/report.json"
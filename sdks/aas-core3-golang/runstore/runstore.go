@@ -0,0 +1,192 @@
+// Package runstore keeps the runs of aasbench run apart under one results
+// directory, so that repeated runs do not overwrite each other and tooling
+// can find earlier ones:
+//
+//	<root>/runs/<timestamp>-<run id>/   the output directory of one run
+//	<root>/latest                       symlink to the newest completed run
+//
+// It also lists the runs and prunes them by a retention policy.
+package runstore
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+const (
+	// RunsDir holds one directory per run under the results root.
+	RunsDir = "runs"
+	// LatestLink is the symlink under the results root to the newest
+	// completed run.
+	LatestLink = "latest"
+)
+
+// stampLayout is the UTC start time a run directory's name begins with,
+// the layout of logging.NewRunID's prefix.
+const stampLayout = "20060102T150405Z"
+
+var (
+	stamped = regexp.MustCompile(`^\d{8}T\d{6}Z(-|$)`)
+	unsafe  = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// DirName returns the name of the directory of the run with ID runID that
+// started at started: the ID itself when it already begins with its start
+// time, as generated IDs do, and the start time and the ID otherwise, so
+// the names sort by start time.
+func DirName(runID string, started time.Time) string {
+	if stamped.MatchString(runID) {
+		return unsafe.ReplaceAllString(runID, "_")
+	}
+	name := started.UTC().Format(stampLayout)
+	if runID != "" {
+		name += "-" + unsafe.ReplaceAllString(runID, "_")
+	}
+	return name
+}
+
+// Create makes the directory of a new run under root and returns its path.
+// It fails rather than reuse the directory of an earlier run.
+func Create(root, runID string, started time.Time) (string, error) {
+	runs := filepath.Join(root, RunsDir)
+	if err := os.MkdirAll(runs, 0755); err != nil {
+		return "", err
+	}
+	dir := filepath.Join(runs, DirName(runID, started))
+	if err := os.Mkdir(dir, 0755); err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return "", fmt.Errorf("run directory %s already exists; give the run another ID", dir)
+		}
+		return "", err
+	}
+	return dir, nil
+}
+
+// SetLatest points root's latest symlink at dir, a run directory under
+// root. The link is relative, so the results directory can be moved or
+// archived whole, and is replaced atomically.
+func SetLatest(root, dir string) error {
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return err
+	}
+	link := filepath.Join(root, LatestLink)
+	if fi, err := os.Lstat(link); err == nil && fi.Mode()&os.ModeSymlink == 0 {
+		return fmt.Errorf("%s exists and is not a symlink", link)
+	}
+	tmp := filepath.Join(root, fmt.Sprintf(".%s-%d", LatestLink, os.Getpid()))
+	os.Remove(tmp)
+	if err := os.Symlink(filepath.ToSlash(rel), tmp); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, link); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// Run is one run directory under a results root.
+type Run struct {
+	// Name is the directory's name under RunsDir.
+	Name    string    `json:"name"`
+	Dir     string    `json:"dir"`
+	Started time.Time `json:"started"`
+	// Complete is whether the run got as far as writing its report.
+	Complete bool `json:"complete"`
+	// Latest is whether root's latest symlink points at the run.
+	Latest bool `json:"latest"`
+}
+
+// List returns the runs under root, oldest first. A root without runs
+// has none. A directory whose name does not begin with a start time is
+// dated by its modification time.
+func List(root string) ([]Run, error) {
+	entries, err := os.ReadDir(filepath.Join(root, RunsDir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	latest, _ := os.Readlink(filepath.Join(root, LatestLink))
+	var runs []Run
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		dir := filepath.Join(root, RunsDir, e.Name())
+		r := Run{Name: e.Name(), Dir: dir}
+		if stamped.MatchString(e.Name()) {
+			r.Started, _ = time.Parse(stampLayout, e.Name()[:len(stampLayout)])
+		}
+		if r.Started.IsZero() {
+			fi, err := e.Info()
+			if err != nil {
+				return nil, err
+			}
+			r.Started = fi.ModTime().UTC()
+		}
+		if _, err := os.Stat(filepath.Join(dir, report.ReportFile)); err == nil {
+			r.Complete = true
+		}
+		r.Latest = latest != "" && filepath.Clean(filepath.Join(root, filepath.FromSlash(latest))) == filepath.Clean(dir)
+		runs = append(runs, r)
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		if !runs[i].Started.Equal(runs[j].Started) {
+			return runs[i].Started.Before(runs[j].Started)
+		}
+		return runs[i].Name < runs[j].Name
+	})
+	return runs, nil
+}
+
+// Policy is how many runs, and how old, a results root retains. A zero
+// field does not limit.
+type Policy struct {
+	// Keep is how many of the newest runs are retained.
+	Keep int
+	// MaxAge is the age beyond which runs are removed.
+	MaxAge time.Duration
+}
+
+// Expired returns the runs of runs, as List returns them, that p does not
+// retain at now. The latest run is always retained.
+func (p Policy) Expired(runs []Run, now time.Time) []Run {
+	var expired []Run
+	for i, r := range runs {
+		if r.Latest {
+			continue
+		}
+		tooMany := p.Keep > 0 && len(runs)-i > p.Keep
+		tooOld := p.MaxAge > 0 && now.Sub(r.Started) > p.MaxAge
+		if tooMany || tooOld {
+			expired = append(expired, r)
+		}
+	}
+	return expired
+}
+
+// Prune removes the runs under root that p does not retain at now and
+// returns them.
+func Prune(root string, p Policy, now time.Time) ([]Run, error) {
+	runs, err := List(root)
+	if err != nil {
+		return nil, err
+	}
+	expired := p.Expired(runs, now)
+	for i, r := range expired {
+		if err := os.RemoveAll(r.Dir); err != nil {
+			return expired[:i], err
+		}
+	}
+	return expired, nil
+}
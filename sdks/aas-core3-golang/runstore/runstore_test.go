@@ -0,0 +1,111 @@
+package runstore
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+var started = time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+
+func TestDirNameSortsByStartTime(t *testing.T) {
+	for _, tc := range []struct{ id, want string }{
+		{"20261016T030000Z-3f9a1c", "20261016T030000Z-3f9a1c"},
+		{"nightly 42/arm64", "20261016T030000Z-nightly_42_arm64"},
+		{"", "20261016T030000Z"},
+	} {
+		if got := DirName(tc.id, started); got != tc.want {
+			t.Errorf("DirName(%q) = %q, want %q", tc.id, got, tc.want)
+		}
+	}
+}
+
+// addRun creates a run under root that started at at and, when complete,
+// wrote its report.
+func addRun(t *testing.T, root string, at time.Time, complete bool) string {
+	t.Helper()
+	dir, err := Create(root, "", at)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if complete {
+		if err := os.WriteFile(filepath.Join(dir, report.ReportFile), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCreateRefusesToReuseARun(t *testing.T) {
+	root := t.TempDir()
+	addRun(t, root, started, true)
+	if _, err := Create(root, "", started); err == nil {
+		t.Error("want an error for a run directory that exists")
+	}
+}
+
+func TestListFollowsLatest(t *testing.T) {
+	root := t.TempDir()
+	first := addRun(t, root, started, true)
+	second := addRun(t, root, started.Add(time.Hour), true)
+	addRun(t, root, started.Add(2*time.Hour), false)
+	if err := SetLatest(root, first); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetLatest(root, second); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(root, LatestLink, report.ReportFile)); err != nil || string(data) != "{}" {
+		t.Errorf("latest/report.json = %q, %v", data, err)
+	}
+
+	runs, err := List(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("runs = %+v, want 3", runs)
+	}
+	for i, want := range []struct{ complete, latest bool }{{true, false}, {true, true}, {false, false}} {
+		if runs[i].Complete != want.complete || runs[i].Latest != want.latest {
+			t.Errorf("run %d = %+v, want complete %v latest %v", i, runs[i], want.complete, want.latest)
+		}
+	}
+	if !runs[0].Started.Equal(started) {
+		t.Errorf("first run started %v, want %v", runs[0].Started, started)
+	}
+}
+
+func TestPruneKeepsNewestAndLatest(t *testing.T) {
+	root := t.TempDir()
+	var dirs []string
+	for i := range 5 {
+		dirs = append(dirs, addRun(t, root, started.Add(time.Duration(i)*24*time.Hour), true))
+	}
+	// The latest run is retained even when the policy would drop it.
+	if err := SetLatest(root, dirs[0]); err != nil {
+		t.Fatal(err)
+	}
+	now := started.Add(4 * 24 * time.Hour)
+	removed, err := Prune(root, Policy{Keep: 3}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0].Dir != dirs[1] {
+		t.Errorf("removed %+v, want only %s", removed, dirs[1])
+	}
+	removed, err = Prune(root, Policy{MaxAge: 36 * time.Hour}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 || removed[0].Dir != dirs[2] {
+		t.Errorf("removed %+v, want only %s", removed, dirs[2])
+	}
+	runs, _ := List(root)
+	if len(runs) != 3 {
+		t.Errorf("%d runs left, want 3", len(runs))
+	}
+}
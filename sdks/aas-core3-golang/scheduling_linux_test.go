@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// cpuMask is a cpu_set_t large enough for cpus.
+func cpuMask(cpus []int) []uint64 {
+	words := 16 // CPU_SETSIZE of 1024
+	for _, cpu := range cpus {
+		if cpu/64 >= words {
+			words = cpu/64 + 1
+		}
+	}
+	mask := make([]uint64, words)
+	for _, cpu := range cpus {
+		mask[cpu/64] |= 1 << (uint(cpu) % 64)
+	}
+	return mask
+}
+
+// setAffinity pins every thread of the process to cpus. sched_setaffinity
+// applies to one thread, so each task in /proc/self/task is pinned; threads
+// the runtime starts later inherit the mask of the thread creating them.
+func setAffinity(cpus []int) error {
+	mask := cpuMask(cpus)
+	tasks, err := os.ReadDir("/proc/self/task")
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		_, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_SETAFFINITY,
+			uintptr(tid), uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+		if errno != 0 && errno != syscall.ESRCH { // ESRCH: the thread exited
+			return fmt.Errorf("sched_setaffinity: %w", errno)
+		}
+	}
+	return nil
+}
+
+// getAffinity returns the CPUs the calling thread may run on.
+func getAffinity() ([]int, error) {
+	mask := cpuMask(nil)
+	n, _, errno := syscall.RawSyscall(syscall.SYS_SCHED_GETAFFINITY,
+		0, uintptr(len(mask)*8), uintptr(unsafe.Pointer(&mask[0])))
+	if errno != 0 {
+		return nil, fmt.Errorf("sched_getaffinity: %w", errno)
+	}
+	var cpus []int
+	for i := 0; i < int(n)/8; i++ {
+		for bit := 0; bit < 64; bit++ {
+			if mask[i]&(1<<uint(bit)) != 0 {
+				cpus = append(cpus, i*64+bit)
+			}
+		}
+	}
+	return cpus, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package main
+
+import "errors"
+
+func setAffinity(cpus []int) error {
+	return errors.New("CPU affinity needs Linux sched_setaffinity")
+}
+
+func getAffinity() ([]int, error) {
+	return nil, errors.New("CPU affinity needs Linux sched_getaffinity")
+}
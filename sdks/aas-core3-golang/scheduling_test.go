@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schedulingFile is the schema of scheduling.json: the GOMAXPROCS the
+// benchmarks ran with and the CPUs the process was allowed on, so a report
+// records how it was pinned.
+type schedulingFile struct {
+	GOMAXPROCS int `json:"gomaxprocs"`
+	// CPUAffinity is the effective affinity as a CPU list ("0,2-3"), empty
+	// where the platform cannot report it.
+	CPUAffinity string `json:"cpu_affinity,omitempty"`
+	// Pinned is true when CPU_AFFINITY was requested and applied.
+	Pinned bool `json:"pinned"`
+}
+
+// applyScheduling pins the process to the CPUs in CPU_AFFINITY (a list such
+// as "0,2-3"; Linux only) and sets GOMAXPROCS to BENCH_GOMAXPROCS. Pinning
+// without BENCH_GOMAXPROCS sets GOMAXPROCS to the number of pinned CPUs,
+// as the runtime would have chosen had the affinity been set before it
+// started. It must run before m.Run, which fixes the benchmark CPU list.
+func applyScheduling() (schedulingFile, error) {
+	var s schedulingFile
+	if list := os.Getenv("CPU_AFFINITY"); list != "" {
+		cpus, err := parseCPUList(list)
+		if err != nil {
+			return s, fmt.Errorf("CPU_AFFINITY: %w", err)
+		}
+		if err := setAffinity(cpus); err != nil {
+			return s, fmt.Errorf("pin to CPUs %s: %w", list, err)
+		}
+		runtime.GOMAXPROCS(len(cpus))
+		s.Pinned = true
+	}
+	if v := os.Getenv("BENCH_GOMAXPROCS"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return s, fmt.Errorf("BENCH_GOMAXPROCS: want a positive integer, got %q", v)
+		}
+		runtime.GOMAXPROCS(n)
+	}
+	s.GOMAXPROCS = runtime.GOMAXPROCS(0)
+	if cpus, err := getAffinity(); err == nil {
+		s.CPUAffinity = formatCPUList(cpus)
+	}
+	return s, nil
+}
+
+// parseCPUList parses a Linux CPU list: comma-separated CPUs and inclusive
+// ranges.
+func parseCPUList(list string) ([]int, error) {
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil || first < 0 {
+			return nil, fmt.Errorf("invalid CPU %q in %q", part, list)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil || last < first {
+				return nil, fmt.Errorf("invalid CPU range %q in %q", part, list)
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			seen[cpu] = true
+		}
+	}
+	cpus := make([]int, 0, len(seen))
+	for cpu := range seen {
+		cpus = append(cpus, cpu)
+	}
+	sort.Ints(cpus)
+	return cpus, nil
+}
+
+// formatCPUList writes sorted CPUs as a CPU list, collapsing runs into
+// ranges.
+func formatCPUList(cpus []int) string {
+	var parts []string
+	for i := 0; i < len(cpus); {
+		j := i
+		for j+1 < len(cpus) && cpus[j+1] == cpus[j]+1 {
+			j++
+		}
+		if j == i {
+			parts = append(parts, strconv.Itoa(cpus[i]))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", cpus[i], cpus[j]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
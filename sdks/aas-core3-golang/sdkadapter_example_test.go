@@ -0,0 +1,94 @@
+//go:build example_sdk_adapter
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// An example SDK adapter, built only with GOFLAGS=-tags=example_sdk_adapter.
+// It stands in for a fork or a competing library: an untyped implementation
+// on encoding/json maps. A real adapter follows the same shape in a file
+// with its own build tag, and its module in go.mod.
+func init() {
+	useSDKAdapter(jsonMapAdapter{})
+}
+
+// jsonMapAdapter keeps an environment as the map encoding/json decodes.
+type jsonMapAdapter struct{}
+
+func (jsonMapAdapter) SDKID() string  { return "example-jsonmap-golang" }
+func (jsonMapAdapter) Module() string { return stdModule }
+
+func (jsonMapAdapter) Deserialize(raw []byte) (interface{}, error) {
+	var env map[string]interface{}
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, err
+	}
+	if env == nil {
+		return nil, fmt.Errorf("environment is not a JSON object")
+	}
+	return env, nil
+}
+
+func (jsonMapAdapter) Serialize(env interface{}) ([]byte, error) {
+	return json.Marshal(env)
+}
+
+// identifiables are the model types that must carry an id.
+var identifiables = map[string]bool{"AssetAdministrationShell": true, "Submodel": true, "ConceptDescription": true}
+
+// Validate checks the two constraints the map representation can: every
+// identifiable has an id, and every Property a valueType.
+func (jsonMapAdapter) Validate(env interface{}) int {
+	violations := 0
+	walkElements(env, func(el map[string]interface{}, modelType string) {
+		if identifiables[modelType] && el["id"] == nil {
+			violations++
+		}
+		if modelType == "Property" && el["valueType"] == nil {
+			violations++
+		}
+	})
+	return violations
+}
+
+func (jsonMapAdapter) Traverse(env interface{}) int {
+	count := 0
+	walkElements(env, func(map[string]interface{}, string) { count++ })
+	return count
+}
+
+func (jsonMapAdapter) Update(env interface{}) error {
+	var touched []map[string]interface{}
+	var originals []string
+	walkElements(env, func(el map[string]interface{}, modelType string) {
+		if v, ok := el["value"].(string); ok && modelType == "Property" {
+			el["value"] = v + "_updated"
+			touched = append(touched, el)
+			originals = append(originals, v)
+		}
+	})
+	for i, el := range touched {
+		el["value"] = originals[i]
+	}
+	return nil
+}
+
+// walkElements calls fn on every object of v that has a modelType.
+func walkElements(v interface{}, fn func(el map[string]interface{}, modelType string)) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if modelType, ok := v["modelType"].(string); ok {
+			fn(v, modelType)
+		}
+		for _, child := range v {
+			walkElements(child, fn)
+		}
+	case []interface{}:
+		for _, child := range v {
+			walkElements(child, fn)
+		}
+	}
+}
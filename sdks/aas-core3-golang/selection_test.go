@@ -0,0 +1,83 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Selection flags of the harness, finer than -bench: go test -bench=.
+// -operations=xml -datasets=wide runs the xml track on wide only.
+var (
+	operationsFlag = flag.String("operations", "", "comma-separated operation IDs or tracks (core, validation, xml, aasx) to run, with the operations they need; empty runs all")
+	datasetsFlag   = flag.String("datasets", "", "comma-separated dataset names to run; empty runs all")
+)
+
+// benchSelection is what the selection flags let run. A nil set allows
+// everything.
+type benchSelection struct {
+	operations map[string]bool
+	datasets   map[string]bool
+}
+
+var globalSelection benchSelection
+
+// newSelection resolves the flag values against the default manifest and
+// the registered operations, so a registered operation's needs count too.
+func newSelection(operations, datasets string) (benchSelection, error) {
+	var s benchSelection
+	if terms := splitTerms(operations); len(terms) > 0 {
+		m := report.DefaultOperations()
+		for _, op := range registeredOperations().Operations {
+			m[op.ID] = op
+		}
+		selected, err := m.Select(terms)
+		if err != nil {
+			return s, fmt.Errorf("-operations: %w", err)
+		}
+		s.operations = selected
+	}
+	if names := splitTerms(datasets); len(names) > 0 {
+		s.datasets = make(map[string]bool, len(names))
+		for _, name := range names {
+			s.datasets[name] = true
+		}
+	}
+	return s, nil
+}
+
+func splitTerms(v string) []string {
+	var terms []string
+	for _, term := range strings.Split(v, ",") {
+		if term = strings.TrimSpace(term); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+func (s benchSelection) operation(id string) bool {
+	return s.operations == nil || s.operations[id]
+}
+
+// dataset reports whether name runs. Micro track inputs are built in and
+// belong to their operation, so only the operation filter applies to them.
+func (s benchSelection) dataset(name string) bool {
+	return s.datasets == nil || s.datasets[name] || strings.HasPrefix(name, report.MicroDatasetPrefix)
+}
+
+// selectOperation skips a benchmark function, before its setup, when
+// -operations does not select its operation or the SDK adapter in use does
+// not implement it.
+func selectOperation(b *testing.B, id string) {
+	b.Helper()
+	if !globalSelection.operation(id) {
+		b.Skipf("%s is not selected by -operations", id)
+	}
+	if !adapterSupports(id) {
+		b.Skipf("%s is specific to aas-core3; SDK adapter %s runs the core operations only", id, sdkAdapter.SDKID())
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+)
+
+// The generated datasets carry no semanticIds, so setup tags every
+// submodel element: every eighth with a semanticId of its own
+// (rareSemanticIDPrefix followed by its index in descent order), all the
+// others with commonSemanticID. find_by_semantic_id then looks up the
+// common ID, which seven in eight elements carry, and
+// find_by_semantic_id_miss the first rare one, which only one element
+// carries and which shares its prefix with every other rare ID.
+const (
+	commonSemanticID     = "urn:benchmark:semantic:common"
+	rareSemanticIDPrefix = "urn:benchmark:semantic:rare:"
+	rareSemanticIDEvery  = 8
+)
+
+// semanticDatasets are the datasets the semanticId lookups run on: many
+// flat elements, and a realistic mix.
+var semanticDatasets = map[string]bool{"wide": true, "mixed": true}
+
+// tagSemanticIDs sets the semanticIds find_by_semantic_id looks up and
+// returns how many elements it tagged.
+func tagSemanticIDs(env aastypes.IEnvironment) int {
+	n := 0
+	env.Descend(func(node aastypes.IClass) bool {
+		el, ok := node.(aastypes.ISubmodelElement)
+		if !ok {
+			return false // continue descending
+		}
+		id := commonSemanticID
+		if n%rareSemanticIDEvery == 0 {
+			id = rareSemanticIDPrefix + strconv.Itoa(n)
+		}
+		el.SetSemanticID(aastypes.NewReference(
+			aastypes.ReferenceTypesExternalReference,
+			[]aastypes.IKey{aastypes.NewKey(aastypes.KeyTypesGlobalReference, id)},
+		))
+		n++
+		return false // continue descending
+	})
+	return n
+}
+
+// findBySemanticID scans env for every element whose semanticId is the
+// single global reference target, as an integrator looking up the
+// elements of a concept does.
+func findBySemanticID(env aastypes.IEnvironment, target string) []aastypes.IHasSemantics {
+	var found []aastypes.IHasSemantics
+	env.Descend(func(node aastypes.IClass) bool {
+		hs, ok := node.(aastypes.IHasSemantics)
+		if !ok {
+			return false // continue descending
+		}
+		if ref := hs.SemanticID(); ref != nil {
+			if keys := ref.Keys(); len(keys) == 1 && keys[0].Value() == target {
+				found = append(found, hs)
+			}
+		}
+		return false // continue descending
+	})
+	return found
+}
+
+// benchmarkFindBySemanticID runs find_by_semantic_id for target on the
+// semantic datasets, recorded as operation. want is how many elements
+// target must find, given the number tagged.
+func benchmarkFindBySemanticID(b *testing.B, operation, target string, want func(tagged int) int) {
+	selectOperation(b, operation)
+	for _, f := range datasetFiles(b) {
+		name := datasetName(f)
+		if !semanticDatasets[name] {
+			continue
+		}
+		env, err := deserializeEnv(loadRawJSON(b, f))
+		if err != nil {
+			b.Fatalf("Setup failed for %s: %v", name, err)
+		}
+		tagged := tagSemanticIDs(env)
+		if got := len(findBySemanticID(env, target)); got != want(tagged) {
+			b.Fatalf("Setup failed for %s: %s found %d of %d tagged elements, want %d", name, target, got, tagged, want(tagged))
+		}
+		runObserved(b, operation, name, func(b *testing.B) {
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				found := findBySemanticID(env, target)
+				_ = found
+			}
+		})
+	}
+	globalMemStats.Groups[operation] = captureMemSnapshot()
+	globalHeap.writeProfile(operation)
+}
+
+// BenchmarkFindBySemanticId benchmarks the hit-heavy lookup: most
+// elements carry the semanticId looked up.
+func BenchmarkFindBySemanticId(b *testing.B) {
+	benchmarkFindBySemanticID(b, "find_by_semantic_id", commonSemanticID, func(tagged int) int {
+		return tagged - (tagged+rareSemanticIDEvery-1)/rareSemanticIDEvery
+	})
+}
+
+// BenchmarkFindBySemanticIdMiss benchmarks the miss-heavy lookup: all
+// but one element carry another semanticId.
+func BenchmarkFindBySemanticIdMiss(b *testing.B) {
+	benchmarkFindBySemanticID(b, "find_by_semantic_id_miss", rareSemanticIDPrefix+"0", func(tagged int) int {
+		return min(tagged, 1)
+	})
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// expectedDatasets are the datasets every operation should run on; they
+// are the ones datasets/generate.py always writes.
+var expectedDatasets = []string{"wide", "deep", "mixed"}
+
+// xmlOperations read XML input, which may come from an .xml fixture or be
+// derived from the dataset's JSON.
+var xmlOperations = map[string]bool{"deserialize_xml": true, "serialize_xml": true, "deserialize_xml_nsheavy": true}
+
+// narrowOperations run on some of the expected datasets only, by design.
+var narrowOperations = map[string]map[string]bool{
+	"find_by_semantic_id":      semanticDatasets,
+	"find_by_semantic_id_miss": semanticDatasets,
+	// startup deserializes its own built-in document.
+	"startup": {},
+}
+
+// skippedEntry is one expected dataset an operation could not run on.
+type skippedEntry struct {
+	Operation string `json:"operation"`
+	Dataset   string `json:"dataset"`
+	Reason    string `json:"reason"`
+}
+
+// skippedFile is the schema of skipped.json.
+type skippedFile struct {
+	Entries []skippedEntry `json:"entries"`
+}
+
+// skipRecorder remembers which datasets each operation ran on, so that the
+// report can list expected datasets that were missing instead of silently
+// leaving the operation out.
+type skipRecorder struct {
+	mu  sync.Mutex
+	ran map[string]map[string]bool
+}
+
+var globalSkipped = &skipRecorder{ran: make(map[string]map[string]bool)}
+
+// observe records that operation ran on dataset. Micro track operations
+// have built-in inputs, so no dataset is expected of them.
+func (r *skipRecorder) observe(operation, dataset string) {
+	if strings.HasPrefix(dataset, report.MicroDatasetPrefix) {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.ran[operation] == nil {
+		r.ran[operation] = make(map[string]bool)
+	}
+	r.ran[operation][dataset] = true
+}
+
+// snapshot lists, for every operation that ran at all, the expected
+// datasets it did not run on. Operations excluded by -bench or -operations
+// never ran and are not listed, nor are datasets excluded by -datasets.
+func (r *skipRecorder) snapshot() skippedFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f := skippedFile{Entries: []skippedEntry{}}
+	for operation, datasets := range r.ran {
+		for _, ds := range expectedDatasets {
+			if datasets[ds] || !globalSelection.dataset(ds) {
+				continue
+			}
+			if only, ok := narrowOperations[operation]; ok && !only[ds] {
+				continue
+			}
+			input := ds + ".json"
+			if xmlOperations[operation] {
+				input = fmt.Sprintf("%s.xml or %s.json", ds, ds)
+			}
+			f.Entries = append(f.Entries, skippedEntry{
+				Operation: operation,
+				Dataset:   ds,
+				Reason:    fmt.Sprintf("no %s in the datasets directory", input),
+			})
+		}
+	}
+	sort.Slice(f.Entries, func(i, j int) bool {
+		a, b := f.Entries[i], f.Entries[j]
+		if a.Operation != b.Operation {
+			return a.Operation < b.Operation
+		}
+		return a.Dataset < b.Dataset
+	})
+	return f
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// soakRunner loops one dataset through deserialize, update and serialize
+// after the benchmarks, as a long-lived process would, and samples the
+// live heap as it goes. Enabled with SOAK_DURATION (e.g. 10m);
+// SOAK_INTERVAL is the time between samples (default 10s) and
+// SOAK_DATASET the dataset (default mixed).
+type soakRunner struct {
+	duration time.Duration
+	interval time.Duration
+	dataset  string
+}
+
+var globalSoak = newSoakRunner()
+
+func newSoakRunner() *soakRunner {
+	s := &soakRunner{interval: 10 * time.Second, dataset: "mixed"}
+	if v := os.Getenv("SOAK_DURATION"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			s.duration = d
+		} else {
+			slog.Warn("invalid SOAK_DURATION", "value", v, "err", err)
+		}
+	}
+	if v := os.Getenv("SOAK_INTERVAL"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			s.interval = d
+		} else {
+			slog.Warn("invalid SOAK_INTERVAL", "value", v, "err", err)
+		}
+	}
+	if v := os.Getenv("SOAK_DATASET"); v != "" {
+		s.dataset = v
+	}
+	return s
+}
+
+// run soaks the dataset for the configured duration and returns its
+// samples with the fitted growth, or nil when soaking is disabled or the
+// dataset cannot be loaded. Every sample follows a forced collection, so
+// garbage not yet collected does not pass for growth; the collections
+// are few enough not to slow the loop noticeably.
+func (s *soakRunner) run() *report.Soak {
+	if s.duration <= 0 {
+		return nil
+	}
+	raw, err := os.ReadFile(filepath.Join(os.Getenv("DATASETS_DIR"), s.dataset+".json"))
+	if err != nil {
+		slog.Error("soak dataset unreadable", "dataset", s.dataset, "err", err)
+		return nil
+	}
+	result := &report.Soak{Dataset: s.dataset, IntervalS: s.interval.Seconds()}
+	sample := func(elapsed time.Duration) {
+		runtime.GC()
+		result.Samples = append(result.Samples, report.SoakSample{
+			ElapsedS:   elapsed.Seconds(),
+			HeapBytes:  liveHeapBytes(),
+			Goroutines: runtime.NumGoroutine(),
+		})
+	}
+
+	slog.Info("soaking", "dataset", s.dataset, "duration", s.duration, "interval", s.interval)
+	start := time.Now()
+	sample(0)
+	next := s.interval
+	for elapsed := time.Since(start); elapsed < s.duration; elapsed = time.Since(start) {
+		if elapsed >= next {
+			sample(elapsed)
+			next += s.interval
+		}
+		if err := soakIteration(raw); err != nil {
+			slog.Error("soak iteration failed", "dataset", s.dataset, "iteration", result.Iterations, "err", err)
+			return nil
+		}
+		result.Iterations++
+	}
+	elapsed := time.Since(start)
+	result.DurationS = elapsed.Seconds()
+	sample(elapsed)
+	report.FitSoak(result)
+	slog.Info("soaked", "dataset", s.dataset, "iterations", result.Iterations,
+		"memory_growth_bytes_per_min", result.MemoryGrowthBytesPerMin, "r_squared", result.RSquared)
+	return result
+}
+
+// soakIteration is one pass of the soak loop: the request a daemon
+// serving AAS documents handles over and over.
+func soakIteration(raw []byte) error {
+	env, err := sdkAdapter.Deserialize(raw)
+	if err != nil {
+		return err
+	}
+	if err := sdkAdapter.Update(env); err != nil {
+		return err
+	}
+	_, err = sdkAdapter.Serialize(env)
+	return err
+}
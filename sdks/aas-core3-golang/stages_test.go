@@ -0,0 +1,55 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// stageSpan is how long one harness stage took.
+type stageSpan struct {
+	Stage      string    `json:"stage"`
+	Operation  string    `json:"operation,omitempty"`
+	Dataset    string    `json:"dataset,omitempty"`
+	Start      time.Time `json:"start"`
+	DurationNs int64     `json:"duration_ns"`
+}
+
+// stagesFile is the schema of stages.json.
+type stagesFile struct {
+	Spans []stageSpan `json:"spans"`
+}
+
+// stageRecorder times the stages of the harness, dataset loads, the
+// benchmark of each operation on each dataset and memory captures, for
+// stages.json. aasbench run folds it into run_manifest.json, which shows
+// where a run that suddenly takes longer spends its time.
+type stageRecorder struct {
+	mu    sync.Mutex
+	spans []stageSpan
+}
+
+var globalStages = &stageRecorder{}
+
+// time starts timing a stage and returns the function that ends it, for
+// use as defer globalStages.time(...)().
+func (r *stageRecorder) time(stage, operation, dataset string) func() {
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.spans = append(r.spans, stageSpan{
+			Stage:      stage,
+			Operation:  operation,
+			Dataset:    dataset,
+			Start:      start.UTC(),
+			DurationNs: elapsed.Nanoseconds(),
+		})
+	}
+}
+
+func (r *stageRecorder) snapshot() stagesFile {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return stagesFile{Spans: append([]stageSpan{}, r.spans...)}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// startup times what a short-lived CLI or serverless function pays before
+// its first useful work: from starting a fresh process to its first
+// successful deserialize of a tiny document. The process is the test
+// binary itself, re-executed with startupProbeEnv set, so it loads the
+// same SDK and runtime as the rest of the suite; the other side of the
+// comparison is a JVM or .NET SDK, whose cold start dominates such uses.
+
+// startupProbeEnv makes TestMain run startupProbe instead of the suite.
+const startupProbeEnv = "AASBENCH_STARTUP_PROBE"
+
+// startupDataset is the dataset startup is reported under; its document
+// is built into the harness.
+const startupDataset = "startup_tiny"
+
+// startupDocument is the tiny document the probe deserializes: one shell
+// and one submodel with a single property.
+const startupDocument = `{
+  "assetAdministrationShells": [{
+    "modelType": "AssetAdministrationShell",
+    "id": "urn:benchmark:startup:aas",
+    "assetInformation": {"assetKind": "Instance", "globalAssetId": "urn:benchmark:startup:asset"},
+    "submodels": [{"type": "ModelReference", "keys": [{"type": "Submodel", "value": "urn:benchmark:startup:sm"}]}]
+  }],
+  "submodels": [{
+    "modelType": "Submodel",
+    "id": "urn:benchmark:startup:sm",
+    "idShort": "Startup",
+    "submodelElements": [{"modelType": "Property", "idShort": "Ready", "valueType": "xs:boolean", "value": "true"}]
+  }]
+}`
+
+// startupReady is the line the probe writes once it has deserialized.
+const startupReady = "ready"
+
+// startupProbe is the whole life of a probe process: deserialize the tiny
+// document with the SDK adapter in use and report that on stdout. It
+// returns the process's exit code.
+func startupProbe() int {
+	if _, err := sdkAdapter.Deserialize([]byte(startupDocument)); err != nil {
+		fmt.Fprintf(os.Stderr, "startup probe: %v\n", err)
+		return 1
+	}
+	fmt.Println(startupReady)
+	return 0
+}
+
+// startProbe starts a probe process and returns once it has reported its
+// deserialize. wait waits for the process to exit and reports whether
+// the probe succeeded.
+func startProbe() (wait func() error, err error) {
+	cmd := exec.Command(os.Args[0])
+	cmd.Env = append(os.Environ(), startupProbeEnv+"=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	line, _ := bufio.NewReader(stdout).ReadString('\n')
+	return func() error {
+		if err := cmd.Wait(); err != nil || strings.TrimSpace(line) != startupReady {
+			return fmt.Errorf("startup probe failed (%v): %s", err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	}, nil
+}
+
+// BenchmarkStartup benchmarks process start -> first successful
+// deserialize. Each iteration starts one probe process and is timed until
+// the probe reports; its exit is not timed. Where the harness cannot start
+// processes (WebAssembly) the operation is skipped.
+func BenchmarkStartup(b *testing.B) {
+	selectOperation(b, "startup")
+	if runtime.GOOS == "js" || runtime.GOOS == "wasip1" {
+		b.Skipf("cannot start processes on %s", runtime.GOOS)
+	}
+	// The first start also brings the binary into the page cache.
+	wait, err := startProbe()
+	if err == nil {
+		err = wait()
+	}
+	if err != nil {
+		b.Fatal(err)
+	}
+	runObserved(b, "startup", startupDataset, func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			wait, err := startProbe()
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.StopTimer()
+			if err := wait(); err != nil {
+				b.Fatal(err)
+			}
+			b.StartTimer()
+		}
+	})
+	globalMemStats.Groups["startup"] = captureMemSnapshot()
+	globalHeap.writeProfile("startup")
+}
@@ -0,0 +1,282 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	aas "github.com/aas-core-works/aas-core3.0-golang/jsonization"
+	aastypes "github.com/aas-core-works/aas-core3.0-golang/types"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// streamBufferSize is the buffer serialize_stream writes through, the
+// size of a typical HTTP response writer's.
+const streamBufferSize = 64 << 10
+
+// serialize_stream writes the environment as JSON to a writer as it walks
+// it, the way a server streams a response, instead of marshaling the
+// document into one byte slice. The SDK still builds its jsonable tree
+// first; only the document bytes are never held whole.
+func init() {
+	registerOperation(benchOperation{
+		ID: "serialize_stream", Benchmark: "SerializeStream", Needs: []string{"deserialize"}, Payload: true, Peak: true,
+		Setup: setupStream,
+		Run: func(input interface{}) error {
+			return serializeStream(bufio.NewWriterSize(io.Discard, streamBufferSize), input.(aastypes.IEnvironment))
+		},
+		Output: func(input interface{}) ([]byte, error) {
+			var buf bytes.Buffer
+			err := serializeStream(bufio.NewWriterSize(&buf, streamBufferSize), input.(aastypes.IEnvironment))
+			return buf.Bytes(), err
+		},
+	})
+}
+
+// setupStream deserializes a dataset and checks that streaming it writes
+// the bytes serialize marshals.
+func setupStream(raw []byte) (interface{}, error) {
+	env, err := deserializeEnv(raw)
+	if err != nil {
+		return nil, err
+	}
+	jsonable, err := aas.ToJsonable(env)
+	if err != nil {
+		return nil, err
+	}
+	want, err := json.Marshal(jsonable)
+	if err != nil {
+		return nil, err
+	}
+	var got bytes.Buffer
+	if err := serializeStream(bufio.NewWriterSize(&got, streamBufferSize), env); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(got.Bytes(), want) {
+		return nil, fmt.Errorf("streamed JSON differs from the marshaled document (%d vs %d bytes)", got.Len(), len(want))
+	}
+	return env, nil
+}
+
+// serializeStream writes env as JSON to w and flushes it.
+func serializeStream(w *bufio.Writer, env aastypes.IEnvironment) error {
+	jsonable, err := aas.ToJsonable(env)
+	if err != nil {
+		return err
+	}
+	if err := writeJsonable(w, jsonable); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+// writeJsonable writes a jsonable as aas.ToJsonable builds them (objects,
+// arrays, strings, float64s and bools) to w. Keys are sorted and strings
+// and numbers encoded as json.Marshal does, so the output is identical.
+func writeJsonable(w *bufio.Writer, v interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			writeJSONString(w, k)
+			w.WriteByte(':')
+			if err := writeJsonable(w, v[k]); err != nil {
+				return err
+			}
+		}
+		w.WriteByte('}')
+	case []interface{}:
+		w.WriteByte('[')
+		for i, item := range v {
+			if i > 0 {
+				w.WriteByte(',')
+			}
+			if err := writeJsonable(w, item); err != nil {
+				return err
+			}
+		}
+		w.WriteByte(']')
+	case string:
+		writeJSONString(w, v)
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("unsupported number %v", v)
+		}
+		var scratch [64]byte
+		w.Write(appendJSONFloat(scratch[:0], v))
+	case bool:
+		w.WriteString(strconv.FormatBool(v))
+	case nil:
+		w.WriteString("null")
+	default:
+		return fmt.Errorf("unexpected jsonable %T", v)
+	}
+	// A bufio.Writer keeps its first error and reports it on Flush.
+	return nil
+}
+
+// appendJSONFloat formats f like json.Marshal: plain notation from 1e-6
+// to 1e21, exponent notation without a padded exponent outside.
+func appendJSONFloat(b []byte, f float64) []byte {
+	abs := math.Abs(f)
+	format := byte('f')
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	b = strconv.AppendFloat(b, f, format, -1, 64)
+	if format == 'e' {
+		// clean up e-09 to e-9
+		if n := len(b); n >= 4 && b[n-4] == 'e' && b[n-3] == '-' && b[n-2] == '0' {
+			b[n-2] = b[n-1]
+			b = b[:n-1]
+		}
+	}
+	return b
+}
+
+const hexDigits = "0123456789abcdef"
+
+// writeJSONString writes s quoted and escaped like json.Marshal, which
+// also escapes <, > and &, U+2028 and U+2029, and replaces invalid UTF-8.
+func writeJSONString(w *bufio.Writer, s string) {
+	w.WriteByte('"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			w.WriteString(s[start:i])
+			switch c {
+			case '"', '\\':
+				w.WriteByte('\\')
+				w.WriteByte(c)
+			case '\n':
+				w.WriteString(`\n`)
+			case '\r':
+				w.WriteString(`\r`)
+			case '\t':
+				w.WriteString(`\t`)
+			case '\b':
+				w.WriteString(`\b`)
+			case '\f':
+				w.WriteString(`\f`)
+			default:
+				w.WriteString(`\u00`)
+				w.WriteByte(hexDigits[c>>4])
+				w.WriteByte(hexDigits[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			w.WriteString(s[start:i])
+			w.WriteString(`\ufffd`)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			w.WriteString(s[start:i])
+			w.WriteString(`\u202`)
+			w.WriteByte(hexDigits[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	w.WriteString(s[start:])
+	w.WriteByte('"')
+}
+
+// BenchmarkSerializeStream benchmarks AAS Environment -> JSON written
+// through a buffered writer to io.Discard.
+func BenchmarkSerializeStream(b *testing.B) { benchmarkOperation(b, "serialize_stream") }
+
+// Probing of an operation's peak intermediate allocation: the runs, and
+// how often the heap is sampled during one.
+const (
+	peakProbeRuns      = 3
+	peakSampleInterval = 50 * time.Microsecond
+)
+
+// recordPeak probes run outside the timed loop and records the most the
+// heap grew during a call in memory_stats.json, under operation/dataset.
+// Serialize and serialize_stream record it, which shows what holding the
+// whole document costs.
+func recordPeak(operation, dataset string, run func() error) error {
+	if !memoryCapture {
+		return nil
+	}
+	defer globalStages.time(report.StageMemoryCapture, operation, dataset)()
+	peak, err := peakHeapGrowth(run)
+	if err != nil {
+		return err
+	}
+	globalMemStats.Peaks[report.PeakKey(operation, dataset)] = peak
+	return nil
+}
+
+// peakHeapGrowth calls run peakProbeRuns times, each after a forced GC,
+// and returns the most the heap (live and not yet swept objects) grew over
+// its size before a call, sampled every peakSampleInterval during the call
+// and once after it. Sampling can miss a short-lived peak between two
+// samples, so the figure is a lower bound.
+func peakHeapGrowth(run func() error) (uint64, error) {
+	var peak uint64
+	for i := 0; i < peakProbeRuns; i++ {
+		runtime.GC()
+		base := liveHeapBytes()
+		growth := func() uint64 {
+			if live := liveHeapBytes(); live > base {
+				return live - base
+			}
+			return 0
+		}
+		stop, sampled := make(chan struct{}), make(chan uint64)
+		go func() {
+			ticker := time.NewTicker(peakSampleInterval)
+			defer ticker.Stop()
+			var top uint64
+			for {
+				top = max(top, growth())
+				select {
+				case <-stop:
+					sampled <- top
+					return
+				case <-ticker.C:
+				}
+			}
+		}()
+		err := run()
+		last := growth()
+		close(stop)
+		peak = max(peak, last, <-sampled)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return peak, nil
+}
@@ -0,0 +1,242 @@
+// Package sysinfo probes the benchmark host for the report's environment
+// object: CPU topology and clock policy, cgroup limits, kernel and
+// virtualization hints. It reads Linux /proc and /sys; elsewhere only the
+// fields the Go runtime knows are filled.
+package sysinfo
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Collect describes the current host.
+func Collect() *report.Environment {
+	return collect("/")
+}
+
+// collect reads every probed file relative to root so tests can supply a
+// fake tree.
+func collect(root string) *report.Environment {
+	r := reader(root)
+	env := &report.Environment{
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		KernelVersion: r.line("proc/sys/kernel/osrelease"),
+		CPU:           r.cpu(),
+		Cgroup:        r.cgroup(),
+	}
+	if kb, ok := r.meminfoKB("MemTotal"); ok {
+		total := kb * 1024
+		env.MemoryTotalBytes = &total
+	}
+	env.FrequencyScaling = r.frequencyScaling()
+	env.Virtualization = r.virtualization()
+	return env
+}
+
+type reader string
+
+func (r reader) path(name string) string {
+	return filepath.Join(string(r), name)
+}
+
+// line returns the trimmed first line of name, or "" when unreadable.
+func (r reader) line(name string) string {
+	data, err := os.ReadFile(r.path(name))
+	if err != nil {
+		return ""
+	}
+	first, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(first)
+}
+
+func (r reader) exists(name string) bool {
+	_, err := os.Stat(r.path(name))
+	return err == nil
+}
+
+// cpu parses /proc/cpuinfo. Sockets and physical cores come from the
+// distinct "physical id" and ("physical id", "core id") pairs, which some
+// virtual machines and architectures omit.
+func (r reader) cpu() report.CPUInfo {
+	info := report.CPUInfo{LogicalCores: runtime.NumCPU()}
+	f, err := os.Open(r.path("proc/cpuinfo"))
+	if err != nil {
+		return info
+	}
+	defer f.Close()
+
+	var mhzSum float64
+	var mhzCount int
+	sockets := make(map[string]bool)
+	cores := make(map[string]bool)
+	physicalID := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "model name":
+			if info.Model == "" {
+				info.Model = value
+			}
+		case "cpu MHz":
+			if mhz, err := strconv.ParseFloat(value, 64); err == nil {
+				mhzSum += mhz
+				mhzCount++
+			}
+		case "physical id":
+			physicalID = value
+			sockets[value] = true
+		case "core id":
+			cores[physicalID+"/"+value] = true
+		}
+	}
+	if mhzCount > 0 {
+		mhz := math.Round(mhzSum/float64(mhzCount)*10) / 10
+		info.MHz = &mhz
+	}
+	if len(sockets) > 0 {
+		n := len(sockets)
+		info.Sockets = &n
+	}
+	if len(cores) > 0 {
+		n := len(cores)
+		info.PhysicalCores = &n
+	}
+	if nodes, _ := filepath.Glob(r.path("sys/devices/system/node/node[0-9]*")); len(nodes) > 0 {
+		n := len(nodes)
+		info.NUMANodes = &n
+	}
+	return info
+}
+
+func (r reader) meminfoKB(field string) (int64, bool) {
+	f, err := os.Open(r.path("proc/meminfo"))
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok || key != field {
+			continue
+		}
+		kb, err := strconv.ParseInt(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		return kb, err == nil
+	}
+	return 0, false
+}
+
+// unlimitedMemory is the smallest value treated as "no limit"; cgroup v1
+// reports an unset limit as a page-aligned number close to math.MaxInt64.
+const unlimitedMemory = 1 << 62
+
+// cgroup reads the limits of the root of the visible cgroup hierarchy,
+// which inside a container is the container's own cgroup.
+func (r reader) cgroup() *report.CgroupLimits {
+	switch {
+	case r.exists("sys/fs/cgroup/cgroup.controllers"):
+		limits := &report.CgroupLimits{Version: "v2"}
+		if quota, period, ok := strings.Cut(r.line("sys/fs/cgroup/cpu.max"), " "); ok && quota != "max" {
+			limits.CPULimitCores = ratio(quota, period)
+		}
+		limits.MemoryLimitBytes = memoryLimit(r.line("sys/fs/cgroup/memory.max"))
+		return limits
+	case r.exists("sys/fs/cgroup/cpu") || r.exists("sys/fs/cgroup/memory"):
+		limits := &report.CgroupLimits{Version: "v1"}
+		if quota := r.line("sys/fs/cgroup/cpu/cpu.cfs_quota_us"); quota != "" && quota != "-1" {
+			limits.CPULimitCores = ratio(quota, r.line("sys/fs/cgroup/cpu/cpu.cfs_period_us"))
+		}
+		limits.MemoryLimitBytes = memoryLimit(r.line("sys/fs/cgroup/memory/memory.limit_in_bytes"))
+		return limits
+	}
+	return nil
+}
+
+func ratio(quota, period string) *float64 {
+	q, err1 := strconv.ParseFloat(quota, 64)
+	p, err2 := strconv.ParseFloat(period, 64)
+	if err1 != nil || err2 != nil || q <= 0 || p <= 0 {
+		return nil
+	}
+	cores := math.Round(q/p*100) / 100
+	return &cores
+}
+
+func memoryLimit(v string) *int64 {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || n <= 0 || n >= unlimitedMemory {
+		return nil
+	}
+	return &n
+}
+
+// frequencyScaling reports cpu0's governor, taken as representative, and
+// the turbo/boost switch of intel_pstate or acpi-cpufreq.
+func (r reader) frequencyScaling() report.FrequencyScaling {
+	fs := report.FrequencyScaling{Governor: r.line("sys/devices/system/cpu/cpu0/cpufreq/scaling_governor")}
+	if fs.Governor != "" {
+		active := fs.Governor != "performance"
+		fs.Active = &active
+	}
+	if v := r.line("sys/devices/system/cpu/intel_pstate/no_turbo"); v != "" {
+		enabled := v == "0"
+		fs.TurboEnabled = &enabled
+	} else if v := r.line("sys/devices/system/cpu/cpufreq/boost"); v != "" {
+		enabled := v == "1"
+		fs.TurboEnabled = &enabled
+	}
+	return fs
+}
+
+func (r reader) virtualization() report.Virtualization {
+	var v report.Virtualization
+	if r.cpuFlag("hypervisor") {
+		v.Hypervisor = r.line("sys/class/dmi/id/sys_vendor")
+		if v.Hypervisor == "" {
+			v.Hypervisor = "unknown"
+		}
+	}
+	switch {
+	case os.Getenv("KUBERNETES_SERVICE_HOST") != "" && string(r) == "/":
+		v.Container = "kubernetes"
+	case r.exists(".dockerenv"):
+		v.Container = "docker"
+	case r.exists("run/.containerenv"):
+		v.Container = "podman"
+	}
+	return v
+}
+
+func (r reader) cpuFlag(flag string) bool {
+	f, err := os.Open(r.path("proc/cpuinfo"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if ok && strings.TrimSpace(key) == "flags" {
+			for _, f := range strings.Fields(value) {
+				if f == flag {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package sysinfo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestCollect(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"proc/cpuinfo": "processor\t: 0\nmodel name\t: Test CPU @ 2.00GHz\ncpu MHz\t\t: 2000.000\nphysical id\t: 0\ncore id\t\t: 0\nflags\t\t: fpu sse hypervisor\n\n" +
+			"processor\t: 1\nmodel name\t: Test CPU @ 2.00GHz\ncpu MHz\t\t: 2100.000\nphysical id\t: 0\ncore id\t\t: 0\nflags\t\t: fpu sse hypervisor\n",
+		"proc/meminfo":                                         "MemTotal:        2048 kB\nMemFree:          100 kB\n",
+		"proc/sys/kernel/osrelease":                            "6.1.0-test\n",
+		"sys/devices/system/node/node0/cpulist":                "0-1\n",
+		"sys/fs/cgroup/cgroup.controllers":                     "cpu memory\n",
+		"sys/fs/cgroup/cpu.max":                                "150000 100000\n",
+		"sys/fs/cgroup/memory.max":                             "max\n",
+		"sys/devices/system/cpu/cpu0/cpufreq/scaling_governor": "powersave\n",
+		"sys/devices/system/cpu/intel_pstate/no_turbo":         "0\n",
+		"sys/class/dmi/id/sys_vendor":                          "KVM\n",
+		".dockerenv":                                           "",
+	})
+
+	env := collect(root)
+	if env.KernelVersion != "6.1.0-test" || env.CPU.Model != "Test CPU @ 2.00GHz" {
+		t.Errorf("kernel/model = %q/%q", env.KernelVersion, env.CPU.Model)
+	}
+	if env.CPU.MHz == nil || *env.CPU.MHz != 2050 {
+		t.Errorf("mhz = %v, want 2050", env.CPU.MHz)
+	}
+	if *env.CPU.Sockets != 1 || *env.CPU.PhysicalCores != 1 || *env.CPU.NUMANodes != 1 {
+		t.Errorf("topology = %+v", env.CPU)
+	}
+	if *env.MemoryTotalBytes != 2048*1024 {
+		t.Errorf("memory = %d", *env.MemoryTotalBytes)
+	}
+	if c := env.Cgroup; c == nil || c.Version != "v2" || *c.CPULimitCores != 1.5 || c.MemoryLimitBytes != nil {
+		t.Errorf("cgroup = %+v", c)
+	}
+	if fs := env.FrequencyScaling; !*fs.Active || !*fs.TurboEnabled {
+		t.Errorf("frequency scaling = %+v", fs)
+	}
+	if v := env.Virtualization; v.Hypervisor != "KVM" || v.Container != "docker" {
+		t.Errorf("virtualization = %+v", v)
+	}
+}
+
+func TestCollectEmptyTree(t *testing.T) {
+	env := collect(t.TempDir())
+	if env.CPU.LogicalCores < 1 || env.Cgroup != nil || env.MemoryTotalBytes != nil || env.FrequencyScaling.Active != nil {
+		t.Errorf("unexpected values from an empty tree: %+v", env)
+	}
+}
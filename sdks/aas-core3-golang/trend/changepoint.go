@@ -0,0 +1,123 @@
+// Package trend finds the points in a benchmark's history where its
+// performance shifted, so regression triage can start from a window of
+// commits instead of a chart.
+package trend
+
+import (
+	"math"
+	"sort"
+)
+
+// DefaultMinSegment is the fewest runs a level must hold to count as one;
+// a single outlier run is not a shift.
+const DefaultMinSegment = 3
+
+// Changepoints segments values by PELT (pruned exact linear time) with a
+// squared-error cost, and returns the indexes at which a new segment
+// starts. Every segment holds at least minSegment values. penalty is the
+// cost of one more segment; see Penalty.
+func Changepoints(values []float64, minSegment int, penalty float64) []int {
+	n := len(values)
+	if minSegment < 1 {
+		minSegment = 1
+	}
+	if n < 2*minSegment {
+		return nil
+	}
+	sum := make([]float64, n+1)
+	sumSq := make([]float64, n+1)
+	for i, v := range values {
+		sum[i+1] = sum[i] + v
+		sumSq[i+1] = sumSq[i] + v*v
+	}
+	// cost is the squared error of values[a:b] around their mean.
+	cost := func(a, b int) float64 {
+		s := sum[b] - sum[a]
+		return sumSq[b] - sumSq[a] - s*s/float64(b-a)
+	}
+
+	best := make([]float64, n+1)
+	last := make([]int, n+1)
+	best[0] = -penalty
+	candidates := []int{0}
+	for t := 1; t <= n; t++ {
+		best[t] = math.Inf(1)
+		for _, tau := range candidates {
+			if t-tau < minSegment || math.IsInf(best[tau], 1) {
+				continue
+			}
+			if v := best[tau] + cost(tau, t) + penalty; v < best[t] {
+				best[t], last[t] = v, tau
+			}
+		}
+		// A candidate that cannot beat the optimum now never will.
+		kept := candidates[:0]
+		for _, tau := range candidates {
+			if t-tau < minSegment || best[tau]+cost(tau, t) <= best[t] {
+				kept = append(kept, tau)
+			}
+		}
+		candidates = append(kept, t)
+	}
+
+	var starts []int
+	for t := last[n]; t > 0; t = last[t] {
+		starts = append(starts, t)
+	}
+	sort.Ints(starts)
+	return starts
+}
+
+// Despike replaces isolated outliers by the median of their neighborhood
+// (a Hampel filter over two values either side): a run further from that
+// median than three robust deviations, and than 3 * floor, is one noisy run
+// rather than the start of a new level, and would otherwise be cut out as a
+// segment of its own.
+func Despike(values []float64, floor float64) []float64 {
+	out := append([]float64(nil), values...)
+	window := make([]float64, 0, 5)
+	devs := make([]float64, 0, 5)
+	for i, v := range values {
+		window = append(window[:0], values[max(0, i-2):min(len(values), i+3)]...)
+		med := median(window)
+		devs = devs[:0]
+		for _, w := range window {
+			devs = append(devs, math.Abs(w-med))
+		}
+		limit := math.Max(3*1.4826*median(devs), 3*floor)
+		if math.Abs(v-med) > limit {
+			out[i] = med
+		}
+	}
+	return out
+}
+
+// median sorts values in place and returns their median.
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	mid := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[mid-1] + values[mid]) / 2
+	}
+	return values[mid]
+}
+
+// Penalty is a BIC-style segment cost, factor * 2 * sigma^2 * ln(n), with
+// the noise sigma estimated from the differences of consecutive values so
+// that the shifts themselves do not inflate it. floor bounds sigma from
+// below, keeping a series of identical runs from splitting on rounding.
+func Penalty(values []float64, factor, floor float64) float64 {
+	sigma := floor
+	if len(values) > 1 {
+		diffs := make([]float64, len(values)-1)
+		for i := 1; i < len(values); i++ {
+			diffs[i-1] = math.Abs(values[i] - values[i-1])
+		}
+		// MAD scaled to a standard deviation, divided by sqrt(2) because a
+		// difference of two values carries the noise of both.
+		if s := median(diffs) / (0.6745 * math.Sqrt2); s > sigma {
+			sigma = s
+		}
+	}
+	return factor * 2 * sigma * sigma * math.Log(float64(len(values)))
+}
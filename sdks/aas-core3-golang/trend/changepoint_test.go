@@ -0,0 +1,32 @@
+package trend
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChangepointsFindsLevelShifts(t *testing.T) {
+	values := []float64{10, 10.1, 9.9, 10, 10.2, 15, 15.1, 14.9, 15, 12, 12.1, 11.9, 12}
+	penalty := Penalty(values, DefaultPenaltyFactor, 0.01)
+	if got := Changepoints(values, DefaultMinSegment, penalty); !reflect.DeepEqual(got, []int{5, 9}) {
+		t.Errorf("changepoints = %v, want [5 9]", got)
+	}
+
+	// Noise alone is not a shift, nor is a single outlier run once
+	// despiked.
+	flat := []float64{10, 10.2, 9.8, 10.1, 9.9, 10, 10, 10.1, 9.9, 10.2}
+	if got := Changepoints(flat, DefaultMinSegment, Penalty(flat, DefaultPenaltyFactor, 0.01)); len(got) != 0 {
+		t.Errorf("changepoints in noise = %v, want none", got)
+	}
+	flat[5] = 14
+	despiked := Despike(flat, 0.01)
+	if despiked[5] > 10.2 {
+		t.Errorf("outlier kept as %v", despiked[5])
+	}
+	if got := Changepoints(despiked, DefaultMinSegment, Penalty(despiked, DefaultPenaltyFactor, 0.01)); len(got) != 0 {
+		t.Errorf("changepoints around an outlier = %v, want none", got)
+	}
+	if got := Changepoints([]float64{1, 2, 3}, DefaultMinSegment, 0); got != nil {
+		t.Errorf("too short a series split at %v", got)
+	}
+}
@@ -0,0 +1,232 @@
+package trend
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/aggregate"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/report"
+)
+
+// Defaults of Options.
+const (
+	DefaultPenaltyFactor = 1.0
+	// sigmaFloor is the smallest noise assumed, in log ns: about 0.5%.
+	sigmaFloor = 0.005
+)
+
+// Run is one measurement in a series and the build it came from.
+type Run struct {
+	Time    time.Time `json:"time"`
+	Commit  string    `json:"commit,omitempty"`
+	Version string    `json:"sdk_version,omitempty"`
+	MeanNs  float64   `json:"mean_ns"`
+}
+
+// Series is the history of one SDK operation on one dataset, oldest first.
+type Series struct {
+	SDKID     string `json:"sdk_id"`
+	Dataset   string `json:"dataset"`
+	Operation string `json:"operation"`
+	Runs      []Run  `json:"runs"`
+}
+
+// Shift is a changepoint: the mean moved between LastBefore and
+// FirstAfter, so the change landed in the commits between them.
+type Shift struct {
+	SDKID      string  `json:"sdk_id"`
+	Dataset    string  `json:"dataset"`
+	Operation  string  `json:"operation"`
+	Direction  string  `json:"direction"`
+	BeforeNs   float64 `json:"before_ns"`
+	AfterNs    float64 `json:"after_ns"`
+	ChangePct  float64 `json:"change_pct"`
+	LastBefore Run     `json:"last_before"`
+	FirstAfter Run     `json:"first_after"`
+}
+
+// Options tunes Analyze.
+type Options struct {
+	// MinSegment is the fewest runs a level must hold; DefaultMinSegment
+	// when < 1.
+	MinSegment int
+	// PenaltyFactor scales Penalty; higher finds fewer shifts.
+	// DefaultPenaltyFactor when <= 0.
+	PenaltyFactor float64
+	// MinShiftPct drops shifts smaller than this, in percent.
+	MinShiftPct float64
+}
+
+// History collects series from reports and results.json snapshots. A
+// report seen in several snapshots counts once.
+type History struct {
+	series map[string]*Series
+	seen   map[string]bool
+}
+
+// NewHistory returns an empty History.
+func NewHistory() *History {
+	return &History{series: make(map[string]*Series), seen: make(map[string]bool)}
+}
+
+// Add adds the runs of a report.json, or of every SDK pipeline in a
+// results.json. A run is placed at its report's metadata.timestamp, or at
+// the snapshot's generated_at for reports without one. It returns the
+// number of reports added.
+func (h *History) Add(obj aggregate.Object) (int, error) {
+	if _, ok := obj["sdk_benchmarks"]; ok {
+		fallback, _ := time.Parse(time.RFC3339Nano, stringOf(obj["generated_at"]))
+		entries, _ := obj["sdk_benchmarks"].([]interface{})
+		added := 0
+		for _, raw := range entries {
+			sdk, _ := raw.(aggregate.Object)
+			pipeline, ok := sdk["pipeline"].(aggregate.Object)
+			if !ok {
+				continue
+			}
+			if h.addReport(stringOf(sdk["id"]), pipeline, fallback) {
+				added++
+			}
+		}
+		return added, nil
+	}
+	if _, ok := obj["datasets"]; !ok {
+		return 0, fmt.Errorf("neither a report.json nor a results.json")
+	}
+	sdkID := stringOf(obj["sdk_id"])
+	if sdkID == "" {
+		return 0, fmt.Errorf("report has no sdk_id")
+	}
+	meta, _ := obj["metadata"].(aggregate.Object)
+	if _, err := time.Parse(time.RFC3339Nano, stringOf(meta["timestamp"])); err != nil {
+		return 0, fmt.Errorf("report has no valid metadata.timestamp")
+	}
+	if !h.addReport(sdkID, obj, time.Time{}) {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+func (h *History) addReport(sdkID string, rep aggregate.Object, fallback time.Time) bool {
+	meta, _ := rep["metadata"].(aggregate.Object)
+	at, err := time.Parse(time.RFC3339Nano, stringOf(meta["timestamp"]))
+	if err != nil {
+		at = fallback
+	}
+	key := sdkID + "\x00" + at.UTC().Format(time.RFC3339Nano)
+	if sdkID == "" || at.IsZero() || h.seen[key] {
+		return false
+	}
+	h.seen[key] = true
+
+	aggregate.NormalizePipelineReport(rep)
+	datasets, _ := rep["datasets"].(aggregate.Object)
+	for dsName, dsRaw := range datasets {
+		ds, _ := dsRaw.(aggregate.Object)
+		ops, _ := ds["operations"].(aggregate.Object)
+		for opID, opRaw := range ops {
+			op, _ := opRaw.(aggregate.Object)
+			state := stringOf(op["failure_state"])
+			mean, _ := op["mean_ns"].(float64)
+			if (state != "" && state != report.FailureOK) || mean <= 0 {
+				continue
+			}
+			seriesKey := sdkID + "\x00" + dsName + "\x00" + opID
+			s := h.series[seriesKey]
+			if s == nil {
+				s = &Series{SDKID: sdkID, Dataset: dsName, Operation: opID}
+				h.series[seriesKey] = s
+			}
+			s.Runs = append(s.Runs, Run{
+				Time:    at.UTC(),
+				Commit:  stringOf(meta["observatory_git_sha"]),
+				Version: stringOf(meta["sdk_package_version"]),
+				MeanNs:  mean,
+			})
+		}
+	}
+	return true
+}
+
+// Series returns every series sorted by SDK, dataset and operation, each
+// with its runs in time order.
+func (h *History) Series() []*Series {
+	out := make([]*Series, 0, len(h.series))
+	for _, s := range h.series {
+		sort.SliceStable(s.Runs, func(i, j int) bool { return s.Runs[i].Time.Before(s.Runs[j].Time) })
+		out = append(out, s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		a, b := out[i], out[j]
+		if a.SDKID != b.SDKID {
+			return a.SDKID < b.SDKID
+		}
+		if a.Dataset != b.Dataset {
+			return a.Dataset < b.Dataset
+		}
+		return a.Operation < b.Operation
+	})
+	return out
+}
+
+// Analyze finds the shifts in s. Changepoints are detected on despiked log
+// mean_ns, so a shift is judged by its relative size whatever the
+// operation's scale; each is reported with the raw means of the levels on
+// either side.
+func Analyze(s *Series, opts Options) []Shift {
+	if opts.MinSegment < 1 {
+		opts.MinSegment = DefaultMinSegment
+	}
+	if opts.PenaltyFactor <= 0 {
+		opts.PenaltyFactor = DefaultPenaltyFactor
+	}
+	logs := make([]float64, len(s.Runs))
+	for i, r := range s.Runs {
+		logs[i] = math.Log(r.MeanNs)
+	}
+	logs = Despike(logs, sigmaFloor)
+	starts := Changepoints(logs, opts.MinSegment, Penalty(logs, opts.PenaltyFactor, sigmaFloor))
+
+	bounds := append(append([]int{0}, starts...), len(s.Runs))
+	var shifts []Shift
+	for i := 1; i < len(bounds)-1; i++ {
+		before := meanNs(s.Runs[bounds[i-1]:bounds[i]])
+		after := meanNs(s.Runs[bounds[i]:bounds[i+1]])
+		pct := (after - before) / before * 100
+		if math.Abs(pct) < opts.MinShiftPct {
+			continue
+		}
+		direction := compare.Improvement
+		if pct > 0 {
+			direction = compare.Regression
+		}
+		shifts = append(shifts, Shift{
+			SDKID:      s.SDKID,
+			Dataset:    s.Dataset,
+			Operation:  s.Operation,
+			Direction:  direction,
+			BeforeNs:   before,
+			AfterNs:    after,
+			ChangePct:  math.Round(pct*100) / 100,
+			LastBefore: s.Runs[bounds[i]-1],
+			FirstAfter: s.Runs[bounds[i]],
+		})
+	}
+	return shifts
+}
+
+func meanNs(runs []Run) float64 {
+	sum := 0.0
+	for _, r := range runs {
+		sum += r.MeanNs
+	}
+	return sum / float64(len(runs))
+}
+
+func stringOf(v interface{}) string {
+	s, _ := v.(string)
+	return s
+}
@@ -0,0 +1,69 @@
+package trend
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/aggregate"
+	"github.com/aas-benchmark-observatory/sdks/aas-core3-golang/compare"
+)
+
+func decode(t *testing.T, s string) aggregate.Object {
+	t.Helper()
+	var obj aggregate.Object
+	if err := json.Unmarshal([]byte(s), &obj); err != nil {
+		t.Fatal(err)
+	}
+	return obj
+}
+
+// nightlyReport is a report.json of day measuring deserialize on wide.
+func nightlyReport(t *testing.T, day int, meanNs float64) aggregate.Object {
+	return decode(t, fmt.Sprintf(`{"sdk_id": "aas-core3-golang",
+		"metadata": {"timestamp": "2026-09-%02dT03:00:00Z", "observatory_git_sha": "sha%02d", "sdk_package_version": "v1.0.7"},
+		"datasets": {"wide": {"operations": {
+			"Deserialize": {"mean_ns": %g, "failure_state": "ok"},
+			"validate": {"mean_ns": 0, "failure_state": "skipped_missing_dataset"}
+		}}}}`, day, day, meanNs))
+}
+
+func TestAnalyzeReportsShiftWindow(t *testing.T) {
+	h := NewHistory()
+	means := []float64{1000, 1010, 990, 1005, 995, 1300, 1310, 1290, 1305}
+	// Added out of order; the history sorts by timestamp.
+	for i := len(means) - 1; i >= 0; i-- {
+		if n, err := h.Add(nightlyReport(t, i+1, means[i])); err != nil || n != 1 {
+			t.Fatalf("add day %d: %d, %v", i+1, n, err)
+		}
+	}
+	// A results.json holding an already seen report adds nothing.
+	results := decode(t, `{"generated_at": "2026-09-09T04:00:00Z", "sdk_benchmarks": []}`)
+	results["sdk_benchmarks"] = []interface{}{aggregate.Object{"id": "aas-core3-golang", "pipeline": nightlyReport(t, 9, 1305)}}
+	if n, _ := h.Add(results); n != 0 {
+		t.Errorf("duplicate report added %d time(s)", n)
+	}
+
+	series := h.Series()
+	if len(series) != 1 || series[0].Operation != "deserialize" || len(series[0].Runs) != len(means) {
+		t.Fatalf("series = %+v, want one deserialize series of %d runs", series, len(means))
+	}
+	shifts := Analyze(series[0], Options{MinShiftPct: compare.DefaultThresholdPct})
+	if len(shifts) != 1 {
+		t.Fatalf("shifts = %+v, want one", shifts)
+	}
+	s := shifts[0]
+	if s.Direction != compare.Regression || s.LastBefore.Commit != "sha05" || s.FirstAfter.Commit != "sha06" {
+		t.Errorf("shift = %+v, want a regression between sha05 and sha06", s)
+	}
+	if s.ChangePct < 29 || s.ChangePct > 31 {
+		t.Errorf("change = %v%%, want about 30%%", s.ChangePct)
+	}
+
+	if shifts := Analyze(series[0], Options{MinShiftPct: 50}); len(shifts) != 0 {
+		t.Errorf("shift below --min-shift kept: %+v", shifts)
+	}
+	if _, err := h.Add(decode(t, `{"sdk_id": "x", "datasets": {}}`)); err == nil {
+		t.Error("want an error for a report without a timestamp")
+	}
+}
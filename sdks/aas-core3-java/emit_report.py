@@ -1,8 +1,8 @@
 #!/usr/bin/env python3
-"""Convert JMH JSON output to report.json schema v2.
+"""Convert JMH JSON output to report.json schema v3.
 
 Usage:
-    python3 emit_report.py <jmh_results.json> <output_path>
+    python3 emit_report.py [--schema-version {2,3}] <jmh_results.json> <output_path>
 
 JMH results have times in nanoseconds when OutputTimeUnit is NANOSECONDS.
 
@@ -13,7 +13,11 @@ Schema v2 additions (SRQ-2):
       gc.alloc.rate.norm -> alloc_bytes_per_op
       gc.count           -> gc_count
       gc.time            -> gc_pause_ms
+
+Schema v3 adds a metrics_manifest giving the unit and aggregation of every
+numeric field; --schema-version 2 leaves it out.
 """
+import argparse
 import json
 import os
 import re
@@ -21,6 +25,9 @@ import sys
 from datetime import datetime, timezone
 from pathlib import Path
 
+sys.path.insert(0, str(Path(__file__).resolve().parents[2] / "scripts"))
+import metrics_manifest  # noqa: E402
+
 CORE_DATASETS = {"wide", "deep", "mixed"}
 CORE_OPERATIONS = {"deserialize", "validate", "traverse", "update", "serialize"}
 
@@ -93,12 +100,18 @@ def _extract_gc_metrics(result):
 
 
 def main():
-    if len(sys.argv) != 3:
-        print("Usage: python3 emit_report.py <jmh_json> <output_path>", file=sys.stderr)
-        sys.exit(1)
-
-    input_path = sys.argv[1]
-    output_path = sys.argv[2]
+    parser = argparse.ArgumentParser(description="Convert JMH JSON output to report.json")
+    parser.add_argument("jmh_json", help="Path to JMH JSON results")
+    parser.add_argument("output", help="Path to write report.json")
+    parser.add_argument(
+        "--schema-version", type=int, default=metrics_manifest.SCHEMA_VERSION,
+        choices=[metrics_manifest.LEGACY_SCHEMA_VERSION, metrics_manifest.SCHEMA_VERSION],
+        help="report.json schema version to write",
+    )
+    args = parser.parse_args()
+
+    input_path = args.jmh_json
+    output_path = args.output
 
     with open(input_path) as f:
         jmh_results = json.load(f)
@@ -217,6 +230,7 @@ def main():
         },
         "datasets": datasets,
     }
+    metrics_manifest.set_schema_version(report, args.schema_version)
 
     out_dir = Path(output_path).parent
     out_dir.mkdir(parents=True, exist_ok=True)
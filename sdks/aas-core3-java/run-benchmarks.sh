@@ -22,9 +22,28 @@ mvn -q package -DskipTests
 export JMH_OUTPUT="$OUTPUT_DIR/jmh_results.json"
 java -jar target/aas-benchmark-java-1.0.0.jar
 
-# Convert JMH JSON to report.json
-python3 "$SCRIPT_DIR/emit_report.py" \
-  "$JMH_OUTPUT" \
-  "$OUTPUT_DIR/report.json"
+# Convert JMH JSON to report.json with the shared Go tooling when it is
+# available; emit_report.py remains as a fallback.
+GO_TOOLING="$SCRIPT_DIR/../aas-core3-golang"
+if command -v aasbench >/dev/null 2>&1; then
+  AASBENCH=(aasbench)
+elif command -v go >/dev/null 2>&1; then
+  AASBENCH=(go -C "$GO_TOOLING" run ./cmd/aasbench)
+else
+  AASBENCH=()
+fi
+
+if [ ${#AASBENCH[@]} -gt 0 ]; then
+  SDK_VERSION="$(grep -A1 'aas-core3.0-java</artifactId>' "$SCRIPT_DIR/pom.xml" | sed -n 's/.*<version>\(.*\)<\/version>.*/\1/p')"
+  "${AASBENCH[@]}" ingest --format jmh \
+    --input "$JMH_OUTPUT" \
+    --output "$OUTPUT_DIR/report.json" \
+    --sdk-id aas-core3-java \
+    --meta "sdk_package_version=${SDK_VERSION:-unknown}"
+else
+  python3 "$SCRIPT_DIR/emit_report.py" \
+    "$JMH_OUTPUT" \
+    "$OUTPUT_DIR/report.json"
+fi
 
 echo "Report written to $OUTPUT_DIR/report.json"
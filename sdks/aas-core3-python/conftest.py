@@ -96,6 +96,13 @@ def aasx_path(request):
     return request.param
 
 
+@pytest.fixture(params=["store", "fastest", "default", "best"])
+def compression_level(request):
+    """Parametrized fixture yielding each zip compression level of the
+    aasx_repackage sweep."""
+    return request.param
+
+
 class MemoryTracker:
     """Track peak RSS and tracemalloc memory usage around operations."""
 
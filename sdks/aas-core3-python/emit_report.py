@@ -2,7 +2,10 @@
 """Convert pytest-benchmark JSON output + memory measurements to report.json.
 
 Usage:
-    python3 emit_report.py <bench_json> <memory_json> <output_path>
+    python3 emit_report.py [--schema-version {2,3}] <bench_json> <memory_json> <output_path>
+
+Reports are written as schema version 3, with a metrics_manifest, unless
+--schema-version 2 asks for the legacy layout.
 """
 
 import argparse
@@ -11,11 +14,17 @@ import json
 import platform
 import sys
 from datetime import datetime, timezone
+from pathlib import Path
+
+sys.path.insert(0, str(Path(__file__).resolve().parents[2] / "scripts"))
+import metrics_manifest  # noqa: E402
 
 CORE_DATASETS = {"wide", "deep", "mixed"}
 CORE_OPERATIONS = {"deserialize", "validate", "traverse", "update", "serialize"}
 XML_OPERATIONS = {"deserialize_xml", "serialize_xml"}
 AASX_OPERATIONS = {"aasx_extract", "aasx_repackage"}
+# Levels of the aasx_repackage compression sweep, in order of effort.
+COMPRESSION_LEVELS = ["store", "fastest", "default", "best"]
 
 
 def parse_benchmark_name(name):
@@ -108,6 +117,11 @@ def main():
     parser.add_argument("bench_json", help="Path to pytest-benchmark JSON output")
     parser.add_argument("memory_json", help="Path to memory measurements JSON")
     parser.add_argument("output", help="Path to write report.json")
+    parser.add_argument(
+        "--schema-version", type=int, default=metrics_manifest.SCHEMA_VERSION,
+        choices=[metrics_manifest.LEGACY_SCHEMA_VERSION, metrics_manifest.SCHEMA_VERSION],
+        help="report.json schema version to write",
+    )
     args = parser.parse_args()
 
     with open(args.bench_json, "r", encoding="utf-8") as f:
@@ -136,10 +150,26 @@ def main():
 
     # Organize benchmarks by dataset
     datasets = {}
+    compression_sweep = []
     for bench in bench_data.get("benchmarks", []):
         name = bench.get("name", "")
         operation, dataset = parse_benchmark_name(name)
 
+        # A compression sweep point; only the default level is also the
+        # operation's measurement.
+        extra = bench.get("extra_info", {})
+        level = extra.get("compression_level")
+        if level is not None:
+            dataset = dataset.removesuffix(f"-{level}")
+            compression_sweep.append({
+                "dataset": dataset,
+                "level": level,
+                "mean_ns": seconds_to_ns(bench.get("stats", {}).get("mean", 0)),
+                "package_size_bytes": extra.get("package_size_bytes", 0),
+            })
+            if level != "default":
+                continue
+
         if dataset not in datasets:
             datasets[dataset] = {"operations": {}}
 
@@ -175,6 +205,13 @@ def main():
         },
         "datasets": datasets_output,
     }
+    if compression_sweep:
+        compression_sweep.sort(key=lambda p: (
+            p["dataset"],
+            COMPRESSION_LEVELS.index(p["level"]) if p["level"] in COMPRESSION_LEVELS else len(COMPRESSION_LEVELS),
+        ))
+        report["compression_sweep"] = compression_sweep
+    metrics_manifest.set_schema_version(report, args.schema_version)
 
     with open(args.output, "w", encoding="utf-8") as f:
         json.dump(report, f, indent=2)
@@ -29,10 +29,30 @@ pytest "$SCRIPT_DIR/bench_pipeline.py" \
   --benchmark-warmup=on \
   -v
 
-# Convert pytest-benchmark JSON to report.json
-python3 "$SCRIPT_DIR/emit_report.py" \
-  "$BENCH_JSON" \
-  "$MEMORY_JSON" \
-  "$OUTPUT_DIR/report.json"
+# Convert pytest-benchmark JSON to report.json with the shared Go tooling
+# when it is available; the memory figures travel in each benchmark's
+# extra_info. emit_report.py remains as a fallback.
+GO_TOOLING="$SCRIPT_DIR/../aas-core3-golang"
+if command -v aasbench >/dev/null 2>&1; then
+  AASBENCH=(aasbench)
+elif command -v go >/dev/null 2>&1; then
+  AASBENCH=(go -C "$GO_TOOLING" run ./cmd/aasbench)
+else
+  AASBENCH=()
+fi
+
+if [ ${#AASBENCH[@]} -gt 0 ]; then
+  SDK_VERSION="$(python3 -c 'import importlib.metadata as m; print(m.version("aas-core3.0"))' 2>/dev/null || echo unknown)"
+  "${AASBENCH[@]}" ingest --format pytest-benchmark \
+    --input "$BENCH_JSON" \
+    --output "$OUTPUT_DIR/report.json" \
+    --sdk-id aas-core3-python \
+    --meta "sdk_package_version=$SDK_VERSION"
+else
+  python3 "$SCRIPT_DIR/emit_report.py" \
+    "$BENCH_JSON" \
+    "$MEMORY_JSON" \
+    "$OUTPUT_DIR/report.json"
+fi
 
 echo "Report written to $OUTPUT_DIR/report.json"
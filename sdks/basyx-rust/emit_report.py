@@ -1,8 +1,8 @@
 #!/usr/bin/env python3
-"""Convert criterion benchmark results to report.json schema v2.
+"""Convert criterion benchmark results to report.json schema v3.
 
 Usage:
-    python3 emit_report.py <criterion_target_dir> <output_path>
+    python3 emit_report.py [--schema-version {2,3}] <criterion_target_dir> <output_path>
 
 Criterion stores results in target/criterion/<group>/<benchmark>/new/estimates.json
 with times in nanoseconds.
@@ -10,13 +10,20 @@ with times in nanoseconds.
 Schema v2 additions:
   - memory.heap_used_bytes, gc_pause_ms, gc_count, traced_peak_bytes
   - peak_rss_bytes read from /proc/self/status VmHWM (Linux CI only)
+
+Schema v3 adds a metrics_manifest giving the unit and aggregation of every
+numeric field; --schema-version 2 leaves it out.
 """
+import argparse
 import json
 import os
 import sys
 from datetime import datetime, timezone
 from pathlib import Path
 
+sys.path.insert(0, str(Path(__file__).resolve().parents[2] / "scripts"))
+import metrics_manifest  # noqa: E402
+
 CORE_DATASETS = {"wide", "deep", "mixed"}
 CORE_OPERATIONS = {"deserialize", "validate", "traverse", "update", "serialize"}
 
@@ -56,12 +63,18 @@ def _read_vmhwm_bytes():
 
 
 def main():
-    if len(sys.argv) != 3:
-        print("Usage: python3 emit_report.py <criterion_dir> <output_path>", file=sys.stderr)
-        sys.exit(1)
-
-    criterion_dir = Path(sys.argv[1])
-    output_path = sys.argv[2]
+    parser = argparse.ArgumentParser(description="Convert criterion results to report.json")
+    parser.add_argument("criterion_dir", help="Path to criterion's target directory")
+    parser.add_argument("output", help="Path to write report.json")
+    parser.add_argument(
+        "--schema-version", type=int, default=metrics_manifest.SCHEMA_VERSION,
+        choices=[metrics_manifest.LEGACY_SCHEMA_VERSION, metrics_manifest.SCHEMA_VERSION],
+        help="report.json schema version to write",
+    )
+    args = parser.parse_args()
+
+    criterion_dir = Path(args.criterion_dir)
+    output_path = args.output
 
     # Read peak RSS early (will be None on non-Linux)
     peak_rss_bytes = _read_vmhwm_bytes()
@@ -181,6 +194,7 @@ def main():
         },
         "datasets": datasets,
     }
+    metrics_manifest.set_schema_version(report, args.schema_version)
 
     out_dir = Path(output_path).parent
     out_dir.mkdir(parents=True, exist_ok=True)
@@ -21,9 +21,30 @@ rm -rf "$SCRIPT_DIR/target/criterion"
 # Run criterion benchmarks and capture a raw log.
 cargo bench --locked --bench pipeline 2>&1 | tee "$OUTPUT_DIR/criterion_raw.log"
 
-# Convert criterion output to report.json
-python3 "$SCRIPT_DIR/emit_report.py" \
-  "$SCRIPT_DIR/target/criterion" \
-  "$OUTPUT_DIR/report.json"
+# Convert criterion output to report.json with the shared Go tooling when it
+# is available; emit_report.py remains as a fallback.
+GO_TOOLING="$SCRIPT_DIR/../aas-core3-golang"
+if command -v aasbench >/dev/null 2>&1; then
+  AASBENCH=(aasbench)
+elif command -v go >/dev/null 2>&1; then
+  AASBENCH=(go -C "$GO_TOOLING" run ./cmd/aasbench)
+else
+  AASBENCH=()
+fi
+
+if [ ${#AASBENCH[@]} -gt 0 ]; then
+  RUST_VERSION="$(rustc --version 2>/dev/null || echo unknown)"
+  SDK_VERSION="$(sed -n 's/^basyx-rs *= *"\(.*\)"/\1/p' "$SCRIPT_DIR/Cargo.toml")"
+  "${AASBENCH[@]}" ingest --format criterion \
+    --input "$SCRIPT_DIR/target/criterion" \
+    --output "$OUTPUT_DIR/report.json" \
+    --sdk-id basyx-rust \
+    --meta "runtime_version=$RUST_VERSION" \
+    --meta "sdk_package_version=${SDK_VERSION:-unknown}"
+else
+  python3 "$SCRIPT_DIR/emit_report.py" \
+    "$SCRIPT_DIR/target/criterion" \
+    "$OUTPUT_DIR/report.json"
+fi
 
 echo "Report written to $OUTPUT_DIR/report.json"